@@ -0,0 +1,10 @@
+// Package database 内嵌版本化的 SQL 迁移脚本，随程序一起发布
+package database
+
+import "embed"
+
+// Migrations 迁移脚本，按数据库方言分目录（migrations/sqlite、migrations/postgres、migrations/mysql），
+// 文件名形如 001_create_users_table.up.sql 与对应的 .down.sql
+//
+//go:embed migrations/sqlite/*.sql migrations/postgres/*.sql migrations/mysql/*.sql
+var Migrations embed.FS
@@ -1,17 +0,0 @@
--- filepath: d:\MyProject\ERP\backend\database\migrations\001_create_users_table.sql
--- 用户表（核心认证表）
-CREATE TABLE IF NOT EXISTS users (
-    id INTEGER PRIMARY KEY AUTOINCREMENT,
-    username VARCHAR(50) UNIQUE NOT NULL,
-    password_hash VARCHAR(255) NOT NULL,
-    user_type VARCHAR(20) NOT NULL CHECK(user_type IN ('donor', 'volunteer', 'employee')),
-    status VARCHAR(20) DEFAULT 'active',
-    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
-    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
-    last_login DATETIME
-);
-
--- 创建索引
-CREATE INDEX IF NOT EXISTS idx_users_username ON users(username);
-CREATE INDEX IF NOT EXISTS idx_users_type ON users(user_type);
-CREATE INDEX IF NOT EXISTS idx_users_status ON users(status);
\ No newline at end of file
@@ -1,112 +0,0 @@
--- 核心实体表 - 地点和项目
-
--- 地点表
-CREATE TABLE IF NOT EXISTS locations (
-    id INTEGER PRIMARY KEY AUTOINCREMENT,
-    location_id VARCHAR(50) UNIQUE NOT NULL,
-    name VARCHAR(200) NOT NULL,
-    type VARCHAR(50),
-    address TEXT,
-    country_code VARCHAR(3),
-    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
-    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
-);
-
--- 项目表
-CREATE TABLE IF NOT EXISTS projects (
-    id INTEGER PRIMARY KEY AUTOINCREMENT,
-    project_id VARCHAR(50) UNIQUE NOT NULL,
-    name VARCHAR(200) NOT NULL,
-    description TEXT,
-    project_type VARCHAR(200),
-    budget DECIMAL(12,2),
-    actual_cost DECIMAL(12,2) DEFAULT 0,
-    location_id INTEGER,
-    start_date DATE,
-    end_date DATE,
-    status VARCHAR(20) DEFAULT 'planning',
-    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
-    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
-    FOREIGN KEY (location_id) REFERENCES locations(id)
-);
-
--- 创建索引
-CREATE INDEX IF NOT EXISTS idx_projects_status ON projects(status);
-CREATE INDEX IF NOT EXISTS idx_projects_location ON projects(location_id);
-
--- 捐赠者表
-CREATE TABLE IF NOT EXISTS donors (
-    id INTEGER PRIMARY KEY AUTOINCREMENT,
-    user_id INTEGER UNIQUE,
-    donor_id VARCHAR(20) UNIQUE NOT NULL,
-    first_name VARCHAR(50) NOT NULL,
-    last_name VARCHAR(50) NOT NULL,
-    email VARCHAR(100),
-    phone VARCHAR(20),
-    address TEXT,
-    donor_type VARCHAR(20) DEFAULT 'individual',
-    total_donated DECIMAL(12,2) DEFAULT 0,
-    enrollment_date DATE DEFAULT (DATE('now')),
-    status VARCHAR(20) DEFAULT 'active',
-    notes TEXT,
-    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
-    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
-    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
-);
-
--- 志愿者表
-CREATE TABLE IF NOT EXISTS volunteers (
-    id INTEGER PRIMARY KEY AUTOINCREMENT,
-    user_id INTEGER UNIQUE,
-    volunteer_id VARCHAR(20) UNIQUE NOT NULL,
-    first_name VARCHAR(50) NOT NULL,
-    last_name VARCHAR(50) NOT NULL,
-    email VARCHAR(100),
-    phone VARCHAR(20),
-    location_id INTEGER,
-    skills TEXT,
-    availability TEXT,
-    hours_contributed DECIMAL(10,2) DEFAULT 0,
-    status VARCHAR(20) DEFAULT 'active',
-    notes TEXT,
-    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
-    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
-    
-    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
-    FOREIGN KEY (location_id) REFERENCES locations(id)
-);
-
--- 员工表
-CREATE TABLE IF NOT EXISTS employees (
-    id INTEGER PRIMARY KEY AUTOINCREMENT,
-    user_id INTEGER UNIQUE,
-    employee_id VARCHAR(20) UNIQUE NOT NULL,
-    first_name VARCHAR(50) NOT NULL,
-    last_name VARCHAR(50) NOT NULL,
-    email VARCHAR(100),
-    phone VARCHAR(20),
-    position VARCHAR(100),
-    department VARCHAR(100),
-    salary DECIMAL(10,2),
-    hire_date DATE DEFAULT (DATE('now')),
-    location_id INTEGER,
-    status VARCHAR(20) DEFAULT 'pending',
-    notes TEXT,
-    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
-    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
-    FOREIGN KEY (location_id) REFERENCES locations(id),
-    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
-);
-
--- 创建索引
-CREATE INDEX IF NOT EXISTS idx_donors_user ON donors(user_id);
-CREATE INDEX IF NOT EXISTS idx_donors_email ON donors(email);
-CREATE INDEX IF NOT EXISTS idx_donors_status ON donors(status);
-CREATE INDEX IF NOT EXISTS idx_volunteers_user ON volunteers(user_id);
-CREATE INDEX IF NOT EXISTS idx_volunteers_email ON volunteers(email);
-CREATE INDEX IF NOT EXISTS idx_volunteers_location ON volunteers(location_id);
-CREATE INDEX IF NOT EXISTS idx_volunteers_status ON volunteers(status);
-CREATE INDEX IF NOT EXISTS idx_employees_user ON employees(user_id);
-CREATE INDEX IF NOT EXISTS idx_employees_email ON employees(email);
-CREATE INDEX IF NOT EXISTS idx_employees_location ON employees(location_id);
-CREATE INDEX IF NOT EXISTS idx_employees_status ON employees(status);
\ No newline at end of file
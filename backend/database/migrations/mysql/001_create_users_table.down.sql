@@ -0,0 +1,2 @@
+-- 用户表（核心认证表）
+DROP TABLE IF EXISTS users;
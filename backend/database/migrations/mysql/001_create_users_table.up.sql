@@ -0,0 +1,17 @@
+-- 用户表（核心认证表）
+
+-- 用户表
+CREATE TABLE users (
+    id bigint unsigned AUTO_INCREMENT,
+    username varchar(191) NOT NULL UNIQUE,
+    password_hash longtext NOT NULL,
+    user_type longtext NOT NULL,
+    status varchar(191) DEFAULT 'active',
+    created_at datetime(3) NULL,
+    updated_at datetime(3) NULL,
+    last_login datetime(3) NULL,
+    deleted_at datetime(3) NULL,
+    deleted_by bigint unsigned,
+    PRIMARY KEY (id),
+    INDEX idx_users_deleted_at (deleted_at)
+);
@@ -0,0 +1,122 @@
+-- 核心实体表 - 地点、项目、捐赠者、志愿者、员工
+
+-- 地点表
+CREATE TABLE locations (
+    id bigint unsigned AUTO_INCREMENT,
+    location_id varchar(191) NOT NULL,
+    name longtext NOT NULL,
+    type longtext,
+    address longtext,
+    country_code varchar(3),
+    created_at datetime(3) NULL,
+    updated_at datetime(3) NULL,
+    deleted_at datetime(3) NULL,
+    deleted_by bigint unsigned,
+    PRIMARY KEY (id),
+    INDEX idx_locations_deleted_at (deleted_at),
+    UNIQUE INDEX idx_locations_location_id (location_id)
+);
+
+-- 项目表
+CREATE TABLE projects (
+    id bigint unsigned AUTO_INCREMENT,
+    project_id varchar(191) NOT NULL,
+    name longtext NOT NULL,
+    description longtext,
+    project_type longtext,
+    budget double,
+    actual_cost double DEFAULT 0,
+    location_id bigint unsigned,
+    start_date datetime(3) NULL,
+    end_date datetime(3) NULL,
+    status varchar(191) DEFAULT 'planning',
+    created_at datetime(3) NULL,
+    updated_at datetime(3) NULL,
+    deleted_at datetime(3) NULL,
+    deleted_by bigint unsigned,
+    PRIMARY KEY (id),
+    INDEX idx_projects_deleted_at (deleted_at),
+    UNIQUE INDEX idx_projects_project_id (project_id),
+    CONSTRAINT fk_projects_location_id FOREIGN KEY (location_id) REFERENCES locations(id) ON DELETE SET NULL
+);
+
+-- 捐赠者表
+CREATE TABLE donors (
+    id bigint unsigned AUTO_INCREMENT,
+    user_id bigint unsigned,
+    donor_id varchar(191) NOT NULL,
+    first_name longtext NOT NULL,
+    last_name longtext NOT NULL,
+    email longtext,
+    phone longtext,
+    address longtext,
+    donor_type varchar(191) DEFAULT 'individual',
+    total_donated double DEFAULT 0,
+    enrollment_date datetime(3) NULL DEFAULT (CURRENT_DATE),
+    status varchar(191) DEFAULT 'active',
+    notes longtext,
+    created_at datetime(3) NULL,
+    updated_at datetime(3) NULL,
+    deleted_at datetime(3) NULL,
+    deleted_by bigint unsigned,
+    PRIMARY KEY (id),
+    INDEX idx_donors_deleted_at (deleted_at),
+    UNIQUE INDEX idx_donors_donor_id (donor_id),
+    UNIQUE INDEX idx_donors_user_id (user_id),
+    CONSTRAINT fk_donors_user_id FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE SET NULL
+);
+
+-- 志愿者表
+CREATE TABLE volunteers (
+    id bigint unsigned AUTO_INCREMENT,
+    user_id bigint unsigned,
+    volunteer_id varchar(191) NOT NULL,
+    first_name longtext NOT NULL,
+    last_name longtext NOT NULL,
+    email longtext,
+    phone longtext,
+    location_id bigint unsigned,
+    skills longtext,
+    availability longtext,
+    hours_contributed double DEFAULT 0,
+    status varchar(191) DEFAULT 'active',
+    notes longtext,
+    created_at datetime(3) NULL,
+    updated_at datetime(3) NULL,
+    deleted_at datetime(3) NULL,
+    deleted_by bigint unsigned,
+    PRIMARY KEY (id),
+    INDEX idx_volunteers_deleted_at (deleted_at),
+    UNIQUE INDEX idx_volunteers_user_id (user_id),
+    UNIQUE INDEX idx_volunteers_volunteer_id (volunteer_id),
+    CONSTRAINT fk_volunteers_user_id FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE SET NULL,
+    CONSTRAINT fk_volunteers_location_id FOREIGN KEY (location_id) REFERENCES locations(id) ON DELETE SET NULL
+);
+
+-- 员工表
+CREATE TABLE employees (
+    id bigint unsigned AUTO_INCREMENT,
+    user_id bigint unsigned,
+    employee_id varchar(191) NOT NULL,
+    first_name longtext NOT NULL,
+    last_name longtext NOT NULL,
+    email longtext,
+    phone longtext,
+    position longtext,
+    department longtext,
+    salary double,
+    hire_date datetime(3) NULL DEFAULT (CURRENT_DATE),
+    location_id bigint unsigned,
+    status varchar(191) DEFAULT 'active',
+    notes longtext,
+    created_at datetime(3) NULL,
+    updated_at datetime(3) NULL,
+    deleted_at datetime(3) NULL,
+    deleted_by bigint unsigned,
+    PRIMARY KEY (id),
+    INDEX idx_employees_deleted_at (deleted_at),
+    UNIQUE INDEX idx_employees_employee_id (employee_id),
+    UNIQUE INDEX idx_employees_user_id (user_id),
+    CONSTRAINT fk_employees_user_id FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE SET NULL,
+    CONSTRAINT fk_employees_location_id FOREIGN KEY (location_id) REFERENCES locations(id) ON DELETE SET NULL
+);
@@ -0,0 +1,8 @@
+-- 财务管理表 - 交易、捐赠、基金、支出、采购、薪资
+ALTER TABLE donations DROP FOREIGN KEY fk_donations_fund_id;
+DROP TABLE IF EXISTS payrolls;
+DROP TABLE IF EXISTS purchases;
+DROP TABLE IF EXISTS expenses;
+DROP TABLE IF EXISTS funds;
+DROP TABLE IF EXISTS donations;
+DROP TABLE IF EXISTS transactions;
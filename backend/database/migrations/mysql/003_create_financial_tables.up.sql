@@ -0,0 +1,136 @@
+-- 财务管理表 - 交易、捐赠、基金、支出、采购、薪资
+
+-- 交易表
+CREATE TABLE transactions (
+    id bigint unsigned AUTO_INCREMENT,
+    transaction_id varchar(50) NOT NULL UNIQUE,
+    transaction_record text,
+    type varchar(20) NOT NULL,
+    amount decimal(12,2) NOT NULL,
+    from_currency varchar(3) NOT NULL,
+    to_currency varchar(3) NOT NULL,
+    from_entity varchar(200),
+    to_entity varchar(200),
+    transaction_date datetime(3) NULL,
+    created_at datetime(3) NULL,
+    updated_at datetime(3) NULL,
+    deleted_at datetime(3) NULL,
+    deleted_by bigint unsigned,
+    PRIMARY KEY (id),
+    INDEX idx_transactions_deleted_at (deleted_at)
+);
+
+-- 捐赠记录表
+CREATE TABLE donations (
+    id bigint unsigned AUTO_INCREMENT,
+    donation_id varchar(20) NOT NULL UNIQUE,
+    donor_id bigint unsigned NOT NULL,
+    amount decimal(10,2) NOT NULL,
+    transaction_id bigint unsigned,
+    donation_type varchar(20) NOT NULL,
+    category varchar(20) NOT NULL,
+    project_id bigint unsigned,
+    fund_id bigint unsigned,
+    donation_date datetime(3) NULL,
+    payment_method longtext,
+    notes longtext,
+    created_at datetime(3) NULL,
+    updated_at datetime(3) NULL,
+    deleted_at datetime(3) NULL,
+    deleted_by bigint unsigned,
+    PRIMARY KEY (id),
+    INDEX idx_donations_deleted_at (deleted_at),
+    CONSTRAINT fk_donations_project_id FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE RESTRICT,
+    CONSTRAINT fk_donations_donor_id FOREIGN KEY (donor_id) REFERENCES donors(id) ON DELETE RESTRICT,
+    CONSTRAINT fk_donations_transaction_id FOREIGN KEY (transaction_id) REFERENCES transactions(id) ON DELETE RESTRICT
+);
+
+-- 基金表
+CREATE TABLE funds (
+    id bigint unsigned AUTO_INCREMENT,
+    fund_id varchar(20) NOT NULL UNIQUE,
+    donor_id bigint unsigned,
+    project_id bigint unsigned,
+    transaction_id bigint unsigned,
+    name varchar(200) NOT NULL,
+    fund_type varchar(20) NOT NULL,
+    total_amount decimal(12,2) NOT NULL,
+    current_balance decimal(12,2) DEFAULT 0,
+    status varchar(20) DEFAULT 'active',
+    created_at datetime(3) NULL,
+    updated_at datetime(3) NULL,
+    deleted_at datetime(3) NULL,
+    deleted_by bigint unsigned,
+    available_from datetime(3) NULL,
+    available_until datetime(3) NULL,
+    restricted_purpose varchar(50),
+    PRIMARY KEY (id),
+    INDEX idx_funds_deleted_at (deleted_at),
+    CONSTRAINT fk_funds_project_id FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE SET NULL,
+    CONSTRAINT fk_funds_donor_id FOREIGN KEY (donor_id) REFERENCES donors(id) ON DELETE SET NULL,
+    CONSTRAINT fk_funds_transaction_id FOREIGN KEY (transaction_id) REFERENCES transactions(id) ON DELETE SET NULL
+);
+
+-- 支出表
+CREATE TABLE expenses (
+    id bigint unsigned AUTO_INCREMENT,
+    expense_id varchar(20) NOT NULL UNIQUE,
+    fund_id bigint unsigned NOT NULL,
+    project_id bigint unsigned,
+    employee_id bigint unsigned,
+    created_by bigint unsigned,
+    transaction_id bigint unsigned,
+    description longtext NOT NULL,
+    amount decimal(10,2) NOT NULL,
+    expense_date datetime(3) NULL,
+    approval_status varchar(20) DEFAULT 'draft',
+    created_at datetime(3) NULL,
+    updated_at datetime(3) NULL,
+    deleted_at datetime(3) NULL,
+    deleted_by bigint unsigned,
+    PRIMARY KEY (id),
+    INDEX idx_expenses_deleted_at (deleted_at),
+    CONSTRAINT fk_expenses_project_id FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE RESTRICT,
+    CONSTRAINT fk_expenses_employee_id FOREIGN KEY (employee_id) REFERENCES employees(id) ON DELETE RESTRICT,
+    CONSTRAINT fk_expenses_created_by FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE SET NULL,
+    CONSTRAINT fk_expenses_fund_id FOREIGN KEY (fund_id) REFERENCES funds(id) ON DELETE RESTRICT,
+    CONSTRAINT fk_expenses_transaction_id FOREIGN KEY (transaction_id) REFERENCES transactions(id) ON DELETE RESTRICT
+);
+
+-- 采购表
+CREATE TABLE purchases (
+    id bigint unsigned AUTO_INCREMENT,
+    purchase_id varchar(50) NOT NULL UNIQUE,
+    transaction_id bigint unsigned,
+    total_spent decimal(12,2) NOT NULL,
+    supplier_name varchar(200),
+    purchase_date datetime(3) NULL,
+    description longtext,
+    created_at datetime(3) NULL,
+    updated_at datetime(3) NULL,
+    deleted_at datetime(3) NULL,
+    deleted_by bigint unsigned,
+    PRIMARY KEY (id),
+    INDEX idx_purchases_deleted_at (deleted_at),
+    CONSTRAINT fk_purchases_transaction_id FOREIGN KEY (transaction_id) REFERENCES transactions(id) ON DELETE RESTRICT
+);
+
+-- 薪资表
+CREATE TABLE payrolls (
+    id bigint unsigned AUTO_INCREMENT,
+    transaction_id bigint unsigned NOT NULL,
+    employee_id bigint unsigned NOT NULL,
+    amount decimal(10,2) NOT NULL,
+    pay_date datetime(3) NULL,
+    created_at datetime(3) NULL,
+    updated_at datetime(3) NULL,
+    deleted_at datetime(3) NULL,
+    deleted_by bigint unsigned,
+    PRIMARY KEY (id),
+    INDEX idx_payrolls_deleted_at (deleted_at),
+    CONSTRAINT fk_payrolls_employee_id FOREIGN KEY (employee_id) REFERENCES employees(id) ON DELETE RESTRICT,
+    CONSTRAINT fk_payrolls_transaction_id FOREIGN KEY (transaction_id) REFERENCES transactions(id) ON DELETE RESTRICT
+);
+
+-- 引用后建表的外键
+ALTER TABLE donations ADD CONSTRAINT fk_donations_fund_id FOREIGN KEY (fund_id) REFERENCES funds(id) ON DELETE RESTRICT;
@@ -0,0 +1,7 @@
+-- 库存和礼品表
+ALTER TABLE gifts DROP FOREIGN KEY fk_gifts_delivery_id;
+DROP TABLE IF EXISTS deliveries;
+DROP TABLE IF EXISTS inventory_transactions;
+DROP TABLE IF EXISTS gifts;
+DROP TABLE IF EXISTS gift_types;
+DROP TABLE IF EXISTS inventories;
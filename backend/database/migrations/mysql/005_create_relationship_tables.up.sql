@@ -0,0 +1,119 @@
+-- 关联表
+
+-- 志愿者-项目表
+CREATE TABLE volunteer_projects (
+    id bigint unsigned AUTO_INCREMENT,
+    volunteer_id bigint unsigned NOT NULL,
+    project_id bigint unsigned NOT NULL,
+    role varchar(100),
+    contract_start datetime(3) NULL,
+    contract_end datetime(3) NULL,
+    work_unit varchar(50),
+    total_amount decimal(10,2),
+    contract_date datetime(3) NULL,
+    contract_detail longtext,
+    status varchar(20) DEFAULT 'active',
+    created_at datetime(3) NULL,
+    deleted_at datetime(3) NULL,
+    deleted_by bigint unsigned,
+    PRIMARY KEY (id),
+    INDEX idx_volunteer_projects_deleted_at (deleted_at),
+    CONSTRAINT fk_volunteer_projects_project_id FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE,
+    CONSTRAINT fk_volunteer_projects_volunteer_id FOREIGN KEY (volunteer_id) REFERENCES volunteers(id) ON DELETE CASCADE
+);
+
+-- 员工-项目表
+CREATE TABLE employee_projects (
+    id bigint unsigned AUTO_INCREMENT,
+    employee_id bigint unsigned NOT NULL,
+    project_id bigint unsigned NOT NULL,
+    title varchar(100),
+    start_date datetime(3) NULL,
+    end_date datetime(3) NULL,
+    work_unit varchar(50),
+    allocated_amount decimal(10,2),
+    last_updated datetime(3) NULL,
+    created_at datetime(3) NULL,
+    deleted_at datetime(3) NULL,
+    deleted_by bigint unsigned,
+    PRIMARY KEY (id),
+    INDEX idx_employee_projects_deleted_at (deleted_at),
+    CONSTRAINT fk_employee_projects_project_id FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE,
+    CONSTRAINT fk_employee_projects_employee_id FOREIGN KEY (employee_id) REFERENCES employees(id) ON DELETE CASCADE
+);
+
+-- 基金拨款表
+CREATE TABLE fund_projects (
+    id bigint unsigned AUTO_INCREMENT,
+    transaction_id bigint unsigned NOT NULL,
+    project_id bigint unsigned NOT NULL,
+    fund_id bigint unsigned NOT NULL,
+    allocated_amount decimal(12,2) NOT NULL,
+    allocation_date datetime(3) NULL,
+    purpose longtext,
+    created_at datetime(3) NULL,
+    deleted_at datetime(3) NULL,
+    deleted_by bigint unsigned,
+    PRIMARY KEY (id),
+    INDEX idx_fund_projects_deleted_at (deleted_at),
+    CONSTRAINT fk_fund_projects_project_id FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE RESTRICT,
+    CONSTRAINT fk_fund_projects_fund_id FOREIGN KEY (fund_id) REFERENCES funds(id) ON DELETE RESTRICT,
+    CONSTRAINT fk_fund_projects_transaction_id FOREIGN KEY (transaction_id) REFERENCES transactions(id) ON DELETE RESTRICT
+);
+
+-- 捐赠-库存表
+CREATE TABLE donation_inventories (
+    id bigint unsigned AUTO_INCREMENT,
+    donor_id bigint unsigned NOT NULL,
+    inventory_id bigint unsigned NOT NULL,
+    donation_date datetime(3) NULL,
+    project_id bigint unsigned,
+    quantity bigint DEFAULT 1,
+    estimated_value decimal(10,2),
+    created_at datetime(3) NULL,
+    deleted_at datetime(3) NULL,
+    deleted_by bigint unsigned,
+    PRIMARY KEY (id),
+    INDEX idx_donation_inventories_deleted_at (deleted_at),
+    CONSTRAINT fk_donation_inventories_project_id FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE SET NULL,
+    CONSTRAINT fk_donation_inventories_donor_id FOREIGN KEY (donor_id) REFERENCES donors(id) ON DELETE RESTRICT,
+    CONSTRAINT fk_donation_inventories_inventory_id FOREIGN KEY (inventory_id) REFERENCES inventories(id) ON DELETE RESTRICT
+);
+
+-- 配送-库存表
+CREATE TABLE delivery_inventories (
+    id bigint unsigned AUTO_INCREMENT,
+    delivery_id bigint unsigned NOT NULL,
+    inventory_id bigint unsigned NOT NULL,
+    quantity bigint DEFAULT 1,
+    unit_cost decimal(8,2),
+    created_at datetime(3) NULL,
+    updated_at datetime(3) NULL,
+    deleted_at datetime(3) NULL,
+    deleted_by bigint unsigned,
+    PRIMARY KEY (id),
+    INDEX idx_delivery_inventories_deleted_at (deleted_at),
+    CONSTRAINT fk_delivery_inventories_inventory_id FOREIGN KEY (inventory_id) REFERENCES inventories(id) ON DELETE RESTRICT,
+    CONSTRAINT fk_delivery_inventories_delivery_id FOREIGN KEY (delivery_id) REFERENCES deliveries(id) ON DELETE CASCADE
+);
+
+-- 排班表
+CREATE TABLE schedules (
+    id bigint unsigned AUTO_INCREMENT,
+    schedule_id varchar(20) NOT NULL UNIQUE,
+    person_id bigint unsigned NOT NULL,
+    person_type varchar(20) NOT NULL,
+    project_id bigint unsigned,
+    shift_date datetime(3) NULL,
+    start_time longtext,
+    end_time longtext,
+    hours_worked decimal(5,2),
+    status varchar(20) DEFAULT 'scheduled',
+    notes longtext,
+    created_at datetime(3) NULL,
+    deleted_at datetime(3) NULL,
+    deleted_by bigint unsigned,
+    PRIMARY KEY (id),
+    INDEX idx_schedules_deleted_at (deleted_at),
+    CONSTRAINT fk_schedules_project_id FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE
+);
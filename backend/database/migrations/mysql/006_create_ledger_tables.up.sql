@@ -0,0 +1,50 @@
+-- 总账表 - 会计科目、凭证、分录
+
+-- 会计科目表
+CREATE TABLE accounts (
+    id bigint unsigned AUTO_INCREMENT,
+    code varchar(20) NOT NULL UNIQUE,
+    name varchar(200) NOT NULL,
+    type varchar(20) NOT NULL,
+    parent_id bigint unsigned,
+    is_active boolean DEFAULT true,
+    created_at datetime(3) NULL,
+    updated_at datetime(3) NULL,
+    PRIMARY KEY (id)
+);
+
+-- 记账凭证表
+CREATE TABLE journal_entries (
+    id bigint unsigned AUTO_INCREMENT,
+    transaction_id bigint unsigned,
+    source_type varchar(20) NOT NULL,
+    source_id bigint unsigned,
+    entry_date datetime(3) NOT NULL,
+    description longtext,
+    reversal_of_id bigint unsigned,
+    reversed_by_id bigint unsigned,
+    created_at datetime(3) NULL,
+    PRIMARY KEY (id),
+    INDEX idx_journal_entries_entry_date (entry_date),
+    INDEX idx_journal_source (source_type,source_id),
+    CONSTRAINT fk_journal_entries_transaction_id FOREIGN KEY (transaction_id) REFERENCES transactions(id) ON DELETE RESTRICT
+);
+
+-- 凭证分录表
+CREATE TABLE journal_lines (
+    id bigint unsigned AUTO_INCREMENT,
+    journal_entry_id bigint unsigned NOT NULL,
+    account_id bigint unsigned NOT NULL,
+    fund_id bigint unsigned,
+    project_id bigint unsigned,
+    debit decimal(12,2) DEFAULT 0,
+    credit decimal(12,2) DEFAULT 0,
+    memo longtext,
+    PRIMARY KEY (id),
+    INDEX idx_journal_lines_account_id (account_id),
+    INDEX idx_journal_lines_journal_entry_id (journal_entry_id),
+    CONSTRAINT fk_journal_lines_project_id FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE RESTRICT,
+    CONSTRAINT fk_journal_lines_fund_id FOREIGN KEY (fund_id) REFERENCES funds(id) ON DELETE RESTRICT,
+    CONSTRAINT fk_journal_lines_journal_entry_id FOREIGN KEY (journal_entry_id) REFERENCES journal_entries(id) ON DELETE CASCADE,
+    CONSTRAINT fk_journal_lines_account_id FOREIGN KEY (account_id) REFERENCES accounts(id) ON DELETE RESTRICT
+);
@@ -0,0 +1,32 @@
+-- 支出审批表
+
+-- 审批记录表
+CREATE TABLE expense_approvals (
+    id bigint unsigned AUTO_INCREMENT,
+    expense_id bigint unsigned NOT NULL,
+    action varchar(20) NOT NULL,
+    from_status varchar(20),
+    to_status varchar(20) NOT NULL,
+    user_id bigint unsigned,
+    employee_id bigint unsigned,
+    amount decimal(10,2),
+    comment longtext,
+    created_at datetime(3) NULL,
+    PRIMARY KEY (id),
+    INDEX idx_expense_approvals_expense_id (expense_id),
+    CONSTRAINT fk_expense_approvals_user_id FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE RESTRICT,
+    CONSTRAINT fk_expense_approvals_employee_id FOREIGN KEY (employee_id) REFERENCES employees(id) ON DELETE RESTRICT,
+    CONSTRAINT fk_expense_approvals_expense_id FOREIGN KEY (expense_id) REFERENCES expenses(id) ON DELETE CASCADE
+);
+
+-- 审批额度表
+CREATE TABLE approval_thresholds (
+    id bigint unsigned AUTO_INCREMENT,
+    department varchar(100),
+    position varchar(100) NOT NULL,
+    max_amount decimal(12,2) NOT NULL,
+    created_at datetime(3) NULL,
+    updated_at datetime(3) NULL,
+    PRIMARY KEY (id),
+    UNIQUE INDEX idx_threshold_dept_pos (department,position)
+);
@@ -0,0 +1,42 @@
+-- 角色与权限表
+
+-- 权限表
+CREATE TABLE permissions (
+    id bigint unsigned AUTO_INCREMENT,
+    resource varchar(50) NOT NULL,
+    action varchar(50) NOT NULL,
+    description longtext,
+    PRIMARY KEY (id),
+    UNIQUE INDEX idx_permission (resource,action)
+);
+
+-- 角色表
+CREATE TABLE roles (
+    id bigint unsigned AUTO_INCREMENT,
+    name varchar(50) NOT NULL UNIQUE,
+    description longtext,
+    is_system boolean DEFAULT false,
+    created_at datetime(3) NULL,
+    updated_at datetime(3) NULL,
+    require_two_factor boolean DEFAULT false,
+    PRIMARY KEY (id)
+);
+
+-- 角色-权限表
+CREATE TABLE role_permissions (
+    role_id bigint unsigned,
+    permission_id bigint unsigned,
+    PRIMARY KEY (role_id,permission_id),
+    CONSTRAINT fk_role_permissions_role_id FOREIGN KEY (role_id) REFERENCES roles(id) ON DELETE CASCADE,
+    CONSTRAINT fk_role_permissions_permission_id FOREIGN KEY (permission_id) REFERENCES permissions(id) ON DELETE CASCADE
+);
+
+-- 用户-角色表
+CREATE TABLE user_roles (
+    user_id bigint unsigned,
+    role_id bigint unsigned,
+    created_at datetime(3) NULL,
+    PRIMARY KEY (user_id,role_id),
+    CONSTRAINT fk_user_roles_user_id FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT fk_user_roles_role_id FOREIGN KEY (role_id) REFERENCES roles(id) ON DELETE CASCADE
+);
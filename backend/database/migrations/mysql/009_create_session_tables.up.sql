@@ -0,0 +1,116 @@
+-- 登录会话与账户安全表
+
+-- 登录会话表
+CREATE TABLE sessions (
+    id bigint unsigned AUTO_INCREMENT,
+    session_id varchar(64) NOT NULL,
+    user_id bigint unsigned NOT NULL,
+    user_agent varchar(255),
+    client_ip varchar(64),
+    expires_at datetime(3) NULL,
+    last_used_at datetime(3) NULL,
+    revoked_at datetime(3) NULL,
+    revoke_reason varchar(30),
+    created_at datetime(3) NULL,
+    PRIMARY KEY (id),
+    UNIQUE INDEX idx_sessions_session_id (session_id),
+    INDEX idx_sessions_user_id (user_id),
+    CONSTRAINT fk_sessions_user_id FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- 刷新令牌表
+CREATE TABLE refresh_tokens (
+    id bigint unsigned AUTO_INCREMENT,
+    session_id bigint unsigned NOT NULL,
+    token_hash varchar(64) NOT NULL,
+    expires_at datetime(3) NULL,
+    used_at datetime(3) NULL,
+    created_at datetime(3) NULL,
+    PRIMARY KEY (id),
+    INDEX idx_refresh_tokens_session_id (session_id),
+    UNIQUE INDEX idx_refresh_tokens_token_hash (token_hash),
+    CONSTRAINT fk_refresh_tokens_session_id FOREIGN KEY (session_id) REFERENCES sessions(id) ON DELETE CASCADE
+);
+
+-- 密码重置令牌表
+CREATE TABLE password_reset_tokens (
+    id bigint unsigned AUTO_INCREMENT,
+    user_id bigint unsigned NOT NULL,
+    token_hash varchar(64) NOT NULL,
+    expires_at datetime(3) NULL,
+    used_at datetime(3) NULL,
+    created_at datetime(3) NULL,
+    PRIMARY KEY (id),
+    UNIQUE INDEX idx_password_reset_tokens_token_hash (token_hash),
+    INDEX idx_password_reset_tokens_user_id (user_id),
+    CONSTRAINT fk_password_reset_tokens_user_id FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- 登录尝试表
+CREATE TABLE login_attempts (
+    id bigint unsigned AUTO_INCREMENT,
+    username varchar(100),
+    user_id bigint unsigned,
+    client_ip varchar(64),
+    user_agent varchar(255),
+    success boolean,
+    reason varchar(30),
+    created_at datetime(3) NULL,
+    PRIMARY KEY (id),
+    INDEX idx_login_attempts_client_ip (client_ip),
+    INDEX idx_login_attempts_created_at (created_at),
+    INDEX idx_login_attempts_user_id (user_id),
+    INDEX idx_login_attempts_username (username),
+    CONSTRAINT fk_login_attempts_user_id FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE SET NULL
+);
+
+-- 账户锁定表
+CREATE TABLE account_lockouts (
+    user_id bigint unsigned,
+    failed_count bigint,
+    lock_count bigint,
+    locked_until datetime(3) NULL,
+    updated_at datetime(3) NULL,
+    PRIMARY KEY (user_id),
+    CONSTRAINT fk_account_lockouts_user_id FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- 双因素认证表
+CREATE TABLE two_factors (
+    user_id bigint unsigned,
+    secret longtext NOT NULL,
+    enabled boolean DEFAULT false,
+    confirmed_at datetime(3) NULL,
+    last_used_step bigint,
+    created_at datetime(3) NULL,
+    updated_at datetime(3) NULL,
+    PRIMARY KEY (user_id),
+    CONSTRAINT fk_two_factors_user_id FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- 恢复码表
+CREATE TABLE recovery_codes (
+    id bigint unsigned AUTO_INCREMENT,
+    user_id bigint unsigned NOT NULL,
+    code_hash varchar(64) NOT NULL,
+    used_at datetime(3) NULL,
+    created_at datetime(3) NULL,
+    PRIMARY KEY (id),
+    INDEX idx_recovery_codes_user_id (user_id),
+    CONSTRAINT fk_recovery_codes_user_id FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- 两步登录挑战表
+CREATE TABLE login_challenges (
+    id bigint unsigned AUTO_INCREMENT,
+    token_hash varchar(64) NOT NULL,
+    user_id bigint unsigned NOT NULL,
+    attempts bigint,
+    expires_at datetime(3) NULL,
+    used_at datetime(3) NULL,
+    created_at datetime(3) NULL,
+    PRIMARY KEY (id),
+    UNIQUE INDEX idx_login_challenges_token_hash (token_hash),
+    INDEX idx_login_challenges_user_id (user_id),
+    CONSTRAINT fk_login_challenges_user_id FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
@@ -0,0 +1,19 @@
+-- 员工注册审核表
+
+-- 注册审核表
+CREATE TABLE registration_reviews (
+    id bigint unsigned AUTO_INCREMENT,
+    user_id bigint unsigned NOT NULL,
+    employee_id bigint unsigned NOT NULL,
+    decision varchar(20) NOT NULL,
+    reason longtext,
+    department varchar(100),
+    position varchar(100),
+    reviewed_by bigint unsigned,
+    created_at datetime(3) NULL,
+    PRIMARY KEY (id),
+    INDEX idx_registration_reviews_employee_id (employee_id),
+    INDEX idx_registration_reviews_user_id (user_id),
+    CONSTRAINT fk_registration_reviews_user_id FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT fk_registration_reviews_employee_id FOREIGN KEY (employee_id) REFERENCES employees(id) ON DELETE CASCADE
+);
@@ -0,0 +1,2 @@
+-- 审计日志表（只允许追加）
+DROP TABLE IF EXISTS audit_logs;
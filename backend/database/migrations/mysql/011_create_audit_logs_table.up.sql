@@ -0,0 +1,30 @@
+-- 审计日志表（只允许追加）
+
+-- 审计日志表
+CREATE TABLE audit_logs (
+    id bigint unsigned AUTO_INCREMENT,
+    user_id bigint unsigned,
+    username varchar(100),
+    action varchar(10),
+    table_name varchar(64),
+    record_id varchar(64),
+    changes text,
+    client_ip varchar(64),
+    created_at datetime(3) NULL,
+    PRIMARY KEY (id),
+    INDEX idx_audit_logs_action (action),
+    INDEX idx_audit_logs_created_at (created_at),
+    INDEX idx_audit_logs_record_id (record_id),
+    INDEX idx_audit_logs_table (table_name),
+    INDEX idx_audit_logs_user_id (user_id)
+);
+
+-- 禁止修改或删除审计日志，绕过应用程序的写入同样被拒绝
+CREATE TRIGGER audit_logs_no_update BEFORE UPDATE ON audit_logs FOR EACH ROW
+BEGIN
+    SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'audit log is append-only';
+END;
+CREATE TRIGGER audit_logs_no_delete BEFORE DELETE ON audit_logs FOR EACH ROW
+BEGIN
+    SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'audit log is append-only';
+END;
@@ -0,0 +1,3 @@
+-- 批量导入表
+DROP TABLE IF EXISTS import_jobs;
+DROP TABLE IF EXISTS import_mappings;
@@ -0,0 +1,31 @@
+-- 批量导入表
+
+-- 导入列映射模板表
+CREATE TABLE import_mappings (
+    id bigint unsigned AUTO_INCREMENT,
+    resource varchar(50) NOT NULL,
+    name varchar(100) NOT NULL,
+    mapping text NOT NULL,
+    created_by bigint unsigned,
+    created_at datetime(3) NULL,
+    updated_at datetime(3) NULL,
+    PRIMARY KEY (id),
+    UNIQUE INDEX idx_import_mappings_resource_name (resource,name)
+);
+
+-- 导入记录表
+CREATE TABLE import_jobs (
+    id bigint unsigned AUTO_INCREMENT,
+    resource varchar(50) NOT NULL,
+    filename varchar(255),
+    mode varchar(10) NOT NULL,
+    dry_run boolean,
+    total_rows bigint,
+    imported bigint,
+    failed bigint,
+    errors text,
+    created_by bigint unsigned,
+    created_at datetime(3) NULL,
+    PRIMARY KEY (id),
+    INDEX idx_import_jobs_resource (resource)
+);
@@ -0,0 +1,30 @@
+-- 年度捐赠收据表
+
+-- 收据表
+CREATE TABLE tax_receipts (
+    id bigint unsigned AUTO_INCREMENT,
+    receipt_number varchar(30) NOT NULL UNIQUE,
+    year bigint NOT NULL,
+    donor_id bigint unsigned NOT NULL,
+    status varchar(10) NOT NULL,
+    cash_total decimal(12,2),
+    in_kind_total decimal(12,2),
+    content text NOT NULL,
+    replaces_id bigint unsigned,
+    replaced_by_id bigint unsigned,
+    issued_by bigint unsigned,
+    issued_at datetime(3) NOT NULL,
+    voided_by bigint unsigned,
+    voided_at datetime(3) NULL,
+    void_reason longtext,
+    PRIMARY KEY (id),
+    INDEX idx_tax_receipts_donor_year (year,donor_id),
+    CONSTRAINT fk_tax_receipts_donor_id FOREIGN KEY (donor_id) REFERENCES donors(id) ON DELETE RESTRICT
+);
+
+-- 收据号计数表
+CREATE TABLE receipt_sequences (
+    year bigint,
+    last_number bigint NOT NULL,
+    PRIMARY KEY (year)
+);
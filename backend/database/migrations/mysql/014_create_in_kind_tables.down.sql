@@ -0,0 +1,15 @@
+-- 实物捐赠估值与入库
+DROP TABLE IF EXISTS in_kind_valuations;
+
+-- 恢复库存变动表原结构，单边变动的空库存以另一边补齐
+UPDATE inventory_transactions SET to_inventory_id = COALESCE(to_inventory_id, from_inventory_id), from_inventory_id = COALESCE(from_inventory_id, to_inventory_id);
+ALTER TABLE inventory_transactions
+    DROP INDEX idx_inventory_transactions_source,
+    DROP COLUMN reversed_by_id,
+    DROP COLUMN reversal_of_id,
+    DROP COLUMN source_id,
+    DROP COLUMN source_type,
+    MODIFY to_inventory_id bigint unsigned NOT NULL,
+    MODIFY from_inventory_id bigint unsigned NOT NULL;
+
+ALTER TABLE donors DROP COLUMN in_kind_total;
@@ -0,0 +1,29 @@
+-- 实物捐赠估值与入库
+
+-- 捐赠者的实物捐赠估值累计
+ALTER TABLE donors ADD COLUMN in_kind_total double DEFAULT 0;
+
+-- 库存变动：单边变动的出库或入库库存可以为空，并记录来源单据与冲销关系
+ALTER TABLE inventory_transactions
+    MODIFY to_inventory_id bigint unsigned NULL,
+    MODIFY from_inventory_id bigint unsigned NULL,
+    ADD COLUMN source_type varchar(20),
+    ADD COLUMN source_id bigint unsigned,
+    ADD COLUMN reversal_of_id bigint unsigned,
+    ADD COLUMN reversed_by_id bigint unsigned,
+    ADD INDEX idx_inventory_transactions_source (source_type,source_id);
+
+-- 实物捐赠估值表
+CREATE TABLE in_kind_valuations (
+    id bigint unsigned AUTO_INCREMENT,
+    donation_inventory_id bigint unsigned NOT NULL UNIQUE,
+    basis varchar(20) NOT NULL,
+    unit_value decimal(10,2) NOT NULL,
+    total_value decimal(12,2) NOT NULL,
+    appraiser varchar(200),
+    notes longtext,
+    valued_by bigint unsigned,
+    valued_at datetime(3) NOT NULL,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_in_kind_valuations_donation_inventory_id FOREIGN KEY (donation_inventory_id) REFERENCES donation_inventories(id) ON DELETE CASCADE
+);
@@ -0,0 +1,2 @@
+-- 库存台账期初
+DELETE FROM inventory_transactions WHERE source_type = 'opening_balance';
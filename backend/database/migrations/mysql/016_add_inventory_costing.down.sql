@@ -0,0 +1,6 @@
+-- 库存计价
+DROP TABLE IF EXISTS inventory_cost_methods;
+ALTER TABLE deliveries DROP FOREIGN KEY fk_deliveries_project_id;
+ALTER TABLE deliveries DROP COLUMN project_id;
+ALTER TABLE inventory_transactions DROP FOREIGN KEY fk_inventory_transactions_purchase_id;
+ALTER TABLE inventory_transactions DROP COLUMN purchase_id, DROP COLUMN unit_cost;
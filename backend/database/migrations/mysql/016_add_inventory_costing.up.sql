@@ -0,0 +1,35 @@
+-- 库存计价：库存变动的单位成本与采购来源、配送所属项目、类别的计价方法
+
+-- 库存变动的单位成本与采购入库对应的采购
+ALTER TABLE inventory_transactions
+    ADD COLUMN unit_cost decimal(12,4) DEFAULT 0,
+    ADD COLUMN purchase_id bigint unsigned,
+    ADD CONSTRAINT fk_inventory_transactions_purchase_id FOREIGN KEY (purchase_id) REFERENCES purchases(id) ON DELETE SET NULL;
+
+-- 配送所属项目
+ALTER TABLE deliveries
+    ADD COLUMN project_id bigint unsigned,
+    ADD CONSTRAINT fk_deliveries_project_id FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE SET NULL;
+
+-- 库存类别的计价方法
+CREATE TABLE inventory_cost_methods (
+    id bigint unsigned AUTO_INCREMENT,
+    category varchar(100) NOT NULL UNIQUE,
+    method varchar(20) NOT NULL,
+    created_at datetime(3) NULL,
+    updated_at datetime(3) NULL,
+    PRIMARY KEY (id)
+);
+
+-- 已有变动的单位成本：实物捐赠取估值单价，其余取库存上手工填写的单位成本
+UPDATE inventory_transactions SET unit_cost = COALESCE(
+    (SELECT di.estimated_value / di.quantity FROM donation_inventories di
+        WHERE inventory_transactions.source_type = 'in_kind' AND di.id = inventory_transactions.source_id AND di.quantity > 0),
+    (SELECT i.unit_cost FROM inventories i WHERE i.id = COALESCE(inventory_transactions.from_inventory_id, inventory_transactions.to_inventory_id)),
+    0);
+
+-- 配送明细的单位成本取其出库变动的成本
+UPDATE delivery_inventories di
+JOIN inventory_transactions t ON t.source_type = 'delivery_inventory' AND t.source_id = di.id
+    AND t.reversal_of_id IS NULL AND t.reversed_by_id IS NULL
+SET di.unit_cost = t.unit_cost;
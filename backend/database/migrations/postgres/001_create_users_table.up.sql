@@ -0,0 +1,17 @@
+-- 用户表（核心认证表）
+
+-- 用户表
+CREATE TABLE users (
+    id bigserial,
+    username text NOT NULL UNIQUE,
+    password_hash text NOT NULL,
+    user_type text NOT NULL,
+    status text DEFAULT 'active',
+    created_at timestamptz,
+    updated_at timestamptz,
+    last_login timestamptz,
+    deleted_at timestamptz,
+    deleted_by bigint,
+    PRIMARY KEY (id)
+);
+CREATE INDEX idx_users_deleted_at ON users (deleted_at);
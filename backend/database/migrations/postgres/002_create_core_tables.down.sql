@@ -0,0 +1,6 @@
+-- 核心实体表 - 地点、项目、捐赠者、志愿者、员工
+DROP TABLE IF EXISTS employees;
+DROP TABLE IF EXISTS volunteers;
+DROP TABLE IF EXISTS donors;
+DROP TABLE IF EXISTS projects;
+DROP TABLE IF EXISTS locations;
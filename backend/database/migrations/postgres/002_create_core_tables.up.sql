@@ -0,0 +1,122 @@
+-- 核心实体表 - 地点、项目、捐赠者、志愿者、员工
+
+-- 地点表
+CREATE TABLE locations (
+    id bigserial,
+    location_id text NOT NULL,
+    name text NOT NULL,
+    type text,
+    address text,
+    country_code varchar(3),
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    deleted_by bigint,
+    PRIMARY KEY (id)
+);
+CREATE INDEX idx_locations_deleted_at ON locations (deleted_at);
+CREATE UNIQUE INDEX idx_locations_location_id ON locations (location_id);
+
+-- 项目表
+CREATE TABLE projects (
+    id bigserial,
+    project_id text NOT NULL,
+    name text NOT NULL,
+    description text,
+    project_type text,
+    budget decimal,
+    actual_cost decimal DEFAULT 0,
+    location_id bigint,
+    start_date timestamptz,
+    end_date timestamptz,
+    status text DEFAULT 'planning',
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    deleted_by bigint,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_projects_location_id FOREIGN KEY (location_id) REFERENCES locations(id) ON DELETE SET NULL
+);
+CREATE INDEX idx_projects_deleted_at ON projects (deleted_at);
+CREATE UNIQUE INDEX idx_projects_project_id ON projects (project_id);
+
+-- 捐赠者表
+CREATE TABLE donors (
+    id bigserial,
+    user_id bigint,
+    donor_id text NOT NULL,
+    first_name text NOT NULL,
+    last_name text NOT NULL,
+    email text,
+    phone text,
+    address text,
+    donor_type text DEFAULT 'individual',
+    total_donated decimal DEFAULT 0,
+    enrollment_date timestamptz DEFAULT CURRENT_DATE,
+    status text DEFAULT 'active',
+    notes text,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    deleted_by bigint,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_donors_user_id FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE SET NULL
+);
+CREATE INDEX idx_donors_deleted_at ON donors (deleted_at);
+CREATE UNIQUE INDEX idx_donors_donor_id ON donors (donor_id);
+CREATE UNIQUE INDEX idx_donors_user_id ON donors (user_id);
+
+-- 志愿者表
+CREATE TABLE volunteers (
+    id bigserial,
+    user_id bigint,
+    volunteer_id text NOT NULL,
+    first_name text NOT NULL,
+    last_name text NOT NULL,
+    email text,
+    phone text,
+    location_id bigint,
+    skills text,
+    availability text,
+    hours_contributed decimal DEFAULT 0,
+    status text DEFAULT 'active',
+    notes text,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    deleted_by bigint,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_volunteers_user_id FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE SET NULL,
+    CONSTRAINT fk_volunteers_location_id FOREIGN KEY (location_id) REFERENCES locations(id) ON DELETE SET NULL
+);
+CREATE INDEX idx_volunteers_deleted_at ON volunteers (deleted_at);
+CREATE UNIQUE INDEX idx_volunteers_user_id ON volunteers (user_id);
+CREATE UNIQUE INDEX idx_volunteers_volunteer_id ON volunteers (volunteer_id);
+
+-- 员工表
+CREATE TABLE employees (
+    id bigserial,
+    user_id bigint,
+    employee_id text NOT NULL,
+    first_name text NOT NULL,
+    last_name text NOT NULL,
+    email text,
+    phone text,
+    position text,
+    department text,
+    salary decimal,
+    hire_date timestamptz DEFAULT CURRENT_DATE,
+    location_id bigint,
+    status text DEFAULT 'active',
+    notes text,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    deleted_by bigint,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_employees_user_id FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE SET NULL,
+    CONSTRAINT fk_employees_location_id FOREIGN KEY (location_id) REFERENCES locations(id) ON DELETE SET NULL
+);
+CREATE INDEX idx_employees_deleted_at ON employees (deleted_at);
+CREATE UNIQUE INDEX idx_employees_employee_id ON employees (employee_id);
+CREATE UNIQUE INDEX idx_employees_user_id ON employees (user_id);
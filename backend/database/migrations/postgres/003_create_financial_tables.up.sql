@@ -0,0 +1,136 @@
+-- 财务管理表 - 交易、捐赠、基金、支出、采购、薪资
+
+-- 交易表
+CREATE TABLE transactions (
+    id bigserial,
+    transaction_id varchar(50) NOT NULL UNIQUE,
+    transaction_record text,
+    type varchar(20) NOT NULL,
+    amount decimal(12,2) NOT NULL,
+    from_currency varchar(3) NOT NULL,
+    to_currency varchar(3) NOT NULL,
+    from_entity varchar(200),
+    to_entity varchar(200),
+    transaction_date timestamptz,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    deleted_by bigint,
+    PRIMARY KEY (id)
+);
+CREATE INDEX idx_transactions_deleted_at ON transactions (deleted_at);
+
+-- 捐赠记录表
+CREATE TABLE donations (
+    id bigserial,
+    donation_id varchar(20) NOT NULL UNIQUE,
+    donor_id bigint NOT NULL,
+    amount decimal(10,2) NOT NULL,
+    transaction_id bigint,
+    donation_type varchar(20) NOT NULL,
+    category varchar(20) NOT NULL,
+    project_id bigint,
+    fund_id bigint,
+    donation_date timestamptz,
+    payment_method text,
+    notes text,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    deleted_by bigint,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_donations_project_id FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE RESTRICT,
+    CONSTRAINT fk_donations_donor_id FOREIGN KEY (donor_id) REFERENCES donors(id) ON DELETE RESTRICT,
+    CONSTRAINT fk_donations_transaction_id FOREIGN KEY (transaction_id) REFERENCES transactions(id) ON DELETE RESTRICT
+);
+CREATE INDEX idx_donations_deleted_at ON donations (deleted_at);
+
+-- 基金表
+CREATE TABLE funds (
+    id bigserial,
+    fund_id varchar(20) NOT NULL UNIQUE,
+    donor_id bigint,
+    project_id bigint,
+    transaction_id bigint,
+    name varchar(200) NOT NULL,
+    fund_type varchar(20) NOT NULL,
+    total_amount decimal(12,2) NOT NULL,
+    current_balance decimal(12,2) DEFAULT 0,
+    status varchar(20) DEFAULT 'active',
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    deleted_by bigint,
+    available_from timestamptz,
+    available_until timestamptz,
+    restricted_purpose varchar(50),
+    PRIMARY KEY (id),
+    CONSTRAINT fk_funds_project_id FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE SET NULL,
+    CONSTRAINT fk_funds_donor_id FOREIGN KEY (donor_id) REFERENCES donors(id) ON DELETE SET NULL,
+    CONSTRAINT fk_funds_transaction_id FOREIGN KEY (transaction_id) REFERENCES transactions(id) ON DELETE SET NULL
+);
+CREATE INDEX idx_funds_deleted_at ON funds (deleted_at);
+
+-- 支出表
+CREATE TABLE expenses (
+    id bigserial,
+    expense_id varchar(20) NOT NULL UNIQUE,
+    fund_id bigint NOT NULL,
+    project_id bigint,
+    employee_id bigint,
+    created_by bigint,
+    transaction_id bigint,
+    description text NOT NULL,
+    amount decimal(10,2) NOT NULL,
+    expense_date timestamptz,
+    approval_status varchar(20) DEFAULT 'draft',
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    deleted_by bigint,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_expenses_project_id FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE RESTRICT,
+    CONSTRAINT fk_expenses_employee_id FOREIGN KEY (employee_id) REFERENCES employees(id) ON DELETE RESTRICT,
+    CONSTRAINT fk_expenses_created_by FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE SET NULL,
+    CONSTRAINT fk_expenses_fund_id FOREIGN KEY (fund_id) REFERENCES funds(id) ON DELETE RESTRICT,
+    CONSTRAINT fk_expenses_transaction_id FOREIGN KEY (transaction_id) REFERENCES transactions(id) ON DELETE RESTRICT
+);
+CREATE INDEX idx_expenses_deleted_at ON expenses (deleted_at);
+
+-- 采购表
+CREATE TABLE purchases (
+    id bigserial,
+    purchase_id varchar(50) NOT NULL UNIQUE,
+    transaction_id bigint,
+    total_spent decimal(12,2) NOT NULL,
+    supplier_name varchar(200),
+    purchase_date timestamptz,
+    description text,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    deleted_by bigint,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_purchases_transaction_id FOREIGN KEY (transaction_id) REFERENCES transactions(id) ON DELETE RESTRICT
+);
+CREATE INDEX idx_purchases_deleted_at ON purchases (deleted_at);
+
+-- 薪资表
+CREATE TABLE payrolls (
+    id bigserial,
+    transaction_id bigint NOT NULL,
+    employee_id bigint NOT NULL,
+    amount decimal(10,2) NOT NULL,
+    pay_date timestamptz,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    deleted_by bigint,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_payrolls_employee_id FOREIGN KEY (employee_id) REFERENCES employees(id) ON DELETE RESTRICT,
+    CONSTRAINT fk_payrolls_transaction_id FOREIGN KEY (transaction_id) REFERENCES transactions(id) ON DELETE RESTRICT
+);
+CREATE INDEX idx_payrolls_deleted_at ON payrolls (deleted_at);
+
+-- 引用后建表的外键
+ALTER TABLE donations ADD CONSTRAINT fk_donations_fund_id FOREIGN KEY (fund_id) REFERENCES funds(id) ON DELETE RESTRICT;
@@ -0,0 +1,97 @@
+-- 库存和礼品表
+
+-- 库存表
+CREATE TABLE inventories (
+    id bigserial,
+    inventory_id varchar(50) NOT NULL UNIQUE,
+    name varchar(200) NOT NULL,
+    category varchar(100),
+    purchase_id bigint,
+    location_id bigint,
+    current_stock bigint DEFAULT 0,
+    unit_cost decimal(10,2),
+    status varchar(20) DEFAULT 'available',
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    deleted_by bigint,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_inventories_location_id FOREIGN KEY (location_id) REFERENCES locations(id) ON DELETE SET NULL,
+    CONSTRAINT fk_inventories_purchase_id FOREIGN KEY (purchase_id) REFERENCES purchases(id) ON DELETE SET NULL
+);
+CREATE INDEX idx_inventories_deleted_at ON inventories (deleted_at);
+
+-- 礼品类型表
+CREATE TABLE gift_types (
+    id bigserial,
+    name varchar(100) NOT NULL,
+    category varchar(50),
+    unit_cost decimal(8,2),
+    requires_inventory boolean DEFAULT true,
+    inventory_name varchar(200) NOT NULL,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    deleted_by bigint,
+    PRIMARY KEY (id)
+);
+CREATE INDEX idx_gift_types_deleted_at ON gift_types (deleted_at);
+
+-- 礼品表
+CREATE TABLE gifts (
+    id bigserial,
+    gift_id varchar(20) NOT NULL UNIQUE,
+    donation_id bigint,
+    delivery_id bigint,
+    gift_type_id bigint NOT NULL,
+    total_value decimal(10,2),
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    deleted_by bigint,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_gifts_donation_id FOREIGN KEY (donation_id) REFERENCES donations(id) ON DELETE CASCADE,
+    CONSTRAINT fk_gifts_gift_type_id FOREIGN KEY (gift_type_id) REFERENCES gift_types(id) ON DELETE RESTRICT
+);
+CREATE INDEX idx_gifts_deleted_at ON gifts (deleted_at);
+
+-- 库存调拨表
+CREATE TABLE inventory_transactions (
+    id bigserial,
+    to_inventory_id bigint NOT NULL,
+    from_inventory_id bigint NOT NULL,
+    transaction_type varchar(20) NOT NULL,
+    quantity_change bigint NOT NULL,
+    transaction_date timestamptz,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    deleted_by bigint,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_inventory_transactions_to_inventory_id FOREIGN KEY (to_inventory_id) REFERENCES inventories(id) ON DELETE RESTRICT,
+    CONSTRAINT fk_inventory_transactions_from_inventory_id FOREIGN KEY (from_inventory_id) REFERENCES inventories(id) ON DELETE RESTRICT
+);
+CREATE INDEX idx_inventory_transactions_deleted_at ON inventory_transactions (deleted_at);
+
+-- 配送表
+CREATE TABLE deliveries (
+    id bigserial,
+    delivery_id varchar(50) NOT NULL UNIQUE,
+    quantity bigint NOT NULL,
+    recipient_name varchar(200),
+    recipient_contact varchar(100),
+    location_id bigint,
+    address varchar(300),
+    delivery_date timestamptz,
+    status varchar(20) DEFAULT 'pending',
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    deleted_by bigint,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_deliveries_location_id FOREIGN KEY (location_id) REFERENCES locations(id) ON DELETE SET NULL
+);
+CREATE INDEX idx_deliveries_deleted_at ON deliveries (deleted_at);
+
+-- 引用后建表的外键
+ALTER TABLE gifts ADD CONSTRAINT fk_gifts_delivery_id FOREIGN KEY (delivery_id) REFERENCES deliveries(id) ON DELETE SET NULL;
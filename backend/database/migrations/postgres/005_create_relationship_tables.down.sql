@@ -0,0 +1,7 @@
+-- 关联表
+DROP TABLE IF EXISTS schedules;
+DROP TABLE IF EXISTS delivery_inventories;
+DROP TABLE IF EXISTS donation_inventories;
+DROP TABLE IF EXISTS fund_projects;
+DROP TABLE IF EXISTS employee_projects;
+DROP TABLE IF EXISTS volunteer_projects;
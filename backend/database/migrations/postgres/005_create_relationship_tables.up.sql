@@ -0,0 +1,119 @@
+-- 关联表
+
+-- 志愿者-项目表
+CREATE TABLE volunteer_projects (
+    id bigserial,
+    volunteer_id bigint NOT NULL,
+    project_id bigint NOT NULL,
+    role varchar(100),
+    contract_start timestamptz,
+    contract_end timestamptz,
+    work_unit varchar(50),
+    total_amount decimal(10,2),
+    contract_date timestamptz,
+    contract_detail text,
+    status varchar(20) DEFAULT 'active',
+    created_at timestamptz,
+    deleted_at timestamptz,
+    deleted_by bigint,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_volunteer_projects_project_id FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE,
+    CONSTRAINT fk_volunteer_projects_volunteer_id FOREIGN KEY (volunteer_id) REFERENCES volunteers(id) ON DELETE CASCADE
+);
+CREATE INDEX idx_volunteer_projects_deleted_at ON volunteer_projects (deleted_at);
+
+-- 员工-项目表
+CREATE TABLE employee_projects (
+    id bigserial,
+    employee_id bigint NOT NULL,
+    project_id bigint NOT NULL,
+    title varchar(100),
+    start_date timestamptz,
+    end_date timestamptz,
+    work_unit varchar(50),
+    allocated_amount decimal(10,2),
+    last_updated timestamptz,
+    created_at timestamptz,
+    deleted_at timestamptz,
+    deleted_by bigint,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_employee_projects_project_id FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE,
+    CONSTRAINT fk_employee_projects_employee_id FOREIGN KEY (employee_id) REFERENCES employees(id) ON DELETE CASCADE
+);
+CREATE INDEX idx_employee_projects_deleted_at ON employee_projects (deleted_at);
+
+-- 基金拨款表
+CREATE TABLE fund_projects (
+    id bigserial,
+    transaction_id bigint NOT NULL,
+    project_id bigint NOT NULL,
+    fund_id bigint NOT NULL,
+    allocated_amount decimal(12,2) NOT NULL,
+    allocation_date timestamptz,
+    purpose text,
+    created_at timestamptz,
+    deleted_at timestamptz,
+    deleted_by bigint,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_fund_projects_project_id FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE RESTRICT,
+    CONSTRAINT fk_fund_projects_fund_id FOREIGN KEY (fund_id) REFERENCES funds(id) ON DELETE RESTRICT,
+    CONSTRAINT fk_fund_projects_transaction_id FOREIGN KEY (transaction_id) REFERENCES transactions(id) ON DELETE RESTRICT
+);
+CREATE INDEX idx_fund_projects_deleted_at ON fund_projects (deleted_at);
+
+-- 捐赠-库存表
+CREATE TABLE donation_inventories (
+    id bigserial,
+    donor_id bigint NOT NULL,
+    inventory_id bigint NOT NULL,
+    donation_date timestamptz,
+    project_id bigint,
+    quantity bigint DEFAULT 1,
+    estimated_value decimal(10,2),
+    created_at timestamptz,
+    deleted_at timestamptz,
+    deleted_by bigint,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_donation_inventories_project_id FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE SET NULL,
+    CONSTRAINT fk_donation_inventories_donor_id FOREIGN KEY (donor_id) REFERENCES donors(id) ON DELETE RESTRICT,
+    CONSTRAINT fk_donation_inventories_inventory_id FOREIGN KEY (inventory_id) REFERENCES inventories(id) ON DELETE RESTRICT
+);
+CREATE INDEX idx_donation_inventories_deleted_at ON donation_inventories (deleted_at);
+
+-- 配送-库存表
+CREATE TABLE delivery_inventories (
+    id bigserial,
+    delivery_id bigint NOT NULL,
+    inventory_id bigint NOT NULL,
+    quantity bigint DEFAULT 1,
+    unit_cost decimal(8,2),
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    deleted_by bigint,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_delivery_inventories_inventory_id FOREIGN KEY (inventory_id) REFERENCES inventories(id) ON DELETE RESTRICT,
+    CONSTRAINT fk_delivery_inventories_delivery_id FOREIGN KEY (delivery_id) REFERENCES deliveries(id) ON DELETE CASCADE
+);
+CREATE INDEX idx_delivery_inventories_deleted_at ON delivery_inventories (deleted_at);
+
+-- 排班表
+CREATE TABLE schedules (
+    id bigserial,
+    schedule_id varchar(20) NOT NULL UNIQUE,
+    person_id bigint NOT NULL,
+    person_type varchar(20) NOT NULL,
+    project_id bigint,
+    shift_date timestamptz,
+    start_time text,
+    end_time text,
+    hours_worked decimal(5,2),
+    status varchar(20) DEFAULT 'scheduled',
+    notes text,
+    created_at timestamptz,
+    deleted_at timestamptz,
+    deleted_by bigint,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_schedules_project_id FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE
+);
+CREATE INDEX idx_schedules_deleted_at ON schedules (deleted_at);
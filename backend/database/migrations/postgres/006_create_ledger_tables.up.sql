@@ -0,0 +1,50 @@
+-- 总账表 - 会计科目、凭证、分录
+
+-- 会计科目表
+CREATE TABLE accounts (
+    id bigserial,
+    code varchar(20) NOT NULL UNIQUE,
+    name varchar(200) NOT NULL,
+    type varchar(20) NOT NULL,
+    parent_id bigint,
+    is_active boolean DEFAULT true,
+    created_at timestamptz,
+    updated_at timestamptz,
+    PRIMARY KEY (id)
+);
+
+-- 记账凭证表
+CREATE TABLE journal_entries (
+    id bigserial,
+    transaction_id bigint,
+    source_type varchar(20) NOT NULL,
+    source_id bigint,
+    entry_date timestamptz NOT NULL,
+    description text,
+    reversal_of_id bigint,
+    reversed_by_id bigint,
+    created_at timestamptz,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_journal_entries_transaction_id FOREIGN KEY (transaction_id) REFERENCES transactions(id) ON DELETE RESTRICT
+);
+CREATE INDEX idx_journal_entries_entry_date ON journal_entries (entry_date);
+CREATE INDEX idx_journal_source ON journal_entries (source_type,source_id);
+
+-- 凭证分录表
+CREATE TABLE journal_lines (
+    id bigserial,
+    journal_entry_id bigint NOT NULL,
+    account_id bigint NOT NULL,
+    fund_id bigint,
+    project_id bigint,
+    debit decimal(12,2) DEFAULT 0,
+    credit decimal(12,2) DEFAULT 0,
+    memo text,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_journal_lines_project_id FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE RESTRICT,
+    CONSTRAINT fk_journal_lines_fund_id FOREIGN KEY (fund_id) REFERENCES funds(id) ON DELETE RESTRICT,
+    CONSTRAINT fk_journal_lines_journal_entry_id FOREIGN KEY (journal_entry_id) REFERENCES journal_entries(id) ON DELETE CASCADE,
+    CONSTRAINT fk_journal_lines_account_id FOREIGN KEY (account_id) REFERENCES accounts(id) ON DELETE RESTRICT
+);
+CREATE INDEX idx_journal_lines_account_id ON journal_lines (account_id);
+CREATE INDEX idx_journal_lines_journal_entry_id ON journal_lines (journal_entry_id);
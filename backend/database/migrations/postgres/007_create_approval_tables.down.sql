@@ -0,0 +1,3 @@
+-- 支出审批表
+DROP TABLE IF EXISTS approval_thresholds;
+DROP TABLE IF EXISTS expense_approvals;
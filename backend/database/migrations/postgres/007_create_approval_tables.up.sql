@@ -0,0 +1,32 @@
+-- 支出审批表
+
+-- 审批记录表
+CREATE TABLE expense_approvals (
+    id bigserial,
+    expense_id bigint NOT NULL,
+    action varchar(20) NOT NULL,
+    from_status varchar(20),
+    to_status varchar(20) NOT NULL,
+    user_id bigint,
+    employee_id bigint,
+    amount decimal(10,2),
+    comment text,
+    created_at timestamptz,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_expense_approvals_user_id FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE RESTRICT,
+    CONSTRAINT fk_expense_approvals_employee_id FOREIGN KEY (employee_id) REFERENCES employees(id) ON DELETE RESTRICT,
+    CONSTRAINT fk_expense_approvals_expense_id FOREIGN KEY (expense_id) REFERENCES expenses(id) ON DELETE CASCADE
+);
+CREATE INDEX idx_expense_approvals_expense_id ON expense_approvals (expense_id);
+
+-- 审批额度表
+CREATE TABLE approval_thresholds (
+    id bigserial,
+    department varchar(100),
+    position varchar(100) NOT NULL,
+    max_amount decimal(12,2) NOT NULL,
+    created_at timestamptz,
+    updated_at timestamptz,
+    PRIMARY KEY (id)
+);
+CREATE UNIQUE INDEX idx_threshold_dept_pos ON approval_thresholds (department,position);
@@ -0,0 +1,42 @@
+-- 角色与权限表
+
+-- 权限表
+CREATE TABLE permissions (
+    id bigserial,
+    resource varchar(50) NOT NULL,
+    action varchar(50) NOT NULL,
+    description text,
+    PRIMARY KEY (id)
+);
+CREATE UNIQUE INDEX idx_permission ON permissions (resource,action);
+
+-- 角色表
+CREATE TABLE roles (
+    id bigserial,
+    name varchar(50) NOT NULL UNIQUE,
+    description text,
+    is_system boolean DEFAULT false,
+    created_at timestamptz,
+    updated_at timestamptz,
+    require_two_factor boolean DEFAULT false,
+    PRIMARY KEY (id)
+);
+
+-- 角色-权限表
+CREATE TABLE role_permissions (
+    role_id bigint,
+    permission_id bigint,
+    PRIMARY KEY (role_id,permission_id),
+    CONSTRAINT fk_role_permissions_role_id FOREIGN KEY (role_id) REFERENCES roles(id) ON DELETE CASCADE,
+    CONSTRAINT fk_role_permissions_permission_id FOREIGN KEY (permission_id) REFERENCES permissions(id) ON DELETE CASCADE
+);
+
+-- 用户-角色表
+CREATE TABLE user_roles (
+    user_id bigint,
+    role_id bigint,
+    created_at timestamptz,
+    PRIMARY KEY (user_id,role_id),
+    CONSTRAINT fk_user_roles_user_id FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT fk_user_roles_role_id FOREIGN KEY (role_id) REFERENCES roles(id) ON DELETE CASCADE
+);
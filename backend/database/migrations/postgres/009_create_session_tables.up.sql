@@ -0,0 +1,116 @@
+-- 登录会话与账户安全表
+
+-- 登录会话表
+CREATE TABLE sessions (
+    id bigserial,
+    session_id varchar(64) NOT NULL,
+    user_id bigint NOT NULL,
+    user_agent varchar(255),
+    client_ip varchar(64),
+    expires_at timestamptz,
+    last_used_at timestamptz,
+    revoked_at timestamptz,
+    revoke_reason varchar(30),
+    created_at timestamptz,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_sessions_user_id FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+CREATE INDEX idx_sessions_user_id ON sessions (user_id);
+CREATE UNIQUE INDEX idx_sessions_session_id ON sessions (session_id);
+
+-- 刷新令牌表
+CREATE TABLE refresh_tokens (
+    id bigserial,
+    session_id bigint NOT NULL,
+    token_hash varchar(64) NOT NULL,
+    expires_at timestamptz,
+    used_at timestamptz,
+    created_at timestamptz,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_refresh_tokens_session_id FOREIGN KEY (session_id) REFERENCES sessions(id) ON DELETE CASCADE
+);
+CREATE INDEX idx_refresh_tokens_session_id ON refresh_tokens (session_id);
+CREATE UNIQUE INDEX idx_refresh_tokens_token_hash ON refresh_tokens (token_hash);
+
+-- 密码重置令牌表
+CREATE TABLE password_reset_tokens (
+    id bigserial,
+    user_id bigint NOT NULL,
+    token_hash varchar(64) NOT NULL,
+    expires_at timestamptz,
+    used_at timestamptz,
+    created_at timestamptz,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_password_reset_tokens_user_id FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+CREATE INDEX idx_password_reset_tokens_user_id ON password_reset_tokens (user_id);
+CREATE UNIQUE INDEX idx_password_reset_tokens_token_hash ON password_reset_tokens (token_hash);
+
+-- 登录尝试表
+CREATE TABLE login_attempts (
+    id bigserial,
+    username varchar(100),
+    user_id bigint,
+    client_ip varchar(64),
+    user_agent varchar(255),
+    success boolean,
+    reason varchar(30),
+    created_at timestamptz,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_login_attempts_user_id FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE SET NULL
+);
+CREATE INDEX idx_login_attempts_client_ip ON login_attempts (client_ip);
+CREATE INDEX idx_login_attempts_created_at ON login_attempts (created_at);
+CREATE INDEX idx_login_attempts_user_id ON login_attempts (user_id);
+CREATE INDEX idx_login_attempts_username ON login_attempts (username);
+
+-- 账户锁定表
+CREATE TABLE account_lockouts (
+    user_id bigint,
+    failed_count bigint,
+    lock_count bigint,
+    locked_until timestamptz,
+    updated_at timestamptz,
+    PRIMARY KEY (user_id),
+    CONSTRAINT fk_account_lockouts_user_id FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- 双因素认证表
+CREATE TABLE two_factors (
+    user_id bigint,
+    secret text NOT NULL,
+    enabled boolean DEFAULT false,
+    confirmed_at timestamptz,
+    last_used_step bigint,
+    created_at timestamptz,
+    updated_at timestamptz,
+    PRIMARY KEY (user_id),
+    CONSTRAINT fk_two_factors_user_id FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- 恢复码表
+CREATE TABLE recovery_codes (
+    id bigserial,
+    user_id bigint NOT NULL,
+    code_hash varchar(64) NOT NULL,
+    used_at timestamptz,
+    created_at timestamptz,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_recovery_codes_user_id FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+CREATE INDEX idx_recovery_codes_user_id ON recovery_codes (user_id);
+
+-- 两步登录挑战表
+CREATE TABLE login_challenges (
+    id bigserial,
+    token_hash varchar(64) NOT NULL,
+    user_id bigint NOT NULL,
+    attempts bigint,
+    expires_at timestamptz,
+    used_at timestamptz,
+    created_at timestamptz,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_login_challenges_user_id FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+CREATE INDEX idx_login_challenges_user_id ON login_challenges (user_id);
+CREATE UNIQUE INDEX idx_login_challenges_token_hash ON login_challenges (token_hash);
@@ -0,0 +1,2 @@
+-- 员工注册审核表
+DROP TABLE IF EXISTS registration_reviews;
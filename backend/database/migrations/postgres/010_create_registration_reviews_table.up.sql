@@ -0,0 +1,19 @@
+-- 员工注册审核表
+
+-- 注册审核表
+CREATE TABLE registration_reviews (
+    id bigserial,
+    user_id bigint NOT NULL,
+    employee_id bigint NOT NULL,
+    decision varchar(20) NOT NULL,
+    reason text,
+    department varchar(100),
+    position varchar(100),
+    reviewed_by bigint,
+    created_at timestamptz,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_registration_reviews_user_id FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT fk_registration_reviews_employee_id FOREIGN KEY (employee_id) REFERENCES employees(id) ON DELETE CASCADE
+);
+CREATE INDEX idx_registration_reviews_employee_id ON registration_reviews (employee_id);
+CREATE INDEX idx_registration_reviews_user_id ON registration_reviews (user_id);
@@ -0,0 +1,3 @@
+-- 审计日志表（只允许追加）
+DROP TABLE IF EXISTS audit_logs;
+DROP FUNCTION IF EXISTS audit_logs_append_only();
@@ -0,0 +1,31 @@
+-- 审计日志表（只允许追加）
+
+-- 审计日志表
+CREATE TABLE audit_logs (
+    id bigserial,
+    user_id bigint,
+    username varchar(100),
+    action varchar(10),
+    table_name varchar(64),
+    record_id varchar(64),
+    changes text,
+    client_ip varchar(64),
+    created_at timestamptz,
+    PRIMARY KEY (id)
+);
+CREATE INDEX idx_audit_logs_action ON audit_logs (action);
+CREATE INDEX idx_audit_logs_created_at ON audit_logs (created_at);
+CREATE INDEX idx_audit_logs_record_id ON audit_logs (record_id);
+CREATE INDEX idx_audit_logs_table ON audit_logs (table_name);
+CREATE INDEX idx_audit_logs_user_id ON audit_logs (user_id);
+
+-- 禁止修改或删除审计日志，绕过应用程序的写入同样被拒绝
+CREATE FUNCTION audit_logs_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+CREATE TRIGGER audit_logs_no_update BEFORE UPDATE ON audit_logs
+FOR EACH ROW EXECUTE FUNCTION audit_logs_append_only();
+CREATE TRIGGER audit_logs_no_delete BEFORE DELETE ON audit_logs
+FOR EACH ROW EXECUTE FUNCTION audit_logs_append_only();
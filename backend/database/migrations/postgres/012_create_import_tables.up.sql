@@ -0,0 +1,31 @@
+-- 批量导入表
+
+-- 导入列映射模板表
+CREATE TABLE import_mappings (
+    id bigserial,
+    resource varchar(50) NOT NULL,
+    name varchar(100) NOT NULL,
+    mapping text NOT NULL,
+    created_by bigint,
+    created_at timestamptz,
+    updated_at timestamptz,
+    PRIMARY KEY (id)
+);
+CREATE UNIQUE INDEX idx_import_mappings_resource_name ON import_mappings (resource,name);
+
+-- 导入记录表
+CREATE TABLE import_jobs (
+    id bigserial,
+    resource varchar(50) NOT NULL,
+    filename varchar(255),
+    mode varchar(10) NOT NULL,
+    dry_run boolean,
+    total_rows bigint,
+    imported bigint,
+    failed bigint,
+    errors text,
+    created_by bigint,
+    created_at timestamptz,
+    PRIMARY KEY (id)
+);
+CREATE INDEX idx_import_jobs_resource ON import_jobs (resource);
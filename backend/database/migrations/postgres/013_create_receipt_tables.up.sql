@@ -0,0 +1,30 @@
+-- 年度捐赠收据表
+
+-- 收据表
+CREATE TABLE tax_receipts (
+    id bigserial,
+    receipt_number varchar(30) NOT NULL UNIQUE,
+    year bigint NOT NULL,
+    donor_id bigint NOT NULL,
+    status varchar(10) NOT NULL,
+    cash_total decimal(12,2),
+    in_kind_total decimal(12,2),
+    content text NOT NULL,
+    replaces_id bigint,
+    replaced_by_id bigint,
+    issued_by bigint,
+    issued_at timestamptz NOT NULL,
+    voided_by bigint,
+    voided_at timestamptz,
+    void_reason text,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_tax_receipts_donor_id FOREIGN KEY (donor_id) REFERENCES donors(id) ON DELETE RESTRICT
+);
+CREATE INDEX idx_tax_receipts_donor_year ON tax_receipts (year,donor_id);
+
+-- 收据号计数表
+CREATE TABLE receipt_sequences (
+    year bigint,
+    last_number bigint NOT NULL,
+    PRIMARY KEY (year)
+);
@@ -0,0 +1,14 @@
+-- 实物捐赠估值与入库
+DROP TABLE IF EXISTS in_kind_valuations;
+
+-- 恢复库存变动表原结构，单边变动的空库存以另一边补齐
+DROP INDEX IF EXISTS idx_inventory_transactions_source;
+ALTER TABLE inventory_transactions DROP COLUMN reversed_by_id;
+ALTER TABLE inventory_transactions DROP COLUMN reversal_of_id;
+ALTER TABLE inventory_transactions DROP COLUMN source_id;
+ALTER TABLE inventory_transactions DROP COLUMN source_type;
+UPDATE inventory_transactions SET to_inventory_id = COALESCE(to_inventory_id, from_inventory_id), from_inventory_id = COALESCE(from_inventory_id, to_inventory_id);
+ALTER TABLE inventory_transactions ALTER COLUMN to_inventory_id SET NOT NULL;
+ALTER TABLE inventory_transactions ALTER COLUMN from_inventory_id SET NOT NULL;
+
+ALTER TABLE donors DROP COLUMN in_kind_total;
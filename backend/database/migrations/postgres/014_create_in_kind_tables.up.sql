@@ -0,0 +1,28 @@
+-- 实物捐赠估值与入库
+
+-- 捐赠者的实物捐赠估值累计
+ALTER TABLE donors ADD COLUMN in_kind_total decimal DEFAULT 0;
+
+-- 库存变动：单边变动的出库或入库库存可以为空，并记录来源单据与冲销关系
+ALTER TABLE inventory_transactions ALTER COLUMN to_inventory_id DROP NOT NULL;
+ALTER TABLE inventory_transactions ALTER COLUMN from_inventory_id DROP NOT NULL;
+ALTER TABLE inventory_transactions ADD COLUMN source_type varchar(20);
+ALTER TABLE inventory_transactions ADD COLUMN source_id bigint;
+ALTER TABLE inventory_transactions ADD COLUMN reversal_of_id bigint;
+ALTER TABLE inventory_transactions ADD COLUMN reversed_by_id bigint;
+CREATE INDEX idx_inventory_transactions_source ON inventory_transactions (source_type,source_id);
+
+-- 实物捐赠估值表
+CREATE TABLE in_kind_valuations (
+    id bigserial,
+    donation_inventory_id bigint NOT NULL UNIQUE,
+    basis varchar(20) NOT NULL,
+    unit_value decimal(10,2) NOT NULL,
+    total_value decimal(12,2) NOT NULL,
+    appraiser varchar(200),
+    notes text,
+    valued_by bigint,
+    valued_at timestamptz NOT NULL,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_in_kind_valuations_donation_inventory_id FOREIGN KEY (donation_inventory_id) REFERENCES donation_inventories(id) ON DELETE CASCADE
+);
@@ -0,0 +1,12 @@
+-- 库存台账期初：库存数量改由库存变动维护，为每项库存补记一笔调整，
+-- 使库存变动累计数量与已有的账面库存一致
+
+INSERT INTO inventory_transactions (to_inventory_id, from_inventory_id, transaction_type, quantity_change, transaction_date, source_type, created_at, updated_at)
+SELECT CASE WHEN d.drift > 0 THEN d.id END, CASE WHEN d.drift < 0 THEN d.id END, 'adjustment', ABS(d.drift), CURRENT_TIMESTAMP, 'opening_balance', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP
+FROM (
+    SELECT i.id, i.current_stock
+        - COALESCE((SELECT SUM(t.quantity_change) FROM inventory_transactions t WHERE t.to_inventory_id = i.id), 0)
+        + COALESCE((SELECT SUM(t.quantity_change) FROM inventory_transactions t WHERE t.from_inventory_id = i.id), 0) AS drift
+    FROM inventories i
+) d
+WHERE d.drift <> 0;
@@ -0,0 +1,5 @@
+-- 库存计价
+DROP TABLE IF EXISTS inventory_cost_methods;
+ALTER TABLE deliveries DROP COLUMN project_id;
+ALTER TABLE inventory_transactions DROP COLUMN purchase_id;
+ALTER TABLE inventory_transactions DROP COLUMN unit_cost;
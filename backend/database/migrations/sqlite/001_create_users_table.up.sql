@@ -0,0 +1,16 @@
+-- 用户表（核心认证表）
+
+-- 用户表
+CREATE TABLE IF NOT EXISTS users (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    username TEXT NOT NULL UNIQUE,
+    password_hash TEXT NOT NULL,
+    user_type TEXT NOT NULL,
+    status TEXT DEFAULT 'active',
+    created_at DATETIME,
+    updated_at DATETIME,
+    last_login DATETIME,
+    deleted_at DATETIME,
+    deleted_by INTEGER
+);
+CREATE INDEX IF NOT EXISTS idx_users_deleted_at ON users(deleted_at);
@@ -0,0 +1,117 @@
+-- 核心实体表 - 地点、项目、捐赠者、志愿者、员工
+
+-- 地点表
+CREATE TABLE IF NOT EXISTS locations (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    location_id TEXT NOT NULL,
+    name TEXT NOT NULL,
+    type TEXT,
+    address TEXT,
+    country_code TEXT,
+    created_at DATETIME,
+    updated_at DATETIME,
+    deleted_at DATETIME,
+    deleted_by INTEGER
+);
+CREATE INDEX IF NOT EXISTS idx_locations_deleted_at ON locations(deleted_at);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_locations_location_id ON locations(location_id);
+
+-- 项目表
+CREATE TABLE IF NOT EXISTS projects (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    project_id TEXT NOT NULL,
+    name TEXT NOT NULL,
+    description TEXT,
+    project_type TEXT,
+    budget REAL,
+    actual_cost REAL DEFAULT 0,
+    location_id INTEGER,
+    start_date DATETIME,
+    end_date DATETIME,
+    status TEXT DEFAULT 'planning',
+    created_at DATETIME,
+    updated_at DATETIME,
+    deleted_at DATETIME,
+    deleted_by INTEGER,
+    CONSTRAINT fk_projects_location_id FOREIGN KEY (location_id) REFERENCES locations(id) ON DELETE SET NULL
+);
+CREATE INDEX IF NOT EXISTS idx_projects_deleted_at ON projects(deleted_at);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_projects_project_id ON projects(project_id);
+
+-- 捐赠者表
+CREATE TABLE IF NOT EXISTS donors (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER,
+    donor_id TEXT NOT NULL,
+    first_name TEXT NOT NULL,
+    last_name TEXT NOT NULL,
+    email TEXT,
+    phone TEXT,
+    address TEXT,
+    donor_type TEXT DEFAULT 'individual',
+    total_donated REAL DEFAULT 0,
+    enrollment_date DATETIME DEFAULT CURRENT_DATE,
+    status TEXT DEFAULT 'active',
+    notes TEXT,
+    created_at DATETIME,
+    updated_at DATETIME,
+    deleted_at DATETIME,
+    deleted_by INTEGER,
+    CONSTRAINT fk_donors_user_id FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE SET NULL
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_donors_donor_id ON donors(donor_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_donors_user_id ON donors(user_id);
+CREATE INDEX IF NOT EXISTS idx_donors_deleted_at ON donors(deleted_at);
+
+-- 志愿者表
+CREATE TABLE IF NOT EXISTS volunteers (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER,
+    volunteer_id TEXT NOT NULL,
+    first_name TEXT NOT NULL,
+    last_name TEXT NOT NULL,
+    email TEXT,
+    phone TEXT,
+    location_id INTEGER,
+    skills TEXT,
+    availability TEXT,
+    hours_contributed REAL DEFAULT 0,
+    status TEXT DEFAULT 'active',
+    notes TEXT,
+    created_at DATETIME,
+    updated_at DATETIME,
+    deleted_at DATETIME,
+    deleted_by INTEGER,
+    CONSTRAINT fk_volunteers_user_id FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE SET NULL,
+    CONSTRAINT fk_volunteers_location_id FOREIGN KEY (location_id) REFERENCES locations(id) ON DELETE SET NULL
+);
+CREATE INDEX IF NOT EXISTS idx_volunteers_deleted_at ON volunteers(deleted_at);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_volunteers_volunteer_id ON volunteers(volunteer_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_volunteers_user_id ON volunteers(user_id);
+
+-- 员工表
+CREATE TABLE IF NOT EXISTS employees (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER,
+    employee_id TEXT NOT NULL,
+    first_name TEXT NOT NULL,
+    last_name TEXT NOT NULL,
+    email TEXT,
+    phone TEXT,
+    position TEXT,
+    department TEXT,
+    salary REAL,
+    hire_date DATETIME DEFAULT CURRENT_DATE,
+    location_id INTEGER,
+    status TEXT DEFAULT 'active',
+    notes TEXT,
+    created_at DATETIME,
+    updated_at DATETIME,
+    deleted_at DATETIME,
+    deleted_by INTEGER,
+    CONSTRAINT fk_employees_user_id FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE SET NULL,
+    CONSTRAINT fk_employees_location_id FOREIGN KEY (location_id) REFERENCES locations(id) ON DELETE SET NULL
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_employees_employee_id ON employees(employee_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_employees_user_id ON employees(user_id);
+CREATE INDEX IF NOT EXISTS idx_employees_deleted_at ON employees(deleted_at);
@@ -0,0 +1,128 @@
+-- 财务管理表 - 交易、捐赠、基金、支出、采购、薪资
+
+-- 交易表
+CREATE TABLE IF NOT EXISTS transactions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    transaction_id TEXT NOT NULL UNIQUE,
+    transaction_record TEXT,
+    type TEXT NOT NULL,
+    amount DECIMAL(12,2) NOT NULL,
+    from_currency TEXT NOT NULL,
+    to_currency TEXT NOT NULL,
+    from_entity TEXT,
+    to_entity TEXT,
+    transaction_date DATETIME,
+    created_at DATETIME,
+    updated_at DATETIME,
+    deleted_at DATETIME,
+    deleted_by INTEGER
+);
+CREATE INDEX IF NOT EXISTS idx_transactions_deleted_at ON transactions(deleted_at);
+
+-- 捐赠记录表
+CREATE TABLE IF NOT EXISTS donations (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    donation_id TEXT NOT NULL UNIQUE,
+    donor_id INTEGER NOT NULL,
+    amount DECIMAL(10,2) NOT NULL,
+    transaction_id INTEGER,
+    donation_type TEXT NOT NULL,
+    category TEXT NOT NULL,
+    project_id INTEGER,
+    fund_id INTEGER,
+    donation_date DATETIME,
+    payment_method TEXT,
+    notes TEXT,
+    created_at DATETIME,
+    updated_at DATETIME,
+    deleted_at DATETIME,
+    deleted_by INTEGER,
+    CONSTRAINT fk_donations_project_id FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE RESTRICT,
+    CONSTRAINT fk_donations_donor_id FOREIGN KEY (donor_id) REFERENCES donors(id) ON DELETE RESTRICT,
+    CONSTRAINT fk_donations_fund_id FOREIGN KEY (fund_id) REFERENCES funds(id) ON DELETE RESTRICT,
+    CONSTRAINT fk_donations_transaction_id FOREIGN KEY (transaction_id) REFERENCES transactions(id) ON DELETE RESTRICT
+);
+CREATE INDEX IF NOT EXISTS idx_donations_deleted_at ON donations(deleted_at);
+
+-- 基金表
+CREATE TABLE IF NOT EXISTS funds (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    fund_id TEXT NOT NULL UNIQUE,
+    donor_id INTEGER,
+    project_id INTEGER,
+    transaction_id INTEGER,
+    name TEXT NOT NULL,
+    fund_type TEXT NOT NULL,
+    total_amount DECIMAL(12,2) NOT NULL,
+    current_balance DECIMAL(12,2) DEFAULT 0,
+    status TEXT DEFAULT 'active',
+    created_at DATETIME,
+    updated_at DATETIME,
+    deleted_at DATETIME,
+    deleted_by INTEGER,
+    available_from DATETIME,
+    available_until DATETIME,
+    restricted_purpose TEXT,
+    CONSTRAINT fk_funds_project_id FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE SET NULL,
+    CONSTRAINT fk_funds_donor_id FOREIGN KEY (donor_id) REFERENCES donors(id) ON DELETE SET NULL,
+    CONSTRAINT fk_funds_transaction_id FOREIGN KEY (transaction_id) REFERENCES transactions(id) ON DELETE SET NULL
+);
+CREATE INDEX IF NOT EXISTS idx_funds_deleted_at ON funds(deleted_at);
+
+-- 支出表
+CREATE TABLE IF NOT EXISTS expenses (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    expense_id TEXT NOT NULL UNIQUE,
+    fund_id INTEGER NOT NULL,
+    project_id INTEGER,
+    employee_id INTEGER,
+    created_by INTEGER,
+    transaction_id INTEGER,
+    description TEXT NOT NULL,
+    amount DECIMAL(10,2) NOT NULL,
+    expense_date DATETIME,
+    approval_status TEXT DEFAULT 'draft',
+    created_at DATETIME,
+    updated_at DATETIME,
+    deleted_at DATETIME,
+    deleted_by INTEGER,
+    CONSTRAINT fk_expenses_project_id FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE RESTRICT,
+    CONSTRAINT fk_expenses_employee_id FOREIGN KEY (employee_id) REFERENCES employees(id) ON DELETE RESTRICT,
+    CONSTRAINT fk_expenses_created_by FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE SET NULL,
+    CONSTRAINT fk_expenses_fund_id FOREIGN KEY (fund_id) REFERENCES funds(id) ON DELETE RESTRICT,
+    CONSTRAINT fk_expenses_transaction_id FOREIGN KEY (transaction_id) REFERENCES transactions(id) ON DELETE RESTRICT
+);
+CREATE INDEX IF NOT EXISTS idx_expenses_deleted_at ON expenses(deleted_at);
+
+-- 采购表
+CREATE TABLE IF NOT EXISTS purchases (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    purchase_id TEXT NOT NULL UNIQUE,
+    transaction_id INTEGER,
+    total_spent DECIMAL(12,2) NOT NULL,
+    supplier_name TEXT,
+    purchase_date DATETIME,
+    description TEXT,
+    created_at DATETIME,
+    updated_at DATETIME,
+    deleted_at DATETIME,
+    deleted_by INTEGER,
+    CONSTRAINT fk_purchases_transaction_id FOREIGN KEY (transaction_id) REFERENCES transactions(id) ON DELETE RESTRICT
+);
+CREATE INDEX IF NOT EXISTS idx_purchases_deleted_at ON purchases(deleted_at);
+
+-- 薪资表
+CREATE TABLE IF NOT EXISTS payrolls (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    transaction_id INTEGER NOT NULL,
+    employee_id INTEGER NOT NULL,
+    amount DECIMAL(10,2) NOT NULL,
+    pay_date DATETIME,
+    created_at DATETIME,
+    updated_at DATETIME,
+    deleted_at DATETIME,
+    deleted_by INTEGER,
+    CONSTRAINT fk_payrolls_employee_id FOREIGN KEY (employee_id) REFERENCES employees(id) ON DELETE RESTRICT,
+    CONSTRAINT fk_payrolls_transaction_id FOREIGN KEY (transaction_id) REFERENCES transactions(id) ON DELETE RESTRICT
+);
+CREATE INDEX IF NOT EXISTS idx_payrolls_deleted_at ON payrolls(deleted_at);
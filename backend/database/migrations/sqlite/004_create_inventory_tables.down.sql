@@ -0,0 +1,6 @@
+-- 库存和礼品表
+DROP TABLE IF EXISTS deliveries;
+DROP TABLE IF EXISTS inventory_transactions;
+DROP TABLE IF EXISTS gifts;
+DROP TABLE IF EXISTS gift_types;
+DROP TABLE IF EXISTS inventories;
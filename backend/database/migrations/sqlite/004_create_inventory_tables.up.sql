@@ -0,0 +1,90 @@
+-- 库存和礼品表
+
+-- 库存表
+CREATE TABLE IF NOT EXISTS inventories (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    inventory_id TEXT NOT NULL UNIQUE,
+    name TEXT NOT NULL,
+    category TEXT,
+    purchase_id INTEGER,
+    location_id INTEGER,
+    current_stock INTEGER DEFAULT 0,
+    unit_cost DECIMAL(10,2),
+    status TEXT DEFAULT 'available',
+    created_at DATETIME,
+    updated_at DATETIME,
+    deleted_at DATETIME,
+    deleted_by INTEGER,
+    CONSTRAINT fk_inventories_location_id FOREIGN KEY (location_id) REFERENCES locations(id) ON DELETE SET NULL,
+    CONSTRAINT fk_inventories_purchase_id FOREIGN KEY (purchase_id) REFERENCES purchases(id) ON DELETE SET NULL
+);
+CREATE INDEX IF NOT EXISTS idx_inventories_deleted_at ON inventories(deleted_at);
+
+-- 礼品类型表
+CREATE TABLE IF NOT EXISTS gift_types (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL,
+    category TEXT,
+    unit_cost DECIMAL(8,2),
+    requires_inventory NUMERIC DEFAULT TRUE,
+    inventory_name TEXT NOT NULL,
+    created_at DATETIME,
+    updated_at DATETIME,
+    deleted_at DATETIME,
+    deleted_by INTEGER
+);
+CREATE INDEX IF NOT EXISTS idx_gift_types_deleted_at ON gift_types(deleted_at);
+
+-- 礼品表
+CREATE TABLE IF NOT EXISTS gifts (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    gift_id TEXT NOT NULL UNIQUE,
+    donation_id INTEGER,
+    delivery_id INTEGER,
+    gift_type_id INTEGER NOT NULL,
+    total_value DECIMAL(10,2),
+    created_at DATETIME,
+    updated_at DATETIME,
+    deleted_at DATETIME,
+    deleted_by INTEGER,
+    CONSTRAINT fk_gifts_donation_id FOREIGN KEY (donation_id) REFERENCES donations(id) ON DELETE CASCADE,
+    CONSTRAINT fk_gifts_gift_type_id FOREIGN KEY (gift_type_id) REFERENCES gift_types(id) ON DELETE RESTRICT,
+    CONSTRAINT fk_gifts_delivery_id FOREIGN KEY (delivery_id) REFERENCES deliveries(id) ON DELETE SET NULL
+);
+CREATE INDEX IF NOT EXISTS idx_gifts_deleted_at ON gifts(deleted_at);
+
+-- 库存调拨表
+CREATE TABLE IF NOT EXISTS inventory_transactions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    to_inventory_id INTEGER NOT NULL,
+    from_inventory_id INTEGER NOT NULL,
+    transaction_type TEXT NOT NULL,
+    quantity_change INTEGER NOT NULL,
+    transaction_date DATETIME,
+    created_at DATETIME,
+    updated_at DATETIME,
+    deleted_at DATETIME,
+    deleted_by INTEGER,
+    CONSTRAINT fk_inventory_transactions_to_inventory_id FOREIGN KEY (to_inventory_id) REFERENCES inventories(id) ON DELETE RESTRICT,
+    CONSTRAINT fk_inventory_transactions_from_inventory_id FOREIGN KEY (from_inventory_id) REFERENCES inventories(id) ON DELETE RESTRICT
+);
+CREATE INDEX IF NOT EXISTS idx_inventory_transactions_deleted_at ON inventory_transactions(deleted_at);
+
+-- 配送表
+CREATE TABLE IF NOT EXISTS deliveries (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    delivery_id TEXT NOT NULL UNIQUE,
+    quantity INTEGER NOT NULL,
+    recipient_name TEXT,
+    recipient_contact TEXT,
+    location_id INTEGER,
+    address TEXT,
+    delivery_date DATETIME,
+    status TEXT DEFAULT 'pending',
+    created_at DATETIME,
+    updated_at DATETIME,
+    deleted_at DATETIME,
+    deleted_by INTEGER,
+    CONSTRAINT fk_deliveries_location_id FOREIGN KEY (location_id) REFERENCES locations(id) ON DELETE SET NULL
+);
+CREATE INDEX IF NOT EXISTS idx_deliveries_deleted_at ON deliveries(deleted_at);
@@ -0,0 +1,113 @@
+-- 关联表
+
+-- 志愿者-项目表
+CREATE TABLE IF NOT EXISTS volunteer_projects (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    volunteer_id INTEGER NOT NULL,
+    project_id INTEGER NOT NULL,
+    role TEXT,
+    contract_start DATETIME,
+    contract_end DATETIME,
+    work_unit TEXT,
+    total_amount DECIMAL(10,2),
+    contract_date DATETIME,
+    contract_detail TEXT,
+    status TEXT DEFAULT 'active',
+    created_at DATETIME,
+    deleted_at DATETIME,
+    deleted_by INTEGER,
+    CONSTRAINT fk_volunteer_projects_project_id FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE,
+    CONSTRAINT fk_volunteer_projects_volunteer_id FOREIGN KEY (volunteer_id) REFERENCES volunteers(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_volunteer_projects_deleted_at ON volunteer_projects(deleted_at);
+
+-- 员工-项目表
+CREATE TABLE IF NOT EXISTS employee_projects (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    employee_id INTEGER NOT NULL,
+    project_id INTEGER NOT NULL,
+    title TEXT,
+    start_date DATETIME,
+    end_date DATETIME,
+    work_unit TEXT,
+    allocated_amount DECIMAL(10,2),
+    last_updated DATETIME,
+    created_at DATETIME,
+    deleted_at DATETIME,
+    deleted_by INTEGER,
+    CONSTRAINT fk_employee_projects_project_id FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE,
+    CONSTRAINT fk_employee_projects_employee_id FOREIGN KEY (employee_id) REFERENCES employees(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_employee_projects_deleted_at ON employee_projects(deleted_at);
+
+-- 基金拨款表
+CREATE TABLE IF NOT EXISTS fund_projects (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    transaction_id INTEGER NOT NULL,
+    project_id INTEGER NOT NULL,
+    fund_id INTEGER NOT NULL,
+    allocated_amount DECIMAL(12,2) NOT NULL,
+    allocation_date DATETIME,
+    purpose TEXT,
+    created_at DATETIME,
+    deleted_at DATETIME,
+    deleted_by INTEGER,
+    CONSTRAINT fk_fund_projects_project_id FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE RESTRICT,
+    CONSTRAINT fk_fund_projects_fund_id FOREIGN KEY (fund_id) REFERENCES funds(id) ON DELETE RESTRICT,
+    CONSTRAINT fk_fund_projects_transaction_id FOREIGN KEY (transaction_id) REFERENCES transactions(id) ON DELETE RESTRICT
+);
+CREATE INDEX IF NOT EXISTS idx_fund_projects_deleted_at ON fund_projects(deleted_at);
+
+-- 捐赠-库存表
+CREATE TABLE IF NOT EXISTS donation_inventories (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    donor_id INTEGER NOT NULL,
+    inventory_id INTEGER NOT NULL,
+    donation_date DATETIME,
+    project_id INTEGER,
+    quantity INTEGER DEFAULT 1,
+    estimated_value DECIMAL(10,2),
+    created_at DATETIME,
+    deleted_at DATETIME,
+    deleted_by INTEGER,
+    CONSTRAINT fk_donation_inventories_project_id FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE SET NULL,
+    CONSTRAINT fk_donation_inventories_donor_id FOREIGN KEY (donor_id) REFERENCES donors(id) ON DELETE RESTRICT,
+    CONSTRAINT fk_donation_inventories_inventory_id FOREIGN KEY (inventory_id) REFERENCES inventories(id) ON DELETE RESTRICT
+);
+CREATE INDEX IF NOT EXISTS idx_donation_inventories_deleted_at ON donation_inventories(deleted_at);
+
+-- 配送-库存表
+CREATE TABLE IF NOT EXISTS delivery_inventories (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    delivery_id INTEGER NOT NULL,
+    inventory_id INTEGER NOT NULL,
+    quantity INTEGER DEFAULT 1,
+    unit_cost DECIMAL(8,2),
+    created_at DATETIME,
+    updated_at DATETIME,
+    deleted_at DATETIME,
+    deleted_by INTEGER,
+    CONSTRAINT fk_delivery_inventories_inventory_id FOREIGN KEY (inventory_id) REFERENCES inventories(id) ON DELETE RESTRICT,
+    CONSTRAINT fk_delivery_inventories_delivery_id FOREIGN KEY (delivery_id) REFERENCES deliveries(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_delivery_inventories_deleted_at ON delivery_inventories(deleted_at);
+
+-- 排班表
+CREATE TABLE IF NOT EXISTS schedules (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    schedule_id TEXT NOT NULL UNIQUE,
+    person_id INTEGER NOT NULL,
+    person_type TEXT NOT NULL,
+    project_id INTEGER,
+    shift_date DATETIME,
+    start_time TEXT,
+    end_time TEXT,
+    hours_worked DECIMAL(5,2),
+    status TEXT DEFAULT 'scheduled',
+    notes TEXT,
+    created_at DATETIME,
+    deleted_at DATETIME,
+    deleted_by INTEGER,
+    CONSTRAINT fk_schedules_project_id FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_schedules_deleted_at ON schedules(deleted_at);
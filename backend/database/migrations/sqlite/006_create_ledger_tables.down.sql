@@ -0,0 +1,4 @@
+-- 总账表 - 会计科目、凭证、分录
+DROP TABLE IF EXISTS journal_lines;
+DROP TABLE IF EXISTS journal_entries;
+DROP TABLE IF EXISTS accounts;
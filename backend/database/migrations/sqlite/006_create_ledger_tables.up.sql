@@ -0,0 +1,47 @@
+-- 总账表 - 会计科目、凭证、分录
+
+-- 会计科目表
+CREATE TABLE IF NOT EXISTS accounts (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    code TEXT NOT NULL UNIQUE,
+    name TEXT NOT NULL,
+    type TEXT NOT NULL,
+    parent_id INTEGER,
+    is_active NUMERIC DEFAULT TRUE,
+    created_at DATETIME,
+    updated_at DATETIME
+);
+
+-- 记账凭证表
+CREATE TABLE IF NOT EXISTS journal_entries (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    transaction_id INTEGER,
+    source_type TEXT NOT NULL,
+    source_id INTEGER,
+    entry_date DATETIME NOT NULL,
+    description TEXT,
+    reversal_of_id INTEGER,
+    reversed_by_id INTEGER,
+    created_at DATETIME,
+    CONSTRAINT fk_journal_entries_transaction_id FOREIGN KEY (transaction_id) REFERENCES transactions(id) ON DELETE RESTRICT
+);
+CREATE INDEX IF NOT EXISTS idx_journal_source ON journal_entries(source_type,source_id);
+CREATE INDEX IF NOT EXISTS idx_journal_entries_entry_date ON journal_entries(entry_date);
+
+-- 凭证分录表
+CREATE TABLE IF NOT EXISTS journal_lines (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    journal_entry_id INTEGER NOT NULL,
+    account_id INTEGER NOT NULL,
+    fund_id INTEGER,
+    project_id INTEGER,
+    debit DECIMAL(12,2) DEFAULT 0,
+    credit DECIMAL(12,2) DEFAULT 0,
+    memo TEXT,
+    CONSTRAINT fk_journal_lines_project_id FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE RESTRICT,
+    CONSTRAINT fk_journal_lines_fund_id FOREIGN KEY (fund_id) REFERENCES funds(id) ON DELETE RESTRICT,
+    CONSTRAINT fk_journal_lines_journal_entry_id FOREIGN KEY (journal_entry_id) REFERENCES journal_entries(id) ON DELETE CASCADE,
+    CONSTRAINT fk_journal_lines_account_id FOREIGN KEY (account_id) REFERENCES accounts(id) ON DELETE RESTRICT
+);
+CREATE INDEX IF NOT EXISTS idx_journal_lines_account_id ON journal_lines(account_id);
+CREATE INDEX IF NOT EXISTS idx_journal_lines_journal_entry_id ON journal_lines(journal_entry_id);
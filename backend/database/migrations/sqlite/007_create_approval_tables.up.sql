@@ -0,0 +1,30 @@
+-- 支出审批表
+
+-- 审批记录表
+CREATE TABLE IF NOT EXISTS expense_approvals (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    expense_id INTEGER NOT NULL,
+    action TEXT NOT NULL,
+    from_status TEXT,
+    to_status TEXT NOT NULL,
+    user_id INTEGER,
+    employee_id INTEGER,
+    amount DECIMAL(10,2),
+    comment TEXT,
+    created_at DATETIME,
+    CONSTRAINT fk_expense_approvals_user_id FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE RESTRICT,
+    CONSTRAINT fk_expense_approvals_employee_id FOREIGN KEY (employee_id) REFERENCES employees(id) ON DELETE RESTRICT,
+    CONSTRAINT fk_expense_approvals_expense_id FOREIGN KEY (expense_id) REFERENCES expenses(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_expense_approvals_expense_id ON expense_approvals(expense_id);
+
+-- 审批额度表
+CREATE TABLE IF NOT EXISTS approval_thresholds (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    department TEXT,
+    position TEXT NOT NULL,
+    max_amount DECIMAL(12,2) NOT NULL,
+    created_at DATETIME,
+    updated_at DATETIME
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_threshold_dept_pos ON approval_thresholds(department,position);
@@ -0,0 +1,40 @@
+-- 角色与权限表
+
+-- 权限表
+CREATE TABLE IF NOT EXISTS permissions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    resource TEXT NOT NULL,
+    action TEXT NOT NULL,
+    description TEXT
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_permission ON permissions(resource,action);
+
+-- 角色表
+CREATE TABLE IF NOT EXISTS roles (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL UNIQUE,
+    description TEXT,
+    is_system NUMERIC DEFAULT FALSE,
+    created_at DATETIME,
+    updated_at DATETIME,
+    require_two_factor NUMERIC DEFAULT FALSE
+);
+
+-- 角色-权限表
+CREATE TABLE IF NOT EXISTS role_permissions (
+    role_id INTEGER,
+    permission_id INTEGER,
+    PRIMARY KEY (role_id,permission_id),
+    CONSTRAINT fk_role_permissions_role_id FOREIGN KEY (role_id) REFERENCES roles(id) ON DELETE CASCADE,
+    CONSTRAINT fk_role_permissions_permission_id FOREIGN KEY (permission_id) REFERENCES permissions(id) ON DELETE CASCADE
+);
+
+-- 用户-角色表
+CREATE TABLE IF NOT EXISTS user_roles (
+    user_id INTEGER,
+    role_id INTEGER,
+    created_at DATETIME,
+    PRIMARY KEY (user_id,role_id),
+    CONSTRAINT fk_user_roles_user_id FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT fk_user_roles_role_id FOREIGN KEY (role_id) REFERENCES roles(id) ON DELETE CASCADE
+);
@@ -0,0 +1,110 @@
+-- 登录会话与账户安全表
+
+-- 登录会话表
+CREATE TABLE IF NOT EXISTS sessions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    session_id TEXT NOT NULL,
+    user_id INTEGER NOT NULL,
+    user_agent TEXT,
+    client_ip TEXT,
+    expires_at DATETIME,
+    last_used_at DATETIME,
+    revoked_at DATETIME,
+    revoke_reason TEXT,
+    created_at DATETIME,
+    CONSTRAINT fk_sessions_user_id FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_sessions_user_id ON sessions(user_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_sessions_session_id ON sessions(session_id);
+
+-- 刷新令牌表
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    session_id INTEGER NOT NULL,
+    token_hash TEXT NOT NULL,
+    expires_at DATETIME,
+    used_at DATETIME,
+    created_at DATETIME,
+    CONSTRAINT fk_refresh_tokens_session_id FOREIGN KEY (session_id) REFERENCES sessions(id) ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_refresh_tokens_token_hash ON refresh_tokens(token_hash);
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_session_id ON refresh_tokens(session_id);
+
+-- 密码重置令牌表
+CREATE TABLE IF NOT EXISTS password_reset_tokens (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL,
+    token_hash TEXT NOT NULL,
+    expires_at DATETIME,
+    used_at DATETIME,
+    created_at DATETIME,
+    CONSTRAINT fk_password_reset_tokens_user_id FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_password_reset_tokens_token_hash ON password_reset_tokens(token_hash);
+CREATE INDEX IF NOT EXISTS idx_password_reset_tokens_user_id ON password_reset_tokens(user_id);
+
+-- 登录尝试表
+CREATE TABLE IF NOT EXISTS login_attempts (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    username TEXT,
+    user_id INTEGER,
+    client_ip TEXT,
+    user_agent TEXT,
+    success NUMERIC,
+    reason TEXT,
+    created_at DATETIME,
+    CONSTRAINT fk_login_attempts_user_id FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE SET NULL
+);
+CREATE INDEX IF NOT EXISTS idx_login_attempts_created_at ON login_attempts(created_at);
+CREATE INDEX IF NOT EXISTS idx_login_attempts_client_ip ON login_attempts(client_ip);
+CREATE INDEX IF NOT EXISTS idx_login_attempts_user_id ON login_attempts(user_id);
+CREATE INDEX IF NOT EXISTS idx_login_attempts_username ON login_attempts(username);
+
+-- 账户锁定表
+CREATE TABLE IF NOT EXISTS account_lockouts (
+    user_id INTEGER,
+    failed_count INTEGER,
+    lock_count INTEGER,
+    locked_until DATETIME,
+    updated_at DATETIME,
+    PRIMARY KEY (user_id),
+    CONSTRAINT fk_account_lockouts_user_id FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- 双因素认证表
+CREATE TABLE IF NOT EXISTS two_factors (
+    user_id INTEGER,
+    secret TEXT NOT NULL,
+    enabled NUMERIC DEFAULT FALSE,
+    confirmed_at DATETIME,
+    last_used_step INTEGER,
+    created_at DATETIME,
+    updated_at DATETIME,
+    PRIMARY KEY (user_id),
+    CONSTRAINT fk_two_factors_user_id FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- 恢复码表
+CREATE TABLE IF NOT EXISTS recovery_codes (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL,
+    code_hash TEXT NOT NULL,
+    used_at DATETIME,
+    created_at DATETIME,
+    CONSTRAINT fk_recovery_codes_user_id FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_recovery_codes_user_id ON recovery_codes(user_id);
+
+-- 两步登录挑战表
+CREATE TABLE IF NOT EXISTS login_challenges (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    token_hash TEXT NOT NULL,
+    user_id INTEGER NOT NULL,
+    attempts INTEGER,
+    expires_at DATETIME,
+    used_at DATETIME,
+    created_at DATETIME,
+    CONSTRAINT fk_login_challenges_user_id FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_login_challenges_user_id ON login_challenges(user_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_login_challenges_token_hash ON login_challenges(token_hash);
@@ -0,0 +1,18 @@
+-- 员工注册审核表
+
+-- 注册审核表
+CREATE TABLE IF NOT EXISTS registration_reviews (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL,
+    employee_id INTEGER NOT NULL,
+    decision TEXT NOT NULL,
+    reason TEXT,
+    department TEXT,
+    position TEXT,
+    reviewed_by INTEGER,
+    created_at DATETIME,
+    CONSTRAINT fk_registration_reviews_user_id FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT fk_registration_reviews_employee_id FOREIGN KEY (employee_id) REFERENCES employees(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_registration_reviews_employee_id ON registration_reviews(employee_id);
+CREATE INDEX IF NOT EXISTS idx_registration_reviews_user_id ON registration_reviews(user_id);
@@ -0,0 +1,29 @@
+-- 批量导入表
+
+-- 导入列映射模板表
+CREATE TABLE IF NOT EXISTS import_mappings (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    resource TEXT NOT NULL,
+    name TEXT NOT NULL,
+    mapping TEXT NOT NULL,
+    created_by INTEGER,
+    created_at DATETIME,
+    updated_at DATETIME
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_import_mappings_resource_name ON import_mappings(resource,name);
+
+-- 导入记录表
+CREATE TABLE IF NOT EXISTS import_jobs (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    resource TEXT NOT NULL,
+    filename TEXT,
+    mode TEXT NOT NULL,
+    dry_run NUMERIC,
+    total_rows INTEGER,
+    imported INTEGER,
+    failed INTEGER,
+    errors TEXT,
+    created_by INTEGER,
+    created_at DATETIME
+);
+CREATE INDEX IF NOT EXISTS idx_import_jobs_resource ON import_jobs(resource);
@@ -0,0 +1,3 @@
+-- 年度捐赠收据表
+DROP TABLE IF EXISTS receipt_sequences;
+DROP TABLE IF EXISTS tax_receipts;
@@ -0,0 +1,28 @@
+-- 年度捐赠收据表
+
+-- 收据表
+CREATE TABLE IF NOT EXISTS tax_receipts (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    receipt_number TEXT NOT NULL UNIQUE,
+    year INTEGER NOT NULL,
+    donor_id INTEGER NOT NULL,
+    status TEXT NOT NULL,
+    cash_total DECIMAL(12,2),
+    in_kind_total DECIMAL(12,2),
+    content TEXT NOT NULL,
+    replaces_id INTEGER,
+    replaced_by_id INTEGER,
+    issued_by INTEGER,
+    issued_at DATETIME NOT NULL,
+    voided_by INTEGER,
+    voided_at DATETIME,
+    void_reason TEXT,
+    CONSTRAINT fk_tax_receipts_donor_id FOREIGN KEY (donor_id) REFERENCES donors(id) ON DELETE RESTRICT
+);
+CREATE INDEX IF NOT EXISTS idx_tax_receipts_donor_year ON tax_receipts(year,donor_id);
+
+-- 收据号计数表
+CREATE TABLE IF NOT EXISTS receipt_sequences (
+    year INTEGER PRIMARY KEY,
+    last_number INTEGER NOT NULL
+);
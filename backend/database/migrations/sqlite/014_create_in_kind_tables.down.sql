@@ -0,0 +1,25 @@
+-- 实物捐赠估值与入库
+DROP TABLE IF EXISTS in_kind_valuations;
+
+-- 恢复库存变动表原结构，单边变动的空库存以另一边补齐
+CREATE TABLE inventory_transactions_old (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    to_inventory_id INTEGER NOT NULL,
+    from_inventory_id INTEGER NOT NULL,
+    transaction_type TEXT NOT NULL,
+    quantity_change INTEGER NOT NULL,
+    transaction_date DATETIME,
+    created_at DATETIME,
+    updated_at DATETIME,
+    deleted_at DATETIME,
+    deleted_by INTEGER,
+    CONSTRAINT fk_inventory_transactions_to_inventory_id FOREIGN KEY (to_inventory_id) REFERENCES inventories(id) ON DELETE RESTRICT,
+    CONSTRAINT fk_inventory_transactions_from_inventory_id FOREIGN KEY (from_inventory_id) REFERENCES inventories(id) ON DELETE RESTRICT
+);
+INSERT INTO inventory_transactions_old (id, to_inventory_id, from_inventory_id, transaction_type, quantity_change, transaction_date, created_at, updated_at, deleted_at, deleted_by)
+    SELECT id, COALESCE(to_inventory_id, from_inventory_id), COALESCE(from_inventory_id, to_inventory_id), transaction_type, quantity_change, transaction_date, created_at, updated_at, deleted_at, deleted_by FROM inventory_transactions;
+DROP TABLE inventory_transactions;
+ALTER TABLE inventory_transactions_old RENAME TO inventory_transactions;
+CREATE INDEX IF NOT EXISTS idx_inventory_transactions_deleted_at ON inventory_transactions(deleted_at);
+
+ALTER TABLE donors DROP COLUMN in_kind_total;
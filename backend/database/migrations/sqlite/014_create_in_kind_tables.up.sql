@@ -0,0 +1,44 @@
+-- 实物捐赠估值与入库
+
+-- 捐赠者的实物捐赠估值累计
+ALTER TABLE donors ADD COLUMN in_kind_total REAL DEFAULT 0;
+
+-- 库存变动：单边变动的出库或入库库存可以为空，并记录来源单据与冲销关系
+CREATE TABLE inventory_transactions_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    to_inventory_id INTEGER,
+    from_inventory_id INTEGER,
+    transaction_type TEXT NOT NULL,
+    quantity_change INTEGER NOT NULL,
+    transaction_date DATETIME,
+    source_type TEXT,
+    source_id INTEGER,
+    reversal_of_id INTEGER,
+    reversed_by_id INTEGER,
+    created_at DATETIME,
+    updated_at DATETIME,
+    deleted_at DATETIME,
+    deleted_by INTEGER,
+    CONSTRAINT fk_inventory_transactions_to_inventory_id FOREIGN KEY (to_inventory_id) REFERENCES inventories(id) ON DELETE RESTRICT,
+    CONSTRAINT fk_inventory_transactions_from_inventory_id FOREIGN KEY (from_inventory_id) REFERENCES inventories(id) ON DELETE RESTRICT
+);
+INSERT INTO inventory_transactions_new (id, to_inventory_id, from_inventory_id, transaction_type, quantity_change, transaction_date, created_at, updated_at, deleted_at, deleted_by)
+    SELECT id, to_inventory_id, from_inventory_id, transaction_type, quantity_change, transaction_date, created_at, updated_at, deleted_at, deleted_by FROM inventory_transactions;
+DROP TABLE inventory_transactions;
+ALTER TABLE inventory_transactions_new RENAME TO inventory_transactions;
+CREATE INDEX IF NOT EXISTS idx_inventory_transactions_deleted_at ON inventory_transactions(deleted_at);
+CREATE INDEX IF NOT EXISTS idx_inventory_transactions_source ON inventory_transactions(source_type,source_id);
+
+-- 实物捐赠估值表
+CREATE TABLE IF NOT EXISTS in_kind_valuations (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    donation_inventory_id INTEGER NOT NULL UNIQUE,
+    basis TEXT NOT NULL,
+    unit_value DECIMAL(10,2) NOT NULL,
+    total_value DECIMAL(12,2) NOT NULL,
+    appraiser TEXT,
+    notes TEXT,
+    valued_by INTEGER,
+    valued_at DATETIME NOT NULL,
+    CONSTRAINT fk_in_kind_valuations_donation_inventory_id FOREIGN KEY (donation_inventory_id) REFERENCES donation_inventories(id) ON DELETE CASCADE
+);
@@ -0,0 +1,50 @@
+-- 库存计价
+DROP TABLE IF EXISTS inventory_cost_methods;
+
+-- 外键列不能直接删除，重建库存变动表与配送表
+CREATE TABLE inventory_transactions_old (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    to_inventory_id INTEGER,
+    from_inventory_id INTEGER,
+    transaction_type TEXT NOT NULL,
+    quantity_change INTEGER NOT NULL,
+    transaction_date DATETIME,
+    source_type TEXT,
+    source_id INTEGER,
+    reversal_of_id INTEGER,
+    reversed_by_id INTEGER,
+    created_at DATETIME,
+    updated_at DATETIME,
+    deleted_at DATETIME,
+    deleted_by INTEGER,
+    CONSTRAINT fk_inventory_transactions_to_inventory_id FOREIGN KEY (to_inventory_id) REFERENCES inventories(id) ON DELETE RESTRICT,
+    CONSTRAINT fk_inventory_transactions_from_inventory_id FOREIGN KEY (from_inventory_id) REFERENCES inventories(id) ON DELETE RESTRICT
+);
+INSERT INTO inventory_transactions_old (id, to_inventory_id, from_inventory_id, transaction_type, quantity_change, transaction_date, source_type, source_id, reversal_of_id, reversed_by_id, created_at, updated_at, deleted_at, deleted_by)
+    SELECT id, to_inventory_id, from_inventory_id, transaction_type, quantity_change, transaction_date, source_type, source_id, reversal_of_id, reversed_by_id, created_at, updated_at, deleted_at, deleted_by FROM inventory_transactions;
+DROP TABLE inventory_transactions;
+ALTER TABLE inventory_transactions_old RENAME TO inventory_transactions;
+CREATE INDEX IF NOT EXISTS idx_inventory_transactions_deleted_at ON inventory_transactions(deleted_at);
+CREATE INDEX IF NOT EXISTS idx_inventory_transactions_source ON inventory_transactions(source_type,source_id);
+
+CREATE TABLE deliveries_old (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    delivery_id TEXT NOT NULL UNIQUE,
+    quantity INTEGER NOT NULL,
+    recipient_name TEXT,
+    recipient_contact TEXT,
+    location_id INTEGER,
+    address TEXT,
+    delivery_date DATETIME,
+    status TEXT DEFAULT 'pending',
+    created_at DATETIME,
+    updated_at DATETIME,
+    deleted_at DATETIME,
+    deleted_by INTEGER,
+    CONSTRAINT fk_deliveries_location_id FOREIGN KEY (location_id) REFERENCES locations(id) ON DELETE SET NULL
+);
+INSERT INTO deliveries_old (id, delivery_id, quantity, recipient_name, recipient_contact, location_id, address, delivery_date, status, created_at, updated_at, deleted_at, deleted_by)
+    SELECT id, delivery_id, quantity, recipient_name, recipient_contact, location_id, address, delivery_date, status, created_at, updated_at, deleted_at, deleted_by FROM deliveries;
+DROP TABLE deliveries;
+ALTER TABLE deliveries_old RENAME TO deliveries;
+CREATE INDEX IF NOT EXISTS idx_deliveries_deleted_at ON deliveries(deleted_at);
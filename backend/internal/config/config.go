@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"path/filepath"
+	"time"
 
 	"github.com/spf13/viper"
 )
@@ -14,6 +15,53 @@ type Config struct {
 	Html_Path   string `mapstructure:"HTML_PATH"`
 	DB_Path     string `mapstructure:"DB_PATH"`
 	Encrypt_Seed string `mapstructure:"ENCRYPT_SEED"`
+	Admin_Users  string `mapstructure:"ADMIN_USERS"` // comma-separated usernames granted the admin role at startup
+	Access_Token_TTL  time.Duration `mapstructure:"ACCESS_TOKEN_TTL"`  // lifetime of JWT access tokens, e.g. 15m
+	Refresh_Token_TTL time.Duration `mapstructure:"REFRESH_TOKEN_TTL"` // idle lifetime of a login session's refresh token, e.g. 168h
+	Notification_Log string `mapstructure:"NOTIFICATION_LOG"` // file the default notifier appends user notifications to
+	Public_URL string `mapstructure:"PUBLIC_URL"` // externally reachable base URL, used for links in emails
+
+	// Outgoing mail; when SMTP_HOST is empty notifications go to NOTIFICATION_LOG instead
+	SMTP_Host     string `mapstructure:"SMTP_HOST"`
+	SMTP_Port     string `mapstructure:"SMTP_PORT"`
+	SMTP_Username string `mapstructure:"SMTP_USERNAME"` // leave empty for servers without auth (e.g. a local SMTP stub)
+	SMTP_Password string `mapstructure:"SMTP_PASSWORD"`
+	SMTP_From     string `mapstructure:"SMTP_FROM"`
+
+	// Password policy
+	Password_Min_Length     int    `mapstructure:"PASSWORD_MIN_LENGTH"`
+	Password_Require_Upper  bool   `mapstructure:"PASSWORD_REQUIRE_UPPER"`
+	Password_Require_Lower  bool   `mapstructure:"PASSWORD_REQUIRE_LOWER"`
+	Password_Require_Digit  bool   `mapstructure:"PASSWORD_REQUIRE_DIGIT"`
+	Password_Require_Symbol bool   `mapstructure:"PASSWORD_REQUIRE_SYMBOL"`
+	Password_Breached_List  string `mapstructure:"PASSWORD_BREACHED_LIST"` // file of breached passwords or SHA-1 hashes, one per line; empty disables the check
+	Password_Reset_TTL time.Duration `mapstructure:"PASSWORD_RESET_TTL"`
+
+	// Login brute-force protection
+	Login_Max_Failures    int           `mapstructure:"LOGIN_MAX_FAILURES"`    // consecutive failures before an account locks; 0 disables account lockout
+	Login_Lockout_Base    time.Duration `mapstructure:"LOGIN_LOCKOUT_BASE"`    // first lockout, doubled on each further lockout
+	Login_Lockout_Max     time.Duration `mapstructure:"LOGIN_LOCKOUT_MAX"`     // cap on a single lockout
+	Login_IP_Max_Failures int           `mapstructure:"LOGIN_IP_MAX_FAILURES"` // failures allowed per client IP within LOGIN_IP_WINDOW; 0 disables
+	Login_IP_Window       time.Duration `mapstructure:"LOGIN_IP_WINDOW"`
+
+	// Two-factor authentication; TOTP secrets are encrypted with a key derived from ENCRYPT_SEED
+	Two_Factor_Issuer        string        `mapstructure:"TWO_FACTOR_ISSUER"`        // issuer name shown in authenticator apps
+	Two_Factor_Challenge_TTL time.Duration `mapstructure:"TWO_FACTOR_CHALLENGE_TTL"` // time allowed to enter the code after the password step
+
+	// Organisation details printed on donor tax receipts
+	Org_Name          string `mapstructure:"ORG_NAME"`
+	Org_Address       string `mapstructure:"ORG_ADDRESS"`
+	Org_Tax_ID        string `mapstructure:"ORG_TAX_ID"`        // charity registration or tax number
+	Org_Contact       string `mapstructure:"ORG_CONTACT"`       // phone, email or website shown on receipts
+	Receipt_Signatory string `mapstructure:"RECEIPT_SIGNATORY"` // name and title of the official signing receipts
+
+	// Database connection; DB_DRIVER is sqlite (default), postgres or mysql
+	DB_Driver string `mapstructure:"DB_DRIVER"`
+	DB_DSN    string `mapstructure:"DB_DSN"` // driver-specific connection string; empty with sqlite means DB_PATH
+	DB_Max_Open_Conns     int           `mapstructure:"DB_MAX_OPEN_CONNS"` // 0 means unlimited
+	DB_Max_Idle_Conns     int           `mapstructure:"DB_MAX_IDLE_CONNS"`
+	DB_Conn_Max_Lifetime  time.Duration `mapstructure:"DB_CONN_MAX_LIFETIME"`  // 0 keeps connections open indefinitely
+	DB_Conn_Max_Idle_Time time.Duration `mapstructure:"DB_CONN_MAX_IDLE_TIME"` // 0 keeps idle connections indefinitely
 	//JWTSecret string `mapstructure:"JWT_SECRET"`
 }
 
@@ -29,6 +77,41 @@ func Load() *Config {
 	viper.SetDefault("HTML_PATH", filepath.Join("..", "..", "frontend", "templates"))
 	viper.SetDefault("DB_PATH", filepath.Join("..", "data", "erp.db"))
 	viper.SetDefault("ENCRYPT_SEED", "This is a random seed: ahdgcv-ajweory943gb;caP.'CK[QW]")
+	viper.SetDefault("ADMIN_USERS", "")
+	viper.SetDefault("ACCESS_TOKEN_TTL", "15m")
+	viper.SetDefault("REFRESH_TOKEN_TTL", "168h")
+	viper.SetDefault("NOTIFICATION_LOG", "./notifications.log")
+	viper.SetDefault("PUBLIC_URL", "http://localhost:33031")
+	viper.SetDefault("SMTP_HOST", "")
+	viper.SetDefault("SMTP_PORT", "25")
+	viper.SetDefault("SMTP_USERNAME", "")
+	viper.SetDefault("SMTP_PASSWORD", "")
+	viper.SetDefault("SMTP_FROM", "no-reply@localhost")
+	viper.SetDefault("PASSWORD_MIN_LENGTH", 8)
+	viper.SetDefault("PASSWORD_REQUIRE_UPPER", true)
+	viper.SetDefault("PASSWORD_REQUIRE_LOWER", true)
+	viper.SetDefault("PASSWORD_REQUIRE_DIGIT", true)
+	viper.SetDefault("PASSWORD_REQUIRE_SYMBOL", false)
+	viper.SetDefault("PASSWORD_BREACHED_LIST", "")
+	viper.SetDefault("PASSWORD_RESET_TTL", "30m")
+	viper.SetDefault("LOGIN_MAX_FAILURES", 5)
+	viper.SetDefault("LOGIN_LOCKOUT_BASE", "1m")
+	viper.SetDefault("LOGIN_LOCKOUT_MAX", "24h")
+	viper.SetDefault("LOGIN_IP_MAX_FAILURES", 20)
+	viper.SetDefault("LOGIN_IP_WINDOW", "15m")
+	viper.SetDefault("TWO_FACTOR_ISSUER", "MIS for ECF")
+	viper.SetDefault("TWO_FACTOR_CHALLENGE_TTL", "5m")
+	viper.SetDefault("ORG_NAME", "ECF")
+	viper.SetDefault("ORG_ADDRESS", "")
+	viper.SetDefault("ORG_TAX_ID", "")
+	viper.SetDefault("ORG_CONTACT", "")
+	viper.SetDefault("RECEIPT_SIGNATORY", "")
+	viper.SetDefault("DB_DRIVER", "sqlite")
+	viper.SetDefault("DB_DSN", "")
+	viper.SetDefault("DB_MAX_OPEN_CONNS", 0)
+	viper.SetDefault("DB_MAX_IDLE_CONNS", 2)
+	viper.SetDefault("DB_CONN_MAX_LIFETIME", "0s")
+	viper.SetDefault("DB_CONN_MAX_IDLE_TIME", "0s")
 	//viper.SetDefault("JWT_SECRET", "your-secret-key")
 
 	//viper.AutomaticEnv()
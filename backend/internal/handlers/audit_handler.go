@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"erp-backend/internal/repo"
+	"erp-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AuditHandler 审计日志查询
+type AuditHandler struct {
+	auditService *services.AuditService
+}
+
+func NewAuditHandler(as *services.AuditService) *AuditHandler {
+	return &AuditHandler{auditService: as}
+}
+
+// GET /api/v1/audit?user_id=&table=donations&record_id=&action=update&from=2024-01-01&to=2024-01-31&limit=200
+// from/to 为日期（含当天）
+func (h *AuditHandler) GetLogs(c *gin.Context) {
+	userID, ok := optionalUserID(c)
+	if !ok {
+		return
+	}
+	from, err := parseDatePtr(c.Query("from"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid from, expected YYYY-MM-DD"})
+		return
+	}
+	to, err := parseDatePtr(c.Query("to"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid to, expected YYYY-MM-DD"})
+		return
+	}
+	if to != nil {
+		end := to.AddDate(0, 0, 1)
+		to = &end
+	}
+	limit := 200
+	if s := c.Query("limit"); s != "" {
+		if limit, err = strconv.Atoi(s); err != nil || limit <= 0 || limit > 1000 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and 1000"})
+			return
+		}
+	}
+	list, err := h.auditService.Search(repo.AuditFilter{
+		UserID:   userID,
+		Table:    c.Query("table"),
+		RecordID: c.Query("record_id"),
+		Action:   c.Query("action"),
+		From:     from,
+		To:       to,
+		Limit:    limit,
+	})
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": list, "count": len(list)})
+}
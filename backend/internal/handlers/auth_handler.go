@@ -1,8 +1,11 @@
 package handlers
 
 import (
+	"errors"
 	"log"
+	"math"
 	"net/http"
+	"strconv"
 
 	"erp-backend/internal/services"
 
@@ -33,6 +36,7 @@ func (h *AuthHandler) Register(c *gin.Context) {
 	}
 
 	log.Println("Parsed registration request:", req)
+	req.UserAgent, req.ClientIP = c.Request.UserAgent(), c.ClientIP()
 
 	resp, err := h.authService.Register(&req)
 	log.Println("Registration response:", resp, "Error:", err)
@@ -44,9 +48,13 @@ func (h *AuthHandler) Register(c *gin.Context) {
 		return
 	}
 
+	message := "Registration successful"
+	if resp.Status == "pending" {
+		message = "Registration submitted; your account will be activated once an administrator approves it"
+	}
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
-		"message": "Registration successful",
+		"message": message,
 		"data":    resp,
 	})
 	log.Println("User registered successfully:", c)
@@ -64,7 +72,17 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
+	req.UserAgent, req.ClientIP = c.Request.UserAgent(), c.ClientIP()
 	resp, err := h.authService.Login(&req)
+	var lockErr *services.LockoutError
+	if errors.As(err, &lockErr) {
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(lockErr.RetryAfter.Seconds()))))
+		c.JSON(http.StatusTooManyRequests, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusUnauthorized, gin.H{
 			"success": false,
@@ -94,8 +112,46 @@ func (h *AuthHandler) ShowRegisterPage(c *gin.Context) {
 	})
 }
 
-// Logout 用户登出
+// ShowResetPasswordPage 显示找回密码页面（申请重置邮件，或通过 ?token= 设置新密码）
+func (h *AuthHandler) ShowResetPasswordPage(c *gin.Context) {
+	c.HTML(http.StatusOK, "reset-password.html", gin.H{
+		"title": "Reset Password",
+	})
+}
+
+// Refresh 用刷新令牌换取新令牌；旧的刷新令牌随即作废
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	var req services.RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Request parameter error: " + err.Error(),
+		})
+		return
+	}
+
+	resp, err := h.authService.Refresh(&req)
+	if err != nil {
+		respondAuthError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Token refreshed",
+		"data":    resp,
+	})
+}
+
+// Logout 用户登出：终止当前会话，访问令牌与刷新令牌立即失效
 func (h *AuthHandler) Logout(c *gin.Context) {
+	if err := h.authService.Logout(c.GetString("session_id")); err != nil && !errors.Is(err, services.ErrNotFound) {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
 		"message": "Logout successful",
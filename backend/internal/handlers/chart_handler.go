@@ -50,6 +50,18 @@ func serializeLinePts(pts []repo.LinePoint) (out []map[string]interface{}, total
 	return []map[string]interface{}{series}, total, avg
 }
 
+// serializeSeries 按名称输出多条折线，total 与 avg 为各条之和
+func serializeSeries(list []services.ChartSeries) (out []map[string]interface{}, total float64, avg float64) {
+	for _, sr := range list {
+		series, t, a := serializeLinePts(sr.Points)
+		series[0]["name"] = sr.Name
+		out = append(out, series[0])
+		total += t
+		avg += a
+	}
+	return out, total, avg
+}
+
 func serializePiePts(pts []repo.PiePoint) (pie []map[string]interface{}, total float64, avg float64) {
 	out := make([]map[string]interface{}, len(pts))
 	count := float64(len(pts))
@@ -62,7 +74,7 @@ func serializePiePts(pts []repo.PiePoint) (pie []map[string]interface{}, total f
 	return out, total, avg
 }
 
-// GET /api/v1/don/charts/donations-by-donor?start=2025-01-01&end=2025-12-31
+// GET /api/v1/don/charts/donations-by-donor?start=2025-01-01&end=2025-12-31&kind=all  kind 为 cash、in_kind 或 all（默认）
 func (h *ChartHandler) DonorDonations(c *gin.Context) {
 	donorID, _ := c.Get("role_id")
 
@@ -77,13 +89,13 @@ func (h *ChartHandler) DonorDonations(c *gin.Context) {
 		return
 	}
 
-	pts, err := h.chartService.DonationsByDonor(donorID.(uint), start, end)
+	list, err := h.chartService.DonationsByDonor(donorID.(uint), c.DefaultQuery("kind", services.DonationKindAll), start, end)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondServiceError(c, err)
 		return
 	}
 
-	series, total, avg := serializeLinePts(pts)
+	series, total, avg := serializeSeries(list)
 
 	c.JSON(http.StatusOK, gin.H{
 		"title":  "Donations by Date",
@@ -93,7 +105,7 @@ func (h *ChartHandler) DonorDonations(c *gin.Context) {
 	})
 }
 
-// Get /api/v1/don/charts/donations-by-project?start=...&end=...
+// Get /api/v1/don/charts/donations-by-project?start=...&end=...&kind=all
 func (h *ChartHandler) DonorDonationsByProject(c *gin.Context) {
 	donorID, _ := c.Get("role_id")
 
@@ -109,9 +121,9 @@ func (h *ChartHandler) DonorDonationsByProject(c *gin.Context) {
 		return
 	}
 
-	pts, err := h.chartService.DonorDonationsByProject(donorID.(uint), start, end)
+	pts, err := h.chartService.DonorDonationsByProject(donorID.(uint), c.DefaultQuery("kind", services.DonationKindAll), start, end)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondServiceError(c, err)
 		return
 	}
 	pie, total, avg := serializePiePts(pts)
@@ -138,7 +150,7 @@ func (h *ChartHandler) FundAllocations(c *gin.Context) {
 		return
 	}
 
-	pts, err := h.chartService.FundAllocationsByDate(start, end)
+	pts, err := h.chartService.Scoped(projectScope(c)).FundAllocationsByDate(start, end)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -167,7 +179,7 @@ func (h *ChartHandler) FundAllocationsByProject(c *gin.Context) {
 		return
 	}
 
-	pts, err := h.chartService.FundAllocationsByProject(start, end)
+	pts, err := h.chartService.Scoped(projectScope(c)).FundAllocationsByProject(start, end)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -195,7 +207,7 @@ func (h *ChartHandler) Expenses(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid end date"})
 		return
 	}
-	pts, err := h.chartService.ExpensesByDate(start, end)
+	pts, err := h.chartService.Scoped(projectScope(c)).ExpensesByDate(start, end)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -223,7 +235,7 @@ func (h *ChartHandler) ExpensesByProject(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid end date"})
 		return
 	}
-	pts, err := h.chartService.ExpensesByProject(start, end)
+	pts, err := h.chartService.Scoped(projectScope(c)).ExpensesByProject(start, end)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -239,7 +251,7 @@ func (h *ChartHandler) ExpensesByProject(c *gin.Context) {
 	})
 }
 
-// GET /api/v1/charts/donations?start=...&end=...
+// GET /api/v1/charts/donations?start=...&end=...&kind=all
 func (h *ChartHandler) Donations(c *gin.Context) {
 	start, err := parseDatePtr(c.Query("start"))
 	if err != nil {
@@ -251,13 +263,13 @@ func (h *ChartHandler) Donations(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid end date"})
 		return
 	}
-	pts, err := h.chartService.DonationsByDate(start, end)
+	list, err := h.chartService.Scoped(projectScope(c)).DonationsByDate(c.DefaultQuery("kind", services.DonationKindAll), start, end)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondServiceError(c, err)
 		return
 	}
 
-	series, total, avg := serializeLinePts(pts)
+	series, total, avg := serializeSeries(list)
 
 	c.JSON(http.StatusOK, gin.H{
 		"title":  "Donations by Date",
@@ -267,7 +279,7 @@ func (h *ChartHandler) Donations(c *gin.Context) {
 	})
 }
 
-// GET /api/v1/fin/charts/donations-by-project?start=...&end=...
+// GET /api/v1/fin/charts/donations-by-project?start=...&end=...&kind=all
 func (h *ChartHandler) DonationsByProject(c *gin.Context) {
 
 	start, err := parseDatePtr(c.Query("start"))
@@ -282,9 +294,9 @@ func (h *ChartHandler) DonationsByProject(c *gin.Context) {
 		return
 	}
 
-	pts, err := h.chartService.DonationsByProject(start, end)
+	pts, err := h.chartService.Scoped(projectScope(c)).DonationsByProject(c.DefaultQuery("kind", services.DonationKindAll), start, end)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondServiceError(c, err)
 		return
 	}
 
@@ -321,7 +333,7 @@ func (h *ChartHandler) VolunteerHours(c *gin.Context) {
 		return
 	}
 
-	pts, err := h.chartService.VolunteerHoursByVolunteer(uint(vid), start, end)
+	pts, err := h.chartService.Scoped(projectScope(c)).VolunteerHoursByVolunteer(uint(vid), start, end)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -329,7 +341,7 @@ func (h *ChartHandler) VolunteerHours(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"data": pts})
 }
 
-// GET /api/v1/don/charts/line/donations-by-donor?start=...&end=...
+// GET /api/v1/don/charts/line/donations-by-donor?start=...&end=...&kind=all
 func (h *ChartHandler) DonorDonationsLineChart(c *gin.Context) {
 	donorID, _ := c.Get("role_id")
 	start, err := parseDatePtr(c.Query("start"))
@@ -342,12 +354,12 @@ func (h *ChartHandler) DonorDonationsLineChart(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid end date"})
 		return
 	}
-	pts, err := h.chartService.DonationsByDonor(donorID.(uint), start, end)
+	list, err := h.chartService.DonationsByDonor(donorID.(uint), c.DefaultQuery("kind", services.DonationKindAll), start, end)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondServiceError(c, err)
 		return
 	}
-	series, total, avg := serializeLinePts(pts)
+	series, total, avg := serializeSeries(list)
 	c.JSON(http.StatusOK, gin.H{
 		"title":  "Donations by Date",
 		"series": series,
@@ -356,7 +368,7 @@ func (h *ChartHandler) DonorDonationsLineChart(c *gin.Context) {
 	})
 }
 
-// Get /api/v1/don/charts/pie/donations-by-project?start=...&end=...
+// Get /api/v1/don/charts/pie/donations-by-project?start=...&end=...&kind=all
 func (h *ChartHandler) DonorDonationsByProjectPieChart(c *gin.Context) {
 	donorID, _ := c.Get("role_id")
 
@@ -370,9 +382,9 @@ func (h *ChartHandler) DonorDonationsByProjectPieChart(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid end date"})
 		return
 	}
-	pts, err := h.chartService.DonorDonationsByProject(donorID.(uint), start, end)
+	pts, err := h.chartService.DonorDonationsByProject(donorID.(uint), c.DefaultQuery("kind", services.DonationKindAll), start, end)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondServiceError(c, err)
 		return
 	}
 	pie, total, avg := serializePiePts(pts)
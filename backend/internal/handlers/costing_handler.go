@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"net/http"
+
+	"erp-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CostingHandler 库存计价 API：类别的计价方法、库存估值与配送成本报表
+type CostingHandler struct {
+	costingService *services.CostingService
+}
+
+func NewCostingHandler(cs *services.CostingService) *CostingHandler {
+	return &CostingHandler{costingService: cs}
+}
+
+// GET /api/v1/fin/inventory/cost-methods
+func (h *CostingHandler) GetCostMethods(c *gin.Context) {
+	list, err := h.costingService.CostMethods()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": list, "count": len(list)})
+}
+
+// PUT /api/v1/fin/inventory/cost-methods
+// body: {"category": "Food", "method": "fifo"}
+func (h *CostingHandler) SetCostMethod(c *gin.Context) {
+	var req struct {
+		Category string `json:"category" binding:"required"`
+		Method   string `json:"method" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	m, err := h.costingService.WithContext(c.Request.Context()).SetCostMethod(req.Category, req.Method)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": m})
+}
+
+// GET /api/v1/fin/inventory/valuation?as_of=2025-12-31
+func (h *CostingHandler) Valuation(c *gin.Context) {
+	asOf, err := parseDatePtr(c.Query("as_of"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid as_of date"})
+		return
+	}
+	report, err := h.costingService.Valuation(asOf)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": report})
+}
+
+// GET /api/v1/fin/inventory/distribution-cost?start=2025-01-01&end=2025-12-31
+func (h *CostingHandler) DistributionCost(c *gin.Context) {
+	start, err := parseDatePtr(c.Query("start"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid start date"})
+		return
+	}
+	end, err := parseDatePtr(c.Query("end"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid end date"})
+		return
+	}
+	report, err := h.costingService.Scoped(projectScope(c)).DistributionCost(start, end)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": report})
+}
@@ -1,14 +1,19 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"reflect"
 	"strconv"
 	"strings"
 	"time"
 
+	"erp-backend/internal/middleware"
 	"erp-backend/internal/models"
+	"erp-backend/internal/repo"
 	"erp-backend/internal/services"
 
 	"github.com/gin-gonic/gin"
@@ -113,61 +118,236 @@ func notImplementedFilter(c *gin.Context) {
 	c.JSON(http.StatusNotImplemented, gin.H{"error": "Filter not implemented"})
 }
 
-// parseFilterParams reads query string params `query`, `number_range`, `date_range`
-// and unmarshals them into appropriate Go maps.
-func parseFilterParams(c *gin.Context) (map[string]interface{}, map[string][]interface{}, map[string][]string, error) {
-	var q map[string]interface{}
-	var nr map[string][]interface{}
-	var dr map[string][]string
+// respondServiceError 将服务层错误映射为对应的 HTTP 状态码
+func respondServiceError(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, services.ErrInvalidInput), errors.Is(err, repo.ErrInvalidListParams), errors.Is(err, repo.ErrInvalidFilter):
+		status = http.StatusBadRequest
+	case errors.Is(err, services.ErrNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, services.ErrConflict):
+		status = http.StatusConflict
+	case errors.Is(err, services.ErrForbidden):
+		status = http.StatusForbidden
+	case errors.Is(err, services.ErrUnauthorized):
+		status = http.StatusUnauthorized
+	case errors.Is(err, services.ErrTooManyRequests):
+		status = http.StatusTooManyRequests
+	}
+	var depErr *repo.DependentsError
+	if errors.As(err, &depErr) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "dependents": depErr.Dependents})
+		return
+	}
+	if repo.IsForeignKeyViolation(err) {
+		status = http.StatusConflict
+	}
+	var fundErr *services.FundError
+	if errors.As(err, &fundErr) {
+		c.JSON(status, gin.H{"error": err.Error(), "code": fundErr.Code, "detail": fundErr})
+		return
+	}
+	c.JSON(status, gin.H{"error": err.Error()})
+}
+
+// deleteContext 返回删除请求使用的 context：?force=cascade 需要 trash:force-delete 权限，
+// 此时按 restrict 策略引用的记录一并删除而不是阻止删除；已写入错误响应时返回 false
+func deleteContext(c *gin.Context) (context.Context, bool) {
+	ctx := c.Request.Context()
+	switch c.Query("force") {
+	case "":
+		return ctx, true
+	case "cascade":
+		if !middleware.HasPermission(c, models.ResourceTrash, models.ActionForceDelete) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "force=cascade requires trash:force-delete permission"})
+			return nil, false
+		}
+		return repo.WithForceCascade(ctx), true
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "force must be cascade"})
+		return nil, false
+	}
+}
+
+// projectScope 返回 ResolveProjectScope 中间件存入的调用者数据范围（nil 表示全机构）；
+// 未经该中间件的请求按不属于任何项目处理
+func projectScope(c *gin.Context) *repo.ProjectScope {
+	v, ok := c.Get(middleware.ProjectScopeKey)
+	if !ok {
+		return &repo.ProjectScope{}
+	}
+	scope, _ := v.(*repo.ProjectScope)
+	return scope
+}
+
+// parseFilterParams 读取过滤参数，合并为一个各部分之间为 AND 的条件树：
+//   - filter：JSON 条件树，如 {"or":[{"field":"project.name","op":"like","value":"%food%"},{"field":"amount","op":"between","value":[10,20]}]}，
+//     顶层也可以是条件数组（AND）。运算符见 repo.Filter
+//   - query：{列: 值}，字符串按 LIKE（不自动加通配符），其余按相等
+//   - number_range / date_range：{列: [最小, 最大]}，日期为 YYYY-MM-DD，空值表示不限
+//
+// 字段在仓储层按模型校验，无效时返回 repo.ErrInvalidFilter
+func parseFilterParams(c *gin.Context) (repo.Filter, error) {
+	var conds []repo.Filter
+
+	if s := c.Query("filter"); s != "" {
+		f, err := decodeFilter(s)
+		if err != nil {
+			return repo.Filter{}, err
+		}
+		conds = append(conds, f)
+	}
 
+	var q map[string]interface{}
 	if s := c.Query("query"); s != "" {
 		if err := json.Unmarshal([]byte(s), &q); err != nil {
-			for k, v := range q {
-				q[k] = "%" + fmt.Sprint(v) + "%"
-			}
-			return nil, nil, nil, err
+			return repo.Filter{}, err
+		}
+	}
+	for key, value := range q {
+		if value == "" || value == nil {
+			continue
 		}
-	} else {
-		q = map[string]interface{}{}
+		op := repo.OpEq
+		if _, ok := value.(string); ok {
+			op = repo.OpLike
+		}
+		conds = append(conds, repo.Filter{Field: key, Op: op, Value: value})
 	}
 
+	var nr map[string][]interface{}
 	if s := c.Query("number_range"); s != "" {
 		if err := json.Unmarshal([]byte(s), &nr); err != nil {
-			return nil, nil, nil, err
+			return repo.Filter{}, err
+		}
+	}
+	for key, pair := range nr {
+		if len(pair) > 0 && pair[0] != nil && pair[0] != "" {
+			conds = append(conds, repo.Filter{Field: key, Op: repo.OpGte, Value: pair[0]})
+		}
+		if len(pair) > 1 && pair[1] != nil && pair[1] != "" {
+			conds = append(conds, repo.Filter{Field: key, Op: repo.OpLte, Value: pair[1]})
 		}
-	} else {
-		nr = map[string][]interface{}{}
 	}
 
+	var dr map[string][]string
 	if s := c.Query("date_range"); s != "" {
 		if err := json.Unmarshal([]byte(s), &dr); err != nil {
-			return nil, nil, nil, err
+			return repo.Filter{}, err
 		}
-	} else {
-		dr = map[string][]string{}
 	}
-
-	// Validate and normalize date_range entries to YYYY-MM-DD
 	const layout = "2006-01-02"
 	for key, pair := range dr {
-		if len(pair) == 0 {
-			continue
-		}
-		// normalize each element if present, validate format
-		for i := 0; i < len(pair) && i < 2; i++ {
-			if pair[i] == "" {
+		for i, op := range []string{repo.OpGte, repo.OpLte} {
+			if i >= len(pair) || pair[i] == "" {
 				continue
 			}
 			t, err := time.Parse(layout, pair[i])
 			if err != nil {
-				return nil, nil, nil, fmt.Errorf("invalid date for %s: %s", key, pair[i])
+				return repo.Filter{}, fmt.Errorf("invalid date for %s: %s", key, pair[i])
 			}
-			pair[i] = t.Format(layout)
+			conds = append(conds, repo.Filter{Field: key, Op: op, Value: t.Format(layout)})
+		}
+	}
+
+	return repo.Filter{And: conds}, nil
+}
+
+// decodeFilter 解析 filter 参数；顶层为数组时各条件之间为 AND，未知的键视为错误
+func decodeFilter(s string) (repo.Filter, error) {
+	var f repo.Filter
+	dec := json.NewDecoder(strings.NewReader(s))
+	dec.DisallowUnknownFields()
+	if strings.HasPrefix(strings.TrimSpace(s), "[") {
+		var conds []repo.Filter
+		if err := dec.Decode(&conds); err != nil {
+			return f, fmt.Errorf("filter: %w", err)
+		}
+		f.And = conds
+	} else if err := dec.Decode(&f); err != nil {
+		return f, fmt.Errorf("filter: %w", err)
+	}
+	if f.IsZero() {
+		return f, errors.New("filter: empty condition")
+	}
+	return f, nil
+}
+
+// 列表接口的默认与最大每页行数
+const (
+	defaultPageSize = 50
+	maxPageSize     = 1000
+)
+
+// parseListParams 读取列表参数：page（默认 1）、page_size（默认 50，最大 1000）、
+// sort（逗号分隔的列名，前缀 - 表示降序，如 sort=-amount,donation_date）、fields（逗号分隔的列名）。
+// 带 cursor 参数时改用按 id 的游标分页，第一页传空值，之后传上一页返回的 next_cursor
+func parseListParams(c *gin.Context) (repo.ListParams, error) {
+	p := repo.ListParams{Page: 1, PageSize: defaultPageSize}
+	if s := c.Query("page"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 1 {
+			return p, errors.New("page must be a positive integer")
+		}
+		p.Page = n
+	}
+	if s := c.Query("page_size"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 1 || n > maxPageSize {
+			return p, fmt.Errorf("page_size must be between 1 and %d", maxPageSize)
 		}
-		dr[key] = pair
+		p.PageSize = n
+	}
+	for _, col := range splitList(c.Query("sort")) {
+		desc := strings.HasPrefix(col, "-")
+		p.Sort = append(p.Sort, repo.SortField{Column: strings.TrimPrefix(col, "-"), Desc: desc})
 	}
+	p.Fields = splitList(c.Query("fields"))
+	p.Cursor, p.Keyset = c.GetQuery("cursor")
+	return p, nil
+}
 
-	return q, nr, dr, nil
+// splitList 拆分逗号分隔的参数，忽略空项
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// respondList 返回一页数据、本页行数与分页信息；指定了 fields 时每行只输出 id 与这些字段
+func respondList(c *gin.Context, list interface{}, page *models.Pagination, fields []string) {
+	count := reflect.ValueOf(list).Len()
+	if len(fields) == 0 {
+		c.JSON(http.StatusOK, gin.H{"data": list, "count": count, "pagination": page})
+		return
+	}
+	raw, err := json.Marshal(list)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	var rows []map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &rows); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	keep := map[string]bool{"id": true}
+	for _, f := range fields {
+		keep[f] = true
+	}
+	for _, row := range rows {
+		for key := range row {
+			if !keep[key] {
+				delete(row, key)
+			}
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{"data": rows, "count": count, "pagination": page})
 }
 
 // parseDateRange 简单解析 start_date 和 end_date（格式：YYYY-MM-DD），失败返回零值
@@ -193,12 +373,17 @@ func (h *ERPHandler) CreateUser(c *gin.Context) {
 }
 
 func (h *ERPHandler) GetAllUsers(c *gin.Context) {
-	list, err := h.userService.GetAll()
+	p, err := parseListParams(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid list parameters: " + err.Error()})
+		return
+	}
+	list, page, err := h.userService.List(p)
+	if err != nil {
+		respondServiceError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"data": list, "count": len(list)})
+	respondList(c, list, page, p.Fields)
 }
 
 func (h *ERPHandler) UpdateUser(c *gin.Context) {
@@ -213,15 +398,31 @@ func (h *ERPHandler) UpdateUser(c *gin.Context) {
 		return
 	}
 	m.ID = uint(id)
-	if err := h.userService.Update(&m); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.userService.WithContext(c.Request.Context()).Update(&m); err != nil {
+		respondServiceError(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"data": m})
 }
 
+// FilterUsers 按用户名、类型、状态、最近登录时间及档案字段（如 employee.department、donor.email）查找账号
 func (h *ERPHandler) FilterUsers(c *gin.Context) {
-	c.JSON(http.StatusNotImplemented, gin.H{"error": "Filter not implemented for user table"})
+	filter, err := parseFilterParams(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid filter parameters: " + err.Error()})
+		return
+	}
+	p, err := parseListParams(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid list parameters: " + err.Error()})
+		return
+	}
+	list, page, err := h.userService.Filter(filter, p)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+	respondList(c, list, page, p.Fields)
 }
 
 func (h *ERPHandler) DeleteUser(c *gin.Context) {
@@ -230,8 +431,12 @@ func (h *ERPHandler) DeleteUser(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
 		return
 	}
-	if err := h.userService.Delete(uint(id)); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	ctx, ok := deleteContext(c)
+	if !ok {
+		return
+	}
+	if err := h.userService.WithContext(ctx).Delete(uint(id)); err != nil {
+		respondServiceError(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"message": "deleted"})
@@ -249,7 +454,7 @@ func (h *ERPHandler) CreateProject(c *gin.Context) {
 	if m.ProjectID == "" {
 		m.ProjectID = generateID("PRO")
 	}
-	if err := h.projectService.Create(&m); err != nil {
+	if err := h.projectService.WithContext(c.Request.Context()).Create(&m); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -257,12 +462,17 @@ func (h *ERPHandler) CreateProject(c *gin.Context) {
 }
 
 func (h *ERPHandler) GetAllProjects(c *gin.Context) {
-	list, err := h.projectService.GetAll()
+	p, err := parseListParams(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid list parameters: " + err.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"data": list, "count": len(list)})
+	list, page, err := h.projectService.List(p)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+	respondList(c, list, page, p.Fields)
 }
 
 func (h *ERPHandler) UpdateProject(c *gin.Context) {
@@ -277,7 +487,7 @@ func (h *ERPHandler) UpdateProject(c *gin.Context) {
 		return
 	}
 	m.ID = uint(id)
-	if err := h.projectService.Update(&m); err != nil {
+	if err := h.projectService.WithContext(c.Request.Context()).Update(&m); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -285,17 +495,22 @@ func (h *ERPHandler) UpdateProject(c *gin.Context) {
 }
 
 func (h *ERPHandler) FilterProjects(c *gin.Context) {
-	query, numberRange, dateRange, err := parseFilterParams(c)
+	filter, err := parseFilterParams(c)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid filter parameters: " + err.Error()})
 		return
 	}
-	list, err := h.projectService.Filter(query, numberRange, dateRange)
+	p, err := parseListParams(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid list parameters: " + err.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"data": list, "count": len(list)})
+	list, page, err := h.projectService.Filter(filter, p)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+	respondList(c, list, page, p.Fields)
 }
 
 func (h *ERPHandler) DeleteProject(c *gin.Context) {
@@ -304,8 +519,12 @@ func (h *ERPHandler) DeleteProject(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
 		return
 	}
-	if err := h.projectService.Delete(uint(id)); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	ctx, ok := deleteContext(c)
+	if !ok {
+		return
+	}
+	if err := h.projectService.WithContext(ctx).Delete(uint(id)); err != nil {
+		respondServiceError(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"message": "deleted"})
@@ -321,20 +540,25 @@ func (h *ERPHandler) CreateDonor(c *gin.Context) {
 	if m.DonorID == "" {
 		m.DonorID = generateID("DNR")
 	}
-	if err := h.donorService.Create(&m); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.donorService.WithContext(c.Request.Context()).Create(&m); err != nil {
+		respondServiceError(c, err)
 		return
 	}
 	c.JSON(http.StatusCreated, gin.H{"data": m})
 }
 
 func (h *ERPHandler) GetAllDonors(c *gin.Context) {
-	list, err := h.donorService.GetAll()
+	p, err := parseListParams(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid list parameters: " + err.Error()})
+		return
+	}
+	list, page, err := h.donorService.List(p)
+	if err != nil {
+		respondServiceError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"data": list, "count": len(list)})
+	respondList(c, list, page, p.Fields)
 }
 
 func (h *ERPHandler) UpdateDonor(c *gin.Context) {
@@ -349,25 +573,30 @@ func (h *ERPHandler) UpdateDonor(c *gin.Context) {
 		return
 	}
 	m.ID = uint(id)
-	if err := h.donorService.Update(&m); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.donorService.WithContext(c.Request.Context()).Update(&m); err != nil {
+		respondServiceError(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"data": m})
 }
 
 func (h *ERPHandler) FilterDonors(c *gin.Context) {
-	query, numberRange, dateRange, err := parseFilterParams(c)
+	filter, err := parseFilterParams(c)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid filter parameters: " + err.Error()})
 		return
 	}
-	list, err := h.donorService.Filter(query, numberRange, dateRange)
+	p, err := parseListParams(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid list parameters: " + err.Error()})
+		return
+	}
+	list, page, err := h.donorService.Filter(filter, p)
+	if err != nil {
+		respondServiceError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"data": list, "count": len(list)})
+	respondList(c, list, page, p.Fields)
 }
 
 func (h *ERPHandler) DeleteDonor(c *gin.Context) {
@@ -376,8 +605,12 @@ func (h *ERPHandler) DeleteDonor(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
 		return
 	}
-	if err := h.donorService.Delete(uint(id)); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	ctx, ok := deleteContext(c)
+	if !ok {
+		return
+	}
+	if err := h.donorService.WithContext(ctx).Delete(uint(id)); err != nil {
+		respondServiceError(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"message": "deleted"})
@@ -393,20 +626,25 @@ func (h *ERPHandler) CreateDonation(c *gin.Context) {
 	if m.DonationID == "" {
 		m.DonationID = generateID("DON")
 	}
-	if err := h.donationService.Create(&m); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.donationService.Scoped(projectScope(c)).WithContext(c.Request.Context()).Create(&m); err != nil {
+		respondServiceError(c, err)
 		return
 	}
 	c.JSON(http.StatusCreated, gin.H{"data": m})
 }
 
 func (h *ERPHandler) GetAllDonations(c *gin.Context) {
-	list, err := h.donationService.GetAll()
+	p, err := parseListParams(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid list parameters: " + err.Error()})
+		return
+	}
+	list, page, err := h.donationService.Scoped(projectScope(c)).List(p)
+	if err != nil {
+		respondServiceError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"data": list, "count": len(list)})
+	respondList(c, list, page, p.Fields)
 }
 
 func (h *ERPHandler) UpdateDonation(c *gin.Context) {
@@ -421,25 +659,30 @@ func (h *ERPHandler) UpdateDonation(c *gin.Context) {
 		return
 	}
 	m.ID = uint(id)
-	if err := h.donationService.Update(&m); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.donationService.Scoped(projectScope(c)).WithContext(c.Request.Context()).Update(&m); err != nil {
+		respondServiceError(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"data": m})
 }
 
 func (h *ERPHandler) FilterDonations(c *gin.Context) {
-	query, numberRange, dateRange, err := parseFilterParams(c)
+	filter, err := parseFilterParams(c)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid filter parameters: " + err.Error()})
 		return
 	}
-	list, err := h.donationService.Filter(query, numberRange, dateRange)
+	p, err := parseListParams(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid list parameters: " + err.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"data": list, "count": len(list)})
+	list, page, err := h.donationService.Scoped(projectScope(c)).Filter(filter, p)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+	respondList(c, list, page, p.Fields)
 }
 
 func (h *ERPHandler) DeleteDonation(c *gin.Context) {
@@ -448,8 +691,12 @@ func (h *ERPHandler) DeleteDonation(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
 		return
 	}
-	if err := h.donationService.Delete(uint(id)); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	ctx, ok := deleteContext(c)
+	if !ok {
+		return
+	}
+	if err := h.donationService.Scoped(projectScope(c)).WithContext(ctx).Delete(uint(id)); err != nil {
+		respondServiceError(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"message": "deleted"})
@@ -465,7 +712,7 @@ func (h *ERPHandler) CreateVolunteer(c *gin.Context) {
 	if m.VolunteerID == "" {
 		m.VolunteerID = generateID("VOL")
 	}
-	if err := h.volunteerService.Create(&m); err != nil {
+	if err := h.volunteerService.WithContext(c.Request.Context()).Create(&m); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -473,12 +720,17 @@ func (h *ERPHandler) CreateVolunteer(c *gin.Context) {
 }
 
 func (h *ERPHandler) GetAllVolunteers(c *gin.Context) {
-	list, err := h.volunteerService.GetAll()
+	p, err := parseListParams(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid list parameters: " + err.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"data": list, "count": len(list)})
+	list, page, err := h.volunteerService.List(p)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+	respondList(c, list, page, p.Fields)
 }
 
 func (h *ERPHandler) UpdateVolunteer(c *gin.Context) {
@@ -493,7 +745,7 @@ func (h *ERPHandler) UpdateVolunteer(c *gin.Context) {
 		return
 	}
 	m.ID = uint(id)
-	if err := h.volunteerService.Update(&m); err != nil {
+	if err := h.volunteerService.WithContext(c.Request.Context()).Update(&m); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -501,17 +753,22 @@ func (h *ERPHandler) UpdateVolunteer(c *gin.Context) {
 }
 
 func (h *ERPHandler) FilterVolunteers(c *gin.Context) {
-	query, numberRange, dateRange, err := parseFilterParams(c)
+	filter, err := parseFilterParams(c)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid filter parameters: " + err.Error()})
 		return
 	}
-	list, err := h.volunteerService.Filter(query, numberRange, dateRange)
+	p, err := parseListParams(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid list parameters: " + err.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"data": list, "count": len(list)})
+	list, page, err := h.volunteerService.Filter(filter, p)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+	respondList(c, list, page, p.Fields)
 }
 
 func (h *ERPHandler) DeleteVolunteer(c *gin.Context) {
@@ -520,8 +777,12 @@ func (h *ERPHandler) DeleteVolunteer(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
 		return
 	}
-	if err := h.volunteerService.Delete(uint(id)); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	ctx, ok := deleteContext(c)
+	if !ok {
+		return
+	}
+	if err := h.volunteerService.WithContext(ctx).Delete(uint(id)); err != nil {
+		respondServiceError(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"message": "deleted"})
@@ -537,7 +798,7 @@ func (h *ERPHandler) CreateEmployee(c *gin.Context) {
 	if m.EmployeeID == "" {
 		m.EmployeeID = generateID("EMP")
 	}
-	if err := h.employeeService.Create(&m); err != nil {
+	if err := h.employeeService.WithContext(c.Request.Context()).Create(&m); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -545,12 +806,17 @@ func (h *ERPHandler) CreateEmployee(c *gin.Context) {
 }
 
 func (h *ERPHandler) GetAllEmployees(c *gin.Context) {
-	list, err := h.employeeService.GetAll()
+	p, err := parseListParams(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid list parameters: " + err.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"data": list, "count": len(list)})
+	list, page, err := h.employeeService.List(p)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+	respondList(c, list, page, p.Fields)
 }
 
 func (h *ERPHandler) UpdateEmployee(c *gin.Context) {
@@ -565,7 +831,7 @@ func (h *ERPHandler) UpdateEmployee(c *gin.Context) {
 		return
 	}
 	m.ID = uint(id)
-	if err := h.employeeService.Update(&m); err != nil {
+	if err := h.employeeService.WithContext(c.Request.Context()).Update(&m); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -573,17 +839,22 @@ func (h *ERPHandler) UpdateEmployee(c *gin.Context) {
 }
 
 func (h *ERPHandler) FilterEmployees(c *gin.Context) {
-	query, numberRange, dateRange, err := parseFilterParams(c)
+	filter, err := parseFilterParams(c)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid filter parameters: " + err.Error()})
 		return
 	}
-	list, err := h.employeeService.Filter(query, numberRange, dateRange)
+	p, err := parseListParams(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid list parameters: " + err.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"data": list, "count": len(list)})
+	list, page, err := h.employeeService.Filter(filter, p)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+	respondList(c, list, page, p.Fields)
 }
 
 func (h *ERPHandler) DeleteEmployee(c *gin.Context) {
@@ -592,8 +863,12 @@ func (h *ERPHandler) DeleteEmployee(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
 		return
 	}
-	if err := h.employeeService.Delete(uint(id)); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	ctx, ok := deleteContext(c)
+	if !ok {
+		return
+	}
+	if err := h.employeeService.WithContext(ctx).Delete(uint(id)); err != nil {
+		respondServiceError(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"message": "deleted"})
@@ -609,7 +884,7 @@ func (h *ERPHandler) CreateLocation(c *gin.Context) {
 	if m.LocationID == "" {
 		m.LocationID = generateID("LOC")
 	}
-	if err := h.locationService.Create(&m); err != nil {
+	if err := h.locationService.WithContext(c.Request.Context()).Create(&m); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -617,12 +892,17 @@ func (h *ERPHandler) CreateLocation(c *gin.Context) {
 }
 
 func (h *ERPHandler) GetAllLocations(c *gin.Context) {
-	list, err := h.locationService.GetAll()
+	p, err := parseListParams(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid list parameters: " + err.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"data": list, "count": len(list)})
+	list, page, err := h.locationService.List(p)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+	respondList(c, list, page, p.Fields)
 }
 
 func (h *ERPHandler) UpdateLocation(c *gin.Context) {
@@ -637,7 +917,7 @@ func (h *ERPHandler) UpdateLocation(c *gin.Context) {
 		return
 	}
 	m.ID = uint(id)
-	if err := h.locationService.Update(&m); err != nil {
+	if err := h.locationService.WithContext(c.Request.Context()).Update(&m); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -645,17 +925,22 @@ func (h *ERPHandler) UpdateLocation(c *gin.Context) {
 }
 
 func (h *ERPHandler) FilterLocations(c *gin.Context) {
-	query, numberRange, dateRange, err := parseFilterParams(c)
+	filter, err := parseFilterParams(c)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid filter parameters: " + err.Error()})
 		return
 	}
-	list, err := h.locationService.Filter(query, numberRange, dateRange)
+	p, err := parseListParams(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid list parameters: " + err.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"data": list, "count": len(list)})
+	list, page, err := h.locationService.Filter(filter, p)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+	respondList(c, list, page, p.Fields)
 }
 
 func (h *ERPHandler) DeleteLocation(c *gin.Context) {
@@ -664,8 +949,12 @@ func (h *ERPHandler) DeleteLocation(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
 		return
 	}
-	if err := h.locationService.Delete(uint(id)); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	ctx, ok := deleteContext(c)
+	if !ok {
+		return
+	}
+	if err := h.locationService.WithContext(ctx).Delete(uint(id)); err != nil {
+		respondServiceError(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"message": "deleted"})
@@ -681,20 +970,25 @@ func (h *ERPHandler) CreateFund(c *gin.Context) {
 	if m.FundID == "" {
 		m.FundID = generateID("FND")
 	}
-	if err := h.fundService.Create(&m); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.fundService.WithContext(c.Request.Context()).Create(&m); err != nil {
+		respondServiceError(c, err)
 		return
 	}
 	c.JSON(http.StatusCreated, gin.H{"data": m})
 }
 
 func (h *ERPHandler) GetAllFunds(c *gin.Context) {
-	list, err := h.fundService.GetAll()
+	p, err := parseListParams(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid list parameters: " + err.Error()})
+		return
+	}
+	list, page, err := h.fundService.List(p)
+	if err != nil {
+		respondServiceError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"data": list, "count": len(list)})
+	respondList(c, list, page, p.Fields)
 }
 
 func (h *ERPHandler) UpdateFund(c *gin.Context) {
@@ -709,25 +1003,30 @@ func (h *ERPHandler) UpdateFund(c *gin.Context) {
 		return
 	}
 	m.ID = uint(id)
-	if err := h.fundService.Update(&m); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.fundService.WithContext(c.Request.Context()).Update(&m); err != nil {
+		respondServiceError(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"data": m})
 }
 
 func (h *ERPHandler) FilterFunds(c *gin.Context) {
-	query, numberRange, dateRange, err := parseFilterParams(c)
+	filter, err := parseFilterParams(c)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid filter parameters: " + err.Error()})
 		return
 	}
-	list, err := h.fundService.Filter(query, numberRange, dateRange)
+	p, err := parseListParams(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid list parameters: " + err.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"data": list, "count": len(list)})
+	list, page, err := h.fundService.Filter(filter, p)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+	respondList(c, list, page, p.Fields)
 }
 
 func (h *ERPHandler) DeleteFund(c *gin.Context) {
@@ -736,8 +1035,12 @@ func (h *ERPHandler) DeleteFund(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
 		return
 	}
-	if err := h.fundService.Delete(uint(id)); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	ctx, ok := deleteContext(c)
+	if !ok {
+		return
+	}
+	if err := h.fundService.WithContext(ctx).Delete(uint(id)); err != nil {
+		respondServiceError(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"message": "deleted"})
@@ -753,20 +1056,36 @@ func (h *ERPHandler) CreateExpense(c *gin.Context) {
 	if m.ExpenseID == "" {
 		m.ExpenseID = generateID("EXP")
 	}
-	if err := h.expenseService.Create(&m); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	// 填报人记为当前员工，只有持有 expenses:file-on-behalf 的角色可以指定其他员工；登记人总是当前用户
+	if m.EmployeeID == nil || !middleware.HasPermission(c, "expenses", models.ActionFileOnBehalf) {
+		m.EmployeeID = nil
+		if empID := c.GetUint("role_id"); empID != 0 {
+			m.EmployeeID = &empID
+		}
+	}
+	m.CreatedBy = nil
+	if userID := c.GetUint("user_id"); userID != 0 {
+		m.CreatedBy = &userID
+	}
+	if err := h.expenseService.Scoped(projectScope(c)).WithContext(c.Request.Context()).Create(&m); err != nil {
+		respondServiceError(c, err)
 		return
 	}
 	c.JSON(http.StatusCreated, gin.H{"data": m})
 }
 
 func (h *ERPHandler) GetAllExpenses(c *gin.Context) {
-	list, err := h.expenseService.GetAll()
+	p, err := parseListParams(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid list parameters: " + err.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"data": list, "count": len(list)})
+	list, page, err := h.expenseService.Scoped(projectScope(c)).List(p)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+	respondList(c, list, page, p.Fields)
 }
 
 func (h *ERPHandler) UpdateExpense(c *gin.Context) {
@@ -781,25 +1100,30 @@ func (h *ERPHandler) UpdateExpense(c *gin.Context) {
 		return
 	}
 	m.ID = uint(id)
-	if err := h.expenseService.Update(&m); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.expenseService.Scoped(projectScope(c)).WithContext(c.Request.Context()).Update(&m); err != nil {
+		respondServiceError(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"data": m})
 }
 
 func (h *ERPHandler) FilterExpenses(c *gin.Context) {
-	query, numberRange, dateRange, err := parseFilterParams(c)
+	filter, err := parseFilterParams(c)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid filter parameters: " + err.Error()})
 		return
 	}
-	list, err := h.expenseService.Filter(query, numberRange, dateRange)
+	p, err := parseListParams(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid list parameters: " + err.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"data": list, "count": len(list)})
+	list, page, err := h.expenseService.Scoped(projectScope(c)).Filter(filter, p)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+	respondList(c, list, page, p.Fields)
 }
 
 func (h *ERPHandler) DeleteExpense(c *gin.Context) {
@@ -808,8 +1132,12 @@ func (h *ERPHandler) DeleteExpense(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
 		return
 	}
-	if err := h.expenseService.Delete(uint(id)); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	ctx, ok := deleteContext(c)
+	if !ok {
+		return
+	}
+	if err := h.expenseService.Scoped(projectScope(c)).WithContext(ctx).Delete(uint(id)); err != nil {
+		respondServiceError(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"message": "deleted"})
@@ -825,7 +1153,7 @@ func (h *ERPHandler) CreateTransaction(c *gin.Context) {
 	if m.TransactionID == "" {
 		m.TransactionID = generateID("TRX")
 	}
-	if err := h.transactionService.Create(&m); err != nil {
+	if err := h.transactionService.WithContext(c.Request.Context()).Create(&m); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -833,12 +1161,17 @@ func (h *ERPHandler) CreateTransaction(c *gin.Context) {
 }
 
 func (h *ERPHandler) GetAllTransactions(c *gin.Context) {
-	list, err := h.transactionService.GetAll()
+	p, err := parseListParams(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid list parameters: " + err.Error()})
+		return
+	}
+	list, page, err := h.transactionService.List(p)
+	if err != nil {
+		respondServiceError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"data": list, "count": len(list)})
+	respondList(c, list, page, p.Fields)
 }
 
 func (h *ERPHandler) UpdateTransaction(c *gin.Context) {
@@ -853,7 +1186,7 @@ func (h *ERPHandler) UpdateTransaction(c *gin.Context) {
 		return
 	}
 	m.ID = uint(id)
-	if err := h.transactionService.Update(&m); err != nil {
+	if err := h.transactionService.WithContext(c.Request.Context()).Update(&m); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -861,17 +1194,22 @@ func (h *ERPHandler) UpdateTransaction(c *gin.Context) {
 }
 
 func (h *ERPHandler) FilterTransactions(c *gin.Context) {
-	query, numberRange, dateRange, err := parseFilterParams(c)
+	filter, err := parseFilterParams(c)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid filter parameters: " + err.Error()})
 		return
 	}
-	list, err := h.transactionService.Filter(query, numberRange, dateRange)
+	p, err := parseListParams(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid list parameters: " + err.Error()})
+		return
+	}
+	list, page, err := h.transactionService.Filter(filter, p)
+	if err != nil {
+		respondServiceError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"data": list, "count": len(list)})
+	respondList(c, list, page, p.Fields)
 }
 
 func (h *ERPHandler) DeleteTransaction(c *gin.Context) {
@@ -880,8 +1218,12 @@ func (h *ERPHandler) DeleteTransaction(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
 		return
 	}
-	if err := h.transactionService.Delete(uint(id)); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	ctx, ok := deleteContext(c)
+	if !ok {
+		return
+	}
+	if err := h.transactionService.WithContext(ctx).Delete(uint(id)); err != nil {
+		respondServiceError(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"message": "deleted"})
@@ -897,20 +1239,25 @@ func (h *ERPHandler) CreatePurchase(c *gin.Context) {
 	if m.PurchaseID == "" {
 		m.PurchaseID = generateID("PUR")
 	}
-	if err := h.purchaseService.Create(&m); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.purchaseService.WithContext(c.Request.Context()).Create(&m); err != nil {
+		respondServiceError(c, err)
 		return
 	}
 	c.JSON(http.StatusCreated, gin.H{"data": m})
 }
 
 func (h *ERPHandler) GetAllPurchases(c *gin.Context) {
-	list, err := h.purchaseService.GetAll()
+	p, err := parseListParams(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid list parameters: " + err.Error()})
+		return
+	}
+	list, page, err := h.purchaseService.List(p)
+	if err != nil {
+		respondServiceError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"data": list, "count": len(list)})
+	respondList(c, list, page, p.Fields)
 }
 
 func (h *ERPHandler) UpdatePurchase(c *gin.Context) {
@@ -925,25 +1272,30 @@ func (h *ERPHandler) UpdatePurchase(c *gin.Context) {
 		return
 	}
 	m.ID = uint(id)
-	if err := h.purchaseService.Update(&m); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.purchaseService.WithContext(c.Request.Context()).Update(&m); err != nil {
+		respondServiceError(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"data": m})
 }
 
 func (h *ERPHandler) FilterPurchases(c *gin.Context) {
-	query, numberRange, dateRange, err := parseFilterParams(c)
+	filter, err := parseFilterParams(c)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid filter parameters: " + err.Error()})
 		return
 	}
-	list, err := h.purchaseService.Filter(query, numberRange, dateRange)
+	p, err := parseListParams(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid list parameters: " + err.Error()})
+		return
+	}
+	list, page, err := h.purchaseService.Filter(filter, p)
+	if err != nil {
+		respondServiceError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"data": list, "count": len(list)})
+	respondList(c, list, page, p.Fields)
 }
 
 func (h *ERPHandler) DeletePurchase(c *gin.Context) {
@@ -952,8 +1304,12 @@ func (h *ERPHandler) DeletePurchase(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
 		return
 	}
-	if err := h.purchaseService.Delete(uint(id)); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	ctx, ok := deleteContext(c)
+	if !ok {
+		return
+	}
+	if err := h.purchaseService.WithContext(ctx).Delete(uint(id)); err != nil {
+		respondServiceError(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"message": "deleted"})
@@ -966,20 +1322,25 @@ func (h *ERPHandler) CreatePayroll(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	if err := h.payrollService.Create(&m); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.payrollService.WithContext(c.Request.Context()).Create(&m); err != nil {
+		respondServiceError(c, err)
 		return
 	}
 	c.JSON(http.StatusCreated, gin.H{"data": m})
 }
 
 func (h *ERPHandler) GetAllPayrolls(c *gin.Context) {
-	list, err := h.payrollService.GetAll()
+	p, err := parseListParams(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid list parameters: " + err.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"data": list, "count": len(list)})
+	list, page, err := h.payrollService.List(p)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+	respondList(c, list, page, p.Fields)
 }
 
 func (h *ERPHandler) UpdatePayroll(c *gin.Context) {
@@ -994,25 +1355,30 @@ func (h *ERPHandler) UpdatePayroll(c *gin.Context) {
 		return
 	}
 	m.ID = uint(id)
-	if err := h.payrollService.Update(&m); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.payrollService.WithContext(c.Request.Context()).Update(&m); err != nil {
+		respondServiceError(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"data": m})
 }
 
 func (h *ERPHandler) FilterPayrolls(c *gin.Context) {
-	query, numberRange, dateRange, err := parseFilterParams(c)
+	filter, err := parseFilterParams(c)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid filter parameters: " + err.Error()})
 		return
 	}
-	list, err := h.payrollService.Filter(query, numberRange, dateRange)
+	p, err := parseListParams(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid list parameters: " + err.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"data": list, "count": len(list)})
+	list, page, err := h.payrollService.Filter(filter, p)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+	respondList(c, list, page, p.Fields)
 }
 
 func (h *ERPHandler) DeletePayroll(c *gin.Context) {
@@ -1021,8 +1387,12 @@ func (h *ERPHandler) DeletePayroll(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
 		return
 	}
-	if err := h.payrollService.Delete(uint(id)); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	ctx, ok := deleteContext(c)
+	if !ok {
+		return
+	}
+	if err := h.payrollService.WithContext(ctx).Delete(uint(id)); err != nil {
+		respondServiceError(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"message": "deleted"})
@@ -1038,20 +1408,25 @@ func (h *ERPHandler) CreateInventory(c *gin.Context) {
 	if m.InventoryID == "" {
 		m.InventoryID = generateID("INV")
 	}
-	if err := h.inventoryService.Create(&m); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.inventoryService.WithContext(c.Request.Context()).Create(&m); err != nil {
+		respondServiceError(c, err)
 		return
 	}
 	c.JSON(http.StatusCreated, gin.H{"data": m})
 }
 
 func (h *ERPHandler) GetAllInventories(c *gin.Context) {
-	list, err := h.inventoryService.GetAll()
+	p, err := parseListParams(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid list parameters: " + err.Error()})
+		return
+	}
+	list, page, err := h.inventoryService.List(p)
+	if err != nil {
+		respondServiceError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"data": list, "count": len(list)})
+	respondList(c, list, page, p.Fields)
 }
 
 func (h *ERPHandler) UpdateInventory(c *gin.Context) {
@@ -1066,25 +1441,40 @@ func (h *ERPHandler) UpdateInventory(c *gin.Context) {
 		return
 	}
 	m.ID = uint(id)
-	if err := h.inventoryService.Update(&m); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.inventoryService.WithContext(c.Request.Context()).Update(&m); err != nil {
+		respondServiceError(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"data": m})
 }
 
 func (h *ERPHandler) FilterInventories(c *gin.Context) {
-	query, numberRange, dateRange, err := parseFilterParams(c)
+	filter, err := parseFilterParams(c)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid filter parameters: " + err.Error()})
 		return
 	}
-	list, err := h.inventoryService.Filter(query, numberRange, dateRange)
+	p, err := parseListParams(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid list parameters: " + err.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"data": list, "count": len(list)})
+	list, page, err := h.inventoryService.Filter(filter, p)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+	respondList(c, list, page, p.Fields)
+}
+
+// GET /api/v1/dbms/inventory/reconcile?all=true  比较账面库存与库存变动累计数量，默认只列出不一致的库存
+func (h *ERPHandler) ReconcileInventory(c *gin.Context) {
+	list, checked, err := h.inventoryService.Reconcile(c.Query("all") == "true")
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": list, "count": len(list), "checked": checked})
 }
 
 func (h *ERPHandler) DeleteInventory(c *gin.Context) {
@@ -1093,8 +1483,12 @@ func (h *ERPHandler) DeleteInventory(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
 		return
 	}
-	if err := h.inventoryService.Delete(uint(id)); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	ctx, ok := deleteContext(c)
+	if !ok {
+		return
+	}
+	if err := h.inventoryService.WithContext(ctx).Delete(uint(id)); err != nil {
+		respondServiceError(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"message": "deleted"})
@@ -1107,7 +1501,7 @@ func (h *ERPHandler) CreateGiftType(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	if err := h.giftTypeService.Create(&m); err != nil {
+	if err := h.giftTypeService.WithContext(c.Request.Context()).Create(&m); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -1115,12 +1509,17 @@ func (h *ERPHandler) CreateGiftType(c *gin.Context) {
 }
 
 func (h *ERPHandler) GetAllGiftTypes(c *gin.Context) {
-	list, err := h.giftTypeService.GetAll()
+	p, err := parseListParams(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid list parameters: " + err.Error()})
+		return
+	}
+	list, page, err := h.giftTypeService.List(p)
+	if err != nil {
+		respondServiceError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"data": list, "count": len(list)})
+	respondList(c, list, page, p.Fields)
 }
 
 func (h *ERPHandler) UpdateGiftType(c *gin.Context) {
@@ -1135,7 +1534,7 @@ func (h *ERPHandler) UpdateGiftType(c *gin.Context) {
 		return
 	}
 	m.ID = uint(id)
-	if err := h.giftTypeService.Update(&m); err != nil {
+	if err := h.giftTypeService.WithContext(c.Request.Context()).Update(&m); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -1143,17 +1542,22 @@ func (h *ERPHandler) UpdateGiftType(c *gin.Context) {
 }
 
 func (h *ERPHandler) FilterGiftTypes(c *gin.Context) {
-	query, numberRange, dateRange, err := parseFilterParams(c)
+	filter, err := parseFilterParams(c)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid filter parameters: " + err.Error()})
 		return
 	}
-	list, err := h.giftTypeService.Filter(query, numberRange, dateRange)
+	p, err := parseListParams(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid list parameters: " + err.Error()})
+		return
+	}
+	list, page, err := h.giftTypeService.Filter(filter, p)
+	if err != nil {
+		respondServiceError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"data": list, "count": len(list)})
+	respondList(c, list, page, p.Fields)
 }
 
 func (h *ERPHandler) DeleteGiftType(c *gin.Context) {
@@ -1162,8 +1566,12 @@ func (h *ERPHandler) DeleteGiftType(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
 		return
 	}
-	if err := h.giftTypeService.Delete(uint(id)); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	ctx, ok := deleteContext(c)
+	if !ok {
+		return
+	}
+	if err := h.giftTypeService.WithContext(ctx).Delete(uint(id)); err != nil {
+		respondServiceError(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"message": "deleted"})
@@ -1179,7 +1587,7 @@ func (h *ERPHandler) CreateGift(c *gin.Context) {
 	if m.GiftID == "" {
 		m.GiftID = generateID("GFT")
 	}
-	if err := h.giftService.Create(&m); err != nil {
+	if err := h.giftService.WithContext(c.Request.Context()).Create(&m); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -1187,12 +1595,17 @@ func (h *ERPHandler) CreateGift(c *gin.Context) {
 }
 
 func (h *ERPHandler) GetAllGifts(c *gin.Context) {
-	list, err := h.giftService.GetAll()
+	p, err := parseListParams(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid list parameters: " + err.Error()})
+		return
+	}
+	list, page, err := h.giftService.List(p)
+	if err != nil {
+		respondServiceError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"data": list, "count": len(list)})
+	respondList(c, list, page, p.Fields)
 }
 
 func (h *ERPHandler) UpdateGift(c *gin.Context) {
@@ -1207,7 +1620,7 @@ func (h *ERPHandler) UpdateGift(c *gin.Context) {
 		return
 	}
 	m.ID = uint(id)
-	if err := h.giftService.Update(&m); err != nil {
+	if err := h.giftService.WithContext(c.Request.Context()).Update(&m); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -1215,17 +1628,22 @@ func (h *ERPHandler) UpdateGift(c *gin.Context) {
 }
 
 func (h *ERPHandler) FilterGifts(c *gin.Context) {
-	query, numberRange, dateRange, err := parseFilterParams(c)
+	filter, err := parseFilterParams(c)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid filter parameters: " + err.Error()})
 		return
 	}
-	list, err := h.giftService.Filter(query, numberRange, dateRange)
+	p, err := parseListParams(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid list parameters: " + err.Error()})
+		return
+	}
+	list, page, err := h.giftService.Filter(filter, p)
+	if err != nil {
+		respondServiceError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"data": list, "count": len(list)})
+	respondList(c, list, page, p.Fields)
 }
 
 func (h *ERPHandler) DeleteGift(c *gin.Context) {
@@ -1234,8 +1652,12 @@ func (h *ERPHandler) DeleteGift(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
 		return
 	}
-	if err := h.giftService.Delete(uint(id)); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	ctx, ok := deleteContext(c)
+	if !ok {
+		return
+	}
+	if err := h.giftService.WithContext(ctx).Delete(uint(id)); err != nil {
+		respondServiceError(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"message": "deleted"})
@@ -1248,20 +1670,25 @@ func (h *ERPHandler) CreateInventoryTransaction(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	if err := h.inventoryTransactionService.Create(&m); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.inventoryTransactionService.WithContext(c.Request.Context()).Create(&m); err != nil {
+		respondServiceError(c, err)
 		return
 	}
 	c.JSON(http.StatusCreated, gin.H{"data": m})
 }
 
 func (h *ERPHandler) GetAllInventoryTransactions(c *gin.Context) {
-	list, err := h.inventoryTransactionService.GetAll()
+	p, err := parseListParams(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid list parameters: " + err.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"data": list, "count": len(list)})
+	list, page, err := h.inventoryTransactionService.List(p)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+	respondList(c, list, page, p.Fields)
 }
 
 func (h *ERPHandler) UpdateInventoryTransaction(c *gin.Context) {
@@ -1276,25 +1703,30 @@ func (h *ERPHandler) UpdateInventoryTransaction(c *gin.Context) {
 		return
 	}
 	m.ID = uint(id)
-	if err := h.inventoryTransactionService.Update(&m); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.inventoryTransactionService.WithContext(c.Request.Context()).Update(&m); err != nil {
+		respondServiceError(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"data": m})
 }
 
 func (h *ERPHandler) FilterInventoryTransactions(c *gin.Context) {
-	query, numberRange, dateRange, err := parseFilterParams(c)
+	filter, err := parseFilterParams(c)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid filter parameters: " + err.Error()})
 		return
 	}
-	list, err := h.inventoryTransactionService.Filter(query, numberRange, dateRange)
+	p, err := parseListParams(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid list parameters: " + err.Error()})
+		return
+	}
+	list, page, err := h.inventoryTransactionService.Filter(filter, p)
+	if err != nil {
+		respondServiceError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"data": list, "count": len(list)})
+	respondList(c, list, page, p.Fields)
 }
 
 func (h *ERPHandler) DeleteInventoryTransaction(c *gin.Context) {
@@ -1303,11 +1735,15 @@ func (h *ERPHandler) DeleteInventoryTransaction(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
 		return
 	}
-	if err := h.inventoryTransactionService.Delete(uint(id)); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	ctx, ok := deleteContext(c)
+	if !ok {
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"message": "deleted"})
+	if err := h.inventoryTransactionService.WithContext(ctx).Delete(uint(id)); err != nil {
+		respondServiceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "reversed"})
 }
 
 // Delivery
@@ -1320,7 +1756,7 @@ func (h *ERPHandler) CreateDelivery(c *gin.Context) {
 	if m.DeliveryID == "" {
 		m.DeliveryID = generateID("DLY")
 	}
-	if err := h.deliveryService.Create(&m); err != nil {
+	if err := h.deliveryService.WithContext(c.Request.Context()).Create(&m); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -1328,12 +1764,17 @@ func (h *ERPHandler) CreateDelivery(c *gin.Context) {
 }
 
 func (h *ERPHandler) GetAllDeliveries(c *gin.Context) {
-	list, err := h.deliveryService.GetAll()
+	p, err := parseListParams(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid list parameters: " + err.Error()})
+		return
+	}
+	list, page, err := h.deliveryService.List(p)
+	if err != nil {
+		respondServiceError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"data": list, "count": len(list)})
+	respondList(c, list, page, p.Fields)
 }
 
 func (h *ERPHandler) UpdateDelivery(c *gin.Context) {
@@ -1348,7 +1789,7 @@ func (h *ERPHandler) UpdateDelivery(c *gin.Context) {
 		return
 	}
 	m.ID = uint(id)
-	if err := h.deliveryService.Update(&m); err != nil {
+	if err := h.deliveryService.WithContext(c.Request.Context()).Update(&m); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -1356,17 +1797,22 @@ func (h *ERPHandler) UpdateDelivery(c *gin.Context) {
 }
 
 func (h *ERPHandler) FilterDeliveries(c *gin.Context) {
-	query, numberRange, dateRange, err := parseFilterParams(c)
+	filter, err := parseFilterParams(c)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid filter parameters: " + err.Error()})
 		return
 	}
-	list, err := h.deliveryService.Filter(query, numberRange, dateRange)
+	p, err := parseListParams(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid list parameters: " + err.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"data": list, "count": len(list)})
+	list, page, err := h.deliveryService.Filter(filter, p)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+	respondList(c, list, page, p.Fields)
 }
 
 func (h *ERPHandler) DeleteDelivery(c *gin.Context) {
@@ -1375,8 +1821,12 @@ func (h *ERPHandler) DeleteDelivery(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
 		return
 	}
-	if err := h.deliveryService.Delete(uint(id)); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	ctx, ok := deleteContext(c)
+	if !ok {
+		return
+	}
+	if err := h.deliveryService.WithContext(ctx).Delete(uint(id)); err != nil {
+		respondServiceError(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"message": "deleted"})
@@ -1391,20 +1841,25 @@ func (h *ERPHandler) CreateVolunteerProject(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	if err := h.volunteerProjectService.Create(&m); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.volunteerProjectService.Scoped(projectScope(c)).WithContext(c.Request.Context()).Create(&m); err != nil {
+		respondServiceError(c, err)
 		return
 	}
 	c.JSON(http.StatusCreated, gin.H{"data": m})
 }
 
 func (h *ERPHandler) GetAllVolunteerProjects(c *gin.Context) {
-	list, err := h.volunteerProjectService.GetAll()
+	p, err := parseListParams(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid list parameters: " + err.Error()})
+		return
+	}
+	list, page, err := h.volunteerProjectService.Scoped(projectScope(c)).List(p)
+	if err != nil {
+		respondServiceError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"data": list, "count": len(list)})
+	respondList(c, list, page, p.Fields)
 }
 
 func (h *ERPHandler) UpdateVolunteerProject(c *gin.Context) {
@@ -1419,25 +1874,30 @@ func (h *ERPHandler) UpdateVolunteerProject(c *gin.Context) {
 		return
 	}
 	m.ID = uint(id)
-	if err := h.volunteerProjectService.Update(&m); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.volunteerProjectService.Scoped(projectScope(c)).WithContext(c.Request.Context()).Update(&m); err != nil {
+		respondServiceError(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"data": m})
 }
 
 func (h *ERPHandler) FilterVolunteerProjects(c *gin.Context) {
-	query, numberRange, dateRange, err := parseFilterParams(c)
+	filter, err := parseFilterParams(c)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid filter parameters: " + err.Error()})
 		return
 	}
-	list, err := h.volunteerProjectService.Filter(query, numberRange, dateRange)
+	p, err := parseListParams(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid list parameters: " + err.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"data": list, "count": len(list)})
+	list, page, err := h.volunteerProjectService.Scoped(projectScope(c)).Filter(filter, p)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+	respondList(c, list, page, p.Fields)
 }
 
 func (h *ERPHandler) DeleteVolunteerProject(c *gin.Context) {
@@ -1446,8 +1906,12 @@ func (h *ERPHandler) DeleteVolunteerProject(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
 		return
 	}
-	if err := h.volunteerProjectService.Delete(uint(id)); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	ctx, ok := deleteContext(c)
+	if !ok {
+		return
+	}
+	if err := h.volunteerProjectService.Scoped(projectScope(c)).WithContext(ctx).Delete(uint(id)); err != nil {
+		respondServiceError(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"message": "deleted"})
@@ -1459,7 +1923,7 @@ func (h *ERPHandler) CreateEmployeeProject(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	if err := h.employeeProjectService.Create(&m); err != nil {
+	if err := h.employeeProjectService.WithContext(c.Request.Context()).Create(&m); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -1467,12 +1931,17 @@ func (h *ERPHandler) CreateEmployeeProject(c *gin.Context) {
 }
 
 func (h *ERPHandler) GetAllEmployeeProjects(c *gin.Context) {
-	list, err := h.employeeProjectService.GetAll()
+	p, err := parseListParams(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid list parameters: " + err.Error()})
+		return
+	}
+	list, page, err := h.employeeProjectService.List(p)
+	if err != nil {
+		respondServiceError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"data": list, "count": len(list)})
+	respondList(c, list, page, p.Fields)
 }
 
 func (h *ERPHandler) UpdateEmployeeProject(c *gin.Context) {
@@ -1487,7 +1956,7 @@ func (h *ERPHandler) UpdateEmployeeProject(c *gin.Context) {
 		return
 	}
 	m.ID = uint(id)
-	if err := h.employeeProjectService.Update(&m); err != nil {
+	if err := h.employeeProjectService.WithContext(c.Request.Context()).Update(&m); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -1495,17 +1964,22 @@ func (h *ERPHandler) UpdateEmployeeProject(c *gin.Context) {
 }
 
 func (h *ERPHandler) FilterEmployeeProjects(c *gin.Context) {
-	query, numberRange, dateRange, err := parseFilterParams(c)
+	filter, err := parseFilterParams(c)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid filter parameters: " + err.Error()})
 		return
 	}
-	list, err := h.employeeProjectService.Filter(query, numberRange, dateRange)
+	p, err := parseListParams(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid list parameters: " + err.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"data": list, "count": len(list)})
+	list, page, err := h.employeeProjectService.Filter(filter, p)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+	respondList(c, list, page, p.Fields)
 }
 
 func (h *ERPHandler) DeleteEmployeeProject(c *gin.Context) {
@@ -1514,8 +1988,12 @@ func (h *ERPHandler) DeleteEmployeeProject(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
 		return
 	}
-	if err := h.employeeProjectService.Delete(uint(id)); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	ctx, ok := deleteContext(c)
+	if !ok {
+		return
+	}
+	if err := h.employeeProjectService.WithContext(ctx).Delete(uint(id)); err != nil {
+		respondServiceError(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"message": "deleted"})
@@ -1527,20 +2005,25 @@ func (h *ERPHandler) CreateFundProject(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	if err := h.fundProjectService.Create(&m); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.fundProjectService.Scoped(projectScope(c)).WithContext(c.Request.Context()).Create(&m); err != nil {
+		respondServiceError(c, err)
 		return
 	}
 	c.JSON(http.StatusCreated, gin.H{"data": m})
 }
 
 func (h *ERPHandler) GetAllFundProjects(c *gin.Context) {
-	list, err := h.fundProjectService.GetAll()
+	p, err := parseListParams(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid list parameters: " + err.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"data": list, "count": len(list)})
+	list, page, err := h.fundProjectService.Scoped(projectScope(c)).List(p)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+	respondList(c, list, page, p.Fields)
 }
 
 func (h *ERPHandler) UpdateFundProject(c *gin.Context) {
@@ -1555,25 +2038,30 @@ func (h *ERPHandler) UpdateFundProject(c *gin.Context) {
 		return
 	}
 	m.ID = uint(id)
-	if err := h.fundProjectService.Update(&m); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.fundProjectService.Scoped(projectScope(c)).WithContext(c.Request.Context()).Update(&m); err != nil {
+		respondServiceError(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"data": m})
 }
 
 func (h *ERPHandler) FilterFundProjects(c *gin.Context) {
-	query, numberRange, dateRange, err := parseFilterParams(c)
+	filter, err := parseFilterParams(c)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid filter parameters: " + err.Error()})
 		return
 	}
-	list, err := h.fundProjectService.Filter(query, numberRange, dateRange)
+	p, err := parseListParams(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid list parameters: " + err.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"data": list, "count": len(list)})
+	list, page, err := h.fundProjectService.Scoped(projectScope(c)).Filter(filter, p)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+	respondList(c, list, page, p.Fields)
 }
 
 func (h *ERPHandler) DeleteFundProject(c *gin.Context) {
@@ -1582,8 +2070,12 @@ func (h *ERPHandler) DeleteFundProject(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
 		return
 	}
-	if err := h.fundProjectService.Delete(uint(id)); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	ctx, ok := deleteContext(c)
+	if !ok {
+		return
+	}
+	if err := h.fundProjectService.Scoped(projectScope(c)).WithContext(ctx).Delete(uint(id)); err != nil {
+		respondServiceError(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"message": "deleted"})
@@ -1595,20 +2087,25 @@ func (h *ERPHandler) CreateDonationInventory(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	if err := h.donationInventoryService.Create(&m); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.donationInventoryService.WithContext(c.Request.Context()).Create(&m); err != nil {
+		respondServiceError(c, err)
 		return
 	}
 	c.JSON(http.StatusCreated, gin.H{"data": m})
 }
 
 func (h *ERPHandler) GetAllDonationInventories(c *gin.Context) {
-	list, err := h.donationInventoryService.GetAll()
+	p, err := parseListParams(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid list parameters: " + err.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"data": list, "count": len(list)})
+	list, page, err := h.donationInventoryService.List(p)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+	respondList(c, list, page, p.Fields)
 }
 
 func (h *ERPHandler) UpdateDonationInventory(c *gin.Context) {
@@ -1623,25 +2120,30 @@ func (h *ERPHandler) UpdateDonationInventory(c *gin.Context) {
 		return
 	}
 	m.ID = uint(id)
-	if err := h.donationInventoryService.Update(&m); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.donationInventoryService.WithContext(c.Request.Context()).Update(&m); err != nil {
+		respondServiceError(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"data": m})
 }
 
 func (h *ERPHandler) FilterDonationInventories(c *gin.Context) {
-	query, numberRange, dateRange, err := parseFilterParams(c)
+	filter, err := parseFilterParams(c)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid filter parameters: " + err.Error()})
 		return
 	}
-	list, err := h.donationInventoryService.Filter(query, numberRange, dateRange)
+	p, err := parseListParams(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid list parameters: " + err.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"data": list, "count": len(list)})
+	list, page, err := h.donationInventoryService.Filter(filter, p)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+	respondList(c, list, page, p.Fields)
 }
 
 func (h *ERPHandler) DeleteDonationInventory(c *gin.Context) {
@@ -1650,8 +2152,12 @@ func (h *ERPHandler) DeleteDonationInventory(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
 		return
 	}
-	if err := h.donationInventoryService.Delete(uint(id)); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	ctx, ok := deleteContext(c)
+	if !ok {
+		return
+	}
+	if err := h.donationInventoryService.WithContext(ctx).Delete(uint(id)); err != nil {
+		respondServiceError(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"message": "deleted"})
@@ -1663,20 +2169,25 @@ func (h *ERPHandler) CreateDeliveryInventory(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	if err := h.deliveryInventoryService.Create(&m); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.deliveryInventoryService.WithContext(c.Request.Context()).Create(&m); err != nil {
+		respondServiceError(c, err)
 		return
 	}
 	c.JSON(http.StatusCreated, gin.H{"data": m})
 }
 
 func (h *ERPHandler) GetAllDeliveryInventories(c *gin.Context) {
-	list, err := h.deliveryInventoryService.GetAll()
+	p, err := parseListParams(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid list parameters: " + err.Error()})
+		return
+	}
+	list, page, err := h.deliveryInventoryService.List(p)
+	if err != nil {
+		respondServiceError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"data": list, "count": len(list)})
+	respondList(c, list, page, p.Fields)
 }
 
 func (h *ERPHandler) UpdateDeliveryInventory(c *gin.Context) {
@@ -1691,25 +2202,30 @@ func (h *ERPHandler) UpdateDeliveryInventory(c *gin.Context) {
 		return
 	}
 	m.ID = uint(id)
-	if err := h.deliveryInventoryService.Update(&m); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.deliveryInventoryService.WithContext(c.Request.Context()).Update(&m); err != nil {
+		respondServiceError(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"data": m})
 }
 
 func (h *ERPHandler) FilterDeliveryInventories(c *gin.Context) {
-	query, numberRange, dateRange, err := parseFilterParams(c)
+	filter, err := parseFilterParams(c)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid filter parameters: " + err.Error()})
 		return
 	}
-	list, err := h.deliveryInventoryService.Filter(query, numberRange, dateRange)
+	p, err := parseListParams(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid list parameters: " + err.Error()})
+		return
+	}
+	list, page, err := h.deliveryInventoryService.Filter(filter, p)
+	if err != nil {
+		respondServiceError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"data": list, "count": len(list)})
+	respondList(c, list, page, p.Fields)
 }
 
 func (h *ERPHandler) DeleteDeliveryInventory(c *gin.Context) {
@@ -1718,8 +2234,12 @@ func (h *ERPHandler) DeleteDeliveryInventory(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
 		return
 	}
-	if err := h.deliveryInventoryService.Delete(uint(id)); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	ctx, ok := deleteContext(c)
+	if !ok {
+		return
+	}
+	if err := h.deliveryInventoryService.WithContext(ctx).Delete(uint(id)); err != nil {
+		respondServiceError(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"message": "deleted"})
@@ -1734,20 +2254,25 @@ func (h *ERPHandler) CreateSchedule(c *gin.Context) {
 	if m.ScheduleID == "" {
 		m.ScheduleID = generateID("SCH")
 	}
-	if err := h.scheduleService.Create(&m); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.scheduleService.Scoped(projectScope(c)).WithContext(c.Request.Context()).Create(&m); err != nil {
+		respondServiceError(c, err)
 		return
 	}
 	c.JSON(http.StatusCreated, gin.H{"data": m})
 }
 
 func (h *ERPHandler) GetAllSchedules(c *gin.Context) {
-	list, err := h.scheduleService.GetAll()
+	p, err := parseListParams(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid list parameters: " + err.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"data": list, "count": len(list)})
+	list, page, err := h.scheduleService.Scoped(projectScope(c)).List(p)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+	respondList(c, list, page, p.Fields)
 }
 
 func (h *ERPHandler) UpdateSchedule(c *gin.Context) {
@@ -1762,25 +2287,30 @@ func (h *ERPHandler) UpdateSchedule(c *gin.Context) {
 		return
 	}
 	m.ID = uint(id)
-	if err := h.scheduleService.Update(&m); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.scheduleService.Scoped(projectScope(c)).WithContext(c.Request.Context()).Update(&m); err != nil {
+		respondServiceError(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"data": m})
 }
 
 func (h *ERPHandler) FilterSchedules(c *gin.Context) {
-	query, numberRange, dateRange, err := parseFilterParams(c)
+	filter, err := parseFilterParams(c)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid filter parameters: " + err.Error()})
 		return
 	}
-	list, err := h.scheduleService.Filter(query, numberRange, dateRange)
+	p, err := parseListParams(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid list parameters: " + err.Error()})
+		return
+	}
+	list, page, err := h.scheduleService.Scoped(projectScope(c)).Filter(filter, p)
+	if err != nil {
+		respondServiceError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"data": list, "count": len(list)})
+	respondList(c, list, page, p.Fields)
 }
 
 func (h *ERPHandler) DeleteSchedule(c *gin.Context) {
@@ -1789,8 +2319,12 @@ func (h *ERPHandler) DeleteSchedule(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
 		return
 	}
-	if err := h.scheduleService.Delete(uint(id)); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	ctx, ok := deleteContext(c)
+	if !ok {
+		return
+	}
+	if err := h.scheduleService.Scoped(projectScope(c)).WithContext(ctx).Delete(uint(id)); err != nil {
+		respondServiceError(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"message": "deleted"})
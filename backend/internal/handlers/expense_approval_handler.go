@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"erp-backend/internal/models"
+	"erp-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ExpenseActionRequest 审批动作请求体
+type ExpenseActionRequest struct {
+	Comment string `json:"comment"`
+}
+
+// currentActor 从认证上下文取出当前用户及其员工档案 ID
+func currentActor(c *gin.Context) services.Actor {
+	return services.Actor{UserID: c.GetUint("user_id"), EmployeeID: c.GetUint("role_id")}
+}
+
+// expenseAction 解析路径参数与备注后执行一个审批动作
+func (h *ERPHandler) expenseAction(c *gin.Context, action func(id uint, actor services.Actor, comment string) (*models.Expense, error)) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+	var req ExpenseActionRequest
+	// 备注可选，允许空请求体
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	expense, err := action(uint(id), currentActor(c), req.Comment)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": expense})
+}
+
+// POST /api/v1/dbms/expenses/:id/submit
+func (h *ERPHandler) SubmitExpense(c *gin.Context) {
+	h.expenseAction(c, h.expenseService.Scoped(projectScope(c)).WithContext(c.Request.Context()).Submit)
+}
+
+// POST /api/v1/dbms/expenses/:id/approve
+func (h *ERPHandler) ApproveExpense(c *gin.Context) {
+	h.expenseAction(c, h.expenseService.Scoped(projectScope(c)).WithContext(c.Request.Context()).Approve)
+}
+
+// POST /api/v1/dbms/expenses/:id/reject
+func (h *ERPHandler) RejectExpense(c *gin.Context) {
+	h.expenseAction(c, h.expenseService.Scoped(projectScope(c)).WithContext(c.Request.Context()).Reject)
+}
+
+// POST /api/v1/dbms/expenses/:id/pay
+func (h *ERPHandler) PayExpense(c *gin.Context) {
+	h.expenseAction(c, h.expenseService.Scoped(projectScope(c)).WithContext(c.Request.Context()).Pay)
+}
+
+// GET /api/v1/dbms/expenses/:id/approvals
+func (h *ERPHandler) GetExpenseApprovals(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+	list, err := h.expenseService.Scoped(projectScope(c)).GetApprovals(uint(id))
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": list, "count": len(list)})
+}
+
+// GET /api/v1/dbms/approval-thresholds
+func (h *ERPHandler) GetApprovalThresholds(c *gin.Context) {
+	list, err := h.expenseService.GetApprovalThresholds()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": list, "count": len(list)})
+}
+
+// PUT /api/v1/dbms/approval-thresholds
+func (h *ERPHandler) SaveApprovalThreshold(c *gin.Context) {
+	var m models.ApprovalThreshold
+	if err := c.ShouldBindJSON(&m); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := h.expenseService.WithContext(c.Request.Context()).SaveApprovalThreshold(&m); err != nil {
+		respondServiceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": m})
+}
+
+// DELETE /api/v1/dbms/approval-thresholds/:id
+func (h *ERPHandler) DeleteApprovalThreshold(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+	if err := h.expenseService.WithContext(c.Request.Context()).DeleteApprovalThreshold(uint(id)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "deleted"})
+}
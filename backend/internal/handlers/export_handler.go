@@ -0,0 +1,286 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"erp-backend/internal/middleware"
+	"erp-backend/internal/models"
+	"erp-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/xuri/excelize/v2"
+)
+
+// ExportHandler 以 CSV、XLSX 或 JSON 文件导出 dbms 资源
+type ExportHandler struct {
+	exportService *services.ExportService
+}
+
+func NewExportHandler(es *services.ExportService) *ExportHandler {
+	return &ExportHandler{exportService: es}
+}
+
+// GET /api/v1/dbms/<resource>/export?format=csv|xlsx|json  按与 search 相同的过滤参数（filter、query、
+// number_range、date_range）以及 sort、fields 导出全部符合条件的记录（不分页）。
+// 除本表列外，多对一关联输出名称列（如 project_name、donor_name），调用者不能读取的关联不输出；
+// 没有 pii:read 权限时联系方式等个人信息被遮盖
+func (h *ExportHandler) Export(c *gin.Context) {
+	p, err := parseListParams(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid list parameters: " + err.Error()})
+		return
+	}
+	filter, err := parseFilterParams(c)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+	resource := trashResource(c)
+	format := c.DefaultQuery("format", "csv")
+	var w exportWriter
+	switch format {
+	case "csv":
+		w = &csvExport{c: c}
+	case "json":
+		w = &jsonExport{c: c}
+	case "xlsx":
+		w = &xlsxExport{c: c}
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be csv, xlsx or json"})
+		return
+	}
+	req := &services.ExportRequest{
+		Resource: resource,
+		Filter:   filter,
+		Sort:     p.Sort,
+		Fields:   p.Fields,
+		CanRead:  func(r string) bool { return middleware.HasPermission(c, r, models.ActionRead) },
+		ShowPII:  middleware.HasPermission(c, models.ResourcePII, models.ActionRead),
+	}
+	started := false
+	attach := func(contentType string) {
+		started = true
+		c.Header("Content-Type", contentType)
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s-%s.%s"`, resource, time.Now().Format("20060102"), format))
+		c.Status(http.StatusOK)
+	}
+	err = h.exportService.Scoped(projectScope(c)).WithContext(c.Request.Context()).Export(req, &startedWriter{w: w, attach: attach})
+	if err == nil {
+		err = w.Close()
+	}
+	if err != nil {
+		if !started {
+			respondServiceError(c, err)
+			return
+		}
+		// 已开始输出文件，只能中断
+		log.Printf("export of %s failed: %v", resource, err)
+		c.Abort()
+	}
+}
+
+// exportWriter 一种导出文件格式
+type exportWriter interface {
+	services.ExportWriter
+	ContentType() string
+	Close() error
+}
+
+// startedWriter 在写出表头前设置响应头
+type startedWriter struct {
+	w      exportWriter
+	attach func(contentType string)
+}
+
+func (s *startedWriter) Header(columns []string) error {
+	s.attach(s.w.ContentType())
+	return s.w.Header(columns)
+}
+
+func (s *startedWriter) Rows(rows [][]interface{}) error {
+	return s.w.Rows(rows)
+}
+
+// exportCell 单元格的文本形式：空值为空串，时间为 RFC 3339
+func exportCell(v interface{}) string {
+	switch x := v.(type) {
+	case nil:
+		return ""
+	case time.Time:
+		if x.IsZero() {
+			return ""
+		}
+		return x.Format(time.RFC3339)
+	case float64:
+		return strconv.FormatFloat(x, 'f', -1, 64)
+	}
+	return fmt.Sprint(v)
+}
+
+// spreadsheetSafe 对文本单元格应用 spreadsheetText，其他类型原样返回
+func spreadsheetSafe(v interface{}) interface{} {
+	if text, ok := v.(string); ok {
+		return spreadsheetText(text)
+	}
+	return v
+}
+
+// spreadsheetText 以 = + - @、制表符或回车开头的文本前加 '，避免表格软件把捐赠者等填写的内容当作公式执行
+func spreadsheetText(text string) string {
+	if text != "" && strings.ContainsRune("=+-@\t\r", rune(text[0])) {
+		return "'" + text
+	}
+	return text
+}
+
+type csvExport struct {
+	c *gin.Context
+	w *csv.Writer
+}
+
+func (e *csvExport) ContentType() string { return "text/csv; charset=utf-8" }
+
+func (e *csvExport) Header(columns []string) error {
+	e.w = csv.NewWriter(e.c.Writer)
+	return e.w.Write(columns)
+}
+
+func (e *csvExport) Rows(rows [][]interface{}) error {
+	record := make([]string, 0)
+	for _, row := range rows {
+		record = record[:0]
+		for _, v := range row {
+			record = append(record, exportCell(spreadsheetSafe(v)))
+		}
+		if err := e.w.Write(record); err != nil {
+			return err
+		}
+	}
+	// 每批写完即发送给客户端
+	e.w.Flush()
+	e.c.Writer.Flush()
+	return e.w.Error()
+}
+
+// Close 发送尚未发出的内容；没有数据行时只有表头
+func (e *csvExport) Close() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+// jsonExport 输出对象数组，每个对象的键按列的顺序排列
+type jsonExport struct {
+	c       *gin.Context
+	columns [][]byte
+	rows    int
+}
+
+func (e *jsonExport) ContentType() string { return "application/json; charset=utf-8" }
+
+func (e *jsonExport) Header(columns []string) error {
+	for _, col := range columns {
+		key, _ := json.Marshal(col)
+		e.columns = append(e.columns, key)
+	}
+	_, err := e.c.Writer.WriteString("[")
+	return err
+}
+
+func (e *jsonExport) Rows(rows [][]interface{}) error {
+	var buf []byte
+	for _, row := range rows {
+		if e.rows > 0 {
+			buf = append(buf, ',')
+		}
+		e.rows++
+		buf = append(buf, "\n{"...)
+		for i, v := range row {
+			value, err := json.Marshal(v)
+			if err != nil {
+				return err
+			}
+			if i > 0 {
+				buf = append(buf, ',')
+			}
+			buf = append(buf, e.columns[i]...)
+			buf = append(buf, ':')
+			buf = append(buf, value...)
+		}
+		buf = append(buf, '}')
+	}
+	if _, err := e.c.Writer.Write(buf); err != nil {
+		return err
+	}
+	e.c.Writer.Flush()
+	return nil
+}
+
+func (e *jsonExport) Close() error {
+	_, err := e.c.Writer.WriteString("\n]\n")
+	return err
+}
+
+// xlsxExport 以流式写入工作表，行数据暂存在临时文件中，全部写完后输出文件
+type xlsxExport struct {
+	c    *gin.Context
+	file *excelize.File
+	sw   *excelize.StreamWriter
+	row  int
+}
+
+func (e *xlsxExport) ContentType() string {
+	return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+}
+
+func (e *xlsxExport) Header(columns []string) error {
+	e.file = excelize.NewFile()
+	sw, err := e.file.NewStreamWriter("Sheet1")
+	if err != nil {
+		return err
+	}
+	e.sw = sw
+	cells := make([]interface{}, len(columns))
+	for i, col := range columns {
+		cells[i] = col
+	}
+	return e.setRow(cells)
+}
+
+func (e *xlsxExport) Rows(rows [][]interface{}) error {
+	for _, row := range rows {
+		for i, v := range row {
+			if t, ok := v.(time.Time); ok {
+				row[i] = exportCell(t)
+			}
+			row[i] = spreadsheetSafe(row[i])
+		}
+		if err := e.setRow(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *xlsxExport) setRow(cells []interface{}) error {
+	e.row++
+	cell, err := excelize.CoordinatesToCellName(1, e.row)
+	if err != nil {
+		return err
+	}
+	return e.sw.SetRow(cell, cells)
+}
+
+func (e *xlsxExport) Close() error {
+	defer e.file.Close()
+	if err := e.sw.Flush(); err != nil {
+		return err
+	}
+	return e.file.Write(e.c.Writer)
+}
@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"erp-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FundAccountingHandler 基金会计 API
+type FundAccountingHandler struct {
+	fundAccountingService *services.FundAccountingService
+}
+
+func NewFundAccountingHandler(fs *services.FundAccountingService) *FundAccountingHandler {
+	return &FundAccountingHandler{fundAccountingService: fs}
+}
+
+// GET /api/v1/fin/funds/net-assets
+func (h *FundAccountingHandler) NetAssets(c *gin.Context) {
+	report, err := h.fundAccountingService.NetAssets()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": report})
+}
+
+// GET /api/v1/fin/funds/:id/availability?amount=500&project_id=3&date=2025-06-01
+// 可支用时返回 200，否则返回与实际过账相同的 409 错误
+func (h *FundAccountingHandler) CheckAvailability(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+	amount, err := strconv.ParseFloat(c.Query("amount"), 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid amount"})
+		return
+	}
+	var projectID *uint
+	if s := c.Query("project_id"); s != "" {
+		pid, err := strconv.ParseUint(s, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid project_id"})
+			return
+		}
+		v := uint(pid)
+		projectID = &v
+	}
+	date := time.Now().UTC()
+	if d, err := parseDatePtr(c.Query("date")); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid date"})
+		return
+	} else if d != nil {
+		date = *d
+	}
+
+	fund, err := h.fundAccountingService.CheckDebit(uint(id), amount, projectID, date)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"available": true, "fund": fund}})
+}
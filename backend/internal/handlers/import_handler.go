@@ -0,0 +1,180 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"erp-backend/internal/models"
+	"erp-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxImportFileSize 导入文件的大小上限
+const maxImportFileSize = 20 << 20
+
+// ImportHandler 批量导入 donors、donations、inventory
+type ImportHandler struct {
+	importService *services.ImportService
+}
+
+func NewImportHandler(is *services.ImportService) *ImportHandler {
+	return &ImportHandler{importService: is}
+}
+
+// currentUserID 当前用户的 id，未登录时为 nil
+func currentUserID(c *gin.Context) *uint {
+	if id := c.GetUint("user_id"); id != 0 {
+		return &id
+	}
+	return nil
+}
+
+// POST /api/v1/dbms/<resource>/import  multipart 表单：
+//   - file：CSV 或 XLSX 文件（按扩展名识别，也可用 format 指定）；sheet：XLSX 工作表，默认第一个
+//   - template：已保存的映射模板名；mapping：{"文件列名": "字段"} JSON，覆盖模板中的同名列。
+//     未映射的列按列名与字段名相同导入
+//   - dry_run=true：只校验并返回预览，不写入
+//   - mode=all（默认，任一行失败则全部不写入）或 mode=batch（跳过失败行，每 batch_size 行提交一次）
+//
+// 返回导入记录、错误列表与错误报告的下载地址
+func (h *ImportHandler) Import(c *gin.Context) {
+	header, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "file is required"})
+		return
+	}
+	if header.Size > maxImportFileSize {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": fmt.Sprintf("file is larger than %d MB", maxImportFileSize>>20)})
+		return
+	}
+	req := &services.ImportRequest{
+		Resource:  trashResource(c),
+		Filename:  header.Filename,
+		Format:    strings.ToLower(c.PostForm("format")),
+		Sheet:     c.PostForm("sheet"),
+		Template:  c.PostForm("template"),
+		Mode:      c.PostForm("mode"),
+		CreatedBy: currentUserID(c),
+	}
+	if req.Format == "" {
+		req.Format = strings.TrimPrefix(strings.ToLower(filepath.Ext(header.Filename)), ".")
+	}
+	if raw := c.PostForm("mapping"); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &req.Mapping); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "mapping must be a JSON object of column name to field"})
+			return
+		}
+	}
+	if raw := c.PostForm("dry_run"); raw != "" {
+		if req.DryRun, err = strconv.ParseBool(raw); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "dry_run must be true or false"})
+			return
+		}
+	}
+	if raw := c.PostForm("batch_size"); raw != "" {
+		if req.BatchSize, err = strconv.Atoi(raw); err != nil || req.BatchSize <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "batch_size must be a positive integer"})
+			return
+		}
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	defer file.Close()
+	req.Data = file
+
+	result, err := h.importService.Scoped(projectScope(c)).WithContext(c.Request.Context()).Import(req)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+	resp := gin.H{"data": result}
+	if len(result.Errors) > 0 {
+		resp["report_url"] = fmt.Sprintf("%s%s/imports/%d/errors", trashPrefix, req.Resource, result.Job.ID)
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// GET /api/v1/dbms/<resource>/imports/:id/errors  以 CSV 下载导入的错误报告：行号、字段、值与原因
+func (h *ImportHandler) ErrorReport(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+	resource := trashResource(c)
+	job, errs, err := h.importService.ErrorReport(resource, uint(id))
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s-import-%d-errors.csv"`, resource, job.ID))
+	writeImportErrors(c.Writer, errs)
+}
+
+// writeImportErrors 写出错误报告 CSV；字段名与值来自上传的文件，按导出同样的规则转义公式
+func writeImportErrors(out io.Writer, errs []models.ImportError) error {
+	w := csv.NewWriter(out)
+	w.Write([]string{"row", "field", "value", "message"})
+	for _, e := range errs {
+		w.Write([]string{strconv.Itoa(e.Row), spreadsheetText(e.Field), spreadsheetText(e.Value), spreadsheetText(e.Message)})
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// GET /api/v1/dbms/<resource>/import-mappings
+func (h *ImportHandler) ListMappings(c *gin.Context) {
+	list, err := h.importService.ListMappings(trashResource(c))
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": list, "count": len(list)})
+}
+
+// SaveMappingRequest 保存映射模板的请求体
+type SaveMappingRequest struct {
+	Name    string            `json:"name" binding:"required"`
+	Mapping map[string]string `json:"mapping" binding:"required"`
+}
+
+// POST /api/v1/dbms/<resource>/import-mappings  保存映射模板，同名模板被替换
+func (h *ImportHandler) SaveMapping(c *gin.Context) {
+	var req SaveMappingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	m, err := h.importService.WithContext(c.Request.Context()).SaveMapping(trashResource(c), req.Name, req.Mapping, currentUserID(c))
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": m})
+}
+
+// DELETE /api/v1/dbms/<resource>/import-mappings/:id
+func (h *ImportHandler) DeleteMapping(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+	if err := h.importService.WithContext(c.Request.Context()).DeleteMapping(trashResource(c), uint(id)); err != nil {
+		respondServiceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "deleted"})
+}
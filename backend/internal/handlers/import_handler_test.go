@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/csv"
+	"testing"
+
+	"erp-backend/internal/models"
+)
+
+func TestWriteImportErrorsEscapesFormulas(t *testing.T) {
+	tests := []struct {
+		in   models.ImportError
+		want []string
+	}{
+		{models.ImportError{Row: 2, Field: "amount", Value: "abc", Message: "amount must be a number"},
+			[]string{"2", "amount", "abc", "amount must be a number"}},
+		{models.ImportError{Row: 3, Field: "email", Value: `=HYPERLINK("http://evil.example","click")`, Message: "invalid email"},
+			[]string{"3", "email", `'=HYPERLINK("http://evil.example","click")`, "invalid email"}},
+		{models.ImportError{Row: 4, Field: "=cmd|' /C calc'!A0", Value: "+1", Message: "unknown column"},
+			[]string{"4", "'=cmd|' /C calc'!A0", "'+1", "unknown column"}},
+		{models.ImportError{Row: 5, Field: "amount", Value: "-5", Message: "@SUM(A1)"},
+			[]string{"5", "amount", "'-5", "'@SUM(A1)"}},
+		{models.ImportError{Row: 6, Field: "notes", Value: "\t=1+1", Message: "\r=1+1"},
+			[]string{"6", "notes", "'\t=1+1", "'\r=1+1"}},
+	}
+	errs := make([]models.ImportError, len(tests))
+	for i, tt := range tests {
+		errs[i] = tt.in
+	}
+
+	var buf bytes.Buffer
+	if err := writeImportErrors(&buf, errs); err != nil {
+		t.Fatal(err)
+	}
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != len(tests)+1 {
+		t.Fatalf("report has %d records, want header and %d rows", len(records), len(tests))
+	}
+	if got := records[0]; len(got) != 4 || got[0] != "row" || got[1] != "field" || got[2] != "value" || got[3] != "message" {
+		t.Errorf("header = %q", got)
+	}
+	for i, tt := range tests {
+		got := records[i+1]
+		for j := range tt.want {
+			if got[j] != tt.want[j] {
+				t.Errorf("row %d column %d = %q, want %q", tt.in.Row, j, got[j], tt.want[j])
+			}
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"erp-backend/internal/models"
+	"erp-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LedgerHandler 总账 API
+type LedgerHandler struct {
+	ledgerService *services.LedgerService
+}
+
+func NewLedgerHandler(ls *services.LedgerService) *LedgerHandler {
+	return &LedgerHandler{ledgerService: ls}
+}
+
+// GET /api/v1/fin/ledger/accounts
+func (h *LedgerHandler) GetAccounts(c *gin.Context) {
+	list, err := h.ledgerService.GetAccounts()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": list, "count": len(list)})
+}
+
+// POST /api/v1/fin/ledger/accounts
+func (h *LedgerHandler) CreateAccount(c *gin.Context) {
+	var m models.Account
+	if err := c.ShouldBindJSON(&m); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := h.ledgerService.CreateAccount(&m); err != nil {
+		respondServiceError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"data": m})
+}
+
+// GET /api/v1/fin/ledger/entries?source_type=donation&source_id=1&start=...&end=...
+func (h *LedgerHandler) GetEntries(c *gin.Context) {
+	var sourceID uint
+	if s := c.Query("source_id"); s != "" {
+		id, err := strconv.ParseUint(s, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid source_id"})
+			return
+		}
+		sourceID = uint(id)
+	}
+	start, err := parseDatePtr(c.Query("start"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid start date"})
+		return
+	}
+	end, err := parseDatePtr(c.Query("end"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid end date"})
+		return
+	}
+
+	list, err := h.ledgerService.GetEntries(c.Query("source_type"), sourceID, start, end)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": list, "count": len(list)})
+}
+
+// POST /api/v1/fin/ledger/entries
+func (h *LedgerHandler) PostEntry(c *gin.Context) {
+	var req services.ManualEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	entry, err := h.ledgerService.PostManualEntry(&req)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"data": entry})
+}
+
+// GET /api/v1/fin/ledger/trial-balance?as_of=2025-12-31
+func (h *LedgerHandler) TrialBalance(c *gin.Context) {
+	asOf, err := parseDatePtr(c.Query("as_of"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid as_of date"})
+		return
+	}
+	report, err := h.ledgerService.TrialBalance(asOf)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": report})
+}
+
+// GET /api/v1/fin/ledger/accounts/:id/activity?start=...&end=...
+func (h *LedgerHandler) AccountActivity(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+	start, err := parseDatePtr(c.Query("start"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid start date"})
+		return
+	}
+	end, err := parseDatePtr(c.Query("end"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid end date"})
+		return
+	}
+	report, err := h.ledgerService.AccountActivity(uint(id), start, end)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": report})
+}
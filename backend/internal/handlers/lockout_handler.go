@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"erp-backend/internal/repo"
+	"erp-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LockoutHandler 登录记录审查与账号解锁
+type LockoutHandler struct {
+	lockoutService *services.LockoutService
+}
+
+func NewLockoutHandler(ls *services.LockoutService) *LockoutHandler {
+	return &LockoutHandler{lockoutService: ls}
+}
+
+// GET /api/v1/admin/login-attempts?username=&user_id=&ip=&failed=true&since=2024-01-01&limit=200
+func (h *LockoutHandler) GetAttempts(c *gin.Context) {
+	userID, ok := optionalUserID(c)
+	if !ok {
+		return
+	}
+	since, err := parseDatePtr(c.Query("since"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid since, expected YYYY-MM-DD"})
+		return
+	}
+	limit := 200
+	if s := c.Query("limit"); s != "" {
+		if limit, err = strconv.Atoi(s); err != nil || limit <= 0 || limit > 1000 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and 1000"})
+			return
+		}
+	}
+	list, err := h.lockoutService.Attempts(repo.LoginAttemptFilter{
+		Username:   c.Query("username"),
+		UserID:     userID,
+		ClientIP:   c.Query("ip"),
+		FailedOnly: c.Query("failed") == "true",
+		Since:      since,
+		Limit:      limit,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": list, "count": len(list)})
+}
+
+// GET /api/v1/admin/lockouts 当前被锁定的账号
+func (h *LockoutHandler) GetLockouts(c *gin.Context) {
+	list, err := h.lockoutService.LockedAccounts()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": list, "count": len(list)})
+}
+
+// DELETE /api/v1/admin/lockouts/:id  (:id 为 user_id) 解除锁定
+func (h *LockoutHandler) Unlock(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+	if err := h.lockoutService.Unlock(uint(id)); err != nil {
+		respondServiceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "account unlocked"})
+}
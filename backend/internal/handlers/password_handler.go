@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+
+	"erp-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PasswordHandler 修改密码与找回密码
+type PasswordHandler struct {
+	passwordService *services.PasswordService
+}
+
+func NewPasswordHandler(ps *services.PasswordService) *PasswordHandler {
+	return &PasswordHandler{passwordService: ps}
+}
+
+// ChangePassword 登录用户修改自己的密码
+func (h *PasswordHandler) ChangePassword(c *gin.Context) {
+	var req services.ChangePasswordRequest
+	if !bindAuthRequest(c, &req) {
+		return
+	}
+	if err := h.passwordService.Change(c.GetUint("user_id"), c.GetString("session_id"), &req); err != nil {
+		respondAuthError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Password changed; other sessions have been signed out",
+	})
+}
+
+// ForgotPassword 申请重置密码；无论账号是否存在都返回相同结果
+func (h *PasswordHandler) ForgotPassword(c *gin.Context) {
+	var req services.ForgotPasswordRequest
+	if !bindAuthRequest(c, &req) {
+		return
+	}
+	if err := h.passwordService.RequestReset(&req); err != nil {
+		respondAuthError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "If the account exists, a password reset link has been sent to its email address",
+	})
+}
+
+// ResetPassword 使用邮件中的令牌设置新密码
+func (h *PasswordHandler) ResetPassword(c *gin.Context) {
+	var req services.ResetPasswordRequest
+	if !bindAuthRequest(c, &req) {
+		return
+	}
+	if err := h.passwordService.Reset(&req); err != nil {
+		respondAuthError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Password has been reset, please log in with the new password",
+	})
+}
+
+func bindAuthRequest(c *gin.Context, req interface{}) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Request parameter error: " + err.Error(),
+		})
+		return false
+	}
+	return true
+}
+
+// respondAuthError 以认证接口的 success/message 格式返回服务层错误
+func respondAuthError(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, services.ErrInvalidInput):
+		status = http.StatusBadRequest
+	case errors.Is(err, services.ErrNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, services.ErrConflict):
+		status = http.StatusConflict
+	case errors.Is(err, services.ErrForbidden):
+		status = http.StatusForbidden
+	case errors.Is(err, services.ErrUnauthorized):
+		status = http.StatusUnauthorized
+	case errors.Is(err, services.ErrTooManyRequests):
+		status = http.StatusTooManyRequests
+		var lockErr *services.LockoutError
+		if errors.As(err, &lockErr) {
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(lockErr.RetryAfter.Seconds()))))
+		}
+	}
+	c.JSON(status, gin.H{
+		"success": false,
+		"message": err.Error(),
+	})
+}
@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"erp-backend/internal/models"
+	"erp-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReceiptHandler 年度捐赠收据：捐赠者下载本人的收据，财务人员批量开具、作废与重开
+type ReceiptHandler struct {
+	receiptService *services.ReceiptService
+}
+
+func NewReceiptHandler(rs *services.ReceiptService) *ReceiptHandler {
+	return &ReceiptHandler{receiptService: rs}
+}
+
+// ReceiptReasonRequest 作废或重开收据的请求体
+type ReceiptReasonRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+// GenerateReceiptsRequest 批量开具收据的请求体
+type GenerateReceiptsRequest struct {
+	Year int `json:"year" binding:"required"`
+}
+
+func sendReceipt(c *gin.Context, receipt *models.TaxReceipt, data []byte) {
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="receipt-%s.pdf"`, receipt.ReceiptNumber))
+	c.Data(http.StatusOK, "application/pdf", data)
+}
+
+// GET /api/v1/donor/receipts  本人的全部收据，含已作废的
+func (h *ReceiptHandler) DonorList(c *gin.Context) {
+	list, err := h.receiptService.List(0, c.GetUint("role_id"))
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": list, "count": len(list)})
+}
+
+// GET /api/v1/donor/receipts/:year  下载本人某年的收据 PDF；尚未开具时按当前的捐赠记录开具
+func (h *ReceiptHandler) DonorDownload(c *gin.Context) {
+	year, err := strconv.Atoi(c.Param("year"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid year"})
+		return
+	}
+	receipt, data, err := h.receiptService.WithContext(c.Request.Context()).DonorPDF(c.GetUint("role_id"), year, currentUserID(c))
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+	sendReceipt(c, receipt, data)
+}
+
+// GET /api/v1/fin/receipts?year=2025&donor_id=1
+func (h *ReceiptHandler) List(c *gin.Context) {
+	var year int
+	var donorID uint64
+	var err error
+	if s := c.Query("year"); s != "" {
+		if year, err = strconv.Atoi(s); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid year"})
+			return
+		}
+	}
+	if s := c.Query("donor_id"); s != "" {
+		if donorID, err = strconv.ParseUint(s, 10, 32); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid donor_id"})
+			return
+		}
+	}
+	list, err := h.receiptService.List(year, uint(donorID))
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": list, "count": len(list)})
+}
+
+// POST /api/v1/fin/receipts/generate  为当年有捐赠的每位捐赠者开具收据，已有有效收据的跳过
+func (h *ReceiptHandler) Generate(c *gin.Context) {
+	var req GenerateReceiptsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	result, err := h.receiptService.WithContext(c.Request.Context()).Generate(req.Year, currentUserID(c))
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": result})
+}
+
+// GET /api/v1/fin/receipts/:id/pdf
+func (h *ReceiptHandler) Download(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+	receipt, data, err := h.receiptService.PDF(uint(id))
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+	sendReceipt(c, receipt, data)
+}
+
+// POST /api/v1/fin/receipts/:id/void  {"reason": "..."}
+func (h *ReceiptHandler) Void(c *gin.Context) {
+	h.change(c, (*services.ReceiptService).Void)
+}
+
+// POST /api/v1/fin/receipts/:id/reissue  {"reason": "..."}  作废原收据并按当前的捐赠记录以新收据号重开
+func (h *ReceiptHandler) Reissue(c *gin.Context) {
+	h.change(c, (*services.ReceiptService).Reissue)
+}
+
+func (h *ReceiptHandler) change(c *gin.Context, fn func(s *services.ReceiptService, id uint, by *uint, reason string) (*models.TaxReceipt, error)) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+	var req ReceiptReasonRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	receipt, err := fn(h.receiptService.WithContext(c.Request.Context()), uint(id), currentUserID(c), req.Reason)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": receipt})
+}
@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"erp-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegistrationHandler 员工注册审核队列
+type RegistrationHandler struct {
+	registrationService *services.RegistrationService
+}
+
+func NewRegistrationHandler(rs *services.RegistrationService) *RegistrationHandler {
+	return &RegistrationHandler{registrationService: rs}
+}
+
+// GET /api/v1/admin/registrations
+func (h *RegistrationHandler) GetPending(c *gin.Context) {
+	list, err := h.registrationService.Pending()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": list, "count": len(list)})
+}
+
+// GET /api/v1/admin/registrations/reviews?user_id=1
+func (h *RegistrationHandler) GetReviews(c *gin.Context) {
+	userID, ok := optionalUserID(c)
+	if !ok {
+		return
+	}
+	list, err := h.registrationService.Reviews(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": list, "count": len(list)})
+}
+
+// POST /api/v1/admin/registrations/:id/approve  (:id 为注册账号的 user_id)
+func (h *RegistrationHandler) Approve(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+	var req services.ApproveRegistrationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	review, err := h.registrationService.Approve(uint(id), currentActor(c), &req)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "registration approved", "data": review})
+}
+
+// POST /api/v1/admin/registrations/:id/reject
+func (h *RegistrationHandler) Reject(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+	var req services.RejectRegistrationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	review, err := h.registrationService.Reject(uint(id), currentActor(c), &req)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "registration rejected", "data": review})
+}
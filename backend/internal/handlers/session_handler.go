@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"erp-backend/internal/models"
+	"erp-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SessionHandler 管理员查看与终止用户的登录会话
+type SessionHandler struct {
+	sessionService *services.SessionService
+}
+
+func NewSessionHandler(ss *services.SessionService) *SessionHandler {
+	return &SessionHandler{sessionService: ss}
+}
+
+// GET /api/v1/admin/sessions?user_id=1
+func (h *SessionHandler) GetSessions(c *gin.Context) {
+	userID, ok := optionalUserID(c)
+	if !ok {
+		return
+	}
+	list, err := h.sessionService.ActiveSessions(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": list, "count": len(list)})
+}
+
+// DELETE /api/v1/admin/sessions/:id
+func (h *SessionHandler) RevokeSession(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+	if err := h.sessionService.RevokeSession(uint(id)); err != nil {
+		respondServiceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "session revoked"})
+}
+
+// DELETE /api/v1/admin/sessions?user_id=1 终止该用户的全部会话
+func (h *SessionHandler) RevokeUserSessions(c *gin.Context) {
+	userID, ok := optionalUserID(c)
+	if !ok {
+		return
+	}
+	if userID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "user_id is required"})
+		return
+	}
+	n, err := h.sessionService.RevokeUserSessions(userID, models.SessionRevokedAdmin)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "sessions revoked", "count": n})
+}
+
+func optionalUserID(c *gin.Context) (uint, bool) {
+	s := c.Query("user_id")
+	if s == "" {
+		return 0, true
+	}
+	id, err := strconv.ParseUint(s, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user_id"})
+		return 0, false
+	}
+	return uint(id), true
+}
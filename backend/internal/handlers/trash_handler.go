@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"erp-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// trashPrefix 回收站路由所在的前缀，资源名取其后的第一段
+const trashPrefix = "/api/v1/dbms/"
+
+// TrashHandler 各 ERP 资源的回收站：查看已删除的记录、恢复与彻底删除
+type TrashHandler struct {
+	trashService *services.TrashService
+}
+
+func NewTrashHandler(ts *services.TrashService) *TrashHandler {
+	return &TrashHandler{trashService: ts}
+}
+
+// trashResource 从路由模板中取资源名，如 /api/v1/dbms/donors/trash → donors
+func trashResource(c *gin.Context) string {
+	resource, _, _ := strings.Cut(strings.TrimPrefix(c.FullPath(), trashPrefix), "/")
+	return resource
+}
+
+// GET /api/v1/dbms/<resource>/trash  已删除的记录，最近删除的在前
+func (h *TrashHandler) List(c *gin.Context) {
+	list, count, err := h.trashService.Scoped(projectScope(c)).List(trashResource(c))
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": list, "count": count})
+}
+
+// POST /api/v1/dbms/<resource>/:id/restore  从回收站恢复
+func (h *TrashHandler) Restore(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+	err = h.trashService.Scoped(projectScope(c)).WithContext(c.Request.Context()).Restore(trashResource(c), uint(id))
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "restored"})
+}
+
+// DELETE /api/v1/dbms/<resource>/:id/purge  彻底删除回收站中的记录（需要 trash:purge 权限）
+func (h *TrashHandler) Purge(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+	if err := h.trashService.Scoped(projectScope(c)).WithContext(c.Request.Context()).Purge(trashResource(c), uint(id)); err != nil {
+		respondServiceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "purged"})
+}
@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"erp-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TwoFactorHandler 双因素认证：两步登录、登记与管理
+type TwoFactorHandler struct {
+	authService      *services.AuthService
+	twoFactorService *services.TwoFactorService
+}
+
+func NewTwoFactorHandler(as *services.AuthService, tfs *services.TwoFactorService) *TwoFactorHandler {
+	return &TwoFactorHandler{authService: as, twoFactorService: tfs}
+}
+
+// VerifyLogin 两步登录的第二步：提交挑战令牌与验证码（或恢复码）换取令牌
+func (h *TwoFactorHandler) VerifyLogin(c *gin.Context) {
+	var req services.TwoFactorLoginRequest
+	if !bindAuthRequest(c, &req) {
+		return
+	}
+	req.UserAgent, req.ClientIP = c.Request.UserAgent(), c.ClientIP()
+	resp, err := h.authService.VerifyTwoFactor(&req)
+	if err != nil {
+		respondAuthError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Login successful",
+		"data":    resp,
+	})
+}
+
+// SetupLogin 角色强制双因素认证但尚未登记时，登录过程中获取登记密钥
+func (h *TwoFactorHandler) SetupLogin(c *gin.Context) {
+	var req services.TwoFactorSetupRequest
+	if !bindAuthRequest(c, &req) {
+		return
+	}
+	enrollment, err := h.authService.SetupTwoFactor(&req)
+	if err != nil {
+		respondAuthError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Add the account to your authenticator app, then enter the generated code",
+		"data":    enrollment,
+	})
+}
+
+// GetStatus 当前账号的双因素认证状态
+func (h *TwoFactorHandler) GetStatus(c *gin.Context) {
+	status, err := h.twoFactorService.Status(c.GetUint("user_id"), c.GetStringSlice("roles"))
+	if err != nil {
+		respondAuthError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    status,
+	})
+}
+
+// Enroll 开始登记：返回密钥与供二维码使用的 otpauth:// URI
+func (h *TwoFactorHandler) Enroll(c *gin.Context) {
+	enrollment, err := h.twoFactorService.Enroll(c.GetUint("user_id"), c.GetString("username"), c.GetString("user_type"))
+	if err != nil {
+		respondAuthError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Add the account to your authenticator app, then confirm with the generated code",
+		"data":    enrollment,
+	})
+}
+
+// Confirm 提交验证码完成登记，返回恢复码
+func (h *TwoFactorHandler) Confirm(c *gin.Context) {
+	var req services.TwoFactorCodeRequest
+	if !bindAuthRequest(c, &req) {
+		return
+	}
+	codes, err := h.twoFactorService.Confirm(c.GetUint("user_id"), req.Code)
+	if err != nil {
+		respondAuthError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Two-factor authentication enabled; store the recovery codes somewhere safe",
+		"data":    gin.H{"recovery_codes": codes},
+	})
+}
+
+// Disable 关闭双因素认证
+func (h *TwoFactorHandler) Disable(c *gin.Context) {
+	var req services.TwoFactorCodeRequest
+	if !bindAuthRequest(c, &req) {
+		return
+	}
+	if err := h.twoFactorService.Disable(c.GetUint("user_id"), c.GetStringSlice("roles"), req.Code); err != nil {
+		respondAuthError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Two-factor authentication disabled",
+	})
+}
+
+// RegenerateRecoveryCodes 重新生成恢复码，旧的恢复码全部作废
+func (h *TwoFactorHandler) RegenerateRecoveryCodes(c *gin.Context) {
+	var req services.TwoFactorCodeRequest
+	if !bindAuthRequest(c, &req) {
+		return
+	}
+	codes, err := h.twoFactorService.RegenerateRecoveryCodes(c.GetUint("user_id"), req.Code)
+	if err != nil {
+		respondAuthError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "New recovery codes generated; previous codes no longer work",
+		"data":    gin.H{"recovery_codes": codes},
+	})
+}
+
+// DELETE /api/v1/admin/two-factor/:id  (:id 为 user_id) 清除账号的双因素认证
+func (h *TwoFactorHandler) Reset(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+	if err := h.twoFactorService.Reset(uint(id)); err != nil {
+		respondServiceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "two-factor authentication reset"})
+}
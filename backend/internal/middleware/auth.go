@@ -5,11 +5,24 @@ import (
 	"net/http"
 	"strings"
 
+	"erp-backend/internal/repo"
 	"erp-backend/pkg/utils"
 
 	"github.com/gin-gonic/gin"
 )
 
+// SessionValidator 判断登录会话是否已被终止（撤销列表）
+type SessionValidator interface {
+	IsRevoked(sessionID string) bool
+}
+
+var sessionValidator SessionValidator
+
+// SetSessionValidator 注册会话撤销列表（启动时由 main 注入）
+func SetSessionValidator(sv SessionValidator) {
+	sessionValidator = sv
+}
+
 // AuthMiddleware JWT认证中间件
 func AuthMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -37,7 +50,7 @@ func AuthMiddleware() gin.HandlerFunc {
 
 		// 验证token
 		claims, err := utils.ValidateToken(parts[1])
-		if err != nil {
+		if err != nil || claims.SessionID == "" {
 			c.JSON(http.StatusUnauthorized, gin.H{
 				"success": false,
 				"message": "Invalid or expired authentication token",
@@ -46,11 +59,30 @@ func AuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
+		// 已注销或被终止的会话，其令牌在到期前也不再接受
+		if sessionValidator != nil && sessionValidator.IsRevoked(claims.SessionID) {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"success": false,
+				"message": "Session has been terminated, please log in again",
+			})
+			c.Abort()
+			return
+		}
+
 		// 将用户信息存入上下文
 		c.Set("user_id", claims.UserID)
 		c.Set("username", claims.Username)
 		c.Set("user_type", claims.UserType)
 		c.Set("role_id", claims.RoleID)
+		c.Set("roles", claims.Roles)
+		c.Set("session_id", claims.SessionID)
+
+		// 请求 context 携带操作人，经 WithContext 的写操作据此记录审计日志
+		c.Request = c.Request.WithContext(repo.WithAuditActor(c.Request.Context(), repo.AuditActor{
+			UserID:   claims.UserID,
+			Username: claims.Username,
+			ClientIP: c.ClientIP(),
+		}))
 
 		c.Next()
 	}
@@ -0,0 +1,78 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PermissionChecker 判断一组角色是否拥有某资源上的某操作权限
+type PermissionChecker interface {
+	HasPermission(roles []string, resource, action string) bool
+}
+
+var permissionChecker PermissionChecker
+
+// SetPermissionChecker 注册权限判断实现（启动时由 main 注入）
+func SetPermissionChecker(pc PermissionChecker) {
+	permissionChecker = pc
+}
+
+// RequirePermission 要求当前用户拥有固定的 resource:action 权限
+func RequirePermission(resource, action string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		checkPermission(c, resource, action)
+	}
+}
+
+// RequireResourcePermission 用于整组 CRUD 路由：资源取路由模板中 prefix 之后的第一段，
+// 操作按 HTTP 方法推断（GET→read, POST→create, PUT/PATCH→update, DELETE→delete）。
+// 形如 POST /<resource>/:id/<verb> 的路由使用 verb 作为操作，例如 expenses:approve。
+func RequireResourcePermission(prefix string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		resource, action := routePermission(strings.TrimPrefix(c.FullPath(), prefix), c.Request.Method)
+		checkPermission(c, resource, action)
+	}
+}
+
+func routePermission(path, method string) (resource, action string) {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	resource = segments[0]
+	switch method {
+	case http.MethodGet, http.MethodHead:
+		return resource, "read"
+	case http.MethodPut, http.MethodPatch:
+		action = "update"
+	case http.MethodDelete:
+		action = "delete"
+	default:
+		action = "create"
+	}
+	if len(segments) == 3 && strings.HasPrefix(segments[1], ":") {
+		action = segments[2]
+	}
+	return resource, action
+}
+
+// HasPermission 判断当前请求的用户角色是否拥有某权限，用于处理器内按参数追加的权限检查
+func HasPermission(c *gin.Context, resource, action string) bool {
+	var roles []string
+	if v, ok := c.Get("roles"); ok {
+		roles, _ = v.([]string)
+	}
+	return permissionChecker != nil && permissionChecker.HasPermission(roles, resource, action)
+}
+
+func checkPermission(c *gin.Context, resource, action string) {
+	if !HasPermission(c, resource, action) {
+		c.JSON(http.StatusForbidden, gin.H{
+			"success": false,
+			"message": fmt.Sprintf("Access denied: %s:%s permission required", resource, action),
+		})
+		c.Abort()
+		return
+	}
+	c.Next()
+}
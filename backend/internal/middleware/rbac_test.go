@@ -0,0 +1,100 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// recordingChecker 只授予 granted 中的权限，并记录最近一次检查的 resource:action
+type recordingChecker struct {
+	granted map[string]bool
+	checked string
+}
+
+func (c *recordingChecker) HasPermission(roles []string, resource, action string) bool {
+	c.checked = resource + ":" + action
+	return len(roles) > 0 && c.granted[c.checked]
+}
+
+func TestRequireResourcePermission(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tests := []struct {
+		prefix string
+		method string
+		route  string
+		path   string
+		want   string
+	}{
+		{"/api/v1/dbms", http.MethodGet, "/donors", "/donors", "donors:read"},
+		{"/api/v1/dbms", http.MethodGet, "/donors/search", "/donors/search", "donors:read"},
+		{"/api/v1/dbms", http.MethodGet, "/expenses/:id/approvals", "/expenses/7/approvals", "expenses:read"},
+		{"/api/v1/dbms", http.MethodHead, "/donors", "/donors", "donors:read"},
+		{"/api/v1/dbms", http.MethodPost, "/donors", "/donors", "donors:create"},
+		{"/api/v1/dbms", http.MethodPut, "/donors/:id", "/donors/7", "donors:update"},
+		{"/api/v1/dbms", http.MethodPatch, "/donors/:id", "/donors/7", "donors:update"},
+		{"/api/v1/dbms", http.MethodDelete, "/donors/:id", "/donors/7", "donors:delete"},
+		// POST /<resource>/:id/<verb> 使用 verb 作为操作
+		{"/api/v1/dbms", http.MethodPost, "/expenses/:id/submit", "/expenses/7/submit", "expenses:submit"},
+		{"/api/v1/dbms", http.MethodPost, "/expenses/:id/approve", "/expenses/7/approve", "expenses:approve"},
+		{"/api/v1/dbms", http.MethodPost, "/expenses/:id/reject", "/expenses/7/reject", "expenses:reject"},
+		{"/api/v1/dbms", http.MethodPost, "/expenses/:id/pay", "/expenses/7/pay", "expenses:pay"},
+		{"/api/v1/dbms", http.MethodPost, "/users/:id/restore", "/users/7/restore", "users:restore"},
+		{"/api/v1/dbms", http.MethodDelete, "/users/:id/purge", "/users/7/purge", "users:purge"},
+		// 第二段不是路由参数时仍按 HTTP 方法推断
+		{"/api/v1/fin", http.MethodPost, "/receipts/generate", "/receipts/generate", "receipts:create"},
+		{"/api/v1/fin", http.MethodPut, "/inventory/cost-methods", "/inventory/cost-methods", "inventory:update"},
+		{"/api/v1", http.MethodGet, "/audit", "/audit", "audit:read"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.prefix+tt.route, func(t *testing.T) {
+			for _, granted := range []bool{true, false} {
+				checker := &recordingChecker{granted: map[string]bool{}}
+				if granted {
+					checker.granted[tt.want] = true
+				}
+				SetPermissionChecker(checker)
+				t.Cleanup(func() { SetPermissionChecker(nil) })
+
+				r := gin.New()
+				group := r.Group(tt.prefix)
+				group.Use(func(c *gin.Context) { c.Set("roles", []string{"tester"}) })
+				group.Use(RequireResourcePermission(tt.prefix))
+				group.Handle(tt.method, tt.route, func(c *gin.Context) { c.Status(http.StatusNoContent) })
+
+				w := httptest.NewRecorder()
+				r.ServeHTTP(w, httptest.NewRequest(tt.method, tt.prefix+tt.path, nil))
+
+				if checker.checked != tt.want {
+					t.Errorf("checked %s, want %s", checker.checked, tt.want)
+				}
+				want := http.StatusForbidden
+				if granted {
+					want = http.StatusNoContent
+				}
+				if w.Code != want {
+					t.Errorf("granted=%v: status %d, want %d", granted, w.Code, want)
+				}
+			}
+		})
+	}
+}
+
+func TestRequireResourcePermissionWithoutRoles(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	SetPermissionChecker(&recordingChecker{granted: map[string]bool{"donors:read": true}})
+	t.Cleanup(func() { SetPermissionChecker(nil) })
+
+	r := gin.New()
+	group := r.Group("/api/v1/dbms")
+	group.Use(RequireResourcePermission("/api/v1/dbms"))
+	group.GET("/donors", func(c *gin.Context) { c.Status(http.StatusNoContent) })
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/dbms/donors", nil))
+	if w.Code != http.StatusForbidden {
+		t.Errorf("request without roles: status %d, want %d", w.Code, http.StatusForbidden)
+	}
+}
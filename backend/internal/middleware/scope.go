@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"log"
+	"net/http"
+
+	"erp-backend/internal/repo"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ProjectScopeKey 上下文中保存调用者行级数据范围的键
+const ProjectScopeKey = "project_scope"
+
+// ProjectScopeResolver 根据调用者身份计算其可访问的项目范围
+type ProjectScopeResolver interface {
+	Resolve(userType string, employeeID uint, roles []string) (*repo.ProjectScope, error)
+}
+
+// ResolveProjectScope 计算调用者的项目数据范围并存入上下文（nil 表示全机构）。
+// 需放在 AuthMiddleware 之后。
+func ResolveProjectScope(resolver ProjectScopeResolver) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var roles []string
+		if v, ok := c.Get("roles"); ok {
+			roles, _ = v.([]string)
+		}
+		scope, err := resolver.Resolve(c.GetString("user_type"), c.GetUint("role_id"), roles)
+		if err != nil {
+			log.Printf("failed to resolve project scope for user %d: %v", c.GetUint("user_id"), err)
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"success": false,
+				"message": "Failed to resolve data access scope",
+			})
+			c.Abort()
+			return
+		}
+		c.Set(ProjectScopeKey, scope)
+		c.Next()
+	}
+}
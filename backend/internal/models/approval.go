@@ -0,0 +1,47 @@
+package models
+
+import "time"
+
+// 支出审批状态
+const (
+	ExpenseStatusDraft     = "draft"
+	ExpenseStatusSubmitted = "submitted"
+	ExpenseStatusApproved  = "approved"
+	ExpenseStatusRejected  = "rejected"
+	ExpenseStatusPaid      = "paid"
+)
+
+// 支出审批动作
+const (
+	ExpenseActionSubmit  = "submit"
+	ExpenseActionApprove = "approve"
+	ExpenseActionReject  = "reject"
+	ExpenseActionPay     = "pay"
+)
+
+// ExpenseApproval 支出审批记录表（每次状态流转一条，只追加不修改）
+type ExpenseApproval struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	ExpenseID  uint      `gorm:"index;not null" json:"expense_id"`
+	Action     string    `gorm:"size:20;not null" json:"action"`
+	FromStatus string    `gorm:"size:20" json:"from_status"`
+	ToStatus   string    `gorm:"size:20;not null" json:"to_status"`
+	UserID     uint      `json:"user_id"`
+	EmployeeID *uint     `json:"employee_id"`
+	Amount     float64   `gorm:"type:decimal(10,2)" json:"amount"`
+	Comment    string    `json:"comment"`
+	CreatedAt  time.Time `json:"created_at" gorm:"autoCreateTime"`
+
+	// 关联
+	Employee *Employee `json:"employee,omitempty" gorm:"foreignKey:EmployeeID;references:ID;belongsTo"`
+}
+
+// ApprovalThreshold 审批额度表：某部门（为空表示全部门）中担任某职位的员工可审批的支出上限
+type ApprovalThreshold struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	Department string    `gorm:"size:100;uniqueIndex:idx_threshold_dept_pos" json:"department"`
+	Position   string    `gorm:"size:100;not null;uniqueIndex:idx_threshold_dept_pos" json:"position"`
+	MaxAmount  float64   `gorm:"type:decimal(12,2);not null" json:"max_amount"`
+	CreatedAt  time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt  time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
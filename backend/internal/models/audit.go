@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// 审计操作
+const (
+	AuditCreate  = "create"
+	AuditUpdate  = "update"
+	AuditDelete  = "delete"  // 含软删除
+	AuditRestore = "restore" // 从回收站恢复
+	AuditPurge   = "purge"   // 彻底删除已软删除的行
+)
+
+// AuditLog 审计日志表：每次增删改一条记录一行（只追加，不允许修改或删除）
+type AuditLog struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	UserID    *uint     `gorm:"index" json:"user_id"` // 写入未携带操作人（系统任务、未登录请求）时为空
+	Username  string    `gorm:"size:100" json:"username"`
+	Action    string    `gorm:"size:10;index" json:"action"`
+	Table     string    `gorm:"column:table_name;size:64;index" json:"table_name"`
+	RecordID  string    `gorm:"size:64;index" json:"record_id"` // 主键值，复合主键以逗号分隔
+	Changes   JSONText  `gorm:"type:text" json:"changes"`       // {"列名": {"old": 旧值, "new": 新值}}
+	ClientIP  string    `gorm:"size:64" json:"client_ip"`
+	CreatedAt time.Time `gorm:"index" json:"created_at"`
+}
+
+// JSONText 以文本列保存的 JSON，序列化时原样输出而不是作为字符串
+type JSONText string
+
+func (j JSONText) MarshalJSON() ([]byte, error) {
+	if j == "" {
+		return []byte("null"), nil
+	}
+	return []byte(j), nil
+}
@@ -8,12 +8,19 @@ import (
 type User struct {
 	ID           uint       `json:"id" gorm:"primaryKey"`
 	Username     string     `json:"username" gorm:"unique;not null"`
-	PasswordHash string     `json:"password_hash" gorm:"column:password_hash;not null"`
+	PasswordHash string     `json:"-" gorm:"column:password_hash;not null"`
 	UserType     string     `json:"user_type" gorm:"column:user_type;not null"`
 	Status       string     `json:"status" gorm:"default:active"`
 	CreatedAt    time.Time  `json:"created_at" gorm:"autoCreateTime"`
 	UpdatedAt    time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
 	LastLogin    *time.Time `json:"last_login,omitempty"`
+
+	SoftDelete
+
+	// 关联的档案，按 UserType 至多有其中一个
+	Employee  *Employee  `json:"employee,omitempty" gorm:"foreignKey:UserID"`
+	Volunteer *Volunteer `json:"volunteer,omitempty" gorm:"foreignKey:UserID"`
+	Donor     *Donor     `json:"donor,omitempty" gorm:"foreignKey:UserID"`
 }
 
 // TableName 指定表名
@@ -31,6 +38,8 @@ type Location struct {
 	CountryCode string    `json:"country_code" gorm:"size:3"`
 	CreatedAt   time.Time `json:"created_at" gorm:"autoCreateTime"`
 	UpdatedAt   time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+
+	SoftDelete
 }
 
 // TableName 指定表名
@@ -54,7 +63,9 @@ type Project struct {
 	CreatedAt   time.Time  `json:"created_at" gorm:"autoCreateTime"`
 	UpdatedAt   time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
 
-	Location *Location `json:"location,omitempty" gorm:"foreignKey:LocationID"`
+	SoftDelete
+
+	Location *Location `json:"location,omitempty" gorm:"foreignKey:LocationID;references:ID;belongsTo"`
 }
 
 // TableName 指定表名
@@ -74,12 +85,15 @@ type Donor struct {
 	Address        string    `json:"address"`
 	DonorType      string    `json:"donor_type" gorm:"default:individual"`
 	TotalDonated   float64   `json:"total_donated" gorm:"default:0"`
+	InKindTotal    float64   `json:"in_kind_total" gorm:"default:0"` // 实物捐赠估值累计
 	EnrollmentDate time.Time `json:"enrollment_date" gorm:"default:CURRENT_DATE"`
 	Status         string    `json:"status" gorm:"default:active"`
 	Notes          string    `json:"notes"`
 	CreatedAt      time.Time `json:"created_at" gorm:"autoCreateTime"`
 	UpdatedAt      time.Time `json:"updated_at" gorm:"autoUpdateTime"`
 
+	SoftDelete
+
 	User *User `json:"user,omitempty" gorm:"foreignKey:UserID"`
 }
 
@@ -106,8 +120,10 @@ type Volunteer struct {
 	CreatedAt        time.Time `json:"created_at" gorm:"autoCreateTime"`
 	UpdatedAt        time.Time `json:"updated_at" gorm:"autoUpdateTime"`
 
+	SoftDelete
+
 	User     *User     `json:"user,omitempty" gorm:"foreignKey:UserID"`
-	Location *Location `json:"location,omitempty" gorm:"foreignKey:LocationID"`
+	Location *Location `json:"location,omitempty" gorm:"foreignKey:LocationID;references:ID;belongsTo"`
 }
 
 // TableName 指定表名
@@ -134,8 +150,10 @@ type Employee struct {
 	CreatedAt  time.Time `json:"created_at" gorm:"autoCreateTime"`
 	UpdatedAt  time.Time `json:"updated_at" gorm:"autoUpdateTime"`
 
+	SoftDelete
+
 	User     *User     `json:"user,omitempty" gorm:"foreignKey:UserID"`
-	Location *Location `json:"location,omitempty" gorm:"foreignKey:LocationID"`
+	Location *Location `json:"location,omitempty" gorm:"foreignKey:LocationID;references:ID;belongsTo"`
 }
 
 // TableName 指定表名
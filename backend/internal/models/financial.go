@@ -17,6 +17,8 @@ type Transaction struct {
 	CreatedAt         time.Time  `json:"created_at" gorm:"autoCreateTime"`
 	UpdatedAt         time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
 
+	SoftDelete
+
 	// 关联
 	Purchases []Purchase `json:"purchases,omitempty"`
 	Payrolls  []Payroll  `json:"payrolls,omitempty"`
@@ -32,19 +34,30 @@ type Donation struct {
 	DonationType  string    `gorm:"size:20;not null" json:"donation_type"`
 	Category      string    `gorm:"size:20;not null" json:"category"`
 	ProjectID     *uint     `json:"project_id"`
+	FundID        *uint     `json:"fund_id"`
 	DonationDate  time.Time `json:"donation_date"`
 	PaymentMethod string    `json:"payment_method"`
 	Notes         string    `json:"notes"`
 	CreatedAt     time.Time `json:"created_at" gorm:"autoCreateTime"`
 	UpdatedAt     time.Time `json:"updated_at" gorm:"autoUpdateTime"`
 
-	// 关联
-	Donor       *Donor       `json:"donor,omitempty" gorm:"foreignKey:DonorID;references:ID"`
-	Project     *Project     `json:"project,omitempty" gorm:"foreignKey:ProjectID;references:ID"`
-	Transaction *Transaction `json:"transaction,omitempty" gorm:"foreignKey:TransactionID;references:ID"`
+	SoftDelete
+
+	// 关联。被引用的模型带有同名的业务编号字段（如 Project.ProjectID）时 gorm 会把多对一关联猜成一对一，
+	// 因此多对一关联都标明 belongsTo
+	Donor       *Donor       `json:"donor,omitempty" gorm:"foreignKey:DonorID;references:ID;belongsTo"`
+	Project     *Project     `json:"project,omitempty" gorm:"foreignKey:ProjectID;references:ID;belongsTo"`
+	Fund        *Fund        `json:"fund,omitempty" gorm:"foreignKey:FundID;references:ID;belongsTo"`
+	Transaction *Transaction `json:"transaction,omitempty" gorm:"foreignKey:TransactionID;references:ID;belongsTo"`
 	Gifts       []Gift       `json:"gifts,omitempty"`
 }
 
+// 基金类型：限定性基金由捐赠者限定用途或期限，非限定性基金可自由支配
+const (
+	FundTypeUnrestricted = "unrestricted"
+	FundTypeRestricted   = "restricted"
+)
+
 // Fund 基金管理表
 type Fund struct {
 	ID             uint      `gorm:"primaryKey" json:"id"`
@@ -60,10 +73,20 @@ type Fund struct {
 	CreatedAt      time.Time `json:"created_at" gorm:"autoCreateTime"`
 	UpdatedAt      time.Time `json:"updated_at" gorm:"autoUpdateTime"`
 
-	// 关联
-	Donor       *Donor       `json:"donor,omitempty" gorm:"foreignKey:DonorID"`
-	Project     *Project     `json:"project,omitempty" gorm:"foreignKey:ProjectID"`
-	Transaction *Transaction `json:"transaction,omitempty" gorm:"foreignKey:TransactionID"`
+	SoftDelete
+
+	// 可支用时间窗口（通常用于限定性基金），为空表示不限
+	AvailableFrom  *time.Time `json:"available_from"`
+	AvailableUntil *time.Time `json:"available_until"`
+
+	// 捐赠者（DonorID）限定的用途：只能用于该类型（Project.ProjectType）的项目，为空表示不限
+	RestrictedPurpose string `gorm:"size:50" json:"restricted_purpose"`
+
+	// 关联。被引用的模型带有同名的业务编号字段（如 Project.ProjectID）时 gorm 会把多对一关联猜成一对一，
+	// 因此多对一关联都标明 belongsTo
+	Donor       *Donor       `json:"donor,omitempty" gorm:"foreignKey:DonorID;references:ID;belongsTo"`
+	Project     *Project     `json:"project,omitempty" gorm:"foreignKey:ProjectID;references:ID;belongsTo"`
+	Transaction *Transaction `json:"transaction,omitempty" gorm:"foreignKey:TransactionID;references:ID;belongsTo"`
 	Expenses    []Expense    `json:"expenses,omitempty"`
 }
 
@@ -74,19 +97,22 @@ type Expense struct {
 	FundID         *uint     `gorm:"not null" json:"fund_id"`
 	ProjectID      *uint     `json:"project_id"`
 	EmployeeID     *uint     `json:"employee_id"`
+	CreatedBy      *uint     `json:"created_by"` // 登记支出的用户；审批人与付款人不能是登记人
 	TransactionID  *uint     `json:"transaction_id"`
 	Description    string    `gorm:"not null" json:"description"`
 	Amount         float64   `gorm:"type:decimal(10,2);not null" json:"amount"`
 	ExpenseDate    time.Time `json:"expense_date"`
-	ApprovalStatus string    `gorm:"size:20;default:pending" json:"approval_status"`
+	ApprovalStatus string    `gorm:"size:20;default:draft" json:"approval_status"`
 	CreatedAt      time.Time `json:"created_at" gorm:"autoCreateTime"`
 	UpdatedAt      time.Time `json:"updated_at" gorm:"autoUpdateTime"`
 
+	SoftDelete
+
 	// 关联
-	Fund        *Fund        `json:"fund,omitempty" gorm:"foreignKey:FundID"`
-	Project     *Project     `json:"project,omitempty" gorm:"foreignKey:ProjectID"`
-	Employee    *Employee    `json:"employee,omitempty" gorm:"foreignKey:EmployeeID"`
-	Transaction *Transaction `json:"transaction,omitempty" gorm:"foreignKey:TransactionID"`
+	Fund        *Fund        `json:"fund,omitempty" gorm:"foreignKey:FundID;references:ID;belongsTo"`
+	Project     *Project     `json:"project,omitempty" gorm:"foreignKey:ProjectID;references:ID;belongsTo"`
+	Employee    *Employee    `json:"employee,omitempty" gorm:"foreignKey:EmployeeID;references:ID;belongsTo"`
+	Transaction *Transaction `json:"transaction,omitempty" gorm:"foreignKey:TransactionID;references:ID;belongsTo"`
 }
 
 // Purchase 采购表
@@ -101,6 +127,8 @@ type Purchase struct {
 	CreatedAt     time.Time  `json:"created_at" gorm:"autoCreateTime"`
 	UpdatedAt     time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
 
+	SoftDelete
+
 	// 关联
 	Transaction *Transaction `json:"transaction,omitempty"`
 	Inventory   []Inventory  `json:"inventory,omitempty"`
@@ -116,9 +144,11 @@ type Payroll struct {
 	CreatedAt     time.Time `json:"created_at" gorm:"autoCreateTime"`
 	UpdatedAt     time.Time `json:"updated_at" gorm:"autoUpdateTime"`
 
+	SoftDelete
+
 	// 关联
-	Transaction Transaction `json:"transaction,omitempty" gorm:"foreignKey:TransactionID"`
-	Employee    Employee    `json:"employee,omitempty" gorm:"foreignKey:EmployeeID"`
+	Transaction Transaction `json:"transaction,omitempty" gorm:"foreignKey:TransactionID;references:ID;belongsTo"`
+	Employee    Employee    `json:"employee,omitempty" gorm:"foreignKey:EmployeeID;references:ID;belongsTo"`
 }
 
 // Inventory 库存表
@@ -135,9 +165,11 @@ type Inventory struct {
 	CreatedAt    time.Time `json:"created_at" gorm:"autoCreateTime"`
 	UpdatedAt    time.Time `json:"updated_at" gorm:"autoUpdateTime"`
 
+	SoftDelete
+
 	// 关联
-	Purchase *Purchase `json:"purchase,omitempty" gorm:"foreignKey:PurchaseID"`
-	Location *Location `json:"location,omitempty" gorm:"foreignKey:LocationID"`
+	Purchase *Purchase `json:"purchase,omitempty" gorm:"foreignKey:PurchaseID;references:ID;belongsTo"`
+	Location *Location `json:"location,omitempty" gorm:"foreignKey:LocationID;references:ID;belongsTo"`
 	// InventoryTransactions []InventoryTransaction `json:"inventory_transactions,omitempty"`
 }
 
@@ -154,6 +186,8 @@ type GiftType struct {
 	// 关联
 	Gifts     []Gift     `json:"gifts,omitempty"`
 	Inventory *Inventory `json:"inventory,omitempty" gorm:"foreignKey:InventoryName;references:Name"`
+
+	SoftDelete
 }
 
 // Gift 礼品记录表
@@ -167,26 +201,71 @@ type Gift struct {
 	CreatedAt  time.Time `json:"created_at" gorm:"autoCreateTime"`
 	UpdatedAt  time.Time `json:"updated_at" gorm:"autoUpdateTime"`
 
+	SoftDelete
+
 	// 关联
-	Donation *Donation `json:"donation,omitempty" gorm:"foreignKey:DonationID"`
-	Delivery *Delivery `json:"delivery,omitempty" gorm:"foreignKey:DeliveryID"`
+	Donation *Donation `json:"donation,omitempty" gorm:"foreignKey:DonationID;references:ID;belongsTo"`
+	Delivery *Delivery `json:"delivery,omitempty" gorm:"foreignKey:DeliveryID;references:ID;belongsTo"`
 	GiftType GiftType  `json:"gift_type,omitempty" gorm:"foreignKey:GiftTypeID"`
 }
 
-// InventoryTransaction 库存交易记录表
+// 库存变动类型
+const (
+	InventoryMoveReceipt    = "receipt"    // 入库（到货、期初库存）
+	InventoryMoveTransfer   = "transfer"   // 调拨
+	InventoryMoveAdjustment = "adjustment" // 盘点调整、损耗
+	InventoryMoveDelivery   = "delivery"   // 配送出库
+	InventoryMoveDonation   = "donation"   // 实物捐赠入库
+)
+
+// 库存变动的来源（另见总账分录来源 Source*）
+const (
+	StockSourceDelivery       = "delivery_inventory" // 配送明细
+	StockSourceOpeningBalance = "opening_balance"    // 启用库存台账时按已有库存补记的期初调整
+)
+
+// InventoryTransaction 库存交易记录表：From 出库、To 入库，单边的入库或出库另一方为空，QuantityChange 为变动数量。
+// 由业务单据产生的变动记录来源单据；单据修改或删除时以一条方向相反的变动冲销，原记录保留
 type InventoryTransaction struct {
 	ID              uint      `gorm:"primaryKey" json:"id"`
-	ToInventoryID   *uint     `gorm:"not null" json:"to_inventory_id"`
-	FromInventoryID *uint     `gorm:"not null" json:"from_inventory_id"`
+	ToInventoryID   *uint     `json:"to_inventory_id"`
+	FromInventoryID *uint     `json:"from_inventory_id"`
 	TransactionType string    `gorm:"size:20;not null" json:"transaction_type"`
 	QuantityChange  int       `gorm:"not null" json:"quantity_change"`
 	TransactionDate time.Time `json:"transaction_date"`
+	SourceType      string    `gorm:"size:20;index:idx_inventory_transactions_source" json:"source_type"`
+	SourceID        *uint     `gorm:"index:idx_inventory_transactions_source" json:"source_id"`
+	UnitCost        float64   `gorm:"type:decimal(12,4);default:0" json:"unit_cost"` // 过账时确定的单位成本
+	PurchaseID      *uint     `json:"purchase_id"`                                   // 采购入库对应的采购
+	ReversalOfID    *uint     `json:"reversal_of_id"`
+	ReversedByID    *uint     `json:"reversed_by_id"`
 	CreatedAt       time.Time `json:"created_at" gorm:"autoCreateTime"`
 	UpdatedAt       time.Time `json:"updated_at" gorm:"autoUpdateTime"`
 
+	SoftDelete
+
 	// 关联
 	ToInventory   *Inventory `json:"to_inventory,omitempty" gorm:"foreignKey:ToInventoryID"`
 	FromInventory *Inventory `json:"from_inventory,omitempty" gorm:"foreignKey:FromInventoryID"`
+	Purchase      *Purchase  `json:"purchase,omitempty" gorm:"foreignKey:PurchaseID;references:ID;belongsTo"`
+}
+
+// 库存计价方法
+const (
+	CostMethodFIFO    = "fifo"    // 先进先出
+	CostMethodAverage = "average" // 移动加权平均
+)
+
+// DefaultCostMethod 未设置计价方法的类别使用的方法
+const DefaultCostMethod = CostMethodAverage
+
+// InventoryCostMethod 库存类别的计价方法表，按 Inventory.Category 设置
+type InventoryCostMethod struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Category  string    `gorm:"size:100;not null;unique" json:"category"`
+	Method    string    `gorm:"size:20;not null" json:"method"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"autoUpdateTime"`
 }
 
 // Delivery 配送表
@@ -197,12 +276,16 @@ type Delivery struct {
 	RecipientName    string     `gorm:"size:200" json:"recipient_name"`
 	RecipientContact string     `gorm:"size:100" json:"recipient_contact"`
 	LocationID       *uint      `json:"location_id"`
+	ProjectID        *uint      `json:"project_id"` // 配送所属项目，用于按项目统计配送成本
 	Address          string     `gorm:"size:300" json:"address"`
 	DeliveryDate     *time.Time `json:"delivery_date"`
 	Status           string     `gorm:"size:20;default:pending" json:"status"`
 	CreatedAt        time.Time  `json:"created_at" gorm:"autoCreateTime"`
 	UpdatedAt        time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
 
+	SoftDelete
+
 	// 关联
-	Location *Location `json:"location,omitempty" gorm:"foreignKey:LocationID"`
+	Location *Location `json:"location,omitempty" gorm:"foreignKey:LocationID;references:ID;belongsTo"`
+	Project  *Project  `json:"project,omitempty" gorm:"foreignKey:ProjectID;references:ID;belongsTo"`
 }
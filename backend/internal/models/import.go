@@ -0,0 +1,43 @@
+package models
+
+import "time"
+
+// 导入的提交方式
+const (
+	ImportModeAll   = "all"   // 单个事务：任一行失败则全部不写入
+	ImportModeBatch = "batch" // 按批提交：跳过失败的行，其余行每批一个事务写入
+)
+
+// ImportMapping 导入列映射模板表：保存某资源的文件列名到字段（数据库列名）的映射，按名称复用
+type ImportMapping struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Resource  string    `gorm:"size:50;not null;uniqueIndex:idx_import_mappings_resource_name" json:"resource"`
+	Name      string    `gorm:"size:100;not null;uniqueIndex:idx_import_mappings_resource_name" json:"name"`
+	Mapping   JSONText  `gorm:"type:text;not null" json:"mapping"` // {"文件列名": "字段"}，字段为空表示忽略该列
+	CreatedBy *uint     `json:"created_by"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// ImportError 导入失败的一行中的一个字段；Row 为文件中的行号（表头为第 1 行），Field 为空表示整行失败
+type ImportError struct {
+	Row     int    `json:"row"`
+	Field   string `json:"field,omitempty"`
+	Value   string `json:"value,omitempty"`
+	Message string `json:"message"`
+}
+
+// ImportJob 导入记录表：每次导入（含试运行）一行，保存统计与逐行错误，供下载错误报告
+type ImportJob struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Resource  string    `gorm:"size:50;not null;index" json:"resource"`
+	Filename  string    `gorm:"size:255" json:"filename"`
+	Mode      string    `gorm:"size:10;not null" json:"mode"`
+	DryRun    bool      `json:"dry_run"`
+	TotalRows int       `json:"total_rows"`
+	Imported  int       `json:"imported"` // 已写入（试运行时为可写入）的行数
+	Failed    int       `json:"failed"`
+	Errors    JSONText  `gorm:"type:text" json:"-"` // []ImportError
+	CreatedBy *uint     `json:"created_by"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
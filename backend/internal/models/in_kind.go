@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// 实物捐赠的估值依据
+const (
+	ValuationFairMarket    = "fair_market_value" // 同类物品的市场价
+	ValuationAppraisal     = "appraisal"         // 第三方评估，需注明评估人
+	ValuationDonorEstimate = "donor_estimate"    // 捐赠者申报
+)
+
+// InKindValuation 实物捐赠的估值记录：每笔实物捐赠一条，随捐赠修改而更新
+type InKindValuation struct {
+	ID                  uint      `gorm:"primaryKey" json:"id"`
+	DonationInventoryID uint      `gorm:"unique;not null" json:"donation_inventory_id"`
+	Basis               string    `gorm:"size:20;not null" json:"basis"`
+	UnitValue           float64   `gorm:"type:decimal(10,2);not null" json:"unit_value"`
+	TotalValue          float64   `gorm:"type:decimal(12,2);not null" json:"total_value"`
+	Appraiser           string    `gorm:"size:200" json:"appraiser"`
+	Notes               string    `json:"notes"`
+	ValuedBy            *uint     `json:"valued_by"`
+	ValuedAt            time.Time `gorm:"not null" json:"valued_at"`
+}
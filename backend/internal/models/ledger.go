@@ -0,0 +1,102 @@
+package models
+
+import "time"
+
+// 会计科目类型
+const (
+	AccountTypeAsset     = "asset"
+	AccountTypeLiability = "liability"
+	AccountTypeNetAssets = "net_assets"
+	AccountTypeRevenue   = "revenue"
+	AccountTypeExpense   = "expense"
+)
+
+// 系统过账使用的默认科目代码
+const (
+	AccountCash                = "1000"
+	AccountProjectCash         = "1010"
+	AccountInventory           = "1300"
+	AccountNetAssetsFree       = "3000"
+	AccountNetAssetsRestricted = "3100"
+	AccountContributions       = "4000"
+	AccountContributionsRestr  = "4100"
+	AccountContributionsInKind = "4200"
+	AccountProgramExpenses     = "5000"
+	AccountSalaries            = "5100"
+)
+
+// 日记账来源类型
+const (
+	SourceDonation    = "donation"
+	SourceExpense     = "expense"
+	SourcePurchase    = "purchase"
+	SourcePayroll     = "payroll"
+	SourceFundProject = "fund_project"
+	SourceInKind      = "in_kind"
+	SourceManual      = "manual"
+)
+
+// Account 会计科目表
+type Account struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Code      string    `gorm:"size:20;unique;not null" json:"code"`
+	Name      string    `gorm:"size:200;not null" json:"name"`
+	Type      string    `gorm:"size:20;not null" json:"type"`
+	ParentID  *uint     `json:"parent_id"`
+	IsActive  bool      `gorm:"default:true" json:"is_active"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// DebitNormal 资产与费用类科目余额在借方
+func (a Account) DebitNormal() bool {
+	return a.Type == AccountTypeAsset || a.Type == AccountTypeExpense
+}
+
+// JournalEntry 日记账分录（借贷必须平衡）
+type JournalEntry struct {
+	ID            uint      `gorm:"primaryKey" json:"id"`
+	TransactionID *uint     `json:"transaction_id"`
+	SourceType    string    `gorm:"size:20;not null;index:idx_journal_source" json:"source_type"`
+	SourceID      uint      `gorm:"index:idx_journal_source" json:"source_id"`
+	EntryDate     time.Time `gorm:"not null;index" json:"entry_date"`
+	Description   string    `json:"description"`
+	ReversalOfID  *uint     `json:"reversal_of_id"`
+	ReversedByID  *uint     `json:"reversed_by_id"`
+	CreatedAt     time.Time `json:"created_at" gorm:"autoCreateTime"`
+
+	// 关联
+	Transaction *Transaction  `json:"transaction,omitempty" gorm:"foreignKey:TransactionID;references:ID;belongsTo"`
+	Lines       []JournalLine `json:"lines,omitempty"`
+}
+
+// JournalLine 分录明细行，Debit 与 Credit 只有一个非零
+type JournalLine struct {
+	ID             uint    `gorm:"primaryKey" json:"id"`
+	JournalEntryID uint    `gorm:"not null;index" json:"journal_entry_id"`
+	AccountID      uint    `gorm:"not null;index" json:"account_id"`
+	FundID         *uint   `json:"fund_id"`
+	ProjectID      *uint   `json:"project_id"`
+	Debit          float64 `gorm:"type:decimal(12,2);default:0" json:"debit"`
+	Credit         float64 `gorm:"type:decimal(12,2);default:0" json:"credit"`
+	Memo           string  `json:"memo"`
+
+	// 关联
+	Account *Account `json:"account,omitempty" gorm:"foreignKey:AccountID"`
+}
+
+// DefaultChartOfAccounts 首次启动时写入的基础科目
+func DefaultChartOfAccounts() []Account {
+	return []Account{
+		{Code: AccountCash, Name: "Cash and Bank", Type: AccountTypeAsset},
+		{Code: AccountProjectCash, Name: "Cash Designated for Projects", Type: AccountTypeAsset},
+		{Code: AccountInventory, Name: "Inventory", Type: AccountTypeAsset},
+		{Code: AccountNetAssetsFree, Name: "Net Assets Without Donor Restrictions", Type: AccountTypeNetAssets},
+		{Code: AccountNetAssetsRestricted, Name: "Net Assets With Donor Restrictions", Type: AccountTypeNetAssets},
+		{Code: AccountContributions, Name: "Contributions Without Donor Restrictions", Type: AccountTypeRevenue},
+		{Code: AccountContributionsRestr, Name: "Contributions With Donor Restrictions", Type: AccountTypeRevenue},
+		{Code: AccountContributionsInKind, Name: "In-Kind Contributions", Type: AccountTypeRevenue},
+		{Code: AccountProgramExpenses, Name: "Program Expenses", Type: AccountTypeExpense},
+		{Code: AccountSalaries, Name: "Salaries and Wages", Type: AccountTypeExpense},
+	}
+}
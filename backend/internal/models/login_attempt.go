@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+// 登录失败原因
+const (
+	LoginFailedPassword = "bad_password"
+	LoginUnknownUser    = "unknown_user"
+	LoginAccountLocked  = "locked"
+	LoginIPBlocked      = "ip_blocked"
+	LoginInactive       = "inactive"
+	LoginBadTwoFactor   = "bad_2fa_code"
+)
+
+// CredentialFailureReasons 凭据错误类的失败原因，计入来源 IP 的失败次数；
+// 锁定拦截（locked、ip_blocked）与密码正确后的拒绝（inactive）不计入
+var CredentialFailureReasons = []string{LoginFailedPassword, LoginUnknownUser, LoginBadTwoFactor}
+
+// LoginAttempt 登录尝试记录表：每次登录请求一条（只追加），供安全审查可疑活动
+type LoginAttempt struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Username  string    `gorm:"size:100;index" json:"username"`
+	UserID    *uint     `gorm:"index" json:"user_id"` // 用户名不存在时为空
+	ClientIP  string    `gorm:"size:64;index" json:"client_ip"`
+	UserAgent string    `gorm:"size:255" json:"user_agent"`
+	Success   bool      `json:"success"`
+	Reason    string    `gorm:"size:30" json:"reason,omitempty"`
+	CreatedAt time.Time `gorm:"index" json:"created_at"`
+}
+
+// AccountLockout 账号锁定状态表：连续失败次数达到阈值后锁定，
+// 每次锁定的时长按 LockCount 指数增长，登录成功或管理员解锁后清零
+type AccountLockout struct {
+	UserID      uint       `gorm:"primaryKey;autoIncrement:false" json:"user_id"`
+	FailedCount int        `json:"failed_count"` // 上次锁定（或成功登录）以来的连续失败次数
+	LockCount   int        `json:"lock_count"`
+	LockedUntil *time.Time `json:"locked_until"`
+	UpdatedAt   time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
+
+	User *User `json:"user,omitempty" gorm:"foreignKey:UserID"`
+}
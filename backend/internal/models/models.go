@@ -4,10 +4,12 @@ import "time"
 
 // Pagination represents pagination metadata
 type Pagination struct {
-	Page       int   `json:"page"`
-	PageSize   int   `json:"page_size"`
-	TotalPages int   `json:"total_pages"`
-	TotalItems int64 `json:"total_items"`
+	Page       int    `json:"page"` // 游标分页时为 0
+	PageSize   int    `json:"page_size"`
+	TotalPages int    `json:"total_pages"`
+	TotalItems int64  `json:"total_items"`
+	HasMore    bool   `json:"has_more"`
+	NextCursor string `json:"next_cursor,omitempty"` // 游标分页时取下一页用
 }
 
 // APIResponse represents a standard API response
@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// PasswordResetToken 密码重置令牌表：只保存令牌的 SHA-256 哈希。
+// 令牌只能使用一次，过期或签发新令牌后作废
+type PasswordResetToken struct {
+	ID        uint       `gorm:"primaryKey" json:"id"`
+	UserID    uint       `gorm:"index;not null" json:"user_id"`
+	TokenHash string     `gorm:"size:64;uniqueIndex;not null" json:"-"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at"`
+	CreatedAt time.Time  `json:"created_at" gorm:"autoCreateTime"`
+}
@@ -0,0 +1,132 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// 内置角色
+const (
+	RoleAdmin          = "admin"
+	RoleFinanceAdmin   = "finance-admin"
+	RoleHR             = "hr"
+	RoleWarehouse      = "warehouse"
+	RoleProjectManager = "project-manager"
+	RoleStaff          = "staff"
+)
+
+// 通用操作；资源或操作为 * 表示全部
+const (
+	ActionRead         = "read"
+	ActionCreate       = "create"
+	ActionUpdate       = "update"
+	ActionDelete       = "delete"
+	PermissionWildcard = "*"
+)
+
+// 全机构数据范围：拥有此权限的角色可访问所有项目的数据，否则只能访问所参与项目的数据
+const (
+	ResourceDataScope = "data-scope"
+	ActionOrgWide     = "org-wide"
+)
+
+// 回收站：<资源>:restore 恢复已删除的记录；彻底删除需要 trash:purge，删除时强制级联（?force=cascade）
+// 需要 trash:force-delete，内置角色中只有 admin 拥有这两项
+const (
+	ResourceTrash     = "trash"
+	ActionRestore     = "restore"
+	ActionPurge       = "purge"
+	ActionForceDelete = "force-delete"
+)
+
+// 支出：填报人固定为登记支出的员工本人；持有 expenses:file-on-behalf 的角色可代其他员工填报
+const ActionFileOnBehalf = "file-on-behalf"
+
+// 个人信息：没有 pii:read 的调用者导出数据时，联系方式、地址与薪资等列被遮盖
+const ResourcePII = "pii"
+
+// Permission 权限表：某资源上的某操作，如 expenses:approve
+type Permission struct {
+	ID          uint   `gorm:"primaryKey" json:"id"`
+	Resource    string `gorm:"size:50;not null;uniqueIndex:idx_permission" json:"resource"`
+	Action      string `gorm:"size:50;not null;uniqueIndex:idx_permission" json:"action"`
+	Description string `json:"description"`
+}
+
+// Code 返回 resource:action 形式的权限代码
+func (p Permission) Code() string {
+	return p.Resource + ":" + p.Action
+}
+
+// ParsePermissionCode 解析 resource:action 形式的权限代码
+func ParsePermissionCode(code string) (resource, action string, ok bool) {
+	resource, action, ok = strings.Cut(strings.TrimSpace(code), ":")
+	if !ok || resource == "" || action == "" {
+		return "", "", false
+	}
+	return resource, action, true
+}
+
+// Role 角色表
+type Role struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	Name        string    `gorm:"size:50;unique;not null" json:"name"`
+	Description string    `json:"description"`
+	IsSystem    bool      `gorm:"default:false" json:"is_system"`
+	CreatedAt   time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt   time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+
+	// 成员登录时必须使用双因素认证
+	RequireTwoFactor bool `gorm:"default:false" json:"require_two_factor"`
+
+	// 关联
+	Permissions []Permission `json:"permissions,omitempty" gorm:"many2many:role_permissions"`
+}
+
+// UserRole 用户-角色关联表
+type UserRole struct {
+	UserID    uint      `gorm:"primaryKey" json:"user_id"`
+	RoleID    uint      `gorm:"primaryKey" json:"role_id"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+
+	Role *Role `json:"role,omitempty" gorm:"foreignKey:RoleID"`
+}
+
+// DefaultRole 内置角色及其权限代码
+type DefaultRole struct {
+	Name        string
+	Description string
+	Permissions []string
+}
+
+// expenseFiler 普通员工填报支出所需的权限
+var expenseFiler = []string{"expenses:read", "expenses:create", "expenses:update", "expenses:submit"}
+
+// DefaultRoles 返回首次启动时写入的内置角色
+func DefaultRoles() []DefaultRole {
+	return []DefaultRole{
+		{RoleAdmin, "Full access to every resource", []string{"*:*"}},
+		{RoleFinanceAdmin, "Donations, funds, expenses, payroll and the general ledger", []string{
+			"donations:*", "funds:*", "expenses:*", "transactions:*", "purchases:*", "payrolls:*",
+			"fund-projects:*", "ledger:*", "approval-thresholds:*", "receipts:*", "charts:read", "data-scope:org-wide",
+			"donors:read", "projects:read", "employees:read", "gifts:read", "gift-types:read",
+		}},
+		{RoleHR, "Employees, volunteers, schedules and payroll", append([]string{
+			"employees:*", "volunteers:*", "volunteer-projects:*", "employee-projects:*", "schedules:*",
+			"payrolls:*", "users:read", "users:update", "projects:read", "locations:read", "data-scope:org-wide",
+			"pii:read",
+		}, expenseFiler...)},
+		{RoleWarehouse, "Inventory, gifts and deliveries", append([]string{
+			"inventory:*", "inventory-transactions:*", "deliveries:*", "delivery-inventory:*",
+			"donation-inventory:*", "gift-types:*", "gifts:*", "purchases:read", "locations:read", "projects:read",
+		}, expenseFiler...)},
+		{RoleProjectManager, "Projects and their staffing, schedules and spending", append([]string{
+			"projects:*", "volunteer-projects:*", "employee-projects:*", "schedules:*",
+			"volunteers:read", "employees:read", "funds:read", "fund-projects:read", "deliveries:read",
+			"locations:read", "charts:read", "expenses:approve",
+		}, expenseFiler...)},
+		{RoleStaff, "Default role for employees: read reference data and file expenses", append([]string{
+			"projects:read", "locations:read", "schedules:read", "gift-types:read",
+		}, expenseFiler...)},
+	}
+}
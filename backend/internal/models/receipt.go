@@ -0,0 +1,71 @@
+package models
+
+import "time"
+
+// 年度捐赠收据状态
+const (
+	ReceiptStatusIssued = "issued" // 有效
+	ReceiptStatusVoid   = "void"   // 已作废；重开时被新收据替换
+)
+
+// ReceiptOrganisation 收据上的机构信息，取自配置
+type ReceiptOrganisation struct {
+	Name      string `json:"name"`
+	Address   string `json:"address"`
+	TaxID     string `json:"tax_id"` // 慈善登记号或税号
+	Contact   string `json:"contact"`
+	Signatory string `json:"signatory"` // 签发人
+}
+
+// ReceiptGift 收据上的一笔捐款
+type ReceiptGift struct {
+	DonationID string    `json:"donation_id"`
+	Date       time.Time `json:"date"`
+	Amount     float64   `json:"amount"`
+	Method     string    `json:"method"`
+	Project    string    `json:"project"`
+}
+
+// ReceiptItem 收据上的一项实物捐赠，Value 为该项的估值合计
+type ReceiptItem struct {
+	Name     string     `json:"name"`
+	Date     *time.Time `json:"date"`
+	Quantity int        `json:"quantity"`
+	Value    float64    `json:"value"`
+}
+
+// ReceiptContent 收据开具时的内容快照；之后捐赠记录变动不影响已开具的收据，需作废重开
+type ReceiptContent struct {
+	Organisation ReceiptOrganisation `json:"organisation"`
+	DonorCode    string              `json:"donor_code"`
+	DonorName    string              `json:"donor_name"`
+	DonorAddress string              `json:"donor_address"`
+	Gifts        []ReceiptGift       `json:"gifts"`
+	Items        []ReceiptItem       `json:"items"`
+}
+
+// TaxReceipt 年度捐赠收据表：每位捐赠者每年至多一张有效收据。收据号按年连续编号，
+// 作废的收据保留，重开的收据通过 ReplacesID 与被替换的收据相连
+type TaxReceipt struct {
+	ID            uint       `gorm:"primaryKey" json:"id"`
+	ReceiptNumber string     `gorm:"size:30;unique;not null" json:"receipt_number"` // <年份>-<6 位序号>，如 2025-000001
+	Year          int        `gorm:"not null;index:idx_tax_receipts_donor_year" json:"year"`
+	DonorID       uint       `gorm:"not null;index:idx_tax_receipts_donor_year" json:"donor_id"`
+	Status        string     `gorm:"size:10;not null" json:"status"`
+	CashTotal     float64    `gorm:"type:decimal(12,2)" json:"cash_total"`
+	InKindTotal   float64    `gorm:"type:decimal(12,2)" json:"in_kind_total"`
+	Content       JSONText   `gorm:"type:text;not null" json:"-"` // ReceiptContent
+	ReplacesID    *uint      `json:"replaces_id"`
+	ReplacedByID  *uint      `json:"replaced_by_id"`
+	IssuedBy      *uint      `json:"issued_by"`
+	IssuedAt      time.Time  `gorm:"not null" json:"issued_at"`
+	VoidedBy      *uint      `json:"voided_by"`
+	VoidedAt      *time.Time `json:"voided_at"`
+	VoidReason    string     `json:"void_reason"`
+}
+
+// ReceiptSequence 收据号计数表：每年一行，记录已使用的最大序号
+type ReceiptSequence struct {
+	Year       int `gorm:"primaryKey;autoIncrement:false" json:"year"`
+	LastNumber int `gorm:"not null" json:"last_number"`
+}
@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// 员工注册审核结果
+const (
+	RegistrationApproved = "approved"
+	RegistrationRejected = "rejected"
+)
+
+// RegistrationReview 员工注册审核记录表：管理员对待审核员工账号的批准或拒绝（只追加不修改）
+type RegistrationReview struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	UserID     uint      `gorm:"index;not null" json:"user_id"`
+	EmployeeID uint      `gorm:"index;not null" json:"employee_id"`
+	Decision   string    `gorm:"size:20;not null" json:"decision"`
+	Reason     string    `json:"reason"`
+	Department string    `gorm:"size:100" json:"department,omitempty"`
+	Position   string    `gorm:"size:100" json:"position,omitempty"`
+	ReviewedBy uint      `json:"reviewed_by"` // 审核人的 user_id
+	CreatedAt  time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
@@ -17,9 +17,11 @@ type VolunteerProject struct {
 	Status         string     `gorm:"size:20;default:active" json:"status"`
 	CreatedAt      time.Time  `json:"created_at"`
 
+	SoftDelete
+
 	// 关联
-	Volunteer *Volunteer `json:"volunteer,omitempty" gorm:"foreignKey:VolunteerID"`
-	Project   *Project   `json:"project,omitempty" gorm:"foreignKey:ProjectID"`
+	Volunteer *Volunteer `json:"volunteer,omitempty" gorm:"foreignKey:VolunteerID;references:ID;belongsTo"`
+	Project   *Project   `json:"project,omitempty" gorm:"foreignKey:ProjectID;references:ID;belongsTo"`
 }
 
 // EmployeeProject 员工-项目关联表
@@ -35,9 +37,11 @@ type EmployeeProject struct {
 	LastUpdated     time.Time  `json:"last_updated"`
 	CreatedAt       time.Time  `json:"created_at"`
 
+	SoftDelete
+
 	// 关联
-	Employee *Employee `json:"employee,omitempty" gorm:"foreignKey:EmployeeID"`
-	Project  *Project  `json:"project,omitempty" gorm:"foreignKey:ProjectID"`
+	Employee *Employee `json:"employee,omitempty" gorm:"foreignKey:EmployeeID;references:ID;belongsTo"`
+	Project  *Project  `json:"project,omitempty" gorm:"foreignKey:ProjectID;references:ID;belongsTo"`
 }
 
 // FundProject 资金-项目关联表
@@ -51,10 +55,12 @@ type FundProject struct {
 	Purpose         string    `json:"purpose"`
 	CreatedAt       time.Time `json:"created_at"`
 
+	SoftDelete
+
 	// 关联
-	Transaction *Transaction `json:"transaction,omitempty" gorm:"foreignKey:TransactionID"`
-	Project     *Project     `json:"project,omitempty" gorm:"foreignKey:ProjectID"`
-	Fund        *Fund        `json:"fund,omitempty" gorm:"foreignKey:FundID"`
+	Transaction *Transaction `json:"transaction,omitempty" gorm:"foreignKey:TransactionID;references:ID;belongsTo"`
+	Project     *Project     `json:"project,omitempty" gorm:"foreignKey:ProjectID;references:ID;belongsTo"`
+	Fund        *Fund        `json:"fund,omitempty" gorm:"foreignKey:FundID;references:ID;belongsTo"`
 }
 
 // DonationInventory 捐赠-库存关联表（实物捐赠）
@@ -68,10 +74,13 @@ type DonationInventory struct {
 	EstimatedValue float64    `gorm:"type:decimal(10,2)" json:"estimated_value"`
 	CreatedAt      time.Time  `json:"created_at"`
 
+	SoftDelete
+
 	// 关联
-	Donor     *Donor     `json:"donor,omitempty" gorm:"foreignKey:DonorID"`
-	Inventory *Inventory `json:"inventory,omitempty" gorm:"foreignKey:InventoryID"`
-	Project   *Project   `json:"project,omitempty" gorm:"foreignKey:ProjectID"`
+	Donor     *Donor           `json:"donor,omitempty" gorm:"foreignKey:DonorID;references:ID;belongsTo"`
+	Inventory *Inventory       `json:"inventory,omitempty" gorm:"foreignKey:InventoryID;references:ID;belongsTo"`
+	Project   *Project         `json:"project,omitempty" gorm:"foreignKey:ProjectID;references:ID;belongsTo"`
+	Valuation *InKindValuation `json:"valuation,omitempty" gorm:"foreignKey:DonationInventoryID"`
 }
 
 // DeliveryInventory 用于跟踪捐赠物品的交付情况
@@ -80,13 +89,15 @@ type DeliveryInventory struct {
 	DeliveryID  *uint     `gorm:"not null" json:"delivery_id"`
 	InventoryID *uint     `gorm:"not null" json:"inventory_id"`
 	Quantity    int       `gorm:"default:1" json:"quantity"`
-	UnitCost    float64   `gorm:"type:decimal(8,2)" json:"unit_cost"`
+	UnitCost    float64   `gorm:"type:decimal(8,2)" json:"unit_cost"` // 出库时按计价方法计算，不接受手工填写
 	CreatedAt   time.Time `json:"created_at" gorm:"autoCreateTime"`
 	UpdatedAt   time.Time `json:"updated_at" gorm:"autoUpdateTime"`
 
+	SoftDelete
+
 	// 关联
-	Delivery  *Delivery  `json:"delivery,omitempty" gorm:"foreignKey:DeliveryID"`
-	Inventory *Inventory `json:"inventory,omitempty" gorm:"foreignKey:InventoryID"`
+	Delivery  *Delivery  `json:"delivery,omitempty" gorm:"foreignKey:DeliveryID;references:ID;belongsTo"`
+	Inventory *Inventory `json:"inventory,omitempty" gorm:"foreignKey:InventoryID;references:ID;belongsTo"`
 }
 
 // Schedule 调度表
@@ -104,6 +115,8 @@ type Schedule struct {
 	Notes       string    `json:"notes"`
 	CreatedAt   time.Time `json:"created_at"`
 
+	SoftDelete
+
 	// 关联
 	Project *Project `json:"project,omitempty"`
 }
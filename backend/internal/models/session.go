@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+// 会话被终止的原因
+const (
+	SessionRevokedLogout         = "logout"
+	SessionRevokedAdmin          = "admin"
+	SessionRevokedReuse          = "refresh_token_reuse"
+	SessionRevokedPasswordChange = "password_change"
+	SessionRevokedPasswordReset  = "password_reset"
+)
+
+// Session 登录会话表：每次登录一条记录。访问令牌通过 sid 关联会话，
+// 会话被注销或终止后其访问令牌立即失效，刷新令牌也不能再使用
+type Session struct {
+	ID           uint       `gorm:"primaryKey" json:"id"`
+	SessionID    string     `gorm:"size:64;uniqueIndex;not null" json:"session_id"`
+	UserID       uint       `gorm:"index;not null" json:"user_id"`
+	UserAgent    string     `gorm:"size:255" json:"user_agent"`
+	ClientIP     string     `gorm:"size:64" json:"client_ip"`
+	ExpiresAt    time.Time  `json:"expires_at"` // 当前刷新令牌的过期时间
+	LastUsedAt   time.Time  `json:"last_used_at"`
+	RevokedAt    *time.Time `json:"revoked_at"`
+	RevokeReason string     `gorm:"size:30" json:"revoke_reason,omitempty"`
+	CreatedAt    time.Time  `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// RefreshToken 刷新令牌表：只保存令牌的 SHA-256 哈希。
+// 每个令牌只能使用一次，使用后签发新令牌；已使用的令牌再次出现说明令牌被盗用，整个会话随即终止
+type RefreshToken struct {
+	ID        uint       `gorm:"primaryKey" json:"id"`
+	SessionID uint       `gorm:"index;not null" json:"session_id"`
+	TokenHash string     `gorm:"size:64;uniqueIndex;not null" json:"-"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at"`
+	CreatedAt time.Time  `json:"created_at" gorm:"autoCreateTime"`
+}
@@ -0,0 +1,9 @@
+package models
+
+import "gorm.io/gorm"
+
+// SoftDelete 软删除字段，嵌入各 ERP 模型：删除只记录时间与操作人，默认查询自动排除已删除的行
+type SoftDelete struct {
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+	DeletedBy *uint          `json:"deleted_by"`
+}
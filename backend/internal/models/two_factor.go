@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// TwoFactor 双因素认证表：每个账号一条。登记后须用一次验证码确认才会启用
+type TwoFactor struct {
+	UserID       uint       `gorm:"primaryKey;autoIncrement:false" json:"user_id"`
+	Secret       string     `gorm:"not null" json:"-"` // 以 ENCRYPT_SEED 派生的密钥 AES-GCM 加密的 TOTP 密钥
+	Enabled      bool       `gorm:"default:false" json:"enabled"`
+	ConfirmedAt  *time.Time `json:"confirmed_at"`
+	LastUsedStep int64      `json:"-"` // 最近一次通过验证的时间步，同一验证码不能重复使用
+	CreatedAt    time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt    time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// RecoveryCode 一次性恢复码表：只保存 SHA-256 哈希，丢失身份验证器时代替验证码使用
+type RecoveryCode struct {
+	ID        uint       `gorm:"primaryKey" json:"id"`
+	UserID    uint       `gorm:"index;not null" json:"user_id"`
+	CodeHash  string     `gorm:"size:64;not null" json:"-"`
+	UsedAt    *time.Time `json:"used_at"`
+	CreatedAt time.Time  `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// LoginChallenge 两步登录的挑战令牌表：密码验证通过后签发，提交第二因素时换取访问令牌
+type LoginChallenge struct {
+	ID        uint       `gorm:"primaryKey" json:"id"`
+	TokenHash string     `gorm:"size:64;uniqueIndex;not null" json:"-"`
+	UserID    uint       `gorm:"index;not null" json:"user_id"`
+	Attempts  int        `json:"attempts"` // 已提交的错误验证码次数
+	ExpiresAt time.Time  `json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at"`
+	CreatedAt time.Time  `json:"created_at" gorm:"autoCreateTime"`
+}
@@ -0,0 +1,62 @@
+package repo
+
+import (
+	"erp-backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// ApprovalRepository 支出审批记录与审批额度
+type ApprovalRepository struct {
+	db *gorm.DB
+}
+
+func NewApprovalRepository(db *gorm.DB) *ApprovalRepository {
+	return &ApprovalRepository{db: db}
+}
+
+// CreateApproval 追加一条审批记录
+func (r *ApprovalRepository) CreateApproval(approval *models.ExpenseApproval) error {
+	return r.db.Create(approval).Error
+}
+
+// GetApprovals 按时间顺序返回某笔支出的审批记录
+func (r *ApprovalRepository) GetApprovals(expenseID uint) ([]models.ExpenseApproval, error) {
+	var approvals []models.ExpenseApproval
+	err := r.db.Preload("Employee").Where("expense_id = ?", expenseID).
+		Order("created_at, id").Find(&approvals).Error
+	return approvals, err
+}
+
+func (r *ApprovalRepository) GetThresholds() ([]models.ApprovalThreshold, error) {
+	var thresholds []models.ApprovalThreshold
+	err := r.db.Order("department, position").Find(&thresholds).Error
+	return thresholds, err
+}
+
+// ThresholdsForPosition 返回某职位（不区分大小写）的所有审批额度
+func (r *ApprovalRepository) ThresholdsForPosition(position string) ([]models.ApprovalThreshold, error) {
+	var thresholds []models.ApprovalThreshold
+	err := r.db.Where("lower(position) = lower(?)", position).Find(&thresholds).Error
+	return thresholds, err
+}
+
+// SaveThreshold 按 部门+职位 新建或更新审批额度
+func (r *ApprovalRepository) SaveThreshold(threshold *models.ApprovalThreshold) error {
+	var existing models.ApprovalThreshold
+	err := r.db.Where("department = ? AND position = ?", threshold.Department, threshold.Position).
+		First(&existing).Error
+	if err == nil {
+		threshold.ID = existing.ID
+		threshold.CreatedAt = existing.CreatedAt
+		return r.db.Save(threshold).Error
+	}
+	if err != gorm.ErrRecordNotFound {
+		return err
+	}
+	return r.db.Create(threshold).Error
+}
+
+func (r *ApprovalRepository) DeleteThreshold(id uint) error {
+	return r.db.Delete(&models.ApprovalThreshold{}, id).Error
+}
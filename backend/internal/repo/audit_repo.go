@@ -0,0 +1,398 @@
+package repo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"erp-backend/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrAuditAppendOnly 审计日志只能追加
+var ErrAuditAppendOnly = errors.New("audit log is append-only")
+
+const (
+	auditTable     = "audit_logs"
+	auditBeforeKey = "audit:before"
+	auditRedacted  = "[redacted]"
+)
+
+// auditSkipTables 不记录审计的表：会话、登录记录与认证密钥等高频或敏感的安全数据
+var auditSkipTables = map[string]bool{
+	auditTable:              true,
+	"sessions":              true,
+	"refresh_tokens":        true,
+	"password_reset_tokens": true,
+	"login_attempts":        true,
+	"account_lockouts":      true,
+	"two_factors":           true,
+	"recovery_codes":        true,
+	"login_challenges":      true,
+}
+
+// auditRedactedColumns 只记录"已修改"而不记录值的列
+var auditRedactedColumns = map[string]bool{
+	"password_hash": true,
+	"secret":        true,
+	"token_hash":    true,
+	"code_hash":     true,
+}
+
+// AuditActor 发起修改的用户，由认证中间件放入请求的 context
+type AuditActor struct {
+	UserID   uint
+	Username string
+	ClientIP string
+}
+
+type auditActorKey struct{}
+
+// WithAuditActor 返回携带操作人的 context；仓储通过 WithContext 使用后，写操作的审计记录归属于该用户
+func WithAuditActor(ctx context.Context, actor AuditActor) context.Context {
+	return context.WithValue(ctx, auditActorKey{}, actor)
+}
+
+// RegisterAuditCallbacks 注册 GORM 回调：每次 Create/Update/Delete 在同一事务中写入审计日志。
+// 修改前的数据在执行前按相同条件读出，修改后的数据按主键重新读取。
+// 直接执行的原生 SQL（Exec）不经过回调，不会被记录。
+func RegisterAuditCallbacks(db *gorm.DB) error {
+	cb := db.Callback()
+	if err := cb.Create().After("gorm:create").Register("audit:after_create", auditAfter(models.AuditCreate)); err != nil {
+		return err
+	}
+	if err := cb.Update().Before("gorm:update").Register("audit:before_update", auditBefore); err != nil {
+		return err
+	}
+	if err := cb.Update().After("gorm:update").Register("audit:after_update", auditAfter(models.AuditUpdate)); err != nil {
+		return err
+	}
+	if err := cb.Delete().Before("gorm:delete").Register("audit:before_delete", auditBefore); err != nil {
+		return err
+	}
+	return cb.Delete().After("gorm:delete").Register("audit:after_delete", auditAfter(models.AuditDelete))
+}
+
+func audited(db *gorm.DB) bool {
+	s := db.Statement
+	return db.Error == nil && s.Schema != nil && len(s.Schema.PrimaryFields) > 0 && !auditSkipTables[s.Table]
+}
+
+// auditBefore 读出将被修改或删除的行
+func auditBefore(db *gorm.DB) {
+	if db.Statement.Table == auditTable {
+		db.AddError(ErrAuditAppendOnly)
+		return
+	}
+	if !audited(db) {
+		return
+	}
+	q, ok := auditTarget(db)
+	if !ok {
+		return
+	}
+	rows, err := findRows(db.Statement, q)
+	if err != nil {
+		db.AddError(err)
+		return
+	}
+	db.InstanceSet(auditBeforeKey, rows)
+}
+
+// findRows 以模型类型读取查询结果，并转换为 列名→值
+func findRows(s *gorm.Statement, q *gorm.DB) ([]map[string]interface{}, error) {
+	list := reflect.New(reflect.SliceOf(s.Schema.ModelType))
+	if err := q.Find(list.Interface()).Error; err != nil {
+		return nil, err
+	}
+	rows := make([]map[string]interface{}, 0, list.Elem().Len())
+	for i := 0; i < list.Elem().Len(); i++ {
+		rows = append(rows, rowValues(s, list.Elem().Index(i)))
+	}
+	return rows, nil
+}
+
+// auditTarget 以语句的 WHERE 条件与模型上的主键构造查询；没有任何条件时返回 false
+func auditTarget(db *gorm.DB) (*gorm.DB, bool) {
+	s := db.Statement
+	q := db.Session(&gorm.Session{NewDB: true, SkipHooks: true}).Unscoped().
+		Model(reflect.New(s.Schema.ModelType).Interface()).Table(s.Table)
+	conds := 0
+	if c, ok := s.Clauses["WHERE"]; ok {
+		if where, ok := c.Expression.(clause.Where); ok && len(where.Exprs) > 0 {
+			q = q.Clauses(where)
+			conds++
+		}
+	}
+	switch s.ReflectValue.Kind() {
+	case reflect.Struct:
+		for _, f := range s.Schema.PrimaryFields {
+			if v, zero := f.ValueOf(s.Context, s.ReflectValue); !zero {
+				q = q.Where(clause.Eq{Column: clause.Column{Table: s.Table, Name: f.DBName}, Value: v})
+				conds++
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		if len(s.Schema.PrimaryFields) == 1 {
+			f := s.Schema.PrimaryFields[0]
+			var ids []interface{}
+			for i := 0; i < s.ReflectValue.Len(); i++ {
+				if v, zero := f.ValueOf(s.Context, reflect.Indirect(s.ReflectValue.Index(i))); !zero {
+					ids = append(ids, v)
+				}
+			}
+			if len(ids) > 0 {
+				q = q.Where(clause.IN{Column: clause.Column{Table: s.Table, Name: f.DBName}, Values: ids})
+				conds++
+			}
+		}
+	}
+	return q, conds > 0
+}
+
+// auditAfter 语句成功后生成审计记录，与业务写入处于同一事务
+func auditAfter(action string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		if !audited(db) || db.Statement.RowsAffected == 0 {
+			return
+		}
+		var entries []models.AuditLog
+		switch action {
+		case models.AuditCreate:
+			for _, row := range createdRows(db.Statement) {
+				entries = append(entries, auditEntry(db, action, row, nil, row))
+			}
+		case models.AuditUpdate, models.AuditDelete:
+			v, _ := db.InstanceGet(auditBeforeKey)
+			before, _ := v.([]map[string]interface{})
+			for _, old := range before {
+				var now map[string]interface{}
+				if action == models.AuditUpdate {
+					var err error
+					if now, err = reloadRow(db, old); err != nil {
+						db.AddError(err)
+						return
+					}
+					if now == nil {
+						continue
+					}
+				}
+				if entry := auditEntry(db, action, old, old, now); entry.Changes != "" {
+					entries = append(entries, entry)
+				}
+			}
+		}
+		if len(entries) == 0 {
+			return
+		}
+		if err := db.Session(&gorm.Session{NewDB: true, SkipHooks: true}).Create(&entries).Error; err != nil {
+			db.AddError(fmt.Errorf("failed to write audit log: %w", err))
+		}
+	}
+}
+
+// createdRows 返回新建记录各列的值（通过 map 创建的记录不记录）
+func createdRows(s *gorm.Statement) []map[string]interface{} {
+	var values []reflect.Value
+	switch s.ReflectValue.Kind() {
+	case reflect.Struct:
+		values = append(values, s.ReflectValue)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < s.ReflectValue.Len(); i++ {
+			values = append(values, reflect.Indirect(s.ReflectValue.Index(i)))
+		}
+	}
+	rows := make([]map[string]interface{}, 0, len(values))
+	for _, rv := range values {
+		if rv.Kind() == reflect.Struct {
+			rows = append(rows, rowValues(s, rv))
+		}
+	}
+	return rows
+}
+
+// rowValues 取出模型各数据库列的值（不含关联）
+func rowValues(s *gorm.Statement, rv reflect.Value) map[string]interface{} {
+	row := map[string]interface{}{}
+	for _, f := range s.Schema.Fields {
+		if f.DBName == "" {
+			continue
+		}
+		v, _ := f.ValueOf(s.Context, rv)
+		row[f.DBName] = v
+	}
+	return row
+}
+
+// reloadRow 按主键读取修改后的行；行已不存在时返回 nil
+func reloadRow(db *gorm.DB, old map[string]interface{}) (map[string]interface{}, error) {
+	s := db.Statement
+	q := db.Session(&gorm.Session{NewDB: true, SkipHooks: true}).Unscoped().
+		Model(reflect.New(s.Schema.ModelType).Interface()).Table(s.Table)
+	for _, f := range s.Schema.PrimaryFields {
+		q = q.Where(clause.Eq{Column: clause.Column{Table: s.Table, Name: f.DBName}, Value: old[f.DBName]})
+	}
+	rows, err := findRows(s, q.Limit(1))
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+	return rows[0], nil
+}
+
+// auditEntry 组装审计记录；Changes 只包含新旧值不同的列
+func auditEntry(db *gorm.DB, action string, keyRow, old, now map[string]interface{}) models.AuditLog {
+	s := db.Statement
+	ids := make([]string, 0, len(s.Schema.PrimaryFields))
+	for _, f := range s.Schema.PrimaryFields {
+		ids = append(ids, fmt.Sprint(auditValue(keyRow[f.DBName])))
+	}
+
+	changes := map[string]map[string]interface{}{}
+	for col := range unionKeys(old, now) {
+		o, n := auditValue(old[col]), auditValue(now[col])
+		ob, _ := json.Marshal(o)
+		nb, _ := json.Marshal(n)
+		if action == models.AuditUpdate && string(ob) == string(nb) {
+			continue
+		}
+		if auditRedactedColumns[col] {
+			o, n = redact(o), redact(n)
+		}
+		change := map[string]interface{}{}
+		if old != nil {
+			change["old"] = o
+		}
+		if now != nil {
+			change["new"] = n
+		}
+		changes[col] = change
+	}
+
+	// 只刷新了 updated_at 的保存不记录
+	if _, touched := changes["updated_at"]; action == models.AuditUpdate && touched && len(changes) == 1 {
+		delete(changes, "updated_at")
+	}
+
+	entry := models.AuditLog{
+		Action:   softDeleteAction(s, action, changes),
+		Table:    s.Table,
+		RecordID: strings.Join(ids, ","),
+	}
+	if len(changes) > 0 {
+		b, _ := json.Marshal(changes)
+		entry.Changes = models.JSONText(b)
+	}
+	if actor, ok := s.Context.Value(auditActorKey{}).(AuditActor); ok {
+		if actor.UserID != 0 {
+			id := actor.UserID
+			entry.UserID = &id
+		}
+		entry.Username, entry.ClientIP = actor.Username, actor.ClientIP
+	}
+	return entry
+}
+
+// softDeleteAction 区分软删除相关的写入：设置 deleted_at 记为 delete，清除记为 restore，
+// 对支持软删除的表执行的物理删除记为 purge
+func softDeleteAction(s *gorm.Statement, action string, changes map[string]map[string]interface{}) string {
+	switch action {
+	case models.AuditUpdate:
+		change, ok := changes["deleted_at"]
+		if !ok {
+			return action
+		}
+		if deleted, _ := change["new"].(gorm.DeletedAt); deleted.Valid {
+			return models.AuditDelete
+		}
+		return models.AuditRestore
+	case models.AuditDelete:
+		if _, ok := s.Schema.FieldsByDBName["deleted_at"]; ok {
+			return models.AuditPurge
+		}
+	}
+	return action
+}
+
+// auditValue 统一值的表示：[]byte 按文本记录
+func auditValue(v interface{}) interface{} {
+	if b, ok := v.([]byte); ok {
+		return string(b)
+	}
+	return v
+}
+
+func redact(v interface{}) interface{} {
+	if v == nil || v == "" {
+		return v
+	}
+	return auditRedacted
+}
+
+func unionKeys(a, b map[string]interface{}) map[string]struct{} {
+	keys := map[string]struct{}{}
+	for k := range a {
+		keys[k] = struct{}{}
+	}
+	for k := range b {
+		keys[k] = struct{}{}
+	}
+	return keys
+}
+
+// AuditFilter 审计日志查询条件，零值表示不限
+type AuditFilter struct {
+	UserID   uint
+	Table    string
+	RecordID string
+	Action   string
+	From     *time.Time
+	To       *time.Time // 不含
+	Limit    int
+}
+
+// AuditRepository 审计日志仓库：只提供查询，记录由回调写入
+type AuditRepository struct {
+	db *gorm.DB
+}
+
+func NewAuditRepository(db *gorm.DB) *AuditRepository {
+	return &AuditRepository{db: db}
+}
+
+// Search 按条件查询审计日志，最新的在前
+func (r *AuditRepository) Search(f AuditFilter) ([]models.AuditLog, error) {
+	tx := r.db.Model(&models.AuditLog{})
+	if f.UserID != 0 {
+		tx = tx.Where("user_id = ?", f.UserID)
+	}
+	if f.Table != "" {
+		tx = tx.Where("table_name = ?", f.Table)
+	}
+	if f.RecordID != "" {
+		tx = tx.Where("record_id = ?", f.RecordID)
+	}
+	if f.Action != "" {
+		tx = tx.Where("action = ?", f.Action)
+	}
+	if f.From != nil {
+		tx = tx.Where("created_at >= ?", *f.From)
+	}
+	if f.To != nil {
+		tx = tx.Where("created_at < ?", *f.To)
+	}
+	if f.Limit > 0 {
+		tx = tx.Limit(f.Limit)
+	}
+	var logs []models.AuditLog
+	err := tx.Order("id DESC").Find(&logs).Error
+	return logs, err
+}
@@ -0,0 +1,152 @@
+package repo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"erp-backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+func openAuditedDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db := openMigratedDB(t)
+	if err := RegisterAuditCallbacks(db); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func TestAuditCallbacksRecordEachWrite(t *testing.T) {
+	db := openAuditedDB(t)
+	ctx := WithAuditActor(context.Background(), AuditActor{UserID: 7, Username: "auditor", ClientIP: "10.0.0.1"})
+	projects := NewProjectRepository(db).WithContext(ctx)
+	trash := NewTrashRepository(db).WithContext(ctx)
+	project := &models.Project{ProjectID: "PRJ-1", Name: "Wells"}
+
+	steps := []struct {
+		name   string
+		run    func() error
+		action string // 空表示不应产生审计记录
+	}{
+		{"create", func() error { return projects.Create(project) }, models.AuditCreate},
+		{"rename", func() error { project.Name = "Deep wells"; return projects.Update(project) }, models.AuditUpdate},
+		{"save unchanged", func() error { return projects.Update(project) }, ""},
+		{"soft delete", func() error { return projects.Delete(project.ID) }, models.AuditDelete},
+		{"restore", func() error { return trash.Restore("projects", project.ID) }, models.AuditRestore},
+		{"delete again", func() error { return projects.Delete(project.ID) }, models.AuditDelete},
+		{"purge", func() error { return trash.Purge("projects", project.ID) }, models.AuditPurge},
+	}
+	audit := NewAuditRepository(db)
+	seen := 0
+	for _, step := range steps {
+		if err := step.run(); err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+		logs, err := audit.Search(AuditFilter{Table: "projects", RecordID: fmt.Sprint(project.ID)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if step.action == "" {
+			if len(logs) != seen {
+				t.Errorf("%s wrote %d audit entries, want none", step.name, len(logs)-seen)
+			}
+			continue
+		}
+		if len(logs) != seen+1 {
+			t.Fatalf("%s wrote %d audit entries, want 1", step.name, len(logs)-seen)
+		}
+		seen++
+		latest := logs[0]
+		if latest.Action != step.action {
+			t.Errorf("%s recorded action %q, want %q", step.name, latest.Action, step.action)
+		}
+		if latest.UserID == nil || *latest.UserID != 7 || latest.Username != "auditor" || latest.ClientIP != "10.0.0.1" {
+			t.Errorf("%s recorded actor %v/%q/%q, want 7/auditor/10.0.0.1", step.name, latest.UserID, latest.Username, latest.ClientIP)
+		}
+		if step.action == models.AuditUpdate {
+			var changes map[string]map[string]interface{}
+			if err := json.Unmarshal([]byte(latest.Changes), &changes); err != nil {
+				t.Fatal(err)
+			}
+			if name := changes["name"]; name["old"] != "Wells" || name["new"] != "Deep wells" {
+				t.Errorf("rename recorded name change %v, want Wells → Deep wells", name)
+			}
+		}
+	}
+}
+
+func TestAuditRedactsSecretsAndSkipsSessionTables(t *testing.T) {
+	db := openAuditedDB(t)
+	user := &models.User{Username: "alice", PasswordHash: "bcrypt-hash", UserType: "employee", Status: "active"}
+	if err := db.Create(user).Error; err != nil {
+		t.Fatal(err)
+	}
+	session := &models.Session{SessionID: "sid", UserID: user.ID, ExpiresAt: time.Now().UTC(), LastUsedAt: time.Now().UTC()}
+	if err := db.Create(session).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	audit := NewAuditRepository(db)
+	logs, err := audit.Search(AuditFilter{Table: "users"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(logs) != 1 {
+		t.Fatalf("%d audit entries for users, want 1", len(logs))
+	}
+	var changes map[string]map[string]interface{}
+	if err := json.Unmarshal([]byte(logs[0].Changes), &changes); err != nil {
+		t.Fatal(err)
+	}
+	if got := changes["password_hash"]["new"]; got != auditRedacted {
+		t.Errorf("password_hash recorded as %v, want %q", got, auditRedacted)
+	}
+	if logs, _ := audit.Search(AuditFilter{Table: "sessions"}); len(logs) != 0 {
+		t.Errorf("%d audit entries for sessions, want 0", len(logs))
+	}
+}
+
+func TestAuditLogIsAppendOnly(t *testing.T) {
+	db := openAuditedDB(t)
+	if err := db.Create(&models.Project{ProjectID: "PRJ-1", Name: "Wells"}).Error; err != nil {
+		t.Fatal(err)
+	}
+	var entry models.AuditLog
+	if err := db.First(&entry).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		run  func() error
+		want error // nil 表示只要求失败（由数据库触发器拒绝）
+	}{
+		{"gorm update", func() error {
+			return db.Model(&models.AuditLog{}).Where("id = ?", entry.ID).Update("action", models.AuditDelete).Error
+		}, ErrAuditAppendOnly},
+		{"gorm save", func() error { entry.Action = models.AuditDelete; return db.Save(&entry).Error }, ErrAuditAppendOnly},
+		{"gorm delete", func() error { return db.Delete(&models.AuditLog{}, entry.ID).Error }, ErrAuditAppendOnly},
+		{"raw update", func() error { return db.Exec("UPDATE audit_logs SET action = 'delete'").Error }, nil},
+		{"raw delete", func() error { return db.Exec("DELETE FROM audit_logs").Error }, nil},
+	}
+	for _, tt := range tests {
+		err := tt.run()
+		if err == nil || tt.want != nil && !errors.Is(err, tt.want) {
+			t.Errorf("%s = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+
+	var stored models.AuditLog
+	if err := db.First(&stored, entry.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if stored.Action != models.AuditCreate {
+		t.Errorf("audit entry action = %q after rejected writes, want %q", stored.Action, models.AuditCreate)
+	}
+}
@@ -10,7 +10,8 @@ import (
 
 // ChartRepository provides DB aggregation methods used by charting/reporting
 type ChartRepository struct {
-	db *gorm.DB
+	db    *gorm.DB
+	scope *ProjectScope // nil 表示聚合全机构数据
 }
 
 func NewChartRepository(db *gorm.DB) *ChartRepository {
@@ -36,15 +37,17 @@ func (r *ChartRepository) DonationsByDonor(donorID uint, start, end *time.Time)
 		Date  string  `gorm:"column:date"`
 		Value float64 `gorm:"column:sum_amount"`
 	}
+	day := dateOf(r.db, "donation_date")
 
-	tx := r.db.Model(&models.Donation{}).Select("date(donation_date) as date, sum(amount) as sum_amount").Where("donor_id = ?", donorID)
+	tx := r.db.Model(&models.Donation{}).Select(day+" as date, sum(amount) as sum_amount").Where("donor_id = ?", donorID)
 	if start != nil {
-		tx = tx.Where("date(donation_date) >= ?", start.Format("2006-01-02"))
+		tx = tx.Where(day+" >= ?", start.Format("2006-01-02"))
 	}
 	if end != nil {
-		tx = tx.Where("date(donation_date) <= ?", end.Format("2006-01-02"))
+		tx = tx.Where(day+" <= ?", end.Format("2006-01-02"))
 	}
-	tx = tx.Group("date(donation_date)").Order("date(donation_date)")
+	tx = r.scope.apply(tx, "donations.project_id", "")
+	tx = tx.Group(day).Order(day)
 
 	if err := tx.Scan(&rows).Error; err != nil {
 		return nil, err
@@ -63,6 +66,7 @@ func (r *ChartRepository) DonationsByProject(donorID uint, start, end *time.Time
 		ProjectName string  `gorm:"column:project_name"`
 		Value       float64 `gorm:"column:sum_amount"`
 	}
+	day := dateOf(r.db, "donation_date")
 
 	tx := r.db.Model(&models.Donation{}).
 		Select("donations.project_id as project_id, projects.name as project_name, sum(donations.amount) as sum_amount").
@@ -72,12 +76,13 @@ func (r *ChartRepository) DonationsByProject(donorID uint, start, end *time.Time
 		tx = tx.Where("donor_id = ?", donorID)
 	}
 	if start != nil {
-		tx = tx.Where("date(donation_date) >= ?", start.Format("2006-01-02"))
+		tx = tx.Where(day+" >= ?", start.Format("2006-01-02"))
 	}
 	if end != nil {
-		tx = tx.Where("date(donation_date) <= ?", end.Format("2006-01-02"))
+		tx = tx.Where(day+" <= ?", end.Format("2006-01-02"))
 	}
 
+	tx = r.scope.apply(tx, "donations.project_id", "")
 	tx = tx.Group("donations.project_id, projects.name").Order("sum_amount DESC")
 
 	if err := tx.Scan(&rows).Error; err != nil {
@@ -103,15 +108,17 @@ func (r *ChartRepository) FundAllocationsByDate(start, end *time.Time) ([]LinePo
 		Date  string  `gorm:"column:date"`
 		Value float64 `gorm:"column:sum_amount"`
 	}
+	day := dateOf(r.db, "allocation_date")
 
-	tx := r.db.Model(&models.FundProject{}).Select("date(allocation_date) as date, sum(allocated_amount) as sum_amount")
+	tx := r.db.Model(&models.FundProject{}).Select(day + " as date, sum(allocated_amount) as sum_amount")
 	if start != nil {
-		tx = tx.Where("date(allocation_date) >= ?", start.Format("2006-01-02"))
+		tx = tx.Where(day+" >= ?", start.Format("2006-01-02"))
 	}
 	if end != nil {
-		tx = tx.Where("date(allocation_date) <= ?", end.Format("2006-01-02"))
+		tx = tx.Where(day+" <= ?", end.Format("2006-01-02"))
 	}
-	tx = tx.Group("date(allocation_date)").Order("date(allocation_date)")
+	tx = r.scope.apply(tx, "fund_projects.project_id", "")
+	tx = tx.Group(day).Order(day)
 
 	if err := tx.Scan(&rows).Error; err != nil {
 		return nil, err
@@ -133,18 +140,20 @@ func (r *ChartRepository) FundAllocationsByProject(start, end *time.Time) ([]Pie
 		ProjectName string  `gorm:"column:project_name"`
 		Value       float64 `gorm:"column:sum_amount"`
 	}
+	day := dateOf(r.db, "allocation_date")
 	tx := r.db.Model(&models.FundProject{}).
 		Select("fund_projects.project_id as project_id, projects.name as project_name, sum(fund_projects.allocated_amount) as sum_amount").
 		Joins("LEFT JOIN projects ON projects.id = fund_projects.project_id")
 
 	if start != nil {
-		tx = tx.Where("date(allocation_date) >= ?", start.Format("2006-01-02"))
+		tx = tx.Where(day+" >= ?", start.Format("2006-01-02"))
 	}
 	if end != nil {
-		tx = tx.Where("date(allocation_date) <= ?", end.Format("2006-01-02"))
+		tx = tx.Where(day+" <= ?", end.Format("2006-01-02"))
 	}
 
-	tx = tx.Group("fund_projects.project_id").Order("sum_amount DESC")
+	tx = r.scope.apply(tx, "fund_projects.project_id", "")
+	tx = tx.Group("fund_projects.project_id, projects.name").Order("sum_amount DESC")
 
 	if err := tx.Scan(&rows).Error; err != nil {
 		return nil, err
@@ -169,18 +178,20 @@ func (r *ChartRepository) DonationsByDate(start, end *time.Time) ([]LinePoint, e
 		Date  string  `gorm:"column:date"`
 		Value float64 `gorm:"column:sum_amount"`
 	}
+	day := dateOf(r.db, "donation_date")
 
 	tx := r.db.Model(&models.Donation{}).
-		Select("date(donation_date) as date, sum(amount) as sum_amount")
+		Select(day + " as date, sum(amount) as sum_amount")
 
 	if start != nil {
-		tx = tx.Where("date(donation_date) >= ?", start.Format("2006-01-02"))
+		tx = tx.Where(day+" >= ?", start.Format("2006-01-02"))
 	}
 	if end != nil {
-		tx = tx.Where("date(donation_date) <= ?", end.Format("2006-01-02"))
+		tx = tx.Where(day+" <= ?", end.Format("2006-01-02"))
 	}
 
-	tx = tx.Group("date(donation_date)").Order("date(donation_date)")
+	tx = r.scope.apply(tx, "donations.project_id", "")
+	tx = tx.Group(day).Order(day)
 
 	if err := tx.Scan(&rows).Error; err != nil {
 		return nil, err
@@ -199,18 +210,20 @@ func (r *ChartRepository) ExpensesByDate(start, end *time.Time) ([]LinePoint, er
 		Date  string  `gorm:"column:date"`
 		Value float64 `gorm:"column:sum_amount"`
 	}
+	day := dateOf(r.db, "expense_date")
 
 	tx := r.db.Model(&models.Expense{}).
-		Select("date(expense_date) as date, sum(amount) as sum_amount")
+		Select(day + " as date, sum(amount) as sum_amount")
 
 	if start != nil {
-		tx = tx.Where("date(expense_date) >= ?", start.Format("2006-01-02"))
+		tx = tx.Where(day+" >= ?", start.Format("2006-01-02"))
 	}
 	if end != nil {
-		tx = tx.Where("date(expense_date) <= ?", end.Format("2006-01-02"))
+		tx = tx.Where(day+" <= ?", end.Format("2006-01-02"))
 	}
 
-	tx = tx.Group("date(expense_date)").Order("date(expense_date)")
+	tx = r.scope.apply(tx, "expenses.project_id", expenseOwnerCond)
+	tx = tx.Group(day).Order(day)
 
 	if err := tx.Scan(&rows).Error; err != nil {
 		return nil, err
@@ -230,18 +243,20 @@ func (r *ChartRepository) ExpensesByProject(start, end *time.Time) ([]PiePoint,
 		ProjectName string  `gorm:"column:project_name"`
 		Value       float64 `gorm:"column:sum_amount"`
 	}
+	day := dateOf(r.db, "expense_date")
 
 	tx := r.db.Model(&models.Expense{}).
 		Select("expenses.project_id as project_id, projects.name as project_name, sum(expenses.amount) as sum_amount").
 		Joins("LEFT JOIN projects ON projects.id = expenses.project_id")
 
 	if start != nil {
-		tx = tx.Where("date(expense_date) >= ?", start.Format("2006-01-02"))
+		tx = tx.Where(day+" >= ?", start.Format("2006-01-02"))
 	}
 	if end != nil {
-		tx = tx.Where("date(expense_date) <= ?", end.Format("2006-01-02"))
+		tx = tx.Where(day+" <= ?", end.Format("2006-01-02"))
 	}
 
+	tx = r.scope.apply(tx, "expenses.project_id", expenseOwnerCond)
 	tx = tx.Group("expenses.project_id, projects.name").Order("sum_amount DESC")
 
 	if err := tx.Scan(&rows).Error; err != nil {
@@ -271,15 +286,17 @@ func (r *ChartRepository) VolunteerHoursByVolunteer(volunteerID uint, start, end
 		Date  string  `gorm:"column:date"`
 		Hours float64 `gorm:"column:sum_hours"`
 	}
+	day := dateOf(r.db, "shift_date")
 
-	tx := r.db.Model(&models.Schedule{}).Select("date(shift_date) as date, sum(hours_worked) as sum_hours").Where("person_type = ? AND person_id = ?", "volunteer", volunteerID)
+	tx := r.db.Model(&models.Schedule{}).Select(day+" as date, sum(hours_worked) as sum_hours").Where("person_type = ? AND person_id = ?", "volunteer", volunteerID)
 	if start != nil {
-		tx = tx.Where("date(shift_date) >= ?", start.Format("2006-01-02"))
+		tx = tx.Where(day+" >= ?", start.Format("2006-01-02"))
 	}
 	if end != nil {
-		tx = tx.Where("date(shift_date) <= ?", end.Format("2006-01-02"))
+		tx = tx.Where(day+" <= ?", end.Format("2006-01-02"))
 	}
-	tx = tx.Group("date(shift_date)").Order("date(shift_date)")
+	tx = r.scope.apply(tx, "schedules.project_id", scheduleOwnerCond)
+	tx = tx.Group(day).Order(day)
 
 	if err := tx.Scan(&rows).Error; err != nil {
 		return nil, err
@@ -290,3 +307,76 @@ func (r *ChartRepository) VolunteerHoursByVolunteer(volunteerID uint, start, end
 	}
 	return out, nil
 }
+
+// InKindByDate 按日期聚合实物捐赠估值；donorID 为 0 时不按捐赠者过滤
+func (r *ChartRepository) InKindByDate(donorID uint, start, end *time.Time) ([]LinePoint, error) {
+	var rows []struct {
+		Date  string  `gorm:"column:date"`
+		Value float64 `gorm:"column:sum_amount"`
+	}
+	day := dateOf(r.db, "donation_date")
+
+	tx := r.db.Model(&models.DonationInventory{}).Select(day + " as date, sum(estimated_value) as sum_amount")
+	if donorID != 0 {
+		tx = tx.Where("donor_id = ?", donorID)
+	}
+	if start != nil {
+		tx = tx.Where(day+" >= ?", start.Format("2006-01-02"))
+	}
+	if end != nil {
+		tx = tx.Where(day+" <= ?", end.Format("2006-01-02"))
+	}
+	tx = r.scope.apply(tx, "donation_inventories.project_id", "")
+	tx = tx.Group(day).Order(day)
+
+	if err := tx.Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	out := make([]LinePoint, 0, len(rows))
+	for _, rrow := range rows {
+		out = append(out, LinePoint{Date: rrow.Date, Value: rrow.Value})
+	}
+	return out, nil
+}
+
+// InKindByProject 按项目聚合实物捐赠估值；donorID 为 0 时不按捐赠者过滤
+func (r *ChartRepository) InKindByProject(donorID uint, start, end *time.Time) ([]PiePoint, error) {
+	var rows []struct {
+		ProjectID   uint    `gorm:"column:project_id"`
+		ProjectName string  `gorm:"column:project_name"`
+		Value       float64 `gorm:"column:sum_amount"`
+	}
+	day := dateOf(r.db, "donation_date")
+
+	tx := r.db.Model(&models.DonationInventory{}).
+		Select("donation_inventories.project_id as project_id, projects.name as project_name, sum(donation_inventories.estimated_value) as sum_amount").
+		Joins("LEFT JOIN projects ON projects.id = donation_inventories.project_id")
+
+	if donorID != 0 {
+		tx = tx.Where("donor_id = ?", donorID)
+	}
+	if start != nil {
+		tx = tx.Where(day+" >= ?", start.Format("2006-01-02"))
+	}
+	if end != nil {
+		tx = tx.Where(day+" <= ?", end.Format("2006-01-02"))
+	}
+
+	tx = r.scope.apply(tx, "donation_inventories.project_id", "")
+	tx = tx.Group("donation_inventories.project_id, projects.name").Order("sum_amount DESC")
+
+	if err := tx.Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	out := make([]PiePoint, 0, len(rows))
+	for _, rrow := range rows {
+		out = append(out, PiePoint{
+			ProjectID:   rrow.ProjectID,
+			ProjectName: rrow.ProjectName,
+			Value:       rrow.Value,
+		})
+	}
+	return out, nil
+}
@@ -0,0 +1,128 @@
+package repo
+
+import (
+	"context"
+)
+
+// WithContext 返回绑定到 ctx 的事务入口，事务内的写操作按 ctx 中的操作人记录审计日志
+func (s *Store) WithContext(ctx context.Context) *Store {
+	return &Store{db: s.db.WithContext(ctx)}
+}
+
+// 以下 WithContext 返回绑定到 ctx 的仓储副本，用法同 Scoped
+
+func (r *UserRepository) WithContext(ctx context.Context) *UserRepository {
+	return &UserRepository{db: r.db.WithContext(ctx)}
+}
+
+func (r *ProjectRepository) WithContext(ctx context.Context) *ProjectRepository {
+	return &ProjectRepository{db: r.db.WithContext(ctx)}
+}
+
+func (r *DonorRepository) WithContext(ctx context.Context) *DonorRepository {
+	return &DonorRepository{db: r.db.WithContext(ctx)}
+}
+
+func (r *DonationRepository) WithContext(ctx context.Context) *DonationRepository {
+	return &DonationRepository{db: r.db.WithContext(ctx)}
+}
+
+func (r *VolunteerRepository) WithContext(ctx context.Context) *VolunteerRepository {
+	return &VolunteerRepository{db: r.db.WithContext(ctx)}
+}
+
+func (r *EmployeeRepository) WithContext(ctx context.Context) *EmployeeRepository {
+	return &EmployeeRepository{db: r.db.WithContext(ctx)}
+}
+
+func (r *LocationRepository) WithContext(ctx context.Context) *LocationRepository {
+	return &LocationRepository{db: r.db.WithContext(ctx)}
+}
+
+func (r *FundRepository) WithContext(ctx context.Context) *FundRepository {
+	return &FundRepository{db: r.db.WithContext(ctx)}
+}
+
+func (r *ExpenseRepository) WithContext(ctx context.Context) *ExpenseRepository {
+	return &ExpenseRepository{db: r.db.WithContext(ctx)}
+}
+
+func (r *TransactionRepository) WithContext(ctx context.Context) *TransactionRepository {
+	return &TransactionRepository{db: r.db.WithContext(ctx)}
+}
+
+func (r *PurchaseRepository) WithContext(ctx context.Context) *PurchaseRepository {
+	return &PurchaseRepository{db: r.db.WithContext(ctx)}
+}
+
+func (r *PayrollRepository) WithContext(ctx context.Context) *PayrollRepository {
+	return &PayrollRepository{db: r.db.WithContext(ctx)}
+}
+
+func (r *InventoryRepository) WithContext(ctx context.Context) *InventoryRepository {
+	return &InventoryRepository{db: r.db.WithContext(ctx)}
+}
+
+func (r *GiftTypeRepository) WithContext(ctx context.Context) *GiftTypeRepository {
+	return &GiftTypeRepository{db: r.db.WithContext(ctx)}
+}
+
+func (r *GiftRepository) WithContext(ctx context.Context) *GiftRepository {
+	return &GiftRepository{db: r.db.WithContext(ctx)}
+}
+
+func (r *InventoryTransactionRepository) WithContext(ctx context.Context) *InventoryTransactionRepository {
+	return &InventoryTransactionRepository{db: r.db.WithContext(ctx)}
+}
+
+func (r *DeliveryRepository) WithContext(ctx context.Context) *DeliveryRepository {
+	return &DeliveryRepository{db: r.db.WithContext(ctx)}
+}
+
+func (r *VolunteerProjectRepository) WithContext(ctx context.Context) *VolunteerProjectRepository {
+	return &VolunteerProjectRepository{db: r.db.WithContext(ctx)}
+}
+
+func (r *EmployeeProjectRepository) WithContext(ctx context.Context) *EmployeeProjectRepository {
+	return &EmployeeProjectRepository{db: r.db.WithContext(ctx)}
+}
+
+func (r *FundProjectRepository) WithContext(ctx context.Context) *FundProjectRepository {
+	return &FundProjectRepository{db: r.db.WithContext(ctx)}
+}
+
+func (r *DonationInventoryRepository) WithContext(ctx context.Context) *DonationInventoryRepository {
+	return &DonationInventoryRepository{db: r.db.WithContext(ctx)}
+}
+
+func (r *DeliveryInventoryRepository) WithContext(ctx context.Context) *DeliveryInventoryRepository {
+	return &DeliveryInventoryRepository{db: r.db.WithContext(ctx)}
+}
+
+func (r *ScheduleRepository) WithContext(ctx context.Context) *ScheduleRepository {
+	return &ScheduleRepository{db: r.db.WithContext(ctx)}
+}
+
+func (r *ApprovalRepository) WithContext(ctx context.Context) *ApprovalRepository {
+	return &ApprovalRepository{db: r.db.WithContext(ctx)}
+}
+
+func (r *TrashRepository) WithContext(ctx context.Context) *TrashRepository {
+	return &TrashRepository{db: r.db.WithContext(ctx), scope: r.scope}
+}
+
+func (r *ImportRepository) WithContext(ctx context.Context) *ImportRepository {
+	return &ImportRepository{db: r.db.WithContext(ctx)}
+}
+
+func (r *ExportRepository) WithContext(ctx context.Context) *ExportRepository {
+	return &ExportRepository{db: r.db.WithContext(ctx), scope: r.scope}
+}
+
+func (r *ReceiptRepository) WithContext(ctx context.Context) *ReceiptRepository {
+	return &ReceiptRepository{db: r.db.WithContext(ctx)}
+}
+
+func (r *CostingRepository) WithContext(ctx context.Context) *CostingRepository {
+	return &CostingRepository{db: r.db.WithContext(ctx), scope: r.scope}
+}
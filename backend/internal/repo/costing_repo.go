@@ -0,0 +1,144 @@
+package repo
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"erp-backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// CostingRepository 库存计价：各类别的计价方法，以及库存估值与配送成本报表
+type CostingRepository struct {
+	db    *gorm.DB
+	scope *ProjectScope // nil 表示统计全机构数据；只作用于按项目统计的配送成本
+}
+
+func NewCostingRepository(db *gorm.DB) *CostingRepository {
+	return &CostingRepository{db: db}
+}
+
+// CategoryCostMethod 一个库存类别的计价方法；Default 表示未设置、使用默认方法
+type CategoryCostMethod struct {
+	Category string `json:"category"`
+	Method   string `json:"method"`
+	Default  bool   `json:"default"`
+}
+
+// CostMethod 返回类别的计价方法，未设置时为 models.DefaultCostMethod
+func (r *CostingRepository) CostMethod(category string) (string, error) {
+	var m models.InventoryCostMethod
+	err := r.db.Where("category = ?", category).First(&m).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return models.DefaultCostMethod, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return m.Method, nil
+}
+
+// CostMethods 返回库存中出现的类别与已设置计价方法的类别，按类别排序
+func (r *CostingRepository) CostMethods() ([]CategoryCostMethod, error) {
+	var categories []string
+	if err := r.db.Model(&models.Inventory{}).Where("category <> ''").
+		Distinct().Pluck("category", &categories).Error; err != nil {
+		return nil, err
+	}
+	var configured []models.InventoryCostMethod
+	if err := r.db.Find(&configured).Error; err != nil {
+		return nil, err
+	}
+	byCategory := make(map[string]CategoryCostMethod, len(categories)+len(configured))
+	for _, c := range categories {
+		byCategory[c] = CategoryCostMethod{Category: c, Method: models.DefaultCostMethod, Default: true}
+	}
+	for _, m := range configured {
+		byCategory[m.Category] = CategoryCostMethod{Category: m.Category, Method: m.Method}
+	}
+	out := make([]CategoryCostMethod, 0, len(byCategory))
+	for _, m := range byCategory {
+		out = append(out, m)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Category < out[j].Category })
+	return out, nil
+}
+
+// SaveCostMethod 设置类别的计价方法，已有设置时替换
+func (r *CostingRepository) SaveCostMethod(m *models.InventoryCostMethod) error {
+	var existing models.InventoryCostMethod
+	err := r.db.Where("category = ?", m.Category).First(&existing).Error
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return r.db.Create(m).Error
+	case err != nil:
+		return err
+	}
+	m.ID = existing.ID
+	m.CreatedAt = existing.CreatedAt
+	return r.db.Save(m).Error
+}
+
+// ValuationRow 一项库存截至某日的数量与成本
+type ValuationRow struct {
+	ID          uint    `json:"id"`
+	InventoryID string  `json:"inventory_id"`
+	Name        string  `json:"name"`
+	Category    string  `json:"category"`
+	Quantity    int     `json:"quantity"`
+	Value       float64 `json:"value"`
+}
+
+// Valuation 按库存变动累计各未删除库存截至 asOf（含当日）的数量与成本；asOf 为 nil 表示全部变动。
+// 冲销变动与原变动的日期相同，两者一并计入、相互抵消
+func (r *CostingRepository) Valuation(asOf *time.Time) ([]ValuationRow, error) {
+	cond, args := "", []interface{}{}
+	if asOf != nil {
+		cond = " AND t.transaction_date < ?"
+		before := asOf.AddDate(0, 0, 1)
+		args = []interface{}{before, before, before, before}
+	}
+	sum := func(side, expr string) string {
+		return "COALESCE((SELECT SUM(" + expr + ") FROM inventory_transactions t WHERE t." + side + " = inventories.id" + cond + "), 0)"
+	}
+	var rows []ValuationRow
+	err := r.db.Model(&models.Inventory{}).
+		Select("inventories.id, inventories.inventory_id, inventories.name, COALESCE(inventories.category, '') AS category, "+
+			sum("to_inventory_id", "t.quantity_change")+" - "+sum("from_inventory_id", "t.quantity_change")+" AS quantity, "+
+			sum("to_inventory_id", "t.quantity_change * t.unit_cost")+" - "+sum("from_inventory_id", "t.quantity_change * t.unit_cost")+" AS value",
+			args...).
+		Order("inventories.category, inventories.inventory_id").Scan(&rows).Error
+	return rows, err
+}
+
+// DistributionCostRow 一个项目在期间内配送物品的数量与成本；ProjectID 为空表示未关联项目的配送
+type DistributionCostRow struct {
+	ProjectID   *uint   `json:"project_id"`
+	ProjectName string  `json:"project_name"`
+	Deliveries  int     `json:"deliveries"`
+	Quantity    int     `json:"quantity"`
+	Cost        float64 `json:"cost"`
+}
+
+// DistributionCost 按项目汇总 [start, end] 期间未删除配送明细的数量与成本，日期取配送日期，未填写时取登记时间
+func (r *CostingRepository) DistributionCost(start, end *time.Time) ([]DistributionCostRow, error) {
+	day := "COALESCE(deliveries.delivery_date, deliveries.created_at)"
+	tx := r.db.Model(&models.DeliveryInventory{}).
+		Select("deliveries.project_id, COALESCE(projects.name, '') AS project_name, COUNT(DISTINCT deliveries.id) AS deliveries, " +
+			"SUM(delivery_inventories.quantity) AS quantity, SUM(delivery_inventories.quantity * delivery_inventories.unit_cost) AS cost").
+		Joins("JOIN deliveries ON deliveries.id = delivery_inventories.delivery_id AND deliveries.deleted_at IS NULL").
+		Joins("LEFT JOIN projects ON projects.id = deliveries.project_id")
+	if start != nil {
+		tx = tx.Where(day+" >= ?", *start)
+	}
+	if end != nil {
+		tx = tx.Where(day+" < ?", end.AddDate(0, 0, 1))
+	}
+	tx = r.scope.apply(tx, "deliveries.project_id", "")
+
+	var rows []DistributionCostRow
+	err := tx.Group("deliveries.project_id, projects.name").Order("deliveries.project_id").Scan(&rows).Error
+	return rows, err
+}
@@ -6,7 +6,6 @@ import (
 
 	// "sync"
 
-	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 
@@ -18,71 +17,63 @@ var DB *gorm.DB
 // var dbOnce sync.Once
 // var initErr error
 
-// InitDatabase 初始化数据库连接
-func InitDatabase(dbPath string) error {
-	var err error
+// InitDatabase 初始化数据库连接；数据库结构落后于内嵌的迁移时拒绝启动，需先执行 migrate up
+func InitDatabase(cfg DatabaseConfig) error {
+	if err := OpenDatabase(cfg); err != nil {
+		return err
+	}
+
+	migrator, err := NewMigrator(DB)
+	if err != nil {
+		return fmt.Errorf("failed to load migrations: %w", err)
+	}
+	if err := migrator.Check(); err != nil {
+		return fmt.Errorf("%w (run `migrate up` first)", err)
+	}
+
+	// 写入默认会计科目
+	if err := NewLedgerRepository(DB).SeedAccounts(models.DefaultChartOfAccounts()); err != nil {
+		return fmt.Errorf("failed to seed chart of accounts: %w", err)
+	}
+
+	// 写入内置角色
+	if err := NewRBACRepository(DB).SeedRoles(models.DefaultRoles()); err != nil {
+		return fmt.Errorf("failed to seed roles: %w", err)
+	}
+
+	return nil
+}
+
+// OpenDatabase 连接数据库并注册审计回调，不检查数据库结构；供迁移命令使用
+func OpenDatabase(cfg DatabaseConfig) error {
+	dialector, err := openDialector(cfg.Driver, cfg.DSN)
+	if err != nil {
+		return err
+	}
 
 	// 配置GORM日志
 	config := &gorm.Config{
 		Logger: logger.Default.LogMode(logger.Info),
 	}
 
-	// 连接SQLite数据库
-	DB, err = gorm.Open(sqlite.Open(dbPath), config)
+	// 连接数据库
+	DB, err = gorm.Open(dialector, config)
 	if err != nil {
 		return fmt.Errorf("failed to connect database: %w", err)
 	}
+	if err := configurePool(DB, cfg); err != nil {
+		return fmt.Errorf("failed to configure connection pool: %w", err)
+	}
 
 	log.Println("Database connected successfully")
 
-	// 自动迁移所有模型
-	err = AutoMigrate()
-	if err != nil {
-		return fmt.Errorf("failed to migrate database: %w", err)
+	// 所有增删改写入审计日志
+	if err := RegisterAuditCallbacks(DB); err != nil {
+		return fmt.Errorf("failed to register audit callbacks: %w", err)
 	}
-
-	log.Println("Database migration completed")
 	return nil
 }
 
-// AutoMigrate 自动迁移所有数据表
-func AutoMigrate() error {
-	return DB.AutoMigrate(
-		// 核心实体
-		&models.User{},
-		&models.Location{},
-		&models.Project{},
-		&models.Donor{},
-		&models.Volunteer{},
-		&models.Employee{},
-
-		// 财务相关
-		&models.Transaction{},
-		&models.Donation{},
-		&models.Fund{},
-		&models.Expense{},
-		&models.Purchase{},
-		&models.Payroll{},
-
-		// 库存和礼品
-		&models.Inventory{},
-		&models.GiftType{},
-		&models.Gift{},
-		&models.InventoryTransaction{},
-		&models.Delivery{},
-
-		// 关联表
-		&models.VolunteerProject{},
-		&models.EmployeeProject{},
-		&models.FundProject{},
-		&models.DonationInventory{},
-		&models.DeliveryInventory{},
-
-		// Schedule
-		&models.Schedule{},
-	)
-}
-
 // GetDB 获取数据库实例
 func GetDB() *gorm.DB {
 	return DB
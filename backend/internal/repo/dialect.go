@@ -0,0 +1,86 @@
+package repo
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// 支持的数据库驱动，与 gorm 方言名称一致
+const (
+	DriverSQLite   = "sqlite"
+	DriverPostgres = "postgres"
+	DriverMySQL    = "mysql"
+)
+
+// DatabaseConfig 数据库驱动、连接串与连接池设置
+type DatabaseConfig struct {
+	Driver          string // sqlite、postgres 或 mysql，空表示 sqlite
+	DSN             string // sqlite 为数据库文件路径
+	MaxOpenConns    int    // 0 表示不限制
+	MaxIdleConns    int    // 0 表示使用 database/sql 的默认值
+	ConnMaxLifetime time.Duration
+	ConnMaxIdleTime time.Duration
+}
+
+// openDialector 按驱动名称创建 gorm 方言，并补上程序依赖的连接参数
+func openDialector(driver, dsn string) (gorm.Dialector, error) {
+	switch driver {
+	case "", DriverSQLite:
+		// 每个连接都开启外键检查
+		return sqlite.Open(withParams(dsn, "_foreign_keys=1")), nil
+	case DriverPostgres:
+		return postgres.Open(dsn), nil
+	case DriverMySQL:
+		// 迁移脚本一次执行多条语句；时间列读出为 time.Time
+		return mysql.Open(withParams(dsn, "multiStatements=true", "parseTime=true")), nil
+	}
+	return nil, fmt.Errorf("unsupported database driver %q", driver)
+}
+
+// withParams 在连接串上追加连接串中尚未设置的参数
+func withParams(dsn string, params ...string) string {
+	for _, param := range params {
+		key := param[:strings.Index(param, "=")+1]
+		if strings.Contains(dsn, "?"+key) || strings.Contains(dsn, "&"+key) {
+			continue
+		}
+		sep := "?"
+		if strings.Contains(dsn, "?") {
+			sep = "&"
+		}
+		dsn += sep + param
+	}
+	return dsn
+}
+
+// configurePool 应用连接池设置
+func configurePool(db *gorm.DB, cfg DatabaseConfig) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
+	if cfg.MaxIdleConns > 0 {
+		sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
+	}
+	sqlDB.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	sqlDB.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
+	return nil
+}
+
+// dateOf 返回取时间列日期部分（YYYY-MM-DD 文本）的 SQL 表达式，用于按天分组与比较
+func dateOf(db *gorm.DB, column string) string {
+	switch db.Dialector.Name() {
+	case DriverPostgres:
+		return fmt.Sprintf("to_char(%s, 'YYYY-MM-DD')", column)
+	case DriverMySQL:
+		return fmt.Sprintf("DATE_FORMAT(%s, '%%Y-%%m-%%d')", column)
+	}
+	return fmt.Sprintf("date(%s)", column)
+}
@@ -0,0 +1,123 @@
+package repo
+
+import (
+	"testing"
+	"time"
+
+	"erp-backend/internal/models"
+)
+
+func TestWithParams(t *testing.T) {
+	tests := []struct {
+		dsn    string
+		params []string
+		want   string
+	}{
+		{"erp.db", []string{"_foreign_keys=1"}, "erp.db?_foreign_keys=1"},
+		{"file:erp.db?cache=shared", []string{"_foreign_keys=1"}, "file:erp.db?cache=shared&_foreign_keys=1"},
+		{"erp.db?_foreign_keys=0", []string{"_foreign_keys=1"}, "erp.db?_foreign_keys=0"},
+		{"erp:secret@tcp(db:3306)/erp", []string{"multiStatements=true", "parseTime=true"},
+			"erp:secret@tcp(db:3306)/erp?multiStatements=true&parseTime=true"},
+		{"erp:secret@tcp(db:3306)/erp?parseTime=false&loc=UTC", []string{"multiStatements=true", "parseTime=true"},
+			"erp:secret@tcp(db:3306)/erp?parseTime=false&loc=UTC&multiStatements=true"},
+		// 参数名只按完整的键匹配
+		{"erp.db?x_foreign_keys=1", []string{"_foreign_keys=1"}, "erp.db?x_foreign_keys=1&_foreign_keys=1"},
+	}
+	for _, tt := range tests {
+		if got := withParams(tt.dsn, tt.params...); got != tt.want {
+			t.Errorf("withParams(%q, %q) = %q, want %q", tt.dsn, tt.params, got, tt.want)
+		}
+	}
+}
+
+func TestOpenDialectorRejectsUnknownDriver(t *testing.T) {
+	if _, err := openDialector("oracle", "dsn"); err == nil {
+		t.Fatal("openDialector accepted an unsupported driver")
+	}
+}
+
+func TestDateOfBucketsByDay(t *testing.T) {
+	db := openMigratedDB(t)
+	donor := models.Donor{DonorID: "DNR-1", FirstName: "Ada", LastName: "Lovelace"}
+	if err := db.Create(&donor).Error; err != nil {
+		t.Fatal(err)
+	}
+	at := func(s string) time.Time {
+		v, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return v
+	}
+	for i, d := range []struct {
+		date   time.Time
+		amount float64
+	}{
+		{at("2025-03-01T00:05:00Z"), 10},
+		{at("2025-03-01T23:55:00Z"), 15},
+		{at("2025-03-02T12:00:00Z"), 7},
+		{at("2025-03-04T08:30:00Z"), 3},
+	} {
+		donation := models.Donation{
+			DonationID: "DON-" + string(rune('A'+i)), DonorID: &donor.ID, Amount: d.amount,
+			DonationType: "one-time", Category: "general", DonationDate: d.date,
+		}
+		if err := db.Create(&donation).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	charts := NewChartRepository(db)
+	got, err := charts.DonationsByDonor(donor.ID, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []LinePoint{{"2025-03-01", 25}, {"2025-03-02", 7}, {"2025-03-04", 3}}
+	if len(got) != len(want) {
+		t.Fatalf("DonationsByDonor = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("DonationsByDonor[%d] = %v, want %v", i, got[i], want[i])
+		}
+	}
+
+	// 起止日期按日期部分比较，两端都包含
+	start, end := at("2025-03-02T00:00:00Z"), at("2025-03-04T00:00:00Z")
+	got, err = charts.DonationsByDonor(donor.ID, &start, &end)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0].Date != "2025-03-02" || got[1].Date != "2025-03-04" {
+		t.Errorf("DonationsByDonor(2025-03-02..2025-03-04) = %v", got)
+	}
+}
+
+func TestIsForeignKeyViolation(t *testing.T) {
+	db := openMigratedDB(t)
+	missing := uint(999999)
+	err := db.Create(&models.Donation{
+		DonationID: "DON-FK", DonorID: &missing, Amount: 1,
+		DonationType: "one-time", Category: "general", DonationDate: time.Now().UTC(),
+	}).Error
+	if err == nil {
+		t.Fatal("inserting a donation for a missing donor succeeded")
+	}
+	if !IsForeignKeyViolation(err) {
+		t.Errorf("IsForeignKeyViolation(%v) = false, want true", err)
+	}
+
+	if err := db.Create(&models.Donor{DonorID: "DNR-1", FirstName: "A", LastName: "B"}).Error; err != nil {
+		t.Fatal(err)
+	}
+	err = db.Create(&models.Donor{DonorID: "DNR-1", FirstName: "C", LastName: "D"}).Error
+	if err == nil {
+		t.Fatal("inserting a duplicate donor_id succeeded")
+	}
+	if IsForeignKeyViolation(err) {
+		t.Errorf("IsForeignKeyViolation(%v) = true for a unique violation", err)
+	}
+	if IsForeignKeyViolation(nil) {
+		t.Error("IsForeignKeyViolation(nil) = true")
+	}
+}
@@ -4,57 +4,9 @@ import (
 	"erp-backend/internal/models"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
-// applyFilters applies query, number_range and date_range filters to the GORM tx.
-// - query: map[string]interface{} -> for string values use LIKE (without adding wildcards), otherwise =
-// - numberRange: map[string][]interface{} -> [min, max], apply >= min and <= max if present
-// - dateRange: map[string][]string -> [start, end] in YYYY-MM-DD, apply >= start and <= end
-func applyFilters(tx *gorm.DB, query map[string]interface{}, numberRange map[string][]interface{}, dateRange map[string][]string) *gorm.DB {
-	if tx == nil {
-		return tx
-	}
-
-	for key, value := range query {
-		if value != "" && value != nil {
-			if s, ok := value.(string); ok {
-				// Use LIKE as requested but do not add wildcard characters
-				tx = tx.Where(key+" LIKE ?", s)
-			} else {
-				tx = tx.Where(key+" = ?", value)
-			}
-		}
-	}
-
-	for key, rangeVals := range numberRange {
-		if len(rangeVals) > 0 {
-			if rangeVals[0] != nil && rangeVals[0] != "" {
-				tx = tx.Where(key+" >= ?", rangeVals[0])
-			}
-		}
-		if len(rangeVals) > 1 {
-			if rangeVals[1] != nil && rangeVals[1] != "" {
-				tx = tx.Where(key+" <= ?", rangeVals[1])
-			}
-		}
-	}
-
-	for key, rangeVals := range dateRange {
-		if len(rangeVals) > 0 {
-			if rangeVals[0] != "" {
-				tx = tx.Where(key+" >= ?", rangeVals[0])
-			}
-		}
-		if len(rangeVals) > 1 {
-			if rangeVals[1] != "" {
-				tx = tx.Where(key+" <= ?", rangeVals[1])
-			}
-		}
-	}
-
-	return tx
-}
-
 // UserRepository 用户仓库
 type UserRepository struct {
 	db *gorm.DB
@@ -76,6 +28,13 @@ func (r *UserRepository) GetAll() ([]models.User, error) {
 	return users, nil
 }
 
+// List 分页读取账号，附带关联的档案
+func (r *UserRepository) List(p ListParams) ([]models.User, *models.Pagination, error) {
+	var users []models.User
+	page, err := paginate(withProfiles(r.db.Model(&models.User{})), p, &users)
+	return users, page, err
+}
+
 func (r *UserRepository) Search(query map[string]interface{}) ([]models.User, error) {
 	tx := r.db.Model(&models.User{})
 
@@ -90,22 +49,59 @@ func (r *UserRepository) Search(query map[string]interface{}) ([]models.User, er
 	return users, err
 }
 
-func (r *UserRepository) Filter(query map[string]interface{}, numberRange map[string][]interface{}, dateRange map[string][]string) ([]models.User, error) {
-	tx := r.db.Model(&models.User{})
+// Filter 按账号字段或档案字段（如 employee.department、donor.email）过滤账号，附带关联的档案
+func (r *UserRepository) Filter(f Filter, p ListParams) ([]models.User, *models.Pagination, error) {
+	tx, err := applyFilter(r.db.Model(&models.User{}), f)
+	if err != nil {
+		return nil, nil, err
+	}
+	var users []models.User
+	page, err := paginate(withProfiles(tx), p, &users)
+	return users, page, err
+}
 
-	tx = applyFilters(tx, query, numberRange, dateRange)
+// withProfiles 预加载账号的员工、志愿者或捐赠者档案
+func withProfiles(tx *gorm.DB) *gorm.DB {
+	return tx.Preload("Employee").Preload("Volunteer").Preload("Donor")
+}
 
-	var users []models.User
-	err := tx.Find(&users).Error
-	return users, err
+func (r *UserRepository) GetByID(id uint) (*models.User, error) {
+	var user models.User
+	if err := r.db.First(&user, id).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
 }
 
+// Update 保存账号资料；密码哈希只能通过 UpdatePassword 修改，账号状态由注册审核维护，最近登录时间由登录维护
 func (r *UserRepository) Update(user *models.User) error {
-	return r.db.Save(user).Error
+	return saveLive(r.db, user, "password_hash", "status", "user_type", "last_login", "created_at")
+}
+
+// UpdatePassword 更新密码哈希
+func (r *UserRepository) UpdatePassword(id uint, hash string) error {
+	return r.db.Model(&models.User{}).Where("id = ?", id).Update("password_hash", hash).Error
+}
+
+// ContactEmail 返回账号对应档案（员工/志愿者/捐赠者）的邮箱，没有档案时返回空字符串
+func (r *UserRepository) ContactEmail(user *models.User) (string, error) {
+	tables := map[string]string{"employee": "employees", "volunteer": "volunteers", "donor": "donors"}
+	table, ok := tables[user.UserType]
+	if !ok {
+		return "", nil
+	}
+	var emails []string
+	if err := r.db.Table(table).Where("user_id = ?", user.ID).Limit(1).Pluck("email", &emails).Error; err != nil {
+		return "", err
+	}
+	if len(emails) == 0 {
+		return "", nil
+	}
+	return emails[0], nil
 }
 
 func (r *UserRepository) Delete(id uint) error {
-	return r.db.Delete(&models.User{}, id).Error
+	return softDelete(r.db, &models.User{}, id)
 }
 
 // ProjectRepository 项目仓储
@@ -127,6 +123,12 @@ func (r *ProjectRepository) GetAll() ([]models.Project, error) {
 	return projects, err
 }
 
+func (r *ProjectRepository) List(p ListParams) ([]models.Project, *models.Pagination, error) {
+	var projects []models.Project
+	page, err := paginate(r.db.Model(&models.Project{}), p, &projects)
+	return projects, page, err
+}
+
 func (r *ProjectRepository) Search(query map[string]interface{}) ([]models.Project, error) {
 	tx := r.db
 
@@ -141,22 +143,22 @@ func (r *ProjectRepository) Search(query map[string]interface{}) ([]models.Proje
 	return projects, err
 }
 
-func (r *ProjectRepository) Filter(query map[string]interface{}, numberRange map[string][]interface{}, dateRange map[string][]string) ([]models.Project, error) {
-	tx := r.db.Model(&models.Project{})
-
-	tx = applyFilters(tx, query, numberRange, dateRange)
-
+func (r *ProjectRepository) Filter(f Filter, p ListParams) ([]models.Project, *models.Pagination, error) {
+	tx, err := applyFilter(r.db.Model(&models.Project{}), f)
+	if err != nil {
+		return nil, nil, err
+	}
 	var projects []models.Project
-	err := tx.Find(&projects).Error
-	return projects, err
+	page, err := paginate(tx, p, &projects)
+	return projects, page, err
 }
 
 func (r *ProjectRepository) Update(project *models.Project) error {
-	return r.db.Save(project).Error
+	return saveLive(r.db, project)
 }
 
 func (r *ProjectRepository) Delete(id uint) error {
-	return r.db.Delete(&models.Project{}, id).Error
+	return softDelete(r.db, &models.Project{}, id)
 }
 
 // DonorRepository 捐赠者仓储
@@ -178,6 +180,12 @@ func (r *DonorRepository) GetAll() ([]models.Donor, error) {
 	return donors, err
 }
 
+func (r *DonorRepository) List(p ListParams) ([]models.Donor, *models.Pagination, error) {
+	var donors []models.Donor
+	page, err := paginate(r.db.Model(&models.Donor{}), p, &donors)
+	return donors, page, err
+}
+
 func (r *DonorRepository) Search(query map[string]interface{}) ([]models.Donor, error) {
 	tx := r.db.Model(&models.Donor{})
 
@@ -192,12 +200,13 @@ func (r *DonorRepository) Search(query map[string]interface{}) ([]models.Donor,
 	return donors, err
 }
 
+// Update 保存捐赠者资料；total_donated 与 in_kind_total 只由过账以原子增减维护，不随资料写回
 func (r *DonorRepository) Update(donor *models.Donor) error {
-	return r.db.Save(donor).Error
+	return saveLive(r.db, donor, "total_donated", "in_kind_total", "created_at")
 }
 
 func (r *DonorRepository) Delete(id uint) error {
-	return r.db.Delete(&models.Donor{}, id).Error
+	return softDelete(r.db, &models.Donor{}, id)
 }
 
 // DonationRepository 捐赠仓储
@@ -209,8 +218,9 @@ func NewDonationRepository(db *gorm.DB) *DonationRepository {
 	return &DonationRepository{db: db}
 }
 
+// Create 只写入捐赠行本身，礼品等关联由 DonationService 的过账流程负责
 func (r *DonationRepository) Create(donation *models.Donation) error {
-	return r.db.Create(donation).Error
+	return r.db.Omit(clause.Associations).Create(donation).Error
 }
 
 func (r *DonationRepository) GetAll() ([]models.Donation, error) {
@@ -219,6 +229,12 @@ func (r *DonationRepository) GetAll() ([]models.Donation, error) {
 	return donations, err
 }
 
+func (r *DonationRepository) List(p ListParams) ([]models.Donation, *models.Pagination, error) {
+	var donations []models.Donation
+	page, err := paginate(r.db.Model(&models.Donation{}), p, &donations)
+	return donations, page, err
+}
+
 func (r *DonationRepository) Search(query map[string]interface{}) ([]models.Donation, error) {
 	tx := r.db
 
@@ -234,11 +250,11 @@ func (r *DonationRepository) Search(query map[string]interface{}) ([]models.Dona
 }
 
 func (r *DonationRepository) Update(donation *models.Donation) error {
-	return r.db.Save(donation).Error
+	return saveLive(r.db, donation, clause.Associations)
 }
 
 func (r *DonationRepository) Delete(id uint) error {
-	return r.db.Delete(&models.Donation{}, id).Error
+	return softDelete(r.db, &models.Donation{}, id)
 }
 
 // VolunteerRepository 志愿者仓储
@@ -260,6 +276,12 @@ func (r *VolunteerRepository) GetAll() ([]models.Volunteer, error) {
 	return volunteers, err
 }
 
+func (r *VolunteerRepository) List(p ListParams) ([]models.Volunteer, *models.Pagination, error) {
+	var volunteers []models.Volunteer
+	page, err := paginate(r.db.Model(&models.Volunteer{}), p, &volunteers)
+	return volunteers, page, err
+}
+
 func (r *VolunteerRepository) Search(query map[string]interface{}) ([]models.Volunteer, error) {
 	tx := r.db
 
@@ -275,11 +297,11 @@ func (r *VolunteerRepository) Search(query map[string]interface{}) ([]models.Vol
 }
 
 func (r *VolunteerRepository) Update(volunteer *models.Volunteer) error {
-	return r.db.Save(volunteer).Error
+	return saveLive(r.db, volunteer)
 }
 
 func (r *VolunteerRepository) Delete(id uint) error {
-	return r.db.Delete(&models.Volunteer{}, id).Error
+	return softDelete(r.db, &models.Volunteer{}, id)
 }
 
 // EmployeeRepository 员工仓储
@@ -301,6 +323,12 @@ func (r *EmployeeRepository) GetAll() ([]models.Employee, error) {
 	return employees, err
 }
 
+func (r *EmployeeRepository) List(p ListParams) ([]models.Employee, *models.Pagination, error) {
+	var employees []models.Employee
+	page, err := paginate(r.db.Model(&models.Employee{}), p, &employees)
+	return employees, page, err
+}
+
 func (r *EmployeeRepository) Search(query map[string]interface{}) ([]models.Employee, error) {
 	tx := r.db
 
@@ -316,11 +344,11 @@ func (r *EmployeeRepository) Search(query map[string]interface{}) ([]models.Empl
 }
 
 func (r *EmployeeRepository) Update(employee *models.Employee) error {
-	return r.db.Save(employee).Error
+	return saveLive(r.db, employee)
 }
 
 func (r *EmployeeRepository) Delete(id uint) error {
-	return r.db.Delete(&models.Employee{}, id).Error
+	return softDelete(r.db, &models.Employee{}, id)
 }
 
 // LocationRepository 地点仓储
@@ -342,6 +370,12 @@ func (r *LocationRepository) GetAll() ([]models.Location, error) {
 	return locations, err
 }
 
+func (r *LocationRepository) List(p ListParams) ([]models.Location, *models.Pagination, error) {
+	var locations []models.Location
+	page, err := paginate(r.db.Model(&models.Location{}), p, &locations)
+	return locations, page, err
+}
+
 func (r *LocationRepository) Search(query map[string]interface{}) ([]models.Location, error) {
 	tx := r.db.Model(&models.Location{})
 
@@ -357,11 +391,11 @@ func (r *LocationRepository) Search(query map[string]interface{}) ([]models.Loca
 }
 
 func (r *LocationRepository) Update(location *models.Location) error {
-	return r.db.Save(location).Error
+	return saveLive(r.db, location)
 }
 
 func (r *LocationRepository) Delete(id uint) error {
-	return r.db.Delete(&models.Location{}, id).Error
+	return softDelete(r.db, &models.Location{}, id)
 }
 
 // FundRepository 基金仓储
@@ -383,6 +417,12 @@ func (r *FundRepository) GetAll() ([]models.Fund, error) {
 	return funds, err
 }
 
+func (r *FundRepository) List(p ListParams) ([]models.Fund, *models.Pagination, error) {
+	var funds []models.Fund
+	page, err := paginate(r.db.Model(&models.Fund{}), p, &funds)
+	return funds, page, err
+}
+
 func (r *FundRepository) Search(query map[string]interface{}) ([]models.Fund, error) {
 	tx := r.db
 
@@ -397,12 +437,13 @@ func (r *FundRepository) Search(query map[string]interface{}) ([]models.Fund, er
 	return funds, err
 }
 
+// Update 保存基金资料；current_balance 只由过账以原子增减维护，不随资料写回
 func (r *FundRepository) Update(fund *models.Fund) error {
-	return r.db.Save(fund).Error
+	return saveLive(r.db, fund, "current_balance", "created_at")
 }
 
 func (r *FundRepository) Delete(id uint) error {
-	return r.db.Delete(&models.Fund{}, id).Error
+	return softDelete(r.db, &models.Fund{}, id)
 }
 
 // ExpenseRepository 支出仓储
@@ -424,6 +465,12 @@ func (r *ExpenseRepository) GetAll() ([]models.Expense, error) {
 	return expenses, err
 }
 
+func (r *ExpenseRepository) List(p ListParams) ([]models.Expense, *models.Pagination, error) {
+	var expenses []models.Expense
+	page, err := paginate(r.db.Model(&models.Expense{}), p, &expenses)
+	return expenses, page, err
+}
+
 func (r *ExpenseRepository) Search(query map[string]interface{}) ([]models.Expense, error) {
 	tx := r.db
 
@@ -439,11 +486,11 @@ func (r *ExpenseRepository) Search(query map[string]interface{}) ([]models.Expen
 }
 
 func (r *ExpenseRepository) Update(expense *models.Expense) error {
-	return r.db.Save(expense).Error
+	return saveLive(r.db, expense)
 }
 
 func (r *ExpenseRepository) Delete(id uint) error {
-	return r.db.Delete(&models.Expense{}, id).Error
+	return softDelete(r.db, &models.Expense{}, id)
 }
 
 // TransactionRepository 交易仓储
@@ -465,6 +512,12 @@ func (r *TransactionRepository) GetAll() ([]models.Transaction, error) {
 	return transactions, err
 }
 
+func (r *TransactionRepository) List(p ListParams) ([]models.Transaction, *models.Pagination, error) {
+	var transactions []models.Transaction
+	page, err := paginate(r.db.Model(&models.Transaction{}), p, &transactions)
+	return transactions, page, err
+}
+
 func (r *TransactionRepository) Search(query map[string]interface{}) ([]models.Transaction, error) {
 	tx := r.db.Model(&models.Transaction{})
 
@@ -480,11 +533,11 @@ func (r *TransactionRepository) Search(query map[string]interface{}) ([]models.T
 }
 
 func (r *TransactionRepository) Update(transaction *models.Transaction) error {
-	return r.db.Save(transaction).Error
+	return saveLive(r.db, transaction)
 }
 
 func (r *TransactionRepository) Delete(id uint) error {
-	return r.db.Delete(&models.Transaction{}, id).Error
+	return softDelete(r.db, &models.Transaction{}, id)
 }
 
 // PurchaseRepository 采购仓储
@@ -506,6 +559,12 @@ func (r *PurchaseRepository) GetAll() ([]models.Purchase, error) {
 	return purchases, err
 }
 
+func (r *PurchaseRepository) List(p ListParams) ([]models.Purchase, *models.Pagination, error) {
+	var purchases []models.Purchase
+	page, err := paginate(r.db.Model(&models.Purchase{}), p, &purchases)
+	return purchases, page, err
+}
+
 func (r *PurchaseRepository) Search(query map[string]interface{}) ([]models.Purchase, error) {
 	tx := r.db
 
@@ -521,11 +580,11 @@ func (r *PurchaseRepository) Search(query map[string]interface{}) ([]models.Purc
 }
 
 func (r *PurchaseRepository) Update(purchase *models.Purchase) error {
-	return r.db.Save(purchase).Error
+	return saveLive(r.db, purchase)
 }
 
 func (r *PurchaseRepository) Delete(id uint) error {
-	return r.db.Delete(&models.Purchase{}, id).Error
+	return softDelete(r.db, &models.Purchase{}, id)
 }
 
 // PayrollRepository 薪资仓储
@@ -547,6 +606,12 @@ func (r *PayrollRepository) GetAll() ([]models.Payroll, error) {
 	return payrolls, err
 }
 
+func (r *PayrollRepository) List(p ListParams) ([]models.Payroll, *models.Pagination, error) {
+	var payrolls []models.Payroll
+	page, err := paginate(r.db.Model(&models.Payroll{}), p, &payrolls)
+	return payrolls, page, err
+}
+
 func (r *PayrollRepository) Search(query map[string]interface{}) ([]models.Payroll, error) {
 	tx := r.db
 
@@ -562,11 +627,11 @@ func (r *PayrollRepository) Search(query map[string]interface{}) ([]models.Payro
 }
 
 func (r *PayrollRepository) Update(payroll *models.Payroll) error {
-	return r.db.Save(payroll).Error
+	return saveLive(r.db, payroll)
 }
 
 func (r *PayrollRepository) Delete(id uint) error {
-	return r.db.Delete(&models.Payroll{}, id).Error
+	return softDelete(r.db, &models.Payroll{}, id)
 }
 
 // InventoryRepository 库存仓储
@@ -588,6 +653,12 @@ func (r *InventoryRepository) GetAll() ([]models.Inventory, error) {
 	return inventories, err
 }
 
+func (r *InventoryRepository) List(p ListParams) ([]models.Inventory, *models.Pagination, error) {
+	var inventories []models.Inventory
+	page, err := paginate(r.db.Model(&models.Inventory{}), p, &inventories)
+	return inventories, page, err
+}
+
 func (r *InventoryRepository) Search(query map[string]interface{}) ([]models.Inventory, error) {
 	tx := r.db
 
@@ -602,12 +673,13 @@ func (r *InventoryRepository) Search(query map[string]interface{}) ([]models.Inv
 	return inventories, err
 }
 
+// Update 保存库存信息；库存数量只由库存变动调整，不随之写入
 func (r *InventoryRepository) Update(inventory *models.Inventory) error {
-	return r.db.Save(inventory).Error
+	return saveLive(r.db, inventory, "current_stock")
 }
 
 func (r *InventoryRepository) Delete(id uint) error {
-	return r.db.Delete(&models.Inventory{}, id).Error
+	return softDelete(r.db, &models.Inventory{}, id)
 }
 
 // GiftTypeRepository 礼品类型仓储
@@ -629,6 +701,12 @@ func (r *GiftTypeRepository) GetAll() ([]models.GiftType, error) {
 	return giftTypes, err
 }
 
+func (r *GiftTypeRepository) List(p ListParams) ([]models.GiftType, *models.Pagination, error) {
+	var giftTypes []models.GiftType
+	page, err := paginate(r.db.Model(&models.GiftType{}), p, &giftTypes)
+	return giftTypes, page, err
+}
+
 func (r *GiftTypeRepository) Search(query map[string]interface{}) ([]models.GiftType, error) {
 	tx := r.db.Model(&models.GiftType{})
 
@@ -644,11 +722,11 @@ func (r *GiftTypeRepository) Search(query map[string]interface{}) ([]models.Gift
 }
 
 func (r *GiftTypeRepository) Update(giftType *models.GiftType) error {
-	return r.db.Save(giftType).Error
+	return saveLive(r.db, giftType)
 }
 
 func (r *GiftTypeRepository) Delete(id uint) error {
-	return r.db.Delete(&models.GiftType{}, id).Error
+	return softDelete(r.db, &models.GiftType{}, id)
 }
 
 // GiftRepository 礼品仓储
@@ -670,6 +748,12 @@ func (r *GiftRepository) GetAll() ([]models.Gift, error) {
 	return gifts, err
 }
 
+func (r *GiftRepository) List(p ListParams) ([]models.Gift, *models.Pagination, error) {
+	var gifts []models.Gift
+	page, err := paginate(r.db.Model(&models.Gift{}), p, &gifts)
+	return gifts, page, err
+}
+
 func (r *GiftRepository) Search(query map[string]interface{}) ([]models.Gift, error) {
 	tx := r.db
 
@@ -685,11 +769,11 @@ func (r *GiftRepository) Search(query map[string]interface{}) ([]models.Gift, er
 }
 
 func (r *GiftRepository) Update(gift *models.Gift) error {
-	return r.db.Save(gift).Error
+	return saveLive(r.db, gift)
 }
 
 func (r *GiftRepository) Delete(id uint) error {
-	return r.db.Delete(&models.Gift{}, id).Error
+	return softDelete(r.db, &models.Gift{}, id)
 }
 
 // InventoryTransactionRepository 库存交易仓储
@@ -711,6 +795,12 @@ func (r *InventoryTransactionRepository) GetAll() ([]models.InventoryTransaction
 	return transactions, err
 }
 
+func (r *InventoryTransactionRepository) List(p ListParams) ([]models.InventoryTransaction, *models.Pagination, error) {
+	var transactions []models.InventoryTransaction
+	page, err := paginate(r.db.Model(&models.InventoryTransaction{}), p, &transactions)
+	return transactions, page, err
+}
+
 func (r *InventoryTransactionRepository) Search(query map[string]interface{}) ([]models.InventoryTransaction, error) {
 	tx := r.db
 
@@ -726,11 +816,11 @@ func (r *InventoryTransactionRepository) Search(query map[string]interface{}) ([
 }
 
 func (r *InventoryTransactionRepository) Update(transaction *models.InventoryTransaction) error {
-	return r.db.Save(transaction).Error
+	return saveLive(r.db, transaction)
 }
 
 func (r *InventoryTransactionRepository) Delete(id uint) error {
-	return r.db.Delete(&models.InventoryTransaction{}, id).Error
+	return softDelete(r.db, &models.InventoryTransaction{}, id)
 }
 
 // DeliveryRepository 配送仓储
@@ -752,6 +842,12 @@ func (r *DeliveryRepository) GetAll() ([]models.Delivery, error) {
 	return deliveries, err
 }
 
+func (r *DeliveryRepository) List(p ListParams) ([]models.Delivery, *models.Pagination, error) {
+	var deliveries []models.Delivery
+	page, err := paginate(r.db.Model(&models.Delivery{}), p, &deliveries)
+	return deliveries, page, err
+}
+
 func (r *DeliveryRepository) Search(query map[string]interface{}) ([]models.Delivery, error) {
 	tx := r.db
 
@@ -767,11 +863,11 @@ func (r *DeliveryRepository) Search(query map[string]interface{}) ([]models.Deli
 }
 
 func (r *DeliveryRepository) Update(delivery *models.Delivery) error {
-	return r.db.Save(delivery).Error
+	return saveLive(r.db, delivery)
 }
 
 func (r *DeliveryRepository) Delete(id uint) error {
-	return r.db.Delete(&models.Delivery{}, id).Error
+	return softDelete(r.db, &models.Delivery{}, id)
 }
 
 // VolunteerProjectRepository 志愿者-项目关联仓储
@@ -793,6 +889,12 @@ func (r *VolunteerProjectRepository) GetAll() ([]models.VolunteerProject, error)
 	return vps, err
 }
 
+func (r *VolunteerProjectRepository) List(p ListParams) ([]models.VolunteerProject, *models.Pagination, error) {
+	var vps []models.VolunteerProject
+	page, err := paginate(r.db.Model(&models.VolunteerProject{}), p, &vps)
+	return vps, page, err
+}
+
 func (r *VolunteerProjectRepository) Search(query map[string]interface{}) ([]models.VolunteerProject, error) {
 	tx := r.db
 
@@ -807,12 +909,20 @@ func (r *VolunteerProjectRepository) Search(query map[string]interface{}) ([]mod
 	return volunteerProjects, err
 }
 
+func (r *VolunteerProjectRepository) GetByID(id uint) (*models.VolunteerProject, error) {
+	var vp models.VolunteerProject
+	if err := r.db.First(&vp, id).Error; err != nil {
+		return nil, err
+	}
+	return &vp, nil
+}
+
 func (r *VolunteerProjectRepository) Update(vp *models.VolunteerProject) error {
-	return r.db.Save(vp).Error
+	return saveLive(r.db, vp)
 }
 
 func (r *VolunteerProjectRepository) Delete(id uint) error {
-	return r.db.Delete(&models.VolunteerProject{}, id).Error
+	return softDelete(r.db, &models.VolunteerProject{}, id)
 }
 
 // EmployeeProjectRepository 员工-项目关联仓储
@@ -834,6 +944,12 @@ func (r *EmployeeProjectRepository) GetAll() ([]models.EmployeeProject, error) {
 	return eps, err
 }
 
+func (r *EmployeeProjectRepository) List(p ListParams) ([]models.EmployeeProject, *models.Pagination, error) {
+	var eps []models.EmployeeProject
+	page, err := paginate(r.db.Model(&models.EmployeeProject{}), p, &eps)
+	return eps, page, err
+}
+
 func (r *EmployeeProjectRepository) Search(query map[string]interface{}) ([]models.EmployeeProject, error) {
 	tx := r.db
 
@@ -849,11 +965,11 @@ func (r *EmployeeProjectRepository) Search(query map[string]interface{}) ([]mode
 }
 
 func (r *EmployeeProjectRepository) Update(ep *models.EmployeeProject) error {
-	return r.db.Save(ep).Error
+	return saveLive(r.db, ep)
 }
 
 func (r *EmployeeProjectRepository) Delete(id uint) error {
-	return r.db.Delete(&models.EmployeeProject{}, id).Error
+	return softDelete(r.db, &models.EmployeeProject{}, id)
 }
 
 // FundProjectRepository 基金-项目关联仓储
@@ -875,6 +991,12 @@ func (r *FundProjectRepository) GetAll() ([]models.FundProject, error) {
 	return fps, err
 }
 
+func (r *FundProjectRepository) List(p ListParams) ([]models.FundProject, *models.Pagination, error) {
+	var fps []models.FundProject
+	page, err := paginate(r.db.Model(&models.FundProject{}), p, &fps)
+	return fps, page, err
+}
+
 func (r *FundProjectRepository) Search(query map[string]interface{}) ([]models.FundProject, error) {
 	tx := r.db
 
@@ -890,11 +1012,11 @@ func (r *FundProjectRepository) Search(query map[string]interface{}) ([]models.F
 }
 
 func (r *FundProjectRepository) Update(fp *models.FundProject) error {
-	return r.db.Save(fp).Error
+	return saveLive(r.db, fp)
 }
 
 func (r *FundProjectRepository) Delete(id uint) error {
-	return r.db.Delete(&models.FundProject{}, id).Error
+	return softDelete(r.db, &models.FundProject{}, id)
 }
 
 // DonationInventoryRepository 捐赠-库存关联仓储
@@ -907,7 +1029,7 @@ func NewDonationInventoryRepository(db *gorm.DB) *DonationInventoryRepository {
 }
 
 func (r *DonationInventoryRepository) Create(di *models.DonationInventory) error {
-	return r.db.Create(di).Error
+	return r.db.Omit(clause.Associations).Create(di).Error
 }
 
 func (r *DonationInventoryRepository) GetAll() ([]models.DonationInventory, error) {
@@ -916,6 +1038,12 @@ func (r *DonationInventoryRepository) GetAll() ([]models.DonationInventory, erro
 	return dis, err
 }
 
+func (r *DonationInventoryRepository) List(p ListParams) ([]models.DonationInventory, *models.Pagination, error) {
+	var dis []models.DonationInventory
+	page, err := paginate(r.db.Model(&models.DonationInventory{}), p, &dis)
+	return dis, page, err
+}
+
 func (r *DonationInventoryRepository) Search(query map[string]interface{}) ([]models.DonationInventory, error) {
 	tx := r.db
 
@@ -931,11 +1059,11 @@ func (r *DonationInventoryRepository) Search(query map[string]interface{}) ([]mo
 }
 
 func (r *DonationInventoryRepository) Update(di *models.DonationInventory) error {
-	return r.db.Save(di).Error
+	return saveLive(r.db, di, clause.Associations)
 }
 
 func (r *DonationInventoryRepository) Delete(id uint) error {
-	return r.db.Delete(&models.DonationInventory{}, id).Error
+	return softDelete(r.db, &models.DonationInventory{}, id)
 }
 
 // DeliveryInventoryRepository 配送-库存关联仓储
@@ -948,7 +1076,7 @@ func NewDeliveryInventoryRepository(db *gorm.DB) *DeliveryInventoryRepository {
 }
 
 func (r *DeliveryInventoryRepository) Create(di *models.DeliveryInventory) error {
-	return r.db.Create(di).Error
+	return r.db.Omit(clause.Associations).Create(di).Error
 }
 func (r *DeliveryInventoryRepository) GetAll() ([]models.DeliveryInventory, error) {
 	var dis []models.DeliveryInventory
@@ -956,6 +1084,12 @@ func (r *DeliveryInventoryRepository) GetAll() ([]models.DeliveryInventory, erro
 	return dis, err
 }
 
+func (r *DeliveryInventoryRepository) List(p ListParams) ([]models.DeliveryInventory, *models.Pagination, error) {
+	var dis []models.DeliveryInventory
+	page, err := paginate(r.db.Model(&models.DeliveryInventory{}), p, &dis)
+	return dis, page, err
+}
+
 func (r *DeliveryInventoryRepository) Search(query map[string]interface{}) ([]models.DeliveryInventory, error) {
 	tx := r.db
 	for key, value := range query {
@@ -969,11 +1103,11 @@ func (r *DeliveryInventoryRepository) Search(query map[string]interface{}) ([]mo
 }
 
 func (r *DeliveryInventoryRepository) Update(di *models.DeliveryInventory) error {
-	return r.db.Save(di).Error
+	return saveLive(r.db, di, clause.Associations)
 }
 
 func (r *DeliveryInventoryRepository) Delete(id uint) error {
-	return r.db.Delete(&models.DeliveryInventory{}, id).Error
+	return softDelete(r.db, &models.DeliveryInventory{}, id)
 }
 
 // ScheduleRepository 调度仓储
@@ -995,6 +1129,12 @@ func (r *ScheduleRepository) GetAll() ([]models.Schedule, error) {
 	return schedules, err
 }
 
+func (r *ScheduleRepository) List(p ListParams) ([]models.Schedule, *models.Pagination, error) {
+	var schedules []models.Schedule
+	page, err := paginate(r.db.Model(&models.Schedule{}), p, &schedules)
+	return schedules, page, err
+}
+
 func (r *ScheduleRepository) Search(query map[string]interface{}) ([]models.Schedule, error) {
 	tx := r.db
 
@@ -1009,180 +1149,230 @@ func (r *ScheduleRepository) Search(query map[string]interface{}) ([]models.Sche
 	return schedules, err
 }
 
+func (r *ScheduleRepository) GetByID(id uint) (*models.Schedule, error) {
+	var schedule models.Schedule
+	if err := r.db.First(&schedule, id).Error; err != nil {
+		return nil, err
+	}
+	return &schedule, nil
+}
+
 func (r *ScheduleRepository) Update(schedule *models.Schedule) error {
-	return r.db.Save(schedule).Error
+	return saveLive(r.db, schedule)
 }
 
 func (r *ScheduleRepository) Delete(id uint) error {
-	return r.db.Delete(&models.Schedule{}, id).Error
+	return softDelete(r.db, &models.Schedule{}, id)
 }
 
-// ------- Generic Filter methods for repositories (use applyFilters) -------
+// ------- Generic Filter methods for repositories (use applyFilter) -------
 
-func (r *DonorRepository) Filter(query map[string]interface{}, numberRange map[string][]interface{}, dateRange map[string][]string) ([]models.Donor, error) {
-	tx := r.db.Model(&models.Donor{})
-	tx = applyFilters(tx, query, numberRange, dateRange)
+func (r *DonorRepository) Filter(f Filter, p ListParams) ([]models.Donor, *models.Pagination, error) {
+	tx, err := applyFilter(r.db.Model(&models.Donor{}), f)
+	if err != nil {
+		return nil, nil, err
+	}
 	var donors []models.Donor
-	err := tx.Find(&donors).Error
-	return donors, err
+	page, err := paginate(tx, p, &donors)
+	return donors, page, err
 }
 
-func (r *DonationRepository) Filter(query map[string]interface{}, numberRange map[string][]interface{}, dateRange map[string][]string) ([]models.Donation, error) {
-	tx := r.db.Model(&models.Donation{})
-	tx = applyFilters(tx, query, numberRange, dateRange)
+func (r *DonationRepository) Filter(f Filter, p ListParams) ([]models.Donation, *models.Pagination, error) {
+	tx, err := applyFilter(r.db.Model(&models.Donation{}), f)
+	if err != nil {
+		return nil, nil, err
+	}
 	var donations []models.Donation
-	err := tx.Find(&donations).Error
-	return donations, err
+	page, err := paginate(tx, p, &donations)
+	return donations, page, err
 }
 
-func (r *VolunteerRepository) Filter(query map[string]interface{}, numberRange map[string][]interface{}, dateRange map[string][]string) ([]models.Volunteer, error) {
-	tx := r.db.Model(&models.Volunteer{})
-	tx = applyFilters(tx, query, numberRange, dateRange)
+func (r *VolunteerRepository) Filter(f Filter, p ListParams) ([]models.Volunteer, *models.Pagination, error) {
+	tx, err := applyFilter(r.db.Model(&models.Volunteer{}), f)
+	if err != nil {
+		return nil, nil, err
+	}
 	var volunteers []models.Volunteer
-	err := tx.Find(&volunteers).Error
-	return volunteers, err
+	page, err := paginate(tx, p, &volunteers)
+	return volunteers, page, err
 }
 
-func (r *EmployeeRepository) Filter(query map[string]interface{}, numberRange map[string][]interface{}, dateRange map[string][]string) ([]models.Employee, error) {
-	tx := r.db.Model(&models.Employee{})
-	tx = applyFilters(tx, query, numberRange, dateRange)
+func (r *EmployeeRepository) Filter(f Filter, p ListParams) ([]models.Employee, *models.Pagination, error) {
+	tx, err := applyFilter(r.db.Model(&models.Employee{}), f)
+	if err != nil {
+		return nil, nil, err
+	}
 	var employees []models.Employee
-	err := tx.Find(&employees).Error
-	return employees, err
+	page, err := paginate(tx, p, &employees)
+	return employees, page, err
 }
 
-func (r *LocationRepository) Filter(query map[string]interface{}, numberRange map[string][]interface{}, dateRange map[string][]string) ([]models.Location, error) {
-	tx := r.db.Model(&models.Location{})
-	tx = applyFilters(tx, query, numberRange, dateRange)
+func (r *LocationRepository) Filter(f Filter, p ListParams) ([]models.Location, *models.Pagination, error) {
+	tx, err := applyFilter(r.db.Model(&models.Location{}), f)
+	if err != nil {
+		return nil, nil, err
+	}
 	var locations []models.Location
-	err := tx.Find(&locations).Error
-	return locations, err
+	page, err := paginate(tx, p, &locations)
+	return locations, page, err
 }
 
-func (r *FundRepository) Filter(query map[string]interface{}, numberRange map[string][]interface{}, dateRange map[string][]string) ([]models.Fund, error) {
-	tx := r.db.Model(&models.Fund{})
-	tx = applyFilters(tx, query, numberRange, dateRange)
+func (r *FundRepository) Filter(f Filter, p ListParams) ([]models.Fund, *models.Pagination, error) {
+	tx, err := applyFilter(r.db.Model(&models.Fund{}), f)
+	if err != nil {
+		return nil, nil, err
+	}
 	var funds []models.Fund
-	err := tx.Find(&funds).Error
-	return funds, err
+	page, err := paginate(tx, p, &funds)
+	return funds, page, err
 }
 
-func (r *ExpenseRepository) Filter(query map[string]interface{}, numberRange map[string][]interface{}, dateRange map[string][]string) ([]models.Expense, error) {
-	tx := r.db.Model(&models.Expense{})
-	tx = applyFilters(tx, query, numberRange, dateRange)
+func (r *ExpenseRepository) Filter(f Filter, p ListParams) ([]models.Expense, *models.Pagination, error) {
+	tx, err := applyFilter(r.db.Model(&models.Expense{}), f)
+	if err != nil {
+		return nil, nil, err
+	}
 	var expenses []models.Expense
-	err := tx.Find(&expenses).Error
-	return expenses, err
+	page, err := paginate(tx, p, &expenses)
+	return expenses, page, err
 }
 
-func (r *TransactionRepository) Filter(query map[string]interface{}, numberRange map[string][]interface{}, dateRange map[string][]string) ([]models.Transaction, error) {
-	tx := r.db.Model(&models.Transaction{})
-	tx = applyFilters(tx, query, numberRange, dateRange)
+func (r *TransactionRepository) Filter(f Filter, p ListParams) ([]models.Transaction, *models.Pagination, error) {
+	tx, err := applyFilter(r.db.Model(&models.Transaction{}), f)
+	if err != nil {
+		return nil, nil, err
+	}
 	var transactions []models.Transaction
-	err := tx.Find(&transactions).Error
-	return transactions, err
+	page, err := paginate(tx, p, &transactions)
+	return transactions, page, err
 }
 
-func (r *PurchaseRepository) Filter(query map[string]interface{}, numberRange map[string][]interface{}, dateRange map[string][]string) ([]models.Purchase, error) {
-	tx := r.db.Model(&models.Purchase{})
-	tx = applyFilters(tx, query, numberRange, dateRange)
+func (r *PurchaseRepository) Filter(f Filter, p ListParams) ([]models.Purchase, *models.Pagination, error) {
+	tx, err := applyFilter(r.db.Model(&models.Purchase{}), f)
+	if err != nil {
+		return nil, nil, err
+	}
 	var purchases []models.Purchase
-	err := tx.Find(&purchases).Error
-	return purchases, err
+	page, err := paginate(tx, p, &purchases)
+	return purchases, page, err
 }
 
-func (r *PayrollRepository) Filter(query map[string]interface{}, numberRange map[string][]interface{}, dateRange map[string][]string) ([]models.Payroll, error) {
-	tx := r.db.Model(&models.Payroll{})
-	tx = applyFilters(tx, query, numberRange, dateRange)
+func (r *PayrollRepository) Filter(f Filter, p ListParams) ([]models.Payroll, *models.Pagination, error) {
+	tx, err := applyFilter(r.db.Model(&models.Payroll{}), f)
+	if err != nil {
+		return nil, nil, err
+	}
 	var payrolls []models.Payroll
-	err := tx.Find(&payrolls).Error
-	return payrolls, err
+	page, err := paginate(tx, p, &payrolls)
+	return payrolls, page, err
 }
 
-func (r *InventoryRepository) Filter(query map[string]interface{}, numberRange map[string][]interface{}, dateRange map[string][]string) ([]models.Inventory, error) {
-	tx := r.db.Model(&models.Inventory{})
-	tx = applyFilters(tx, query, numberRange, dateRange)
+func (r *InventoryRepository) Filter(f Filter, p ListParams) ([]models.Inventory, *models.Pagination, error) {
+	tx, err := applyFilter(r.db.Model(&models.Inventory{}), f)
+	if err != nil {
+		return nil, nil, err
+	}
 	var inventories []models.Inventory
-	err := tx.Find(&inventories).Error
-	return inventories, err
+	page, err := paginate(tx, p, &inventories)
+	return inventories, page, err
 }
 
-func (r *GiftTypeRepository) Filter(query map[string]interface{}, numberRange map[string][]interface{}, dateRange map[string][]string) ([]models.GiftType, error) {
-	tx := r.db.Model(&models.GiftType{})
-	tx = applyFilters(tx, query, numberRange, dateRange)
+func (r *GiftTypeRepository) Filter(f Filter, p ListParams) ([]models.GiftType, *models.Pagination, error) {
+	tx, err := applyFilter(r.db.Model(&models.GiftType{}), f)
+	if err != nil {
+		return nil, nil, err
+	}
 	var giftTypes []models.GiftType
-	err := tx.Find(&giftTypes).Error
-	return giftTypes, err
+	page, err := paginate(tx, p, &giftTypes)
+	return giftTypes, page, err
 }
 
-func (r *GiftRepository) Filter(query map[string]interface{}, numberRange map[string][]interface{}, dateRange map[string][]string) ([]models.Gift, error) {
-	tx := r.db.Model(&models.Gift{})
-	tx = applyFilters(tx, query, numberRange, dateRange)
+func (r *GiftRepository) Filter(f Filter, p ListParams) ([]models.Gift, *models.Pagination, error) {
+	tx, err := applyFilter(r.db.Model(&models.Gift{}), f)
+	if err != nil {
+		return nil, nil, err
+	}
 	var gifts []models.Gift
-	err := tx.Find(&gifts).Error
-	return gifts, err
+	page, err := paginate(tx, p, &gifts)
+	return gifts, page, err
 }
 
-func (r *InventoryTransactionRepository) Filter(query map[string]interface{}, numberRange map[string][]interface{}, dateRange map[string][]string) ([]models.InventoryTransaction, error) {
-	tx := r.db.Model(&models.InventoryTransaction{})
-	tx = applyFilters(tx, query, numberRange, dateRange)
+func (r *InventoryTransactionRepository) Filter(f Filter, p ListParams) ([]models.InventoryTransaction, *models.Pagination, error) {
+	tx, err := applyFilter(r.db.Model(&models.InventoryTransaction{}), f)
+	if err != nil {
+		return nil, nil, err
+	}
 	var transactions []models.InventoryTransaction
-	err := tx.Find(&transactions).Error
-	return transactions, err
+	page, err := paginate(tx, p, &transactions)
+	return transactions, page, err
 }
 
-func (r *DeliveryRepository) Filter(query map[string]interface{}, numberRange map[string][]interface{}, dateRange map[string][]string) ([]models.Delivery, error) {
-	tx := r.db.Model(&models.Delivery{})
-	tx = applyFilters(tx, query, numberRange, dateRange)
+func (r *DeliveryRepository) Filter(f Filter, p ListParams) ([]models.Delivery, *models.Pagination, error) {
+	tx, err := applyFilter(r.db.Model(&models.Delivery{}), f)
+	if err != nil {
+		return nil, nil, err
+	}
 	var deliveries []models.Delivery
-	err := tx.Find(&deliveries).Error
-	return deliveries, err
+	page, err := paginate(tx, p, &deliveries)
+	return deliveries, page, err
 }
 
-func (r *VolunteerProjectRepository) Filter(query map[string]interface{}, numberRange map[string][]interface{}, dateRange map[string][]string) ([]models.VolunteerProject, error) {
-	tx := r.db.Model(&models.VolunteerProject{})
-	tx = applyFilters(tx, query, numberRange, dateRange)
+func (r *VolunteerProjectRepository) Filter(f Filter, p ListParams) ([]models.VolunteerProject, *models.Pagination, error) {
+	tx, err := applyFilter(r.db.Model(&models.VolunteerProject{}), f)
+	if err != nil {
+		return nil, nil, err
+	}
 	var vps []models.VolunteerProject
-	err := tx.Find(&vps).Error
-	return vps, err
+	page, err := paginate(tx, p, &vps)
+	return vps, page, err
 }
 
-func (r *EmployeeProjectRepository) Filter(query map[string]interface{}, numberRange map[string][]interface{}, dateRange map[string][]string) ([]models.EmployeeProject, error) {
-	tx := r.db.Model(&models.EmployeeProject{})
-	tx = applyFilters(tx, query, numberRange, dateRange)
+func (r *EmployeeProjectRepository) Filter(f Filter, p ListParams) ([]models.EmployeeProject, *models.Pagination, error) {
+	tx, err := applyFilter(r.db.Model(&models.EmployeeProject{}), f)
+	if err != nil {
+		return nil, nil, err
+	}
 	var eps []models.EmployeeProject
-	err := tx.Find(&eps).Error
-	return eps, err
+	page, err := paginate(tx, p, &eps)
+	return eps, page, err
 }
 
-func (r *FundProjectRepository) Filter(query map[string]interface{}, numberRange map[string][]interface{}, dateRange map[string][]string) ([]models.FundProject, error) {
-	tx := r.db.Model(&models.FundProject{})
-	tx = applyFilters(tx, query, numberRange, dateRange)
+func (r *FundProjectRepository) Filter(f Filter, p ListParams) ([]models.FundProject, *models.Pagination, error) {
+	tx, err := applyFilter(r.db.Model(&models.FundProject{}), f)
+	if err != nil {
+		return nil, nil, err
+	}
 	var fps []models.FundProject
-	err := tx.Find(&fps).Error
-	return fps, err
+	page, err := paginate(tx, p, &fps)
+	return fps, page, err
 }
 
-func (r *DonationInventoryRepository) Filter(query map[string]interface{}, numberRange map[string][]interface{}, dateRange map[string][]string) ([]models.DonationInventory, error) {
-	tx := r.db.Model(&models.DonationInventory{})
-	tx = applyFilters(tx, query, numberRange, dateRange)
+func (r *DonationInventoryRepository) Filter(f Filter, p ListParams) ([]models.DonationInventory, *models.Pagination, error) {
+	tx, err := applyFilter(r.db.Model(&models.DonationInventory{}), f)
+	if err != nil {
+		return nil, nil, err
+	}
 	var dis []models.DonationInventory
-	err := tx.Find(&dis).Error
-	return dis, err
+	page, err := paginate(tx, p, &dis)
+	return dis, page, err
 }
 
-func (r *DeliveryInventoryRepository) Filter(query map[string]interface{}, numberRange map[string][]interface{}, dateRange map[string][]string) ([]models.DeliveryInventory, error) {
-	tx := r.db.Model(&models.DeliveryInventory{})
-	tx = applyFilters(tx, query, numberRange, dateRange)
+func (r *DeliveryInventoryRepository) Filter(f Filter, p ListParams) ([]models.DeliveryInventory, *models.Pagination, error) {
+	tx, err := applyFilter(r.db.Model(&models.DeliveryInventory{}), f)
+	if err != nil {
+		return nil, nil, err
+	}
 	var dis []models.DeliveryInventory
-	err := tx.Find(&dis).Error
-	return dis, err
+	page, err := paginate(tx, p, &dis)
+	return dis, page, err
 }
 
-func (r *ScheduleRepository) Filter(query map[string]interface{}, numberRange map[string][]interface{}, dateRange map[string][]string) ([]models.Schedule, error) {
-	tx := r.db.Model(&models.Schedule{})
-	tx = applyFilters(tx, query, numberRange, dateRange)
+func (r *ScheduleRepository) Filter(f Filter, p ListParams) ([]models.Schedule, *models.Pagination, error) {
+	tx, err := applyFilter(r.db.Model(&models.Schedule{}), f)
+	if err != nil {
+		return nil, nil, err
+	}
 	var schedules []models.Schedule
-	err := tx.Find(&schedules).Error
-	return schedules, err
+	page, err := paginate(tx, p, &schedules)
+	return schedules, page, err
 }
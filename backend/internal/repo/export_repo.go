@@ -0,0 +1,300 @@
+package repo
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// ExportColumn 导出文件中的一列：本表的列，或外键所引用记录的名称
+type ExportColumn struct {
+	Name     string // 本表列为数据库列名，关联列为外键去掉 _id 后加 _name，如 project_name
+	Resource string // 关联列引用的资源，用于检查调用者能否读取；本表列为空
+
+	field  *schema.Field // 本表列；关联列为其外键列
+	parent string        // 关联列引用的表
+	label  []string      // 被引用记录上组成名称的列
+}
+
+// ExportRepository 按过滤条件分批读取 /api/v1/dbms 下的资源，供导出使用
+type ExportRepository struct {
+	db    *gorm.DB
+	scope *ProjectScope // nil 表示不受项目范围限制
+}
+
+func NewExportRepository(db *gorm.DB) *ExportRepository {
+	return &ExportRepository{db: db}
+}
+
+// Scoped 返回只读取范围内记录的仓储副本；不按项目划分的资源不受影响
+func (r *ExportRepository) Scoped(s *ProjectScope) *ExportRepository {
+	return &ExportRepository{db: r.db, scope: s}
+}
+
+// ExportResources 返回可导出的资源名（按字母排序），即支持回收站的全部 ERP 资源
+func ExportResources() []string {
+	return TrashResources()
+}
+
+// Columns 返回资源可导出的列：本表可列出的列（与 fields 参数相同），以及 references 中
+// 各外键所引用记录的名称列
+func (r *ExportRepository) Columns(resource string) ([]ExportColumn, error) {
+	res, ok := trashResources[resource]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	sch, err := r.parse(res.model())
+	if err != nil {
+		return nil, err
+	}
+	var columns []ExportColumn
+	for _, f := range sch.Fields {
+		if f.DBName != "" && listable(sch, f.DBName) {
+			columns = append(columns, ExportColumn{Name: f.DBName, field: f})
+		}
+	}
+	for _, ref := range references {
+		if ref.Table != res.table {
+			continue
+		}
+		fk, ok := sch.FieldsByDBName[ref.Column]
+		parent := resourceOfTable(ref.Parent)
+		name := strings.TrimSuffix(ref.Column, "_id") + "_name"
+		if !ok || parent == "" || sch.FieldsByDBName[name] != nil {
+			continue
+		}
+		parentSchema, err := r.parse(trashResources[parent].model())
+		if err != nil {
+			return nil, err
+		}
+		label := labelColumns(parentSchema)
+		if len(label) == 0 {
+			continue
+		}
+		columns = append(columns, ExportColumn{Name: name, Resource: parent, field: fk, parent: ref.Parent, label: label})
+	}
+	return columns, nil
+}
+
+// FieldOwner 返回过滤或排序字段所在的资源与列名：本表列为 resource 本身，"关联.列名" 为关联记录的资源。
+// 无法解析的字段返回空资源，由查询时报告字段无效
+func (r *ExportRepository) FieldOwner(resource, field string) (string, string, error) {
+	res, ok := trashResources[resource]
+	if !ok {
+		return "", "", gorm.ErrRecordNotFound
+	}
+	relation, column, dotted := strings.Cut(field, ".")
+	if !dotted {
+		return resource, field, nil
+	}
+	sch, err := r.parse(res.model())
+	if err != nil {
+		return "", "", err
+	}
+	rel := relationByJSON(sch, relation)
+	if rel == nil {
+		return "", column, nil
+	}
+	return resourceOfTable(rel.FieldSchema.Table), column, nil
+}
+
+func (r *ExportRepository) parse(model interface{}) (*schema.Schema, error) {
+	stmt := &gorm.Statement{DB: r.db}
+	if err := stmt.Parse(model); err != nil {
+		return nil, err
+	}
+	return stmt.Schema, nil
+}
+
+// Export 按过滤条件与排序分批读取记录，每批转换为与 columns 对齐的值后交给 fn。
+// 未指定排序或只按 id 排序时按 id 游标分批，否则按偏移分批
+func (r *ExportRepository) Export(resource string, columns []ExportColumn, f Filter, sort []SortField, batch int, fn func(rows [][]interface{}) error) error {
+	res, ok := trashResources[resource]
+	if !ok {
+		return gorm.ErrRecordNotFound
+	}
+	model := res.model()
+	q := r.db.Model(model)
+	if res.column != "" {
+		q = r.scope.apply(q, res.column, res.ownerCond)
+	}
+	q, err := applyFilter(q, f)
+	if err != nil {
+		return err
+	}
+	if err := q.Statement.Parse(model); err != nil {
+		return err
+	}
+	sch := q.Statement.Schema
+	for _, s := range sort {
+		if !listable(sch, s.Column) {
+			return fmt.Errorf("%w: cannot sort by %q", ErrInvalidListParams, s.Column)
+		}
+	}
+
+	idColumn := clause.Column{Table: sch.Table, Name: "id"}
+	keyset := len(sort) == 0 || (len(sort) == 1 && sort[0].Column == "id")
+	desc := keyset && len(sort) == 1 && sort[0].Desc
+	var after interface{}
+	for offset := 0; ; offset += batch {
+		page := q.Session(&gorm.Session{})
+		if keyset {
+			if after != nil {
+				if desc {
+					page = page.Where(clause.Lt{Column: idColumn, Value: after})
+				} else {
+					page = page.Where(clause.Gt{Column: idColumn, Value: after})
+				}
+			}
+			page = page.Order(clause.OrderByColumn{Column: idColumn, Desc: desc})
+		} else {
+			for _, s := range sort {
+				page = page.Order(clause.OrderByColumn{Column: clause.Column{Table: sch.Table, Name: s.Column}, Desc: s.Desc})
+			}
+			page = page.Order(clause.OrderByColumn{Column: idColumn}).Offset(offset)
+		}
+		list := reflect.New(reflect.SliceOf(reflect.TypeOf(model).Elem()))
+		if err := page.Limit(batch).Find(list.Interface()).Error; err != nil {
+			return err
+		}
+		records := list.Elem()
+		if records.Len() == 0 {
+			return nil
+		}
+		rows, err := r.exportRows(q.Statement.Context, records, columns)
+		if err != nil {
+			return err
+		}
+		if err := fn(rows); err != nil {
+			return err
+		}
+		if records.Len() < batch {
+			return nil
+		}
+		after, _ = sch.PrioritizedPrimaryField.ValueOf(q.Statement.Context, records.Index(records.Len()-1))
+	}
+}
+
+// exportRows 取出一批记录在各列上的值（指针已解引用，空值为 nil），关联列按外键成批查出名称。
+// 被引用的记录即使已移入回收站也输出名称
+func (r *ExportRepository) exportRows(ctx context.Context, records reflect.Value, columns []ExportColumn) ([][]interface{}, error) {
+	rows := make([][]interface{}, records.Len())
+	for i := range rows {
+		rows[i] = make([]interface{}, len(columns))
+		for j, col := range columns {
+			v, _ := col.field.ValueOf(ctx, records.Index(i))
+			rows[i][j] = deref(v)
+		}
+	}
+	for j, col := range columns {
+		if col.parent == "" {
+			continue
+		}
+		var ids []interface{}
+		for _, row := range rows {
+			if row[j] != nil {
+				ids = append(ids, row[j])
+			}
+		}
+		names := make(map[string]string)
+		if len(ids) > 0 {
+			var parents []map[string]interface{}
+			err := r.db.Table(col.parent).Select(append([]string{"id"}, col.label...)).
+				Where("id IN ?", ids).Find(&parents).Error
+			if err != nil {
+				return nil, err
+			}
+			for _, p := range parents {
+				var parts []string
+				for _, l := range col.label {
+					if text := cellText(p[l]); text != "" {
+						parts = append(parts, text)
+					}
+				}
+				names[cellText(p["id"])] = strings.Join(parts, " ")
+			}
+		}
+		for _, row := range rows {
+			if name, ok := names[cellText(row[j])]; ok && name != "" {
+				row[j] = name
+			} else {
+				row[j] = nil
+			}
+		}
+	}
+	return rows, nil
+}
+
+// cellText 数据库返回值的文本形式；部分驱动以 []byte 返回文本
+func cellText(v interface{}) string {
+	switch x := v.(type) {
+	case nil:
+		return ""
+	case []byte:
+		return string(x)
+	}
+	return fmt.Sprint(v)
+}
+
+func deref(v interface{}) interface{} {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+	if !rv.IsValid() {
+		return nil
+	}
+	// gorm.DeletedAt 等可空类型按其数据库值输出
+	if valuer, ok := rv.Interface().(driver.Valuer); ok {
+		value, err := valuer.Value()
+		if err != nil {
+			return nil
+		}
+		return value
+	}
+	return rv.Interface()
+}
+
+// labelColumns 返回表示一条记录的名称列：name；first_name 与 last_name；username；
+// 都没有时使用业务编号（模型名加 ID 的字符串字段，如 Transaction.TransactionID）
+func labelColumns(sch *schema.Schema) []string {
+	has := func(names ...string) bool {
+		for _, name := range names {
+			if _, ok := sch.FieldsByDBName[name]; !ok {
+				return false
+			}
+		}
+		return true
+	}
+	switch {
+	case has("name"):
+		return []string{"name"}
+	case has("first_name", "last_name"):
+		return []string{"first_name", "last_name"}
+	case has("username"):
+		return []string{"username"}
+	}
+	if code := sch.LookUpField(sch.Name + "ID"); code != nil && code.DBName != "" && code.FieldType.Kind() == reflect.String {
+		return []string{code.DBName}
+	}
+	return nil
+}
+
+// resourceOfTable 返回表对应的路由资源名，不是 dbms 资源时为空
+func resourceOfTable(table string) string {
+	for name, res := range trashResources {
+		if res.table == table {
+			return name
+		}
+	}
+	return ""
+}
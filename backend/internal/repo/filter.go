@@ -0,0 +1,240 @@
+package repo
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// ErrInvalidFilter 过滤条件引用了不可过滤的字段，或运算符、取值无效
+var ErrInvalidFilter = errors.New("invalid filter")
+
+// 过滤运算符
+const (
+	OpEq      = "eq"
+	OpNe      = "ne"
+	OpGt      = "gt"
+	OpGte     = "gte"
+	OpLt      = "lt"
+	OpLte     = "lte"
+	OpIn      = "in"      // 取值为非空数组
+	OpLike    = "like"    // 取值为字符串，通配符由调用者给出
+	OpBetween = "between" // 取值为 [下限, 上限]，含两端
+	OpIsNull  = "is_null" // 取值为 true（为空）或 false（不为空）
+)
+
+// maxFilterDepth 条件树的最大嵌套层数
+const maxFilterDepth = 8
+
+// Filter 过滤条件树。叶子节点由 Field、Op、Value 给出一个条件；分组节点用 And 或 Or 组合子条件。
+// Field 为模型的数据库列名，或 "关联.列名"（如 project.name）表示按关联记录的列过滤。
+// 可过滤的列与 sort、fields 相同：模型的全部数据库列，json:"-" 的列除外
+type Filter struct {
+	Field string      `json:"field,omitempty"`
+	Op    string      `json:"op,omitempty"`
+	Value interface{} `json:"value,omitempty"`
+	And   []Filter    `json:"and,omitempty"`
+	Or    []Filter    `json:"or,omitempty"`
+}
+
+// IsZero 判断是否没有任何条件
+func (f Filter) IsZero() bool {
+	return f.Field == "" && f.Op == "" && len(f.And) == 0 && len(f.Or) == 0
+}
+
+// applyFilter 校验过滤条件并追加到查询；tx 需已通过 Model 指定模型
+func applyFilter(tx *gorm.DB, f Filter) (*gorm.DB, error) {
+	if f.IsZero() {
+		return tx, nil
+	}
+	if err := tx.Statement.Parse(tx.Statement.Model); err != nil {
+		return nil, err
+	}
+	expr, err := buildFilter(tx.Statement.Schema, f, 0)
+	if err != nil {
+		return nil, err
+	}
+	return tx.Where(expr), nil
+}
+
+func buildFilter(sch *schema.Schema, f Filter, depth int) (clause.Expression, error) {
+	if depth > maxFilterDepth {
+		return nil, fmt.Errorf("%w: conditions are nested more than %d levels deep", ErrInvalidFilter, maxFilterDepth)
+	}
+	group := f.And
+	switch {
+	case f.Field != "" || f.Op != "":
+		if len(f.And) > 0 || len(f.Or) > 0 {
+			return nil, fmt.Errorf("%w: a condition cannot also contain and/or groups", ErrInvalidFilter)
+		}
+		return buildCondition(sch, f)
+	case len(f.And) > 0 && len(f.Or) > 0:
+		return nil, fmt.Errorf("%w: use separate groups for and and or", ErrInvalidFilter)
+	case len(f.Or) > 0:
+		group = f.Or
+	case len(f.And) == 0:
+		return nil, fmt.Errorf("%w: empty condition", ErrInvalidFilter)
+	}
+	exprs := make([]clause.Expression, len(group))
+	for i, child := range group {
+		expr, err := buildFilter(sch, child, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		exprs[i] = expr
+	}
+	if len(f.Or) > 0 {
+		return clause.Or(exprs...), nil
+	}
+	return clause.And(exprs...), nil
+}
+
+// buildCondition 构造单个条件；关联字段的条件放在 EXISTS 子查询中，不会因一对多关联产生重复行
+func buildCondition(sch *schema.Schema, f Filter) (clause.Expression, error) {
+	parts := strings.Split(f.Field, ".")
+	switch len(parts) {
+	case 1:
+		if !listable(sch, f.Field) {
+			return nil, fmt.Errorf("%w: unknown field %q", ErrInvalidFilter, f.Field)
+		}
+		return compare(clause.Column{Table: sch.Table, Name: f.Field}, f)
+	case 2:
+	default:
+		return nil, fmt.Errorf("%w: field %q: only one level of related fields is supported", ErrInvalidFilter, f.Field)
+	}
+
+	rel := relationByJSON(sch, parts[0])
+	if rel == nil {
+		return nil, fmt.Errorf("%w: unknown relation %q in field %q", ErrInvalidFilter, parts[0], f.Field)
+	}
+	related := rel.FieldSchema
+	if !listable(related, parts[1]) {
+		return nil, fmt.Errorf("%w: unknown field %q", ErrInvalidFilter, f.Field)
+	}
+	// 子查询的表使用别名，自关联（同一张表）时也不会混淆
+	alias := "rel_" + parts[0]
+	cond, err := compare(clause.Column{Table: alias, Name: parts[1]}, f)
+	if err != nil {
+		return nil, err
+	}
+	conds := []clause.Expression{cond}
+	for _, ref := range rel.References {
+		if ref.OwnPrimaryKey {
+			conds = append(conds, clause.Eq{
+				Column: clause.Column{Table: alias, Name: ref.ForeignKey.DBName},
+				Value:  clause.Column{Table: sch.Table, Name: ref.PrimaryKey.DBName},
+			})
+		} else {
+			conds = append(conds, clause.Eq{
+				Column: clause.Column{Table: alias, Name: ref.PrimaryKey.DBName},
+				Value:  clause.Column{Table: sch.Table, Name: ref.ForeignKey.DBName},
+			})
+		}
+	}
+	if _, ok := related.FieldsByDBName["deleted_at"]; ok {
+		conds = append(conds, clause.Eq{Column: clause.Column{Table: alias, Name: "deleted_at"}, Value: nil})
+	}
+	return clause.Expr{
+		SQL:  "EXISTS (SELECT 1 FROM ? WHERE ?)",
+		Vars: []interface{}{clause.Table{Name: related.Table, Alias: alias}, clause.And(conds...)},
+	}, nil
+}
+
+// relationByJSON 按 JSON 名称查找可用于过滤的关联；多对多与多态关联不支持
+func relationByJSON(sch *schema.Schema, name string) *schema.Relationship {
+	for _, rel := range sch.Relationships.Relations {
+		jsonName := strings.Split(rel.Field.Tag.Get("json"), ",")[0]
+		if jsonName != name || rel.FieldSchema == nil || rel.JoinTable != nil || rel.Polymorphic != nil {
+			continue
+		}
+		return rel
+	}
+	return nil
+}
+
+// compare 按运算符构造列上的条件，并校验取值的形式
+func compare(col clause.Column, f Filter) (clause.Expression, error) {
+	invalid := func(format string, args ...interface{}) error {
+		return fmt.Errorf("%w: field %q: %s", ErrInvalidFilter, f.Field, fmt.Sprintf(format, args...))
+	}
+	switch f.Op {
+	case OpEq, OpNe, OpGt, OpGte, OpLt, OpLte:
+		v, ok := scalar(f.Value)
+		if !ok {
+			return nil, invalid("%s needs a string, number or boolean value", f.Op)
+		}
+		switch f.Op {
+		case OpEq:
+			return clause.Eq{Column: col, Value: v}, nil
+		case OpNe:
+			return clause.Neq{Column: col, Value: v}, nil
+		case OpGt:
+			return clause.Gt{Column: col, Value: v}, nil
+		case OpGte:
+			return clause.Gte{Column: col, Value: v}, nil
+		case OpLt:
+			return clause.Lt{Column: col, Value: v}, nil
+		}
+		return clause.Lte{Column: col, Value: v}, nil
+	case OpLike:
+		s, ok := f.Value.(string)
+		if !ok {
+			return nil, invalid("like needs a string value")
+		}
+		return clause.Like{Column: col, Value: s}, nil
+	case OpIn:
+		list, ok := f.Value.([]interface{})
+		if !ok || len(list) == 0 {
+			return nil, invalid("in needs a non-empty array value")
+		}
+		values := make([]interface{}, len(list))
+		for i, item := range list {
+			if values[i], ok = scalar(item); !ok {
+				return nil, invalid("in values must be strings, numbers or booleans")
+			}
+		}
+		return clause.IN{Column: col, Values: values}, nil
+	case OpBetween:
+		list, ok := f.Value.([]interface{})
+		if !ok || len(list) != 2 {
+			return nil, invalid("between needs a [low, high] array value")
+		}
+		low, okLow := scalar(list[0])
+		high, okHigh := scalar(list[1])
+		if !okLow || !okHigh {
+			return nil, invalid("between bounds must be strings or numbers")
+		}
+		return clause.Expr{SQL: "? BETWEEN ? AND ?", Vars: []interface{}{col, low, high}}, nil
+	case OpIsNull:
+		isNull, ok := f.Value.(bool)
+		if !ok {
+			return nil, invalid("is_null needs true or false")
+		}
+		if isNull {
+			return clause.Eq{Column: col, Value: nil}, nil
+		}
+		return clause.Neq{Column: col, Value: nil}, nil
+	case "":
+		return nil, invalid("missing op")
+	}
+	return nil, invalid("unknown op %q", f.Op)
+}
+
+// scalar 接受 JSON 解码得到的字符串、数字与布尔值；整数值转换为 int64，便于与整数列比较
+func scalar(v interface{}) (interface{}, bool) {
+	switch x := v.(type) {
+	case string, bool, int, int64, uint, uint64:
+		return x, true
+	case float64:
+		if x == math.Trunc(x) && math.Abs(x) < 1<<53 {
+			return int64(x), true
+		}
+		return x, true
+	}
+	return nil, false
+}
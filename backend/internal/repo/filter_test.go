@@ -0,0 +1,99 @@
+package repo
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"erp-backend/internal/models"
+
+	"gorm.io/gorm/schema"
+)
+
+// 登记的每个外键在模型上都应是多对一关联；被引用的模型带有同名业务编号字段时，
+// 缺少 belongsTo 标记的关联会被 gorm 猜成一对一，过滤、导出与 Preload 都会连错列
+func TestRelationsFollowRegisteredForeignKeys(t *testing.T) {
+	cache := &sync.Map{}
+	for _, ref := range references {
+		model, ok := softDeleteModels[ref.Table]
+		if !ok {
+			continue
+		}
+		sch, err := schema.Parse(model(), cache, schema.NamingStrategy{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, rel := range sch.Relationships.Relations {
+			if rel.FieldSchema == nil || rel.FieldSchema.Table != ref.Parent || rel.JoinTable != nil {
+				continue
+			}
+			for _, r := range rel.References {
+				if r.ForeignKey.DBName == ref.Column && r.ForeignKey.Schema == sch && rel.Type != schema.BelongsTo {
+					t.Errorf("%s.%s (%s.%s -> %s) is parsed as %s, want belongs_to", sch.Name, rel.Name, ref.Table, ref.Column, ref.Parent, rel.Type)
+				}
+			}
+		}
+		for _, rel := range sch.Relationships.Relations {
+			if rel.FieldSchema == nil || rel.JoinTable != nil || rel.Type != schema.HasOne {
+				continue
+			}
+			for _, r := range rel.References {
+				if r.ForeignKey.FieldType != r.PrimaryKey.FieldType && r.ForeignKey.IndirectFieldType != r.PrimaryKey.IndirectFieldType {
+					t.Errorf("%s.%s joins %s.%s to %s.%s", sch.Name, rel.Name,
+						r.ForeignKey.Schema.Table, r.ForeignKey.DBName, r.PrimaryKey.Schema.Table, r.PrimaryKey.DBName)
+				}
+			}
+		}
+	}
+}
+
+func TestFilterAndPreloadManyToOne(t *testing.T) {
+	db := openMigratedDB(t)
+	wells := models.Project{ProjectID: "PRJ-1", Name: "Wells"}
+	school := models.Project{ProjectID: "PRJ-2", Name: "School"}
+	donor := models.Donor{DonorID: "DNR-1", FirstName: "Ada", LastName: "Lovelace"}
+	for _, v := range []interface{}{&wells, &school, &donor} {
+		if err := db.Create(v).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i, project := range []*models.Project{&wells, &school, &school} {
+		donation := models.Donation{
+			DonationID: "DON-" + string(rune('A'+i)), DonorID: &donor.ID, ProjectID: &project.ID, Amount: 10,
+			DonationType: "one-time", Category: "general", DonationDate: time.Now().UTC(),
+		}
+		if err := db.Create(&donation).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	donations := NewDonationRepository(db)
+	for _, tt := range []struct {
+		filter Filter
+		want   int
+	}{
+		{Filter{Field: "project.name", Op: OpEq, Value: "School"}, 2},
+		{Filter{Field: "project.name", Op: OpEq, Value: "Wells"}, 1},
+		{Filter{Field: "project.project_id", Op: OpEq, Value: "PRJ-1"}, 1},
+		{Filter{Field: "donor.last_name", Op: OpEq, Value: "Lovelace"}, 3},
+		{Filter{Field: "project.name", Op: OpEq, Value: "Clinic"}, 0},
+	} {
+		got, _, err := donations.Filter(tt.filter, ListParams{Page: 1, PageSize: 50})
+		if err != nil {
+			t.Fatalf("Filter(%s = %v): %v", tt.filter.Field, tt.filter.Value, err)
+		}
+		if len(got) != tt.want {
+			t.Errorf("Filter(%s = %v) returned %d donations, want %d", tt.filter.Field, tt.filter.Value, len(got), tt.want)
+		}
+	}
+
+	var loaded []models.Donation
+	if err := db.Preload("Project").Order("id").Find(&loaded).Error; err != nil {
+		t.Fatal(err)
+	}
+	for i, want := range []string{"Wells", "School", "School"} {
+		if loaded[i].Project == nil || loaded[i].Project.Name != want {
+			t.Errorf("donation %s preloaded project %+v, want %s", loaded[i].DonationID, loaded[i].Project, want)
+		}
+	}
+}
@@ -0,0 +1,188 @@
+package repo
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// fkClause 建表语句中的外键约束子句
+var fkClause = regexp.MustCompile("(?i),\\s*CONSTRAINT\\s+`?\\w+`?\\s+FOREIGN KEY\\s*\\([^)]*\\)\\s*REFERENCES\\s*`?\\w+`?\\s*\\([^)]*\\)(\\s+ON\\s+(DELETE|UPDATE)\\s+(SET\\s+NULL|SET\\s+DEFAULT|CASCADE|RESTRICT|NO\\s+ACTION))*")
+
+// onDeleteAction 删除策略对应的 SQL 外键动作
+var onDeleteAction = map[string]string{
+	OnDeleteRestrict: "RESTRICT",
+	OnDeleteCascade:  "CASCADE",
+	OnDeleteNullify:  "SET NULL",
+}
+
+// foreignKey 表上的一个外键：列、被引用表与删除动作
+type foreignKey struct {
+	column, parent, onDelete string
+}
+
+// syncForeignKeys 使 SQLite 各表的外键约束与 references 一致。SQLite 不能修改已有表的约束，
+// 约束不一致的表按官方推荐的步骤重建：关闭外键检查，新建表、复制数据、替换原表并重建索引
+func syncForeignKeys(db *gorm.DB) error {
+	if db.Dialector.Name() != DriverSQLite {
+		return nil
+	}
+	var tables []string
+	if err := db.Raw("SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%'").
+		Scan(&tables).Error; err != nil {
+		return err
+	}
+	want := make(map[string][]foreignKey)
+	for _, ref := range references {
+		want[ref.Table] = append(want[ref.Table], foreignKey{ref.Column, ref.Parent, onDeleteAction[ref.Policy]})
+	}
+	var stale []string
+	for _, table := range tables {
+		have, err := tableForeignKeys(db, table)
+		if err != nil {
+			return err
+		}
+		if want[table], err = existingColumns(db, table, want[table]); err != nil {
+			return err
+		}
+		if !sameForeignKeys(have, want[table]) {
+			stale = append(stale, table)
+		}
+	}
+	if len(stale) == 0 {
+		return nil
+	}
+	err := db.Connection(func(conn *gorm.DB) error {
+		// foreign_keys 在事务内设置无效，必须在同一连接上于事务外关闭
+		if err := conn.Exec("PRAGMA foreign_keys = OFF").Error; err != nil {
+			return err
+		}
+		defer conn.Exec("PRAGMA foreign_keys = ON")
+		return conn.Transaction(func(tx *gorm.DB) error {
+			for _, table := range stale {
+				if err := rebuildTable(tx, table, want[table]); err != nil {
+					return fmt.Errorf("failed to rebuild %s: %w", table, err)
+				}
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return err
+	}
+	log.Printf("Foreign keys updated on %d tables", len(stale))
+	return reportDanglingRows(db)
+}
+
+// tableForeignKeys 读出表上现有的外键
+func tableForeignKeys(db *gorm.DB, table string) ([]foreignKey, error) {
+	var rows []struct {
+		Table    string
+		From     string
+		OnDelete string `gorm:"column:on_delete"`
+	}
+	if err := db.Raw(fmt.Sprintf("PRAGMA foreign_key_list(`%s`)", table)).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	keys := make([]foreignKey, len(rows))
+	for i, row := range rows {
+		keys[i] = foreignKey{row.From, row.Table, strings.ToUpper(row.OnDelete)}
+	}
+	return keys, nil
+}
+
+// existingColumns 去掉表上还没有的列的外键；这些列由尚未执行的迁移添加，约束随迁移一起建立
+func existingColumns(db *gorm.DB, table string, keys []foreignKey) ([]foreignKey, error) {
+	var columns []string
+	if err := db.Raw("SELECT name FROM pragma_table_info(?)", table).Scan(&columns).Error; err != nil {
+		return nil, err
+	}
+	present := make(map[string]bool, len(columns))
+	for _, c := range columns {
+		present[c] = true
+	}
+	var out []foreignKey
+	for _, k := range keys {
+		if present[k.column] {
+			out = append(out, k)
+		}
+	}
+	return out, nil
+}
+
+func sameForeignKeys(a, b []foreignKey) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	key := func(k foreignKey) string { return k.column + "|" + k.parent + "|" + k.onDelete }
+	ka := make([]string, len(a))
+	kb := make([]string, len(b))
+	for i := range a {
+		ka[i], kb[i] = key(a[i]), key(b[i])
+	}
+	sort.Strings(ka)
+	sort.Strings(kb)
+	return strings.Join(ka, ",") == strings.Join(kb, ",")
+}
+
+// rebuildTable 以新的外键约束重建表，保留数据与索引
+func rebuildTable(tx *gorm.DB, table string, keys []foreignKey) error {
+	var ddl string
+	if err := tx.Raw("SELECT sql FROM sqlite_master WHERE type = 'table' AND name = ?", table).Scan(&ddl).Error; err != nil {
+		return err
+	}
+	var indexes []string
+	if err := tx.Raw("SELECT sql FROM sqlite_master WHERE type IN ('index', 'trigger') AND tbl_name = ? AND sql IS NOT NULL", table).
+		Scan(&indexes).Error; err != nil {
+		return err
+	}
+	body := fkClause.ReplaceAllString(ddl, "")
+	end := strings.LastIndex(body, ")")
+	if end < 0 {
+		return fmt.Errorf("unexpected table definition: %s", ddl)
+	}
+	var constraints strings.Builder
+	for _, k := range keys {
+		fmt.Fprintf(&constraints, ",CONSTRAINT `fk_%s_%s` FOREIGN KEY (`%s`) REFERENCES `%s`(`id`) ON DELETE %s",
+			table, k.column, k.column, k.parent, k.onDelete)
+	}
+	tmp := table + "__fk"
+	start := strings.Index(body, "(")
+	create := fmt.Sprintf("CREATE TABLE `%s` %s%s)", tmp, body[start:end], constraints.String())
+	for _, sql := range []string{
+		create,
+		fmt.Sprintf("INSERT INTO `%s` SELECT * FROM `%s`", tmp, table),
+		fmt.Sprintf("DROP TABLE `%s`", table),
+		fmt.Sprintf("ALTER TABLE `%s` RENAME TO `%s`", tmp, table),
+	} {
+		if err := tx.Exec(sql).Error; err != nil {
+			return err
+		}
+	}
+	for _, sql := range indexes {
+		if err := tx.Exec(sql).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reportDanglingRows 记录重建前已存在的、引用了不存在的行的记录；这些记录需要人工修正
+func reportDanglingRows(db *gorm.DB) error {
+	var rows []struct {
+		Table  string
+		Rowid  int64
+		Parent string
+	}
+	if err := db.Raw("PRAGMA foreign_key_check").Scan(&rows).Error; err != nil {
+		return err
+	}
+	for _, row := range rows {
+		log.Printf("Warning: %s row %d references a missing %s row", row.Table, row.Rowid, row.Parent)
+	}
+	return nil
+}
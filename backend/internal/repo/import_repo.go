@@ -0,0 +1,116 @@
+package repo
+
+import (
+	"erp-backend/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// existsChunk 按 id 检查引用是否存在时每条查询的 id 数
+const existsChunk = 500
+
+// ImportRepository 批量导入的映射模板、导入记录与逐行校验所需的查询
+type ImportRepository struct {
+	db *gorm.DB
+}
+
+func NewImportRepository(db *gorm.DB) *ImportRepository {
+	return &ImportRepository{db: db}
+}
+
+// Fields 返回模型中可导入的列（与排序、字段选择可用的列相同），按模型中的声明顺序
+func (r *ImportRepository) Fields(model interface{}) ([]*schema.Field, error) {
+	stmt := &gorm.Statement{DB: r.db}
+	if err := stmt.Parse(model); err != nil {
+		return nil, err
+	}
+	var fields []*schema.Field
+	for _, f := range stmt.Schema.Fields {
+		if f.DBName != "" && listable(stmt.Schema, f.DBName) {
+			fields = append(fields, f)
+		}
+	}
+	return fields, nil
+}
+
+// ParentTables 返回表上各外键列引用的表，按 references 声明
+func ParentTables(table string) map[string]string {
+	parents := make(map[string]string)
+	for _, ref := range references {
+		if ref.Table == table {
+			parents[ref.Column] = ref.Parent
+		}
+	}
+	return parents
+}
+
+// ExistingIDs 返回 ids 中在 table 里存在的行；回收站中的行视为不存在
+func (r *ImportRepository) ExistingIDs(table string, ids []uint) (map[uint]bool, error) {
+	found := make(map[uint]bool, len(ids))
+	for start := 0; start < len(ids); start += existsChunk {
+		end := start + existsChunk
+		if end > len(ids) {
+			end = len(ids)
+		}
+		q := r.db.Table(table)
+		if model, ok := softDeleteModels[table]; ok {
+			q = r.db.Model(model())
+		}
+		var hits []uint
+		if err := q.Where("id IN ?", ids[start:end]).Pluck("id", &hits).Error; err != nil {
+			return nil, err
+		}
+		for _, id := range hits {
+			found[id] = true
+		}
+	}
+	return found, nil
+}
+
+func (r *ImportRepository) ListMappings(resource string) ([]models.ImportMapping, error) {
+	var mappings []models.ImportMapping
+	err := r.db.Where("resource = ?", resource).Order("name").Find(&mappings).Error
+	return mappings, err
+}
+
+func (r *ImportRepository) GetMapping(resource, name string) (*models.ImportMapping, error) {
+	var mapping models.ImportMapping
+	err := r.db.Where("resource = ? AND name = ?", resource, name).First(&mapping).Error
+	return &mapping, err
+}
+
+// SaveMapping 保存映射模板，同一资源下同名的模板被替换
+func (r *ImportRepository) SaveMapping(mapping *models.ImportMapping) error {
+	existing, err := r.GetMapping(mapping.Resource, mapping.Name)
+	switch {
+	case err == gorm.ErrRecordNotFound:
+		return r.db.Create(mapping).Error
+	case err != nil:
+		return err
+	}
+	mapping.ID = existing.ID
+	mapping.CreatedAt = existing.CreatedAt
+	return r.db.Save(mapping).Error
+}
+
+func (r *ImportRepository) DeleteMapping(resource string, id uint) error {
+	result := r.db.Where("resource = ?", resource).Delete(&models.ImportMapping{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *ImportRepository) CreateJob(job *models.ImportJob) error {
+	return r.db.Create(job).Error
+}
+
+func (r *ImportRepository) GetJob(resource string, id uint) (*models.ImportJob, error) {
+	var job models.ImportJob
+	err := r.db.Where("resource = ?", resource).First(&job, id).Error
+	return &job, err
+}
@@ -0,0 +1,392 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/mattn/go-sqlite3"
+	"gorm.io/gorm"
+)
+
+// 删除被引用的行时对引用行的处理策略
+const (
+	OnDeleteRestrict = "restrict" // 存在引用时拒绝删除
+	OnDeleteCascade  = "cascade"  // 一并删除引用行
+	OnDeleteNullify  = "nullify"  // 把引用列置空
+)
+
+// Reference 一条外键关系：Table.Column 引用 Parent.id，删除 Parent 的行时按 Policy 处理引用行
+type Reference struct {
+	Table  string
+	Column string
+	Parent string
+	Policy string
+}
+
+// references 全部外键关系；数据库层面的外键约束与删除检查都以此为准
+var references = []Reference{
+	// 用户
+	{"donors", "user_id", "users", OnDeleteNullify},
+	{"volunteers", "user_id", "users", OnDeleteNullify},
+	{"employees", "user_id", "users", OnDeleteNullify},
+	{"user_roles", "user_id", "users", OnDeleteCascade},
+	{"sessions", "user_id", "users", OnDeleteCascade},
+	{"refresh_tokens", "session_id", "sessions", OnDeleteCascade},
+	{"password_reset_tokens", "user_id", "users", OnDeleteCascade},
+	{"login_attempts", "user_id", "users", OnDeleteNullify},
+	{"account_lockouts", "user_id", "users", OnDeleteCascade},
+	{"two_factors", "user_id", "users", OnDeleteCascade},
+	{"recovery_codes", "user_id", "users", OnDeleteCascade},
+	{"login_challenges", "user_id", "users", OnDeleteCascade},
+	{"registration_reviews", "user_id", "users", OnDeleteCascade},
+	{"expense_approvals", "user_id", "users", OnDeleteRestrict},
+	{"expenses", "created_by", "users", OnDeleteNullify},
+
+	// 角色与权限
+	{"user_roles", "role_id", "roles", OnDeleteCascade},
+	{"role_permissions", "role_id", "roles", OnDeleteCascade},
+	{"role_permissions", "permission_id", "permissions", OnDeleteCascade},
+
+	// 地点
+	{"projects", "location_id", "locations", OnDeleteNullify},
+	{"volunteers", "location_id", "locations", OnDeleteNullify},
+	{"employees", "location_id", "locations", OnDeleteNullify},
+	{"inventories", "location_id", "locations", OnDeleteNullify},
+	{"deliveries", "location_id", "locations", OnDeleteNullify},
+
+	// 项目
+	{"donations", "project_id", "projects", OnDeleteRestrict},
+	{"expenses", "project_id", "projects", OnDeleteRestrict},
+	{"fund_projects", "project_id", "projects", OnDeleteRestrict},
+	{"funds", "project_id", "projects", OnDeleteNullify},
+	{"donation_inventories", "project_id", "projects", OnDeleteNullify},
+	{"deliveries", "project_id", "projects", OnDeleteNullify},
+	{"schedules", "project_id", "projects", OnDeleteCascade},
+	{"employee_projects", "project_id", "projects", OnDeleteCascade},
+	{"volunteer_projects", "project_id", "projects", OnDeleteCascade},
+	{"journal_lines", "project_id", "projects", OnDeleteRestrict},
+
+	// 捐赠者、志愿者与员工
+	{"donations", "donor_id", "donors", OnDeleteRestrict},
+	{"funds", "donor_id", "donors", OnDeleteNullify},
+	{"donation_inventories", "donor_id", "donors", OnDeleteRestrict},
+	{"volunteer_projects", "volunteer_id", "volunteers", OnDeleteCascade},
+	{"employee_projects", "employee_id", "employees", OnDeleteCascade},
+	{"payrolls", "employee_id", "employees", OnDeleteRestrict},
+	{"expenses", "employee_id", "employees", OnDeleteRestrict},
+	{"expense_approvals", "employee_id", "employees", OnDeleteRestrict},
+	{"registration_reviews", "employee_id", "employees", OnDeleteCascade},
+	{"tax_receipts", "donor_id", "donors", OnDeleteRestrict},
+
+	// 基金
+	{"donations", "fund_id", "funds", OnDeleteRestrict},
+	{"expenses", "fund_id", "funds", OnDeleteRestrict},
+	{"fund_projects", "fund_id", "funds", OnDeleteRestrict},
+	{"journal_lines", "fund_id", "funds", OnDeleteRestrict},
+
+	// 交易记录
+	{"donations", "transaction_id", "transactions", OnDeleteRestrict},
+	{"expenses", "transaction_id", "transactions", OnDeleteRestrict},
+	{"purchases", "transaction_id", "transactions", OnDeleteRestrict},
+	{"payrolls", "transaction_id", "transactions", OnDeleteRestrict},
+	{"fund_projects", "transaction_id", "transactions", OnDeleteRestrict},
+	{"funds", "transaction_id", "transactions", OnDeleteNullify},
+	{"journal_entries", "transaction_id", "transactions", OnDeleteRestrict},
+
+	// 单据明细
+	{"gifts", "donation_id", "donations", OnDeleteCascade},
+	{"expense_approvals", "expense_id", "expenses", OnDeleteCascade},
+	{"inventories", "purchase_id", "purchases", OnDeleteNullify},
+	{"inventory_transactions", "purchase_id", "purchases", OnDeleteNullify},
+	{"in_kind_valuations", "donation_inventory_id", "donation_inventories", OnDeleteCascade},
+
+	// 库存与礼品
+	{"inventory_transactions", "to_inventory_id", "inventories", OnDeleteRestrict},
+	{"inventory_transactions", "from_inventory_id", "inventories", OnDeleteRestrict},
+	{"donation_inventories", "inventory_id", "inventories", OnDeleteRestrict},
+	{"delivery_inventories", "inventory_id", "inventories", OnDeleteRestrict},
+	{"gifts", "gift_type_id", "gift_types", OnDeleteRestrict},
+	{"gifts", "delivery_id", "deliveries", OnDeleteNullify},
+	{"delivery_inventories", "delivery_id", "deliveries", OnDeleteCascade},
+
+	// 总账
+	{"journal_lines", "journal_entry_id", "journal_entries", OnDeleteCascade},
+	{"journal_lines", "account_id", "accounts", OnDeleteRestrict},
+}
+
+// References 返回全部外键关系
+func References() []Reference {
+	return append([]Reference(nil), references...)
+}
+
+// softDeleteModels 支持软删除的表及其模型，由 trashResources 得出
+var softDeleteModels = func() map[string]func() interface{} {
+	m := make(map[string]func() interface{}, len(trashResources))
+	for _, res := range trashResources {
+		m[res.table] = res.model
+	}
+	return m
+}()
+
+// Dependent 阻止删除的一类引用行
+type Dependent struct {
+	Table  string `json:"table"`
+	Column string `json:"column"`
+	Count  int    `json:"count"`
+}
+
+// DependentsError 仍有记录按 restrict 策略引用待删除的行，删除被拒绝
+type DependentsError struct {
+	Table      string
+	ID         uint
+	Dependents []Dependent
+}
+
+func (e *DependentsError) Error() string {
+	parts := make([]string, len(e.Dependents))
+	for i, d := range e.Dependents {
+		parts[i] = fmt.Sprintf("%d %s.%s", d.Count, d.Table, d.Column)
+	}
+	return fmt.Sprintf("%s %d is still referenced by %s", e.Table, e.ID, strings.Join(parts, ", "))
+}
+
+// foreignKeyErrors 各数据库外键约束错误信息中的特征文本
+var foreignKeyErrors = []string{
+	"FOREIGN KEY constraint failed",   // SQLite
+	"violates foreign key constraint", // PostgreSQL
+	"a foreign key constraint fails",  // MySQL
+}
+
+// IsForeignKeyViolation 判断错误是否由数据库外键约束引起（写入了不存在的引用）。
+// 优先按驱动的错误码判断，错误码不可用时（如错误已被格式化为文本）按错误信息判断
+func IsForeignKeyViolation(err error) bool {
+	if err == nil {
+		return false
+	}
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) {
+		return sqliteErr.ExtendedCode == sqlite3.ErrConstraintForeignKey
+	}
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code == "23503" // foreign_key_violation
+	}
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return mysqlErr.Number == 1451 || mysqlErr.Number == 1452 // 被引用的行不能删除、引用的行不存在
+	}
+	for _, text := range foreignKeyErrors {
+		if strings.Contains(err.Error(), text) {
+			return true
+		}
+	}
+	return false
+}
+
+type forceCascadeKey struct{}
+
+// WithForceCascade 返回要求强制级联删除的 context：按 restrict 策略引用的记录不再阻止删除，而是一并删除
+func WithForceCascade(ctx context.Context) context.Context {
+	return context.WithValue(ctx, forceCascadeKey{}, true)
+}
+
+func forceCascade(db *gorm.DB) bool {
+	force, _ := db.Statement.Context.Value(forceCascadeKey{}).(bool)
+	return force
+}
+
+// ForceDeleter 强制级联删除时删除一条引用记录；过账类单据需要先冲销余额与总账
+type ForceDeleter func(tx *Tx, id uint) error
+
+// forceDeleters 各表的强制删除函数，由服务层注册；未注册的表直接软删除
+var forceDeleters = map[string]ForceDeleter{}
+
+// RegisterForceDeleter 注册某表在强制级联删除时使用的删除函数
+func RegisterForceDeleter(table string, fn ForceDeleter) {
+	forceDeleters[table] = fn
+}
+
+// tableOf 返回模型对应的表名
+func tableOf(db *gorm.DB, model interface{}) (string, error) {
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(model); err != nil {
+		return "", err
+	}
+	return stmt.Schema.Table, nil
+}
+
+// softDeleteRow 把一行移入回收站，并按引用策略处理引用它的未删除行：cascade 的一并移入回收站
+// （删除时间相同，恢复时一并恢复），nullify 的置空，restrict 的阻止删除；
+// 行不存在或已在回收站时不做任何修改
+func softDeleteRow(db *gorm.DB, table string, id uint) error {
+	var live int64
+	if err := db.Model(softDeleteModels[table]()).Where("id = ?", id).Count(&live).Error; err != nil {
+		return err
+	}
+	if live == 0 {
+		return nil
+	}
+	columns := softDeleteColumns(db)
+	return db.Transaction(func(tx *gorm.DB) error {
+		blockers, err := softDeleteTree(tx, table, []uint{id}, columns)
+		if err != nil {
+			return err
+		}
+		if len(blockers) > 0 {
+			return &DependentsError{Table: table, ID: id, Dependents: blockers}
+		}
+		return nil
+	})
+}
+
+// softDeleteTree 处理引用 table 中 ids 行的记录后把这些行移入回收站，返回阻止删除的引用；
+// 不支持软删除的引用表（总账、会话等）在软删除时不受影响，彻底删除时再处理
+func softDeleteTree(tx *gorm.DB, table string, ids []uint, columns map[string]interface{}) ([]Dependent, error) {
+	var blockers []Dependent
+	for _, ref := range references {
+		model, ok := softDeleteModels[ref.Table]
+		if ref.Parent != table || !ok {
+			continue
+		}
+		q := tx.Model(model()).Where(ref.Column+" IN ?", ids)
+		if ref.Policy == OnDeleteNullify {
+			if err := q.UpdateColumn(ref.Column, nil).Error; err != nil {
+				return nil, fmt.Errorf("failed to clear %s.%s: %w", ref.Table, ref.Column, err)
+			}
+			continue
+		}
+		var childIDs []uint
+		if err := q.Pluck("id", &childIDs).Error; err != nil {
+			return nil, err
+		}
+		if len(childIDs) == 0 {
+			continue
+		}
+		if ref.Policy == OnDeleteRestrict && !forceCascade(tx) {
+			blockers = append(blockers, Dependent{Table: ref.Table, Column: ref.Column, Count: len(childIDs)})
+			continue
+		}
+		if del, ok := forceDeleters[ref.Table]; ok && ref.Policy == OnDeleteRestrict {
+			for _, childID := range childIDs {
+				if err := del(newTx(tx), childID); err != nil {
+					return nil, err
+				}
+			}
+			continue
+		}
+		nested, err := softDeleteTree(tx, ref.Table, childIDs, columns)
+		if err != nil {
+			return nil, err
+		}
+		blockers = append(blockers, nested...)
+	}
+	if len(blockers) > 0 {
+		return blockers, nil
+	}
+	err := tx.Model(softDeleteModels[table]()).Where("id IN ?", ids).UpdateColumns(columns).Error
+	return nil, err
+}
+
+// restoreTree 恢复随 table 中 ids 行一起按 cascade 策略移入回收站的记录（删除时间与被引用行相同）
+func restoreTree(tx *gorm.DB, table string, ids []uint) error {
+	for _, ref := range references {
+		model, ok := softDeleteModels[ref.Table]
+		if ref.Parent != table || ref.Policy != OnDeleteCascade || !ok {
+			continue
+		}
+		var childIDs []uint
+		err := tx.Unscoped().Model(model()).
+			Where(fmt.Sprintf("%s IN ? AND deleted_at = (SELECT p.deleted_at FROM %s p WHERE p.id = %s.%s)",
+				ref.Column, table, ref.Table, ref.Column), ids).
+			Pluck("id", &childIDs).Error
+		if err != nil {
+			return err
+		}
+		if len(childIDs) == 0 {
+			continue
+		}
+		if err := restoreTree(tx, ref.Table, childIDs); err != nil {
+			return err
+		}
+		err = tx.Unscoped().Model(model()).Where("id IN ?", childIDs).
+			UpdateColumns(map[string]interface{}{"deleted_at": nil, "deleted_by": nil}).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// purgeTree 彻底删除 table 中的 ids 行，包括回收站中的引用行在内按引用策略处理；
+// 存在 restrict 引用时返回阻止删除的引用，不删除任何行
+func purgeTree(tx *gorm.DB, table string, ids []uint) ([]Dependent, error) {
+	var blockers []Dependent
+	for _, ref := range references {
+		if ref.Parent != table {
+			continue
+		}
+		model, soft := softDeleteModels[ref.Table]
+		q := tx.Table(ref.Table).Where(ref.Column+" IN ?", ids)
+		if soft {
+			q = tx.Unscoped().Model(model()).Where(ref.Column+" IN ?", ids)
+		}
+		switch ref.Policy {
+		case OnDeleteNullify:
+			if err := q.UpdateColumn(ref.Column, nil).Error; err != nil {
+				return nil, fmt.Errorf("failed to clear %s.%s: %w", ref.Table, ref.Column, err)
+			}
+		case OnDeleteRestrict:
+			var count int64
+			if err := q.Count(&count).Error; err != nil {
+				return nil, err
+			}
+			if count > 0 {
+				blockers = append(blockers, Dependent{Table: ref.Table, Column: ref.Column, Count: int(count)})
+			}
+		case OnDeleteCascade:
+			if !soft {
+				// 无软删除的明细表直接删除，更深层的引用由数据库外键级联
+				err := tx.Exec(fmt.Sprintf("DELETE FROM %s WHERE %s IN ?", ref.Table, ref.Column), ids).Error
+				if err != nil {
+					return nil, fmt.Errorf("failed to delete %s: %w", ref.Table, err)
+				}
+				continue
+			}
+			var childIDs []uint
+			if err := q.Pluck("id", &childIDs).Error; err != nil {
+				return nil, err
+			}
+			if len(childIDs) == 0 {
+				continue
+			}
+			nested, err := purgeTree(tx, ref.Table, childIDs)
+			if err != nil {
+				return nil, err
+			}
+			blockers = append(blockers, nested...)
+		}
+	}
+	if len(blockers) > 0 {
+		return blockers, nil
+	}
+	err := tx.Unscoped().Where("id IN ?", ids).Delete(softDeleteModels[table]()).Error
+	return nil, err
+}
+
+// purgeRow 在事务中彻底删除一行；仍被引用（含回收站中的记录）时返回 *DependentsError
+func purgeRow(db *gorm.DB, table string, id uint) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		blockers, err := purgeTree(tx, table, []uint{id})
+		if err != nil {
+			return err
+		}
+		if len(blockers) > 0 {
+			return &DependentsError{Table: table, ID: id, Dependents: blockers}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,181 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"erp-backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// integrityFixture 一个地点下的项目，项目有一条排班（cascade）与一笔捐赠（restrict）
+type integrityFixture struct {
+	location *models.Location
+	project  *models.Project
+	schedule *models.Schedule
+	donor    *models.Donor
+	donation *models.Donation
+}
+
+func newIntegrityFixture(t *testing.T, db *gorm.DB) integrityFixture {
+	t.Helper()
+	f := integrityFixture{
+		location: &models.Location{LocationID: "LOC-1", Name: "Depot"},
+		donor:    &models.Donor{DonorID: "DNR-1", FirstName: "Dana", LastName: "Lee"},
+	}
+	f.project = &models.Project{ProjectID: "PRJ-1", Name: "Wells"}
+	for _, value := range []interface{}{f.location, f.donor} {
+		if err := db.Create(value).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	f.project.LocationID = &f.location.ID
+	if err := db.Create(f.project).Error; err != nil {
+		t.Fatal(err)
+	}
+	f.schedule = &models.Schedule{ScheduleID: "SCH-1", PersonID: 1, PersonType: "volunteer", ProjectID: &f.project.ID, ShiftDate: time.Now().UTC()}
+	f.donation = &models.Donation{DonationID: "DON-1", DonorID: &f.donor.ID, ProjectID: &f.project.ID, Amount: 10,
+		DonationType: "one-time", Category: "general", DonationDate: time.Now().UTC()}
+	for _, value := range []interface{}{f.schedule, f.donation} {
+		if err := db.Create(value).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	return f
+}
+
+// live 判断行是否存在且不在回收站
+func live(t *testing.T, db *gorm.DB, model interface{}, id uint) bool {
+	t.Helper()
+	var count int64
+	if err := db.Model(model).Where("id = ?", id).Count(&count).Error; err != nil {
+		t.Fatal(err)
+	}
+	return count == 1
+}
+
+// trashed 判断行是否在回收站
+func trashed(t *testing.T, db *gorm.DB, model interface{}, id uint) bool {
+	t.Helper()
+	var count int64
+	if err := db.Unscoped().Model(model).Where("id = ? AND deleted_at IS NOT NULL", id).Count(&count).Error; err != nil {
+		t.Fatal(err)
+	}
+	return count == 1
+}
+
+func TestDeleteRestrictedByReferences(t *testing.T) {
+	db := openMigratedDB(t)
+	f := newIntegrityFixture(t, db)
+
+	err := NewProjectRepository(db).Delete(f.project.ID)
+	var depErr *DependentsError
+	if !errors.As(err, &depErr) {
+		t.Fatalf("deleting a project with donations = %v, want *DependentsError", err)
+	}
+	if len(depErr.Dependents) != 1 || depErr.Dependents[0] != (Dependent{Table: "donations", Column: "project_id", Count: 1}) {
+		t.Errorf("dependents = %+v, want 1 donations.project_id", depErr.Dependents)
+	}
+	// 拒绝删除时不应处理任何引用行
+	if !live(t, db, &models.Project{}, f.project.ID) || !live(t, db, &models.Schedule{}, f.schedule.ID) {
+		t.Error("a rejected delete moved rows to the trash")
+	}
+	var project models.Project
+	if err := db.First(&project, f.project.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if project.LocationID == nil {
+		t.Error("a rejected delete cleared references to the project")
+	}
+}
+
+func TestDeleteNullifiesReferences(t *testing.T) {
+	db := openMigratedDB(t)
+	f := newIntegrityFixture(t, db)
+
+	if err := NewLocationRepository(db).Delete(f.location.ID); err != nil {
+		t.Fatal(err)
+	}
+	var project models.Project
+	if err := db.First(&project, f.project.ID).Error; err != nil {
+		t.Fatalf("project referencing the deleted location: %v", err)
+	}
+	if project.LocationID != nil {
+		t.Errorf("project location_id = %d, want NULL", *project.LocationID)
+	}
+	if !trashed(t, db, &models.Location{}, f.location.ID) {
+		t.Error("location is not in the trash")
+	}
+}
+
+func TestDeleteCascadesToTrashAndBack(t *testing.T) {
+	db := openMigratedDB(t)
+	f := newIntegrityFixture(t, db)
+	if err := db.Delete(&models.Donation{}, f.donation.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	if err := NewProjectRepository(db).Delete(f.project.ID); err != nil {
+		t.Fatal(err)
+	}
+	if !trashed(t, db, &models.Project{}, f.project.ID) || !trashed(t, db, &models.Schedule{}, f.schedule.ID) {
+		t.Fatal("project and its schedule are not both in the trash")
+	}
+
+	// 恢复项目时一并恢复随其删除的排班
+	trash := NewTrashRepository(db)
+	if err := trash.Restore("projects", f.project.ID); err != nil {
+		t.Fatal(err)
+	}
+	if !live(t, db, &models.Schedule{}, f.schedule.ID) {
+		t.Error("schedule deleted with the project was not restored")
+	}
+
+	// 回收站中的捐赠仍引用项目，彻底删除被拒绝
+	if err := NewProjectRepository(db).Delete(f.project.ID); err != nil {
+		t.Fatal(err)
+	}
+	var depErr *DependentsError
+	if err := trash.Purge("projects", f.project.ID); !errors.As(err, &depErr) {
+		t.Fatalf("purging a project referenced by a trashed donation = %v, want *DependentsError", err)
+	}
+	if err := trash.Purge("donations", f.donation.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := trash.Purge("projects", f.project.ID); err != nil {
+		t.Fatal(err)
+	}
+	var remaining int64
+	db.Unscoped().Model(&models.Schedule{}).Where("id = ?", f.schedule.ID).Count(&remaining)
+	if remaining != 0 {
+		t.Error("schedule survived purging its project")
+	}
+}
+
+func TestForceCascadeDeletesRestrictedReferences(t *testing.T) {
+	db := openMigratedDB(t)
+	f := newIntegrityFixture(t, db)
+
+	projects := NewProjectRepository(db).WithContext(WithForceCascade(context.Background()))
+	if err := projects.Delete(f.project.ID); err != nil {
+		t.Fatal(err)
+	}
+	for name, row := range map[string]struct {
+		model interface{}
+		id    uint
+	}{
+		"project":  {&models.Project{}, f.project.ID},
+		"schedule": {&models.Schedule{}, f.schedule.ID},
+		"donation": {&models.Donation{}, f.donation.ID},
+	} {
+		if !trashed(t, db, row.model, row.id) {
+			t.Errorf("%s is not in the trash after a forced delete", name)
+		}
+	}
+	if !live(t, db, &models.Donor{}, f.donor.ID) {
+		t.Error("forced delete of a project removed the donor of its donation")
+	}
+}
@@ -0,0 +1,169 @@
+package repo
+
+import (
+	"time"
+
+	"erp-backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// LedgerRepository 总账仓储（科目、分录、分录行）
+type LedgerRepository struct {
+	db *gorm.DB
+}
+
+func NewLedgerRepository(db *gorm.DB) *LedgerRepository {
+	return &LedgerRepository{db: db}
+}
+
+// TrialBalanceRow 试算平衡表中的一行
+type TrialBalanceRow struct {
+	AccountID uint    `json:"account_id"`
+	Code      string  `json:"code"`
+	Name      string  `json:"name"`
+	Type      string  `json:"type"`
+	Debit     float64 `json:"debit"`
+	Credit    float64 `json:"credit"`
+}
+
+// ActivityRow 科目明细账中的一行
+type ActivityRow struct {
+	JournalEntryID uint      `json:"journal_entry_id"`
+	EntryDate      time.Time `json:"entry_date"`
+	SourceType     string    `json:"source_type"`
+	SourceID       uint      `json:"source_id"`
+	Description    string    `json:"description"`
+	FundID         *uint     `json:"fund_id"`
+	ProjectID      *uint     `json:"project_id"`
+	Debit          float64   `json:"debit"`
+	Credit         float64   `json:"credit"`
+	Memo           string    `json:"memo"`
+}
+
+// SeedAccounts 写入尚不存在的科目（按 Code 判断），已有科目保持不变
+func (r *LedgerRepository) SeedAccounts(accounts []models.Account) error {
+	for i := range accounts {
+		account := accounts[i]
+		if err := r.db.Where(models.Account{Code: account.Code}).FirstOrCreate(&account).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *LedgerRepository) CreateAccount(account *models.Account) error {
+	return r.db.Create(account).Error
+}
+
+func (r *LedgerRepository) GetAccounts() ([]models.Account, error) {
+	var accounts []models.Account
+	err := r.db.Order("code").Find(&accounts).Error
+	return accounts, err
+}
+
+func (r *LedgerRepository) GetAccountByID(id uint) (*models.Account, error) {
+	var account models.Account
+	if err := r.db.First(&account, id).Error; err != nil {
+		return nil, err
+	}
+	return &account, nil
+}
+
+func (r *LedgerRepository) GetAccountByCode(code string) (*models.Account, error) {
+	var account models.Account
+	if err := r.db.Where("code = ?", code).First(&account).Error; err != nil {
+		return nil, err
+	}
+	return &account, nil
+}
+
+// CreateEntry 写入分录及其明细行
+func (r *LedgerRepository) CreateEntry(entry *models.JournalEntry) error {
+	return r.db.Omit("Transaction").Create(entry).Error
+}
+
+// ActiveEntry 返回某业务单据当前有效（未被冲销且本身不是冲销分录）的分录
+func (r *LedgerRepository) ActiveEntry(sourceType string, sourceID uint) (*models.JournalEntry, error) {
+	var entry models.JournalEntry
+	err := forUpdate(r.db).Preload("Lines").
+		Where("source_type = ? AND source_id = ? AND reversed_by_id IS NULL AND reversal_of_id IS NULL", sourceType, sourceID).
+		First(&entry).Error
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// MarkReversed 记录分录已被 reversalID 冲销
+func (r *LedgerRepository) MarkReversed(id, reversalID uint) error {
+	return r.db.Model(&models.JournalEntry{}).Where("id = ?", id).
+		UpdateColumn("reversed_by_id", reversalID).Error
+}
+
+// GetEntries 按来源过滤分录，按日期倒序
+func (r *LedgerRepository) GetEntries(sourceType string, sourceID uint, start, end *time.Time) ([]models.JournalEntry, error) {
+	tx := r.db.Preload("Lines").Preload("Lines.Account")
+	if sourceType != "" {
+		tx = tx.Where("source_type = ?", sourceType)
+	}
+	if sourceID != 0 {
+		tx = tx.Where("source_id = ?", sourceID)
+	}
+	if start != nil {
+		tx = tx.Where("entry_date >= ?", *start)
+	}
+	if end != nil {
+		tx = tx.Where("entry_date < ?", end.AddDate(0, 0, 1))
+	}
+	var entries []models.JournalEntry
+	err := tx.Order("entry_date DESC, id DESC").Find(&entries).Error
+	return entries, err
+}
+
+// TrialBalance 汇总截至 asOf（含当日）各科目的借贷发生额
+func (r *LedgerRepository) TrialBalance(asOf *time.Time) ([]TrialBalanceRow, error) {
+	var rows []TrialBalanceRow
+	tx := r.db.Model(&models.JournalLine{}).
+		Select("accounts.id as account_id, accounts.code, accounts.name, accounts.type, sum(journal_lines.debit) as debit, sum(journal_lines.credit) as credit").
+		Joins("JOIN journal_entries ON journal_entries.id = journal_lines.journal_entry_id").
+		Joins("JOIN accounts ON accounts.id = journal_lines.account_id")
+	if asOf != nil {
+		tx = tx.Where("journal_entries.entry_date < ?", asOf.AddDate(0, 0, 1))
+	}
+	err := tx.Group("accounts.id, accounts.code, accounts.name, accounts.type").
+		Order("accounts.code").
+		Scan(&rows).Error
+	return rows, err
+}
+
+// AccountTotals 返回科目在 before 之前的借贷合计，用于计算期初余额
+func (r *LedgerRepository) AccountTotals(accountID uint, before time.Time) (debit, credit float64, err error) {
+	var row struct {
+		Debit  float64
+		Credit float64
+	}
+	err = r.db.Model(&models.JournalLine{}).
+		Select("coalesce(sum(journal_lines.debit), 0) as debit, coalesce(sum(journal_lines.credit), 0) as credit").
+		Joins("JOIN journal_entries ON journal_entries.id = journal_lines.journal_entry_id").
+		Where("journal_lines.account_id = ? AND journal_entries.entry_date < ?", accountID, before).
+		Scan(&row).Error
+	return row.Debit, row.Credit, err
+}
+
+// AccountActivity 返回科目在 [start, end] 期间的分录行
+func (r *LedgerRepository) AccountActivity(accountID uint, start, end *time.Time) ([]ActivityRow, error) {
+	var rows []ActivityRow
+	tx := r.db.Model(&models.JournalLine{}).
+		Select("journal_entries.id as journal_entry_id, journal_entries.entry_date, journal_entries.source_type, journal_entries.source_id, journal_entries.description, journal_lines.fund_id, journal_lines.project_id, journal_lines.debit, journal_lines.credit, journal_lines.memo").
+		Joins("JOIN journal_entries ON journal_entries.id = journal_lines.journal_entry_id").
+		Where("journal_lines.account_id = ?", accountID)
+	if start != nil {
+		tx = tx.Where("journal_entries.entry_date >= ?", *start)
+	}
+	if end != nil {
+		tx = tx.Where("journal_entries.entry_date < ?", end.AddDate(0, 0, 1))
+	}
+	err := tx.Order("journal_entries.entry_date, journal_entries.id").Scan(&rows).Error
+	return rows, err
+}
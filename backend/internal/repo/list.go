@@ -0,0 +1,141 @@
+package repo
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+
+	"erp-backend/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// ErrInvalidListParams 排序、字段或游标参数无效
+var ErrInvalidListParams = errors.New("invalid list parameters")
+
+// SortField 一个排序列
+type SortField struct {
+	Column string
+	Desc   bool
+}
+
+// ListParams 列表查询的分页、排序与字段选择
+type ListParams struct {
+	Page     int // 从 1 开始，游标分页时忽略
+	PageSize int
+	Sort     []SortField
+	Fields   []string // 只读取这些列（id 总是读取），空表示全部列
+	Keyset   bool     // 按 id 游标分页：不使用 OFFSET，适合大表
+	Cursor   string   // 上一页返回的 next_cursor，空表示第一页
+}
+
+// paginate 对查询排序、分页并把当前页写入 dest（模型切片的指针），返回分页信息。
+// 排序列与字段只接受模型的数据库列，json:"-" 的列不可用
+func paginate(tx *gorm.DB, p ListParams, dest interface{}) (*models.Pagination, error) {
+	if err := tx.Statement.Parse(tx.Statement.Model); err != nil {
+		return nil, err
+	}
+	sch := tx.Statement.Schema
+	for _, s := range p.Sort {
+		if !listable(sch, s.Column) {
+			return nil, fmt.Errorf("%w: cannot sort by %q", ErrInvalidListParams, s.Column)
+		}
+	}
+	if len(p.Fields) > 0 {
+		cols := []string{sch.PrioritizedPrimaryField.DBName}
+		for _, f := range p.Fields {
+			if !listable(sch, f) {
+				return nil, fmt.Errorf("%w: unknown field %q", ErrInvalidListParams, f)
+			}
+			if f != cols[0] {
+				cols = append(cols, f)
+			}
+		}
+		tx = tx.Select(cols)
+	}
+
+	var total int64
+	if err := tx.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, err
+	}
+	page := &models.Pagination{PageSize: p.PageSize, TotalItems: total}
+	if p.PageSize > 0 {
+		page.TotalPages = int((total + int64(p.PageSize) - 1) / int64(p.PageSize))
+	}
+
+	if p.Keyset {
+		return page, keysetPage(tx, sch, p, dest, page)
+	}
+	page.Page = p.Page
+	page.HasMore = p.Page < page.TotalPages
+	for _, s := range p.Sort {
+		tx = tx.Order(clause.OrderByColumn{Column: clause.Column{Table: sch.Table, Name: s.Column}, Desc: s.Desc})
+	}
+	// id 作为最后的排序列，保证翻页顺序稳定
+	tx = tx.Order(clause.OrderByColumn{Column: clause.Column{Table: sch.Table, Name: "id"}})
+	err := tx.Offset((p.Page - 1) * p.PageSize).Limit(p.PageSize).Find(dest).Error
+	return page, err
+}
+
+// keysetPage 按 id 游标读取一页：只允许按 id 升序或降序，多读一行判断是否还有下一页
+func keysetPage(tx *gorm.DB, sch *schema.Schema, p ListParams, dest interface{}, page *models.Pagination) error {
+	desc := false
+	switch {
+	case len(p.Sort) == 0:
+	case len(p.Sort) == 1 && p.Sort[0].Column == "id":
+		desc = p.Sort[0].Desc
+	default:
+		return fmt.Errorf("%w: cursor pagination only supports sort=id or sort=-id", ErrInvalidListParams)
+	}
+	column := clause.Column{Table: sch.Table, Name: "id"}
+	if p.Cursor != "" {
+		after, err := decodeCursor(p.Cursor)
+		if err != nil {
+			return err
+		}
+		if desc {
+			tx = tx.Where(clause.Lt{Column: column, Value: after})
+		} else {
+			tx = tx.Where(clause.Gt{Column: column, Value: after})
+		}
+	}
+	err := tx.Order(clause.OrderByColumn{Column: column, Desc: desc}).Limit(p.PageSize + 1).Find(dest).Error
+	if err != nil {
+		return err
+	}
+	rows := reflect.ValueOf(dest).Elem()
+	if rows.Len() > p.PageSize {
+		rows.Set(rows.Slice(0, p.PageSize))
+		last := reflect.Indirect(rows.Index(rows.Len() - 1))
+		id, _ := sch.PrioritizedPrimaryField.ValueOf(tx.Statement.Context, last)
+		page.NextCursor = encodeCursor(fmt.Sprint(id))
+		page.HasMore = true
+	}
+	return nil
+}
+
+// listable 判断列是否可用于排序与字段选择
+func listable(sch *schema.Schema, column string) bool {
+	f, ok := sch.FieldsByDBName[column]
+	return ok && f.Readable && f.Tag.Get("json") != "-"
+}
+
+// encodeCursor 游标是对客户端不透明的 id 编码
+func encodeCursor(id string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(id))
+}
+
+func decodeCursor(cursor string) (uint64, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err == nil {
+		var id uint64
+		if id, err = strconv.ParseUint(string(raw), 10, 64); err == nil {
+			return id, nil
+		}
+	}
+	return 0, fmt.Errorf("%w: malformed cursor", ErrInvalidListParams)
+}
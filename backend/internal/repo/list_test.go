@@ -0,0 +1,124 @@
+package repo
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"erp-backend/internal/models"
+)
+
+// createDonors 依次创建 n 个捐赠者，姓氏只有两种以制造排序并列
+func createDonors(t *testing.T, repo *DonorRepository, n int) []models.Donor {
+	t.Helper()
+	donors := make([]models.Donor, n)
+	for i := range donors {
+		donors[i] = models.Donor{DonorID: fmt.Sprintf("DNR-%02d", i+1), FirstName: "Donor", LastName: []string{"Lee", "Kim"}[i%2]}
+		if err := repo.Create(&donors[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return donors
+}
+
+func TestKeysetPagesStayStableWhileRowsChange(t *testing.T) {
+	for _, desc := range []bool{false, true} {
+		t.Run(fmt.Sprintf("desc=%v", desc), func(t *testing.T) {
+			db := openMigratedDB(t)
+			repo := NewDonorRepository(db)
+			donors := createDonors(t, repo, 7)
+
+			var seen []string
+			p := ListParams{PageSize: 3, Keyset: true, Sort: []SortField{{Column: "id", Desc: desc}}}
+			for pages := 0; ; pages++ {
+				if pages > len(donors) {
+					t.Fatal("cursor pagination does not terminate")
+				}
+				list, page, err := repo.List(p)
+				if err != nil {
+					t.Fatal(err)
+				}
+				for _, d := range list {
+					seen = append(seen, d.DonorID)
+				}
+				if pages == 0 {
+					// 翻页之间删除已读过的行并新增一行：后续页既不重复也不跳过原有的行
+					if err := repo.Delete(list[0].ID); err != nil {
+						t.Fatal(err)
+					}
+					if err := repo.Create(&models.Donor{DonorID: "DNR-NEW", FirstName: "Late", LastName: "Lee"}); err != nil {
+						t.Fatal(err)
+					}
+				}
+				if !page.HasMore {
+					if page.NextCursor != "" {
+						t.Errorf("last page has next_cursor %q", page.NextCursor)
+					}
+					break
+				}
+				p.Cursor = page.NextCursor
+			}
+
+			var want []string
+			for _, d := range donors {
+				want = append(want, d.DonorID)
+			}
+			if desc {
+				// 倒序时新增的行位于已读过的位置之前，不会出现
+				for i, j := 0, len(want)-1; i < j; i, j = i+1, j-1 {
+					want[i], want[j] = want[j], want[i]
+				}
+			} else {
+				want = append(want, "DNR-NEW")
+			}
+			if fmt.Sprint(seen) != fmt.Sprint(want) {
+				t.Errorf("pages returned %v, want %v", seen, want)
+			}
+		})
+	}
+}
+
+func TestOffsetPagesBreakTiesByID(t *testing.T) {
+	db := openMigratedDB(t)
+	repo := NewDonorRepository(db)
+	createDonors(t, repo, 7)
+
+	seen := map[uint]bool{}
+	for page := 1; page <= 3; page++ {
+		list, _, err := repo.List(ListParams{Page: page, PageSize: 3, Sort: []SortField{{Column: "last_name"}}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, d := range list {
+			if seen[d.ID] {
+				t.Errorf("donor %s appears on more than one page", d.DonorID)
+			}
+			seen[d.ID] = true
+		}
+	}
+	if len(seen) != 7 {
+		t.Errorf("pages returned %d distinct donors, want 7", len(seen))
+	}
+}
+
+func TestInvalidListParams(t *testing.T) {
+	db := openMigratedDB(t)
+	repo := NewDonorRepository(db)
+	createDonors(t, repo, 2)
+
+	tests := []struct {
+		name string
+		p    ListParams
+	}{
+		{"cursor with another sort column", ListParams{PageSize: 1, Keyset: true, Sort: []SortField{{Column: "last_name"}}}},
+		{"malformed cursor", ListParams{PageSize: 1, Keyset: true, Cursor: "not a cursor"}},
+		{"non-numeric cursor", ListParams{PageSize: 1, Keyset: true, Cursor: encodeCursor("abc")}},
+		{"unknown sort column", ListParams{Page: 1, PageSize: 1, Sort: []SortField{{Column: "nope"}}}},
+		{"unknown field", ListParams{Page: 1, PageSize: 1, Fields: []string{"nope"}}},
+	}
+	for _, tt := range tests {
+		if _, _, err := repo.List(tt.p); !errors.Is(err, ErrInvalidListParams) {
+			t.Errorf("%s: List = %v, want ErrInvalidListParams", tt.name, err)
+		}
+	}
+}
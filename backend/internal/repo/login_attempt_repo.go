@@ -0,0 +1,96 @@
+package repo
+
+import (
+	"time"
+
+	"erp-backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// LoginAttemptRepository 登录尝试记录与账号锁定状态
+type LoginAttemptRepository struct {
+	db *gorm.DB
+}
+
+func NewLoginAttemptRepository(db *gorm.DB) *LoginAttemptRepository {
+	return &LoginAttemptRepository{db: db}
+}
+
+// LoginAttemptFilter 登录记录查询条件，零值字段不参与过滤
+type LoginAttemptFilter struct {
+	Username   string
+	UserID     uint
+	ClientIP   string
+	FailedOnly bool
+	Since      *time.Time
+	Limit      int
+}
+
+func (r *LoginAttemptRepository) Create(attempt *models.LoginAttempt) error {
+	return r.db.Create(attempt).Error
+}
+
+// Search 按时间倒序返回登录记录
+func (r *LoginAttemptRepository) Search(f LoginAttemptFilter) ([]models.LoginAttempt, error) {
+	db := r.db.Order("created_at DESC, id DESC")
+	if f.Username != "" {
+		db = db.Where("username = ?", f.Username)
+	}
+	if f.UserID != 0 {
+		db = db.Where("user_id = ?", f.UserID)
+	}
+	if f.ClientIP != "" {
+		db = db.Where("client_ip = ?", f.ClientIP)
+	}
+	if f.FailedOnly {
+		db = db.Where("success = ?", false)
+	}
+	if f.Since != nil {
+		db = db.Where("created_at >= ?", *f.Since)
+	}
+	if f.Limit > 0 {
+		db = db.Limit(f.Limit)
+	}
+	var attempts []models.LoginAttempt
+	err := db.Find(&attempts).Error
+	return attempts, err
+}
+
+// CountFailuresByIP 统计某 IP 自 since 起的凭据错误次数（见 models.CredentialFailureReasons），
+// 被锁定拦截或被拒绝的尝试不计入，避免封禁期内的重试不断延长封禁
+func (r *LoginAttemptRepository) CountFailuresByIP(ip string, since time.Time) (int64, error) {
+	var n int64
+	err := r.db.Model(&models.LoginAttempt{}).
+		Where("client_ip = ? AND success = ? AND created_at >= ?", ip, false, since).
+		Where("reason IN ?", models.CredentialFailureReasons).Count(&n).Error
+	return n, err
+}
+
+// GetLockout 读取账号锁定状态并锁定该行
+func (r *LoginAttemptRepository) GetLockout(userID uint) (*models.AccountLockout, error) {
+	var lockout models.AccountLockout
+	if err := forUpdate(r.db).First(&lockout, "user_id = ?", userID).Error; err != nil {
+		return nil, err
+	}
+	return &lockout, nil
+}
+
+func (r *LoginAttemptRepository) SaveLockout(lockout *models.AccountLockout) error {
+	return r.db.Save(lockout).Error
+}
+
+// DeleteLockout 清除账号锁定状态；返回是否存在记录
+func (r *LoginAttemptRepository) DeleteLockout(userID uint) (bool, error) {
+	res := r.db.Delete(&models.AccountLockout{}, "user_id = ?", userID)
+	return res.RowsAffected > 0, res.Error
+}
+
+// LockedAccounts 返回 now 时仍处于锁定中的账号
+func (r *LoginAttemptRepository) LockedAccounts(now time.Time) ([]models.AccountLockout, error) {
+	var lockouts []models.AccountLockout
+	err := r.db.Preload("User", func(db *gorm.DB) *gorm.DB {
+		return db.Select("id", "username", "user_type", "status")
+	}).Where("locked_until > ?", now).Order("locked_until DESC").Find(&lockouts).Error
+	return lockouts, err
+}
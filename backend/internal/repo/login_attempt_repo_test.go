@@ -0,0 +1,39 @@
+package repo
+
+import (
+	"testing"
+	"time"
+
+	"erp-backend/internal/models"
+)
+
+func TestCountFailuresByIPCountsOnlyCredentialFailures(t *testing.T) {
+	db := openMigratedDB(t)
+	attempts := NewLoginAttemptRepository(db)
+	since := time.Now().UTC().Add(-time.Hour)
+
+	for _, a := range []models.LoginAttempt{
+		{Username: "alice", ClientIP: "10.0.0.1", Reason: models.LoginFailedPassword},
+		{Username: "nobody", ClientIP: "10.0.0.1", Reason: models.LoginUnknownUser},
+		{Username: "alice", ClientIP: "10.0.0.1", Reason: models.LoginBadTwoFactor},
+		{Username: "alice", ClientIP: "10.0.0.1", Reason: models.LoginIPBlocked},
+		{Username: "alice", ClientIP: "10.0.0.1", Reason: models.LoginAccountLocked},
+		{Username: "bob", ClientIP: "10.0.0.1", Reason: models.LoginInactive},
+		{Username: "alice", ClientIP: "10.0.0.1", Success: true},
+		{Username: "alice", ClientIP: "10.0.0.2", Reason: models.LoginFailedPassword},
+		{Username: "alice", ClientIP: "10.0.0.1", Reason: models.LoginFailedPassword, CreatedAt: since.Add(-time.Minute)},
+	} {
+		a := a
+		if err := attempts.Create(&a); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	n, err := attempts.CountFailuresByIP("10.0.0.1", since)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 3 {
+		t.Errorf("CountFailuresByIP = %d, want 3", n)
+	}
+}
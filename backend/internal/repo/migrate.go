@@ -0,0 +1,273 @@
+package repo
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"erp-backend/database"
+
+	"gorm.io/gorm"
+)
+
+// ErrSchemaOutdated 数据库结构与程序内嵌的迁移不一致：有未执行的迁移、已执行的脚本被修改，或数据库版本比程序新
+var ErrSchemaOutdated = errors.New("database schema is out of date")
+
+// migrationFile 迁移脚本文件名：<版本>_<名称>.up.sql / .down.sql
+var migrationFile = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// createSchemaMigrations 建立迁移记录表，%s 为各方言的时间类型
+const createSchemaMigrations = `CREATE TABLE IF NOT EXISTS schema_migrations (
+    version INTEGER PRIMARY KEY,
+    name TEXT NOT NULL,
+    checksum TEXT NOT NULL,
+    applied_at %s NOT NULL
+)`
+
+// Migration 一个版本化的迁移
+type Migration struct {
+	Version  int
+	Name     string
+	Up       string
+	Down     string
+	Checksum string // 升级脚本的 SHA-256，用于发现执行后被修改的脚本
+}
+
+// MigrationStatus 迁移的执行情况
+type MigrationStatus struct {
+	Version   int
+	Name      string
+	AppliedAt *time.Time
+	Modified  bool // 已执行，但内嵌脚本与执行时的校验和不同
+	Unknown   bool // 数据库中有记录，但程序中没有这个迁移
+}
+
+// appliedMigration schema_migrations 中的一行
+type appliedMigration struct {
+	Version   int
+	Name      string
+	Checksum  string
+	AppliedAt time.Time
+}
+
+// Migrator 按版本顺序执行内嵌的迁移，执行记录保存在 schema_migrations 表中
+type Migrator struct {
+	db         *gorm.DB
+	migrations []Migration
+}
+
+// NewMigrator 读取数据库方言对应的内嵌迁移脚本；版本号重复或缺少升级、回滚脚本时返回错误
+func NewMigrator(db *gorm.DB) (*Migrator, error) {
+	migrations, err := loadMigrations(database.Migrations, path.Join("migrations", db.Dialector.Name()))
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+func loadMigrations(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+	byVersion := make(map[int]*Migration)
+	for _, entry := range entries {
+		m := migrationFile.FindStringSubmatch(entry.Name())
+		if m == nil {
+			return nil, fmt.Errorf("unexpected migration file %s", entry.Name())
+		}
+		version, _ := strconv.Atoi(m[1])
+		content, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		mig, ok := byVersion[version]
+		if !ok {
+			mig = &Migration{Version: version, Name: m[2]}
+			byVersion[version] = mig
+		} else if mig.Name != m[2] {
+			return nil, fmt.Errorf("migration version %d is used by both %s and %s", version, mig.Name, m[2])
+		}
+		if m[3] == "up" {
+			sum := sha256.Sum256(content)
+			mig.Up, mig.Checksum = string(content), hex.EncodeToString(sum[:])
+		} else {
+			mig.Down = string(content)
+		}
+	}
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, mig := range byVersion {
+		if mig.Up == "" || mig.Down == "" {
+			return nil, fmt.Errorf("migration %03d_%s needs both an up and a down script", mig.Version, mig.Name)
+		}
+		migrations = append(migrations, *mig)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// applied 读出已执行的迁移，按版本排序
+func (m *Migrator) applied() ([]appliedMigration, error) {
+	timeType := "DATETIME"
+	if m.db.Dialector.Name() == DriverPostgres {
+		timeType = "TIMESTAMP"
+	}
+	if err := m.db.Exec(fmt.Sprintf(createSchemaMigrations, timeType)).Error; err != nil {
+		return nil, err
+	}
+	var rows []appliedMigration
+	err := m.db.Raw("SELECT version, name, checksum, applied_at FROM schema_migrations ORDER BY version").Scan(&rows).Error
+	return rows, err
+}
+
+// Status 返回全部迁移（含数据库中有而程序中没有的）的执行情况，按版本排序
+func (m *Migrator) Status() ([]MigrationStatus, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+	done := make(map[int]appliedMigration, len(applied))
+	for _, a := range applied {
+		done[a.Version] = a
+	}
+	var status []MigrationStatus
+	for _, mig := range m.migrations {
+		s := MigrationStatus{Version: mig.Version, Name: mig.Name}
+		if a, ok := done[mig.Version]; ok {
+			at := a.AppliedAt
+			s.AppliedAt, s.Modified = &at, a.Checksum != mig.Checksum
+			delete(done, mig.Version)
+		}
+		status = append(status, s)
+	}
+	for _, a := range done {
+		at := a.AppliedAt
+		status = append(status, MigrationStatus{Version: a.Version, Name: a.Name, AppliedAt: &at, Unknown: true})
+	}
+	sort.Slice(status, func(i, j int) bool { return status[i].Version < status[j].Version })
+	return status, nil
+}
+
+// Check 确认数据库结构是最新的，否则返回包装了 ErrSchemaOutdated 的错误
+func (m *Migrator) Check() error {
+	status, err := m.Status()
+	if err != nil {
+		return err
+	}
+	for _, s := range status {
+		if err := statusError(s); err != nil {
+			return err
+		}
+		if s.AppliedAt == nil {
+			return fmt.Errorf("%w: migration %03d_%s has not been applied", ErrSchemaOutdated, s.Version, s.Name)
+		}
+	}
+	return nil
+}
+
+// statusError 迁移记录与程序不符、不能继续升级时返回错误
+func statusError(s MigrationStatus) error {
+	switch {
+	case s.Unknown:
+		return fmt.Errorf("%w: migration %03d_%s is not known to this build", ErrSchemaOutdated, s.Version, s.Name)
+	case s.Modified:
+		return fmt.Errorf("%w: migration %03d_%s was modified after it was applied", ErrSchemaOutdated, s.Version, s.Name)
+	}
+	return nil
+}
+
+// Up 按顺序执行全部未执行的迁移，返回执行的个数；已执行的脚本被修改时拒绝执行。
+// 由旧版本自动建表的数据库先按 references 修正外键约束，迁移脚本随后跳过已存在的表
+func (m *Migrator) Up() (int, error) {
+	status, err := m.Status()
+	if err != nil {
+		return 0, err
+	}
+	for _, s := range status {
+		if err := statusError(s); err != nil {
+			return 0, err
+		}
+	}
+	if err := syncForeignKeys(m.db); err != nil {
+		return 0, err
+	}
+	count := 0
+	for i, mig := range m.migrations {
+		if status[i].AppliedAt != nil {
+			continue
+		}
+		err := m.run(mig.Up, "INSERT INTO schema_migrations (version, name, checksum, applied_at) VALUES (?, ?, ?, ?)",
+			mig.Version, mig.Name, mig.Checksum, time.Now().UTC())
+		if err != nil {
+			return count, fmt.Errorf("migration %03d_%s failed: %w", mig.Version, mig.Name, err)
+		}
+		count++
+	}
+	return count, nil
+}
+
+// Down 按倒序回滚最近执行的 steps 个迁移，返回回滚的个数
+func (m *Migrator) Down(steps int) (int, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return 0, err
+	}
+	byVersion := make(map[int]Migration, len(m.migrations))
+	for _, mig := range m.migrations {
+		byVersion[mig.Version] = mig
+	}
+	count := 0
+	for i := len(applied) - 1; i >= 0 && count < steps; i-- {
+		mig, ok := byVersion[applied[i].Version]
+		if !ok {
+			return count, statusError(MigrationStatus{Version: applied[i].Version, Name: applied[i].Name, Unknown: true})
+		}
+		if err := m.run(mig.Down, "DELETE FROM schema_migrations WHERE version = ?", mig.Version); err != nil {
+			return count, fmt.Errorf("rollback of %03d_%s failed: %w", mig.Version, mig.Name, err)
+		}
+		count++
+	}
+	return count, nil
+}
+
+// run 在一个事务中执行迁移脚本并更新 schema_migrations。SQLite 在脚本执行期间关闭外键检查以便重建或删除表，
+// 提交前检查外键，留下无效引用时整个迁移回滚。MySQL 的 DDL 会隐式提交，脚本中途失败时需手工清理已建的表
+func (m *Migrator) run(script, record string, args ...interface{}) error {
+	if m.db.Dialector.Name() != DriverSQLite {
+		return m.db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Exec(script).Error; err != nil {
+				return err
+			}
+			return tx.Exec(record, args...).Error
+		})
+	}
+	return m.db.Connection(func(conn *gorm.DB) error {
+		if err := conn.Exec("PRAGMA foreign_keys = OFF").Error; err != nil {
+			return err
+		}
+		defer conn.Exec("PRAGMA foreign_keys = ON")
+		return conn.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Exec(script).Error; err != nil {
+				return err
+			}
+			if err := tx.Exec(record, args...).Error; err != nil {
+				return err
+			}
+			var violations int64
+			if err := tx.Raw("SELECT count(*) FROM pragma_foreign_key_check").Scan(&violations).Error; err != nil {
+				return err
+			}
+			if violations > 0 {
+				return fmt.Errorf("%d rows would violate foreign keys", violations)
+			}
+			return nil
+		})
+	})
+}
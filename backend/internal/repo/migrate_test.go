@@ -0,0 +1,190 @@
+package repo
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"testing"
+
+	"erp-backend/database"
+
+	"gorm.io/gorm"
+)
+
+func TestMigrationsMatchAcrossDialects(t *testing.T) {
+	want, err := loadMigrations(database.Migrations, path.Join("migrations", DriverSQLite))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, driver := range []string{DriverPostgres, DriverMySQL} {
+		got, err := loadMigrations(database.Migrations, path.Join("migrations", driver))
+		if err != nil {
+			t.Fatalf("%s: %v", driver, err)
+		}
+		if len(got) != len(want) {
+			t.Fatalf("%s has %d migrations, sqlite has %d", driver, len(got), len(want))
+		}
+		for i := range want {
+			if got[i].Version != want[i].Version || got[i].Name != want[i].Name {
+				t.Errorf("%s migration %03d_%s, sqlite has %03d_%s", driver, got[i].Version, got[i].Name, want[i].Version, want[i].Name)
+			}
+		}
+	}
+}
+
+func TestMigratorUpDown(t *testing.T) {
+	db := openTestDB(t)
+	m, err := NewMigrator(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	total := len(m.migrations)
+
+	if err := m.Check(); !errors.Is(err, ErrSchemaOutdated) {
+		t.Fatalf("Check on an empty database = %v, want ErrSchemaOutdated", err)
+	}
+	if n, err := m.Up(); err != nil || n != total {
+		t.Fatalf("Up = %d, %v; want %d, nil", n, err, total)
+	}
+	if err := m.Check(); err != nil {
+		t.Fatalf("Check after Up: %v", err)
+	}
+	if n, err := m.Up(); err != nil || n != 0 {
+		t.Fatalf("second Up = %d, %v; want 0, nil", n, err)
+	}
+	for _, ref := range references {
+		// 按名称查询列，不依赖各方言 Migrator 对表名参数的支持
+		if err := db.Table(ref.Table).Select(ref.Column).Where("1 = 0").Find(&[]map[string]interface{}{}).Error; err != nil {
+			t.Errorf("reference %s.%s -> %s: %v", ref.Table, ref.Column, ref.Parent, err)
+		}
+	}
+
+	if n, err := m.Down(1); err != nil || n != 1 {
+		t.Fatalf("Down(1) = %d, %v; want 1, nil", n, err)
+	}
+	if err := m.Check(); !errors.Is(err, ErrSchemaOutdated) {
+		t.Fatalf("Check after Down(1) = %v, want ErrSchemaOutdated", err)
+	}
+	if n, err := m.Up(); err != nil || n != 1 {
+		t.Fatalf("Up after Down(1) = %d, %v; want 1, nil", n, err)
+	}
+
+	if n, err := m.Down(total); err != nil || n != total {
+		t.Fatalf("Down(all) = %d, %v; want %d, nil", n, err, total)
+	}
+	tables, err := db.Migrator().GetTables()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, table := range tables {
+		if table != "schema_migrations" && table != "sqlite_sequence" {
+			t.Errorf("table %s left after rolling back every migration", table)
+		}
+	}
+	if n, err := m.Up(); err != nil || n != total {
+		t.Fatalf("Up after Down(all) = %d, %v; want %d, nil", n, err, total)
+	}
+}
+
+func TestMigratorRejectsModifiedScript(t *testing.T) {
+	db := openMigratedDB(t)
+	if err := db.Exec("UPDATE schema_migrations SET checksum = 'changed' WHERE version = 1").Error; err != nil {
+		t.Fatal(err)
+	}
+	m, err := NewMigrator(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Up(); !errors.Is(err, ErrSchemaOutdated) {
+		t.Fatalf("Up with a modified script = %v, want ErrSchemaOutdated", err)
+	}
+	if err := m.Check(); !errors.Is(err, ErrSchemaOutdated) {
+		t.Fatalf("Check with a modified script = %v, want ErrSchemaOutdated", err)
+	}
+}
+
+func TestEachMigrationRollsBackToThePreviousSchema(t *testing.T) {
+	db := openTestDB(t)
+	all, err := NewMigrator(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	before := schemaOf(t, db)
+	for i, mig := range all.migrations {
+		// 只包含前 i+1 个迁移的 Migrator，每次 Up 只执行当前这一个
+		step := &Migrator{db: db, migrations: all.migrations[:i+1]}
+		if n, err := step.Up(); err != nil || n != 1 {
+			t.Fatalf("%03d_%s: Up = %d, %v; want 1, nil", mig.Version, mig.Name, n, err)
+		}
+		after := schemaOf(t, db)
+		if n, err := step.Down(1); err != nil || n != 1 {
+			t.Fatalf("%03d_%s: Down(1) = %d, %v; want 1, nil", mig.Version, mig.Name, n, err)
+		}
+		if got := schemaOf(t, db); got != before {
+			t.Errorf("%03d_%s: rollback left a different schema\n got: %s\nwant: %s", mig.Version, mig.Name, got, before)
+		}
+		if _, err := step.Up(); err != nil {
+			t.Fatalf("%03d_%s: Up after Down(1): %v", mig.Version, mig.Name, err)
+		}
+		if got := schemaOf(t, db); got != after {
+			t.Errorf("%03d_%s: reapplying gave a different schema\n got: %s\nwant: %s", mig.Version, mig.Name, got, after)
+		}
+		before = after
+	}
+}
+
+// schemaOf 描述数据库结构：各表的列（名称、类型、可空）与索引，不含迁移记录表，与列的顺序无关
+func schemaOf(t *testing.T, db *gorm.DB) string {
+	t.Helper()
+	tables, err := db.Migrator().GetTables()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(tables)
+	var b strings.Builder
+	for _, table := range tables {
+		if table == "schema_migrations" || strings.HasPrefix(table, "sqlite_") {
+			continue
+		}
+		columns, err := db.Migrator().ColumnTypes(table)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var parts []string
+		for _, c := range columns {
+			nullable, _ := c.Nullable()
+			parts = append(parts, fmt.Sprintf("%s %s null=%v", c.Name(), strings.ToLower(c.DatabaseTypeName()), nullable))
+		}
+		parts = append(parts, indexesOf(t, db, table)...)
+		sort.Strings(parts)
+		fmt.Fprintf(&b, "%s(%s) ", table, strings.Join(parts, ", "))
+	}
+	return b.String()
+}
+
+// indexesOf 返回表上的索引名；SQLite 的 gorm 驱动不支持 GetIndexes，从 sqlite_master 读取
+func indexesOf(t *testing.T, db *gorm.DB, table string) []string {
+	t.Helper()
+	var names []string
+	if db.Dialector.Name() == DriverSQLite {
+		err := db.Raw("SELECT name FROM sqlite_master WHERE type IN ('index', 'trigger') AND tbl_name = ? AND name NOT LIKE 'sqlite_%'", table).
+			Scan(&names).Error
+		if err != nil {
+			t.Fatal(err)
+		}
+	} else {
+		indexes, err := db.Migrator().GetIndexes(table)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, idx := range indexes {
+			names = append(names, idx.Name())
+		}
+	}
+	for i, name := range names {
+		names[i] = "index " + name
+	}
+	return names
+}
@@ -0,0 +1,43 @@
+package repo
+
+import (
+	"time"
+
+	"erp-backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// PasswordResetRepository 密码重置令牌
+type PasswordResetRepository struct {
+	db *gorm.DB
+}
+
+func NewPasswordResetRepository(db *gorm.DB) *PasswordResetRepository {
+	return &PasswordResetRepository{db: db}
+}
+
+func (r *PasswordResetRepository) Create(token *models.PasswordResetToken) error {
+	return r.db.Create(token).Error
+}
+
+// GetByHash 按哈希读取令牌并锁定该行
+func (r *PasswordResetRepository) GetByHash(hash string) (*models.PasswordResetToken, error) {
+	var token models.PasswordResetToken
+	if err := forUpdate(r.db).Where("token_hash = ?", hash).First(&token).Error; err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// MarkUsed 仅当令牌尚未使用时标记为已使用；返回是否标记成功
+func (r *PasswordResetRepository) MarkUsed(id uint, at time.Time) (bool, error) {
+	res := r.db.Model(&models.PasswordResetToken{}).Where("id = ? AND used_at IS NULL", id).Update("used_at", at)
+	return res.RowsAffected == 1, res.Error
+}
+
+// InvalidateUserTokens 作废某用户所有未使用的令牌
+func (r *PasswordResetRepository) InvalidateUserTokens(userID uint, at time.Time) error {
+	return r.db.Model(&models.PasswordResetToken{}).
+		Where("user_id = ? AND used_at IS NULL", userID).Update("used_at", at).Error
+}
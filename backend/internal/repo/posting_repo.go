@@ -0,0 +1,296 @@
+package repo
+
+import (
+	"errors"
+
+	"erp-backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// GetByID 读取单条捐赠记录（含礼品），并在事务中锁定该行
+func (r *DonationRepository) GetByID(id uint) (*models.Donation, error) {
+	var donation models.Donation
+	if err := forUpdate(r.db).Preload("Gifts").First(&donation, id).Error; err != nil {
+		return nil, err
+	}
+	return &donation, nil
+}
+
+// GetByID 读取捐赠者并锁定该行
+func (r *DonorRepository) GetByID(id uint) (*models.Donor, error) {
+	var donor models.Donor
+	if err := forUpdate(r.db).First(&donor, id).Error; err != nil {
+		return nil, err
+	}
+	return &donor, nil
+}
+
+// AddTotalDonated 以增量方式调整捐赠者累计捐赠额，避免读-改-写覆盖并发更新
+func (r *DonorRepository) AddTotalDonated(id uint, delta float64) error {
+	return r.db.Model(&models.Donor{}).Where("id = ?", id).
+		UpdateColumn("total_donated", gorm.Expr("total_donated + ?", delta)).Error
+}
+
+// AddInKindTotal 以增量方式调整捐赠者的实物捐赠估值累计
+func (r *DonorRepository) AddInKindTotal(id uint, delta float64) error {
+	return r.db.Model(&models.Donor{}).Where("id = ?", id).
+		UpdateColumn("in_kind_total", gorm.Expr("in_kind_total + ?", delta)).Error
+}
+
+// GetByID 读取基金并锁定该行
+func (r *FundRepository) GetByID(id uint) (*models.Fund, error) {
+	var fund models.Fund
+	if err := forUpdate(r.db).First(&fund, id).Error; err != nil {
+		return nil, err
+	}
+	return &fund, nil
+}
+
+// AdjustBalance 以增量方式调整基金当前余额
+func (r *FundRepository) AdjustBalance(id uint, delta float64) error {
+	return r.db.Model(&models.Fund{}).Where("id = ?", id).
+		UpdateColumn("current_balance", gorm.Expr("current_balance + ?", delta)).Error
+}
+
+// Debit 在余额足够时扣减基金余额，返回是否扣减成功。
+// 余额判断与扣减在同一条 UPDATE 中完成，并发扣款不会同时通过；比较时留半分容差以吸收浮点误差。
+func (r *FundRepository) Debit(id uint, amount float64) (bool, error) {
+	res := r.db.Model(&models.Fund{}).Where("id = ? AND current_balance + 0.005 >= ?", id, amount).
+		UpdateColumn("current_balance", gorm.Expr("current_balance - ?", amount))
+	return res.RowsAffected == 1, res.Error
+}
+
+// GetByID 读取单条交易记录
+func (r *TransactionRepository) GetByID(id uint) (*models.Transaction, error) {
+	var transaction models.Transaction
+	if err := r.db.First(&transaction, id).Error; err != nil {
+		return nil, err
+	}
+	return &transaction, nil
+}
+
+// DeleteByDonation 把某笔捐赠下的所有礼品记录移入回收站
+func (r *GiftRepository) DeleteByDonation(donationID uint) error {
+	return r.db.Model(&models.Gift{}).Where("donation_id = ?", donationID).UpdateColumns(softDeleteColumns(r.db)).Error
+}
+
+// RestoreByDonation 恢复随捐赠一起删除的礼品记录
+func (r *GiftRepository) RestoreByDonation(donationID uint) error {
+	return r.db.Unscoped().Model(&models.Gift{}).
+		Where("donation_id = ? AND deleted_at IS NOT NULL", donationID).
+		UpdateColumns(map[string]interface{}{"deleted_at": nil, "deleted_by": nil}).Error
+}
+
+// PurgeByDonation 彻底删除某笔捐赠下的礼品记录（包括回收站中的）
+func (r *GiftRepository) PurgeByDonation(donationID uint) error {
+	return r.db.Unscoped().Where("donation_id = ?", donationID).Delete(&models.Gift{}).Error
+}
+
+// GetByID 读取支出并锁定该行
+func (r *ExpenseRepository) GetByID(id uint) (*models.Expense, error) {
+	var expense models.Expense
+	if err := forUpdate(r.db).First(&expense, id).Error; err != nil {
+		return nil, err
+	}
+	return &expense, nil
+}
+
+// GetByID 读取采购并锁定该行
+func (r *PurchaseRepository) GetByID(id uint) (*models.Purchase, error) {
+	var purchase models.Purchase
+	if err := forUpdate(r.db).First(&purchase, id).Error; err != nil {
+		return nil, err
+	}
+	return &purchase, nil
+}
+
+// GetByID 读取薪资记录并锁定该行
+func (r *PayrollRepository) GetByID(id uint) (*models.Payroll, error) {
+	var payroll models.Payroll
+	if err := forUpdate(r.db).First(&payroll, id).Error; err != nil {
+		return nil, err
+	}
+	return &payroll, nil
+}
+
+// GetByID 读取基金-项目拨款并锁定该行
+func (r *FundProjectRepository) GetByID(id uint) (*models.FundProject, error) {
+	var fp models.FundProject
+	if err := forUpdate(r.db).First(&fp, id).Error; err != nil {
+		return nil, err
+	}
+	return &fp, nil
+}
+
+// GetByID 读取员工
+func (r *EmployeeRepository) GetByID(id uint) (*models.Employee, error) {
+	var employee models.Employee
+	if err := r.db.First(&employee, id).Error; err != nil {
+		return nil, err
+	}
+	return &employee, nil
+}
+
+// GetByID 读取项目
+func (r *ProjectRepository) GetByID(id uint) (*models.Project, error) {
+	var project models.Project
+	if err := r.db.First(&project, id).Error; err != nil {
+		return nil, err
+	}
+	return &project, nil
+}
+
+// GetByID 读取库存并锁定该行
+func (r *InventoryRepository) GetByID(id uint) (*models.Inventory, error) {
+	var inventory models.Inventory
+	if err := forUpdate(r.db).First(&inventory, id).Error; err != nil {
+		return nil, err
+	}
+	return &inventory, nil
+}
+
+// AdjustStock 以增量方式调整库存数量，返回是否调整成功；减少后库存会小于零时不做修改。
+// 判断与调整在同一条 UPDATE 中完成，并发出库不会同时通过
+func (r *InventoryRepository) AdjustStock(id uint, delta int) (bool, error) {
+	res := r.db.Model(&models.Inventory{}).Where("id = ? AND current_stock + ? >= 0", id, delta).
+		UpdateColumn("current_stock", gorm.Expr("current_stock + ?", delta))
+	return res.RowsAffected == 1, res.Error
+}
+
+// ActiveBySource 返回某业务单据产生的、尚未冲销的库存变动
+func (r *InventoryTransactionRepository) ActiveBySource(source string, sourceID uint) ([]models.InventoryTransaction, error) {
+	var moves []models.InventoryTransaction
+	err := forUpdate(r.db).
+		Where("source_type = ? AND source_id = ? AND reversal_of_id IS NULL AND reversed_by_id IS NULL", source, sourceID).
+		Order("id").Find(&moves).Error
+	return moves, err
+}
+
+// MarkReversed 记录冲销原变动的变动
+func (r *InventoryTransactionRepository) MarkReversed(id, reversalID uint) error {
+	return r.db.Model(&models.InventoryTransaction{}).Where("id = ?", id).
+		UpdateColumn("reversed_by_id", reversalID).Error
+}
+
+// GetByID 读取实物捐赠（含估值记录）并锁定该行
+func (r *DonationInventoryRepository) GetByID(id uint) (*models.DonationInventory, error) {
+	var di models.DonationInventory
+	if err := forUpdate(r.db).Preload("Valuation").First(&di, id).Error; err != nil {
+		return nil, err
+	}
+	return &di, nil
+}
+
+// SaveValuation 写入或更新实物捐赠的估值记录，估值人取自 context 中的审计操作人
+func (r *DonationInventoryRepository) SaveValuation(v *models.InKindValuation) error {
+	v.ValuedBy = actorID(r.db)
+	var existing models.InKindValuation
+	err := r.db.Where("donation_inventory_id = ?", v.DonationInventoryID).First(&existing).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		v.ID = 0
+		return r.db.Create(v).Error
+	}
+	if err != nil {
+		return err
+	}
+	v.ID = existing.ID
+	return r.db.Save(v).Error
+}
+
+// GetByID 读取库存变动并锁定该行
+func (r *InventoryTransactionRepository) GetByID(id uint) (*models.InventoryTransaction, error) {
+	var move models.InventoryTransaction
+	if err := forUpdate(r.db).First(&move, id).Error; err != nil {
+		return nil, err
+	}
+	return &move, nil
+}
+
+// RestoreByInventory 恢复随库存一起删除的库存变动
+func (r *InventoryTransactionRepository) RestoreByInventory(inventoryID uint) error {
+	return r.db.Unscoped().Model(&models.InventoryTransaction{}).
+		Where("(to_inventory_id = ? OR from_inventory_id = ?) AND deleted_at IS NOT NULL", inventoryID, inventoryID).
+		UpdateColumns(map[string]interface{}{"deleted_at": nil, "deleted_by": nil}).Error
+}
+
+// StockBalance 一项库存的账面数量与按库存变动累计的数量
+type StockBalance struct {
+	ID           uint   `json:"id"`
+	InventoryID  string `json:"inventory_id"`
+	Name         string `json:"name"`
+	CurrentStock int    `json:"current_stock"`
+	LedgerStock  int    `json:"ledger_stock"`
+	Drift        int    `json:"drift"` // current_stock - ledger_stock
+}
+
+// StockBalances 返回全部未删除库存的账面数量与变动累计数量。
+// 变动只增不改，累计时包括随其他库存一起删除的调拨记录，它们对本库存的影响仍然有效
+func (r *InventoryRepository) StockBalances() ([]StockBalance, error) {
+	var rows []StockBalance
+	err := r.db.Model(&models.Inventory{}).
+		Select("inventories.id, inventories.inventory_id, inventories.name, inventories.current_stock, " +
+			"COALESCE((SELECT SUM(t.quantity_change) FROM inventory_transactions t WHERE t.to_inventory_id = inventories.id), 0) - " +
+			"COALESCE((SELECT SUM(t.quantity_change) FROM inventory_transactions t WHERE t.from_inventory_id = inventories.id), 0) AS ledger_stock").
+		Order("inventories.id").Scan(&rows).Error
+	for i := range rows {
+		rows[i].Drift = rows[i].CurrentStock - rows[i].LedgerStock
+	}
+	return rows, err
+}
+
+// GetByID 读取配送并锁定该行
+func (r *DeliveryRepository) GetByID(id uint) (*models.Delivery, error) {
+	var delivery models.Delivery
+	if err := forUpdate(r.db).First(&delivery, id).Error; err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+// GetByID 读取配送明细并锁定该行
+func (r *DeliveryInventoryRepository) GetByID(id uint) (*models.DeliveryInventory, error) {
+	var di models.DeliveryInventory
+	if err := forUpdate(r.db).First(&di, id).Error; err != nil {
+		return nil, err
+	}
+	return &di, nil
+}
+
+// ByDelivery 返回某次配送未删除的明细并锁定
+func (r *DeliveryInventoryRepository) ByDelivery(deliveryID uint) ([]models.DeliveryInventory, error) {
+	var lines []models.DeliveryInventory
+	err := forUpdate(r.db).Where("delivery_id = ?", deliveryID).Order("id").Find(&lines).Error
+	return lines, err
+}
+
+// SetUnitCost 写入配送明细出库时计算的单位成本
+func (r *DeliveryInventoryRepository) SetUnitCost(id uint, unitCost float64) error {
+	return r.db.Model(&models.DeliveryInventory{}).Where("id = ?", id).
+		UpdateColumn("unit_cost", unitCost).Error
+}
+
+// ActiveByInventory 返回涉及某库存、尚未冲销的库存变动，按变动日期排列；exclude 中的变动
+// （即将被冲销的原变动）不计入。包括随其他库存一起删除的调拨记录
+func (r *InventoryTransactionRepository) ActiveByInventory(inventoryID uint, exclude []uint) ([]models.InventoryTransaction, error) {
+	q := r.db.Unscoped().
+		Where("(to_inventory_id = ? OR from_inventory_id = ?) AND reversal_of_id IS NULL AND reversed_by_id IS NULL", inventoryID, inventoryID)
+	if len(exclude) > 0 {
+		q = q.Where("id NOT IN ?", exclude)
+	}
+	var moves []models.InventoryTransaction
+	err := q.Order("transaction_date, id").Find(&moves).Error
+	return moves, err
+}
+
+// PurchaseReceived 返回已按某采购入库、尚未冲销的成本合计；exclude 中的变动不计入
+func (r *InventoryTransactionRepository) PurchaseReceived(purchaseID uint, exclude []uint) (float64, error) {
+	q := r.db.Unscoped().Model(&models.InventoryTransaction{}).
+		Where("purchase_id = ? AND reversal_of_id IS NULL AND reversed_by_id IS NULL", purchaseID)
+	if len(exclude) > 0 {
+		q = q.Where("id NOT IN ?", exclude)
+	}
+	var total float64
+	err := q.Select("COALESCE(SUM(quantity_change * unit_cost), 0)").Scan(&total).Error
+	return total, err
+}
@@ -0,0 +1,207 @@
+package repo
+
+import (
+	"fmt"
+
+	"erp-backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// RBACRepository 角色、权限与用户角色分配
+type RBACRepository struct {
+	db *gorm.DB
+}
+
+func NewRBACRepository(db *gorm.DB) *RBACRepository {
+	return &RBACRepository{db: db}
+}
+
+// SeedRoles 写入尚不存在的内置角色（已有角色不覆盖，保留管理员的修改）。
+// 新版本引入的权限代码（权限表中尚不存在）会追加给已有的对应内置角色。
+// 首次安装角色表时，为已有员工账号分配 staff 角色，避免升级后全部失去访问权限。
+func (r *RBACRepository) SeedRoles(defaults []models.DefaultRole) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&models.Role{}).Count(&count).Error; err != nil {
+			return err
+		}
+		txRepo := NewRBACRepository(tx)
+		known, err := txRepo.permissionCodes()
+		if err != nil {
+			return err
+		}
+		for _, d := range defaults {
+			if existing, err := txRepo.GetRoleByName(d.Name); err == nil {
+				if err := txRepo.grantIntroduced(existing, d.Permissions, known); err != nil {
+					return err
+				}
+				continue
+			} else if err != gorm.ErrRecordNotFound {
+				return err
+			}
+			perms, err := txRepo.EnsurePermissions(d.Permissions)
+			if err != nil {
+				return err
+			}
+			role := models.Role{Name: d.Name, Description: d.Description, IsSystem: true, Permissions: perms}
+			if err := tx.Create(&role).Error; err != nil {
+				return err
+			}
+		}
+		if count > 0 {
+			return nil
+		}
+		staff, err := txRepo.GetRoleByName(models.RoleStaff)
+		if err != nil {
+			return err
+		}
+		return tx.Exec(`INSERT INTO user_roles (user_id, role_id, created_at)
+			SELECT id, ?, CURRENT_TIMESTAMP FROM users WHERE user_type = ?`, staff.ID, "employee").Error
+	})
+}
+
+// permissionCodes 返回权限表中已有的权限代码
+func (r *RBACRepository) permissionCodes() (map[string]bool, error) {
+	perms, err := r.GetPermissions()
+	if err != nil {
+		return nil, err
+	}
+	known := make(map[string]bool, len(perms))
+	for _, p := range perms {
+		known[p.Code()] = true
+	}
+	return known, nil
+}
+
+// grantIntroduced 将此前从未出现过的默认权限追加给已有角色
+func (r *RBACRepository) grantIntroduced(role *models.Role, codes []string, known map[string]bool) error {
+	var introduced []string
+	for _, code := range codes {
+		if !known[code] {
+			introduced = append(introduced, code)
+		}
+	}
+	if len(introduced) == 0 {
+		return nil
+	}
+	perms, err := r.EnsurePermissions(introduced)
+	if err != nil {
+		return err
+	}
+	return r.db.Model(role).Association("Permissions").Append(perms)
+}
+
+// EnsurePermissions 按 resource:action 代码返回权限记录，不存在的自动创建
+func (r *RBACRepository) EnsurePermissions(codes []string) ([]models.Permission, error) {
+	perms := make([]models.Permission, 0, len(codes))
+	for _, code := range codes {
+		resource, action, ok := models.ParsePermissionCode(code)
+		if !ok {
+			return nil, fmt.Errorf("invalid permission code %q", code)
+		}
+		p := models.Permission{Resource: resource, Action: action}
+		if err := r.db.Where(p).FirstOrCreate(&p).Error; err != nil {
+			return nil, err
+		}
+		perms = append(perms, p)
+	}
+	return perms, nil
+}
+
+func (r *RBACRepository) GetPermissions() ([]models.Permission, error) {
+	var perms []models.Permission
+	err := r.db.Order("resource, action").Find(&perms).Error
+	return perms, err
+}
+
+func (r *RBACRepository) GetRoles() ([]models.Role, error) {
+	var roles []models.Role
+	err := r.db.Preload("Permissions").Order("name").Find(&roles).Error
+	return roles, err
+}
+
+func (r *RBACRepository) GetRoleByID(id uint) (*models.Role, error) {
+	var role models.Role
+	if err := r.db.Preload("Permissions").First(&role, id).Error; err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
+
+func (r *RBACRepository) GetRoleByName(name string) (*models.Role, error) {
+	var role models.Role
+	if err := r.db.Preload("Permissions").Where("name = ?", name).First(&role).Error; err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
+
+// GetRolesByNames 按名称批量读取角色
+func (r *RBACRepository) GetRolesByNames(names []string) ([]models.Role, error) {
+	var roles []models.Role
+	err := r.db.Where("name IN ?", names).Find(&roles).Error
+	return roles, err
+}
+
+// SetRoleTwoFactor 设置角色成员是否必须使用双因素认证
+func (r *RBACRepository) SetRoleTwoFactor(id uint, required bool) error {
+	return r.db.Model(&models.Role{}).Where("id = ?", id).Update("require_two_factor", required).Error
+}
+
+func (r *RBACRepository) CreateRole(role *models.Role) error {
+	return r.db.Create(role).Error
+}
+
+// UpdateRole 更新角色资料并整体替换其权限
+func (r *RBACRepository) UpdateRole(role *models.Role, perms []models.Permission) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Permissions").Save(role).Error; err != nil {
+			return err
+		}
+		return tx.Model(role).Association("Permissions").Replace(perms)
+	})
+}
+
+// DeleteRole 删除角色及其权限、用户分配
+func (r *RBACRepository) DeleteRole(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		role := &models.Role{ID: id}
+		if err := tx.Model(role).Association("Permissions").Clear(); err != nil {
+			return err
+		}
+		if err := tx.Where("role_id = ?", id).Delete(&models.UserRole{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(role).Error
+	})
+}
+
+// GetUserRoles 返回用户拥有的角色
+func (r *RBACRepository) GetUserRoles(userID uint) ([]models.Role, error) {
+	var roles []models.Role
+	err := r.db.Joins("JOIN user_roles ON user_roles.role_id = roles.id").
+		Where("user_roles.user_id = ?", userID).Order("roles.name").Find(&roles).Error
+	return roles, err
+}
+
+// SetUserRoles 整体替换用户的角色
+func (r *RBACRepository) SetUserRoles(userID uint, roleIDs []uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&models.UserRole{}).Error; err != nil {
+			return err
+		}
+		for _, id := range roleIDs {
+			if err := tx.Create(&models.UserRole{UserID: userID, RoleID: id}).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// AddUserRole 为用户追加一个角色，已拥有则不变
+func (r *RBACRepository) AddUserRole(userID, roleID uint) error {
+	ur := models.UserRole{UserID: userID, RoleID: roleID}
+	return r.db.Where(ur).FirstOrCreate(&ur).Error
+}
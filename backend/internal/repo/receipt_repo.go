@@ -0,0 +1,134 @@
+package repo
+
+import (
+	"sort"
+	"time"
+
+	"erp-backend/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ReceiptRepository 年度捐赠收据、收据号计数，以及开具收据所需的捐赠明细
+type ReceiptRepository struct {
+	db *gorm.DB
+}
+
+func NewReceiptRepository(db *gorm.DB) *ReceiptRepository {
+	return &ReceiptRepository{db: db}
+}
+
+// NextNumber 取得某年的下一个收据序号。计数行在事务中被锁定，并发开具的收据不会重号；
+// 事务回滚时序号一并回滚，因此编号连续
+func (r *ReceiptRepository) NextNumber(year int) (int, error) {
+	seq := models.ReceiptSequence{Year: year}
+	if err := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&seq).Error; err != nil {
+		return 0, err
+	}
+	if err := forUpdate(r.db).Where("year = ?", year).First(&seq).Error; err != nil {
+		return 0, err
+	}
+	seq.LastNumber++
+	err := r.db.Model(&models.ReceiptSequence{}).Where("year = ?", year).
+		UpdateColumn("last_number", seq.LastNumber).Error
+	return seq.LastNumber, err
+}
+
+func (r *ReceiptRepository) Create(receipt *models.TaxReceipt) error {
+	return r.db.Create(receipt).Error
+}
+
+// GetByID 读取收据并锁定该行
+func (r *ReceiptRepository) GetByID(id uint) (*models.TaxReceipt, error) {
+	var receipt models.TaxReceipt
+	if err := forUpdate(r.db).First(&receipt, id).Error; err != nil {
+		return nil, err
+	}
+	return &receipt, nil
+}
+
+// Current 返回捐赠者某年的有效收据
+func (r *ReceiptRepository) Current(donorID uint, year int) (*models.TaxReceipt, error) {
+	var receipt models.TaxReceipt
+	err := forUpdate(r.db).Where("donor_id = ? AND year = ? AND status = ?", donorID, year, models.ReceiptStatusIssued).
+		First(&receipt).Error
+	if err != nil {
+		return nil, err
+	}
+	return &receipt, nil
+}
+
+// List 按年份（新的在前）与收据号列出收据，含已作废的；year、donorID 为 0 表示不限
+func (r *ReceiptRepository) List(year int, donorID uint) ([]models.TaxReceipt, error) {
+	q := r.db.Model(&models.TaxReceipt{})
+	if year != 0 {
+		q = q.Where("year = ?", year)
+	}
+	if donorID != 0 {
+		q = q.Where("donor_id = ?", donorID)
+	}
+	var list []models.TaxReceipt
+	err := q.Order("year DESC, receipt_number").Find(&list).Error
+	return list, err
+}
+
+// Void 作废收据；replacedBy 非 nil 时记录替换它的新收据
+func (r *ReceiptRepository) Void(id uint, by *uint, reason string, at time.Time, replacedBy *uint) error {
+	return r.db.Model(&models.TaxReceipt{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"status":         models.ReceiptStatusVoid,
+		"voided_by":      by,
+		"voided_at":      at,
+		"void_reason":    reason,
+		"replaced_by_id": replacedBy,
+	}).Error
+}
+
+// Gifts 返回捐赠者在 [start, end) 内的捐款，按日期排序；已移入回收站的捐款不计入
+func (r *ReceiptRepository) Gifts(donorID uint, start, end time.Time) ([]models.ReceiptGift, error) {
+	var gifts []models.ReceiptGift
+	err := r.db.Model(&models.Donation{}).
+		Select("donations.donation_id, donations.donation_date AS date, donations.amount, donations.payment_method AS method, projects.name AS project").
+		Joins("LEFT JOIN projects ON donations.project_id = projects.id").
+		Where("donations.donor_id = ? AND donations.donation_date >= ? AND donations.donation_date < ?", donorID, start, end).
+		Order("donations.donation_date, donations.id").
+		Scan(&gifts).Error
+	return gifts, err
+}
+
+// Items 返回捐赠者在 [start, end) 内的实物捐赠，按日期排序
+func (r *ReceiptRepository) Items(donorID uint, start, end time.Time) ([]models.ReceiptItem, error) {
+	var items []models.ReceiptItem
+	err := r.db.Model(&models.DonationInventory{}).
+		Select("inventories.name, donation_inventories.donation_date AS date, donation_inventories.quantity, donation_inventories.estimated_value AS value").
+		Joins("LEFT JOIN inventories ON donation_inventories.inventory_id = inventories.id").
+		Where("donation_inventories.donor_id = ? AND donation_inventories.donation_date >= ? AND donation_inventories.donation_date < ?", donorID, start, end).
+		Order("donation_inventories.donation_date, donation_inventories.id").
+		Scan(&items).Error
+	return items, err
+}
+
+// Donors 返回在 [start, end) 内有捐款或实物捐赠的捐赠者 id（升序）
+func (r *ReceiptRepository) Donors(start, end time.Time) ([]uint, error) {
+	var cash, inKind []uint
+	if err := r.db.Model(&models.Donation{}).
+		Where("donation_date >= ? AND donation_date < ?", start, end).
+		Distinct().Pluck("donor_id", &cash).Error; err != nil {
+		return nil, err
+	}
+	if err := r.db.Model(&models.DonationInventory{}).
+		Where("donation_date >= ? AND donation_date < ?", start, end).
+		Distinct().Pluck("donor_id", &inKind).Error; err != nil {
+		return nil, err
+	}
+	seen := make(map[uint]bool)
+	var ids []uint
+	for _, id := range append(cash, inKind...) {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids, nil
+}
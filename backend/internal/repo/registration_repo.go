@@ -0,0 +1,66 @@
+package repo
+
+import (
+	"erp-backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// RegistrationRepository 待审核的员工注册及其审核记录
+type RegistrationRepository struct {
+	db *gorm.DB
+}
+
+func NewRegistrationRepository(db *gorm.DB) *RegistrationRepository {
+	return &RegistrationRepository{db: db}
+}
+
+// pendingEmployees 账号仍为 pending 的员工档案
+func (r *RegistrationRepository) pendingEmployees(db *gorm.DB) *gorm.DB {
+	return db.Joins("JOIN users ON users.id = employees.user_id").
+		Where("users.user_type = ? AND users.status = ?", "employee", "pending")
+}
+
+// PendingEmployees 按注册时间返回所有待审核的员工档案（附带账号）
+func (r *RegistrationRepository) PendingEmployees() ([]models.Employee, error) {
+	var employees []models.Employee
+	err := r.pendingEmployees(r.db).Preload("User").Order("users.created_at, users.id").Find(&employees).Error
+	return employees, err
+}
+
+// GetPendingEmployee 读取某账号待审核的员工档案（附带账号），并在事务中锁定该行
+func (r *RegistrationRepository) GetPendingEmployee(userID uint) (*models.Employee, error) {
+	var employee models.Employee
+	err := r.pendingEmployees(forUpdate(r.db)).Preload("User").Where("employees.user_id = ?", userID).First(&employee).Error
+	if err != nil {
+		return nil, err
+	}
+	return &employee, nil
+}
+
+// SetUserStatus 仅当账号仍处于 from 状态时改为 to；返回是否更新成功
+func (r *RegistrationRepository) SetUserStatus(userID uint, from, to string) (bool, error) {
+	res := r.db.Model(&models.User{}).Where("id = ? AND status = ?", userID, from).Update("status", to)
+	return res.RowsAffected == 1, res.Error
+}
+
+// UpdateEmployee 更新员工档案的指定字段
+func (r *RegistrationRepository) UpdateEmployee(id uint, updates map[string]interface{}) error {
+	return r.db.Model(&models.Employee{}).Where("id = ?", id).Updates(updates).Error
+}
+
+// CreateReview 追加一条审核记录
+func (r *RegistrationRepository) CreateReview(review *models.RegistrationReview) error {
+	return r.db.Create(review).Error
+}
+
+// GetReviews 返回审核记录，userID 为 0 时返回全部，最新的在前
+func (r *RegistrationRepository) GetReviews(userID uint) ([]models.RegistrationReview, error) {
+	var reviews []models.RegistrationReview
+	db := r.db.Order("created_at DESC, id DESC")
+	if userID != 0 {
+		db = db.Where("user_id = ?", userID)
+	}
+	err := db.Find(&reviews).Error
+	return reviews, err
+}
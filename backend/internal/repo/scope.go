@@ -0,0 +1,101 @@
+package repo
+
+import (
+	"erp-backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// ProjectScope 行级数据范围：只能访问所分配项目的数据。
+// nil 表示不受限（拥有全机构权限的调用者）。
+type ProjectScope struct {
+	ProjectIDs []uint
+	EmployeeID uint // 调用者的员工 ID，本人的支出与排班不受项目限制
+}
+
+// 与项目关联的表上，"本人记录"的判定条件
+const (
+	expenseOwnerCond  = "expenses.employee_id = ?"
+	scheduleOwnerCond = "(schedules.person_type = 'employee' AND schedules.person_id = ?)"
+)
+
+// apply 在查询上追加范围条件：column 属于可见项目，或满足 ownerCond（本人记录）。
+// 返回的 *gorm.DB 可作为仓储的基础连接反复使用。
+func (s *ProjectScope) apply(db *gorm.DB, column, ownerCond string) *gorm.DB {
+	if s == nil {
+		return db
+	}
+	cond, args := column+" IN ?", []interface{}{s.ProjectIDs}
+	if len(s.ProjectIDs) == 0 {
+		cond, args = "1 = 0", nil
+	}
+	if ownerCond != "" && s.EmployeeID != 0 {
+		cond, args = "("+cond+" OR "+ownerCond+")", append(args, s.EmployeeID)
+	}
+	return db.Where(cond, args...).Session(&gorm.Session{})
+}
+
+// Allows 判断项目是否在范围内；未关联项目的记录只对不受限的调用者开放
+func (s *ProjectScope) Allows(projectID *uint) bool {
+	if s == nil {
+		return true
+	}
+	if projectID == nil {
+		return false
+	}
+	for _, id := range s.ProjectIDs {
+		if id == *projectID {
+			return true
+		}
+	}
+	return false
+}
+
+// Owns 判断员工是否为调用者本人
+func (s *ProjectScope) Owns(employeeID *uint) bool {
+	return s == nil || (employeeID != nil && s.EmployeeID != 0 && *employeeID == s.EmployeeID)
+}
+
+// AssignedProjectIDs 返回员工参与的全部项目（含内部项目）
+func (r *EmployeeProjectRepository) AssignedProjectIDs(employeeID uint) ([]uint, error) {
+	var ids []uint
+	err := r.db.Model(&models.EmployeeProject{}).
+		Where("employee_id = ?", employeeID).
+		Distinct().Pluck("project_id", &ids).Error
+	return ids, err
+}
+
+// Scoped 返回只读取范围内捐赠的仓储副本；写操作请使用未限定的仓储
+func (r *DonationRepository) Scoped(s *ProjectScope) *DonationRepository {
+	return &DonationRepository{db: s.apply(r.db, "donations.project_id", "")}
+}
+
+// Scoped 返回只读取范围内支出的仓储副本，本人填报的支出始终可见
+func (r *ExpenseRepository) Scoped(s *ProjectScope) *ExpenseRepository {
+	return &ExpenseRepository{db: s.apply(r.db, "expenses.project_id", expenseOwnerCond)}
+}
+
+// Scoped 返回只读取范围内基金拨款的仓储副本
+func (r *FundProjectRepository) Scoped(s *ProjectScope) *FundProjectRepository {
+	return &FundProjectRepository{db: s.apply(r.db, "fund_projects.project_id", "")}
+}
+
+// Scoped 返回只读取范围内排班的仓储副本，本人的排班始终可见
+func (r *ScheduleRepository) Scoped(s *ProjectScope) *ScheduleRepository {
+	return &ScheduleRepository{db: s.apply(r.db, "schedules.project_id", scheduleOwnerCond)}
+}
+
+// Scoped 返回只读取范围内志愿者分配的仓储副本
+func (r *VolunteerProjectRepository) Scoped(s *ProjectScope) *VolunteerProjectRepository {
+	return &VolunteerProjectRepository{db: s.apply(r.db, "volunteer_projects.project_id", "")}
+}
+
+// Scoped 返回只聚合范围内数据的图表仓储副本
+func (r *ChartRepository) Scoped(s *ProjectScope) *ChartRepository {
+	return &ChartRepository{db: r.db, scope: s}
+}
+
+// Scoped 返回按项目统计配送成本时只包含范围内配送的计价仓储副本
+func (r *CostingRepository) Scoped(s *ProjectScope) *CostingRepository {
+	return &CostingRepository{db: r.db, scope: s}
+}
@@ -0,0 +1,168 @@
+package repo
+
+import (
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"erp-backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+func TestScopedRepositoriesHideOtherProjects(t *testing.T) {
+	db := openMigratedDB(t)
+	wells := models.Project{ProjectID: "PRJ-1", Name: "Wells"}
+	school := models.Project{ProjectID: "PRJ-2", Name: "School"}
+	donor := models.Donor{DonorID: "DNR-1", FirstName: "Ada", LastName: "Lovelace"}
+	fund := models.Fund{FundID: "FND-1", Name: "General", FundType: models.FundTypeUnrestricted, TotalAmount: 100}
+	me := models.Employee{EmployeeID: "EMP-1", FirstName: "Sam", LastName: "Field", HireDate: time.Now().UTC()}
+	for _, v := range []interface{}{&wells, &school, &donor, &fund, &me} {
+		if err := db.Create(v).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// 每个项目一笔捐赠与一笔支出，外加一笔未关联项目的捐赠和一笔本人在 School 填报的支出
+	donationIDs := map[string]uint{}
+	for name, projectID := range map[string]*uint{"wells": &wells.ID, "school": &school.ID, "none": nil} {
+		donation := models.Donation{DonationID: "DON-" + name, DonorID: &donor.ID, ProjectID: projectID, Amount: 10,
+			DonationType: "one-time", Category: "general", DonationDate: time.Now().UTC()}
+		if err := db.Create(&donation).Error; err != nil {
+			t.Fatal(err)
+		}
+		donationIDs[name] = donation.ID
+	}
+	expenseIDs := map[string]uint{}
+	for name, e := range map[string]models.Expense{
+		"wells":     {ProjectID: &wells.ID},
+		"school":    {ProjectID: &school.ID},
+		"my-school": {ProjectID: &school.ID, EmployeeID: &me.ID},
+	} {
+		e.ExpenseID, e.FundID, e.Description, e.Amount = "EXP-"+name, &fund.ID, name, 5
+		if err := db.Create(&e).Error; err != nil {
+			t.Fatal(err)
+		}
+		expenseIDs[name] = e.ID
+	}
+
+	page := ListParams{Page: 1, PageSize: 50}
+	bothProjects := Filter{Or: []Filter{
+		{Field: "project.name", Op: OpEq, Value: "Wells"},
+		{Field: "project.name", Op: OpEq, Value: "School"},
+	}}
+	tests := []struct {
+		name      string
+		scope     *ProjectScope
+		donations []string
+		expenses  []string
+	}{
+		{"unrestricted", nil, []string{"none", "school", "wells"}, []string{"my-school", "school", "wells"}},
+		{"wells member", &ProjectScope{ProjectIDs: []uint{wells.ID}, EmployeeID: me.ID}, []string{"wells"}, []string{"my-school", "wells"}},
+		{"no projects", &ProjectScope{EmployeeID: me.ID}, nil, []string{"my-school"}},
+		{"no employee profile", &ProjectScope{ProjectIDs: []uint{wells.ID}}, []string{"wells"}, []string{"wells"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			donations := NewDonationRepository(db).Scoped(tt.scope)
+			listed, _, err := donations.List(page)
+			if err != nil {
+				t.Fatal(err)
+			}
+			searched, err := donations.Search(map[string]interface{}{"donor_id": donor.ID})
+			if err != nil {
+				t.Fatal(err)
+			}
+			filtered, _, err := donations.Filter(bothProjects, page)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// 关联字段过滤只匹配有项目的捐赠
+			var withProject []string
+			for _, n := range tt.donations {
+				if n != "none" {
+					withProject = append(withProject, n)
+				}
+			}
+			requireNames(t, "donations List", donationCodes(listed), prefixed("DON-", tt.donations))
+			requireNames(t, "donations Search", donationCodes(searched), prefixed("DON-", tt.donations))
+			requireNames(t, "donations Filter", donationCodes(filtered), prefixed("DON-", withProject))
+			for name, id := range donationIDs {
+				_, err := donations.GetByID(id)
+				if visible := contains(tt.donations, name); visible != (err == nil) {
+					t.Errorf("donations GetByID(%s) = %v, visible %v", name, err, visible)
+				} else if !visible && !errors.Is(err, gorm.ErrRecordNotFound) {
+					t.Errorf("donations GetByID(%s) = %v, want ErrRecordNotFound", name, err)
+				}
+			}
+
+			expenses := NewExpenseRepository(db).Scoped(tt.scope)
+			listedExpenses, _, err := expenses.List(page)
+			if err != nil {
+				t.Fatal(err)
+			}
+			filteredExpenses, _, err := expenses.Filter(bothProjects, page)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var listedCodes, filteredCodes []string
+			for _, e := range listedExpenses {
+				listedCodes = append(listedCodes, e.ExpenseID)
+			}
+			for _, e := range filteredExpenses {
+				filteredCodes = append(filteredCodes, e.ExpenseID)
+			}
+			requireNames(t, "expenses List", listedCodes, prefixed("EXP-", tt.expenses))
+			requireNames(t, "expenses Filter", filteredCodes, prefixed("EXP-", tt.expenses))
+			for name, id := range expenseIDs {
+				_, err := expenses.GetByID(id)
+				if visible := contains(tt.expenses, name); visible != (err == nil) {
+					t.Errorf("expenses GetByID(%s) = %v, visible %v", name, err, visible)
+				}
+			}
+		})
+	}
+}
+
+func donationCodes(donations []models.Donation) []string {
+	var codes []string
+	for _, d := range donations {
+		codes = append(codes, d.DonationID)
+	}
+	return codes
+}
+
+func prefixed(prefix string, names []string) []string {
+	var out []string
+	for _, n := range names {
+		out = append(out, prefix+n)
+	}
+	return out
+}
+
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// requireNames 比较两组编号，不考虑顺序
+func requireNames(t *testing.T, what string, got, want []string) {
+	t.Helper()
+	sort.Strings(got)
+	sort.Strings(want)
+	if len(got) != len(want) {
+		t.Errorf("%s returned %v, want %v", what, got, want)
+		return
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			t.Errorf("%s returned %v, want %v", what, got, want)
+			return
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package repo
+
+import (
+	"time"
+
+	"erp-backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// SessionRepository 登录会话与刷新令牌
+type SessionRepository struct {
+	db *gorm.DB
+}
+
+func NewSessionRepository(db *gorm.DB) *SessionRepository {
+	return &SessionRepository{db: db}
+}
+
+func (r *SessionRepository) CreateSession(session *models.Session) error {
+	return r.db.Create(session).Error
+}
+
+// GetSessionByID 读取会话并锁定该行
+func (r *SessionRepository) GetSessionByID(id uint) (*models.Session, error) {
+	var session models.Session
+	if err := forUpdate(r.db).First(&session, id).Error; err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (r *SessionRepository) GetSessionBySID(sid string) (*models.Session, error) {
+	var session models.Session
+	if err := r.db.Where("session_id = ?", sid).First(&session).Error; err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// ActiveSessions 返回用户未终止且未过期的会话，userID 为 0 时返回全部用户的
+func (r *SessionRepository) ActiveSessions(userID uint) ([]models.Session, error) {
+	tx := r.db.Where("revoked_at IS NULL AND expires_at > ?", time.Now().UTC())
+	if userID != 0 {
+		tx = tx.Where("user_id = ?", userID)
+	}
+	var sessions []models.Session
+	err := tx.Order("last_used_at DESC").Find(&sessions).Error
+	return sessions, err
+}
+
+// RevokedSince 返回 since 之后被终止的会话 ID，用于重建撤销列表
+func (r *SessionRepository) RevokedSince(since time.Time) ([]models.Session, error) {
+	var sessions []models.Session
+	err := r.db.Where("revoked_at > ?", since).Find(&sessions).Error
+	return sessions, err
+}
+
+// Touch 记录会话最近一次刷新及新的过期时间
+func (r *SessionRepository) Touch(id uint, usedAt, expiresAt time.Time) error {
+	return r.db.Model(&models.Session{}).Where("id = ?", id).
+		Updates(map[string]interface{}{"last_used_at": usedAt, "expires_at": expiresAt}).Error
+}
+
+// Revoke 终止会话；已终止的会话保持原状态，返回是否由本次调用终止
+func (r *SessionRepository) Revoke(id uint, reason string, at time.Time) (bool, error) {
+	res := r.db.Model(&models.Session{}).Where("id = ? AND revoked_at IS NULL", id).
+		Updates(map[string]interface{}{"revoked_at": at, "revoke_reason": reason})
+	return res.RowsAffected > 0, res.Error
+}
+
+func (r *SessionRepository) CreateRefreshToken(token *models.RefreshToken) error {
+	return r.db.Create(token).Error
+}
+
+func (r *SessionRepository) GetRefreshToken(hash string) (*models.RefreshToken, error) {
+	var token models.RefreshToken
+	if err := forUpdate(r.db).Where("token_hash = ?", hash).First(&token).Error; err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// MarkRefreshTokenUsed 以条件更新作废刷新令牌；返回 false 表示令牌已被并发使用
+func (r *SessionRepository) MarkRefreshTokenUsed(id uint, at time.Time) (bool, error) {
+	res := r.db.Model(&models.RefreshToken{}).Where("id = ? AND used_at IS NULL", id).Update("used_at", at)
+	return res.RowsAffected > 0, res.Error
+}
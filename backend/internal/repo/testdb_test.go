@@ -0,0 +1,85 @@
+package repo
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// 仓储测试默认使用内存 SQLite；设置 DB_DRIVER=postgres（或 mysql）与 DB_DSN 时改在该数据库上运行，
+// 例如 README 中用 docker 启动的 PostgreSQL。测试会清空该数据库，只能指向专用的测试库
+
+// openTestDB 打开一个空数据库；测试结束时关闭连接
+func openTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	driver, dsn := os.Getenv("DB_DRIVER"), os.Getenv("DB_DSN")
+	if driver == "" || driver == DriverSQLite {
+		// 每个测试使用独立的共享缓存内存库，同一测试的多个连接看到同一份数据
+		driver = DriverSQLite
+		dsn = fmt.Sprintf("file:%s?mode=memory&cache=shared", strings.ReplaceAll(t.Name(), "/", "_"))
+	}
+	dialector, err := openDialector(driver, dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err := gorm.Open(dialector, &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("connect %s: %v", driver, err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { sqlDB.Close() })
+	if err := resetTestDB(db); err != nil {
+		t.Fatalf("reset %s test database: %v", driver, err)
+	}
+	return db
+}
+
+// openMigratedDB 打开一个空数据库并执行全部迁移
+func openMigratedDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db := openTestDB(t)
+	m, err := NewMigrator(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Up(); err != nil {
+		t.Fatalf("migrate up: %v", err)
+	}
+	return db
+}
+
+// resetTestDB 删除外部测试库中的全部表；内存 SQLite 本来就是空的
+func resetTestDB(db *gorm.DB) error {
+	switch db.Dialector.Name() {
+	case DriverPostgres:
+		if err := db.Exec("DROP SCHEMA public CASCADE").Error; err != nil {
+			return err
+		}
+		return db.Exec("CREATE SCHEMA public").Error
+	case DriverMySQL:
+		tables, err := db.Migrator().GetTables()
+		if err != nil {
+			return err
+		}
+		return db.Connection(func(conn *gorm.DB) error {
+			if err := conn.Exec("SET FOREIGN_KEY_CHECKS = 0").Error; err != nil {
+				return err
+			}
+			defer conn.Exec("SET FOREIGN_KEY_CHECKS = 1")
+			for _, table := range tables {
+				if err := conn.Exec("DROP TABLE IF EXISTS `" + table + "`").Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	}
+	return nil
+}
@@ -0,0 +1,176 @@
+package repo
+
+import (
+	"reflect"
+	"sort"
+
+	"erp-backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// trashResource 回收站中的一类资源：表、模型与（可选的）项目范围列
+type trashResource struct {
+	table     string
+	model     func() interface{}
+	column    string // 按项目限定可见范围的列，为空表示不按项目限定
+	ownerCond string // 本人记录的判定条件，见 ProjectScope.apply
+}
+
+// trashResources 支持回收站的资源，键与 /api/v1/dbms 下的路由资源名一致
+var trashResources = map[string]trashResource{
+	"users":                  {table: "users", model: func() interface{} { return &models.User{} }},
+	"projects":               {table: "projects", model: func() interface{} { return &models.Project{} }},
+	"donors":                 {table: "donors", model: func() interface{} { return &models.Donor{} }},
+	"donations":              {table: "donations", model: func() interface{} { return &models.Donation{} }, column: "donations.project_id"},
+	"volunteers":             {table: "volunteers", model: func() interface{} { return &models.Volunteer{} }},
+	"employees":              {table: "employees", model: func() interface{} { return &models.Employee{} }},
+	"locations":              {table: "locations", model: func() interface{} { return &models.Location{} }},
+	"funds":                  {table: "funds", model: func() interface{} { return &models.Fund{} }},
+	"expenses":               {table: "expenses", model: func() interface{} { return &models.Expense{} }, column: "expenses.project_id", ownerCond: expenseOwnerCond},
+	"transactions":           {table: "transactions", model: func() interface{} { return &models.Transaction{} }},
+	"purchases":              {table: "purchases", model: func() interface{} { return &models.Purchase{} }},
+	"payrolls":               {table: "payrolls", model: func() interface{} { return &models.Payroll{} }},
+	"inventory":              {table: "inventories", model: func() interface{} { return &models.Inventory{} }},
+	"gift-types":             {table: "gift_types", model: func() interface{} { return &models.GiftType{} }},
+	"gifts":                  {table: "gifts", model: func() interface{} { return &models.Gift{} }},
+	"inventory-transactions": {table: "inventory_transactions", model: func() interface{} { return &models.InventoryTransaction{} }},
+	"deliveries":             {table: "deliveries", model: func() interface{} { return &models.Delivery{} }},
+	"volunteer-projects":     {table: "volunteer_projects", model: func() interface{} { return &models.VolunteerProject{} }, column: "volunteer_projects.project_id"},
+	"employee-projects":      {table: "employee_projects", model: func() interface{} { return &models.EmployeeProject{} }},
+	"fund-projects":          {table: "fund_projects", model: func() interface{} { return &models.FundProject{} }, column: "fund_projects.project_id"},
+	"donation-inventory":     {table: "donation_inventories", model: func() interface{} { return &models.DonationInventory{} }},
+	"delivery-inventory":     {table: "delivery_inventories", model: func() interface{} { return &models.DeliveryInventory{} }},
+	"schedules":              {table: "schedules", model: func() interface{} { return &models.Schedule{} }, column: "schedules.project_id", ownerCond: scheduleOwnerCond},
+}
+
+// TrashResources 返回支持回收站的资源名（按字母排序），用于注册路由
+func TrashResources() []string {
+	names := make([]string, 0, len(trashResources))
+	for name := range trashResources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// IsTrashResource 判断资源是否支持回收站
+func IsTrashResource(resource string) bool {
+	_, ok := trashResources[resource]
+	return ok
+}
+
+// softDeleteColumns 软删除时写入的列：删除时间与操作人（取自 context 中的审计操作人）
+func softDeleteColumns(db *gorm.DB) map[string]interface{} {
+	return map[string]interface{}{"deleted_at": db.NowFunc(), "deleted_by": actorID(db)}
+}
+
+// actorID 返回 context 中审计操作人的用户 id，没有时为 nil
+func actorID(db *gorm.DB) *uint {
+	if actor, ok := db.Statement.Context.Value(auditActorKey{}).(AuditActor); ok && actor.UserID != 0 {
+		id := actor.UserID
+		return &id
+	}
+	return nil
+}
+
+// softDelete 把一行移入回收站并按引用策略处理引用它的记录，见 softDeleteRow
+func softDelete(db *gorm.DB, model interface{}, id uint) error {
+	table, err := tableOf(db, model)
+	if err != nil {
+		return err
+	}
+	return softDeleteRow(db, table, id)
+}
+
+// saveLive 与 Save 相同地保存整行，但只更新未删除的行：记录已在回收站或不存在时返回
+// gorm.ErrRecordNotFound，而不是像 Save 那样退化为插入；请求体中的删除标记不会被写入
+func saveLive(db *gorm.DB, value interface{}, omit ...string) error {
+	res := db.Select("*").Omit(append(omit, "deleted_at", "deleted_by")...).Save(value)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// TrashRepository 回收站：列出、恢复与彻底删除已软删除的行
+type TrashRepository struct {
+	db    *gorm.DB
+	scope *ProjectScope // nil 表示不受项目范围限制
+}
+
+func NewTrashRepository(db *gorm.DB) *TrashRepository {
+	return &TrashRepository{db: db}
+}
+
+// Scoped 返回只操作范围内记录的仓储副本；不按项目划分的资源不受影响
+func (r *TrashRepository) Scoped(s *ProjectScope) *TrashRepository {
+	return &TrashRepository{db: r.db, scope: s}
+}
+
+// trashed 构造某资源回收站中记录的查询
+func (r *TrashRepository) trashed(resource string) (*gorm.DB, interface{}, error) {
+	res, ok := trashResources[resource]
+	if !ok {
+		return nil, nil, gorm.ErrRecordNotFound
+	}
+	model := res.model()
+	q := r.db.Unscoped().Model(model).Where("deleted_at IS NOT NULL")
+	if res.column != "" {
+		q = r.scope.apply(q, res.column, res.ownerCond)
+	}
+	return q, model, nil
+}
+
+// List 返回某资源回收站中的记录（模型切片）及条数，最近删除的在前
+func (r *TrashRepository) List(resource string) (interface{}, int, error) {
+	q, model, err := r.trashed(resource)
+	if err != nil {
+		return nil, 0, err
+	}
+	list := reflect.New(reflect.SliceOf(reflect.TypeOf(model).Elem()))
+	if err := q.Order("deleted_at DESC").Find(list.Interface()).Error; err != nil {
+		return nil, 0, err
+	}
+	return list.Elem().Interface(), list.Elem().Len(), nil
+}
+
+// Restore 把记录及随其级联删除的记录移出回收站；记录不在回收站时返回 gorm.ErrRecordNotFound
+func (r *TrashRepository) Restore(resource string, id uint) error {
+	q, _, err := r.trashed(resource)
+	if err != nil {
+		return err
+	}
+	// 先按删除时间恢复级联删除的记录；记录不在范围内时由调用方的事务回滚
+	if err := restoreTree(r.db, trashResources[resource].table, []uint{id}); err != nil {
+		return err
+	}
+	res := q.Where("id = ?", id).UpdateColumns(map[string]interface{}{"deleted_at": nil, "deleted_by": nil})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// Purge 从数据库中彻底删除回收站中的记录；只能彻底删除已软删除的行，
+// 仍被引用（含回收站中的记录）时返回 *DependentsError
+func (r *TrashRepository) Purge(resource string, id uint) error {
+	q, _, err := r.trashed(resource)
+	if err != nil {
+		return err
+	}
+	var count int64
+	if err := q.Where("id = ?", id).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return purgeRow(r.db, trashResources[resource].table, id)
+}
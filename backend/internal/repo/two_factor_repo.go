@@ -0,0 +1,98 @@
+package repo
+
+import (
+	"time"
+
+	"erp-backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// TwoFactorRepository 双因素认证密钥、恢复码与登录挑战
+type TwoFactorRepository struct {
+	db *gorm.DB
+}
+
+func NewTwoFactorRepository(db *gorm.DB) *TwoFactorRepository {
+	return &TwoFactorRepository{db: db}
+}
+
+// Get 读取账号的双因素认证设置并锁定该行
+func (r *TwoFactorRepository) Get(userID uint) (*models.TwoFactor, error) {
+	var tf models.TwoFactor
+	if err := forUpdate(r.db).First(&tf, "user_id = ?", userID).Error; err != nil {
+		return nil, err
+	}
+	return &tf, nil
+}
+
+func (r *TwoFactorRepository) Save(tf *models.TwoFactor) error {
+	return r.db.Save(tf).Error
+}
+
+// Delete 删除账号的双因素认证设置及其恢复码；返回是否存在设置
+func (r *TwoFactorRepository) Delete(userID uint) (bool, error) {
+	if err := r.db.Delete(&models.RecoveryCode{}, "user_id = ?", userID).Error; err != nil {
+		return false, err
+	}
+	res := r.db.Delete(&models.TwoFactor{}, "user_id = ?", userID)
+	return res.RowsAffected > 0, res.Error
+}
+
+// UseStep 仅当时间步晚于上次使用的时间步时记录；返回 false 表示验证码已被使用过
+func (r *TwoFactorRepository) UseStep(userID uint, step int64) (bool, error) {
+	res := r.db.Model(&models.TwoFactor{}).Where("user_id = ? AND last_used_step < ?", userID, step).
+		Update("last_used_step", step)
+	return res.RowsAffected == 1, res.Error
+}
+
+// ReplaceRecoveryCodes 删除旧恢复码并写入新的一组
+func (r *TwoFactorRepository) ReplaceRecoveryCodes(userID uint, hashes []string) error {
+	if err := r.db.Delete(&models.RecoveryCode{}, "user_id = ?", userID).Error; err != nil {
+		return err
+	}
+	codes := make([]models.RecoveryCode, 0, len(hashes))
+	for _, h := range hashes {
+		codes = append(codes, models.RecoveryCode{UserID: userID, CodeHash: h})
+	}
+	return r.db.Create(&codes).Error
+}
+
+// UseRecoveryCode 仅当恢复码存在且未使用时标记为已使用；返回是否成功
+func (r *TwoFactorRepository) UseRecoveryCode(userID uint, hash string, at time.Time) (bool, error) {
+	res := r.db.Model(&models.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, hash).Update("used_at", at)
+	return res.RowsAffected > 0, res.Error
+}
+
+// RemainingRecoveryCodes 统计未使用的恢复码数量
+func (r *TwoFactorRepository) RemainingRecoveryCodes(userID uint) (int64, error) {
+	var n int64
+	err := r.db.Model(&models.RecoveryCode{}).Where("user_id = ? AND used_at IS NULL", userID).Count(&n).Error
+	return n, err
+}
+
+func (r *TwoFactorRepository) CreateChallenge(challenge *models.LoginChallenge) error {
+	return r.db.Create(challenge).Error
+}
+
+// GetChallenge 按哈希读取登录挑战并锁定该行
+func (r *TwoFactorRepository) GetChallenge(hash string) (*models.LoginChallenge, error) {
+	var challenge models.LoginChallenge
+	if err := forUpdate(r.db).Where("token_hash = ?", hash).First(&challenge).Error; err != nil {
+		return nil, err
+	}
+	return &challenge, nil
+}
+
+// RecordChallengeFailure 累加一次错误验证码
+func (r *TwoFactorRepository) RecordChallengeFailure(id uint) error {
+	return r.db.Model(&models.LoginChallenge{}).Where("id = ?", id).
+		Update("attempts", gorm.Expr("attempts + 1")).Error
+}
+
+// CompleteChallenge 仅当挑战尚未使用时标记为已使用；返回是否成功
+func (r *TwoFactorRepository) CompleteChallenge(id uint, at time.Time) (bool, error) {
+	res := r.db.Model(&models.LoginChallenge{}).Where("id = ? AND used_at IS NULL", id).Update("used_at", at)
+	return res.RowsAffected == 1, res.Error
+}
@@ -0,0 +1,103 @@
+package repo
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Store 为需要跨表写入的服务提供事务入口
+type Store struct {
+	db *gorm.DB
+}
+
+func NewStore(db *gorm.DB) *Store {
+	return &Store{db: db}
+}
+
+// Tx 绑定到同一个数据库事务上的仓储集合
+type Tx struct {
+	db             *gorm.DB
+	Donors         *DonorRepository
+	Donations      *DonationRepository
+	Funds          *FundRepository
+	Transactions   *TransactionRepository
+	Gifts          *GiftRepository
+	Expenses       *ExpenseRepository
+	Purchases      *PurchaseRepository
+	Payrolls       *PayrollRepository
+	FundProjects   *FundProjectRepository
+	Employees      *EmployeeRepository
+	Projects       *ProjectRepository
+	Ledger         *LedgerRepository
+	Approvals      *ApprovalRepository
+	Sessions       *SessionRepository
+	Registrations  *RegistrationRepository
+	PasswordResets *PasswordResetRepository
+	Users          *UserRepository
+	LoginAttempts  *LoginAttemptRepository
+	TwoFactor      *TwoFactorRepository
+	Trash          *TrashRepository
+	Inventories    *InventoryRepository
+	Receipts       *ReceiptRepository
+
+	InventoryTransactions *InventoryTransactionRepository
+	DonationInventories   *DonationInventoryRepository
+	Deliveries            *DeliveryRepository
+	DeliveryInventories   *DeliveryInventoryRepository
+	Costing               *CostingRepository
+}
+
+func newTx(db *gorm.DB) *Tx {
+	return &Tx{
+		db:             db,
+		Donors:         NewDonorRepository(db),
+		Donations:      NewDonationRepository(db),
+		Funds:          NewFundRepository(db),
+		Transactions:   NewTransactionRepository(db),
+		Gifts:          NewGiftRepository(db),
+		Expenses:       NewExpenseRepository(db),
+		Purchases:      NewPurchaseRepository(db),
+		Payrolls:       NewPayrollRepository(db),
+		FundProjects:   NewFundProjectRepository(db),
+		Employees:      NewEmployeeRepository(db),
+		Projects:       NewProjectRepository(db),
+		Ledger:         NewLedgerRepository(db),
+		Approvals:      NewApprovalRepository(db),
+		Sessions:       NewSessionRepository(db),
+		Registrations:  NewRegistrationRepository(db),
+		PasswordResets: NewPasswordResetRepository(db),
+		Users:          NewUserRepository(db),
+		LoginAttempts:  NewLoginAttemptRepository(db),
+		TwoFactor:      NewTwoFactorRepository(db),
+		Trash:          NewTrashRepository(db),
+		Inventories:    NewInventoryRepository(db),
+		Receipts:       NewReceiptRepository(db),
+
+		InventoryTransactions: NewInventoryTransactionRepository(db),
+		DonationInventories:   NewDonationInventoryRepository(db),
+		Deliveries:            NewDeliveryRepository(db),
+		DeliveryInventories:   NewDeliveryInventoryRepository(db),
+		Costing:               NewCostingRepository(db),
+	}
+}
+
+// Transaction runs fn inside a single GORM transaction. Any error returned by fn
+// (or a panic) rolls back every write made through the Tx repositories.
+func (s *Store) Transaction(fn func(tx *Tx) error) error {
+	return s.db.Transaction(func(db *gorm.DB) error {
+		return fn(newTx(db))
+	})
+}
+
+// Transaction 在当前事务内以保存点运行 fn：fn 返回错误时只回滚它自己的写入，外层事务可以继续
+func (t *Tx) Transaction(fn func(tx *Tx) error) error {
+	return t.db.Transaction(func(db *gorm.DB) error {
+		return fn(newTx(db))
+	})
+}
+
+// forUpdate adds a row lock (SELECT ... FOR UPDATE) on dialects that support it.
+// SQLite ignores the clause and relies on its database-level write lock instead.
+func forUpdate(db *gorm.DB) *gorm.DB {
+	return db.Clauses(clause.Locking{Strength: "UPDATE"})
+}
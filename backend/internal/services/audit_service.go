@@ -0,0 +1,28 @@
+package services
+
+import (
+	"erp-backend/internal/models"
+	"erp-backend/internal/repo"
+)
+
+// AuditService 审计日志查询；记录由数据库回调写入，这里只读
+type AuditService struct {
+	repo *repo.AuditRepository
+}
+
+func NewAuditService(auditRepo *repo.AuditRepository) *AuditService {
+	return &AuditService{repo: auditRepo}
+}
+
+// Search 按操作人、表、记录与时间范围查询审计日志
+func (s *AuditService) Search(filter repo.AuditFilter) ([]models.AuditLog, error) {
+	switch filter.Action {
+	case "", models.AuditCreate, models.AuditUpdate, models.AuditDelete, models.AuditRestore, models.AuditPurge:
+	default:
+		return nil, invalidInput("action must be one of create, update, delete, restore, purge")
+	}
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return nil, invalidInput("from must be before to")
+	}
+	return s.repo.Search(filter)
+}
@@ -19,6 +19,11 @@ type AuthService struct {
 	employeeRepo  *repo.EmployeeRepository
 	volunteerRepo *repo.VolunteerRepository
 	donorRepo     *repo.DonorRepository
+	rbacRepo      *repo.RBACRepository
+	sessions      *SessionService
+	passwords     *PasswordService
+	lockouts      *LockoutService
+	twoFactor     *TwoFactorService
 }
 
 // NewAuthService 创建认证服务实例
@@ -28,38 +33,86 @@ func NewAuthService(
 	employeeRepo *repo.EmployeeRepository,
 	volunteerRepo *repo.VolunteerRepository,
 	donorRepo *repo.DonorRepository,
+	rbacRepo *repo.RBACRepository,
+	sessions *SessionService,
+	passwords *PasswordService,
+	lockouts *LockoutService,
+	twoFactor *TwoFactorService,
 ) *AuthService {
 	return &AuthService{
 		userRepo:      userRepo,
 		employeeRepo:  employeeRepo,
 		volunteerRepo: volunteerRepo,
 		donorRepo:     donorRepo,
+		rbacRepo:      rbacRepo,
+		sessions:      sessions,
+		passwords:     passwords,
+		lockouts:      lockouts,
+		twoFactor:     twoFactor,
 	}
 }
 
 // RegisterRequest 注册请求
 type RegisterRequest struct {
 	Username  string `json:"username" binding:"required"`
-	Password  string `json:"password" binding:"required,min=6"`
+	Password  string `json:"password" binding:"required"` // 长度与字符要求见密码策略
 	UserType  string `json:"user_type" binding:"required"`
 	Email     string `json:"email" binding:"required,email"`
 	Phone     string `json:"phone"`
 	FirstName string `json:"first_name" binding:"required"`
 	LastName  string `json:"last_name" binding:"required"`
+
+	// 由处理器填写，记录在登录会话上
+	UserAgent string `json:"-"`
+	ClientIP  string `json:"-"`
 }
 
 // LoginRequest 登录请求
 type LoginRequest struct {
 	Username string `json:"username" binding:"required"`
 	Password string `json:"password" binding:"required"`
+
+	// 由处理器填写，记录在登录会话上
+	UserAgent string `json:"-"`
+	ClientIP  string `json:"-"`
+}
+
+// RefreshRequest 刷新令牌请求
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// TwoFactorLoginRequest 两步登录的第二步：提交挑战令牌与验证码（或恢复码）
+type TwoFactorLoginRequest struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+	Code           string `json:"code" binding:"required"`
+
+	// 由处理器填写，记录在登录会话上
+	UserAgent string `json:"-"`
+	ClientIP  string `json:"-"`
 }
 
-// AuthResponse 认证响应
+// TwoFactorSetupRequest 强制双因素认证但尚未登记的账号，凭挑战令牌获取登记信息
+type TwoFactorSetupRequest struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+}
+
+// AuthResponse 认证响应；待审核的账号不签发令牌
 type AuthResponse struct {
-	Token    string `json:"token"`
-	UserType string `json:"user_type"`
-	UserID   uint   `json:"user_id"`
-	RoleID   uint   `json:"role_id"`
+	Token        string   `json:"token,omitempty"`
+	ExpiresIn    int      `json:"expires_in,omitempty"` // 访问令牌有效秒数
+	RefreshToken string   `json:"refresh_token,omitempty"`
+	UserType     string   `json:"user_type"`
+	UserID       uint     `json:"user_id"`
+	Status       string   `json:"status"`
+	RoleID       uint     `json:"role_id"`
+	Roles        []string `json:"roles,omitempty"`
+
+	// 需要双因素认证时只返回挑战令牌，提交验证码后才签发令牌
+	ChallengeToken         string   `json:"challenge_token,omitempty"`
+	TwoFactorRequired      bool     `json:"two_factor_required,omitempty"`
+	TwoFactorSetupRequired bool     `json:"two_factor_setup_required,omitempty"` // 角色强制但尚未登记
+	RecoveryCodes          []string `json:"recovery_codes,omitempty"`            // 登录时完成登记才返回，只显示这一次
 }
 
 // generateID 生成唯一ID
@@ -84,10 +137,10 @@ func (s *AuthService) Register(req *RegisterRequest) (*AuthResponse, error) {
 		return nil, errors.New("username already exists")
 	}
 
-	// 加密密码
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	// 按密码策略校验并加密密码
+	hashedPassword, err := s.passwords.Hash(req.Password, req.Username)
 	if err != nil {
-		return nil, errors.New("failed to hash password")
+		return nil, err
 	}
 
 	var status string
@@ -101,7 +154,7 @@ func (s *AuthService) Register(req *RegisterRequest) (*AuthResponse, error) {
 	// 创建用户
 	user := &models.User{
 		Username:     req.Username,
-		PasswordHash: string(hashedPassword),
+		PasswordHash: hashedPassword,
 		UserType:     req.UserType,
 		Status:       status,
 		UpdatedAt:    time.Now(),
@@ -127,6 +180,12 @@ func (s *AuthService) Register(req *RegisterRequest) (*AuthResponse, error) {
 		if err := s.employeeRepo.Create(employee); err != nil {
 			return nil, errors.New("failed to create employee information: " + err.Error())
 		}
+		// 新员工默认获得 staff 角色，其余角色由管理员分配
+		if staff, err := s.rbacRepo.GetRoleByName(models.RoleStaff); err == nil {
+			if err := s.rbacRepo.AddUserRole(user.ID, staff.ID); err != nil {
+				return nil, errors.New("failed to assign default role: " + err.Error())
+			}
+		}
 
 	case "volunteer":
 		volunteer := &models.Volunteer{
@@ -162,83 +221,262 @@ func (s *AuthService) Register(req *RegisterRequest) (*AuthResponse, error) {
 		return nil, errors.New("invalid user type")
 	}
 
-	// 生成 JWT Token
-	token, err := utils.GenerateToken(user.ID, user.Username, user.UserType, 0)
-	if err != nil {
-		return nil, errors.New("failed to generate token")
+	// 员工账号需管理员在审核队列中批准后才能登录
+	if user.Status != "active" {
+		return &AuthResponse{UserType: user.UserType, UserID: user.ID, Status: user.Status}, nil
 	}
 
-	return &AuthResponse{
-		Token:    token,
-		UserType: user.UserType,
-		UserID:   user.ID,
-		RoleID:   0,
-	}, nil
+	session, refreshToken, err := s.sessions.Start(user.ID, req.UserAgent, req.ClientIP)
+	if err != nil {
+		return nil, errors.New("failed to start session")
+	}
+	return issueTokens(user, 0, nil, session, refreshToken)
 }
 
-// Login 用户登录
+// Login 用户登录；每次尝试都会记录，失败过多时账号或来源 IP 被暂时锁定。
+// 已启用或角色强制双因素认证的账号只返回挑战令牌，须再调用 VerifyTwoFactor
 func (s *AuthService) Login(req *LoginRequest) (*AuthResponse, error) {
+	attempt := LoginAttemptInfo{Username: req.Username, ClientIP: req.ClientIP, UserAgent: req.UserAgent}
+
 	// 查找用户
 	users, err := s.userRepo.Search(map[string]interface{}{"username": req.Username})
-	if err != nil || len(users) != 1 {
+	if err != nil {
+		return nil, errors.New("wrong username or password")
+	}
+	var found *models.User
+	if len(users) == 1 {
+		found = &users[0]
+	}
+
+	// 锁定期内即使密码正确也拒绝登录
+	if err := s.lockouts.Check(found, attempt); err != nil {
+		return nil, err
+	}
+	if found == nil {
+		if err := s.lockouts.RecordFailure(nil, attempt, models.LoginUnknownUser); err != nil {
+			return nil, err
+		}
 		return nil, errors.New("wrong username or password")
 	}
 
-	user := users[0]
+	user := *found
 
 	// 验证密码
 	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.Password)); err != nil {
+		if err := s.lockouts.RecordFailure(&user, attempt, models.LoginFailedPassword); err != nil {
+			return nil, err
+		}
 		return nil, errors.New("wrong username or password")
 	}
 
 	// 检查用户是否激活
+	if user.Status != "active" {
+		s.lockouts.RecordRejected(&user, attempt, models.LoginInactive)
+	}
+	if user.Status == "pending" {
+		return nil, errors.New("user account is awaiting administrator approval")
+	}
 	if user.Status != "active" {
 		return nil, errors.New("user account not active")
 	}
 
+	role_id, roles, err := s.identity(&user)
+	if err != nil {
+		return nil, err
+	}
+
+	enabled, required, err := s.twoFactorState(&user, roles)
+	if err != nil {
+		return nil, err
+	}
+	if enabled || required {
+		challenge, err := s.twoFactor.StartChallenge(user.ID)
+		if err != nil {
+			return nil, errors.New("failed to start two-factor challenge")
+		}
+		return &AuthResponse{
+			UserType:               user.UserType,
+			UserID:                 user.ID,
+			Status:                 user.Status,
+			ChallengeToken:         challenge,
+			TwoFactorRequired:      true,
+			TwoFactorSetupRequired: !enabled,
+		}, nil
+	}
+
+	return s.completeLogin(&user, role_id, roles, attempt)
+}
+
+// SetupTwoFactor 角色强制双因素认证但尚未登记时，凭登录挑战令牌开始登记
+func (s *AuthService) SetupTwoFactor(req *TwoFactorSetupRequest) (*TwoFactorEnrollment, error) {
+	userID, err := s.twoFactor.ChallengeUser(req.ChallengeToken)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, notFound(err, "user")
+	}
+	return s.twoFactor.Enroll(user.ID, user.Username, user.UserType)
+}
+
+// VerifyTwoFactor 两步登录的第二步：校验验证码后签发令牌。
+// 错误的验证码与错误的密码一样计入失败次数
+func (s *AuthService) VerifyTwoFactor(req *TwoFactorLoginRequest) (*AuthResponse, error) {
+	userID, err := s.twoFactor.ChallengeUser(req.ChallengeToken)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil || user.Status != "active" {
+		return nil, unauthorized("user account not active")
+	}
+	attempt := LoginAttemptInfo{Username: user.Username, ClientIP: req.ClientIP, UserAgent: req.UserAgent}
+	if err := s.lockouts.Check(user, attempt); err != nil {
+		return nil, err
+	}
+
+	recoveryCodes, err := s.twoFactor.VerifyChallenge(req.ChallengeToken, req.Code)
+	if errors.Is(err, errBadSecondFactor) {
+		if err := s.lockouts.RecordFailure(user, attempt, models.LoginBadTwoFactor); err != nil {
+			return nil, err
+		}
+		return nil, err
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	role_id, roles, err := s.identity(user)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.completeLogin(user, role_id, roles, attempt)
+	if err != nil {
+		return nil, err
+	}
+	resp.RecoveryCodes = recoveryCodes
+	return resp, nil
+}
+
+// completeLogin 登录成功：清除失败计数、开始会话、更新最近登录时间并签发令牌
+func (s *AuthService) completeLogin(user *models.User, roleID uint, roles []string, attempt LoginAttemptInfo) (*AuthResponse, error) {
+	if err := s.lockouts.RecordSuccess(user, attempt); err != nil {
+		return nil, err
+	}
+
+	session, refreshToken, err := s.sessions.Start(user.ID, attempt.UserAgent, attempt.ClientIP)
+	if err != nil {
+		return nil, errors.New("failed to start session")
+	}
+
+	// update last login time
+	now := time.Now().UTC()
+	user.LastLogin = &now
+	s.userRepo.Update(user)
+
+	return issueTokens(user, roleID, roles, session, refreshToken)
+}
+
+// twoFactorState 返回账号是否已启用双因素认证，以及其角色是否强制要求
+func (s *AuthService) twoFactorState(user *models.User, roles []string) (enabled, required bool, err error) {
+	if enabled, err = s.twoFactor.Enabled(user.ID); err != nil {
+		return false, false, err
+	}
+	if required, err = s.twoFactor.Required(roles); err != nil {
+		return false, false, err
+	}
+	return enabled, required, nil
+}
+
+// Refresh 用刷新令牌换取新的访问令牌与刷新令牌。
+// 角色与账号状态重新从数据库读取，停用的账号不能再刷新。
+func (s *AuthService) Refresh(req *RefreshRequest) (*AuthResponse, error) {
+	session, refreshToken, err := s.sessions.Rotate(req.RefreshToken)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.userRepo.GetByID(session.UserID)
+	if err != nil || user.Status != "active" {
+		s.sessions.End(session.SessionID)
+		return nil, unauthorized("user account not active")
+	}
+	role_id, roles, err := s.identity(user)
+	if err != nil {
+		return nil, err
+	}
+	// 角色改为强制双因素认证后，未登记的会话不能再续期，须重新登录完成登记
+	enabled, required, err := s.twoFactorState(user, roles)
+	if err != nil {
+		return nil, err
+	}
+	if required && !enabled {
+		s.sessions.End(session.SessionID)
+		return nil, unauthorized("two-factor authentication is required, please log in again")
+	}
+	return issueTokens(user, role_id, roles, session, refreshToken)
+}
+
+// Logout 注销当前会话，其访问令牌与刷新令牌立即失效
+func (s *AuthService) Logout(sessionID string) error {
+	return s.sessions.End(sessionID)
+}
+
+// identity 返回写入令牌的档案 ID（员工/志愿者/捐赠者表的主键）与 RBAC 角色
+func (s *AuthService) identity(user *models.User) (uint, []string, error) {
 	var role_id uint
+	var roles []string
 
 	switch user.UserType {
 	case "employee":
 		employees, err := s.employeeRepo.Search(map[string]interface{}{"user_id": user.ID})
 		if err != nil || len(employees) != 1 {
-			return nil, errors.New("employee profile not found")
+			return 0, nil, errors.New("employee profile not found")
 		} else {
 			role_id = employees[0].ID
 		}
+		userRoles, err := s.rbacRepo.GetUserRoles(user.ID)
+		if err != nil {
+			return 0, nil, errors.New("failed to load user roles")
+		}
+		for _, r := range userRoles {
+			roles = append(roles, r.Name)
+		}
 	case "volunteer":
 		volunteers, err := s.volunteerRepo.Search(map[string]interface{}{"user_id": user.ID})
 		if err != nil || len(volunteers) != 1 {
-			return nil, errors.New("volunteer profile not found")
+			return 0, nil, errors.New("volunteer profile not found")
 		} else {
 			role_id = volunteers[0].ID
 		}
 	case "donor":
 		donors, err := s.donorRepo.Search(map[string]interface{}{"user_id": user.ID})
 		if err != nil || len(donors) != 1 {
-			return nil, errors.New("donor profile not found")
+			return 0, nil, errors.New("donor profile not found")
 		} else {
 			role_id = donors[0].ID
 		}
 	default:
-		return nil, errors.New("invalid user type")
+		return 0, nil, errors.New("invalid user type")
 	}
 
-	// 生成 JWT Token
-	token, err := utils.GenerateToken(user.ID, user.Username, user.UserType, role_id)
+	return role_id, roles, nil
+}
+
+// issueTokens 为会话签发访问令牌并组装认证响应
+func issueTokens(user *models.User, roleID uint, roles []string, session *models.Session, refreshToken string) (*AuthResponse, error) {
+	token, err := utils.GenerateToken(user.ID, user.Username, user.UserType, roleID, roles, session.SessionID)
 	if err != nil {
 		return nil, errors.New("failed to generate token")
 	}
-
-	// update last login time
-	now := time.Now().UTC()
-	user.LastLogin = &now
-	s.userRepo.Update(&user)
-
 	return &AuthResponse{
-		Token:    token,
-		UserType: user.UserType,
-		UserID:   user.ID,
-		RoleID:   role_id,
+		Token:        token,
+		ExpiresIn:    int(utils.AccessTokenTTL.Seconds()),
+		RefreshToken: refreshToken,
+		UserType:     user.UserType,
+		UserID:       user.ID,
+		Status:       user.Status,
+		RoleID:       roleID,
+		Roles:        roles,
 	}, nil
 }
@@ -1,6 +1,7 @@
 package services
 
 import (
+	"sort"
 	"time"
 
 	"erp-backend/internal/repo"
@@ -16,13 +17,102 @@ func NewChartService(r *repo.ChartRepository) *ChartService {
 	return &ChartService{repo: r}
 }
 
-// DonationsByDonor returns aggregated donation points for a donor
-func (s *ChartService) DonationsByDonor(donorID uint, start, end *time.Time) ([]repo.LinePoint, error) {
-	return s.repo.DonationsByDonor(donorID, start, end)
+// Scoped returns a copy whose aggregates only cover the caller's projects
+func (s *ChartService) Scoped(scope *repo.ProjectScope) *ChartService {
+	return &ChartService{repo: s.repo.Scoped(scope)}
 }
 
-func (s *ChartService) DonorDonationsByProject(donorID uint, start, end *time.Time) ([]repo.PiePoint, error) {
-	return s.repo.DonationsByProject(donorID, start, end)
+// 捐赠图表的口径：现金捐赠、实物捐赠（按估值）或两者
+const (
+	DonationKindCash   = "cash"
+	DonationKindInKind = "in_kind"
+	DonationKindAll    = "all"
+)
+
+// ChartSeries 一条命名的折线
+type ChartSeries struct {
+	Name   string
+	Points []repo.LinePoint
+}
+
+func checkDonationKind(kind string) error {
+	switch kind {
+	case DonationKindCash, DonationKindInKind, DonationKindAll:
+		return nil
+	}
+	return invalidInput("kind must be %s, %s or %s", DonationKindCash, DonationKindInKind, DonationKindAll)
+}
+
+// donationSeries 按口径组合现金与实物捐赠折线，all 时各为一条
+func donationSeries(kind string, cash, inKind func() ([]repo.LinePoint, error)) ([]ChartSeries, error) {
+	if err := checkDonationKind(kind); err != nil {
+		return nil, err
+	}
+	var out []ChartSeries
+	if kind != DonationKindInKind {
+		pts, err := cash()
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, ChartSeries{Name: "Cash", Points: pts})
+	}
+	if kind != DonationKindCash {
+		pts, err := inKind()
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, ChartSeries{Name: "In-kind", Points: pts})
+	}
+	return out, nil
+}
+
+// donationPie 按口径合并现金与实物捐赠的项目汇总
+func donationPie(kind string, cash, inKind func() ([]repo.PiePoint, error)) ([]repo.PiePoint, error) {
+	if err := checkDonationKind(kind); err != nil {
+		return nil, err
+	}
+	var out []repo.PiePoint
+	index := map[uint]int{}
+	add := func(load func() ([]repo.PiePoint, error)) error {
+		pts, err := load()
+		if err != nil {
+			return err
+		}
+		for _, pt := range pts {
+			if i, ok := index[pt.ProjectID]; ok {
+				out[i].Value += pt.Value
+				continue
+			}
+			index[pt.ProjectID] = len(out)
+			out = append(out, pt)
+		}
+		return nil
+	}
+	if kind != DonationKindInKind {
+		if err := add(cash); err != nil {
+			return nil, err
+		}
+	}
+	if kind != DonationKindCash {
+		if err := add(inKind); err != nil {
+			return nil, err
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Value > out[j].Value })
+	return out, nil
+}
+
+// DonationsByDonor returns aggregated donation series for a donor
+func (s *ChartService) DonationsByDonor(donorID uint, kind string, start, end *time.Time) ([]ChartSeries, error) {
+	return donationSeries(kind,
+		func() ([]repo.LinePoint, error) { return s.repo.DonationsByDonor(donorID, start, end) },
+		func() ([]repo.LinePoint, error) { return s.repo.InKindByDate(donorID, start, end) })
+}
+
+func (s *ChartService) DonorDonationsByProject(donorID uint, kind string, start, end *time.Time) ([]repo.PiePoint, error) {
+	return donationPie(kind,
+		func() ([]repo.PiePoint, error) { return s.repo.DonationsByProject(donorID, start, end) },
+		func() ([]repo.PiePoint, error) { return s.repo.InKindByProject(donorID, start, end) })
 }
 
 // FundAllocationsByDate returns aggregated fund allocation points for a fund
@@ -44,12 +134,16 @@ func (s *ChartService) ExpensesByProject(start, end *time.Time) ([]repo.PiePoint
 }
 
 // Donations
-func (s *ChartService) DonationsByDate(start, end *time.Time) ([]repo.LinePoint, error) {
-	return s.repo.DonationsByDate(start, end)
+func (s *ChartService) DonationsByDate(kind string, start, end *time.Time) ([]ChartSeries, error) {
+	return donationSeries(kind,
+		func() ([]repo.LinePoint, error) { return s.repo.DonationsByDate(start, end) },
+		func() ([]repo.LinePoint, error) { return s.repo.InKindByDate(0, start, end) })
 }
 
-func (s *ChartService) DonationsByProject(start, end *time.Time) ([]repo.PiePoint, error) {
-	return s.repo.DonationsByProject(0, start, end)
+func (s *ChartService) DonationsByProject(kind string, start, end *time.Time) ([]repo.PiePoint, error) {
+	return donationPie(kind,
+		func() ([]repo.PiePoint, error) { return s.repo.DonationsByProject(0, start, end) },
+		func() ([]repo.PiePoint, error) { return s.repo.InKindByProject(0, start, end) })
 }
 
 // VolunteerHoursByVolunteer returns aggregated volunteer hours points
@@ -0,0 +1,157 @@
+package services
+
+import (
+	"context"
+)
+
+// 以下 WithContext 返回绑定到请求 ctx 的服务副本（用法同 Scoped），
+// 经由副本的写操作在审计日志中记录 ctx 携带的操作人与来源 IP
+
+func (s *UserService) WithContext(ctx context.Context) *UserService {
+	bound := *s
+	bound.repo = s.repo.WithContext(ctx)
+	return &bound
+}
+
+func (s *ProjectService) WithContext(ctx context.Context) *ProjectService {
+	bound := *s
+	bound.repo = s.repo.WithContext(ctx)
+	return &bound
+}
+
+func (s *DonorService) WithContext(ctx context.Context) *DonorService {
+	bound := *s
+	bound.repo = s.repo.WithContext(ctx)
+	return &bound
+}
+
+func (s *DonationService) WithContext(ctx context.Context) *DonationService {
+	bound := *s
+	bound.repo = s.repo.WithContext(ctx)
+	bound.store = s.store.WithContext(ctx)
+	return &bound
+}
+
+func (s *VolunteerService) WithContext(ctx context.Context) *VolunteerService {
+	bound := *s
+	bound.repo = s.repo.WithContext(ctx)
+	return &bound
+}
+
+func (s *EmployeeService) WithContext(ctx context.Context) *EmployeeService {
+	bound := *s
+	bound.repo = s.repo.WithContext(ctx)
+	return &bound
+}
+
+func (s *LocationService) WithContext(ctx context.Context) *LocationService {
+	bound := *s
+	bound.repo = s.repo.WithContext(ctx)
+	return &bound
+}
+
+func (s *FundService) WithContext(ctx context.Context) *FundService {
+	bound := *s
+	bound.repo = s.repo.WithContext(ctx)
+	return &bound
+}
+
+func (s *ExpenseService) WithContext(ctx context.Context) *ExpenseService {
+	bound := *s
+	bound.repo = s.repo.WithContext(ctx)
+	bound.store = s.store.WithContext(ctx)
+	bound.approvalRepo = s.approvalRepo.WithContext(ctx)
+	return &bound
+}
+
+func (s *TransactionService) WithContext(ctx context.Context) *TransactionService {
+	bound := *s
+	bound.repo = s.repo.WithContext(ctx)
+	return &bound
+}
+
+func (s *PurchaseService) WithContext(ctx context.Context) *PurchaseService {
+	bound := *s
+	bound.repo = s.repo.WithContext(ctx)
+	bound.store = s.store.WithContext(ctx)
+	return &bound
+}
+
+func (s *PayrollService) WithContext(ctx context.Context) *PayrollService {
+	bound := *s
+	bound.repo = s.repo.WithContext(ctx)
+	bound.store = s.store.WithContext(ctx)
+	return &bound
+}
+
+func (s *InventoryService) WithContext(ctx context.Context) *InventoryService {
+	bound := *s
+	bound.repo = s.repo.WithContext(ctx)
+	bound.store = s.store.WithContext(ctx)
+	return &bound
+}
+
+func (s *GiftTypeService) WithContext(ctx context.Context) *GiftTypeService {
+	bound := *s
+	bound.repo = s.repo.WithContext(ctx)
+	return &bound
+}
+
+func (s *GiftService) WithContext(ctx context.Context) *GiftService {
+	bound := *s
+	bound.repo = s.repo.WithContext(ctx)
+	return &bound
+}
+
+func (s *InventoryTransactionService) WithContext(ctx context.Context) *InventoryTransactionService {
+	bound := *s
+	bound.repo = s.repo.WithContext(ctx)
+	bound.store = s.store.WithContext(ctx)
+	return &bound
+}
+
+func (s *DeliveryService) WithContext(ctx context.Context) *DeliveryService {
+	bound := *s
+	bound.repo = s.repo.WithContext(ctx)
+	bound.store = s.store.WithContext(ctx)
+	return &bound
+}
+
+func (s *VolunteerProjectService) WithContext(ctx context.Context) *VolunteerProjectService {
+	bound := *s
+	bound.repo = s.repo.WithContext(ctx)
+	return &bound
+}
+
+func (s *EmployeeProjectService) WithContext(ctx context.Context) *EmployeeProjectService {
+	bound := *s
+	bound.repo = s.repo.WithContext(ctx)
+	return &bound
+}
+
+func (s *FundProjectService) WithContext(ctx context.Context) *FundProjectService {
+	bound := *s
+	bound.repo = s.repo.WithContext(ctx)
+	bound.store = s.store.WithContext(ctx)
+	return &bound
+}
+
+func (s *DonationInventoryService) WithContext(ctx context.Context) *DonationInventoryService {
+	bound := *s
+	bound.repo = s.repo.WithContext(ctx)
+	bound.store = s.store.WithContext(ctx)
+	return &bound
+}
+
+func (s *DeliveryInventoryService) WithContext(ctx context.Context) *DeliveryInventoryService {
+	bound := *s
+	bound.repo = s.repo.WithContext(ctx)
+	bound.store = s.store.WithContext(ctx)
+	return &bound
+}
+
+func (s *ScheduleService) WithContext(ctx context.Context) *ScheduleService {
+	bound := *s
+	bound.repo = s.repo.WithContext(ctx)
+	return &bound
+}
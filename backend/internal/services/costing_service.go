@@ -0,0 +1,276 @@
+package services
+
+import (
+	"context"
+	"math"
+	"strings"
+	"time"
+
+	"erp-backend/internal/models"
+	"erp-backend/internal/repo"
+)
+
+// 库存计价：每笔库存变动在过账时确定单位成本。单边入库按采购或实物捐赠估值定价，形成成本层；
+// 出库、调拨与盘亏按出库库存所属类别的计价方法从成本层取得成本：先进先出（fifo）依次消耗最早入库的成本层，
+// 移动加权平均（average）取现有库存的平均成本。调拨入库方沿用出库的成本。
+// 成本过账后不再变动：修改类别的计价方法或补记更早日期的入库只影响之后过账的变动
+
+// costMethods 可用的计价方法
+var costMethods = map[string]bool{
+	models.CostMethodFIFO:    true,
+	models.CostMethodAverage: true,
+}
+
+// CostingService 库存计价方法设置与计价报表
+type CostingService struct {
+	repo *repo.CostingRepository
+}
+
+func NewCostingService(costingRepo *repo.CostingRepository) *CostingService {
+	return &CostingService{repo: costingRepo}
+}
+
+// Scoped 返回按项目统计配送成本时只包含调用者项目的副本
+func (s *CostingService) Scoped(scope *repo.ProjectScope) *CostingService {
+	return &CostingService{repo: s.repo.Scoped(scope)}
+}
+
+func (s *CostingService) WithContext(ctx context.Context) *CostingService {
+	return &CostingService{repo: s.repo.WithContext(ctx)}
+}
+
+// CostMethods 返回各库存类别的计价方法
+func (s *CostingService) CostMethods() ([]repo.CategoryCostMethod, error) {
+	return s.repo.CostMethods()
+}
+
+// SetCostMethod 设置类别的计价方法；只影响之后过账的出库
+func (s *CostingService) SetCostMethod(category, method string) (*models.InventoryCostMethod, error) {
+	category = strings.TrimSpace(category)
+	if category == "" {
+		return nil, invalidInput("category is required")
+	}
+	if !costMethods[method] {
+		return nil, invalidInput("method must be %s or %s", models.CostMethodFIFO, models.CostMethodAverage)
+	}
+	m := &models.InventoryCostMethod{Category: category, Method: method}
+	if err := s.repo.SaveCostMethod(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// ValuationLine 库存估值表中的一项库存
+type ValuationLine struct {
+	repo.ValuationRow
+	Method   string  `json:"method"`
+	UnitCost float64 `json:"unit_cost"` // 平均单位成本：Value / Quantity
+}
+
+// CategoryValuation 一个类别的库存数量与成本合计
+type CategoryValuation struct {
+	Category string  `json:"category"`
+	Method   string  `json:"method"`
+	Quantity int     `json:"quantity"`
+	Value    float64 `json:"value"`
+}
+
+// ValuationReport 截至某日的库存估值表
+type ValuationReport struct {
+	AsOf          *time.Time          `json:"as_of"`
+	Lines         []ValuationLine     `json:"lines"`
+	Categories    []CategoryValuation `json:"categories"`
+	TotalQuantity int                 `json:"total_quantity"`
+	TotalValue    float64             `json:"total_value"`
+}
+
+// Valuation 生成截至 asOf 的库存估值表，数量与成本都为零的库存不列出
+func (s *CostingService) Valuation(asOf *time.Time) (*ValuationReport, error) {
+	rows, err := s.repo.Valuation(asOf)
+	if err != nil {
+		return nil, err
+	}
+	methods, err := s.repo.CostMethods()
+	if err != nil {
+		return nil, err
+	}
+	methodOf := make(map[string]string, len(methods))
+	for _, m := range methods {
+		methodOf[m.Category] = m.Method
+	}
+
+	report := &ValuationReport{AsOf: asOf, Lines: make([]ValuationLine, 0, len(rows)), Categories: []CategoryValuation{}}
+	for _, row := range rows {
+		row.Value = roundCents(row.Value)
+		if row.Quantity == 0 && row.Value == 0 {
+			continue
+		}
+		method := methodOf[row.Category]
+		if method == "" {
+			method = models.DefaultCostMethod
+		}
+		line := ValuationLine{ValuationRow: row, Method: method}
+		if row.Quantity != 0 {
+			line.UnitCost = roundUnitCost(row.Value / float64(row.Quantity))
+		}
+		report.Lines = append(report.Lines, line)
+
+		// 行按类别排列，同一类别的行相邻
+		n := len(report.Categories)
+		if n == 0 || report.Categories[n-1].Category != row.Category {
+			report.Categories = append(report.Categories, CategoryValuation{Category: row.Category, Method: method})
+			n++
+		}
+		report.Categories[n-1].Quantity += row.Quantity
+		report.Categories[n-1].Value = roundCents(report.Categories[n-1].Value + row.Value)
+		report.TotalQuantity += row.Quantity
+		report.TotalValue = roundCents(report.TotalValue + row.Value)
+	}
+	return report, nil
+}
+
+// DistributionCostReport 期间内按项目汇总的配送成本
+type DistributionCostReport struct {
+	Start         *time.Time                 `json:"start"`
+	End           *time.Time                 `json:"end"`
+	Projects      []repo.DistributionCostRow `json:"projects"`
+	TotalQuantity int                        `json:"total_quantity"`
+	TotalCost     float64                    `json:"total_cost"`
+}
+
+// DistributionCost 按项目汇总 [start, end] 期间配送物品的成本
+func (s *CostingService) DistributionCost(start, end *time.Time) (*DistributionCostReport, error) {
+	rows, err := s.repo.DistributionCost(start, end)
+	if err != nil {
+		return nil, err
+	}
+	report := &DistributionCostReport{Start: start, End: end, Projects: rows}
+	for i := range rows {
+		rows[i].Cost = roundCents(rows[i].Cost)
+		report.TotalQuantity += rows[i].Quantity
+		report.TotalCost = roundCents(report.TotalCost + rows[i].Cost)
+	}
+	return report, nil
+}
+
+// ==================== Costing helpers ====================
+
+// moveCost 确定待过账变动的单位成本。有出库一方的变动按出库库存的计价方法计算；
+// 单边入库取 unitCost，未给出时按采购总额中尚未入库的金额计入本次入库，
+// 两者都没有时（如盘盈）取该库存现有的平均成本
+func moveCost(tx *repo.Tx, m stockMove) (float64, error) {
+	if m.from != nil {
+		pool, err := loadCostPool(tx, *m.from, m.replaces)
+		if err != nil {
+			return 0, err
+		}
+		return roundUnitCost(pool.issue(m.quantity) / float64(m.quantity)), nil
+	}
+	if m.purchaseID != nil {
+		purchase, err := tx.Purchases.GetByID(*m.purchaseID)
+		if err != nil {
+			return 0, notFound(err, "purchase")
+		}
+		if m.unitCost > 0 {
+			return roundUnitCost(m.unitCost), nil
+		}
+		received, err := tx.InventoryTransactions.PurchaseReceived(purchase.ID, m.replaces)
+		if err != nil {
+			return 0, err
+		}
+		return roundUnitCost(math.Max(purchase.TotalSpent-received, 0) / float64(m.quantity)), nil
+	}
+	if m.unitCost > 0 {
+		return roundUnitCost(m.unitCost), nil
+	}
+	pool, err := loadCostPool(tx, *m.to, m.replaces)
+	if err != nil {
+		return 0, err
+	}
+	return roundUnitCost(pool.average()), nil
+}
+
+// costLayer 一次入库中尚未发出的数量及其单位成本
+type costLayer struct {
+	quantity int
+	unitCost float64
+}
+
+// costPool 一项库存现有的成本层（按入库日期排列）与计价方法
+type costPool struct {
+	method   string
+	layers   []costLayer
+	quantity int
+	value    float64
+	fallback float64 // 没有成本层可用时的单位成本，取库存上填写的单位成本
+}
+
+// loadCostPool 由尚未冲销的库存变动得出库存现有的成本层：入库形成成本层，已出库的数量从最早的成本层扣除
+func loadCostPool(tx *repo.Tx, inventoryID uint, exclude []uint) (*costPool, error) {
+	inventory, err := tx.Inventories.GetByID(inventoryID)
+	if err != nil {
+		return nil, notFound(err, "inventory")
+	}
+	method, err := tx.Costing.CostMethod(inventory.Category)
+	if err != nil {
+		return nil, err
+	}
+	moves, err := tx.InventoryTransactions.ActiveByInventory(inventoryID, exclude)
+	if err != nil {
+		return nil, err
+	}
+	pool := &costPool{method: method, fallback: inventory.UnitCost}
+	issued := 0
+	for _, mv := range moves {
+		value := float64(mv.QuantityChange) * mv.UnitCost
+		if mv.ToInventoryID != nil && *mv.ToInventoryID == inventoryID {
+			pool.layers = append(pool.layers, costLayer{quantity: mv.QuantityChange, unitCost: mv.UnitCost})
+			pool.quantity += mv.QuantityChange
+			pool.value += value
+		}
+		if mv.FromInventoryID != nil && *mv.FromInventoryID == inventoryID {
+			issued += mv.QuantityChange
+			pool.quantity -= mv.QuantityChange
+			pool.value -= value
+		}
+	}
+	pool.consume(issued)
+	return pool, nil
+}
+
+// issue 返回发出 quantity 的总成本：先进先出按成本层依次计算，移动加权平均按现有平均成本计算
+func (p *costPool) issue(quantity int) float64 {
+	if p.method != models.CostMethodFIFO {
+		return float64(quantity) * p.average()
+	}
+	return p.consume(quantity)
+}
+
+// consume 从最早的成本层起扣除 quantity 并返回其成本；成本层不足的部分按 fallback 计
+func (p *costPool) consume(quantity int) float64 {
+	cost := 0.0
+	for quantity > 0 && len(p.layers) > 0 {
+		layer := &p.layers[0]
+		n := min(quantity, layer.quantity)
+		cost += float64(n) * layer.unitCost
+		layer.quantity -= n
+		quantity -= n
+		if layer.quantity == 0 {
+			p.layers = p.layers[1:]
+		}
+	}
+	return cost + float64(quantity)*p.fallback
+}
+
+// average 现有库存的平均单位成本；没有库存时取 fallback
+func (p *costPool) average() float64 {
+	if p.quantity <= 0 {
+		return p.fallback
+	}
+	return p.value / float64(p.quantity)
+}
+
+// roundUnitCost 单位成本保留四位小数（与 inventory_transactions.unit_cost 的精度一致）
+func roundUnitCost(v float64) float64 {
+	return math.Round(v*10000) / 10000
+}
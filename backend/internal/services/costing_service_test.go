@@ -0,0 +1,89 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"erp-backend/internal/models"
+	"erp-backend/internal/repo"
+)
+
+func TestFIFOAndAverageCostOnAKnownSequence(t *testing.T) {
+	db := openServiceTestDB(t)
+	store := repo.NewStore(db)
+	costing := NewCostingService(repo.NewCostingRepository(db))
+	if _, err := costing.SetCostMethod("food", models.CostMethodFIFO); err != nil {
+		t.Fatal(err)
+	}
+	// tools 未设置计价方法，使用默认的移动加权平均
+	rice := &models.Inventory{InventoryID: "INV-1", Name: "Rice", Category: "food"}
+	saws := &models.Inventory{InventoryID: "INV-2", Name: "Saw", Category: "tools"}
+	mustCreate(t, db, rice, saws)
+	moves := NewInventoryTransactionService(repo.NewInventoryTransactionRepository(db), store)
+
+	day := func(n int) time.Time { return time.Date(2025, 3, n, 0, 0, 0, 0, time.UTC) }
+	// 同一组变动分别按两种方法计价；want 为出库的单位成本
+	sequence := []struct {
+		date     time.Time
+		receive  bool
+		quantity int
+		unitCost float64
+		fifo     float64
+		average  float64
+	}{
+		{day(1), true, 10, 2, 2, 2},
+		{day(2), true, 10, 4, 4, 4},
+		{day(3), false, 15, 0, 2.6667, 3},  // 先进先出：10×2 + 5×4 = 40；平均：60 / 20 = 3
+		{day(4), true, 5, 6, 6, 6},         // 剩余 5×4，再入库 5×6
+		{day(5), false, 6, 0, 4.3333, 4.5}, // 先进先出：5×4 + 1×6 = 26；平均：(15 + 30) / 10 = 4.5
+	}
+	for _, inv := range []struct {
+		inventory *models.Inventory
+		fifo      bool
+	}{{rice, true}, {saws, false}} {
+		id := inv.inventory.ID
+		for i, step := range sequence {
+			move := &models.InventoryTransaction{TransactionType: models.InventoryMoveReceipt, ToInventoryID: &id,
+				QuantityChange: step.quantity, UnitCost: step.unitCost, TransactionDate: step.date}
+			if !step.receive {
+				move = &models.InventoryTransaction{TransactionType: models.InventoryMoveAdjustment, FromInventoryID: &id,
+					QuantityChange: step.quantity, TransactionDate: step.date}
+			}
+			if err := moves.Create(move); err != nil {
+				t.Fatalf("%s step %d: %v", inv.inventory.Name, i+1, err)
+			}
+			want := step.average
+			if inv.fifo {
+				want = step.fifo
+			}
+			if move.UnitCost != want {
+				t.Errorf("%s step %d unit cost %.4f, want %.4f", inv.inventory.Name, i+1, move.UnitCost, want)
+			}
+		}
+	}
+
+	report, err := costing.Valuation(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]struct {
+		method   string
+		quantity int
+		value    float64
+	}{
+		"INV-1": {models.CostMethodFIFO, 4, 24},    // 剩余 4×6
+		"INV-2": {models.CostMethodAverage, 4, 18}, // 剩余 4×4.5
+	}
+	if len(report.Lines) != len(want) {
+		t.Fatalf("valuation has %d lines, want %d", len(report.Lines), len(want))
+	}
+	for _, line := range report.Lines {
+		w := want[line.InventoryID]
+		if line.Method != w.method || line.Quantity != w.quantity || line.Value != w.value {
+			t.Errorf("%s valued %s %d for %.2f, want %s %d for %.2f", line.InventoryID, line.Method, line.Quantity, line.Value, w.method, w.quantity, w.value)
+		}
+	}
+	if report.TotalQuantity != 8 || report.TotalValue != 42 {
+		t.Errorf("valuation totals %d for %.2f, want 8 for 42", report.TotalQuantity, report.TotalValue)
+	}
+}
@@ -0,0 +1,185 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"erp-backend/internal/models"
+	"erp-backend/internal/repo"
+)
+
+// 配送出库：登记配送明细时按数量从库存出库，库存不足时拒绝；修改明细时重新出库并冲销原出库，
+// 删除明细或整个配送时冲销出库、把物品退回库存。明细的单位成本取出库时按计价方法计算的成本
+
+// Create 登记配送明细并出库
+func (s *DeliveryInventoryService) Create(di *models.DeliveryInventory) error {
+	if err := prepareDeliveryLine(di); err != nil {
+		return err
+	}
+	return s.store.Transaction(func(tx *repo.Tx) error {
+		delivery, err := tx.Deliveries.GetByID(*di.DeliveryID)
+		if err != nil {
+			return notFound(err, "delivery")
+		}
+		if err := tx.DeliveryInventories.Create(di); err != nil {
+			return fmt.Errorf("failed to create delivery line: %w", err)
+		}
+		move, err := postMove(tx, deliveryMove(di, delivery))
+		if err != nil {
+			return err
+		}
+		return setLineCost(tx, di, move)
+	})
+}
+
+// Update 按新内容重新出库并冲销原出库；同一库存只按净增加的出库量校验库存
+func (s *DeliveryInventoryService) Update(di *models.DeliveryInventory) error {
+	if err := prepareDeliveryLine(di); err != nil {
+		return err
+	}
+	return s.store.Transaction(func(tx *repo.Tx) error {
+		old, err := tx.DeliveryInventories.GetByID(di.ID)
+		if err != nil {
+			return notFound(err, "delivery line")
+		}
+		delivery, err := tx.Deliveries.GetByID(*di.DeliveryID)
+		if err != nil {
+			return notFound(err, "delivery")
+		}
+		di.CreatedAt = old.CreatedAt
+		if err := tx.DeliveryInventories.Update(di); err != nil {
+			return fmt.Errorf("failed to update delivery line: %w", err)
+		}
+		move, err := repostMove(tx, deliveryMove(di, delivery))
+		if err != nil {
+			return err
+		}
+		return setLineCost(tx, di, move)
+	})
+}
+
+// Delete 冲销配送明细的出库后删除
+func (s *DeliveryInventoryService) Delete(id uint) error {
+	return s.store.Transaction(func(tx *repo.Tx) error {
+		old, err := tx.DeliveryInventories.GetByID(id)
+		if err != nil {
+			return notFound(err, "delivery line")
+		}
+		return deleteDeliveryLine(tx, old)
+	})
+}
+
+// deleteDeliveryLine 冲销并删除一条配送明细，也用于强制级联删除
+func deleteDeliveryLine(tx *repo.Tx, old *models.DeliveryInventory) error {
+	if err := reverseMoves(tx, models.StockSourceDelivery, old.ID); err != nil {
+		return err
+	}
+	if err := tx.DeliveryInventories.Delete(old.ID); err != nil {
+		return fmt.Errorf("failed to delete delivery line: %w", err)
+	}
+	return nil
+}
+
+// restoreDeliveryLine 恢复配送明细后重新出库；所属配送仍在回收站时恢复失败
+func restoreDeliveryLine(tx *repo.Tx, id uint) error {
+	di, err := tx.DeliveryInventories.GetByID(id)
+	if err != nil {
+		return notFound(err, "delivery line")
+	}
+	delivery, err := tx.Deliveries.GetByID(*di.DeliveryID)
+	if err != nil {
+		return notFound(err, "delivery")
+	}
+	move, err := postMove(tx, deliveryMove(di, delivery))
+	if err != nil {
+		return err
+	}
+	return setLineCost(tx, di, move)
+}
+
+// Delete 冲销配送下各明细的出库后删除配送，明细随配送一起移入回收站
+func (s *DeliveryService) Delete(id uint) error {
+	return s.store.Transaction(func(tx *repo.Tx) error {
+		if _, err := tx.Deliveries.GetByID(id); err != nil {
+			return notFound(err, "delivery")
+		}
+		lines, err := tx.DeliveryInventories.ByDelivery(id)
+		if err != nil {
+			return err
+		}
+		for _, line := range lines {
+			if err := reverseMoves(tx, models.StockSourceDelivery, line.ID); err != nil {
+				return err
+			}
+		}
+		if err := tx.Deliveries.Delete(id); err != nil {
+			return fmt.Errorf("failed to delete delivery: %w", err)
+		}
+		return nil
+	})
+}
+
+// restoreDelivery 恢复配送后为随之恢复的明细重新出库
+func restoreDelivery(tx *repo.Tx, id uint) error {
+	delivery, err := tx.Deliveries.GetByID(id)
+	if err != nil {
+		return notFound(err, "delivery")
+	}
+	lines, err := tx.DeliveryInventories.ByDelivery(id)
+	if err != nil {
+		return err
+	}
+	for i := range lines {
+		move, err := postMove(tx, deliveryMove(&lines[i], delivery))
+		if err != nil {
+			return err
+		}
+		if err := setLineCost(tx, &lines[i], move); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setLineCost 把出库变动的单位成本写入配送明细
+func setLineCost(tx *repo.Tx, di *models.DeliveryInventory, move *models.InventoryTransaction) error {
+	di.UnitCost = roundCents(move.UnitCost)
+	if err := tx.DeliveryInventories.SetUnitCost(di.ID, di.UnitCost); err != nil {
+		return fmt.Errorf("failed to update delivery line cost: %w", err)
+	}
+	return nil
+}
+
+// prepareDeliveryLine 校验配送明细并补齐默认值
+func prepareDeliveryLine(di *models.DeliveryInventory) error {
+	if optionalID(di.DeliveryID) == nil {
+		return invalidInput("delivery_id is required")
+	}
+	if optionalID(di.InventoryID) == nil {
+		return invalidInput("inventory_id is required")
+	}
+	if di.Quantity == 0 {
+		di.Quantity = 1
+	}
+	if di.Quantity < 0 {
+		return invalidInput("quantity must be greater than zero")
+	}
+	return nil
+}
+
+// deliveryMove 配送明细的出库，日期取配送日期，未填写时为登记时间
+func deliveryMove(di *models.DeliveryInventory, delivery *models.Delivery) stockMove {
+	date := time.Now().UTC()
+	if delivery.DeliveryDate != nil {
+		date = *delivery.DeliveryDate
+	}
+	inventoryID := *di.InventoryID
+	return stockMove{
+		moveType: models.InventoryMoveDelivery,
+		from:     &inventoryID,
+		quantity: di.Quantity,
+		date:     date,
+		source:   models.StockSourceDelivery,
+		sourceID: di.ID,
+	}
+}
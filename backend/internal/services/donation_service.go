@@ -0,0 +1,272 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"erp-backend/internal/models"
+	"erp-backend/internal/repo"
+)
+
+// defaultCurrency 捐赠交易记录使用的记账币种
+const defaultCurrency = "USD"
+
+// Create 过账一笔新捐赠：在同一事务内写入交易记录、捐赠、礼品与总账分录，
+// 并累加捐赠者的 TotalDonated 与入账基金的 CurrentBalance。任一步失败则全部回滚。
+func (s *DonationService) Create(donation *models.Donation) error {
+	if err := prepareDonation(donation); err != nil {
+		return err
+	}
+	if err := checkScope(s.scope, "donation", nil, &scopedRecord{projectID: donation.ProjectID}); err != nil {
+		return err
+	}
+	return s.store.Transaction(func(tx *repo.Tx) error {
+		return postDonation(tx, donation)
+	})
+}
+
+// postDonation 在事务内过账一笔已校验的新捐赠，供 Create 与批量导入共用
+func postDonation(tx *repo.Tx, donation *models.Donation) error {
+	gifts := donation.Gifts
+	donor, fund, err := loadDonationParties(tx, donation)
+	if err != nil {
+		return err
+	}
+
+	if donation.TransactionID, err = saveTransaction(tx, nil, donationTransaction(donation, donor, fund)); err != nil {
+		return err
+	}
+
+	if err := tx.Donations.Create(donation); err != nil {
+		return fmt.Errorf("failed to create donation: %w", err)
+	}
+
+	created, err := createDonationGifts(tx, donation.ID, gifts)
+	if err != nil {
+		return err
+	}
+	donation.Gifts = created
+
+	if err := applyDonation(tx, donation, 1); err != nil {
+		return err
+	}
+	_, err = postJournal(tx, donationJournal(donation, fund))
+	return err
+}
+
+// Update 冲销原捐赠对捐赠者与基金的影响，再按新内容重新过账。
+// 请求中带有 gifts 字段时替换原有礼品记录，否则保留。
+func (s *DonationService) Update(donation *models.Donation) error {
+	if err := prepareDonation(donation); err != nil {
+		return err
+	}
+	gifts := donation.Gifts
+
+	return s.store.Transaction(func(tx *repo.Tx) error {
+		old, err := tx.Donations.GetByID(donation.ID)
+		if err != nil {
+			return notFound(err, "donation")
+		}
+		if err := checkScope(s.scope, "donation", &scopedRecord{projectID: old.ProjectID}, &scopedRecord{projectID: donation.ProjectID}); err != nil {
+			return err
+		}
+
+		donor, fund, err := loadDonationParties(tx, donation)
+		if err != nil {
+			return err
+		}
+
+		// 保留创建时生成的字段
+		if donation.DonationID == "" {
+			donation.DonationID = old.DonationID
+		}
+		donation.CreatedAt = old.CreatedAt
+
+		if donation.TransactionID, err = saveTransaction(tx, old.TransactionID, donationTransaction(donation, donor, fund)); err != nil {
+			return err
+		}
+
+		if err := tx.Donations.Update(donation); err != nil {
+			return fmt.Errorf("failed to update donation: %w", err)
+		}
+
+		if gifts != nil {
+			if err := tx.Gifts.PurgeByDonation(donation.ID); err != nil {
+				return fmt.Errorf("failed to replace gifts: %w", err)
+			}
+			if donation.Gifts, err = createDonationGifts(tx, donation.ID, gifts); err != nil {
+				return err
+			}
+		} else {
+			donation.Gifts = old.Gifts
+		}
+
+		// 先入账新金额再冲销原金额，同一基金内只按净减少额校验余额
+		if err := applyDonation(tx, donation, 1); err != nil {
+			return err
+		}
+		if err := applyDonation(tx, old, -1); err != nil {
+			return err
+		}
+		return repostJournal(tx, donationJournal(donation, fund))
+	})
+}
+
+// Delete 冲销捐赠对捐赠者与基金的影响，并删除捐赠、其礼品及关联交易记录
+func (s *DonationService) Delete(id uint) error {
+	return s.store.Transaction(func(tx *repo.Tx) error {
+		old, err := tx.Donations.GetByID(id)
+		if err != nil {
+			return notFound(err, "donation")
+		}
+		if err := checkScope(s.scope, "donation", &scopedRecord{projectID: old.ProjectID}, nil); err != nil {
+			return err
+		}
+		return deleteDonation(tx, old)
+	})
+}
+
+// deleteDonation 冲销并删除一笔捐赠，也用于强制级联删除
+func deleteDonation(tx *repo.Tx, old *models.Donation) error {
+	if err := applyDonation(tx, old, -1); err != nil {
+		return err
+	}
+	if err := reverseJournal(tx, models.SourceDonation, old.ID); err != nil {
+		return err
+	}
+	if err := tx.Gifts.DeleteByDonation(old.ID); err != nil {
+		return fmt.Errorf("failed to delete gifts: %w", err)
+	}
+	if err := tx.Donations.Delete(old.ID); err != nil {
+		return fmt.Errorf("failed to delete donation: %w", err)
+	}
+	return deleteTransaction(tx, old.TransactionID)
+}
+
+// restoreDonation 恢复捐赠后，一并恢复其礼品与交易记录并重新过账
+func restoreDonation(tx *repo.Tx, id uint) error {
+	donation, err := tx.Donations.GetByID(id)
+	if err != nil {
+		return notFound(err, "donation")
+	}
+	if err := tx.Gifts.RestoreByDonation(id); err != nil {
+		return fmt.Errorf("failed to restore gifts: %w", err)
+	}
+	if err := restoreTransaction(tx, donation.TransactionID); err != nil {
+		return err
+	}
+	_, fund, err := loadDonationParties(tx, donation)
+	if err != nil {
+		return err
+	}
+	if err := applyDonation(tx, donation, 1); err != nil {
+		return err
+	}
+	_, err = postJournal(tx, donationJournal(donation, fund))
+	return err
+}
+
+// prepareDonation 校验必填字段并补齐默认值
+func prepareDonation(donation *models.Donation) error {
+	if donation.DonorID == nil {
+		return invalidInput("donor_id is required")
+	}
+	if donation.Amount <= 0 {
+		return invalidInput("amount must be greater than zero")
+	}
+	if donation.DonationID == "" {
+		donation.DonationID = generateID("DON")
+	}
+	if donation.DonationDate.IsZero() {
+		donation.DonationDate = time.Now().UTC()
+	}
+	return nil
+}
+
+// loadDonationParties 锁定并返回捐赠涉及的捐赠者和（可选的）入账基金
+func loadDonationParties(tx *repo.Tx, donation *models.Donation) (*models.Donor, *models.Fund, error) {
+	donor, err := tx.Donors.GetByID(*donation.DonorID)
+	if err != nil {
+		return nil, nil, notFound(err, "donor")
+	}
+	if donation.FundID == nil {
+		return donor, nil, nil
+	}
+	fund, err := tx.Funds.GetByID(*donation.FundID)
+	if err != nil {
+		return nil, nil, notFound(err, "fund")
+	}
+	return donor, fund, nil
+}
+
+// applyDonation 按 sign（+1 过账 / -1 冲销）调整捐赠者累计额和基金余额。
+// 冲销时若该笔捐赠已被基金支用，余额不足以退回则拒绝。
+func applyDonation(tx *repo.Tx, donation *models.Donation, sign float64) error {
+	if donation.DonorID != nil {
+		if err := tx.Donors.AddTotalDonated(*donation.DonorID, sign*donation.Amount); err != nil {
+			return fmt.Errorf("failed to update donor total: %w", err)
+		}
+	}
+	if donation.FundID == nil {
+		return nil
+	}
+	if sign < 0 {
+		return withdrawFund(tx, *donation.FundID, donation.Amount)
+	}
+	return creditFund(tx, donation.FundID, donation.Amount)
+}
+
+// donationTransaction 构造捐赠对应的交易记录
+func donationTransaction(donation *models.Donation, donor *models.Donor, fund *models.Fund) *models.Transaction {
+	to := "General Fund"
+	if fund != nil {
+		to = fmt.Sprintf("%s (%s)", fund.Name, fund.FundID)
+	}
+	date := donation.DonationDate
+	return &models.Transaction{
+		TransactionID:     "TRX-" + donation.DonationID,
+		TransactionRecord: fmt.Sprintf("Donation %s (%s)", donation.DonationID, donation.PaymentMethod),
+		Type:              "donation",
+		Amount:            donation.Amount,
+		FromEntity:        fmt.Sprintf("%s %s (%s)", donor.FirstName, donor.LastName, donor.DonorID),
+		ToEntity:          to,
+		TransactionDate:   &date,
+	}
+}
+
+// donationJournal 借：现金；贷：捐赠收入（入账基金为限定性基金时计入限定性捐赠收入）
+func donationJournal(donation *models.Donation, fund *models.Fund) journal {
+	revenue := models.AccountContributions
+	if fund != nil && strings.EqualFold(fund.FundType, models.FundTypeRestricted) {
+		revenue = models.AccountContributionsRestr
+	}
+	return journal{
+		source:        models.SourceDonation,
+		sourceID:      donation.ID,
+		transactionID: donation.TransactionID,
+		date:          donation.DonationDate,
+		description:   fmt.Sprintf("Donation %s", donation.DonationID),
+		lines: []ledgerLine{
+			{account: models.AccountCash, fundID: donation.FundID, projectID: donation.ProjectID, debit: donation.Amount},
+			{account: revenue, fundID: donation.FundID, projectID: donation.ProjectID, credit: donation.Amount},
+		},
+	}
+}
+
+// createDonationGifts 为捐赠写入礼品记录，礼品编号按顺序生成以免同一秒内重复
+func createDonationGifts(tx *repo.Tx, donationID uint, gifts []models.Gift) ([]models.Gift, error) {
+	base := generateID("GFT")
+	for i := range gifts {
+		gift := &gifts[i]
+		gift.ID = 0
+		gift.DonationID = &donationID
+		if gift.GiftID == "" {
+			gift.GiftID = fmt.Sprintf("%s%02d", base, i+1)
+		}
+		if err := tx.Gifts.Create(gift); err != nil {
+			return nil, fmt.Errorf("failed to create gift: %w", err)
+		}
+	}
+	return gifts, nil
+}
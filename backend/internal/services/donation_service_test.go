@@ -0,0 +1,140 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"erp-backend/internal/models"
+	"erp-backend/internal/repo"
+
+	"gorm.io/gorm"
+)
+
+func newDonationTestService(t *testing.T) (*DonationService, *gorm.DB) {
+	t.Helper()
+	db := openServiceTestDB(t)
+	return NewDonationService(repo.NewDonationRepository(db), repo.NewStore(db)), db
+}
+
+// accountBalance 返回科目在总账中的余额（按正常余额方向）
+func accountBalance(t *testing.T, db *gorm.DB, code string) float64 {
+	t.Helper()
+	report, err := NewLedgerService(repo.NewLedgerRepository(db), repo.NewStore(db)).TrialBalance(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range report.Lines {
+		if line.Code == code {
+			return line.Balance
+		}
+	}
+	return 0
+}
+
+func reload(t *testing.T, db *gorm.DB, value interface{}, id uint) {
+	t.Helper()
+	if err := db.First(value, id).Error; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDonationPostingUpdatesFundDonorAndLedger(t *testing.T) {
+	tests := []struct {
+		name     string
+		fundType string // 空表示不入基金
+		revenue  string
+	}{
+		{"general", "", models.AccountContributions},
+		{"unrestricted fund", models.FundTypeUnrestricted, models.AccountContributions},
+		{"restricted fund", models.FundTypeRestricted, models.AccountContributionsRestr},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, db := newDonationTestService(t)
+			donor := &models.Donor{DonorID: "DNR-1", FirstName: "Dana", LastName: "Lee"}
+			mustCreate(t, db, donor)
+			var fundID *uint
+			if tt.fundType != "" {
+				fund := &models.Fund{FundID: "FND-1", Name: "Water", FundType: tt.fundType, TotalAmount: 1000}
+				mustCreate(t, db, fund)
+				fundID = &fund.ID
+			}
+
+			donation := &models.Donation{DonorID: &donor.ID, FundID: fundID, Amount: 300, DonationType: "one-time", Category: "cash"}
+			if err := svc.Create(donation); err != nil {
+				t.Fatal(err)
+			}
+			if donation.TransactionID == nil {
+				t.Error("donation has no transaction record")
+			}
+			reload(t, db, donor, donor.ID)
+			if donor.TotalDonated != 300 {
+				t.Errorf("donor total_donated = %.2f, want 300", donor.TotalDonated)
+			}
+			if fundID != nil {
+				var fund models.Fund
+				reload(t, db, &fund, *fundID)
+				if fund.CurrentBalance != 300 {
+					t.Errorf("fund balance = %.2f, want 300", fund.CurrentBalance)
+				}
+			}
+			if got := accountBalance(t, db, models.AccountCash); got != 300 {
+				t.Errorf("cash balance = %.2f, want 300", got)
+			}
+			if got := accountBalance(t, db, tt.revenue); got != 300 {
+				t.Errorf("account %s balance = %.2f, want 300", tt.revenue, got)
+			}
+
+			if err := svc.Delete(donation.ID); err != nil {
+				t.Fatal(err)
+			}
+			reload(t, db, donor, donor.ID)
+			if donor.TotalDonated != 0 {
+				t.Errorf("donor total_donated after delete = %.2f, want 0", donor.TotalDonated)
+			}
+			if got := accountBalance(t, db, models.AccountCash); got != 0 {
+				t.Errorf("cash balance after delete = %.2f, want 0", got)
+			}
+		})
+	}
+}
+
+func TestDonationPostingRollsBackOnFailure(t *testing.T) {
+	svc, db := newDonationTestService(t)
+	donor := &models.Donor{DonorID: "DNR-1", FirstName: "Dana", LastName: "Lee"}
+	fund := &models.Fund{FundID: "FND-1", Name: "Water", FundType: models.FundTypeUnrestricted, TotalAmount: 1000}
+	mustCreate(t, db, donor, fund)
+
+	missing := uint(999)
+	err := svc.Create(&models.Donation{DonorID: &donor.ID, FundID: &missing, Amount: 50, DonationType: "one-time", Category: "cash"})
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Create with an unknown fund = %v, want ErrNotFound", err)
+	}
+
+	// 基金余额已被支用时，删除捐赠无法退回款项，整笔冲销回滚
+	donation := &models.Donation{DonorID: &donor.ID, FundID: &fund.ID, Amount: 200, DonationType: "one-time", Category: "cash"}
+	if err := svc.Create(donation); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Model(fund).UpdateColumn("current_balance", 150).Error; err != nil {
+		t.Fatal(err)
+	}
+	var fundErr *FundError
+	if err := svc.Delete(donation.ID); !errors.As(err, &fundErr) || fundErr.Code != FundErrInsufficientBalance {
+		t.Fatalf("Delete of a spent donation = %v, want %s", err, FundErrInsufficientBalance)
+	}
+
+	reload(t, db, donor, donor.ID)
+	if donor.TotalDonated != 200 {
+		t.Errorf("donor total_donated = %.2f, want 200 (only the committed donation)", donor.TotalDonated)
+	}
+	var donations, transactions int64
+	db.Model(&models.Donation{}).Count(&donations)
+	db.Model(&models.Transaction{}).Count(&transactions)
+	if donations != 1 || transactions != 1 {
+		t.Errorf("%d donations and %d transactions remain, want 1 of each", donations, transactions)
+	}
+	if got := accountBalance(t, db, models.AccountCash); got != 200 {
+		t.Errorf("cash balance = %.2f, want 200", got)
+	}
+}
@@ -1,6 +1,8 @@
 package services
 
 import (
+	"strings"
+
 	"erp-backend/internal/models"
 	"erp-backend/internal/repo"
 )
@@ -36,11 +38,13 @@ func NewDonorService(donorRepo *repo.DonorRepository) *DonorService {
 
 // DonationService 捐赠服务
 type DonationService struct {
-	repo *repo.DonationRepository
+	repo  *repo.DonationRepository
+	store *repo.Store
+	scope *repo.ProjectScope
 }
 
-func NewDonationService(donationRepo *repo.DonationRepository) *DonationService {
-	return &DonationService{repo: donationRepo}
+func NewDonationService(donationRepo *repo.DonationRepository, store *repo.Store) *DonationService {
+	return &DonationService{repo: donationRepo, store: store}
 }
 
 // VolunteerService 志愿者服务
@@ -81,11 +85,14 @@ func NewFundService(fundRepo *repo.FundRepository) *FundService {
 
 // ExpenseService 支出服务
 type ExpenseService struct {
-	repo *repo.ExpenseRepository
+	repo         *repo.ExpenseRepository
+	approvalRepo *repo.ApprovalRepository
+	store        *repo.Store
+	scope        *repo.ProjectScope
 }
 
-func NewExpenseService(expenseRepo *repo.ExpenseRepository) *ExpenseService {
-	return &ExpenseService{repo: expenseRepo}
+func NewExpenseService(expenseRepo *repo.ExpenseRepository, approvalRepo *repo.ApprovalRepository, store *repo.Store) *ExpenseService {
+	return &ExpenseService{repo: expenseRepo, approvalRepo: approvalRepo, store: store}
 }
 
 // TransactionService 交易服务
@@ -99,29 +106,32 @@ func NewTransactionService(transactionRepo *repo.TransactionRepository) *Transac
 
 // PurchaseService 采购服务
 type PurchaseService struct {
-	repo *repo.PurchaseRepository
+	repo  *repo.PurchaseRepository
+	store *repo.Store
 }
 
-func NewPurchaseService(purchaseRepo *repo.PurchaseRepository) *PurchaseService {
-	return &PurchaseService{repo: purchaseRepo}
+func NewPurchaseService(purchaseRepo *repo.PurchaseRepository, store *repo.Store) *PurchaseService {
+	return &PurchaseService{repo: purchaseRepo, store: store}
 }
 
 // PayrollService 薪资服务
 type PayrollService struct {
-	repo *repo.PayrollRepository
+	repo  *repo.PayrollRepository
+	store *repo.Store
 }
 
-func NewPayrollService(payrollRepo *repo.PayrollRepository) *PayrollService {
-	return &PayrollService{repo: payrollRepo}
+func NewPayrollService(payrollRepo *repo.PayrollRepository, store *repo.Store) *PayrollService {
+	return &PayrollService{repo: payrollRepo, store: store}
 }
 
 // InventoryService 库存服务
 type InventoryService struct {
-	repo *repo.InventoryRepository
+	repo  *repo.InventoryRepository
+	store *repo.Store
 }
 
-func NewInventoryService(inventoryRepo *repo.InventoryRepository) *InventoryService {
-	return &InventoryService{repo: inventoryRepo}
+func NewInventoryService(inventoryRepo *repo.InventoryRepository, store *repo.Store) *InventoryService {
+	return &InventoryService{repo: inventoryRepo, store: store}
 }
 
 // GiftTypeService 礼品类型服务
@@ -144,25 +154,28 @@ func NewGiftService(giftRepo *repo.GiftRepository) *GiftService {
 
 // InventoryTransactionService 库存交易服务
 type InventoryTransactionService struct {
-	repo *repo.InventoryTransactionRepository
+	repo  *repo.InventoryTransactionRepository
+	store *repo.Store
 }
 
-func NewInventoryTransactionService(inventoryTransactionRepo *repo.InventoryTransactionRepository) *InventoryTransactionService {
-	return &InventoryTransactionService{repo: inventoryTransactionRepo}
+func NewInventoryTransactionService(inventoryTransactionRepo *repo.InventoryTransactionRepository, store *repo.Store) *InventoryTransactionService {
+	return &InventoryTransactionService{repo: inventoryTransactionRepo, store: store}
 }
 
 // DeliveryService 配送服务
 type DeliveryService struct {
-	repo *repo.DeliveryRepository
+	repo  *repo.DeliveryRepository
+	store *repo.Store
 }
 
-func NewDeliveryService(deliveryRepo *repo.DeliveryRepository) *DeliveryService {
-	return &DeliveryService{repo: deliveryRepo}
+func NewDeliveryService(deliveryRepo *repo.DeliveryRepository, store *repo.Store) *DeliveryService {
+	return &DeliveryService{repo: deliveryRepo, store: store}
 }
 
 // VolunteerProjectService 志愿者-项目服务
 type VolunteerProjectService struct {
-	repo *repo.VolunteerProjectRepository
+	repo  *repo.VolunteerProjectRepository
+	scope *repo.ProjectScope
 }
 
 func NewVolunteerProjectService(volunteerProjectRepo *repo.VolunteerProjectRepository) *VolunteerProjectService {
@@ -180,34 +193,39 @@ func NewEmployeeProjectService(employeeProjectRepo *repo.EmployeeProjectReposito
 
 // FundProjectService 基金-项目服务
 type FundProjectService struct {
-	repo *repo.FundProjectRepository
+	repo  *repo.FundProjectRepository
+	store *repo.Store
+	scope *repo.ProjectScope
 }
 
-func NewFundProjectService(fundProjectRepo *repo.FundProjectRepository) *FundProjectService {
-	return &FundProjectService{repo: fundProjectRepo}
+func NewFundProjectService(fundProjectRepo *repo.FundProjectRepository, store *repo.Store) *FundProjectService {
+	return &FundProjectService{repo: fundProjectRepo, store: store}
 }
 
 // DonationInventoryService 捐赠-库存服务
 type DonationInventoryService struct {
-	repo *repo.DonationInventoryRepository
+	repo  *repo.DonationInventoryRepository
+	store *repo.Store
 }
 
-func NewDonationInventoryService(donationInventoryRepo *repo.DonationInventoryRepository) *DonationInventoryService {
-	return &DonationInventoryService{repo: donationInventoryRepo}
+func NewDonationInventoryService(donationInventoryRepo *repo.DonationInventoryRepository, store *repo.Store) *DonationInventoryService {
+	return &DonationInventoryService{repo: donationInventoryRepo, store: store}
 }
 
 // DeliveryInventoryService 配送-库存服务
 type DeliveryInventoryService struct {
-	repo *repo.DeliveryInventoryRepository
+	repo  *repo.DeliveryInventoryRepository
+	store *repo.Store
 }
 
-func NewDeliveryInventoryService(deliveryInventoryRepo *repo.DeliveryInventoryRepository) *DeliveryInventoryService {
-	return &DeliveryInventoryService{repo: deliveryInventoryRepo}
+func NewDeliveryInventoryService(deliveryInventoryRepo *repo.DeliveryInventoryRepository, store *repo.Store) *DeliveryInventoryService {
+	return &DeliveryInventoryService{repo: deliveryInventoryRepo, store: store}
 }
 
 // ScheduleService 日程服务
 type ScheduleService struct {
-	repo *repo.ScheduleRepository
+	repo  *repo.ScheduleRepository
+	scope *repo.ProjectScope
 }
 
 func NewScheduleService(scheduleRepo *repo.ScheduleRepository) *ScheduleService {
@@ -219,21 +237,42 @@ func (s *UserService) Create(user *models.User) error {
 	return s.repo.Create(user)
 }
 
-func (s *UserService) GetAll() ([]models.User, error) {
-	return s.repo.GetAll()
+func (s *UserService) List(p repo.ListParams) ([]models.User, *models.Pagination, error) {
+	return s.repo.List(p)
 }
 
 func (s *UserService) Search(query map[string]interface{}) ([]models.User, error) {
 	return s.repo.Search(query)
 }
 
-func (s *UserService) Filter(query map[string]interface{}, numberRange map[string][]interface{}, dateRange map[string][]string) ([]models.User, error) {
-	return s.repo.Filter(query, numberRange, dateRange)
+func (s *UserService) Filter(f repo.Filter, p repo.ListParams) ([]models.User, *models.Pagination, error) {
+	return s.repo.Filter(f, p)
 }
 
+// Update 修改账号资料；账号状态只能通过注册审核接口变更，账号类型创建后不可修改
 func (s *UserService) Update(user *models.User) error {
-	return s.repo.Update(user)
+	old, err := s.repo.GetByID(user.ID)
+	if err != nil {
+		return notFound(err, "user")
+	}
+	if user.Status != "" && user.Status != old.Status {
+		return invalidInput("status can only be changed through the registration approve/reject endpoints")
+	}
+	if user.UserType != "" && user.UserType != old.UserType {
+		return invalidInput("user_type cannot be changed")
+	}
+	if err := s.repo.Update(user); err != nil {
+		return notFound(err, "user")
+	}
+	// 响应返回库中保存的状态、类型与时间字段
+	saved, err := s.repo.GetByID(user.ID)
+	if err != nil {
+		return notFound(err, "user")
+	}
+	*user = *saved
+	return nil
 }
+
 func (s *UserService) Delete(id uint) error {
 	return s.repo.Delete(id)
 }
@@ -244,16 +283,16 @@ func (s *ProjectService) Create(project *models.Project) error {
 	return s.repo.Create(project)
 }
 
-func (s *ProjectService) GetAll() ([]models.Project, error) {
-	return s.repo.GetAll()
+func (s *ProjectService) List(p repo.ListParams) ([]models.Project, *models.Pagination, error) {
+	return s.repo.List(p)
 }
 
 func (s *ProjectService) Search(query map[string]interface{}) ([]models.Project, error) {
 	return s.repo.Search(query)
 }
 
-func (s *ProjectService) Filter(query map[string]interface{}, numberRange map[string][]interface{}, dateRange map[string][]string) ([]models.Project, error) {
-	return s.repo.Filter(query, numberRange, dateRange)
+func (s *ProjectService) Filter(f repo.Filter, p repo.ListParams) ([]models.Project, *models.Pagination, error) {
+	return s.repo.Filter(f, p)
 }
 
 func (s *ProjectService) Update(project *models.Project) error {
@@ -266,24 +305,52 @@ func (s *ProjectService) Delete(id uint) error {
 
 // ==================== Donor Service Methods ====================
 
+// Create 新建捐赠者；累计捐赠额与实物捐赠累计额只随过账累加，从零开始
 func (s *DonorService) Create(donor *models.Donor) error {
+	if err := validateDonor(donor); err != nil {
+		return err
+	}
+	donor.TotalDonated = 0
+	donor.InKindTotal = 0
 	return s.repo.Create(donor)
 }
 
-func (s *DonorService) GetAll() ([]models.Donor, error) {
-	return s.repo.GetAll()
+func (s *DonorService) List(p repo.ListParams) ([]models.Donor, *models.Pagination, error) {
+	return s.repo.List(p)
 }
 
 func (s *DonorService) Search(query map[string]interface{}) ([]models.Donor, error) {
 	return s.repo.Search(query)
 }
 
-func (s *DonorService) Filter(query map[string]interface{}, numberRange map[string][]interface{}, dateRange map[string][]string) ([]models.Donor, error) {
-	return s.repo.Filter(query, numberRange, dateRange)
+func (s *DonorService) Filter(f repo.Filter, p repo.ListParams) ([]models.Donor, *models.Pagination, error) {
+	return s.repo.Filter(f, p)
 }
 
+// Update 更新捐赠者资料；累计捐赠额与实物捐赠累计额由过账维护，不接受直接修改
 func (s *DonorService) Update(donor *models.Donor) error {
-	return s.repo.Update(donor)
+	if err := validateDonor(donor); err != nil {
+		return err
+	}
+	if err := s.repo.Update(donor); err != nil {
+		return notFound(err, "donor")
+	}
+	// 响应返回过账维护的累计额与创建时间
+	saved, err := s.repo.GetByID(donor.ID)
+	if err != nil {
+		return notFound(err, "donor")
+	}
+	*donor = *saved
+	return nil
+}
+
+func validateDonor(donor *models.Donor) error {
+	donor.FirstName = strings.TrimSpace(donor.FirstName)
+	donor.LastName = strings.TrimSpace(donor.LastName)
+	if donor.FirstName == "" || donor.LastName == "" {
+		return invalidInput("first_name and last_name are required")
+	}
+	return nil
 }
 
 func (s *DonorService) Delete(id uint) error {
@@ -291,29 +358,25 @@ func (s *DonorService) Delete(id uint) error {
 }
 
 // ==================== Donation Service Methods ====================
+// Create/Update/Delete 见 donation_service.go（事务过账流程）
 
-func (s *DonationService) Create(donation *models.Donation) error {
-	return s.repo.Create(donation)
+// Scoped 返回限定在调用者数据范围内的服务副本
+func (s *DonationService) Scoped(scope *repo.ProjectScope) *DonationService {
+	scoped := *s
+	scoped.scope = scope
+	return &scoped
 }
 
-func (s *DonationService) GetAll() ([]models.Donation, error) {
-	return s.repo.GetAll()
+func (s *DonationService) List(p repo.ListParams) ([]models.Donation, *models.Pagination, error) {
+	return s.repo.Scoped(s.scope).List(p)
 }
 
 func (s *DonationService) Search(query map[string]interface{}) ([]models.Donation, error) {
-	return s.repo.Search(query)
-}
-
-func (s *DonationService) Filter(query map[string]interface{}, numberRange map[string][]interface{}, dateRange map[string][]string) ([]models.Donation, error) {
-	return s.repo.Filter(query, numberRange, dateRange)
-}
-
-func (s *DonationService) Update(donation *models.Donation) error {
-	return s.repo.Update(donation)
+	return s.repo.Scoped(s.scope).Search(query)
 }
 
-func (s *DonationService) Delete(id uint) error {
-	return s.repo.Delete(id)
+func (s *DonationService) Filter(f repo.Filter, p repo.ListParams) ([]models.Donation, *models.Pagination, error) {
+	return s.repo.Scoped(s.scope).Filter(f, p)
 }
 
 // ==================== Volunteer Service Methods ====================
@@ -322,16 +385,16 @@ func (s *VolunteerService) Create(volunteer *models.Volunteer) error {
 	return s.repo.Create(volunteer)
 }
 
-func (s *VolunteerService) GetAll() ([]models.Volunteer, error) {
-	return s.repo.GetAll()
+func (s *VolunteerService) List(p repo.ListParams) ([]models.Volunteer, *models.Pagination, error) {
+	return s.repo.List(p)
 }
 
 func (s *VolunteerService) Search(query map[string]interface{}) ([]models.Volunteer, error) {
 	return s.repo.Search(query)
 }
 
-func (s *VolunteerService) Filter(query map[string]interface{}, numberRange map[string][]interface{}, dateRange map[string][]string) ([]models.Volunteer, error) {
-	return s.repo.Filter(query, numberRange, dateRange)
+func (s *VolunteerService) Filter(f repo.Filter, p repo.ListParams) ([]models.Volunteer, *models.Pagination, error) {
+	return s.repo.Filter(f, p)
 }
 
 func (s *VolunteerService) Update(volunteer *models.Volunteer) error {
@@ -348,16 +411,16 @@ func (s *EmployeeService) Create(employee *models.Employee) error {
 	return s.repo.Create(employee)
 }
 
-func (s *EmployeeService) GetAll() ([]models.Employee, error) {
-	return s.repo.GetAll()
+func (s *EmployeeService) List(p repo.ListParams) ([]models.Employee, *models.Pagination, error) {
+	return s.repo.List(p)
 }
 
 func (s *EmployeeService) Search(query map[string]interface{}) ([]models.Employee, error) {
 	return s.repo.Search(query)
 }
 
-func (s *EmployeeService) Filter(query map[string]interface{}, numberRange map[string][]interface{}, dateRange map[string][]string) ([]models.Employee, error) {
-	return s.repo.Filter(query, numberRange, dateRange)
+func (s *EmployeeService) Filter(f repo.Filter, p repo.ListParams) ([]models.Employee, *models.Pagination, error) {
+	return s.repo.Filter(f, p)
 }
 
 func (s *EmployeeService) Update(employee *models.Employee) error {
@@ -374,16 +437,16 @@ func (s *LocationService) Create(location *models.Location) error {
 	return s.repo.Create(location)
 }
 
-func (s *LocationService) GetAll() ([]models.Location, error) {
-	return s.repo.GetAll()
+func (s *LocationService) List(p repo.ListParams) ([]models.Location, *models.Pagination, error) {
+	return s.repo.List(p)
 }
 
 func (s *LocationService) Search(query map[string]interface{}) ([]models.Location, error) {
 	return s.repo.Search(query)
 }
 
-func (s *LocationService) Filter(query map[string]interface{}, numberRange map[string][]interface{}, dateRange map[string][]string) ([]models.Location, error) {
-	return s.repo.Filter(query, numberRange, dateRange)
+func (s *LocationService) Filter(f repo.Filter, p repo.ListParams) ([]models.Location, *models.Pagination, error) {
+	return s.repo.Filter(f, p)
 }
 
 func (s *LocationService) Update(location *models.Location) error {
@@ -396,24 +459,44 @@ func (s *LocationService) Delete(id uint) error {
 
 // ==================== Fund Service Methods ====================
 
+// Create 新建基金；余额从零开始，期初余额通过向基金过账捐赠形成
 func (s *FundService) Create(fund *models.Fund) error {
+	if err := validateFund(fund); err != nil {
+		return err
+	}
+	if fund.CurrentBalance != 0 {
+		return invalidInput("current_balance cannot be set directly; post a donation to the fund to record its opening balance")
+	}
 	return s.repo.Create(fund)
 }
 
-func (s *FundService) GetAll() ([]models.Fund, error) {
-	return s.repo.GetAll()
+func (s *FundService) List(p repo.ListParams) ([]models.Fund, *models.Pagination, error) {
+	return s.repo.List(p)
 }
 
 func (s *FundService) Search(query map[string]interface{}) ([]models.Fund, error) {
 	return s.repo.Search(query)
 }
 
-func (s *FundService) Filter(query map[string]interface{}, numberRange map[string][]interface{}, dateRange map[string][]string) ([]models.Fund, error) {
-	return s.repo.Filter(query, numberRange, dateRange)
+func (s *FundService) Filter(f repo.Filter, p repo.ListParams) ([]models.Fund, *models.Pagination, error) {
+	return s.repo.Filter(f, p)
 }
 
+// Update 更新基金资料；当前余额只随捐赠、支出和拨款过账变动，不接受直接修改
 func (s *FundService) Update(fund *models.Fund) error {
-	return s.repo.Update(fund)
+	if err := validateFund(fund); err != nil {
+		return err
+	}
+	if err := s.repo.Update(fund); err != nil {
+		return notFound(err, "fund")
+	}
+	// 响应返回过账维护的当前余额与创建时间
+	saved, err := s.repo.GetByID(fund.ID)
+	if err != nil {
+		return notFound(err, "fund")
+	}
+	*fund = *saved
+	return nil
 }
 
 func (s *FundService) Delete(id uint) error {
@@ -421,29 +504,25 @@ func (s *FundService) Delete(id uint) error {
 }
 
 // ==================== Expense Service Methods ====================
+// Create/Update/Delete 见 finance_service.go（事务过账流程）
 
-func (s *ExpenseService) Create(expense *models.Expense) error {
-	return s.repo.Create(expense)
+// Scoped 返回限定在调用者数据范围内的服务副本
+func (s *ExpenseService) Scoped(scope *repo.ProjectScope) *ExpenseService {
+	scoped := *s
+	scoped.scope = scope
+	return &scoped
 }
 
-func (s *ExpenseService) GetAll() ([]models.Expense, error) {
-	return s.repo.GetAll()
+func (s *ExpenseService) List(p repo.ListParams) ([]models.Expense, *models.Pagination, error) {
+	return s.repo.Scoped(s.scope).List(p)
 }
 
 func (s *ExpenseService) Search(query map[string]interface{}) ([]models.Expense, error) {
-	return s.repo.Search(query)
-}
-
-func (s *ExpenseService) Filter(query map[string]interface{}, numberRange map[string][]interface{}, dateRange map[string][]string) ([]models.Expense, error) {
-	return s.repo.Filter(query, numberRange, dateRange)
-}
-
-func (s *ExpenseService) Update(expense *models.Expense) error {
-	return s.repo.Update(expense)
+	return s.repo.Scoped(s.scope).Search(query)
 }
 
-func (s *ExpenseService) Delete(id uint) error {
-	return s.repo.Delete(id)
+func (s *ExpenseService) Filter(f repo.Filter, p repo.ListParams) ([]models.Expense, *models.Pagination, error) {
+	return s.repo.Scoped(s.scope).Filter(f, p)
 }
 
 // ==================== Transaction Service Methods ====================
@@ -452,16 +531,16 @@ func (s *TransactionService) Create(transaction *models.Transaction) error {
 	return s.repo.Create(transaction)
 }
 
-func (s *TransactionService) GetAll() ([]models.Transaction, error) {
-	return s.repo.GetAll()
+func (s *TransactionService) List(p repo.ListParams) ([]models.Transaction, *models.Pagination, error) {
+	return s.repo.List(p)
 }
 
 func (s *TransactionService) Search(query map[string]interface{}) ([]models.Transaction, error) {
 	return s.repo.Search(query)
 }
 
-func (s *TransactionService) Filter(query map[string]interface{}, numberRange map[string][]interface{}, dateRange map[string][]string) ([]models.Transaction, error) {
-	return s.repo.Filter(query, numberRange, dateRange)
+func (s *TransactionService) Filter(f repo.Filter, p repo.ListParams) ([]models.Transaction, *models.Pagination, error) {
+	return s.repo.Filter(f, p)
 }
 
 func (s *TransactionService) Update(transaction *models.Transaction) error {
@@ -473,79 +552,51 @@ func (s *TransactionService) Delete(id uint) error {
 }
 
 // ==================== Purchase Service Methods ====================
+// Create/Update/Delete 见 finance_service.go（事务过账流程）
 
-func (s *PurchaseService) Create(purchase *models.Purchase) error {
-	return s.repo.Create(purchase)
-}
-
-func (s *PurchaseService) GetAll() ([]models.Purchase, error) {
-	return s.repo.GetAll()
+func (s *PurchaseService) List(p repo.ListParams) ([]models.Purchase, *models.Pagination, error) {
+	return s.repo.List(p)
 }
 
 func (s *PurchaseService) Search(query map[string]interface{}) ([]models.Purchase, error) {
 	return s.repo.Search(query)
 }
 
-func (s *PurchaseService) Filter(query map[string]interface{}, numberRange map[string][]interface{}, dateRange map[string][]string) ([]models.Purchase, error) {
-	return s.repo.Filter(query, numberRange, dateRange)
-}
-
-func (s *PurchaseService) Update(purchase *models.Purchase) error {
-	return s.repo.Update(purchase)
-}
-
-func (s *PurchaseService) Delete(id uint) error {
-	return s.repo.Delete(id)
+func (s *PurchaseService) Filter(f repo.Filter, p repo.ListParams) ([]models.Purchase, *models.Pagination, error) {
+	return s.repo.Filter(f, p)
 }
 
 // ==================== Payroll Service Methods ====================
+// Create/Update/Delete 见 finance_service.go（事务过账流程）
 
-func (s *PayrollService) Create(payroll *models.Payroll) error {
-	return s.repo.Create(payroll)
-}
-
-func (s *PayrollService) GetAll() ([]models.Payroll, error) {
-	return s.repo.GetAll()
+func (s *PayrollService) List(p repo.ListParams) ([]models.Payroll, *models.Pagination, error) {
+	return s.repo.List(p)
 }
 
-func (s *PayrollService) Filter(query map[string]interface{}, numberRange map[string][]interface{}, dateRange map[string][]string) ([]models.Payroll, error) {
-	return s.repo.Filter(query, numberRange, dateRange)
+func (s *PayrollService) Filter(f repo.Filter, p repo.ListParams) ([]models.Payroll, *models.Pagination, error) {
+	return s.repo.Filter(f, p)
 }
 
 func (s *PayrollService) Search(query map[string]interface{}) ([]models.Payroll, error) {
 	return s.repo.Search(query)
 }
 
-func (s *PayrollService) Update(payroll *models.Payroll) error {
-	return s.repo.Update(payroll)
-}
-
-func (s *PayrollService) Delete(id uint) error {
-	return s.repo.Delete(id)
-}
-
 // ==================== Inventory Service Methods ====================
 
-func (s *InventoryService) Create(inventory *models.Inventory) error {
-	return s.repo.Create(inventory)
-}
+// Create/Update 见 inventory_service.go（库存数量由库存变动维护）
 
-func (s *InventoryService) GetAll() ([]models.Inventory, error) {
-	return s.repo.GetAll()
+func (s *InventoryService) List(p repo.ListParams) ([]models.Inventory, *models.Pagination, error) {
+	return s.repo.List(p)
 }
 
-func (s *InventoryService) Filter(query map[string]interface{}, numberRange map[string][]interface{}, dateRange map[string][]string) ([]models.Inventory, error) {
-	return s.repo.Filter(query, numberRange, dateRange)
+func (s *InventoryService) Filter(f repo.Filter, p repo.ListParams) ([]models.Inventory, *models.Pagination, error) {
+	return s.repo.Filter(f, p)
 }
 
 func (s *InventoryService) Search(query map[string]interface{}) ([]models.Inventory, error) {
 	return s.repo.Search(query)
 }
 
-func (s *InventoryService) Update(inventory *models.Inventory) error {
-	return s.repo.Update(inventory)
-}
-
 func (s *InventoryService) Delete(id uint) error {
 	return s.repo.Delete(id)
 }
@@ -556,12 +607,12 @@ func (s *GiftTypeService) Create(giftType *models.GiftType) error {
 	return s.repo.Create(giftType)
 }
 
-func (s *GiftTypeService) GetAll() ([]models.GiftType, error) {
-	return s.repo.GetAll()
+func (s *GiftTypeService) List(p repo.ListParams) ([]models.GiftType, *models.Pagination, error) {
+	return s.repo.List(p)
 }
 
-func (s *GiftTypeService) Filter(query map[string]interface{}, numberRange map[string][]interface{}, dateRange map[string][]string) ([]models.GiftType, error) {
-	return s.repo.Filter(query, numberRange, dateRange)
+func (s *GiftTypeService) Filter(f repo.Filter, p repo.ListParams) ([]models.GiftType, *models.Pagination, error) {
+	return s.repo.Filter(f, p)
 }
 
 func (s *GiftTypeService) Search(query map[string]interface{}) ([]models.GiftType, error) {
@@ -582,12 +633,12 @@ func (s *GiftService) Create(gift *models.Gift) error {
 	return s.repo.Create(gift)
 }
 
-func (s *GiftService) GetAll() ([]models.Gift, error) {
-	return s.repo.GetAll()
+func (s *GiftService) List(p repo.ListParams) ([]models.Gift, *models.Pagination, error) {
+	return s.repo.List(p)
 }
 
-func (s *GiftService) Filter(query map[string]interface{}, numberRange map[string][]interface{}, dateRange map[string][]string) ([]models.Gift, error) {
-	return s.repo.Filter(query, numberRange, dateRange)
+func (s *GiftService) Filter(f repo.Filter, p repo.ListParams) ([]models.Gift, *models.Pagination, error) {
+	return s.repo.Filter(f, p)
 }
 
 func (s *GiftService) Search(query map[string]interface{}) ([]models.Gift, error) {
@@ -604,42 +655,32 @@ func (s *GiftService) Delete(id uint) error {
 
 // ==================== InventoryTransaction Service Methods ====================
 
-func (s *InventoryTransactionService) Create(transaction *models.InventoryTransaction) error {
-	return s.repo.Create(transaction)
-}
+// Create/Update/Delete 见 inventory_service.go（库存变动过账流程）
 
-func (s *InventoryTransactionService) GetAll() ([]models.InventoryTransaction, error) {
-	return s.repo.GetAll()
+func (s *InventoryTransactionService) List(p repo.ListParams) ([]models.InventoryTransaction, *models.Pagination, error) {
+	return s.repo.List(p)
 }
 
-func (s *InventoryTransactionService) Filter(query map[string]interface{}, numberRange map[string][]interface{}, dateRange map[string][]string) ([]models.InventoryTransaction, error) {
-	return s.repo.Filter(query, numberRange, dateRange)
+func (s *InventoryTransactionService) Filter(f repo.Filter, p repo.ListParams) ([]models.InventoryTransaction, *models.Pagination, error) {
+	return s.repo.Filter(f, p)
 }
 
 func (s *InventoryTransactionService) Search(query map[string]interface{}) ([]models.InventoryTransaction, error) {
 	return s.repo.Search(query)
 }
 
-func (s *InventoryTransactionService) Update(transaction *models.InventoryTransaction) error {
-	return s.repo.Update(transaction)
-}
-
-func (s *InventoryTransactionService) Delete(id uint) error {
-	return s.repo.Delete(id)
-}
-
 // ==================== Delivery Service Methods ====================
 
 func (s *DeliveryService) Create(delivery *models.Delivery) error {
 	return s.repo.Create(delivery)
 }
 
-func (s *DeliveryService) GetAll() ([]models.Delivery, error) {
-	return s.repo.GetAll()
+func (s *DeliveryService) List(p repo.ListParams) ([]models.Delivery, *models.Pagination, error) {
+	return s.repo.List(p)
 }
 
-func (s *DeliveryService) Filter(query map[string]interface{}, numberRange map[string][]interface{}, dateRange map[string][]string) ([]models.Delivery, error) {
-	return s.repo.Filter(query, numberRange, dateRange)
+func (s *DeliveryService) Filter(f repo.Filter, p repo.ListParams) ([]models.Delivery, *models.Pagination, error) {
+	return s.repo.Filter(f, p)
 }
 
 func (s *DeliveryService) Search(query map[string]interface{}) ([]models.Delivery, error) {
@@ -650,33 +691,59 @@ func (s *DeliveryService) Update(delivery *models.Delivery) error {
 	return s.repo.Update(delivery)
 }
 
-func (s *DeliveryService) Delete(id uint) error {
-	return s.repo.Delete(id)
-}
+// Delete 见 delivery_service.go（冲销配送明细的出库）
 
 // ==================== VolunteerProject Service Methods ====================
 
+// Scoped 返回限定在调用者数据范围内的服务副本
+func (s *VolunteerProjectService) Scoped(scope *repo.ProjectScope) *VolunteerProjectService {
+	scoped := *s
+	scoped.scope = scope
+	return &scoped
+}
+
 func (s *VolunteerProjectService) Create(vp *models.VolunteerProject) error {
+	if err := checkScope(s.scope, "volunteer assignment", nil, &scopedRecord{projectID: vp.ProjectID}); err != nil {
+		return err
+	}
 	return s.repo.Create(vp)
 }
 
-func (s *VolunteerProjectService) GetAll() ([]models.VolunteerProject, error) {
-	return s.repo.GetAll()
+func (s *VolunteerProjectService) List(p repo.ListParams) ([]models.VolunteerProject, *models.Pagination, error) {
+	return s.repo.Scoped(s.scope).List(p)
 }
 
-func (s *VolunteerProjectService) Filter(query map[string]interface{}, numberRange map[string][]interface{}, dateRange map[string][]string) ([]models.VolunteerProject, error) {
-	return s.repo.Filter(query, numberRange, dateRange)
+func (s *VolunteerProjectService) Filter(f repo.Filter, p repo.ListParams) ([]models.VolunteerProject, *models.Pagination, error) {
+	return s.repo.Scoped(s.scope).Filter(f, p)
 }
 
 func (s *VolunteerProjectService) Search(query map[string]interface{}) ([]models.VolunteerProject, error) {
-	return s.repo.Search(query)
+	return s.repo.Scoped(s.scope).Search(query)
 }
 
 func (s *VolunteerProjectService) Update(vp *models.VolunteerProject) error {
+	if s.scope != nil {
+		old, err := s.repo.GetByID(vp.ID)
+		if err != nil {
+			return notFound(err, "volunteer assignment")
+		}
+		if err := checkScope(s.scope, "volunteer assignment", &scopedRecord{projectID: old.ProjectID}, &scopedRecord{projectID: vp.ProjectID}); err != nil {
+			return err
+		}
+	}
 	return s.repo.Update(vp)
 }
 
 func (s *VolunteerProjectService) Delete(id uint) error {
+	if s.scope != nil {
+		old, err := s.repo.GetByID(id)
+		if err != nil {
+			return notFound(err, "volunteer assignment")
+		}
+		if err := checkScope(s.scope, "volunteer assignment", &scopedRecord{projectID: old.ProjectID}, nil); err != nil {
+			return err
+		}
+	}
 	return s.repo.Delete(id)
 }
 
@@ -686,12 +753,12 @@ func (s *EmployeeProjectService) Create(ep *models.EmployeeProject) error {
 	return s.repo.Create(ep)
 }
 
-func (s *EmployeeProjectService) GetAll() ([]models.EmployeeProject, error) {
-	return s.repo.GetAll()
+func (s *EmployeeProjectService) List(p repo.ListParams) ([]models.EmployeeProject, *models.Pagination, error) {
+	return s.repo.List(p)
 }
 
-func (s *EmployeeProjectService) Filter(query map[string]interface{}, numberRange map[string][]interface{}, dateRange map[string][]string) ([]models.EmployeeProject, error) {
-	return s.repo.Filter(query, numberRange, dateRange)
+func (s *EmployeeProjectService) Filter(f repo.Filter, p repo.ListParams) ([]models.EmployeeProject, *models.Pagination, error) {
+	return s.repo.Filter(f, p)
 }
 
 func (s *EmployeeProjectService) Search(query map[string]interface{}) ([]models.EmployeeProject, error) {
@@ -707,105 +774,109 @@ func (s *EmployeeProjectService) Delete(id uint) error {
 }
 
 // ==================== FundProject Service Methods ====================
+// Create/Update/Delete 见 finance_service.go（事务过账流程）
 
-func (s *FundProjectService) Create(fp *models.FundProject) error {
-	return s.repo.Create(fp)
+// Scoped 返回限定在调用者数据范围内的服务副本
+func (s *FundProjectService) Scoped(scope *repo.ProjectScope) *FundProjectService {
+	scoped := *s
+	scoped.scope = scope
+	return &scoped
 }
 
-func (s *FundProjectService) GetAll() ([]models.FundProject, error) {
-	return s.repo.GetAll()
+func (s *FundProjectService) List(p repo.ListParams) ([]models.FundProject, *models.Pagination, error) {
+	return s.repo.Scoped(s.scope).List(p)
 }
 
-func (s *FundProjectService) Filter(query map[string]interface{}, numberRange map[string][]interface{}, dateRange map[string][]string) ([]models.FundProject, error) {
-	return s.repo.Filter(query, numberRange, dateRange)
+func (s *FundProjectService) Filter(f repo.Filter, p repo.ListParams) ([]models.FundProject, *models.Pagination, error) {
+	return s.repo.Scoped(s.scope).Filter(f, p)
 }
 
 func (s *FundProjectService) Search(query map[string]interface{}) ([]models.FundProject, error) {
-	return s.repo.Search(query)
-}
-
-func (s *FundProjectService) Update(fp *models.FundProject) error {
-	return s.repo.Update(fp)
-}
-
-func (s *FundProjectService) Delete(id uint) error {
-	return s.repo.Delete(id)
+	return s.repo.Scoped(s.scope).Search(query)
 }
 
 // ==================== DonationInventory Service Methods ====================
 
-func (s *DonationInventoryService) Create(di *models.DonationInventory) error {
-	return s.repo.Create(di)
-}
+// Create/Update/Delete 见 in_kind_service.go（事务过账流程）
 
-func (s *DonationInventoryService) GetAll() ([]models.DonationInventory, error) {
-	return s.repo.GetAll()
+func (s *DonationInventoryService) List(p repo.ListParams) ([]models.DonationInventory, *models.Pagination, error) {
+	return s.repo.List(p)
 }
 
-func (s *DonationInventoryService) Filter(query map[string]interface{}, numberRange map[string][]interface{}, dateRange map[string][]string) ([]models.DonationInventory, error) {
-	return s.repo.Filter(query, numberRange, dateRange)
+func (s *DonationInventoryService) Filter(f repo.Filter, p repo.ListParams) ([]models.DonationInventory, *models.Pagination, error) {
+	return s.repo.Filter(f, p)
 }
 
 func (s *DonationInventoryService) Search(query map[string]interface{}) ([]models.DonationInventory, error) {
 	return s.repo.Search(query)
 }
 
-func (s *DonationInventoryService) Update(di *models.DonationInventory) error {
-	return s.repo.Update(di)
-}
-
-func (s *DonationInventoryService) Delete(id uint) error {
-	return s.repo.Delete(id)
-}
-
 // ==================== DeliveryInventory Service Methods ====================
 
-func (s *DeliveryInventoryService) Create(di *models.DeliveryInventory) error {
-	return s.repo.Create(di)
-}
+// Create/Update/Delete 见 delivery_service.go（配送出库流程）
 
-func (s *DeliveryInventoryService) GetAll() ([]models.DeliveryInventory, error) {
-	return s.repo.GetAll()
+func (s *DeliveryInventoryService) List(p repo.ListParams) ([]models.DeliveryInventory, *models.Pagination, error) {
+	return s.repo.List(p)
 }
 
-func (s *DeliveryInventoryService) Filter(query map[string]interface{}, numberRange map[string][]interface{}, dateRange map[string][]string) ([]models.DeliveryInventory, error) {
-	return s.repo.Filter(query, numberRange, dateRange)
+func (s *DeliveryInventoryService) Filter(f repo.Filter, p repo.ListParams) ([]models.DeliveryInventory, *models.Pagination, error) {
+	return s.repo.Filter(f, p)
 }
 
 func (s *DeliveryInventoryService) Search(query map[string]interface{}) ([]models.DeliveryInventory, error) {
 	return s.repo.Search(query)
 }
 
-func (s *DeliveryInventoryService) Update(di *models.DeliveryInventory) error {
-	return s.repo.Update(di)
-}
+// ==================== Schedule Service Methods ====================
 
-func (s *DeliveryInventoryService) Delete(id uint) error {
-	return s.repo.Delete(id)
+// Scoped 返回限定在调用者数据范围内的服务副本
+func (s *ScheduleService) Scoped(scope *repo.ProjectScope) *ScheduleService {
+	scoped := *s
+	scoped.scope = scope
+	return &scoped
 }
 
-// ==================== Schedule Service Methods ====================
-
 func (s *ScheduleService) Create(schedule *models.Schedule) error {
+	if err := checkScope(s.scope, "schedule", nil, &scopedRecord{projectID: schedule.ProjectID, ownerID: scheduleOwner(schedule)}); err != nil {
+		return err
+	}
 	return s.repo.Create(schedule)
 }
 
-func (s *ScheduleService) GetAll() ([]models.Schedule, error) {
-	return s.repo.GetAll()
+func (s *ScheduleService) List(p repo.ListParams) ([]models.Schedule, *models.Pagination, error) {
+	return s.repo.Scoped(s.scope).List(p)
 }
 
-func (s *ScheduleService) Filter(query map[string]interface{}, numberRange map[string][]interface{}, dateRange map[string][]string) ([]models.Schedule, error) {
-	return s.repo.Filter(query, numberRange, dateRange)
+func (s *ScheduleService) Filter(f repo.Filter, p repo.ListParams) ([]models.Schedule, *models.Pagination, error) {
+	return s.repo.Scoped(s.scope).Filter(f, p)
 }
 
 func (s *ScheduleService) Search(query map[string]interface{}) ([]models.Schedule, error) {
-	return s.repo.Search(query)
+	return s.repo.Scoped(s.scope).Search(query)
 }
 
 func (s *ScheduleService) Update(schedule *models.Schedule) error {
+	if s.scope != nil {
+		old, err := s.repo.GetByID(schedule.ID)
+		if err != nil {
+			return notFound(err, "schedule")
+		}
+		if err := checkScope(s.scope, "schedule", &scopedRecord{projectID: old.ProjectID, ownerID: scheduleOwner(old)}, &scopedRecord{projectID: schedule.ProjectID, ownerID: scheduleOwner(schedule)}); err != nil {
+			return err
+		}
+	}
 	return s.repo.Update(schedule)
 }
 
 func (s *ScheduleService) Delete(id uint) error {
+	if s.scope != nil {
+		old, err := s.repo.GetByID(id)
+		if err != nil {
+			return notFound(err, "schedule")
+		}
+		if err := checkScope(s.scope, "schedule", &scopedRecord{projectID: old.ProjectID, ownerID: scheduleOwner(old)}, nil); err != nil {
+			return err
+		}
+	}
 	return s.repo.Delete(id)
 }
@@ -0,0 +1,46 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// 服务层通用错误，处理器据此映射 HTTP 状态码
+var (
+	ErrNotFound        = errors.New("record not found")
+	ErrInvalidInput    = errors.New("invalid input")
+	ErrConflict        = errors.New("conflict")
+	ErrForbidden       = errors.New("forbidden")
+	ErrUnauthorized    = errors.New("unauthorized")
+	ErrTooManyRequests = errors.New("too many requests")
+)
+
+// invalidInput 包装一条输入校验失败信息
+func invalidInput(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %s", ErrInvalidInput, fmt.Sprintf(format, args...))
+}
+
+// conflict 包装一条与当前记录状态冲突的错误
+func conflict(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %s", ErrConflict, fmt.Sprintf(format, args...))
+}
+
+// forbidden 包装一条无权执行操作的错误
+func forbidden(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %s", ErrForbidden, fmt.Sprintf(format, args...))
+}
+
+// unauthorized 包装一条身份凭据无效（需重新登录）的错误
+func unauthorized(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %s", ErrUnauthorized, fmt.Sprintf(format, args...))
+}
+
+// notFound 将 GORM 的记录不存在错误转换为 ErrNotFound，其余错误原样返回
+func notFound(err error, what string) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("%w: %s", ErrNotFound, what)
+	}
+	return err
+}
@@ -0,0 +1,269 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"erp-backend/internal/models"
+	"erp-backend/internal/repo"
+
+	"gorm.io/gorm"
+)
+
+// 支出审批流程：draft → submitted → approved → paid，submitted/approved 可驳回为 rejected，
+// rejected 修改后退回 draft 或直接重新提交。每次流转都写一条 ExpenseApproval 记录。
+
+// financeDepartment 负责支出付款的部门
+const financeDepartment = "Finance"
+
+// Actor 发起审批操作的当前登录用户
+type Actor struct {
+	UserID     uint
+	EmployeeID uint
+}
+
+// expenseStep 一种审批动作：允许的起始状态、目标状态、权限校验与附带的记账操作
+type expenseStep struct {
+	action    string
+	from      []string
+	to        string
+	authorize func(tx *repo.Tx, expense *models.Expense, employee *models.Employee) error
+	apply     func(tx *repo.Tx, expense *models.Expense) error
+}
+
+// expenseStatus 返回支出的审批状态；旧数据中的 pending 视为已提交
+func expenseStatus(expense *models.Expense) string {
+	switch expense.ApprovalStatus {
+	case "":
+		return models.ExpenseStatusDraft
+	case "pending":
+		return models.ExpenseStatusSubmitted
+	}
+	return expense.ApprovalStatus
+}
+
+// Submit 填报人提交支出待审批
+func (s *ExpenseService) Submit(id uint, actor Actor, comment string) (*models.Expense, error) {
+	return s.transition(id, actor, comment, expenseStep{
+		action:    models.ExpenseActionSubmit,
+		from:      []string{models.ExpenseStatusDraft, models.ExpenseStatusRejected},
+		to:        models.ExpenseStatusSubmitted,
+		authorize: authorizeSubmit,
+	})
+}
+
+// Approve 审批通过；审批人须在额度内，且基金此时须有足够的可用余额
+func (s *ExpenseService) Approve(id uint, actor Actor, comment string) (*models.Expense, error) {
+	return s.transition(id, actor, comment, expenseStep{
+		action:    models.ExpenseActionApprove,
+		from:      []string{models.ExpenseStatusSubmitted},
+		to:        models.ExpenseStatusApproved,
+		authorize: authorizeApproval(true),
+		apply: func(tx *repo.Tx, expense *models.Expense) error {
+			fund, err := tx.Funds.GetByID(*expense.FundID)
+			if err != nil {
+				return notFound(err, "fund")
+			}
+			return checkFundDebit(fund, expenseDebit(expense))
+		},
+	})
+}
+
+// Reject 驳回支出，必须填写原因；对该部门有审批权的员工即可驳回，不受金额上限限制
+func (s *ExpenseService) Reject(id uint, actor Actor, comment string) (*models.Expense, error) {
+	if strings.TrimSpace(comment) == "" {
+		return nil, invalidInput("a comment is required when rejecting an expense")
+	}
+	return s.transition(id, actor, comment, expenseStep{
+		action:    models.ExpenseActionReject,
+		from:      []string{models.ExpenseStatusSubmitted, models.ExpenseStatusApproved},
+		to:        models.ExpenseStatusRejected,
+		authorize: authorizeApproval(false),
+	})
+}
+
+// Pay 财务部付款：从基金列支、写入交易记录并过账
+func (s *ExpenseService) Pay(id uint, actor Actor, comment string) (*models.Expense, error) {
+	return s.transition(id, actor, comment, expenseStep{
+		action:    models.ExpenseActionPay,
+		from:      []string{models.ExpenseStatusApproved},
+		to:        models.ExpenseStatusPaid,
+		authorize: authorizePayment,
+		apply:     postExpensePayment,
+	})
+}
+
+// GetApprovals 返回支出的审批记录
+func (s *ExpenseService) GetApprovals(id uint) ([]models.ExpenseApproval, error) {
+	if _, err := s.repo.Scoped(s.scope).GetByID(id); err != nil {
+		return nil, notFound(err, "expense")
+	}
+	return s.approvalRepo.GetApprovals(id)
+}
+
+func (s *ExpenseService) GetApprovalThresholds() ([]models.ApprovalThreshold, error) {
+	return s.approvalRepo.GetThresholds()
+}
+
+// SaveApprovalThreshold 新建或更新某部门某职位的审批额度
+func (s *ExpenseService) SaveApprovalThreshold(threshold *models.ApprovalThreshold) error {
+	threshold.Department = strings.TrimSpace(threshold.Department)
+	threshold.Position = strings.TrimSpace(threshold.Position)
+	if threshold.Position == "" {
+		return invalidInput("position is required")
+	}
+	if threshold.MaxAmount <= 0 {
+		return invalidInput("max_amount must be greater than zero")
+	}
+	return s.approvalRepo.SaveThreshold(threshold)
+}
+
+func (s *ExpenseService) DeleteApprovalThreshold(id uint) error {
+	return s.approvalRepo.DeleteThreshold(id)
+}
+
+// transition 在一个事务内完成状态校验、权限校验、记账与审批记录
+func (s *ExpenseService) transition(id uint, actor Actor, comment string, step expenseStep) (*models.Expense, error) {
+	var expense *models.Expense
+	err := s.store.Transaction(func(tx *repo.Tx) error {
+		var err error
+		expense, err = tx.Expenses.GetByID(id)
+		if err != nil {
+			return notFound(err, "expense")
+		}
+		if err := checkScope(s.scope, "expense", expenseScope(expense), nil); err != nil {
+			return err
+		}
+		from := expenseStatus(expense)
+		allowed := false
+		for _, st := range step.from {
+			allowed = allowed || st == from
+		}
+		if !allowed {
+			return conflict("cannot %s expense %s while it is %s", step.action, expense.ExpenseID, from)
+		}
+
+		employee, err := tx.Employees.GetByID(actor.EmployeeID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return forbidden("the current user has no employee profile")
+		}
+		if err != nil {
+			return err
+		}
+		if err := step.authorize(tx, expense, employee); err != nil {
+			return err
+		}
+		if step.apply != nil {
+			if err := step.apply(tx, expense); err != nil {
+				return err
+			}
+		}
+
+		expense.ApprovalStatus = step.to
+		if err := tx.Expenses.Update(expense); err != nil {
+			return fmt.Errorf("failed to update expense: %w", err)
+		}
+		return tx.Approvals.CreateApproval(&models.ExpenseApproval{
+			ExpenseID:  expense.ID,
+			Action:     step.action,
+			FromStatus: from,
+			ToStatus:   step.to,
+			UserID:     actor.UserID,
+			EmployeeID: &employee.ID,
+			Amount:     expense.Amount,
+			Comment:    comment,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return expense, nil
+}
+
+// authorizeSubmit 只有填报人可以提交；未指定填报人的支出由提交人认领
+func authorizeSubmit(tx *repo.Tx, expense *models.Expense, employee *models.Employee) error {
+	if expense.EmployeeID == nil {
+		expense.EmployeeID = &employee.ID
+		return nil
+	}
+	if *expense.EmployeeID != employee.ID {
+		return forbidden("only the employee who filed expense %s can submit it", expense.ExpenseID)
+	}
+	return nil
+}
+
+// authorizeApproval 审批人不能是填报人或登记人，且须持有覆盖该部门的审批额度；checkAmount 时额度还须不低于支出金额
+func authorizeApproval(checkAmount bool) func(tx *repo.Tx, expense *models.Expense, approver *models.Employee) error {
+	return func(tx *repo.Tx, expense *models.Expense, approver *models.Employee) error {
+		return checkApprovalAuthority(tx, expense, approver, checkAmount)
+	}
+}
+
+func checkApprovalAuthority(tx *repo.Tx, expense *models.Expense, approver *models.Employee, checkAmount bool) error {
+	if expense.EmployeeID != nil && *expense.EmployeeID == approver.ID || createdExpense(expense, approver) {
+		return forbidden("employees cannot approve or reject their own expenses")
+	}
+	department := ""
+	if expense.EmployeeID != nil {
+		filer, err := tx.Employees.GetByID(*expense.EmployeeID)
+		if err != nil {
+			return notFound(err, "employee")
+		}
+		department = filer.Department
+	}
+
+	thresholds, err := tx.Approvals.ThresholdsForPosition(approver.Position)
+	if err != nil {
+		return err
+	}
+	limit, found := 0.0, false
+	for _, t := range thresholds {
+		// 部门为空的额度适用于所有部门；否则审批人与填报人须同属该部门
+		if t.Department != "" && !(strings.EqualFold(t.Department, department) && strings.EqualFold(approver.Department, department)) {
+			continue
+		}
+		if !found || t.MaxAmount > limit {
+			limit, found = t.MaxAmount, true
+		}
+	}
+	if !found {
+		return forbidden("position %q has no approval authority for department %q", approver.Position, department)
+	}
+	if checkAmount && roundCents(expense.Amount) > roundCents(limit) {
+		return forbidden("amount %.2f exceeds the approval limit of %.2f", expense.Amount, limit)
+	}
+	return nil
+}
+
+// authorizePayment 付款须由财务部员工执行，且不能是填报人或登记人
+func authorizePayment(tx *repo.Tx, expense *models.Expense, employee *models.Employee) error {
+	if !strings.EqualFold(employee.Department, financeDepartment) {
+		return forbidden("only the %s department can pay expenses", financeDepartment)
+	}
+	if expense.EmployeeID != nil && *expense.EmployeeID == employee.ID || createdExpense(expense, employee) {
+		return forbidden("employees cannot pay their own expenses")
+	}
+	return nil
+}
+
+// createdExpense 判断员工是否是登记该支出的用户（代他人填报时登记人与填报人不同）
+func createdExpense(expense *models.Expense, employee *models.Employee) bool {
+	return expense.CreatedBy != nil && employee.UserID != nil && *expense.CreatedBy == *employee.UserID
+}
+
+// postExpensePayment 从基金列支并写入交易记录与总账分录
+func postExpensePayment(tx *repo.Tx, expense *models.Expense) error {
+	fund, err := tx.Funds.GetByID(*expense.FundID)
+	if err != nil {
+		return notFound(err, "fund")
+	}
+	if err := debitFund(tx, fund, expenseDebit(expense)); err != nil {
+		return err
+	}
+	if expense.TransactionID, err = saveTransaction(tx, expense.TransactionID, expenseTransaction(expense, fund)); err != nil {
+		return err
+	}
+	_, err = postJournal(tx, expenseJournal(expense))
+	return err
+}
@@ -0,0 +1,78 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"erp-backend/internal/models"
+	"erp-backend/internal/repo"
+)
+
+func TestExpenseCreatorCannotApproveOrPay(t *testing.T) {
+	db := openServiceTestDB(t)
+	staff := map[string]*models.Employee{
+		// 财务部经理代 filer 登记支出：既有审批额度又在财务部，只能因登记人身份被拒绝
+		"clerk":   {EmployeeID: "EMP-1", FirstName: "Casey", LastName: "Clerk", Department: financeDepartment, Position: "Manager"},
+		"filer":   {EmployeeID: "EMP-2", FirstName: "Fran", LastName: "Filer", Department: "Programs", Position: "Officer"},
+		"manager": {EmployeeID: "EMP-3", FirstName: "Morgan", LastName: "Manager", Department: "Programs", Position: "Manager"},
+		"payer":   {EmployeeID: "EMP-4", FirstName: "Pat", LastName: "Payer", Department: financeDepartment, Position: "Accountant"},
+	}
+	actors := map[string]Actor{}
+	for name, employee := range staff {
+		user := &models.User{Username: name, PasswordHash: "x", UserType: "employee", Status: "active"}
+		mustCreate(t, db, user)
+		employee.UserID = &user.ID
+		mustCreate(t, db, employee)
+		actors[name] = Actor{UserID: user.ID, EmployeeID: employee.ID}
+	}
+	fund := &models.Fund{FundID: "FND-1", Name: "General", FundType: models.FundTypeUnrestricted, TotalAmount: 500, CurrentBalance: 500}
+	mustCreate(t, db, fund, &models.ApprovalThreshold{Position: "Manager", MaxAmount: 1000})
+
+	svc := NewExpenseService(repo.NewExpenseRepository(db), repo.NewApprovalRepository(db), repo.NewStore(db))
+	clerkUser := actors["clerk"].UserID
+	filer := staff["filer"].ID
+	expense := &models.Expense{FundID: &fund.ID, EmployeeID: &filer, CreatedBy: &clerkUser, Description: "Field supplies", Amount: 120}
+	if err := svc.Create(expense); err != nil {
+		t.Fatal(err)
+	}
+
+	steps := []struct {
+		name    string
+		run     func(id uint, actor Actor, comment string) (*models.Expense, error)
+		actor   string
+		wantErr error
+	}{
+		{"clerk submits someone else's expense", svc.Submit, "clerk", ErrForbidden},
+		{"filer submits", svc.Submit, "filer", nil},
+		{"filer approves own expense", svc.Approve, "filer", ErrForbidden},
+		{"creator approves", svc.Approve, "clerk", ErrForbidden},
+		{"creator rejects", svc.Reject, "clerk", ErrForbidden},
+		{"payer pays before approval", svc.Pay, "payer", ErrConflict},
+		{"manager approves", svc.Approve, "manager", nil},
+		{"creator pays", svc.Pay, "clerk", ErrForbidden},
+		{"manager outside finance pays", svc.Pay, "manager", ErrForbidden},
+		{"payer pays", svc.Pay, "payer", nil},
+	}
+	for _, step := range steps {
+		_, err := step.run(expense.ID, actors[step.actor], "ok")
+		if step.wantErr == nil && err != nil || step.wantErr != nil && !errors.Is(err, step.wantErr) {
+			t.Fatalf("%s: err = %v, want %v", step.name, err, step.wantErr)
+		}
+	}
+
+	reload(t, db, expense, expense.ID)
+	if expense.ApprovalStatus != models.ExpenseStatusPaid {
+		t.Errorf("approval_status = %s, want %s", expense.ApprovalStatus, models.ExpenseStatusPaid)
+	}
+	reload(t, db, fund, fund.ID)
+	if fund.CurrentBalance != 380 {
+		t.Errorf("fund balance = %.2f, want 380", fund.CurrentBalance)
+	}
+	approvals, err := svc.GetApprovals(expense.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(approvals) != 3 {
+		t.Errorf("%d approval records, want submit, approve and pay only", len(approvals))
+	}
+}
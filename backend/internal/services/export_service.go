@@ -0,0 +1,193 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"erp-backend/internal/repo"
+)
+
+// exportBatchSize 导出时每次从数据库读取的行数
+const exportBatchSize = 500
+
+// piiColumns 各资源中属于个人信息的列，调用者没有 pii:read 时导出为遮盖后的值
+var piiColumns = map[string]map[string]bool{
+	"donors":     {"email": true, "phone": true, "address": true},
+	"volunteers": {"email": true, "phone": true},
+	"employees":  {"email": true, "phone": true, "salary": true},
+	"deliveries": {"recipient_contact": true, "address": true},
+}
+
+// ExportWriter 接收导出的表头与各批数据行，由处理器按文件格式实现
+type ExportWriter interface {
+	Header(columns []string) error
+	Rows(rows [][]interface{}) error
+}
+
+// ExportRequest 一次导出的资源、条件与调用者权限
+type ExportRequest struct {
+	Resource string
+	Filter   repo.Filter
+	Sort     []repo.SortField
+	Fields   []string                   // 只导出这些列（本表列或关联名称列），空表示全部
+	CanRead  func(resource string) bool // 调用者能否读取某资源；不能读取的关联不输出名称列
+	ShowPII  bool                       // 调用者拥有 pii:read
+}
+
+// ExportService 按过滤条件分批导出 dbms 资源
+type ExportService struct {
+	repo  *repo.ExportRepository
+	scope *repo.ProjectScope
+}
+
+func NewExportService(exportRepo *repo.ExportRepository) *ExportService {
+	return &ExportService{repo: exportRepo}
+}
+
+// Scoped 返回限定在调用者数据范围内的服务副本
+func (s *ExportService) Scoped(scope *repo.ProjectScope) *ExportService {
+	scoped := *s
+	scoped.scope = scope
+	return &scoped
+}
+
+func (s *ExportService) WithContext(ctx context.Context) *ExportService {
+	bound := *s
+	bound.repo = s.repo.WithContext(ctx)
+	return &bound
+}
+
+// Export 把符合条件的记录分批写入 w。表头在第一批数据读出后（没有数据时在查询结束后）写入，
+// 因此条件无效等错误在写出任何内容之前返回
+func (s *ExportService) Export(req *ExportRequest, w ExportWriter) error {
+	all, err := s.repo.Columns(req.Resource)
+	if err != nil {
+		return notFound(err, "resource "+req.Resource)
+	}
+	columns, err := exportColumns(all, req)
+	if err != nil {
+		return err
+	}
+	if err := s.checkPIIConditions(req); err != nil {
+		return err
+	}
+	names := make([]string, len(columns))
+	masked := make([]bool, len(columns))
+	for i, col := range columns {
+		names[i] = col.Name
+		masked[i] = !req.ShowPII && piiColumns[req.Resource][col.Name]
+	}
+
+	started := false
+	err = s.repo.Scoped(s.scope).Export(req.Resource, columns, req.Filter, req.Sort, exportBatchSize, func(rows [][]interface{}) error {
+		if !started {
+			if err := w.Header(names); err != nil {
+				return err
+			}
+			started = true
+		}
+		for _, row := range rows {
+			for i := range row {
+				if masked[i] {
+					row[i] = maskValue(row[i])
+				}
+			}
+		}
+		return w.Rows(rows)
+	})
+	if err != nil || started {
+		return err
+	}
+	return w.Header(names)
+}
+
+// checkPIIConditions 没有 pii:read 的调用者不能按个人信息列（含关联记录的列）过滤或排序，
+// 否则可以由返回的行数逐步推出被遮盖的值
+func (s *ExportService) checkPIIConditions(req *ExportRequest) error {
+	if req.ShowPII {
+		return nil
+	}
+	fields := filterFields(req.Filter, nil)
+	for _, sf := range req.Sort {
+		fields = append(fields, sf.Column)
+	}
+	for _, field := range fields {
+		resource, column, err := s.repo.FieldOwner(req.Resource, field)
+		if err != nil {
+			return err
+		}
+		if piiColumns[resource][column] {
+			return forbidden("filtering or sorting on %s requires pii:read", field)
+		}
+	}
+	return nil
+}
+
+// filterFields 收集条件树中引用的全部字段
+func filterFields(f repo.Filter, fields []string) []string {
+	if f.Field != "" {
+		fields = append(fields, f.Field)
+	}
+	for _, sub := range f.And {
+		fields = filterFields(sub, fields)
+	}
+	for _, sub := range f.Or {
+		fields = filterFields(sub, fields)
+	}
+	return fields
+}
+
+// exportColumns 按 fields 选出要导出的列。未指定 fields 时略去调用者不能读取的关联名称列；
+// 明确要求这样的列时拒绝
+func exportColumns(all []repo.ExportColumn, req *ExportRequest) ([]repo.ExportColumn, error) {
+	readable := func(col repo.ExportColumn) bool {
+		return col.Resource == "" || req.CanRead == nil || req.CanRead(col.Resource)
+	}
+	if len(req.Fields) == 0 {
+		var columns []repo.ExportColumn
+		for _, col := range all {
+			if readable(col) {
+				columns = append(columns, col)
+			}
+		}
+		return columns, nil
+	}
+	byName := make(map[string]repo.ExportColumn, len(all))
+	for _, col := range all {
+		byName[col.Name] = col
+	}
+	columns := make([]repo.ExportColumn, 0, len(req.Fields))
+	for _, name := range req.Fields {
+		col, ok := byName[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown field %q", repo.ErrInvalidListParams, name)
+		}
+		if !readable(col) {
+			return nil, forbidden("%s requires %s:read", name, col.Resource)
+		}
+		columns = append(columns, col)
+	}
+	return columns, nil
+}
+
+// maskValue 遮盖个人信息：邮箱保留首字母与域名，其余文本保留末 4 位，数值整体遮盖
+func maskValue(v interface{}) interface{} {
+	if v == nil {
+		return nil
+	}
+	text, ok := v.(string)
+	if !ok {
+		return "***"
+	}
+	if text == "" {
+		return ""
+	}
+	if at := strings.LastIndex(text, "@"); at > 0 {
+		return text[:1] + "***" + text[at:]
+	}
+	if len(text) > 6 {
+		return "***" + text[len(text)-4:]
+	}
+	return "***"
+}
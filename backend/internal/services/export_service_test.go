@@ -0,0 +1,109 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"erp-backend/internal/models"
+	"erp-backend/internal/repo"
+)
+
+// recordingWriter 收集导出的表头与数据行
+type recordingWriter struct {
+	header []string
+	rows   [][]interface{}
+}
+
+func (w *recordingWriter) Header(columns []string) error {
+	w.header = columns
+	return nil
+}
+
+func (w *recordingWriter) Rows(rows [][]interface{}) error {
+	w.rows = append(w.rows, rows...)
+	return nil
+}
+
+// column 返回第 row 行中名为 name 的列的值
+func (w *recordingWriter) column(t *testing.T, row int, name string) interface{} {
+	t.Helper()
+	for i, col := range w.header {
+		if col == name {
+			return w.rows[row][i]
+		}
+	}
+	t.Fatalf("export has no column %s (header %v)", name, w.header)
+	return nil
+}
+
+func TestExportMasksPIIWithoutPermission(t *testing.T) {
+	db := openServiceTestDB(t)
+	donor := &models.Donor{DonorID: "DNR-1", FirstName: "Dana", LastName: "Lee", Email: "dana@example.org", Phone: "+1 555 010 4567"}
+	employee := &models.Employee{EmployeeID: "EMP-1", FirstName: "Eli", LastName: "Park", Email: "eli@example.org", Salary: 4200}
+	mustCreate(t, db, donor, employee)
+	mustCreate(t, db, &models.Donation{DonationID: "DON-1", DonorID: &donor.ID, Amount: 25, DonationType: "one-time", Category: "cash"})
+	svc := NewExportService(repo.NewExportRepository(db))
+
+	tests := []struct {
+		resource string
+		column   string
+		showPII  bool
+		want     interface{}
+	}{
+		{"donors", "email", false, "d***@example.org"},
+		{"donors", "phone", false, "***4567"},
+		{"donors", "first_name", false, "Dana"},
+		{"donors", "email", true, "dana@example.org"},
+		{"donors", "phone", true, "+1 555 010 4567"},
+		{"employees", "salary", false, "***"},
+		{"employees", "salary", true, 4200.0},
+		// 关联名称列不是个人信息
+		{"donations", "donor_name", false, "Dana Lee"},
+	}
+	for _, tt := range tests {
+		w := &recordingWriter{}
+		if err := svc.Export(&ExportRequest{Resource: tt.resource, ShowPII: tt.showPII}, w); err != nil {
+			t.Fatalf("export %s: %v", tt.resource, err)
+		}
+		if len(w.rows) != 1 {
+			t.Fatalf("export %s returned %d rows, want 1", tt.resource, len(w.rows))
+		}
+		if got := w.column(t, 0, tt.column); got != tt.want {
+			t.Errorf("%s.%s with pii:read=%v exported as %v (%T), want %v", tt.resource, tt.column, tt.showPII, got, got, tt.want)
+		}
+	}
+}
+
+func TestExportRejectsPIIConditionsWithoutPermission(t *testing.T) {
+	db := openServiceTestDB(t)
+	svc := NewExportService(repo.NewExportRepository(db))
+
+	// 按个人信息列过滤或排序可以由结果行数推出被遮盖的值
+	tests := []struct {
+		name string
+		req  ExportRequest
+	}{
+		{"filter on email", ExportRequest{Resource: "donors",
+			Filter: repo.Filter{Field: "email", Op: repo.OpLike, Value: "a%"}}},
+		{"filter nested in or", ExportRequest{Resource: "donors", Filter: repo.Filter{Or: []repo.Filter{
+			{Field: "first_name", Op: repo.OpEq, Value: "Dana"},
+			{Field: "phone", Op: repo.OpEq, Value: "555"},
+		}}}},
+		{"sort by salary", ExportRequest{Resource: "employees", Sort: []repo.SortField{{Column: "salary"}}}},
+		{"filter on a related donor's email", ExportRequest{Resource: "donations",
+			Filter: repo.Filter{Field: "donor.email", Op: repo.OpEq, Value: "dana@example.org"}}},
+	}
+	for _, tt := range tests {
+		w := &recordingWriter{}
+		if err := svc.Export(&tt.req, w); !errors.Is(err, ErrForbidden) {
+			t.Errorf("%s: Export = %v, want ErrForbidden", tt.name, err)
+		}
+		if w.header != nil {
+			t.Errorf("%s: header written before the request was rejected", tt.name)
+		}
+		tt.req.ShowPII = true
+		if err := svc.Export(&tt.req, &recordingWriter{}); err != nil {
+			t.Errorf("%s with pii:read: %v", tt.name, err)
+		}
+	}
+}
@@ -0,0 +1,608 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"erp-backend/internal/models"
+	"erp-backend/internal/repo"
+)
+
+// 支出、采购、薪资与基金拨款的过账流程：业务单据、交易记录与总账分录在同一事务内写入
+// （支出在审批通过并付款时过账）
+
+// ==================== Expense ====================
+
+// Create 以草稿状态登记一笔支出。资金在付款时才从基金列支并过账，审批流程见 expense_approval_service.go
+func (s *ExpenseService) Create(expense *models.Expense) error {
+	if err := prepareExpense(expense); err != nil {
+		return err
+	}
+	if expense.ApprovalStatus != "" && expense.ApprovalStatus != models.ExpenseStatusDraft {
+		return invalidInput("new expenses start as %s; use the submit/approve/reject/pay endpoints to change approval_status", models.ExpenseStatusDraft)
+	}
+	expense.ApprovalStatus = models.ExpenseStatusDraft
+	expense.TransactionID = nil
+	if err := checkScope(s.scope, "expense", nil, expenseScope(expense)); err != nil {
+		return err
+	}
+	return s.store.Transaction(func(tx *repo.Tx) error {
+		if _, err := tx.Funds.GetByID(*expense.FundID); err != nil {
+			return notFound(err, "fund")
+		}
+		if err := tx.Expenses.Create(expense); err != nil {
+			return fmt.Errorf("failed to create expense: %w", err)
+		}
+		return nil
+	})
+}
+
+// Update 只能修改草稿或已驳回的支出，且不能借此修改审批状态；被驳回的支出修改后退回草稿
+func (s *ExpenseService) Update(expense *models.Expense) error {
+	if err := prepareExpense(expense); err != nil {
+		return err
+	}
+	return s.store.Transaction(func(tx *repo.Tx) error {
+		old, err := tx.Expenses.GetByID(expense.ID)
+		if err != nil {
+			return notFound(err, "expense")
+		}
+		if err := checkScope(s.scope, "expense", expenseScope(old), &scopedRecord{projectID: expense.ProjectID, ownerID: old.EmployeeID}); err != nil {
+			return err
+		}
+		if expense.ApprovalStatus != "" && expense.ApprovalStatus != old.ApprovalStatus {
+			return invalidInput("approval_status cannot be edited directly; use the submit/approve/reject/pay endpoints")
+		}
+		if status := expenseStatus(old); status != models.ExpenseStatusDraft && status != models.ExpenseStatusRejected {
+			return conflict("expense %s is %s and can no longer be edited", old.ExpenseID, status)
+		}
+		if _, err := tx.Funds.GetByID(*expense.FundID); err != nil {
+			return notFound(err, "fund")
+		}
+		// 编号、填报人、登记人与审批相关字段保持不变
+		expense.ExpenseID = old.ExpenseID
+		expense.EmployeeID = old.EmployeeID
+		expense.CreatedBy = old.CreatedBy
+		expense.ApprovalStatus = models.ExpenseStatusDraft
+		expense.TransactionID = old.TransactionID
+		expense.CreatedAt = old.CreatedAt
+		if err := tx.Expenses.Update(expense); err != nil {
+			return fmt.Errorf("failed to update expense: %w", err)
+		}
+		return nil
+	})
+}
+
+// Delete 删除尚未付款的支出；已付款的支出已经过账，不能删除
+func (s *ExpenseService) Delete(id uint) error {
+	return s.store.Transaction(func(tx *repo.Tx) error {
+		old, err := tx.Expenses.GetByID(id)
+		if err != nil {
+			return notFound(err, "expense")
+		}
+		if err := checkScope(s.scope, "expense", expenseScope(old), nil); err != nil {
+			return err
+		}
+		return deleteExpense(tx, old)
+	})
+}
+
+// deleteExpense 删除尚未付款的支出，也用于强制级联删除
+func deleteExpense(tx *repo.Tx, old *models.Expense) error {
+	if expenseStatus(old) == models.ExpenseStatusPaid {
+		return conflict("expense %s has been paid and cannot be deleted", old.ExpenseID)
+	}
+	if err := tx.Expenses.Delete(old.ID); err != nil {
+		return fmt.Errorf("failed to delete expense: %w", err)
+	}
+	return nil
+}
+
+// expenseScope 支出对所属项目的成员及填报人本人可见
+func expenseScope(expense *models.Expense) *scopedRecord {
+	return &scopedRecord{projectID: expense.ProjectID, ownerID: expense.EmployeeID}
+}
+
+func prepareExpense(expense *models.Expense) error {
+	if expense.FundID == nil {
+		return invalidInput("fund_id is required")
+	}
+	if expense.Amount <= 0 {
+		return invalidInput("amount must be greater than zero")
+	}
+	if expense.ExpenseID == "" {
+		expense.ExpenseID = generateID("EXP")
+	}
+	if expense.ExpenseDate.IsZero() {
+		expense.ExpenseDate = time.Now().UTC()
+	}
+	return nil
+}
+
+func expenseDebit(expense *models.Expense) fundDebit {
+	return fundDebit{amount: expense.Amount, projectID: expense.ProjectID, date: expense.ExpenseDate}
+}
+
+func expenseTransaction(expense *models.Expense, fund *models.Fund) *models.Transaction {
+	date := expense.ExpenseDate
+	return &models.Transaction{
+		TransactionID:     "TRX-" + expense.ExpenseID,
+		TransactionRecord: fmt.Sprintf("Expense %s: %s", expense.ExpenseID, expense.Description),
+		Type:              "expense",
+		Amount:            expense.Amount,
+		FromEntity:        fmt.Sprintf("%s (%s)", fund.Name, fund.FundID),
+		ToEntity:          expense.Description,
+		TransactionDate:   &date,
+	}
+}
+
+// expenseJournal 借：项目支出；贷：现金
+func expenseJournal(expense *models.Expense) journal {
+	return journal{
+		source:        models.SourceExpense,
+		sourceID:      expense.ID,
+		transactionID: expense.TransactionID,
+		date:          expense.ExpenseDate,
+		description:   fmt.Sprintf("Expense %s: %s", expense.ExpenseID, expense.Description),
+		lines: []ledgerLine{
+			{account: models.AccountProgramExpenses, fundID: expense.FundID, projectID: expense.ProjectID, debit: expense.Amount},
+			{account: models.AccountCash, fundID: expense.FundID, projectID: expense.ProjectID, credit: expense.Amount},
+		},
+	}
+}
+
+// ==================== Purchase ====================
+
+func (s *PurchaseService) Create(purchase *models.Purchase) error {
+	if err := preparePurchase(purchase); err != nil {
+		return err
+	}
+	return s.store.Transaction(func(tx *repo.Tx) error {
+		var err error
+		if purchase.TransactionID, err = saveTransaction(tx, nil, purchaseTransaction(purchase)); err != nil {
+			return err
+		}
+		if err := tx.Purchases.Create(purchase); err != nil {
+			return fmt.Errorf("failed to create purchase: %w", err)
+		}
+		_, err = postJournal(tx, purchaseJournal(purchase))
+		return err
+	})
+}
+
+func (s *PurchaseService) Update(purchase *models.Purchase) error {
+	if err := preparePurchase(purchase); err != nil {
+		return err
+	}
+	return s.store.Transaction(func(tx *repo.Tx) error {
+		old, err := tx.Purchases.GetByID(purchase.ID)
+		if err != nil {
+			return notFound(err, "purchase")
+		}
+		purchase.CreatedAt = old.CreatedAt
+		if purchase.TransactionID, err = saveTransaction(tx, old.TransactionID, purchaseTransaction(purchase)); err != nil {
+			return err
+		}
+		if err := tx.Purchases.Update(purchase); err != nil {
+			return fmt.Errorf("failed to update purchase: %w", err)
+		}
+		return repostJournal(tx, purchaseJournal(purchase))
+	})
+}
+
+func (s *PurchaseService) Delete(id uint) error {
+	return s.store.Transaction(func(tx *repo.Tx) error {
+		old, err := tx.Purchases.GetByID(id)
+		if err != nil {
+			return notFound(err, "purchase")
+		}
+		return deletePurchase(tx, old)
+	})
+}
+
+// deletePurchase 冲销并删除一笔采购，也用于强制级联删除
+func deletePurchase(tx *repo.Tx, old *models.Purchase) error {
+	if err := reverseJournal(tx, models.SourcePurchase, old.ID); err != nil {
+		return err
+	}
+	if err := tx.Purchases.Delete(old.ID); err != nil {
+		return fmt.Errorf("failed to delete purchase: %w", err)
+	}
+	return deleteTransaction(tx, old.TransactionID)
+}
+
+// restorePurchase 恢复采购后，一并恢复交易记录并重新过账
+func restorePurchase(tx *repo.Tx, id uint) error {
+	purchase, err := tx.Purchases.GetByID(id)
+	if err != nil {
+		return notFound(err, "purchase")
+	}
+	if err := restoreTransaction(tx, purchase.TransactionID); err != nil {
+		return err
+	}
+	_, err = postJournal(tx, purchaseJournal(purchase))
+	return err
+}
+
+func preparePurchase(purchase *models.Purchase) error {
+	if purchase.TotalSpent <= 0 {
+		return invalidInput("total_spent must be greater than zero")
+	}
+	if purchase.PurchaseID == "" {
+		purchase.PurchaseID = generateID("PUR")
+	}
+	if purchase.PurchaseDate == nil {
+		now := time.Now().UTC()
+		purchase.PurchaseDate = &now
+	}
+	return nil
+}
+
+func purchaseTransaction(purchase *models.Purchase) *models.Transaction {
+	return &models.Transaction{
+		TransactionID:     "TRX-" + purchase.PurchaseID,
+		TransactionRecord: fmt.Sprintf("Purchase %s: %s", purchase.PurchaseID, purchase.Description),
+		Type:              "purchase",
+		Amount:            purchase.TotalSpent,
+		FromEntity:        "Organisation",
+		ToEntity:          purchase.SupplierName,
+		TransactionDate:   purchase.PurchaseDate,
+	}
+}
+
+// purchaseJournal 借：库存；贷：现金
+func purchaseJournal(purchase *models.Purchase) journal {
+	return journal{
+		source:        models.SourcePurchase,
+		sourceID:      purchase.ID,
+		transactionID: purchase.TransactionID,
+		date:          *purchase.PurchaseDate,
+		description:   fmt.Sprintf("Purchase %s from %s", purchase.PurchaseID, purchase.SupplierName),
+		lines: []ledgerLine{
+			{account: models.AccountInventory, debit: purchase.TotalSpent},
+			{account: models.AccountCash, credit: purchase.TotalSpent},
+		},
+	}
+}
+
+// ==================== Payroll ====================
+
+func (s *PayrollService) Create(payroll *models.Payroll) error {
+	if err := preparePayroll(payroll); err != nil {
+		return err
+	}
+	return s.store.Transaction(func(tx *repo.Tx) error {
+		employee, err := tx.Employees.GetByID(payroll.EmployeeID)
+		if err != nil {
+			return notFound(err, "employee")
+		}
+		transactionID, err := saveTransaction(tx, nil, payrollTransaction(payroll, employee))
+		if err != nil {
+			return err
+		}
+		payroll.TransactionID = *transactionID
+		if err := tx.Payrolls.Create(payroll); err != nil {
+			return fmt.Errorf("failed to create payroll: %w", err)
+		}
+		_, err = postJournal(tx, payrollJournal(payroll, employee))
+		return err
+	})
+}
+
+func (s *PayrollService) Update(payroll *models.Payroll) error {
+	if err := preparePayroll(payroll); err != nil {
+		return err
+	}
+	return s.store.Transaction(func(tx *repo.Tx) error {
+		old, err := tx.Payrolls.GetByID(payroll.ID)
+		if err != nil {
+			return notFound(err, "payroll")
+		}
+		employee, err := tx.Employees.GetByID(payroll.EmployeeID)
+		if err != nil {
+			return notFound(err, "employee")
+		}
+		payroll.CreatedAt = old.CreatedAt
+		transactionID, err := saveTransaction(tx, &old.TransactionID, payrollTransaction(payroll, employee))
+		if err != nil {
+			return err
+		}
+		payroll.TransactionID = *transactionID
+		if err := tx.Payrolls.Update(payroll); err != nil {
+			return fmt.Errorf("failed to update payroll: %w", err)
+		}
+		return repostJournal(tx, payrollJournal(payroll, employee))
+	})
+}
+
+func (s *PayrollService) Delete(id uint) error {
+	return s.store.Transaction(func(tx *repo.Tx) error {
+		old, err := tx.Payrolls.GetByID(id)
+		if err != nil {
+			return notFound(err, "payroll")
+		}
+		return deletePayroll(tx, old)
+	})
+}
+
+// deletePayroll 冲销并删除一笔薪资，也用于强制级联删除
+func deletePayroll(tx *repo.Tx, old *models.Payroll) error {
+	if err := reverseJournal(tx, models.SourcePayroll, old.ID); err != nil {
+		return err
+	}
+	if err := tx.Payrolls.Delete(old.ID); err != nil {
+		return fmt.Errorf("failed to delete payroll: %w", err)
+	}
+	return deleteTransaction(tx, &old.TransactionID)
+}
+
+// restorePayroll 恢复薪资后，一并恢复交易记录并重新过账
+func restorePayroll(tx *repo.Tx, id uint) error {
+	payroll, err := tx.Payrolls.GetByID(id)
+	if err != nil {
+		return notFound(err, "payroll")
+	}
+	employee, err := tx.Employees.GetByID(payroll.EmployeeID)
+	if err != nil {
+		return notFound(err, "employee")
+	}
+	if err := restoreTransaction(tx, &payroll.TransactionID); err != nil {
+		return err
+	}
+	_, err = postJournal(tx, payrollJournal(payroll, employee))
+	return err
+}
+
+func preparePayroll(payroll *models.Payroll) error {
+	if payroll.EmployeeID == 0 {
+		return invalidInput("employee_id is required")
+	}
+	if payroll.Amount <= 0 {
+		return invalidInput("amount must be greater than zero")
+	}
+	if payroll.PayDate.IsZero() {
+		payroll.PayDate = time.Now().UTC()
+	}
+	// 关联对象由过账流程维护，避免客户端传入的嵌套对象被级联写入
+	payroll.Transaction = models.Transaction{}
+	payroll.Employee = models.Employee{}
+	return nil
+}
+
+func payrollTransaction(payroll *models.Payroll, employee *models.Employee) *models.Transaction {
+	date := payroll.PayDate
+	return &models.Transaction{
+		TransactionID:     newTransactionNo("PAY"),
+		TransactionRecord: fmt.Sprintf("Payroll for %s %s", employee.FirstName, employee.LastName),
+		Type:              "payroll",
+		Amount:            payroll.Amount,
+		FromEntity:        "Organisation",
+		ToEntity:          fmt.Sprintf("%s %s (%s)", employee.FirstName, employee.LastName, employee.EmployeeID),
+		TransactionDate:   &date,
+	}
+}
+
+// payrollJournal 借：工资薪金；贷：现金
+func payrollJournal(payroll *models.Payroll, employee *models.Employee) journal {
+	transactionID := payroll.TransactionID
+	return journal{
+		source:        models.SourcePayroll,
+		sourceID:      payroll.ID,
+		transactionID: &transactionID,
+		date:          payroll.PayDate,
+		description:   fmt.Sprintf("Payroll for %s %s (%s)", employee.FirstName, employee.LastName, employee.EmployeeID),
+		lines: []ledgerLine{
+			{account: models.AccountSalaries, debit: payroll.Amount},
+			{account: models.AccountCash, credit: payroll.Amount},
+		},
+	}
+}
+
+// ==================== FundProject ====================
+
+func (s *FundProjectService) Create(fp *models.FundProject) error {
+	if err := prepareFundProject(fp); err != nil {
+		return err
+	}
+	if err := checkScope(s.scope, "fund allocation", nil, &scopedRecord{projectID: fp.ProjectID}); err != nil {
+		return err
+	}
+	return s.store.Transaction(func(tx *repo.Tx) error {
+		fund, project, err := loadAllocationParties(tx, fp)
+		if err != nil {
+			return err
+		}
+		if err := debitFund(tx, fund, allocationDebit(fp)); err != nil {
+			return err
+		}
+		if fp.TransactionID, err = saveTransaction(tx, nil, allocationTransaction(fp, fund, project)); err != nil {
+			return err
+		}
+		if err := tx.FundProjects.Create(fp); err != nil {
+			return fmt.Errorf("failed to create fund allocation: %w", err)
+		}
+		_, err = postJournal(tx, allocationJournal(fp, fund, project))
+		return err
+	})
+}
+
+func (s *FundProjectService) Update(fp *models.FundProject) error {
+	if err := prepareFundProject(fp); err != nil {
+		return err
+	}
+	return s.store.Transaction(func(tx *repo.Tx) error {
+		old, err := tx.FundProjects.GetByID(fp.ID)
+		if err != nil {
+			return notFound(err, "fund allocation")
+		}
+		if err := checkScope(s.scope, "fund allocation", &scopedRecord{projectID: old.ProjectID}, &scopedRecord{projectID: fp.ProjectID}); err != nil {
+			return err
+		}
+		if err := creditFund(tx, old.FundID, old.AllocatedAmount); err != nil {
+			return err
+		}
+		fund, project, err := loadAllocationParties(tx, fp)
+		if err != nil {
+			return err
+		}
+		if err := debitFund(tx, fund, allocationDebit(fp)); err != nil {
+			return err
+		}
+		fp.CreatedAt = old.CreatedAt
+		if fp.TransactionID, err = saveTransaction(tx, old.TransactionID, allocationTransaction(fp, fund, project)); err != nil {
+			return err
+		}
+		if err := tx.FundProjects.Update(fp); err != nil {
+			return fmt.Errorf("failed to update fund allocation: %w", err)
+		}
+		return repostJournal(tx, allocationJournal(fp, fund, project))
+	})
+}
+
+func (s *FundProjectService) Delete(id uint) error {
+	return s.store.Transaction(func(tx *repo.Tx) error {
+		old, err := tx.FundProjects.GetByID(id)
+		if err != nil {
+			return notFound(err, "fund allocation")
+		}
+		if err := checkScope(s.scope, "fund allocation", &scopedRecord{projectID: old.ProjectID}, nil); err != nil {
+			return err
+		}
+		return deleteFundProject(tx, old)
+	})
+}
+
+// deleteFundProject 把拨款退回基金并删除，也用于强制级联删除
+func deleteFundProject(tx *repo.Tx, old *models.FundProject) error {
+	if err := creditFund(tx, old.FundID, old.AllocatedAmount); err != nil {
+		return err
+	}
+	if err := reverseJournal(tx, models.SourceFundProject, old.ID); err != nil {
+		return err
+	}
+	if err := tx.FundProjects.Delete(old.ID); err != nil {
+		return fmt.Errorf("failed to delete fund allocation: %w", err)
+	}
+	return deleteTransaction(tx, old.TransactionID)
+}
+
+// restoreFundProject 恢复拨款后，重新从基金列支并恢复交易记录、重新过账
+func restoreFundProject(tx *repo.Tx, id uint) error {
+	fp, err := tx.FundProjects.GetByID(id)
+	if err != nil {
+		return notFound(err, "fund allocation")
+	}
+	fund, project, err := loadAllocationParties(tx, fp)
+	if err != nil {
+		return err
+	}
+	if err := debitFund(tx, fund, allocationDebit(fp)); err != nil {
+		return err
+	}
+	if err := restoreTransaction(tx, fp.TransactionID); err != nil {
+		return err
+	}
+	_, err = postJournal(tx, allocationJournal(fp, fund, project))
+	return err
+}
+
+func prepareFundProject(fp *models.FundProject) error {
+	if fp.FundID == nil || fp.ProjectID == nil {
+		return invalidInput("fund_id and project_id are required")
+	}
+	if fp.AllocatedAmount <= 0 {
+		return invalidInput("allocated_amount must be greater than zero")
+	}
+	if fp.AllocationDate.IsZero() {
+		fp.AllocationDate = time.Now().UTC()
+	}
+	return nil
+}
+
+func loadAllocationParties(tx *repo.Tx, fp *models.FundProject) (*models.Fund, *models.Project, error) {
+	fund, err := tx.Funds.GetByID(*fp.FundID)
+	if err != nil {
+		return nil, nil, notFound(err, "fund")
+	}
+	project, err := tx.Projects.GetByID(*fp.ProjectID)
+	if err != nil {
+		return nil, nil, notFound(err, "project")
+	}
+	return fund, project, nil
+}
+
+func allocationDebit(fp *models.FundProject) fundDebit {
+	return fundDebit{amount: fp.AllocatedAmount, projectID: fp.ProjectID, date: fp.AllocationDate}
+}
+
+func allocationTransaction(fp *models.FundProject, fund *models.Fund, project *models.Project) *models.Transaction {
+	date := fp.AllocationDate
+	return &models.Transaction{
+		TransactionID:     newTransactionNo("ALC"),
+		TransactionRecord: fmt.Sprintf("Allocation to %s: %s", project.Name, fp.Purpose),
+		Type:              "allocation",
+		Amount:            fp.AllocatedAmount,
+		FromEntity:        fmt.Sprintf("%s (%s)", fund.Name, fund.FundID),
+		ToEntity:          fmt.Sprintf("%s (%s)", project.Name, project.ProjectID),
+		TransactionDate:   &date,
+	}
+}
+
+// allocationJournal 借：项目专用现金；贷：现金（资产科目间重分类，按基金与项目标记）
+func allocationJournal(fp *models.FundProject, fund *models.Fund, project *models.Project) journal {
+	return journal{
+		source:        models.SourceFundProject,
+		sourceID:      fp.ID,
+		transactionID: fp.TransactionID,
+		date:          fp.AllocationDate,
+		description:   fmt.Sprintf("Allocation from %s to %s", fund.Name, project.Name),
+		lines: []ledgerLine{
+			{account: models.AccountProjectCash, fundID: fp.FundID, projectID: fp.ProjectID, debit: fp.AllocatedAmount},
+			{account: models.AccountCash, fundID: fp.FundID, credit: fp.AllocatedAmount},
+		},
+	}
+}
+
+// ==================== Transaction helpers ====================
+
+// saveTransaction 写入或更新业务单据对应的交易记录，返回其主键。
+// id 指向已有交易时沿用其编号与创建时间，否则新建。
+func saveTransaction(tx *repo.Tx, id *uint, transaction *models.Transaction) (*uint, error) {
+	if transaction.FromCurrency == "" {
+		transaction.FromCurrency = defaultCurrency
+	}
+	if transaction.ToCurrency == "" {
+		transaction.ToCurrency = defaultCurrency
+	}
+	if id != nil && *id != 0 {
+		if existing, err := tx.Transactions.GetByID(*id); err == nil {
+			transaction.ID = existing.ID
+			transaction.TransactionID = existing.TransactionID
+			transaction.CreatedAt = existing.CreatedAt
+			if err := tx.Transactions.Update(transaction); err != nil {
+				return nil, fmt.Errorf("failed to update transaction: %w", err)
+			}
+			return &transaction.ID, nil
+		}
+	}
+	if err := tx.Transactions.Create(transaction); err != nil {
+		return nil, fmt.Errorf("failed to create transaction: %w", err)
+	}
+	return &transaction.ID, nil
+}
+
+// newTransactionNo 为没有业务编号的单据生成交易编号
+func newTransactionNo(prefix string) string {
+	return fmt.Sprintf("TRX-%s-%d", prefix, time.Now().UnixNano())
+}
+
+// deleteTransaction 删除业务单据对应的交易记录
+func deleteTransaction(tx *repo.Tx, id *uint) error {
+	if id == nil || *id == 0 {
+		return nil
+	}
+	if err := tx.Transactions.Delete(*id); err != nil {
+		return fmt.Errorf("failed to delete transaction: %w", err)
+	}
+	return nil
+}
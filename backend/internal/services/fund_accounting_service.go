@@ -0,0 +1,231 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"erp-backend/internal/models"
+	"erp-backend/internal/repo"
+)
+
+// 基金支用被拒绝的原因代码
+const (
+	FundErrInactive            = "fund_inactive"
+	FundErrOutsideWindow       = "fund_outside_window"
+	FundErrProjectRestricted   = "fund_project_restricted"
+	FundErrDonorRestricted     = "fund_donor_restricted"
+	FundErrInsufficientBalance = "fund_insufficient_balance"
+)
+
+// FundError 基金支用违反余额或限定条件，处理器据此返回 409
+type FundError struct {
+	Code      string  `json:"code"`
+	FundID    uint    `json:"fund_id"`
+	Requested float64 `json:"requested,omitempty"`
+	Available float64 `json:"available,omitempty"`
+	Message   string  `json:"message"`
+}
+
+func (e *FundError) Error() string {
+	return fmt.Sprintf("%s: %s", ErrConflict, e.Message)
+}
+
+func (e *FundError) Unwrap() error {
+	return ErrConflict
+}
+
+// FundAccountingService 基金会计：净资产汇总与支用预检
+type FundAccountingService struct {
+	fundRepo    *repo.FundRepository
+	projectRepo *repo.ProjectRepository
+}
+
+func NewFundAccountingService(fundRepo *repo.FundRepository, projectRepo *repo.ProjectRepository) *FundAccountingService {
+	return &FundAccountingService{fundRepo: fundRepo, projectRepo: projectRepo}
+}
+
+// NetAssetsReport 按限定性分类汇总的基金净资产
+type NetAssetsReport struct {
+	Unrestricted float64       `json:"unrestricted"`
+	Restricted   float64       `json:"restricted"`
+	Total        float64       `json:"total"`
+	Funds        []models.Fund `json:"funds"`
+}
+
+// NetAssets 汇总各基金当前余额
+func (s *FundAccountingService) NetAssets() (*NetAssetsReport, error) {
+	funds, err := s.fundRepo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	report := &NetAssetsReport{Funds: funds}
+	for _, f := range funds {
+		if isRestrictedFund(&f) {
+			report.Restricted += f.CurrentBalance
+		} else {
+			report.Unrestricted += f.CurrentBalance
+		}
+	}
+	report.Restricted = roundCents(report.Restricted)
+	report.Unrestricted = roundCents(report.Unrestricted)
+	report.Total = roundCents(report.Restricted + report.Unrestricted)
+	return report, nil
+}
+
+// CheckDebit 预检一笔支出能否从基金列支，不做任何写入；实际过账时会在事务内再次校验
+func (s *FundAccountingService) CheckDebit(fundID uint, amount float64, projectID *uint, date time.Time) (*models.Fund, error) {
+	if amount <= 0 {
+		return nil, invalidInput("amount must be greater than zero")
+	}
+	fund, err := s.fundRepo.GetByID(fundID)
+	if err != nil {
+		return nil, notFound(err, "fund")
+	}
+	d := fundDebit{amount: amount, projectID: projectID, date: date}
+	if err := resolveDebitProject(s.projectRepo, fund, &d); err != nil {
+		return nil, err
+	}
+	return fund, checkFundDebit(fund, d)
+}
+
+// ==================== Fund posting helpers ====================
+
+// fundDebit 一笔从基金列支的款项
+type fundDebit struct {
+	amount      float64
+	projectID   *uint
+	projectType string // 支出项目的类型，基金有捐赠者限定用途时由 resolveDebitProject 填入
+	date        time.Time
+}
+
+// resolveDebitProject 基金有捐赠者限定用途时读取支出项目的类型，供 checkFundRestrictions 核对
+func resolveDebitProject(projects *repo.ProjectRepository, fund *models.Fund, d *fundDebit) error {
+	if fund.RestrictedPurpose == "" || d.projectID == nil {
+		return nil
+	}
+	project, err := projects.GetByID(*d.projectID)
+	if err != nil {
+		return notFound(err, "project")
+	}
+	d.projectType = project.ProjectType
+	return nil
+}
+
+// validateFund 校验基金类型、可支用时间窗口与捐赠者限定的用途
+func validateFund(fund *models.Fund) error {
+	switch strings.ToLower(fund.FundType) {
+	case models.FundTypeRestricted, models.FundTypeUnrestricted:
+		fund.FundType = strings.ToLower(fund.FundType)
+	default:
+		return invalidInput("fund_type must be %q or %q", models.FundTypeUnrestricted, models.FundTypeRestricted)
+	}
+	if fund.AvailableFrom != nil && fund.AvailableUntil != nil && fund.AvailableUntil.Before(*fund.AvailableFrom) {
+		return invalidInput("available_until must not be before available_from")
+	}
+	fund.RestrictedPurpose = strings.TrimSpace(fund.RestrictedPurpose)
+	if fund.RestrictedPurpose != "" {
+		if fund.FundType != models.FundTypeRestricted {
+			return invalidInput("restricted_purpose requires fund_type %q", models.FundTypeRestricted)
+		}
+		if fund.DonorID == nil {
+			return invalidInput("restricted_purpose records a donor's designation and requires donor_id")
+		}
+	}
+	return nil
+}
+
+func isRestrictedFund(fund *models.Fund) bool {
+	return strings.EqualFold(fund.FundType, models.FundTypeRestricted)
+}
+
+// checkFundRestrictions 校验基金状态、可支用时间窗口、项目限定及捐赠者限定的用途
+func checkFundRestrictions(fund *models.Fund, d fundDebit) error {
+	if fund.Status != "" && fund.Status != "active" {
+		return &FundError{Code: FundErrInactive, FundID: fund.ID,
+			Message: fmt.Sprintf("fund %s is %s", fund.FundID, fund.Status)}
+	}
+	if fund.AvailableFrom != nil && d.date.Before(*fund.AvailableFrom) {
+		return &FundError{Code: FundErrOutsideWindow, FundID: fund.ID,
+			Message: fmt.Sprintf("fund %s is not available before %s", fund.FundID, fund.AvailableFrom.Format("2006-01-02"))}
+	}
+	if fund.AvailableUntil != nil && d.date.After(*fund.AvailableUntil) {
+		return &FundError{Code: FundErrOutsideWindow, FundID: fund.ID,
+			Message: fmt.Sprintf("fund %s expired on %s", fund.FundID, fund.AvailableUntil.Format("2006-01-02"))}
+	}
+	if fund.ProjectID != nil && (d.projectID == nil || *d.projectID != *fund.ProjectID) {
+		return &FundError{Code: FundErrProjectRestricted, FundID: fund.ID,
+			Message: fmt.Sprintf("fund %s is restricted to project %d", fund.FundID, *fund.ProjectID)}
+	}
+	// 限定性基金只能用于项目支出，不能充作一般开支
+	if isRestrictedFund(fund) && d.projectID == nil {
+		return &FundError{Code: FundErrProjectRestricted, FundID: fund.ID,
+			Message: fmt.Sprintf("restricted fund %s can only be spent on a project", fund.FundID)}
+	}
+	// 捐赠者指定了用途时，项目类型须与之一致
+	if fund.RestrictedPurpose != "" && !strings.EqualFold(d.projectType, fund.RestrictedPurpose) {
+		return &FundError{Code: FundErrDonorRestricted, FundID: fund.ID,
+			Message: fmt.Sprintf("fund %s is restricted by its donor to %s projects", fund.FundID, fund.RestrictedPurpose)}
+	}
+	return nil
+}
+
+// checkFundDebit 按基金当前余额与限定条件校验一笔支出，不做写入
+func checkFundDebit(fund *models.Fund, d fundDebit) error {
+	if err := checkFundRestrictions(fund, d); err != nil {
+		return err
+	}
+	if roundCents(fund.CurrentBalance) < roundCents(d.amount) {
+		return insufficientFunds(fund, d.amount)
+	}
+	return nil
+}
+
+// debitFund 校验限定条件后扣减基金余额；fund 须是本事务内锁定读取的记录
+func debitFund(tx *repo.Tx, fund *models.Fund, d fundDebit) error {
+	if err := resolveDebitProject(tx.Projects, fund, &d); err != nil {
+		return err
+	}
+	if err := checkFundRestrictions(fund, d); err != nil {
+		return err
+	}
+	return withdrawFund(tx, fund.ID, d.amount)
+}
+
+// withdrawFund 仅按余额扣减基金，用于冲销已入账的捐赠
+func withdrawFund(tx *repo.Tx, fundID uint, amount float64) error {
+	ok, err := tx.Funds.Debit(fundID, roundCents(amount))
+	if err != nil {
+		return fmt.Errorf("failed to update fund balance: %w", err)
+	}
+	if ok {
+		return nil
+	}
+	fund, err := tx.Funds.GetByID(fundID)
+	if err != nil {
+		return notFound(err, "fund")
+	}
+	return insufficientFunds(fund, amount)
+}
+
+// creditFund 将款项退回基金（冲销支出或拨款时使用）
+func creditFund(tx *repo.Tx, fundID *uint, amount float64) error {
+	if fundID == nil {
+		return nil
+	}
+	if err := tx.Funds.AdjustBalance(*fundID, roundCents(amount)); err != nil {
+		return fmt.Errorf("failed to update fund balance: %w", err)
+	}
+	return nil
+}
+
+func insufficientFunds(fund *models.Fund, amount float64) *FundError {
+	return &FundError{
+		Code:      FundErrInsufficientBalance,
+		FundID:    fund.ID,
+		Requested: roundCents(amount),
+		Available: roundCents(fund.CurrentBalance),
+		Message: fmt.Sprintf("fund %s has insufficient balance: requested %.2f, available %.2f",
+			fund.FundID, amount, fund.CurrentBalance),
+	}
+}
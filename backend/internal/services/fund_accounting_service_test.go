@@ -0,0 +1,131 @@
+package services
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"erp-backend/internal/models"
+	"erp-backend/internal/repo"
+)
+
+func TestCheckFundRestrictions(t *testing.T) {
+	day := func(d int) *time.Time {
+		v := time.Date(2024, 6, d, 0, 0, 0, 0, time.UTC)
+		return &v
+	}
+	project, other := uint(1), uint(2)
+	tests := []struct {
+		name string
+		fund models.Fund
+		d    fundDebit
+		want string // 空表示允许
+	}{
+		{"unrestricted general spend", models.Fund{FundType: models.FundTypeUnrestricted}, fundDebit{amount: 10}, ""},
+		{"closed fund", models.Fund{FundType: models.FundTypeUnrestricted, Status: "closed"}, fundDebit{amount: 10}, FundErrInactive},
+		{"before window", models.Fund{FundType: models.FundTypeUnrestricted, AvailableFrom: day(10)},
+			fundDebit{amount: 10, date: *day(9)}, FundErrOutsideWindow},
+		{"after window", models.Fund{FundType: models.FundTypeUnrestricted, AvailableUntil: day(10)},
+			fundDebit{amount: 10, date: *day(11)}, FundErrOutsideWindow},
+		{"inside window", models.Fund{FundType: models.FundTypeUnrestricted, AvailableFrom: day(1), AvailableUntil: day(10)},
+			fundDebit{amount: 10, date: *day(10)}, ""},
+		{"other project", models.Fund{FundType: models.FundTypeUnrestricted, ProjectID: &project},
+			fundDebit{amount: 10, projectID: &other}, FundErrProjectRestricted},
+		{"restricted general spend", models.Fund{FundType: models.FundTypeRestricted}, fundDebit{amount: 10}, FundErrProjectRestricted},
+		{"restricted project spend", models.Fund{FundType: models.FundTypeRestricted}, fundDebit{amount: 10, projectID: &other}, ""},
+		{"donor purpose mismatch", models.Fund{FundType: models.FundTypeRestricted, RestrictedPurpose: "education"},
+			fundDebit{amount: 10, projectID: &other, projectType: "healthcare"}, FundErrDonorRestricted},
+		{"donor purpose match", models.Fund{FundType: models.FundTypeRestricted, RestrictedPurpose: "education"},
+			fundDebit{amount: 10, projectID: &other, projectType: "Education"}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.d.date.IsZero() {
+				tt.d.date = *day(5)
+			}
+			err := checkFundRestrictions(&tt.fund, tt.d)
+			if tt.want == "" {
+				if err != nil {
+					t.Fatalf("checkFundRestrictions = %v, want nil", err)
+				}
+				return
+			}
+			var fundErr *FundError
+			if !errors.As(err, &fundErr) || fundErr.Code != tt.want {
+				t.Fatalf("checkFundRestrictions = %v, want %s", err, tt.want)
+			}
+			if !errors.Is(err, ErrConflict) {
+				t.Errorf("FundError does not unwrap to ErrConflict")
+			}
+		})
+	}
+}
+
+func TestCheckDebitReadsDonorPurposeFromProject(t *testing.T) {
+	db := openServiceTestDB(t)
+	donor := &models.Donor{DonorID: "DNR-1", FirstName: "Dana", LastName: "Lee"}
+	mustCreate(t, db, donor)
+	school := &models.Project{ProjectID: "PRJ-1", Name: "School", ProjectType: "education"}
+	clinic := &models.Project{ProjectID: "PRJ-2", Name: "Clinic", ProjectType: "healthcare"}
+	fund := &models.Fund{FundID: "FND-1", Name: "Scholarships", FundType: models.FundTypeRestricted, TotalAmount: 500,
+		CurrentBalance: 500, DonorID: &donor.ID, RestrictedPurpose: "education"}
+	mustCreate(t, db, school, clinic, fund)
+	svc := NewFundAccountingService(repo.NewFundRepository(db), repo.NewProjectRepository(db))
+
+	if _, err := svc.CheckDebit(fund.ID, 100, &school.ID, time.Now().UTC()); err != nil {
+		t.Errorf("CheckDebit for an education project = %v, want nil", err)
+	}
+	var fundErr *FundError
+	if _, err := svc.CheckDebit(fund.ID, 100, &clinic.ID, time.Now().UTC()); !errors.As(err, &fundErr) || fundErr.Code != FundErrDonorRestricted {
+		t.Errorf("CheckDebit for a healthcare project = %v, want %s", err, FundErrDonorRestricted)
+	}
+	if _, err := svc.CheckDebit(fund.ID, 600, &school.ID, time.Now().UTC()); !errors.As(err, &fundErr) || fundErr.Code != FundErrInsufficientBalance {
+		t.Errorf("CheckDebit above the balance = %v, want %s", err, FundErrInsufficientBalance)
+	}
+}
+
+func TestConcurrentFundDebitsNeverOverdraw(t *testing.T) {
+	db := openConcurrentTestDB(t)
+	fund := &models.Fund{FundID: "FND-1", Name: "General", FundType: models.FundTypeUnrestricted, TotalAmount: 100, CurrentBalance: 100}
+	mustCreate(t, db, fund)
+	store := repo.NewStore(db)
+
+	// 5 笔 30 元的支出争用 100 元余额：恰好 3 笔成功，其余因余额不足被拒绝
+	const debits = 5
+	errs := make([]error, debits)
+	var wg sync.WaitGroup
+	for i := 0; i < debits; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = store.Transaction(func(tx *repo.Tx) error {
+				locked, err := tx.Funds.GetByID(fund.ID)
+				if err != nil {
+					return err
+				}
+				return debitFund(tx, locked, fundDebit{amount: 30, date: time.Now().UTC()})
+			})
+		}(i)
+	}
+	wg.Wait()
+
+	succeeded := 0
+	for _, err := range errs {
+		var fundErr *FundError
+		switch {
+		case err == nil:
+			succeeded++
+		case errors.As(err, &fundErr) && fundErr.Code == FundErrInsufficientBalance:
+		default:
+			t.Errorf("debit failed with %v, want nil or %s", err, FundErrInsufficientBalance)
+		}
+	}
+	if succeeded != 3 {
+		t.Errorf("%d debits succeeded, want 3", succeeded)
+	}
+	reload(t, db, fund, fund.ID)
+	if fund.CurrentBalance != 10 {
+		t.Errorf("fund balance = %.2f, want 10", fund.CurrentBalance)
+	}
+}
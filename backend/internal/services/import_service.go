@@ -0,0 +1,515 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+
+	"erp-backend/internal/models"
+	"erp-backend/internal/repo"
+
+	"gorm.io/gorm/schema"
+)
+
+// 导入限制
+const (
+	MaxImportRows      = 10000
+	DefaultImportBatch = 500
+	importPreviewRows  = 20
+)
+
+// importSkipColumns 任何资源都不从文件读取的列：主键、时间戳与回收站列
+var importSkipColumns = map[string]bool{
+	"id": true, "created_at": true, "updated_at": true, "deleted_at": true, "deleted_by": true,
+}
+
+// errImportRollback 试运行或整体导入存在失败行时回滚事务
+var errImportRollback = errors.New("import rolled back")
+
+// importer 一种可导入的资源
+type importer struct {
+	table    string
+	model    func() interface{}
+	required []string // 文件中必须给出的字段
+	skip     []string // 由系统生成或由过账维护的列，不从文件读取
+	prefix   string   // 业务编号前缀
+	number   func(record interface{}) *string
+	save     func(s *ImportService, tx *repo.Tx, record interface{}) error
+}
+
+// importers 可导入的资源，键与 /api/v1/dbms 下的路由资源名一致
+var importers = map[string]importer{
+	"donors": {
+		table:    "donors",
+		model:    func() interface{} { return &models.Donor{} },
+		required: []string{"first_name", "last_name"},
+		skip:     []string{"total_donated", "in_kind_total"}, // 由捐赠记录累加
+		prefix:   "DNR",
+		number:   func(record interface{}) *string { return &record.(*models.Donor).DonorID },
+		save: func(s *ImportService, tx *repo.Tx, record interface{}) error {
+			return tx.Donors.Create(record.(*models.Donor))
+		},
+	},
+	"donations": {
+		table:    "donations",
+		model:    func() interface{} { return &models.Donation{} },
+		required: []string{"donor_id", "amount", "donation_type", "category"},
+		skip:     []string{"transaction_id"},
+		prefix:   "DON",
+		number:   func(record interface{}) *string { return &record.(*models.Donation).DonationID },
+		// 与逐笔创建相同：写入交易记录与总账分录，并累加捐赠者与基金余额
+		save: func(s *ImportService, tx *repo.Tx, record interface{}) error {
+			donation := record.(*models.Donation)
+			if err := prepareDonation(donation); err != nil {
+				return err
+			}
+			if err := checkScope(s.scope, "donation", nil, &scopedRecord{projectID: donation.ProjectID}); err != nil {
+				return err
+			}
+			return postDonation(tx, donation)
+		},
+	},
+	"inventory": {
+		table:    "inventories",
+		model:    func() interface{} { return &models.Inventory{} },
+		required: []string{"name"},
+		prefix:   "INV",
+		number:   func(record interface{}) *string { return &record.(*models.Inventory).InventoryID },
+		// current_stock 记为期初入库
+		save: func(s *ImportService, tx *repo.Tx, record interface{}) error {
+			inventory := record.(*models.Inventory)
+			if inventory.CurrentStock < 0 {
+				return invalidInput("current_stock cannot be negative")
+			}
+			return createInventory(tx, inventory)
+		},
+	},
+}
+
+// ImportResources 返回可导入的资源名（按字母排序），用于注册路由
+func ImportResources() []string {
+	names := make([]string, 0, len(importers))
+	for name := range importers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ImportService 批量导入：解析 CSV/XLSX、按映射转换字段、校验外键并写入
+type ImportService struct {
+	repo  *repo.ImportRepository
+	store *repo.Store
+	scope *repo.ProjectScope
+}
+
+func NewImportService(importRepo *repo.ImportRepository, store *repo.Store) *ImportService {
+	return &ImportService{repo: importRepo, store: store}
+}
+
+// Scoped 返回限定在调用者数据范围内的服务副本
+func (s *ImportService) Scoped(scope *repo.ProjectScope) *ImportService {
+	scoped := *s
+	scoped.scope = scope
+	return &scoped
+}
+
+func (s *ImportService) WithContext(ctx context.Context) *ImportService {
+	bound := *s
+	bound.repo = s.repo.WithContext(ctx)
+	bound.store = s.store.WithContext(ctx)
+	return &bound
+}
+
+// ImportRequest 一次导入的文件与选项
+type ImportRequest struct {
+	Resource  string
+	Filename  string
+	Format    string // csv 或 xlsx
+	Sheet     string // XLSX 工作表，为空表示第一个
+	Data      io.Reader
+	Template  string            // 已保存的映射模板名
+	Mapping   map[string]string // {"文件列名": "字段"}，覆盖模板中的同名列；字段为空表示忽略该列
+	DryRun    bool              // 只校验并预览，不写入
+	Mode      string            // models.ImportModeAll（默认）或 models.ImportModeBatch
+	BatchSize int               // 按批提交时每批的行数
+	CreatedBy *uint
+}
+
+// ImportResult 导入结果
+type ImportResult struct {
+	Job     *models.ImportJob    `json:"job"`
+	Columns map[string]string    `json:"columns"`                   // 实际使用的映射：文件列名 → 字段
+	Ignored []string             `json:"ignored_columns,omitempty"` // 未映射到字段的文件列
+	Errors  []models.ImportError `json:"errors"`
+	Preview []interface{}        `json:"preview,omitempty"` // 试运行时前若干行写入后的记录
+}
+
+// importRow 文件中的一个数据行
+type importRow struct {
+	line   int
+	record interface{}
+	values map[string]interface{} // 已转换的字段值，用于外键检查
+	cells  map[string]string
+	failed bool
+}
+
+// Import 导入文件中的全部数据行。按整体提交时，任一行失败则不写入任何行；
+// 按批提交时跳过失败的行，其余行每 BatchSize 行一个事务。无论结果如何都保存一条导入记录
+func (s *ImportService) Import(req *ImportRequest) (*ImportResult, error) {
+	imp, ok := importers[req.Resource]
+	if !ok {
+		return nil, invalidInput("%s cannot be imported", req.Resource)
+	}
+	switch req.Mode {
+	case "":
+		req.Mode = models.ImportModeAll
+	case models.ImportModeAll, models.ImportModeBatch:
+	default:
+		return nil, invalidInput("mode must be %s or %s", models.ImportModeAll, models.ImportModeBatch)
+	}
+	if req.BatchSize <= 0 {
+		req.BatchSize = DefaultImportBatch
+	}
+
+	table, err := readTable(req.Format, req.Sheet, req.Data)
+	if err != nil {
+		return nil, err
+	}
+	if len(table) == 0 {
+		return nil, invalidInput("the file is empty")
+	}
+	if len(table)-1 > MaxImportRows {
+		return nil, invalidInput("the file has %d rows; at most %d rows can be imported at once", len(table)-1, MaxImportRows)
+	}
+
+	fields, err := s.importFields(imp)
+	if err != nil {
+		return nil, err
+	}
+	mapping, err := s.resolveMapping(req, fields)
+	if err != nil {
+		return nil, err
+	}
+	result := &ImportResult{Columns: make(map[string]string), Errors: []models.ImportError{}}
+	columns, err := bindHeader(table[0], mapping, fields, imp, result)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []*importRow
+	for i, cells := range table[1:] {
+		if blankRow(cells) {
+			continue
+		}
+		rows = append(rows, decodeRow(imp, i+2, cells, columns, fields, result))
+	}
+	if err := s.checkReferences(imp, rows, result); err != nil {
+		return nil, err
+	}
+
+	var valid []*importRow
+	for _, row := range rows {
+		if !row.failed {
+			valid = append(valid, row)
+		}
+	}
+	imported, err := s.write(imp, req, valid, result)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(result.Errors, func(i, j int) bool { return result.Errors[i].Row < result.Errors[j].Row })
+	failed := 0
+	for _, row := range rows {
+		if row.failed {
+			failed++
+		}
+	}
+	errs, _ := json.Marshal(result.Errors)
+	result.Job = &models.ImportJob{
+		Resource:  req.Resource,
+		Filename:  req.Filename,
+		Mode:      req.Mode,
+		DryRun:    req.DryRun,
+		TotalRows: len(rows),
+		Imported:  imported,
+		Failed:    failed,
+		Errors:    models.JSONText(errs),
+		CreatedBy: req.CreatedBy,
+	}
+	if err := s.repo.CreateJob(result.Job); err != nil {
+		return nil, fmt.Errorf("failed to save import job: %w", err)
+	}
+	return result, nil
+}
+
+// importFields 返回资源可从文件读取的字段，按列名索引
+func (s *ImportService) importFields(imp importer) (map[string]*schema.Field, error) {
+	all, err := s.repo.Fields(imp.model())
+	if err != nil {
+		return nil, err
+	}
+	fields := make(map[string]*schema.Field, len(all))
+	for _, f := range all {
+		if !importSkipColumns[f.DBName] {
+			fields[f.DBName] = f
+		}
+	}
+	for _, col := range imp.skip {
+		delete(fields, col)
+	}
+	return fields, nil
+}
+
+// resolveMapping 合并映射模板与请求中的映射，并校验映射到的字段
+func (s *ImportService) resolveMapping(req *ImportRequest, fields map[string]*schema.Field) (map[string]string, error) {
+	mapping := make(map[string]string)
+	if req.Template != "" {
+		tmpl, err := s.repo.GetMapping(req.Resource, req.Template)
+		if err != nil {
+			return nil, notFound(err, "import mapping "+req.Template)
+		}
+		if err := json.Unmarshal([]byte(tmpl.Mapping), &mapping); err != nil {
+			return nil, fmt.Errorf("import mapping %s is corrupt: %w", req.Template, err)
+		}
+	}
+	for header, field := range req.Mapping {
+		mapping[header] = field
+	}
+	return mapping, validateMapping(mapping, fields)
+}
+
+func validateMapping(mapping map[string]string, fields map[string]*schema.Field) error {
+	for header, field := range mapping {
+		if _, ok := fields[field]; field != "" && !ok {
+			return invalidInput("column %q is mapped to %q, which cannot be imported", header, field)
+		}
+	}
+	return nil
+}
+
+// bindHeader 确定每个文件列对应的字段：映射中给出的按映射，其余按列名与字段名相同（不区分大小写，空格视为下划线）
+func bindHeader(header []string, mapping map[string]string, fields map[string]*schema.Field, imp importer, result *ImportResult) ([]string, error) {
+	columns := make([]string, len(header))
+	seen := make(map[string]string)
+	for i, name := range header {
+		name = strings.TrimSpace(name)
+		field, mapped := mapping[name]
+		if !mapped {
+			field = strings.ReplaceAll(strings.ToLower(name), " ", "_")
+			if _, ok := fields[field]; !ok {
+				field = ""
+			}
+		}
+		if field == "" {
+			if name != "" {
+				result.Ignored = append(result.Ignored, name)
+			}
+			continue
+		}
+		if other, dup := seen[field]; dup {
+			return nil, invalidInput("columns %q and %q are both mapped to %s", other, name, field)
+		}
+		seen[field] = name
+		columns[i] = field
+		result.Columns[name] = field
+	}
+	for _, field := range imp.required {
+		if _, ok := seen[field]; !ok {
+			return nil, invalidInput("no column is mapped to the required field %s", field)
+		}
+	}
+	return columns, nil
+}
+
+// decodeRow 把一行单元格转换为模型记录，逐字段记录转换失败与缺少的必填字段
+func decodeRow(imp importer, line int, cells, columns []string, fields map[string]*schema.Field, result *ImportResult) *importRow {
+	row := &importRow{line: line, record: imp.model(), values: make(map[string]interface{}), cells: make(map[string]string)}
+	target := reflect.ValueOf(row.record).Elem()
+	fail := func(field, value, message string) {
+		row.failed = true
+		result.Errors = append(result.Errors, models.ImportError{Row: line, Field: field, Value: value, Message: message})
+	}
+	for i, col := range columns {
+		if col == "" || i >= len(cells) {
+			continue
+		}
+		cell := strings.TrimSpace(cells[i])
+		if cell == "" {
+			continue
+		}
+		row.cells[col] = cell
+		value, err := parseCell(fields[col], cell)
+		if err != nil {
+			fail(col, cell, err.Error())
+			continue
+		}
+		if err := fields[col].Set(context.Background(), target, value); err != nil {
+			fail(col, cell, err.Error())
+			continue
+		}
+		row.values[col] = value
+	}
+	for _, col := range imp.required {
+		if _, ok := row.cells[col]; !ok {
+			fail(col, "", "required")
+		}
+	}
+	return row
+}
+
+// checkReferences 按外键关系检查各行引用的记录是否存在，每个外键列一次批量查询
+func (s *ImportService) checkReferences(imp importer, rows []*importRow, result *ImportResult) error {
+	parents := repo.ParentTables(imp.table)
+	columns := make([]string, 0, len(parents))
+	for column := range parents {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+	for _, column := range columns {
+		parent := parents[column]
+		var ids []uint
+		for _, row := range rows {
+			if id, ok := row.values[column].(uint64); ok {
+				ids = append(ids, uint(id))
+			}
+		}
+		if len(ids) == 0 {
+			continue
+		}
+		found, err := s.repo.ExistingIDs(parent, ids)
+		if err != nil {
+			return err
+		}
+		for _, row := range rows {
+			if id, ok := row.values[column].(uint64); ok && !found[uint(id)] {
+				row.failed = true
+				result.Errors = append(result.Errors, models.ImportError{
+					Row: row.line, Field: column, Value: row.cells[column],
+					Message: fmt.Sprintf("%s %d does not exist", parent, id),
+				})
+			}
+		}
+	}
+	return nil
+}
+
+// write 写入通过校验的行，返回写入（试运行时为可写入）的行数。每行在保存点内写入，
+// 写入失败（如唯一约束冲突）只回滚该行并记入错误
+func (s *ImportService) write(imp importer, req *ImportRequest, rows []*importRow, result *ImportResult) (int, error) {
+	// 未给出业务编号的行按行号顺序编号，以免同一秒内生成的编号重复
+	base := generateID(imp.prefix)
+	for _, row := range rows {
+		if number := imp.number(row.record); *number == "" {
+			*number = fmt.Sprintf("%s%05d", base, row.line)
+		}
+	}
+	batchSize := len(rows)
+	if req.Mode == models.ImportModeBatch && !req.DryRun {
+		batchSize = req.BatchSize
+	}
+	imported := 0
+	for start := 0; start < len(rows); start += batchSize {
+		end := start + batchSize
+		if end > len(rows) {
+			end = len(rows)
+		}
+		written := 0
+		err := s.store.Transaction(func(tx *repo.Tx) error {
+			for _, row := range rows[start:end] {
+				err := tx.Transaction(func(tx *repo.Tx) error {
+					return imp.save(s, tx, row.record)
+				})
+				if err != nil {
+					row.failed = true
+					result.Errors = append(result.Errors, models.ImportError{Row: row.line, Message: err.Error()})
+					continue
+				}
+				written++
+				if req.DryRun && len(result.Preview) < importPreviewRows {
+					result.Preview = append(result.Preview, row.record)
+				}
+			}
+			if req.DryRun || (req.Mode == models.ImportModeAll && len(result.Errors) > 0) {
+				return errImportRollback
+			}
+			return nil
+		})
+		switch {
+		case err == nil:
+			imported += written
+		case errors.Is(err, errImportRollback):
+			if req.DryRun {
+				imported += written
+			}
+		default:
+			return imported, err
+		}
+	}
+	return imported, nil
+}
+
+// ErrorReport 返回导入记录及其逐行错误
+func (s *ImportService) ErrorReport(resource string, id uint) (*models.ImportJob, []models.ImportError, error) {
+	job, err := s.repo.GetJob(resource, id)
+	if err != nil {
+		return nil, nil, notFound(err, "import job")
+	}
+	var errs []models.ImportError
+	if job.Errors != "" {
+		if err := json.Unmarshal([]byte(job.Errors), &errs); err != nil {
+			return nil, nil, fmt.Errorf("import job %d has a corrupt error list: %w", id, err)
+		}
+	}
+	return job, errs, nil
+}
+
+// ListMappings 返回资源的映射模板
+func (s *ImportService) ListMappings(resource string) ([]models.ImportMapping, error) {
+	if _, ok := importers[resource]; !ok {
+		return nil, invalidInput("%s cannot be imported", resource)
+	}
+	return s.repo.ListMappings(resource)
+}
+
+// SaveMapping 校验并保存映射模板，同名模板被替换
+func (s *ImportService) SaveMapping(resource, name string, mapping map[string]string, createdBy *uint) (*models.ImportMapping, error) {
+	imp, ok := importers[resource]
+	if !ok {
+		return nil, invalidInput("%s cannot be imported", resource)
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, invalidInput("name is required")
+	}
+	if len(mapping) == 0 {
+		return nil, invalidInput("mapping is required")
+	}
+	fields, err := s.importFields(imp)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateMapping(mapping, fields); err != nil {
+		return nil, err
+	}
+	raw, err := json.Marshal(mapping)
+	if err != nil {
+		return nil, err
+	}
+	m := &models.ImportMapping{Resource: resource, Name: name, Mapping: models.JSONText(raw), CreatedBy: createdBy}
+	if err := s.repo.SaveMapping(m); err != nil {
+		return nil, fmt.Errorf("failed to save import mapping: %w", err)
+	}
+	return m, nil
+}
+
+// DeleteMapping 删除映射模板
+func (s *ImportService) DeleteMapping(resource string, id uint) error {
+	return notFound(s.repo.DeleteMapping(resource, id), "import mapping")
+}
@@ -0,0 +1,90 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"erp-backend/internal/models"
+	"erp-backend/internal/repo"
+)
+
+func TestImportDryRunAndCommitModes(t *testing.T) {
+	// 第 3 行引用不存在的捐赠者（校验失败），第 5 行与第 2 行编号重复（写入失败）
+	const file = `donation_id,donor_id,amount,donation_type,category
+DON-A,%[1]d,10,one-time,cash
+DON-B,999,20,one-time,cash
+DON-C,%[1]d,30,one-time,cash
+DON-A,%[1]d,40,one-time,cash
+DON-D,%[1]d,50,one-time,cash
+`
+	tests := []struct {
+		name      string
+		dryRun    bool
+		mode      string
+		batchSize int
+		imported  int
+		stored    []string // 导入后库中的捐赠编号
+		total     float64  // 导入后捐赠者的累计捐赠额与现金科目余额
+	}{
+		{"dry run", true, models.ImportModeAll, 0, 3, nil, 0},
+		{"all or nothing", false, models.ImportModeAll, 0, 0, nil, 0},
+		// 第一批（第 2、4 行）提交后，第二批中重复的第 5 行单独回滚，第 6 行照常写入
+		{"per batch", false, models.ImportModeBatch, 2, 3, []string{"DON-A", "DON-C", "DON-D"}, 90},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := openServiceTestDB(t)
+			donor := &models.Donor{DonorID: "DNR-1", FirstName: "Dana", LastName: "Lee"}
+			mustCreate(t, db, donor)
+			svc := NewImportService(repo.NewImportRepository(db), repo.NewStore(db))
+
+			result, err := svc.Import(&ImportRequest{
+				Resource: "donations", Filename: "donations.csv", Format: "csv",
+				Data:   strings.NewReader(fmt.Sprintf(file, donor.ID)),
+				DryRun: tt.dryRun, Mode: tt.mode, BatchSize: tt.batchSize,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			job := result.Job
+			if job.TotalRows != 5 || job.Imported != tt.imported || job.Failed != 2 || job.DryRun != tt.dryRun {
+				t.Errorf("job rows %d imported %d failed %d dry_run %v, want 5, %d, 2, %v",
+					job.TotalRows, job.Imported, job.Failed, job.DryRun, tt.imported, tt.dryRun)
+			}
+			if len(result.Errors) != 2 || result.Errors[0].Row != 3 || result.Errors[0].Field != "donor_id" || result.Errors[1].Row != 5 {
+				t.Errorf("errors = %+v, want donor_id on row 3 and a write failure on row 5", result.Errors)
+			}
+			if tt.dryRun && len(result.Preview) != tt.imported {
+				t.Errorf("dry run previewed %d rows, want %d", len(result.Preview), tt.imported)
+			}
+
+			var stored []string
+			if err := db.Model(&models.Donation{}).Pluck("donation_id", &stored).Error; err != nil {
+				t.Fatal(err)
+			}
+			sort.Strings(stored)
+			if fmt.Sprint(stored) != fmt.Sprint(tt.stored) {
+				t.Errorf("stored donations %v, want %v", stored, tt.stored)
+			}
+			// 回滚的行不应留下余额或总账分录
+			reload(t, db, donor, donor.ID)
+			if donor.TotalDonated != tt.total {
+				t.Errorf("donor total_donated = %.2f, want %.2f", donor.TotalDonated, tt.total)
+			}
+			if got := accountBalance(t, db, models.AccountCash); got != tt.total {
+				t.Errorf("cash balance = %.2f, want %.2f", got, tt.total)
+			}
+
+			// 无论是否写入都保存导入记录与错误报告
+			saved, errs, err := svc.ErrorReport("donations", job.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if saved.Imported != tt.imported || len(errs) != 2 {
+				t.Errorf("saved job imported %d with %d errors, want %d and 2", saved.Imported, len(errs), tt.imported)
+			}
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package services
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+	"gorm.io/gorm/schema"
+)
+
+// 导入文件格式
+const (
+	ImportFormatCSV  = "csv"
+	ImportFormatXLSX = "xlsx"
+)
+
+// importDateLayouts 日期列接受的格式；XLSX 中的日期单元格以序列号读出，另行换算
+var importDateLayouts = []string{
+	"2006-01-02",
+	time.RFC3339,
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05",
+	"2006/01/02",
+	"2006/1/2",
+}
+
+// readTable 读出文件中的全部行（含表头）；XLSX 读取 sheet 指定的工作表，为空时读取第一个
+func readTable(format, sheet string, data io.Reader) ([][]string, error) {
+	switch format {
+	case ImportFormatCSV:
+		raw, err := io.ReadAll(data)
+		if err != nil {
+			return nil, err
+		}
+		// Excel 另存的 CSV 带有 UTF-8 BOM
+		r := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(raw, []byte("\xef\xbb\xbf"))))
+		r.FieldsPerRecord = -1
+		rows, err := r.ReadAll()
+		if err != nil {
+			return nil, invalidInput("malformed CSV: %v", err)
+		}
+		return rows, nil
+	case ImportFormatXLSX:
+		book, err := excelize.OpenReader(data)
+		if err != nil {
+			return nil, invalidInput("malformed XLSX: %v", err)
+		}
+		defer book.Close()
+		if sheet == "" {
+			sheet = book.GetSheetName(0)
+		}
+		// 读取原始值：数字不带千分位，日期为序列号
+		rows, err := book.GetRows(sheet, excelize.Options{RawCellValue: true})
+		if err != nil {
+			return nil, invalidInput("cannot read sheet %q: %v", sheet, err)
+		}
+		return rows, nil
+	}
+	return nil, invalidInput("unsupported format %q (use csv or xlsx)", format)
+}
+
+// blankRow 判断一行是否所有单元格都为空
+func blankRow(row []string) bool {
+	for _, cell := range row {
+		if strings.TrimSpace(cell) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// parseCell 按字段类型转换单元格文本，返回可赋给字段的值
+func parseCell(field *schema.Field, cell string) (interface{}, error) {
+	t := field.IndirectFieldType
+	if t == reflect.TypeOf(time.Time{}) {
+		return parseImportDate(cell)
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return cell, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v, err := strconv.ParseInt(cell, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("not an integer")
+		}
+		return v, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v, err := strconv.ParseUint(cell, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("not a non-negative integer")
+		}
+		return v, nil
+	case reflect.Float32, reflect.Float64:
+		v, err := strconv.ParseFloat(cell, 64)
+		if err != nil {
+			return nil, fmt.Errorf("not a number")
+		}
+		return v, nil
+	case reflect.Bool:
+		switch strings.ToLower(cell) {
+		case "true", "yes", "y", "1":
+			return true, nil
+		case "false", "no", "n", "0":
+			return false, nil
+		}
+		return nil, fmt.Errorf("not a boolean (use true or false)")
+	}
+	return nil, fmt.Errorf("field type %s cannot be imported", t)
+}
+
+// parseImportDate 解析日期文本或 XLSX 日期序列号
+func parseImportDate(cell string) (time.Time, error) {
+	for _, layout := range importDateLayouts {
+		if t, err := time.Parse(layout, cell); err == nil {
+			return t, nil
+		}
+	}
+	if serial, err := strconv.ParseFloat(cell, 64); err == nil {
+		if t, err := excelize.ExcelDateToTime(serial, false); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("not a date (use YYYY-MM-DD)")
+}
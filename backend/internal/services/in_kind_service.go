@@ -0,0 +1,246 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"erp-backend/internal/models"
+	"erp-backend/internal/repo"
+)
+
+// 实物捐赠入库：在同一事务内写入实物捐赠、估值记录、入库变动与总账分录，
+// 并累加捐赠者的实物捐赠累计额。任一步失败则全部回滚
+
+// valuationBases 可用的估值依据
+var valuationBases = map[string]bool{
+	models.ValuationFairMarket:    true,
+	models.ValuationAppraisal:     true,
+	models.ValuationDonorEstimate: true,
+}
+
+// Create 登记一笔实物捐赠并把物品计入库存
+func (s *DonationInventoryService) Create(di *models.DonationInventory) error {
+	valuation, err := prepareInKind(di, nil)
+	if err != nil {
+		return err
+	}
+	return s.store.Transaction(func(tx *repo.Tx) error {
+		donor, inventory, err := loadInKindParties(tx, di)
+		if err != nil {
+			return err
+		}
+		if err := tx.DonationInventories.Create(di); err != nil {
+			return fmt.Errorf("failed to create in-kind donation: %w", err)
+		}
+		if err := saveValuation(tx, di, valuation); err != nil {
+			return err
+		}
+		if _, err := postMove(tx, inKindMove(di)); err != nil {
+			return err
+		}
+		if err := tx.Donors.AddInKindTotal(*di.DonorID, di.EstimatedValue); err != nil {
+			return fmt.Errorf("failed to update donor in-kind total: %w", err)
+		}
+		_, err = postJournal(tx, inKindJournal(di, donor, inventory))
+		return err
+	})
+}
+
+// Update 按新内容重新入库并冲销原入库；请求未给出 valuation 时沿用原估值依据
+func (s *DonationInventoryService) Update(di *models.DonationInventory) error {
+	return s.store.Transaction(func(tx *repo.Tx) error {
+		old, err := tx.DonationInventories.GetByID(di.ID)
+		if err != nil {
+			return notFound(err, "in-kind donation")
+		}
+		valuation, err := prepareInKind(di, old.Valuation)
+		if err != nil {
+			return err
+		}
+		donor, inventory, err := loadInKindParties(tx, di)
+		if err != nil {
+			return err
+		}
+		di.CreatedAt = old.CreatedAt
+		if err := tx.DonationInventories.Update(di); err != nil {
+			return fmt.Errorf("failed to update in-kind donation: %w", err)
+		}
+		if err := saveValuation(tx, di, valuation); err != nil {
+			return err
+		}
+		// 先入库新数量再冲销原数量，已发出部分物品时只要库存足以承担净减少量即可修改
+		if _, err := repostMove(tx, inKindMove(di)); err != nil {
+			return err
+		}
+		if err := tx.Donors.AddInKindTotal(*di.DonorID, di.EstimatedValue); err != nil {
+			return fmt.Errorf("failed to update donor in-kind total: %w", err)
+		}
+		if err := tx.Donors.AddInKindTotal(*old.DonorID, -old.EstimatedValue); err != nil {
+			return fmt.Errorf("failed to update donor in-kind total: %w", err)
+		}
+		return repostJournal(tx, inKindJournal(di, donor, inventory))
+	})
+}
+
+// Delete 冲销实物捐赠的入库、捐赠者累计额与总账分录后删除；物品已经发出、库存不足以退回时拒绝
+func (s *DonationInventoryService) Delete(id uint) error {
+	return s.store.Transaction(func(tx *repo.Tx) error {
+		old, err := tx.DonationInventories.GetByID(id)
+		if err != nil {
+			return notFound(err, "in-kind donation")
+		}
+		return deleteInKind(tx, old)
+	})
+}
+
+// deleteInKind 冲销并删除一笔实物捐赠，也用于强制级联删除
+func deleteInKind(tx *repo.Tx, old *models.DonationInventory) error {
+	if err := reverseMoves(tx, models.SourceInKind, old.ID); err != nil {
+		return err
+	}
+	if err := tx.Donors.AddInKindTotal(*old.DonorID, -old.EstimatedValue); err != nil {
+		return fmt.Errorf("failed to update donor in-kind total: %w", err)
+	}
+	if err := reverseJournal(tx, models.SourceInKind, old.ID); err != nil {
+		return err
+	}
+	if err := tx.DonationInventories.Delete(old.ID); err != nil {
+		return fmt.Errorf("failed to delete in-kind donation: %w", err)
+	}
+	return nil
+}
+
+// restoreInKind 恢复实物捐赠后重新入库并过账
+func restoreInKind(tx *repo.Tx, id uint) error {
+	di, err := tx.DonationInventories.GetByID(id)
+	if err != nil {
+		return notFound(err, "in-kind donation")
+	}
+	donor, inventory, err := loadInKindParties(tx, di)
+	if err != nil {
+		return err
+	}
+	if _, err := postMove(tx, inKindMove(di)); err != nil {
+		return err
+	}
+	if err := tx.Donors.AddInKindTotal(*di.DonorID, di.EstimatedValue); err != nil {
+		return fmt.Errorf("failed to update donor in-kind total: %w", err)
+	}
+	_, err = postJournal(tx, inKindJournal(di, donor, inventory))
+	return err
+}
+
+// prepareInKind 校验实物捐赠并补齐默认值，返回要保存的估值记录。
+// 估值可给出总估值 estimated_value 或单价 valuation.unit_value，另一项按数量计算；
+// 请求未给出估值依据时沿用 prev（新建时为市场价）
+func prepareInKind(di *models.DonationInventory, prev *models.InKindValuation) (*models.InKindValuation, error) {
+	if di.DonorID == nil {
+		return nil, invalidInput("donor_id is required")
+	}
+	if di.InventoryID == 0 {
+		return nil, invalidInput("inventory_id is required")
+	}
+	if di.Quantity == 0 {
+		di.Quantity = 1
+	}
+	if di.Quantity < 0 {
+		return nil, invalidInput("quantity must be greater than zero")
+	}
+	if di.DonationDate == nil {
+		now := time.Now().UTC()
+		di.DonationDate = &now
+	}
+
+	v := di.Valuation
+	di.Valuation = nil
+	if v == nil {
+		v = &models.InKindValuation{Basis: models.ValuationFairMarket}
+		if prev != nil {
+			v.Basis, v.Appraiser, v.Notes = prev.Basis, prev.Appraiser, prev.Notes
+		}
+	}
+	if v.Basis == "" {
+		v.Basis = models.ValuationFairMarket
+	}
+	if !valuationBases[v.Basis] {
+		return nil, invalidInput("valuation basis must be %s, %s or %s",
+			models.ValuationFairMarket, models.ValuationAppraisal, models.ValuationDonorEstimate)
+	}
+	v.Appraiser = strings.TrimSpace(v.Appraiser)
+	if v.Basis == models.ValuationAppraisal && v.Appraiser == "" {
+		return nil, invalidInput("an appraisal valuation needs the appraiser")
+	}
+	if v.UnitValue < 0 || di.EstimatedValue < 0 {
+		return nil, invalidInput("estimated_value cannot be negative")
+	}
+
+	quantity := float64(di.Quantity)
+	switch {
+	case di.EstimatedValue == 0:
+		di.EstimatedValue = roundCents(v.UnitValue * quantity)
+	case v.UnitValue != 0 && math.Abs(v.UnitValue*quantity-di.EstimatedValue) >= 0.01:
+		return nil, invalidInput("valuation unit_value × quantity (%.2f) does not match estimated_value (%.2f)",
+			v.UnitValue*quantity, di.EstimatedValue)
+	}
+	if di.EstimatedValue <= 0 {
+		return nil, invalidInput("estimated_value or valuation.unit_value is required")
+	}
+	di.EstimatedValue = roundCents(di.EstimatedValue)
+	v.UnitValue = roundCents(di.EstimatedValue / quantity)
+	v.TotalValue = di.EstimatedValue
+	v.ValuedAt = time.Now().UTC()
+	return v, nil
+}
+
+// loadInKindParties 锁定并返回实物捐赠涉及的捐赠者与库存
+func loadInKindParties(tx *repo.Tx, di *models.DonationInventory) (*models.Donor, *models.Inventory, error) {
+	donor, err := tx.Donors.GetByID(*di.DonorID)
+	if err != nil {
+		return nil, nil, notFound(err, "donor")
+	}
+	inventory, err := tx.Inventories.GetByID(di.InventoryID)
+	if err != nil {
+		return nil, nil, notFound(err, "inventory")
+	}
+	return donor, inventory, nil
+}
+
+func saveValuation(tx *repo.Tx, di *models.DonationInventory, valuation *models.InKindValuation) error {
+	valuation.ID = 0
+	valuation.DonationInventoryID = di.ID
+	if err := tx.DonationInventories.SaveValuation(valuation); err != nil {
+		return fmt.Errorf("failed to save valuation: %w", err)
+	}
+	di.Valuation = valuation
+	return nil
+}
+
+// inKindMove 实物捐赠入库，单位成本取估值单价
+func inKindMove(di *models.DonationInventory) stockMove {
+	inventoryID := di.InventoryID
+	return stockMove{
+		moveType: models.InventoryMoveDonation,
+		to:       &inventoryID,
+		quantity: di.Quantity,
+		date:     *di.DonationDate,
+		source:   models.SourceInKind,
+		sourceID: di.ID,
+		unitCost: di.EstimatedValue / float64(di.Quantity),
+	}
+}
+
+// inKindJournal 借：库存；贷：实物捐赠收入
+func inKindJournal(di *models.DonationInventory, donor *models.Donor, inventory *models.Inventory) journal {
+	return journal{
+		source:      models.SourceInKind,
+		sourceID:    di.ID,
+		date:        *di.DonationDate,
+		description: fmt.Sprintf("In-kind donation of %d × %s from %s %s (%s)", di.Quantity, inventory.Name, donor.FirstName, donor.LastName, donor.DonorID),
+		lines: []ledgerLine{
+			{account: models.AccountInventory, projectID: di.ProjectID, debit: di.EstimatedValue},
+			{account: models.AccountContributionsInKind, projectID: di.ProjectID, credit: di.EstimatedValue},
+		},
+	}
+}
@@ -0,0 +1,113 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"erp-backend/internal/models"
+	"erp-backend/internal/repo"
+
+	"gorm.io/gorm"
+)
+
+// requireInKindState 检查库存数量、捐赠者实物累计额，以及库存科目与实物捐赠收入科目的余额
+func requireInKindState(t *testing.T, db *gorm.DB, what string, inventoryID, donorID uint, stock int, value float64) {
+	t.Helper()
+	var inventory models.Inventory
+	reload(t, db, &inventory, inventoryID)
+	var donor models.Donor
+	reload(t, db, &donor, donorID)
+	if inventory.CurrentStock != stock || donor.InKindTotal != value {
+		t.Errorf("%s: stock %d donor in-kind total %.2f, want %d and %.2f", what, inventory.CurrentStock, donor.InKindTotal, stock, value)
+	}
+	for _, account := range []string{models.AccountInventory, models.AccountContributionsInKind} {
+		if got := accountBalance(t, db, account); got != value {
+			t.Errorf("%s: account %s balance %.2f, want %.2f", what, account, got, value)
+		}
+	}
+}
+
+func TestInKindDonationPostsStockAndValue(t *testing.T) {
+	db := openServiceTestDB(t)
+	donor := &models.Donor{DonorID: "DNR-1", FirstName: "Dana", LastName: "Lee"}
+	inventory := &models.Inventory{InventoryID: "INV-1", Name: "Blanket"}
+	delivery := &models.Delivery{DeliveryID: "DEL-1", Quantity: 5}
+	mustCreate(t, db, donor, inventory, delivery)
+	svc := NewDonationInventoryService(repo.NewDonationInventoryRepository(db), repo.NewStore(db))
+
+	invalid := []struct {
+		name string
+		di   models.DonationInventory
+	}{
+		{"no value", models.DonationInventory{Quantity: 2}},
+		{"unit value does not match the total", models.DonationInventory{Quantity: 2, EstimatedValue: 30,
+			Valuation: &models.InKindValuation{UnitValue: 10}}},
+		{"appraisal without appraiser", models.DonationInventory{Quantity: 2, EstimatedValue: 30,
+			Valuation: &models.InKindValuation{Basis: models.ValuationAppraisal}}},
+		{"unknown basis", models.DonationInventory{Quantity: 2, EstimatedValue: 30,
+			Valuation: &models.InKindValuation{Basis: "guess"}}},
+	}
+	for _, tt := range invalid {
+		di := tt.di
+		di.DonorID, di.InventoryID = &donor.ID, inventory.ID
+		if err := svc.Create(&di); !errors.Is(err, ErrInvalidInput) {
+			t.Errorf("%s: Create = %v, want ErrInvalidInput", tt.name, err)
+		}
+	}
+	requireInKindState(t, db, "after rejected donations", inventory.ID, donor.ID, 0, 0)
+
+	// 只给出单价时按数量计算总估值，入库变动的单位成本取估值单价
+	di := &models.DonationInventory{DonorID: &donor.ID, InventoryID: inventory.ID, Quantity: 4,
+		Valuation: &models.InKindValuation{Basis: models.ValuationAppraisal, Appraiser: "Acme Appraisals", UnitValue: 12.5}}
+	if err := svc.Create(di); err != nil {
+		t.Fatal(err)
+	}
+	if di.EstimatedValue != 50 {
+		t.Errorf("estimated_value = %.2f, want 50", di.EstimatedValue)
+	}
+	requireInKindState(t, db, "after create", inventory.ID, donor.ID, 4, 50)
+	var move models.InventoryTransaction
+	if err := db.Where("source_type = ? AND source_id = ?", models.SourceInKind, di.ID).First(&move).Error; err != nil {
+		t.Fatal(err)
+	}
+	if move.QuantityChange != 4 || move.UnitCost != 12.5 {
+		t.Errorf("stock movement quantity %d unit cost %.2f, want 4 and 12.50", move.QuantityChange, move.UnitCost)
+	}
+
+	// 修改时未给出估值依据则沿用原依据
+	update := &models.DonationInventory{ID: di.ID, DonorID: &donor.ID, InventoryID: inventory.ID, Quantity: 6, EstimatedValue: 90}
+	if err := svc.Update(update); err != nil {
+		t.Fatal(err)
+	}
+	requireInKindState(t, db, "after update", inventory.ID, donor.ID, 6, 90)
+	stored, err := repo.NewDonationInventoryRepository(db).GetByID(di.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := stored.Valuation; v == nil || v.Basis != models.ValuationAppraisal || v.Appraiser != "Acme Appraisals" || v.UnitValue != 15 || v.TotalValue != 90 {
+		t.Errorf("valuation after update = %+v, want appraisal by Acme Appraisals at 15 × 6 = 90", v)
+	}
+
+	// 已发出的物品无法退回时不能删除，删除失败不改变任何余额
+	lines := NewDeliveryInventoryService(repo.NewDeliveryInventoryRepository(db), repo.NewStore(db))
+	line := &models.DeliveryInventory{DeliveryID: &delivery.ID, InventoryID: &inventory.ID, Quantity: 5}
+	if err := lines.Create(line); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.Delete(di.ID); !errors.Is(err, ErrConflict) {
+		t.Fatalf("deleting an in-kind donation whose items were issued = %v, want ErrConflict", err)
+	}
+	var inv models.Inventory
+	reload(t, db, &inv, inventory.ID)
+	if inv.CurrentStock != 1 {
+		t.Errorf("stock after the rejected delete = %d, want 1", inv.CurrentStock)
+	}
+
+	if err := lines.Delete(line.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.Delete(di.ID); err != nil {
+		t.Fatal(err)
+	}
+	requireInKindState(t, db, "after delete", inventory.ID, donor.ID, 0, 0)
+}
@@ -0,0 +1,58 @@
+package services
+
+import (
+	"erp-backend/internal/repo"
+)
+
+// 强制级联删除（?force=cascade）时，过账类单据按各自的删除流程冲销余额与总账后再删除
+func init() {
+	repo.RegisterForceDeleter("donations", func(tx *repo.Tx, id uint) error {
+		old, err := tx.Donations.GetByID(id)
+		if err != nil {
+			return notFound(err, "donation")
+		}
+		return deleteDonation(tx, old)
+	})
+	repo.RegisterForceDeleter("expenses", func(tx *repo.Tx, id uint) error {
+		old, err := tx.Expenses.GetByID(id)
+		if err != nil {
+			return notFound(err, "expense")
+		}
+		return deleteExpense(tx, old)
+	})
+	repo.RegisterForceDeleter("purchases", func(tx *repo.Tx, id uint) error {
+		old, err := tx.Purchases.GetByID(id)
+		if err != nil {
+			return notFound(err, "purchase")
+		}
+		return deletePurchase(tx, old)
+	})
+	repo.RegisterForceDeleter("payrolls", func(tx *repo.Tx, id uint) error {
+		old, err := tx.Payrolls.GetByID(id)
+		if err != nil {
+			return notFound(err, "payroll")
+		}
+		return deletePayroll(tx, old)
+	})
+	repo.RegisterForceDeleter("fund_projects", func(tx *repo.Tx, id uint) error {
+		old, err := tx.FundProjects.GetByID(id)
+		if err != nil {
+			return notFound(err, "fund allocation")
+		}
+		return deleteFundProject(tx, old)
+	})
+	repo.RegisterForceDeleter("donation_inventories", func(tx *repo.Tx, id uint) error {
+		old, err := tx.DonationInventories.GetByID(id)
+		if err != nil {
+			return notFound(err, "in-kind donation")
+		}
+		return deleteInKind(tx, old)
+	})
+	repo.RegisterForceDeleter("delivery_inventories", func(tx *repo.Tx, id uint) error {
+		old, err := tx.DeliveryInventories.GetByID(id)
+		if err != nil {
+			return notFound(err, "delivery line")
+		}
+		return deleteDeliveryLine(tx, old)
+	})
+}
@@ -0,0 +1,209 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"erp-backend/internal/models"
+	"erp-backend/internal/repo"
+)
+
+// 库存台账：库存数量只由库存变动调整。新建库存时的数量记为一笔入库，之后的到货、调拨、
+// 盘点调整手工登记为库存变动，配送与实物捐赠的变动由各自的单据产生。
+// 变动只增不改：修改以冲销原变动并过账新变动完成，删除以冲销完成，原记录保留
+
+// Create 新建库存；请求中的 current_stock 记为一笔期初入库
+func (s *InventoryService) Create(inventory *models.Inventory) error {
+	if inventory.CurrentStock < 0 {
+		return invalidInput("current_stock cannot be negative")
+	}
+	return s.store.Transaction(func(tx *repo.Tx) error {
+		return createInventory(tx, inventory)
+	})
+}
+
+// createInventory 写入库存并按 current_stock 过账期初入库，也用于批量导入。
+// 期初入库的单位成本取库存上的 unit_cost，未填写时按所属采购中尚未入库的金额计算
+func createInventory(tx *repo.Tx, inventory *models.Inventory) error {
+	opening := inventory.CurrentStock
+	inventory.CurrentStock = 0
+	if err := tx.Inventories.Create(inventory); err != nil {
+		return fmt.Errorf("failed to create inventory: %w", err)
+	}
+	if opening == 0 {
+		return nil
+	}
+	id := inventory.ID
+	if _, err := postMove(tx, stockMove{moveType: models.InventoryMoveReceipt, to: &id, quantity: opening, date: time.Now().UTC(),
+		unitCost: inventory.UnitCost, purchaseID: optionalID(inventory.PurchaseID)}); err != nil {
+		return err
+	}
+	inventory.CurrentStock = opening
+	return nil
+}
+
+// Update 修改库存信息；current_stock 由库存变动维护，与现有数量不同时拒绝
+func (s *InventoryService) Update(inventory *models.Inventory) error {
+	return s.store.Transaction(func(tx *repo.Tx) error {
+		old, err := tx.Inventories.GetByID(inventory.ID)
+		if err != nil {
+			return notFound(err, "inventory")
+		}
+		if inventory.CurrentStock != old.CurrentStock {
+			return invalidInput("current_stock is maintained by stock movements (now %d); post a receipt or adjustment instead", old.CurrentStock)
+		}
+		if err := tx.Inventories.Update(inventory); err != nil {
+			return fmt.Errorf("failed to update inventory: %w", err)
+		}
+		return nil
+	})
+}
+
+// Reconcile 比较各库存的账面数量与按库存变动累计的数量，返回检查的库存数；
+// all 为 false 时只返回两者不一致的库存
+func (s *InventoryService) Reconcile(all bool) ([]repo.StockBalance, int, error) {
+	balances, err := s.repo.StockBalances()
+	if err != nil {
+		return nil, 0, err
+	}
+	out := make([]repo.StockBalance, 0, len(balances))
+	for _, b := range balances {
+		if all || b.Drift != 0 {
+			out = append(out, b)
+		}
+	}
+	return out, len(balances), nil
+}
+
+// restoreInventory 恢复随库存一起删除的库存变动
+func restoreInventory(tx *repo.Tx, id uint) error {
+	if err := tx.InventoryTransactions.RestoreByInventory(id); err != nil {
+		return fmt.Errorf("failed to restore stock movements: %w", err)
+	}
+	return nil
+}
+
+// Create 手工登记一笔库存变动并调整库存数量
+func (s *InventoryTransactionService) Create(move *models.InventoryTransaction) error {
+	m, err := manualMove(move)
+	if err != nil {
+		return err
+	}
+	return s.store.Transaction(func(tx *repo.Tx) error {
+		posted, err := postMove(tx, m)
+		if err != nil {
+			return err
+		}
+		*move = *posted
+		return nil
+	})
+}
+
+// Update 冲销原变动并按请求内容过账新变动，move 返回新变动
+func (s *InventoryTransactionService) Update(move *models.InventoryTransaction) error {
+	m, err := manualMove(move)
+	if err != nil {
+		return err
+	}
+	return s.store.Transaction(func(tx *repo.Tx) error {
+		old, err := tx.InventoryTransactions.GetByID(move.ID)
+		if err != nil {
+			return notFound(err, "stock movement")
+		}
+		if err := checkManualMove(old); err != nil {
+			return err
+		}
+		m.replaces = []uint{old.ID}
+		posted, err := postMove(tx, m)
+		if err != nil {
+			return err
+		}
+		if err := reverseEach(tx, []models.InventoryTransaction{*old}); err != nil {
+			return err
+		}
+		*move = *posted
+		return nil
+	})
+}
+
+// Delete 以一笔方向相反的变动冲销手工登记的变动
+func (s *InventoryTransactionService) Delete(id uint) error {
+	return s.store.Transaction(func(tx *repo.Tx) error {
+		old, err := tx.InventoryTransactions.GetByID(id)
+		if err != nil {
+			return notFound(err, "stock movement")
+		}
+		if err := checkManualMove(old); err != nil {
+			return err
+		}
+		return reverseEach(tx, []models.InventoryTransaction{*old})
+	})
+}
+
+// checkManualMove 只有手工登记且未冲销的变动可以修改或冲销；单据产生的变动随单据修改
+func checkManualMove(m *models.InventoryTransaction) error {
+	switch {
+	case m.SourceType != "":
+		return conflict("stock movement %d was posted by %s %d; change that record instead", m.ID, m.SourceType, derefID(m.SourceID))
+	case m.ReversalOfID != nil:
+		return conflict("stock movement %d is the reversal of movement %d", m.ID, *m.ReversalOfID)
+	case m.ReversedByID != nil:
+		return conflict("stock movement %d has already been reversed by movement %d", m.ID, *m.ReversedByID)
+	}
+	return nil
+}
+
+// manualMove 校验手工登记的变动：入库只有 to，调拨 from 与 to 都有且不同，
+// 调整只有其中一方（to 为盘盈、from 为盘亏或损耗）。unit_cost 与 purchase_id 只用于单边入库的定价，
+// 出库一方的成本按计价方法计算
+func manualMove(move *models.InventoryTransaction) (stockMove, error) {
+	from, to := optionalID(move.FromInventoryID), optionalID(move.ToInventoryID)
+	purchaseID := optionalID(move.PurchaseID)
+	if move.UnitCost < 0 {
+		return stockMove{}, invalidInput("unit_cost cannot be negative")
+	}
+	if purchaseID != nil && move.TransactionType != models.InventoryMoveReceipt {
+		return stockMove{}, invalidInput("purchase_id only applies to receipts")
+	}
+	switch move.TransactionType {
+	case models.InventoryMoveReceipt:
+		if from != nil || to == nil {
+			return stockMove{}, invalidInput("a receipt needs to_inventory_id only")
+		}
+	case models.InventoryMoveTransfer:
+		if from == nil || to == nil {
+			return stockMove{}, invalidInput("a transfer needs from_inventory_id and to_inventory_id")
+		}
+		if *from == *to {
+			return stockMove{}, invalidInput("a transfer needs two different inventories")
+		}
+	case models.InventoryMoveAdjustment:
+		if (from == nil) == (to == nil) {
+			return stockMove{}, invalidInput("an adjustment needs either to_inventory_id (increase) or from_inventory_id (decrease)")
+		}
+	default:
+		return stockMove{}, invalidInput("transaction_type must be %s, %s or %s; deliveries and in-kind donations post their own movements",
+			models.InventoryMoveReceipt, models.InventoryMoveTransfer, models.InventoryMoveAdjustment)
+	}
+	date := move.TransactionDate
+	if date.IsZero() {
+		date = time.Now().UTC()
+	}
+	return stockMove{moveType: move.TransactionType, from: from, to: to, quantity: move.QuantityChange, date: date,
+		unitCost: move.UnitCost, purchaseID: purchaseID}, nil
+}
+
+// optionalID 把 0 视为未填写
+func optionalID(id *uint) *uint {
+	if id == nil || *id == 0 {
+		return nil
+	}
+	return id
+}
+
+func derefID(id *uint) uint {
+	if id == nil {
+		return 0
+	}
+	return *id
+}
@@ -0,0 +1,288 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"erp-backend/internal/models"
+	"erp-backend/internal/repo"
+
+	"gorm.io/gorm"
+)
+
+// LedgerService 总账服务：科目维护、手工分录、试算平衡与科目明细查询
+type LedgerService struct {
+	repo  *repo.LedgerRepository
+	store *repo.Store
+}
+
+func NewLedgerService(ledgerRepo *repo.LedgerRepository, store *repo.Store) *LedgerService {
+	return &LedgerService{repo: ledgerRepo, store: store}
+}
+
+// ManualEntryRequest 手工分录请求
+type ManualEntryRequest struct {
+	EntryDate   string              `json:"entry_date" binding:"required"`
+	Description string              `json:"description" binding:"required"`
+	Lines       []ManualLineRequest `json:"lines" binding:"required,min=2"`
+}
+
+// ManualLineRequest 手工分录明细行，按科目代码指定
+type ManualLineRequest struct {
+	AccountCode string  `json:"account_code" binding:"required"`
+	FundID      *uint   `json:"fund_id"`
+	ProjectID   *uint   `json:"project_id"`
+	Debit       float64 `json:"debit"`
+	Credit      float64 `json:"credit"`
+	Memo        string  `json:"memo"`
+}
+
+// TrialBalanceLine 试算平衡表行，Balance 按科目正常余额方向为正
+type TrialBalanceLine struct {
+	repo.TrialBalanceRow
+	Balance float64 `json:"balance"`
+}
+
+// TrialBalanceReport 试算平衡表
+type TrialBalanceReport struct {
+	AsOf        *time.Time         `json:"as_of"`
+	Lines       []TrialBalanceLine `json:"lines"`
+	TotalDebit  float64            `json:"total_debit"`
+	TotalCredit float64            `json:"total_credit"`
+	Balanced    bool               `json:"balanced"`
+}
+
+// ActivityLine 科目明细行（含滚动余额）
+type ActivityLine struct {
+	repo.ActivityRow
+	Balance float64 `json:"balance"`
+}
+
+// AccountActivityReport 科目明细账
+type AccountActivityReport struct {
+	Account        *models.Account `json:"account"`
+	Start          *time.Time      `json:"start"`
+	End            *time.Time      `json:"end"`
+	OpeningBalance float64         `json:"opening_balance"`
+	Lines          []ActivityLine  `json:"lines"`
+	ClosingBalance float64         `json:"closing_balance"`
+}
+
+func (s *LedgerService) GetAccounts() ([]models.Account, error) {
+	return s.repo.GetAccounts()
+}
+
+func (s *LedgerService) CreateAccount(account *models.Account) error {
+	switch account.Type {
+	case models.AccountTypeAsset, models.AccountTypeLiability, models.AccountTypeNetAssets,
+		models.AccountTypeRevenue, models.AccountTypeExpense:
+	default:
+		return invalidInput("unknown account type %q", account.Type)
+	}
+	if account.Code == "" || account.Name == "" {
+		return invalidInput("code and name are required")
+	}
+	account.IsActive = true
+	return s.repo.CreateAccount(account)
+}
+
+func (s *LedgerService) GetEntries(sourceType string, sourceID uint, start, end *time.Time) ([]models.JournalEntry, error) {
+	return s.repo.GetEntries(sourceType, sourceID, start, end)
+}
+
+// PostManualEntry 过账一笔手工调整分录
+func (s *LedgerService) PostManualEntry(req *ManualEntryRequest) (*models.JournalEntry, error) {
+	date, err := time.Parse("2006-01-02", req.EntryDate)
+	if err != nil {
+		return nil, invalidInput("invalid entry_date %q", req.EntryDate)
+	}
+	lines := make([]ledgerLine, 0, len(req.Lines))
+	for _, l := range req.Lines {
+		lines = append(lines, ledgerLine{
+			account: l.AccountCode, fundID: l.FundID, projectID: l.ProjectID,
+			debit: l.Debit, credit: l.Credit, memo: l.Memo,
+		})
+	}
+
+	var entry *models.JournalEntry
+	err = s.store.Transaction(func(tx *repo.Tx) error {
+		entry, err = postJournal(tx, journal{
+			source: models.SourceManual, date: date, description: req.Description, lines: lines,
+		})
+		return err
+	})
+	return entry, err
+}
+
+// TrialBalance 生成截至 asOf 的试算平衡表
+func (s *LedgerService) TrialBalance(asOf *time.Time) (*TrialBalanceReport, error) {
+	rows, err := s.repo.TrialBalance(asOf)
+	if err != nil {
+		return nil, err
+	}
+	report := &TrialBalanceReport{AsOf: asOf, Lines: make([]TrialBalanceLine, 0, len(rows))}
+	for _, row := range rows {
+		account := models.Account{Type: row.Type}
+		report.Lines = append(report.Lines, TrialBalanceLine{
+			TrialBalanceRow: row,
+			Balance:         normalBalance(account, row.Debit, row.Credit),
+		})
+		report.TotalDebit += row.Debit
+		report.TotalCredit += row.Credit
+	}
+	report.TotalDebit = roundCents(report.TotalDebit)
+	report.TotalCredit = roundCents(report.TotalCredit)
+	report.Balanced = report.TotalDebit == report.TotalCredit
+	return report, nil
+}
+
+// AccountActivity 返回科目在期间内的明细，带期初、滚动与期末余额
+func (s *LedgerService) AccountActivity(accountID uint, start, end *time.Time) (*AccountActivityReport, error) {
+	account, err := s.repo.GetAccountByID(accountID)
+	if err != nil {
+		return nil, notFound(err, "account")
+	}
+	report := &AccountActivityReport{Account: account, Start: start, End: end}
+	if start != nil {
+		debit, credit, err := s.repo.AccountTotals(accountID, *start)
+		if err != nil {
+			return nil, err
+		}
+		report.OpeningBalance = normalBalance(*account, debit, credit)
+	}
+
+	rows, err := s.repo.AccountActivity(accountID, start, end)
+	if err != nil {
+		return nil, err
+	}
+	balance := report.OpeningBalance
+	report.Lines = make([]ActivityLine, 0, len(rows))
+	for _, row := range rows {
+		balance = roundCents(balance + normalBalance(*account, row.Debit, row.Credit))
+		report.Lines = append(report.Lines, ActivityLine{ActivityRow: row, Balance: balance})
+	}
+	report.ClosingBalance = balance
+	return report, nil
+}
+
+// ==================== Posting helpers ====================
+
+// ledgerLine 过账时按科目代码描述的分录行
+type ledgerLine struct {
+	account   string
+	fundID    *uint
+	projectID *uint
+	debit     float64
+	credit    float64
+	memo      string
+}
+
+// journal 一笔待过账的分录
+type journal struct {
+	source        string
+	sourceID      uint
+	transactionID *uint
+	date          time.Time
+	description   string
+	lines         []ledgerLine
+}
+
+// postJournal 校验借贷平衡后写入分录
+func postJournal(tx *repo.Tx, j journal) (*models.JournalEntry, error) {
+	if len(j.lines) < 2 {
+		return nil, invalidInput("journal entry needs at least two lines")
+	}
+	var debit, credit float64
+	entry := &models.JournalEntry{
+		TransactionID: j.transactionID,
+		SourceType:    j.source,
+		SourceID:      j.sourceID,
+		EntryDate:     j.date,
+		Description:   j.description,
+	}
+	for _, l := range j.lines {
+		if l.debit < 0 || l.credit < 0 || (l.debit != 0) == (l.credit != 0) {
+			return nil, invalidInput("each line must have exactly one positive debit or credit (account %s)", l.account)
+		}
+		account, err := tx.Ledger.GetAccountByCode(l.account)
+		if err != nil {
+			return nil, notFound(err, "account "+l.account)
+		}
+		if !account.IsActive {
+			return nil, invalidInput("account %s is inactive", l.account)
+		}
+		entry.Lines = append(entry.Lines, models.JournalLine{
+			AccountID: account.ID,
+			FundID:    l.fundID,
+			ProjectID: l.projectID,
+			Debit:     roundCents(l.debit),
+			Credit:    roundCents(l.credit),
+			Memo:      l.memo,
+		})
+		debit += l.debit
+		credit += l.credit
+	}
+	if roundCents(debit) != roundCents(credit) {
+		return nil, invalidInput("journal entry is not balanced: debit %.2f, credit %.2f", debit, credit)
+	}
+	if err := tx.Ledger.CreateEntry(entry); err != nil {
+		return nil, fmt.Errorf("failed to post journal entry: %w", err)
+	}
+	return entry, nil
+}
+
+// reverseJournal 为某业务单据当前有效的分录过一笔红字冲销分录（以原分录日期入账），没有则不做任何事
+func reverseJournal(tx *repo.Tx, source string, sourceID uint) error {
+	entry, err := tx.Ledger.ActiveEntry(source, sourceID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	reversal := &models.JournalEntry{
+		TransactionID: entry.TransactionID,
+		SourceType:    entry.SourceType,
+		SourceID:      entry.SourceID,
+		EntryDate:     entry.EntryDate,
+		Description:   "Reversal: " + entry.Description,
+		ReversalOfID:  &entry.ID,
+	}
+	for _, l := range entry.Lines {
+		reversal.Lines = append(reversal.Lines, models.JournalLine{
+			AccountID: l.AccountID,
+			FundID:    l.FundID,
+			ProjectID: l.ProjectID,
+			Debit:     l.Credit,
+			Credit:    l.Debit,
+			Memo:      l.Memo,
+		})
+	}
+	if err := tx.Ledger.CreateEntry(reversal); err != nil {
+		return fmt.Errorf("failed to reverse journal entry: %w", err)
+	}
+	return tx.Ledger.MarkReversed(entry.ID, reversal.ID)
+}
+
+// repostJournal 冲销原分录后按新内容重新过账
+func repostJournal(tx *repo.Tx, j journal) error {
+	if err := reverseJournal(tx, j.source, j.sourceID); err != nil {
+		return err
+	}
+	_, err := postJournal(tx, j)
+	return err
+}
+
+// normalBalance 按科目正常余额方向计算余额
+func normalBalance(account models.Account, debit, credit float64) float64 {
+	if account.DebitNormal() {
+		return roundCents(debit - credit)
+	}
+	return roundCents(credit - debit)
+}
+
+func roundCents(v float64) float64 {
+	return math.Round(v*100) / 100
+}
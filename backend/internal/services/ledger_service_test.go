@@ -0,0 +1,134 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"erp-backend/internal/models"
+	"erp-backend/internal/repo"
+)
+
+func newLedgerTestService(t *testing.T) (*LedgerService, *repo.Store) {
+	t.Helper()
+	db := openServiceTestDB(t)
+	store := repo.NewStore(db)
+	return NewLedgerService(repo.NewLedgerRepository(db), store), store
+}
+
+func TestPostJournalRejectsInvalidEntries(t *testing.T) {
+	svc, store := newLedgerTestService(t)
+	tests := []struct {
+		name  string
+		lines []ledgerLine
+	}{
+		{"unbalanced", []ledgerLine{
+			{account: models.AccountCash, debit: 100},
+			{account: models.AccountContributions, credit: 99.99},
+		}},
+		{"single line", []ledgerLine{
+			{account: models.AccountCash, debit: 100},
+		}},
+		{"debit and credit on one line", []ledgerLine{
+			{account: models.AccountCash, debit: 100, credit: 100},
+			{account: models.AccountContributions, credit: 0},
+		}},
+		{"negative amount", []ledgerLine{
+			{account: models.AccountCash, debit: -100},
+			{account: models.AccountContributions, credit: -100},
+		}},
+		{"unknown account", []ledgerLine{
+			{account: "9999", debit: 100},
+			{account: models.AccountContributions, credit: 100},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := store.Transaction(func(tx *repo.Tx) error {
+				_, err := postJournal(tx, journal{source: models.SourceManual, date: time.Now().UTC(), lines: tt.lines})
+				return err
+			})
+			if !errors.Is(err, ErrInvalidInput) && !errors.Is(err, ErrNotFound) {
+				t.Fatalf("postJournal = %v, want a validation error", err)
+			}
+		})
+	}
+
+	entries, err := svc.GetEntries("", 0, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("%d entries were written by rejected journals, want 0", len(entries))
+	}
+}
+
+func TestReverseJournalNetsToZero(t *testing.T) {
+	svc, store := newLedgerTestService(t)
+	date := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	err := store.Transaction(func(tx *repo.Tx) error {
+		_, err := postJournal(tx, journal{
+			source: models.SourceDonation, sourceID: 42, date: date, description: "Donation DON-42",
+			lines: []ledgerLine{
+				{account: models.AccountCash, debit: 125.50},
+				{account: models.AccountContributions, credit: 100},
+				{account: models.AccountContributionsRestr, credit: 25.50},
+			},
+		})
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Transaction(func(tx *repo.Tx) error {
+		return reverseJournal(tx, models.SourceDonation, 42)
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := svc.GetEntries(models.SourceDonation, 42, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("got %d entries, want the original and its reversal", len(entries))
+	}
+	original, reversal := entries[0], entries[1]
+	if original.ReversalOfID != nil {
+		original, reversal = reversal, original
+	}
+	if reversal.ReversalOfID == nil || *reversal.ReversalOfID != original.ID {
+		t.Errorf("reversal_of_id = %v, want %d", reversal.ReversalOfID, original.ID)
+	}
+	if original.ReversedByID == nil || *original.ReversedByID != reversal.ID {
+		t.Errorf("reversed_by_id = %v, want %d", original.ReversedByID, reversal.ID)
+	}
+	if !reversal.EntryDate.Equal(date) {
+		t.Errorf("reversal dated %s, want the original date %s", reversal.EntryDate, date)
+	}
+
+	report, err := svc.TrialBalance(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 原分录与冲销分录各记一次借贷
+	if !report.Balanced || report.TotalDebit != 251 {
+		t.Errorf("trial balance debit %.2f credit %.2f, want 251.00 on both sides", report.TotalDebit, report.TotalCredit)
+	}
+	for _, line := range report.Lines {
+		if line.Balance != 0 {
+			t.Errorf("account %s balance %.2f after reversal, want 0", line.Code, line.Balance)
+		}
+	}
+
+	// 已冲销的分录不再是有效分录，重复冲销不写入任何内容
+	if err := store.Transaction(func(tx *repo.Tx) error {
+		return reverseJournal(tx, models.SourceDonation, 42)
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if entries, _ := svc.GetEntries(models.SourceDonation, 42, nil, nil); len(entries) != 2 {
+		t.Errorf("second reversal wrote %d entries in total, want 2", len(entries))
+	}
+}
@@ -0,0 +1,186 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"erp-backend/internal/models"
+	"erp-backend/internal/repo"
+
+	"gorm.io/gorm"
+)
+
+// LockoutPolicy 登录防暴力破解阈值
+type LockoutPolicy struct {
+	MaxFailures   int           // 账号连续失败多少次后锁定
+	BaseLockout   time.Duration // 首次锁定时长，之后每次锁定翻倍
+	MaxLockout    time.Duration // 单次锁定时长上限
+	IPMaxFailures int           // 同一 IP 在 IPWindow 内允许的失败次数
+	IPWindow      time.Duration
+}
+
+// LockoutError 账号被锁定或来源 IP 被暂时阻止
+type LockoutError struct {
+	Reason     string // models.LoginAccountLocked 或 models.LoginIPBlocked
+	RetryAfter time.Duration
+}
+
+func (e *LockoutError) Error() string {
+	wait := e.RetryAfter.Round(time.Second)
+	if e.Reason == models.LoginIPBlocked {
+		return fmt.Sprintf("too many failed login attempts from this address; try again in %s", wait)
+	}
+	return fmt.Sprintf("account is temporarily locked after repeated failed logins; try again in %s", wait)
+}
+
+func (e *LockoutError) Unwrap() error { return ErrTooManyRequests }
+
+// LockoutService 记录每次登录尝试，按账号与来源 IP 统计失败次数并执行锁定
+type LockoutService struct {
+	repo   *repo.LoginAttemptRepository
+	store  *repo.Store
+	policy LockoutPolicy
+}
+
+func NewLockoutService(attemptRepo *repo.LoginAttemptRepository, store *repo.Store, policy LockoutPolicy) *LockoutService {
+	return &LockoutService{repo: attemptRepo, store: store, policy: policy}
+}
+
+// LoginAttemptInfo 一次登录请求的来源信息
+type LoginAttemptInfo struct {
+	Username  string
+	ClientIP  string
+	UserAgent string
+}
+
+// Check 在校验密码之前调用：来源 IP 失败过多或账号处于锁定期时返回 *LockoutError，并记录本次尝试
+func (s *LockoutService) Check(user *models.User, info LoginAttemptInfo) error {
+	now := time.Now().UTC()
+	if s.policy.IPMaxFailures > 0 && info.ClientIP != "" {
+		n, err := s.repo.CountFailuresByIP(info.ClientIP, now.Add(-s.policy.IPWindow))
+		if err != nil {
+			return err
+		}
+		if n >= int64(s.policy.IPMaxFailures) {
+			s.record(user, info, false, models.LoginIPBlocked)
+			return &LockoutError{Reason: models.LoginIPBlocked, RetryAfter: s.policy.IPWindow}
+		}
+	}
+	if user == nil {
+		return nil
+	}
+	lockout, err := s.repo.GetLockout(user.ID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if lockout.LockedUntil != nil && now.Before(*lockout.LockedUntil) {
+		s.record(user, info, false, models.LoginAccountLocked)
+		return &LockoutError{Reason: models.LoginAccountLocked, RetryAfter: lockout.LockedUntil.Sub(now)}
+	}
+	return nil
+}
+
+// RecordFailure 记录一次失败；user 为空表示用户名不存在，只计入 IP 统计。
+// 账号连续失败达到阈值时锁定，锁定时长为 BaseLockout × 2^(已锁定次数)，不超过 MaxLockout
+func (s *LockoutService) RecordFailure(user *models.User, info LoginAttemptInfo, reason string) error {
+	return s.store.Transaction(func(tx *repo.Tx) error {
+		if err := tx.LoginAttempts.Create(newAttempt(user, info, false, reason)); err != nil {
+			return err
+		}
+		if user == nil || s.policy.MaxFailures <= 0 {
+			return nil
+		}
+		lockout, err := tx.LoginAttempts.GetLockout(user.ID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			lockout, err = &models.AccountLockout{UserID: user.ID}, nil
+		}
+		if err != nil {
+			return err
+		}
+		lockout.FailedCount++
+		if lockout.FailedCount >= s.policy.MaxFailures {
+			until := time.Now().UTC().Add(s.lockoutDuration(lockout.LockCount))
+			lockout.LockedUntil = &until
+			lockout.LockCount++
+			lockout.FailedCount = 0
+			log.Printf("account %d (%s) locked until %s after repeated failed logins from %s",
+				user.ID, user.Username, until.Format(time.RFC3339), info.ClientIP)
+		}
+		return tx.LoginAttempts.SaveLockout(lockout)
+	})
+}
+
+// RecordSuccess 记录一次成功登录并清除账号的失败计数
+func (s *LockoutService) RecordSuccess(user *models.User, info LoginAttemptInfo) error {
+	return s.store.Transaction(func(tx *repo.Tx) error {
+		if err := tx.LoginAttempts.Create(newAttempt(user, info, true, "")); err != nil {
+			return err
+		}
+		_, err := tx.LoginAttempts.DeleteLockout(user.ID)
+		return err
+	})
+}
+
+// RecordRejected 记录一次密码正确但因其他原因（如账号未激活）被拒绝的登录，不计入失败次数
+func (s *LockoutService) RecordRejected(user *models.User, info LoginAttemptInfo, reason string) {
+	s.record(user, info, false, reason)
+}
+
+// Unlock 由管理员解除账号锁定并清零计数
+func (s *LockoutService) Unlock(userID uint) error {
+	ok, err := s.repo.DeleteLockout(userID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("%w: lockout for user %d", ErrNotFound, userID)
+	}
+	return nil
+}
+
+// LockedAccounts 返回当前处于锁定中的账号
+func (s *LockoutService) LockedAccounts() ([]models.AccountLockout, error) {
+	return s.repo.LockedAccounts(time.Now().UTC())
+}
+
+// Attempts 查询登录记录
+func (s *LockoutService) Attempts(filter repo.LoginAttemptFilter) ([]models.LoginAttempt, error) {
+	return s.repo.Search(filter)
+}
+
+func (s *LockoutService) lockoutDuration(previousLocks int) time.Duration {
+	d := s.policy.BaseLockout
+	for i := 0; i < previousLocks && d < s.policy.MaxLockout; i++ {
+		d *= 2
+	}
+	if s.policy.MaxLockout > 0 && d > s.policy.MaxLockout {
+		d = s.policy.MaxLockout
+	}
+	return d
+}
+
+// record 写入一条登录记录；写入失败只记录日志，不影响登录结果
+func (s *LockoutService) record(user *models.User, info LoginAttemptInfo, success bool, reason string) {
+	if err := s.repo.Create(newAttempt(user, info, success, reason)); err != nil {
+		log.Printf("failed to record login attempt for %q: %v", info.Username, err)
+	}
+}
+
+func newAttempt(user *models.User, info LoginAttemptInfo, success bool, reason string) *models.LoginAttempt {
+	attempt := &models.LoginAttempt{
+		Username:  truncate(info.Username, 100),
+		ClientIP:  truncate(info.ClientIP, 64),
+		UserAgent: truncate(info.UserAgent, 255),
+		Success:   success,
+		Reason:    reason,
+	}
+	if user != nil {
+		attempt.UserID = &user.ID
+	}
+	return attempt
+}
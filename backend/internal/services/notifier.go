@@ -0,0 +1,77 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"mime"
+	"net"
+	"net/smtp"
+	"os"
+	"strings"
+	"time"
+)
+
+// Notification 发给用户的一条通知
+type Notification struct {
+	UserID  uint
+	To      string // 收件地址（邮箱）
+	Subject string
+	Body    string
+}
+
+// Notifier 通知发送渠道；发送失败只记录日志，不影响已完成的业务操作
+type Notifier interface {
+	Notify(n Notification) error
+}
+
+// LogNotifier 将通知追加写入日志文件，未接入邮件等渠道时作为默认实现
+type LogNotifier struct {
+	logger *log.Logger
+}
+
+// NewLogNotifier 打开（或创建）通知日志文件
+func NewLogNotifier(path string) (*LogNotifier, error) {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0666)
+	if err != nil {
+		return nil, err
+	}
+	return &LogNotifier{logger: log.New(f, "", log.LstdFlags)}, nil
+}
+
+func (n *LogNotifier) Notify(msg Notification) error {
+	n.logger.Printf("to=%q user_id=%d subject=%q body=%q", msg.To, msg.UserID, msg.Subject, strings.TrimSpace(msg.Body))
+	return nil
+}
+
+// SMTPNotifier 通过 SMTP 发送纯文本邮件。未设置用户名时不做认证，
+// 便于在开发环境对接本地 SMTP 测试服务（如 MailHog、smtp4dev）
+type SMTPNotifier struct {
+	addr string
+	from string
+	auth smtp.Auth
+}
+
+func NewSMTPNotifier(host, port, username, password, from string) *SMTPNotifier {
+	n := &SMTPNotifier{addr: net.JoinHostPort(host, port), from: from}
+	if username != "" {
+		n.auth = smtp.PlainAuth("", username, password, host)
+	}
+	return n
+}
+
+func (n *SMTPNotifier) Notify(msg Notification) error {
+	if msg.To == "" {
+		return errors.New("recipient has no email address")
+	}
+	// 去掉头部字段中的换行，防止邮件头注入
+	header := strings.NewReplacer("\r", "", "\n", "")
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", header.Replace(n.from))
+	fmt.Fprintf(&b, "To: %s\r\n", header.Replace(msg.To))
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("UTF-8", header.Replace(msg.Subject)))
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\nContent-Type: text/plain; charset=UTF-8\r\n\r\n")
+	b.WriteString(strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+	return smtp.SendMail(n.addr, n.auth, n.from, []string{header.Replace(msg.To)}, []byte(b.String()))
+}
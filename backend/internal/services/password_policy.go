@@ -0,0 +1,109 @@
+package services
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+)
+
+// PasswordPolicy 密码策略：最小长度、必须包含的字符类别，以及泄露密码列表
+type PasswordPolicy struct {
+	MinLength     int
+	RequireUpper  bool
+	RequireLower  bool
+	RequireDigit  bool
+	RequireSymbol bool
+
+	breached map[string]struct{} // 泄露密码的 SHA-1（大写十六进制）
+}
+
+// LoadBreachedList 读取泄露密码列表文件。每行一个明文密码，或一个 SHA-1 哈希
+// （兼容 Have I Been Pwned 的 HASH:次数 格式）；空行与 # 开头的行忽略
+func (p *PasswordPolicy) LoadBreachedList(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	breached := map[string]struct{}{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if hash, _, _ := strings.Cut(line, ":"); isSHA1Hex(hash) {
+			breached[strings.ToUpper(hash)] = struct{}{}
+			continue
+		}
+		breached[sha1Hex(line)] = struct{}{}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	p.breached = breached
+	return nil
+}
+
+// Check 校验新密码，不满足策略时返回列出全部问题的 ErrInvalidInput
+func (p *PasswordPolicy) Check(password, username string) error {
+	var problems []string
+	if len([]rune(password)) < p.MinLength {
+		problems = append(problems, fmt.Sprintf("be at least %d characters long", p.MinLength))
+	}
+
+	var upper, lower, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r):
+			symbol = true
+		}
+	}
+	if p.RequireUpper && !upper {
+		problems = append(problems, "contain an uppercase letter")
+	}
+	if p.RequireLower && !lower {
+		problems = append(problems, "contain a lowercase letter")
+	}
+	if p.RequireDigit && !digit {
+		problems = append(problems, "contain a digit")
+	}
+	if p.RequireSymbol && !symbol {
+		problems = append(problems, "contain a symbol")
+	}
+	if username != "" && strings.EqualFold(password, username) {
+		problems = append(problems, "differ from the username")
+	}
+	if len(problems) > 0 {
+		return invalidInput("password must %s", strings.Join(problems, ", "))
+	}
+
+	if _, ok := p.breached[sha1Hex(password)]; ok {
+		return invalidInput("this password has appeared in a data breach; choose a different one")
+	}
+	return nil
+}
+
+func sha1Hex(s string) string {
+	sum := sha1.Sum([]byte(s))
+	return strings.ToUpper(hex.EncodeToString(sum[:]))
+}
+
+func isSHA1Hex(s string) bool {
+	if len(s) != 40 {
+		return false
+	}
+	_, err := hex.DecodeString(s)
+	return err == nil
+}
@@ -0,0 +1,196 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"erp-backend/internal/models"
+	"erp-backend/internal/repo"
+
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// PasswordService 修改密码与通过邮件重置密码；新密码须符合密码策略。
+// 密码变更后终止该用户的其他登录会话
+type PasswordService struct {
+	userRepo *repo.UserRepository
+	store    *repo.Store
+	sessions *SessionService
+	policy   *PasswordPolicy
+	mailer   Notifier
+	resetTTL time.Duration
+	resetURL string // 重置页面地址，邮件中附带 ?token=
+}
+
+func NewPasswordService(userRepo *repo.UserRepository, store *repo.Store, sessions *SessionService, policy *PasswordPolicy, mailer Notifier, resetTTL time.Duration, resetURL string) *PasswordService {
+	return &PasswordService{
+		userRepo: userRepo,
+		store:    store,
+		sessions: sessions,
+		policy:   policy,
+		mailer:   mailer,
+		resetTTL: resetTTL,
+		resetURL: resetURL,
+	}
+}
+
+// ChangePasswordRequest 修改密码请求
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required"`
+}
+
+// ForgotPasswordRequest 申请重置密码
+type ForgotPasswordRequest struct {
+	Username string `json:"username" binding:"required"`
+}
+
+// ResetPasswordRequest 使用重置令牌设置新密码
+type ResetPasswordRequest struct {
+	Token       string `json:"token" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required"`
+}
+
+// Validate 按密码策略校验新密码
+func (s *PasswordService) Validate(password, username string) error {
+	return s.policy.Check(password, username)
+}
+
+// Hash 校验并加密新密码
+func (s *PasswordService) Hash(password, username string) (string, error) {
+	if err := s.Validate(password, username); err != nil {
+		return "", err
+	}
+	hashed, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", errors.New("failed to hash password")
+	}
+	return string(hashed), nil
+}
+
+// Change 登录用户修改密码，须提供当前密码；保留当前会话，终止其他会话，未使用的重置令牌作废
+func (s *PasswordService) Change(userID uint, sessionID string, req *ChangePasswordRequest) error {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return notFound(err, "user")
+	}
+	if bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.CurrentPassword)) != nil {
+		return invalidInput("current password is incorrect")
+	}
+	if req.NewPassword == req.CurrentPassword {
+		return invalidInput("new password must differ from the current password")
+	}
+	hashed, err := s.Hash(req.NewPassword, user.Username)
+	if err != nil {
+		return err
+	}
+	// 修改密码后，此前签发的重置令牌一并作废
+	err = s.store.Transaction(func(tx *repo.Tx) error {
+		if err := tx.Users.UpdatePassword(user.ID, hashed); err != nil {
+			return err
+		}
+		return tx.PasswordResets.InvalidateUserTokens(user.ID, time.Now().UTC())
+	})
+	if err != nil {
+		return err
+	}
+	if _, err := s.sessions.RevokeOtherSessions(user.ID, sessionID, models.SessionRevokedPasswordChange); err != nil {
+		log.Printf("failed to revoke sessions of user %d after password change: %v", user.ID, err)
+	}
+	return nil
+}
+
+// RequestReset 为账号签发重置令牌并发送邮件，此前未使用的令牌作废。
+// 账号不存在、未激活或没有邮箱时静默返回，调用方无法据此判断账号是否存在
+func (s *PasswordService) RequestReset(req *ForgotPasswordRequest) error {
+	users, err := s.userRepo.Search(map[string]interface{}{"username": strings.TrimSpace(req.Username)})
+	if err != nil {
+		return err
+	}
+	if len(users) != 1 || users[0].Status != "active" {
+		return nil
+	}
+	user := users[0]
+	email, err := s.userRepo.ContactEmail(&user)
+	if err != nil {
+		return err
+	}
+	if email == "" {
+		log.Printf("password reset requested for user %d, but no email address is on file", user.ID)
+		return nil
+	}
+
+	raw, err := randomToken(32)
+	if err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	err = s.store.Transaction(func(tx *repo.Tx) error {
+		if err := tx.PasswordResets.InvalidateUserTokens(user.ID, now); err != nil {
+			return err
+		}
+		return tx.PasswordResets.Create(&models.PasswordResetToken{
+			UserID:    user.ID,
+			TokenHash: hashToken(raw),
+			ExpiresAt: now.Add(s.resetTTL),
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	body := fmt.Sprintf("Hello %s,\n\nA password reset was requested for your account. "+
+		"Open the link below within %s to choose a new password:\n\n%s?token=%s\n\n"+
+		"If you did not request this, you can ignore this email.",
+		user.Username, s.resetTTL, s.resetURL, raw)
+	if err := s.mailer.Notify(Notification{UserID: user.ID, To: email, Subject: "Password reset", Body: body}); err != nil {
+		log.Printf("failed to send password reset email to user %d: %v", user.ID, err)
+	}
+	return nil
+}
+
+// Reset 使用重置令牌设置新密码；令牌只能使用一次，成功后终止该用户的全部会话
+func (s *PasswordService) Reset(req *ResetPasswordRequest) error {
+	var userID uint
+	err := s.store.Transaction(func(tx *repo.Tx) error {
+		token, err := tx.PasswordResets.GetByHash(hashToken(req.Token))
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return invalidInput("invalid or expired reset token")
+		}
+		if err != nil {
+			return err
+		}
+		now := time.Now().UTC()
+		if token.UsedAt != nil || now.After(token.ExpiresAt) {
+			return invalidInput("invalid or expired reset token")
+		}
+		user, err := tx.Users.GetByID(token.UserID)
+		if err != nil {
+			return notFound(err, "user")
+		}
+		hashed, err := s.Hash(req.NewPassword, user.Username)
+		if err != nil {
+			return err
+		}
+		ok, err := tx.PasswordResets.MarkUsed(token.ID, now)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return invalidInput("invalid or expired reset token")
+		}
+		userID = user.ID
+		return tx.Users.UpdatePassword(user.ID, hashed)
+	})
+	if err != nil {
+		return err
+	}
+	if _, err := s.sessions.RevokeUserSessions(userID, models.SessionRevokedPasswordReset); err != nil {
+		log.Printf("failed to revoke sessions of user %d after password reset: %v", userID, err)
+	}
+	return nil
+}
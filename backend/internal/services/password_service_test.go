@@ -0,0 +1,157 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"erp-backend/internal/models"
+	"erp-backend/internal/repo"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// recordingNotifier 记录发出的通知，代替邮件渠道
+type recordingNotifier struct {
+	sent []Notification
+}
+
+func (n *recordingNotifier) Notify(msg Notification) error {
+	n.sent = append(n.sent, msg)
+	return nil
+}
+
+// lastToken 从最近一封重置邮件中取出令牌明文
+func (n *recordingNotifier) lastToken(t *testing.T) string {
+	t.Helper()
+	if len(n.sent) == 0 {
+		t.Fatal("no password reset email was sent")
+	}
+	_, rest, ok := strings.Cut(n.sent[len(n.sent)-1].Body, "?token=")
+	if !ok {
+		t.Fatalf("reset email has no token link: %q", n.sent[len(n.sent)-1].Body)
+	}
+	token, _, _ := strings.Cut(rest, "\n")
+	return token
+}
+
+const testPassword = "Original-pass1"
+
+// newPasswordTestService 创建一个带邮箱的员工账号，以及使用 recordingNotifier 的 PasswordService
+func newPasswordTestService(t *testing.T, resetTTL time.Duration) (*PasswordService, *recordingNotifier, *models.User) {
+	t.Helper()
+	db := openServiceTestDB(t)
+	hashed, err := bcrypt.GenerateFromPassword([]byte(testPassword), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	user := &models.User{Username: "alice", PasswordHash: string(hashed), UserType: "employee", Status: "active"}
+	if err := db.Create(user).Error; err != nil {
+		t.Fatal(err)
+	}
+	employee := &models.Employee{UserID: &user.ID, EmployeeID: "EMP-1", FirstName: "Alice", LastName: "Smith",
+		Email: "alice@example.org", HireDate: time.Now().UTC()}
+	if err := db.Create(employee).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	store := repo.NewStore(db)
+	sessions := NewSessionService(repo.NewSessionRepository(db), store, time.Hour)
+	mailer := &recordingNotifier{}
+	svc := NewPasswordService(repo.NewUserRepository(db), store, sessions, &PasswordPolicy{MinLength: 8},
+		mailer, resetTTL, "https://erp.example.org/reset-password")
+	return svc, mailer, user
+}
+
+func requireInvalidToken(t *testing.T, err error, what string) {
+	t.Helper()
+	if !errors.Is(err, ErrInvalidInput) {
+		t.Fatalf("%s: Reset = %v, want ErrInvalidInput", what, err)
+	}
+}
+
+func TestPasswordResetTokenIsSingleUse(t *testing.T) {
+	svc, mailer, user := newPasswordTestService(t, time.Hour)
+
+	if err := svc.RequestReset(&ForgotPasswordRequest{Username: "alice"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(mailer.sent) != 1 {
+		t.Fatalf("sent %d notifications, want 1", len(mailer.sent))
+	}
+	if msg := mailer.sent[0]; msg.UserID != user.ID || msg.To != "alice@example.org" {
+		t.Errorf("reset email sent to user %d <%s>, want user %d <alice@example.org>", msg.UserID, msg.To, user.ID)
+	}
+	token := mailer.lastToken(t)
+
+	if err := svc.Reset(&ResetPasswordRequest{Token: token, NewPassword: "Replaced-pass2"}); err != nil {
+		t.Fatalf("Reset with a fresh token: %v", err)
+	}
+	stored, err := svc.userRepo.GetByID(user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bcrypt.CompareHashAndPassword([]byte(stored.PasswordHash), []byte("Replaced-pass2")) != nil {
+		t.Error("Reset did not store the new password")
+	}
+
+	err = svc.Reset(&ResetPasswordRequest{Token: token, NewPassword: "Another-pass3"})
+	requireInvalidToken(t, err, "second use")
+	requireInvalidToken(t, svc.Reset(&ResetPasswordRequest{Token: "not-a-token", NewPassword: "Another-pass3"}), "unknown token")
+}
+
+func TestPasswordResetIssuingInvalidatesEarlierTokens(t *testing.T) {
+	svc, mailer, _ := newPasswordTestService(t, time.Hour)
+
+	if err := svc.RequestReset(&ForgotPasswordRequest{Username: "alice"}); err != nil {
+		t.Fatal(err)
+	}
+	first := mailer.lastToken(t)
+	if err := svc.RequestReset(&ForgotPasswordRequest{Username: "alice"}); err != nil {
+		t.Fatal(err)
+	}
+	second := mailer.lastToken(t)
+	if first == second {
+		t.Fatal("RequestReset issued the same token twice")
+	}
+
+	requireInvalidToken(t, svc.Reset(&ResetPasswordRequest{Token: first, NewPassword: "Replaced-pass2"}), "superseded token")
+	if err := svc.Reset(&ResetPasswordRequest{Token: second, NewPassword: "Replaced-pass2"}); err != nil {
+		t.Fatalf("Reset with the latest token: %v", err)
+	}
+}
+
+func TestPasswordResetTokenExpires(t *testing.T) {
+	// 有效期为负，签发的令牌立即过期
+	svc, mailer, _ := newPasswordTestService(t, -time.Minute)
+
+	if err := svc.RequestReset(&ForgotPasswordRequest{Username: "alice"}); err != nil {
+		t.Fatal(err)
+	}
+	requireInvalidToken(t, svc.Reset(&ResetPasswordRequest{Token: mailer.lastToken(t), NewPassword: "Replaced-pass2"}), "expired token")
+}
+
+func TestPasswordChangeInvalidatesResetTokens(t *testing.T) {
+	svc, mailer, user := newPasswordTestService(t, time.Hour)
+
+	if err := svc.RequestReset(&ForgotPasswordRequest{Username: "alice"}); err != nil {
+		t.Fatal(err)
+	}
+	token := mailer.lastToken(t)
+	if err := svc.Change(user.ID, "", &ChangePasswordRequest{CurrentPassword: testPassword, NewPassword: "Changed-pass2"}); err != nil {
+		t.Fatal(err)
+	}
+	requireInvalidToken(t, svc.Reset(&ResetPasswordRequest{Token: token, NewPassword: "Replaced-pass3"}), "token issued before a password change")
+}
+
+func TestPasswordResetIsSilentForUnknownUsers(t *testing.T) {
+	svc, mailer, _ := newPasswordTestService(t, time.Hour)
+
+	if err := svc.RequestReset(&ForgotPasswordRequest{Username: "nobody"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(mailer.sent) != 0 {
+		t.Errorf("sent %d notifications for an unknown username, want 0", len(mailer.sent))
+	}
+}
@@ -0,0 +1,271 @@
+package services
+
+import (
+	"strings"
+	"sync"
+
+	"erp-backend/internal/models"
+	"erp-backend/internal/repo"
+)
+
+// RBACService 角色与权限管理，并为鉴权中间件提供权限判断。
+// 角色→权限映射缓存在内存中，角色或权限变更后立即失效重载。
+type RBACService struct {
+	repo *repo.RBACRepository
+
+	mu    sync.RWMutex
+	cache map[string]map[string]bool // role name → permission codes
+}
+
+func NewRBACService(rbacRepo *repo.RBACRepository) *RBACService {
+	return &RBACService{repo: rbacRepo}
+}
+
+// RoleRequest 新建或修改角色的请求
+type RoleRequest struct {
+	Name        string   `json:"name" binding:"required"`
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions"`
+}
+
+// RoleTwoFactorRequest 设置角色是否强制双因素认证
+type RoleTwoFactorRequest struct {
+	Required *bool `json:"required" binding:"required"`
+}
+
+// UserRolesRequest 为用户分配角色的请求
+type UserRolesRequest struct {
+	Roles []string `json:"roles"`
+}
+
+// HasPermission 判断角色集合中是否有任一角色拥有 resource:action 权限（支持 * 通配）
+func (s *RBACService) HasPermission(roles []string, resource, action string) bool {
+	cache, err := s.permissionCache()
+	if err != nil {
+		return false
+	}
+	candidates := []string{
+		resource + ":" + action,
+		resource + ":" + models.PermissionWildcard,
+		models.PermissionWildcard + ":" + action,
+		models.PermissionWildcard + ":" + models.PermissionWildcard,
+	}
+	for _, role := range roles {
+		perms := cache[role]
+		for _, code := range candidates {
+			if perms[code] {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (s *RBACService) permissionCache() (map[string]map[string]bool, error) {
+	s.mu.RLock()
+	cache := s.cache
+	s.mu.RUnlock()
+	if cache != nil {
+		return cache, nil
+	}
+
+	roles, err := s.repo.GetRoles()
+	if err != nil {
+		return nil, err
+	}
+	cache = make(map[string]map[string]bool, len(roles))
+	for _, role := range roles {
+		perms := make(map[string]bool, len(role.Permissions))
+		for _, p := range role.Permissions {
+			perms[p.Code()] = true
+		}
+		cache[role.Name] = perms
+	}
+	s.mu.Lock()
+	s.cache = cache
+	s.mu.Unlock()
+	return cache, nil
+}
+
+func (s *RBACService) invalidate() {
+	s.mu.Lock()
+	s.cache = nil
+	s.mu.Unlock()
+}
+
+// UserRoleNames 返回用户的角色名，用于写入 JWT
+func (s *RBACService) UserRoleNames(userID uint) ([]string, error) {
+	roles, err := s.repo.GetUserRoles(userID)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(roles))
+	for _, r := range roles {
+		names = append(names, r.Name)
+	}
+	return names, nil
+}
+
+// EnsureAdmins 确保配置中列出的用户名拥有 admin 角色（ADMIN_USERS，逗号分隔）
+func (s *RBACService) EnsureAdmins(userRepo *repo.UserRepository, usernames []string) error {
+	admin, err := s.repo.GetRoleByName(models.RoleAdmin)
+	if err != nil {
+		return err
+	}
+	for _, name := range usernames {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		users, err := userRepo.Search(map[string]interface{}{"username": name})
+		if err != nil || len(users) != 1 {
+			continue
+		}
+		if err := s.repo.AddUserRole(users[0].ID, admin.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *RBACService) GetRoles() ([]models.Role, error) {
+	return s.repo.GetRoles()
+}
+
+func (s *RBACService) GetPermissions() ([]models.Permission, error) {
+	return s.repo.GetPermissions()
+}
+
+func (s *RBACService) CreateRole(req *RoleRequest) (*models.Role, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, invalidInput("name is required")
+	}
+	if _, err := s.repo.GetRoleByName(name); err == nil {
+		return nil, conflict("role %q already exists", name)
+	}
+	perms, err := s.permissions(req.Permissions)
+	if err != nil {
+		return nil, err
+	}
+	role := &models.Role{Name: name, Description: req.Description, Permissions: perms}
+	if err := s.repo.CreateRole(role); err != nil {
+		return nil, err
+	}
+	s.invalidate()
+	return role, nil
+}
+
+// UpdateRole 修改角色说明并整体替换权限；内置角色不能改名
+func (s *RBACService) UpdateRole(id uint, req *RoleRequest) (*models.Role, error) {
+	role, err := s.repo.GetRoleByID(id)
+	if err != nil {
+		return nil, notFound(err, "role")
+	}
+	name := strings.TrimSpace(req.Name)
+	if role.IsSystem && name != role.Name {
+		return nil, conflict("built-in role %q cannot be renamed", role.Name)
+	}
+	if role.Name == models.RoleAdmin {
+		return nil, conflict("the %q role always has full access and cannot be changed", models.RoleAdmin)
+	}
+	perms, err := s.permissions(req.Permissions)
+	if err != nil {
+		return nil, err
+	}
+	role.Name = name
+	role.Description = req.Description
+	if err := s.repo.UpdateRole(role, perms); err != nil {
+		return nil, err
+	}
+	role.Permissions = perms
+	s.invalidate()
+	return role, nil
+}
+
+// SetRoleTwoFactor 设置角色成员登录时是否必须使用双因素认证（admin 角色同样适用）
+func (s *RBACService) SetRoleTwoFactor(id uint, required bool) (*models.Role, error) {
+	role, err := s.repo.GetRoleByID(id)
+	if err != nil {
+		return nil, notFound(err, "role")
+	}
+	if err := s.repo.SetRoleTwoFactor(id, required); err != nil {
+		return nil, err
+	}
+	role.RequireTwoFactor = required
+	return role, nil
+}
+
+// RequiresTwoFactor 判断角色集合中是否有任一角色强制双因素认证
+func (s *RBACService) RequiresTwoFactor(roles []string) (bool, error) {
+	if len(roles) == 0 {
+		return false, nil
+	}
+	list, err := s.repo.GetRolesByNames(roles)
+	if err != nil {
+		return false, err
+	}
+	for _, r := range list {
+		if r.RequireTwoFactor {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// DeleteRole 删除自定义角色；内置角色不能删除
+func (s *RBACService) DeleteRole(id uint) error {
+	role, err := s.repo.GetRoleByID(id)
+	if err != nil {
+		return notFound(err, "role")
+	}
+	if role.IsSystem {
+		return conflict("built-in role %q cannot be deleted", role.Name)
+	}
+	if err := s.repo.DeleteRole(id); err != nil {
+		return err
+	}
+	s.invalidate()
+	return nil
+}
+
+func (s *RBACService) GetUserRoles(userID uint) ([]models.Role, error) {
+	return s.repo.GetUserRoles(userID)
+}
+
+// SetUserRoles 整体替换用户的角色；用户需重新登录后新角色才写入令牌
+func (s *RBACService) SetUserRoles(userID uint, names []string) ([]models.Role, error) {
+	roles, err := s.repo.GetRolesByNames(names)
+	if err != nil {
+		return nil, err
+	}
+	if len(roles) != len(names) {
+		known := make(map[string]bool, len(roles))
+		for _, r := range roles {
+			known[r.Name] = true
+		}
+		for _, n := range names {
+			if !known[n] {
+				return nil, invalidInput("unknown role %q", n)
+			}
+		}
+	}
+	ids := make([]uint, 0, len(roles))
+	for _, r := range roles {
+		ids = append(ids, r.ID)
+	}
+	if err := s.repo.SetUserRoles(userID, ids); err != nil {
+		return nil, err
+	}
+	return roles, nil
+}
+
+// permissions 校验权限代码并返回对应记录
+func (s *RBACService) permissions(codes []string) ([]models.Permission, error) {
+	for _, code := range codes {
+		if _, _, ok := models.ParsePermissionCode(code); !ok {
+			return nil, invalidInput("invalid permission %q, expected resource:action", code)
+		}
+	}
+	return s.repo.EnsurePermissions(codes)
+}
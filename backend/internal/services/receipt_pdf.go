@@ -0,0 +1,195 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"erp-backend/internal/models"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+// receiptDate 收据上的日期格式
+const receiptDate = "2006-01-02"
+
+// renderReceipt 按收据开具时保存的内容生成 A4 PDF：机构与捐赠者信息、捐款明细、实物捐赠明细与合计。
+// 使用 PDF 内置字体，文本按 cp1252 编码，超出该字符集的字符无法显示；已作废的收据带有 VOID 水印
+func renderReceipt(receipt *models.TaxReceipt) ([]byte, error) {
+	var content models.ReceiptContent
+	if err := json.Unmarshal([]byte(receipt.Content), &content); err != nil {
+		return nil, fmt.Errorf("receipt %s: %w", receipt.ReceiptNumber, err)
+	}
+	org := content.Organisation
+
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetTitle("Donation receipt "+receipt.ReceiptNumber, true)
+	pdf.SetAuthor(org.Name, true)
+	pdf.SetMargins(20, 20, 20)
+	pdf.SetAutoPageBreak(true, 20)
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+	pdf.AddPage()
+	width, _ := pdf.GetPageSize()
+	width -= 40
+
+	// 机构信息
+	pdf.SetFont("Helvetica", "B", 16)
+	pdf.CellFormat(width, 8, tr(org.Name), "", 1, "L", false, 0, "")
+	pdf.SetFont("Helvetica", "", 10)
+	for _, line := range []string{org.Address, org.Contact} {
+		if line != "" {
+			pdf.MultiCell(width, 5, tr(line), "", "L", false)
+		}
+	}
+	if org.TaxID != "" {
+		pdf.CellFormat(width, 5, tr("Registration number: "+org.TaxID), "", 1, "L", false, 0, "")
+	}
+	pdf.Ln(6)
+
+	pdf.SetFont("Helvetica", "B", 14)
+	pdf.CellFormat(width, 8, fmt.Sprintf("Official Donation Receipt for %d", receipt.Year), "", 1, "L", false, 0, "")
+	pdf.SetFont("Helvetica", "", 10)
+	field := func(label, value string) {
+		pdf.SetFont("Helvetica", "B", 10)
+		pdf.CellFormat(40, 6, label, "", 0, "L", false, 0, "")
+		pdf.SetFont("Helvetica", "", 10)
+		pdf.MultiCell(width-40, 6, tr(value), "", "L", false)
+	}
+	field("Receipt number", receipt.ReceiptNumber)
+	field("Date issued", receipt.IssuedAt.Format(receiptDate))
+	if receipt.ReplacesID != nil {
+		field("Note", "This receipt replaces a previously issued receipt, which is void.")
+	}
+	pdf.Ln(2)
+	field("Donor", content.DonorName)
+	field("Donor ID", content.DonorCode)
+	if content.DonorAddress != "" {
+		field("Address", content.DonorAddress)
+	}
+	pdf.Ln(4)
+
+	// 捐款明细
+	if len(content.Gifts) > 0 {
+		cols := []float64{28, 32, 30, width - 120, 30}
+		receiptTable(pdf, "Monetary donations", cols, []string{"Date", "Reference", "Method", "Project", "Amount"})
+		for _, g := range content.Gifts {
+			receiptRow(pdf, tr, cols, []string{g.Date.Format(receiptDate), g.DonationID, g.Method, g.Project, money(g.Amount)})
+		}
+		receiptTotal(pdf, cols, "Total monetary donations", receipt.CashTotal)
+	}
+
+	// 实物捐赠明细
+	if len(content.Items) > 0 {
+		cols := []float64{28, width - 88, 25, 35}
+		receiptTable(pdf, "In-kind donations", cols, []string{"Date", "Item", "Quantity", "Estimated value"})
+		for _, it := range content.Items {
+			date := ""
+			if it.Date != nil {
+				date = it.Date.Format(receiptDate)
+			}
+			receiptRow(pdf, tr, cols, []string{date, it.Name, strconv.Itoa(it.Quantity), money(it.Value)})
+		}
+		receiptTotal(pdf, cols, "Total in-kind donations", receipt.InKindTotal)
+	}
+
+	pdf.SetFont("Helvetica", "B", 11)
+	pdf.CellFormat(width-35, 7, "Total eligible amount", "T", 0, "R", false, 0, "")
+	pdf.CellFormat(35, 7, money(receipt.CashTotal+receipt.InKindTotal), "T", 1, "R", false, 0, "")
+	pdf.Ln(6)
+
+	pdf.SetFont("Helvetica", "", 9)
+	pdf.MultiCell(width, 5, "No goods or services were provided in exchange for these donations. In-kind donations are "+
+		"listed at their estimated fair market value on the date received. Please keep this receipt for your tax records.",
+		"", "L", false)
+	pdf.Ln(12)
+	pdf.CellFormat(70, 5, "", "B", 1, "L", false, 0, "")
+	signatory := org.Signatory
+	if signatory == "" {
+		signatory = "Authorised signatory"
+	}
+	pdf.CellFormat(70, 5, tr(signatory), "", 1, "L", false, 0, "")
+
+	if receipt.Status == models.ReceiptStatusVoid {
+		pdf.SetAutoPageBreak(false, 0)
+		pdf.SetFont("Helvetica", "B", 90)
+		pdf.SetTextColor(200, 30, 30)
+		pdf.SetAlpha(0.25, "Normal")
+		pageWidth, pageHeight := pdf.GetPageSize()
+		textWidth := pdf.GetStringWidth("VOID")
+		for page := 1; page <= pdf.PageCount(); page++ {
+			pdf.SetPage(page)
+			pdf.TransformBegin()
+			pdf.TransformRotate(35, pageWidth/2, pageHeight/2)
+			pdf.Text((pageWidth-textWidth)/2, pageHeight/2+12, "VOID")
+			pdf.TransformEnd()
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// receiptTable 输出明细表的标题与表头
+func receiptTable(pdf *gofpdf.Fpdf, title string, cols []float64, header []string) {
+	pdf.SetFont("Helvetica", "B", 11)
+	pdf.CellFormat(0, 7, title, "", 1, "L", false, 0, "")
+	pdf.SetFont("Helvetica", "B", 9)
+	pdf.SetFillColor(235, 235, 235)
+	for i, h := range header {
+		pdf.CellFormat(cols[i], 6, h, "B", 0, cellAlign(i, len(cols)), true, 0, "")
+	}
+	pdf.Ln(-1)
+	pdf.SetFont("Helvetica", "", 9)
+}
+
+func receiptRow(pdf *gofpdf.Fpdf, tr func(string) string, cols []float64, cells []string) {
+	for i, text := range cells {
+		// 过长的文本截断到列宽内
+		text = tr(text)
+		for len(text) > 0 && pdf.GetStringWidth(text) > cols[i]-2 {
+			text = text[:len(text)-1]
+		}
+		pdf.CellFormat(cols[i], 6, text, "", 0, cellAlign(i, len(cols)), false, 0, "")
+	}
+	pdf.Ln(-1)
+}
+
+func receiptTotal(pdf *gofpdf.Fpdf, cols []float64, label string, total float64) {
+	var rest float64
+	for _, w := range cols[:len(cols)-1] {
+		rest += w
+	}
+	pdf.SetFont("Helvetica", "B", 9)
+	pdf.CellFormat(rest, 6, label, "T", 0, "R", false, 0, "")
+	pdf.CellFormat(cols[len(cols)-1], 6, money(total), "T", 1, "R", false, 0, "")
+	pdf.Ln(4)
+}
+
+// cellAlign 最后一列（金额）右对齐
+func cellAlign(i, n int) string {
+	if i == n-1 {
+		return "R"
+	}
+	return "L"
+}
+
+// money 金额保留两位小数并按千位分组
+func money(v float64) string {
+	s := strconv.FormatFloat(v, 'f', 2, 64)
+	neg := s[0] == '-'
+	if neg {
+		s = s[1:]
+	}
+	whole, frac := s[:len(s)-3], s[len(s)-3:]
+	for i := len(whole) - 3; i > 0; i -= 3 {
+		whole = whole[:i] + "," + whole[i:]
+	}
+	if neg {
+		return "-" + whole + frac
+	}
+	return whole + frac
+}
@@ -0,0 +1,257 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"erp-backend/internal/models"
+	"erp-backend/internal/repo"
+
+	"gorm.io/gorm"
+)
+
+// ReceiptService 开具、作废与重开年度捐赠收据，并输出 PDF
+type ReceiptService struct {
+	repo  *repo.ReceiptRepository
+	store *repo.Store
+	org   models.ReceiptOrganisation
+}
+
+func NewReceiptService(receiptRepo *repo.ReceiptRepository, store *repo.Store, org models.ReceiptOrganisation) *ReceiptService {
+	return &ReceiptService{repo: receiptRepo, store: store, org: org}
+}
+
+func (s *ReceiptService) WithContext(ctx context.Context) *ReceiptService {
+	bound := *s
+	bound.repo = s.repo.WithContext(ctx)
+	bound.store = s.store.WithContext(ctx)
+	return &bound
+}
+
+// ReceiptFailure 批量开具时某位捐赠者的失败原因
+type ReceiptFailure struct {
+	DonorID uint   `json:"donor_id"`
+	Error   string `json:"error"`
+}
+
+// ReceiptBatchResult 批量开具的结果
+type ReceiptBatchResult struct {
+	Year     int              `json:"year"`
+	Issued   []string         `json:"issued"`   // 新开具的收据号
+	Existing int              `json:"existing"` // 已有有效收据、未重复开具的捐赠者数
+	Failed   []ReceiptFailure `json:"failed"`
+}
+
+// receiptYear 校验收据年份并返回该年的起止时间 [start, end)。年度结束后才能开具当年的收据
+func receiptYear(year int) (start, end time.Time, err error) {
+	if year < 1900 {
+		return start, end, invalidInput("invalid year %d", year)
+	}
+	if year >= time.Now().Year() {
+		return start, end, invalidInput("receipts for %d can be issued after the year ends", year)
+	}
+	start = time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC)
+	return start, start.AddDate(1, 0, 0), nil
+}
+
+// Issue 返回捐赠者某年的有效收据，没有时按当前的捐赠记录开具；当年没有捐赠时返回 ErrNotFound
+func (s *ReceiptService) Issue(donorID uint, year int, by *uint) (*models.TaxReceipt, error) {
+	receipt, _, err := s.issueFor(donorID, year, by)
+	return receipt, err
+}
+
+// issueFor 同 Issue，created 表示收据是本次新开具的
+func (s *ReceiptService) issueFor(donorID uint, year int, by *uint) (receipt *models.TaxReceipt, created bool, err error) {
+	if _, _, err := receiptYear(year); err != nil {
+		return nil, false, err
+	}
+	err = s.store.Transaction(func(tx *repo.Tx) error {
+		// 锁定捐赠者，同一捐赠者的并发请求不会开出两张有效收据
+		donor, err := tx.Donors.GetByID(donorID)
+		if err != nil {
+			return notFound(err, "donor")
+		}
+		current, err := tx.Receipts.Current(donorID, year)
+		if err == nil {
+			receipt = current
+			return nil
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		receipt, err = s.issue(tx, donor, year, by, nil)
+		created = err == nil
+		return err
+	})
+	return receipt, created, err
+}
+
+// issue 按当前的捐赠记录开具一张新收据，replaces 为被替换的收据
+func (s *ReceiptService) issue(tx *repo.Tx, donor *models.Donor, year int, by, replaces *uint) (*models.TaxReceipt, error) {
+	start, end, err := receiptYear(year)
+	if err != nil {
+		return nil, err
+	}
+	gifts, err := tx.Receipts.Gifts(donor.ID, start, end)
+	if err != nil {
+		return nil, err
+	}
+	items, err := tx.Receipts.Items(donor.ID, start, end)
+	if err != nil {
+		return nil, err
+	}
+	if len(gifts) == 0 && len(items) == 0 {
+		return nil, fmt.Errorf("%w: no donations from donor %s in %d", ErrNotFound, donor.DonorID, year)
+	}
+	content := models.ReceiptContent{
+		Organisation: s.org,
+		DonorCode:    donor.DonorID,
+		DonorName:    strings.TrimSpace(donor.FirstName + " " + donor.LastName),
+		DonorAddress: donor.Address,
+		Gifts:        gifts,
+		Items:        items,
+	}
+	raw, err := json.Marshal(content)
+	if err != nil {
+		return nil, err
+	}
+	seq, err := tx.Receipts.NextNumber(year)
+	if err != nil {
+		return nil, err
+	}
+	receipt := &models.TaxReceipt{
+		ReceiptNumber: fmt.Sprintf("%d-%06d", year, seq),
+		Year:          year,
+		DonorID:       donor.ID,
+		Status:        models.ReceiptStatusIssued,
+		Content:       models.JSONText(raw),
+		ReplacesID:    replaces,
+		IssuedBy:      by,
+		IssuedAt:      time.Now(),
+	}
+	for _, g := range gifts {
+		receipt.CashTotal += g.Amount
+	}
+	for _, it := range items {
+		receipt.InKindTotal += it.Value
+	}
+	receipt.CashTotal = math.Round(receipt.CashTotal*100) / 100
+	receipt.InKindTotal = math.Round(receipt.InKindTotal*100) / 100
+	if err := tx.Receipts.Create(receipt); err != nil {
+		return nil, err
+	}
+	return receipt, nil
+}
+
+// Generate 为当年有捐款或实物捐赠的每位捐赠者开具收据；已有有效收据的不重复开具。
+// 每位捐赠者在各自的事务中开具，个别失败不影响其他人
+func (s *ReceiptService) Generate(year int, by *uint) (*ReceiptBatchResult, error) {
+	start, end, err := receiptYear(year)
+	if err != nil {
+		return nil, err
+	}
+	donors, err := s.repo.Donors(start, end)
+	if err != nil {
+		return nil, err
+	}
+	result := &ReceiptBatchResult{Year: year, Issued: []string{}, Failed: []ReceiptFailure{}}
+	for _, donorID := range donors {
+		receipt, created, err := s.issueFor(donorID, year, by)
+		switch {
+		case err != nil:
+			result.Failed = append(result.Failed, ReceiptFailure{DonorID: donorID, Error: err.Error()})
+		case created:
+			result.Issued = append(result.Issued, receipt.ReceiptNumber)
+		default:
+			result.Existing++
+		}
+	}
+	return result, nil
+}
+
+// Void 作废有效收据，需注明原因
+func (s *ReceiptService) Void(id uint, by *uint, reason string) (*models.TaxReceipt, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, invalidInput("a reason is required to void a receipt")
+	}
+	var receipt *models.TaxReceipt
+	err := s.store.Transaction(func(tx *repo.Tx) error {
+		var err error
+		if receipt, err = s.voidable(tx, id); err != nil {
+			return err
+		}
+		if err := tx.Receipts.Void(id, by, reason, time.Now(), nil); err != nil {
+			return err
+		}
+		receipt, err = tx.Receipts.GetByID(id)
+		return err
+	})
+	return receipt, err
+}
+
+// Reissue 作废有效收据，并按当前的捐赠记录以新的收据号重开
+func (s *ReceiptService) Reissue(id uint, by *uint, reason string) (*models.TaxReceipt, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, invalidInput("a reason is required to reissue a receipt")
+	}
+	var receipt *models.TaxReceipt
+	err := s.store.Transaction(func(tx *repo.Tx) error {
+		old, err := s.voidable(tx, id)
+		if err != nil {
+			return err
+		}
+		donor, err := tx.Donors.GetByID(old.DonorID)
+		if err != nil {
+			return notFound(err, "donor")
+		}
+		if receipt, err = s.issue(tx, donor, old.Year, by, &old.ID); err != nil {
+			return err
+		}
+		return tx.Receipts.Void(old.ID, by, reason, time.Now(), &receipt.ID)
+	})
+	return receipt, err
+}
+
+// voidable 读取并锁定收据，已作废的收据不能再作废或重开
+func (s *ReceiptService) voidable(tx *repo.Tx, id uint) (*models.TaxReceipt, error) {
+	receipt, err := tx.Receipts.GetByID(id)
+	if err != nil {
+		return nil, notFound(err, "receipt")
+	}
+	if receipt.Status != models.ReceiptStatusIssued {
+		return nil, conflict("receipt %s is already void", receipt.ReceiptNumber)
+	}
+	return receipt, nil
+}
+
+// List 列出收据（含已作废的）；year、donorID 为 0 表示不限
+func (s *ReceiptService) List(year int, donorID uint) ([]models.TaxReceipt, error) {
+	return s.repo.List(year, donorID)
+}
+
+// PDF 按开具时的内容输出收据的 PDF
+func (s *ReceiptService) PDF(id uint) (*models.TaxReceipt, []byte, error) {
+	receipt, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, nil, notFound(err, "receipt")
+	}
+	data, err := renderReceipt(receipt)
+	return receipt, data, err
+}
+
+// DonorPDF 输出捐赠者某年的有效收据，没有时先开具
+func (s *ReceiptService) DonorPDF(donorID uint, year int, by *uint) (*models.TaxReceipt, []byte, error) {
+	receipt, err := s.Issue(donorID, year, by)
+	if err != nil {
+		return nil, nil, err
+	}
+	data, err := renderReceipt(receipt)
+	return receipt, data, err
+}
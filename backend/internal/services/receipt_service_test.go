@@ -0,0 +1,158 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"erp-backend/internal/models"
+	"erp-backend/internal/repo"
+
+	"gorm.io/gorm"
+)
+
+// receiptYearForTest 已结束、可以开具收据的年份
+var receiptYearForTest = time.Now().Year() - 1
+
+// donorWithGift 创建一位捐赠者及其在 year 年的一笔捐款
+func donorWithGift(t *testing.T, db *gorm.DB, n, year int, amount float64) *models.Donor {
+	t.Helper()
+	donor := &models.Donor{DonorID: fmt.Sprintf("DNR-%02d", n), FirstName: "Donor", LastName: fmt.Sprint(n)}
+	mustCreate(t, db, donor)
+	mustCreate(t, db, &models.Donation{DonationID: fmt.Sprintf("DON-%02d-%d", n, year), DonorID: &donor.ID, Amount: amount,
+		DonationType: "one-time", Category: "cash", DonationDate: time.Date(year, 6, 1, 0, 0, 0, 0, time.UTC)})
+	return donor
+}
+
+// receiptNumbers 返回库中全部收据号（含已作废的），按收据号排序
+func receiptNumbers(t *testing.T, svc *ReceiptService) []string {
+	t.Helper()
+	list, err := svc.List(0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	numbers := make([]string, len(list))
+	for i, r := range list {
+		numbers[i] = r.ReceiptNumber
+	}
+	sort.Strings(numbers)
+	return numbers
+}
+
+// sequence 返回 year 年的第 1 到 n 号收据号
+func sequence(year, n int) []string {
+	numbers := make([]string, n)
+	for i := range numbers {
+		numbers[i] = fmt.Sprintf("%d-%06d", year, i+1)
+	}
+	return numbers
+}
+
+func TestReceiptNumbersAreGapFreeUnderConcurrency(t *testing.T) {
+	db := openConcurrentTestDB(t)
+	svc := NewReceiptService(repo.NewReceiptRepository(db), repo.NewStore(db), models.ReceiptOrganisation{Name: "ERP"})
+	var donors []*models.Donor
+	for i := 1; i <= 8; i++ {
+		donors = append(donors, donorWithGift(t, db, i, receiptYearForTest, 10))
+	}
+	// 只在前年有捐款的捐赠者开具失败，不应占用收据号
+	donors = append(donors, donorWithGift(t, db, 9, receiptYearForTest-1, 10))
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(donors))
+	for i, donor := range donors {
+		wg.Add(1)
+		go func(i int, donorID uint) {
+			defer wg.Done()
+			_, errs[i] = svc.Issue(donorID, receiptYearForTest, nil)
+		}(i, donor.ID)
+	}
+	wg.Wait()
+	for i, err := range errs[:8] {
+		if err != nil {
+			t.Errorf("issuing for donor %d: %v", i+1, err)
+		}
+	}
+	if !errors.Is(errs[8], ErrNotFound) {
+		t.Errorf("issuing for a donor without gifts that year = %v, want ErrNotFound", errs[8])
+	}
+	if got, want := receiptNumbers(t, svc), sequence(receiptYearForTest, 8); fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("receipt numbers %v, want %v", got, want)
+	}
+
+	// 再次批量开具不重复开具，也不占用收据号
+	result, err := svc.Generate(receiptYearForTest, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Issued) != 0 || result.Existing != 8 || len(result.Failed) != 0 {
+		t.Errorf("second Generate issued %v, existing %d, failed %v; want none, 8, none", result.Issued, result.Existing, result.Failed)
+	}
+}
+
+func TestReceiptReissueChain(t *testing.T) {
+	db := openServiceTestDB(t)
+	svc := NewReceiptService(repo.NewReceiptRepository(db), repo.NewStore(db), models.ReceiptOrganisation{Name: "ERP"})
+	donor := donorWithGift(t, db, 1, receiptYearForTest, 100)
+	other := donorWithGift(t, db, 2, receiptYearForTest, 40)
+
+	first, err := svc.Issue(donor.ID, receiptYearForTest, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.Issue(other.ID, receiptYearForTest, nil); err != nil {
+		t.Fatal(err)
+	}
+	// 开具后补录的捐款在重开的收据中计入
+	mustCreate(t, db, &models.Donation{DonationID: "DON-LATE", DonorID: &donor.ID, Amount: 25,
+		DonationType: "one-time", Category: "cash", DonationDate: time.Date(receiptYearForTest, 12, 1, 0, 0, 0, 0, time.UTC)})
+
+	if _, err := svc.Reissue(first.ID, nil, " "); !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("Reissue without a reason = %v, want ErrInvalidInput", err)
+	}
+	second, err := svc.Reissue(first.ID, nil, "late gift")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second.ReceiptNumber != fmt.Sprintf("%d-000003", receiptYearForTest) || second.CashTotal != 125 {
+		t.Errorf("reissued receipt %s total %.2f, want %d-000003 and 125", second.ReceiptNumber, second.CashTotal, receiptYearForTest)
+	}
+	if second.ReplacesID == nil || *second.ReplacesID != first.ID {
+		t.Errorf("reissued receipt replaces %v, want %d", second.ReplacesID, first.ID)
+	}
+	var old models.TaxReceipt
+	reload(t, db, &old, first.ID)
+	if old.Status != models.ReceiptStatusVoid || old.ReplacedByID == nil || *old.ReplacedByID != second.ID || old.VoidReason != "late gift" {
+		t.Errorf("original receipt status %s replaced_by %v reason %q, want void, %d, late gift", old.Status, old.ReplacedByID, old.VoidReason, second.ID)
+	}
+
+	// 已作废的收据不能再作废或重开
+	if _, err := svc.Reissue(first.ID, nil, "again"); !errors.Is(err, ErrConflict) {
+		t.Errorf("Reissue of a void receipt = %v, want ErrConflict", err)
+	}
+	if _, err := svc.Void(first.ID, nil, "again"); !errors.Is(err, ErrConflict) {
+		t.Errorf("Void of a void receipt = %v, want ErrConflict", err)
+	}
+
+	// 作废后再开具得到不替换任何收据的新收据
+	if _, err := svc.Void(second.ID, nil, "wrong address"); err != nil {
+		t.Fatal(err)
+	}
+	third, err := svc.Issue(donor.ID, receiptYearForTest, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if third.ID == second.ID || third.ReplacesID != nil || third.Status != models.ReceiptStatusIssued {
+		t.Errorf("receipt after void: id %d replaces %v status %s, want a new issued receipt", third.ID, third.ReplacesID, third.Status)
+	}
+	current, err := svc.Issue(donor.ID, receiptYearForTest, nil)
+	if err != nil || current.ID != third.ID {
+		t.Errorf("Issue with a current receipt returned %v, %v; want receipt %d", current, err, third.ID)
+	}
+	if got, want := receiptNumbers(t, svc), sequence(receiptYearForTest, 4); fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("receipt numbers %v, want %v", got, want)
+	}
+}
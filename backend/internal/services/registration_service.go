@@ -0,0 +1,165 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"erp-backend/internal/models"
+	"erp-backend/internal/repo"
+)
+
+// RegistrationService 员工注册审核：自助注册的员工账号处于 pending 状态，
+// 由管理员批准（同时设置部门与职位）或拒绝后通知注册人
+type RegistrationService struct {
+	repo     *repo.RegistrationRepository
+	store    *repo.Store
+	sessions *SessionService
+	notifier Notifier
+}
+
+func NewRegistrationService(registrationRepo *repo.RegistrationRepository, store *repo.Store, sessions *SessionService, notifier Notifier) *RegistrationService {
+	return &RegistrationService{repo: registrationRepo, store: store, sessions: sessions, notifier: notifier}
+}
+
+// PendingRegistration 待审核的员工注册
+type PendingRegistration struct {
+	UserID       uint            `json:"user_id"`
+	Username     string          `json:"username"`
+	RegisteredAt time.Time       `json:"registered_at"`
+	Employee     models.Employee `json:"employee"`
+}
+
+// ApproveRegistrationRequest 批准请求
+type ApproveRegistrationRequest struct {
+	Department string `json:"department" binding:"required"`
+	Position   string `json:"position" binding:"required"`
+	Reason     string `json:"reason"`
+}
+
+// RejectRegistrationRequest 拒绝请求
+type RejectRegistrationRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+// Pending 返回待审核队列，按注册时间排序
+func (s *RegistrationService) Pending() ([]PendingRegistration, error) {
+	employees, err := s.repo.PendingEmployees()
+	if err != nil {
+		return nil, err
+	}
+	list := make([]PendingRegistration, 0, len(employees))
+	for _, e := range employees {
+		p := PendingRegistration{Employee: e}
+		if e.User != nil {
+			p.UserID, p.Username, p.RegisteredAt = e.User.ID, e.User.Username, e.User.CreatedAt
+		}
+		p.Employee.User = nil // 不随档案返回账号（含密码哈希）
+		list = append(list, p)
+	}
+	return list, nil
+}
+
+// Reviews 返回审核记录，userID 为 0 时返回全部
+func (s *RegistrationService) Reviews(userID uint) ([]models.RegistrationReview, error) {
+	return s.repo.GetReviews(userID)
+}
+
+// Approve 激活员工账号与档案，并写入部门与职位
+func (s *RegistrationService) Approve(userID uint, actor Actor, req *ApproveRegistrationRequest) (*models.RegistrationReview, error) {
+	department, position := strings.TrimSpace(req.Department), strings.TrimSpace(req.Position)
+	if department == "" || position == "" {
+		return nil, invalidInput("department and position are required")
+	}
+	review := &models.RegistrationReview{
+		Decision:   models.RegistrationApproved,
+		Reason:     strings.TrimSpace(req.Reason),
+		Department: department,
+		Position:   position,
+		ReviewedBy: actor.UserID,
+	}
+	employee, err := s.decide(userID, review, "active", map[string]interface{}{
+		"status":     "active",
+		"department": department,
+		"position":   position,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	body := fmt.Sprintf("Hello %s,\n\nYour employee account has been approved. You can now log in.\nDepartment: %s\nPosition: %s",
+		employee.FirstName, department, position)
+	if review.Reason != "" {
+		body += "\nNote: " + review.Reason
+	}
+	s.notify(employee, "Your employee account has been approved", body)
+	return review, nil
+}
+
+// Reject 拒绝注册；账号保留为 rejected 状态，不能登录
+func (s *RegistrationService) Reject(userID uint, actor Actor, req *RejectRegistrationRequest) (*models.RegistrationReview, error) {
+	reason := strings.TrimSpace(req.Reason)
+	if reason == "" {
+		return nil, invalidInput("a reason is required to reject a registration")
+	}
+	review := &models.RegistrationReview{
+		Decision:   models.RegistrationRejected,
+		Reason:     reason,
+		ReviewedBy: actor.UserID,
+	}
+	employee, err := s.decide(userID, review, "rejected", map[string]interface{}{"status": "rejected"})
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.sessions.RevokeUserSessions(userID, models.SessionRevokedAdmin); err != nil {
+		log.Printf("failed to revoke sessions of rejected user %d: %v", userID, err)
+	}
+	body := fmt.Sprintf("Hello %s,\n\nYour employee account registration has been rejected.\nReason: %s",
+		employee.FirstName, reason)
+	s.notify(employee, "Your employee account registration was not approved", body)
+	return review, nil
+}
+
+// decide 在一个事务中更新账号与档案状态并写入审核记录；同一注册只能审核一次
+func (s *RegistrationService) decide(userID uint, review *models.RegistrationReview, userStatus string, updates map[string]interface{}) (*models.Employee, error) {
+	var employee *models.Employee
+	err := s.store.Transaction(func(tx *repo.Tx) error {
+		var err error
+		employee, err = tx.Registrations.GetPendingEmployee(userID)
+		if err != nil {
+			return notFound(err, "pending registration")
+		}
+		ok, err := tx.Registrations.SetUserStatus(userID, "pending", userStatus)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return conflict("registration has already been reviewed")
+		}
+		if err := tx.Registrations.UpdateEmployee(employee.ID, updates); err != nil {
+			return err
+		}
+		review.UserID, review.EmployeeID = userID, employee.ID
+		return tx.Registrations.CreateReview(review)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return employee, nil
+}
+
+// notify 通知注册人；审核结果已提交，发送失败只记录日志
+func (s *RegistrationService) notify(employee *models.Employee, subject, body string) {
+	if s.notifier == nil {
+		return
+	}
+	n := Notification{To: employee.Email, Subject: subject, Body: body}
+	if employee.UserID != nil {
+		n.UserID = *employee.UserID
+	}
+	if err := s.notifier.Notify(n); err != nil {
+		log.Printf("failed to notify user %d: %v", n.UserID, err)
+	}
+}
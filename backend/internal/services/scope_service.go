@@ -0,0 +1,71 @@
+package services
+
+import (
+	"fmt"
+
+	"erp-backend/internal/models"
+	"erp-backend/internal/repo"
+)
+
+// ProjectScopeService 根据调用者的角色与项目分配计算行级数据范围
+type ProjectScopeService struct {
+	assignments *repo.EmployeeProjectRepository
+	rbac        *RBACService
+}
+
+func NewProjectScopeService(employeeProjectRepo *repo.EmployeeProjectRepository, rbacService *RBACService) *ProjectScopeService {
+	return &ProjectScopeService{assignments: employeeProjectRepo, rbac: rbacService}
+}
+
+// Resolve 拥有 data-scope:org-wide 权限的调用者不受限（返回 nil）；
+// 其余员工只能访问所参与的项目，非员工账号看不到任何项目数据
+func (s *ProjectScopeService) Resolve(userType string, employeeID uint, roles []string) (*repo.ProjectScope, error) {
+	if s.rbac.HasPermission(roles, models.ResourceDataScope, models.ActionOrgWide) {
+		return nil, nil
+	}
+	scope := &repo.ProjectScope{}
+	if userType != "employee" || employeeID == 0 {
+		return scope, nil
+	}
+	ids, err := s.assignments.AssignedProjectIDs(employeeID)
+	if err != nil {
+		return nil, err
+	}
+	scope.ProjectIDs = ids
+	scope.EmployeeID = employeeID
+	return scope, nil
+}
+
+// checkScope 校验写操作的数据范围。current 为已有记录（新建时为 nil）：
+// 不在范围内时按不存在处理，不向调用者透露记录存在；target 为写入后的记录，其项目须在范围内。
+// 与调用者本人相关的记录（owner 为本人）不受项目限制。
+func checkScope(scope *repo.ProjectScope, what string, current, target *scopedRecord) error {
+	if scope == nil {
+		return nil
+	}
+	if current != nil && !current.visibleIn(scope) {
+		return fmt.Errorf("%w: %s", ErrNotFound, what)
+	}
+	if target != nil && !target.visibleIn(scope) {
+		return forbidden("you are not assigned to the project of this %s", what)
+	}
+	return nil
+}
+
+// scopedRecord 记录中与数据范围相关的字段
+type scopedRecord struct {
+	projectID *uint
+	ownerID   *uint
+}
+
+func (r *scopedRecord) visibleIn(scope *repo.ProjectScope) bool {
+	return scope.Allows(r.projectID) || (r.ownerID != nil && scope.Owns(r.ownerID))
+}
+
+// scheduleOwner 排班的本人判定只针对员工排班
+func scheduleOwner(schedule *models.Schedule) *uint {
+	if schedule.PersonType != "employee" {
+		return nil
+	}
+	return &schedule.PersonID
+}
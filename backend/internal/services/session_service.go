@@ -0,0 +1,239 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"erp-backend/internal/models"
+	"erp-backend/internal/repo"
+	"erp-backend/pkg/utils"
+
+	"gorm.io/gorm"
+)
+
+// SessionService 登录会话：签发与轮换刷新令牌、注销会话，并为鉴权中间件维护撤销列表。
+// 撤销列表只需保留访问令牌有效期内被终止的会话，更早的会话其访问令牌已自然过期。
+type SessionService struct {
+	repo       *repo.SessionRepository
+	store      *repo.Store
+	refreshTTL time.Duration
+
+	mu      sync.Mutex
+	revoked map[string]time.Time // session_id → 该会话访问令牌的最晚过期时间
+}
+
+func NewSessionService(sessionRepo *repo.SessionRepository, store *repo.Store, refreshTTL time.Duration) *SessionService {
+	return &SessionService{repo: sessionRepo, store: store, refreshTTL: refreshTTL, revoked: map[string]time.Time{}}
+}
+
+// LoadRevoked 启动时从数据库重建撤销列表
+func (s *SessionService) LoadRevoked() error {
+	sessions, err := s.repo.RevokedSince(time.Now().UTC().Add(-utils.AccessTokenTTL))
+	if err != nil {
+		return err
+	}
+	for _, session := range sessions {
+		s.remember(session.SessionID, *session.RevokedAt)
+	}
+	return nil
+}
+
+// IsRevoked 判断会话是否已被终止（供鉴权中间件调用）
+func (s *SessionService) IsRevoked(sessionID string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	until, ok := s.revoked[sessionID]
+	return ok && time.Now().Before(until)
+}
+
+func (s *SessionService) remember(sessionID string, revokedAt time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	for sid, until := range s.revoked {
+		if now.After(until) {
+			delete(s.revoked, sid)
+		}
+	}
+	s.revoked[sessionID] = revokedAt.Add(utils.AccessTokenTTL)
+}
+
+// Start 为一次成功登录创建会话，返回会话与首个刷新令牌
+func (s *SessionService) Start(userID uint, userAgent, clientIP string) (*models.Session, string, error) {
+	var session *models.Session
+	var refreshToken string
+	err := s.store.Transaction(func(tx *repo.Tx) error {
+		now := time.Now().UTC()
+		sid, err := randomToken(16)
+		if err != nil {
+			return err
+		}
+		session = &models.Session{
+			SessionID:  sid,
+			UserID:     userID,
+			UserAgent:  truncate(userAgent, 255),
+			ClientIP:   clientIP,
+			ExpiresAt:  now.Add(s.refreshTTL),
+			LastUsedAt: now,
+		}
+		if err := tx.Sessions.CreateSession(session); err != nil {
+			return err
+		}
+		refreshToken, err = s.issueRefreshToken(tx, session.ID, now)
+		return err
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	return session, refreshToken, nil
+}
+
+// Rotate 使用一次刷新令牌：作废该令牌并签发新令牌。
+// 已使用过的令牌再次出现说明令牌已泄露，立即终止整个会话。
+func (s *SessionService) Rotate(refreshToken string) (*models.Session, string, error) {
+	var session *models.Session
+	var next string
+	reused := false
+	err := s.store.Transaction(func(tx *repo.Tx) error {
+		token, err := tx.Sessions.GetRefreshToken(hashToken(refreshToken))
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return unauthorized("invalid refresh token")
+		}
+		if err != nil {
+			return err
+		}
+		if session, err = tx.Sessions.GetSessionByID(token.SessionID); err != nil {
+			return err
+		}
+		if session.RevokedAt != nil {
+			return unauthorized("the session has been terminated")
+		}
+
+		now := time.Now().UTC()
+		marked := false
+		if token.UsedAt == nil {
+			if marked, err = tx.Sessions.MarkRefreshTokenUsed(token.ID, now); err != nil {
+				return err
+			}
+		}
+		if !marked {
+			reused = true
+			_, err := tx.Sessions.Revoke(session.ID, models.SessionRevokedReuse, now)
+			return err
+		}
+		if now.After(token.ExpiresAt) {
+			return unauthorized("refresh token expired")
+		}
+
+		if next, err = s.issueRefreshToken(tx, session.ID, now); err != nil {
+			return err
+		}
+		session.LastUsedAt, session.ExpiresAt = now, now.Add(s.refreshTTL)
+		return tx.Sessions.Touch(session.ID, session.LastUsedAt, session.ExpiresAt)
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	if reused {
+		log.Printf("refresh token reuse detected for session %s (user %d); session terminated", session.SessionID, session.UserID)
+		s.remember(session.SessionID, time.Now().UTC())
+		return nil, "", unauthorized("refresh token has already been used; the session has been terminated")
+	}
+	return session, next, nil
+}
+
+// End 注销会话（用户登出）
+func (s *SessionService) End(sessionID string) error {
+	session, err := s.repo.GetSessionBySID(sessionID)
+	if err != nil {
+		return notFound(err, "session")
+	}
+	return s.revoke(session, models.SessionRevokedLogout)
+}
+
+// ActiveSessions 列出用户的活动会话，userID 为 0 时列出全部
+func (s *SessionService) ActiveSessions(userID uint) ([]models.Session, error) {
+	return s.repo.ActiveSessions(userID)
+}
+
+// RevokeSession 由管理员终止指定会话
+func (s *SessionService) RevokeSession(id uint) error {
+	session, err := s.repo.GetSessionByID(id)
+	if err != nil {
+		return notFound(err, "session")
+	}
+	return s.revoke(session, models.SessionRevokedAdmin)
+}
+
+// RevokeUserSessions 终止用户的全部活动会话，返回终止的数量
+func (s *SessionService) RevokeUserSessions(userID uint, reason string) (int, error) {
+	return s.RevokeOtherSessions(userID, "", reason)
+}
+
+// RevokeOtherSessions 终止用户除 keepSID 以外的活动会话（如修改密码后保留当前会话）
+func (s *SessionService) RevokeOtherSessions(userID uint, keepSID, reason string) (int, error) {
+	sessions, err := s.repo.ActiveSessions(userID)
+	if err != nil {
+		return 0, err
+	}
+	n := 0
+	for i := range sessions {
+		if sessions[i].SessionID == keepSID {
+			continue
+		}
+		if err := s.revoke(&sessions[i], reason); err != nil {
+			return n, err
+		}
+		n++
+	}
+	return n, nil
+}
+
+func (s *SessionService) revoke(session *models.Session, reason string) error {
+	now := time.Now().UTC()
+	if _, err := s.repo.Revoke(session.ID, reason, now); err != nil {
+		return err
+	}
+	s.remember(session.SessionID, now)
+	return nil
+}
+
+func (s *SessionService) issueRefreshToken(tx *repo.Tx, sessionID uint, now time.Time) (string, error) {
+	raw, err := randomToken(32)
+	if err != nil {
+		return "", err
+	}
+	token := &models.RefreshToken{SessionID: sessionID, TokenHash: hashToken(raw), ExpiresAt: now.Add(s.refreshTTL)}
+	if err := tx.Sessions.CreateRefreshToken(token); err != nil {
+		return "", err
+	}
+	return raw, nil
+}
+
+// randomToken 生成 n 字节的随机令牌（URL 安全的 Base64 编码）
+func randomToken(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashToken 刷新令牌与密码重置令牌只以 SHA-256 哈希落库
+func hashToken(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
+
+func truncate(s string, n int) string {
+	if len(s) > n {
+		return s[:n]
+	}
+	return s
+}
@@ -0,0 +1,111 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"erp-backend/internal/models"
+	"erp-backend/internal/repo"
+)
+
+func newSessionTestService(t *testing.T, refreshTTL time.Duration) (*SessionService, uint) {
+	t.Helper()
+	db := openServiceTestDB(t)
+	user := &models.User{Username: "alice", PasswordHash: "x", UserType: "employee", Status: "active"}
+	mustCreate(t, db, user)
+	return NewSessionService(repo.NewSessionRepository(db), repo.NewStore(db), refreshTTL), user.ID
+}
+
+func requireUnauthorized(t *testing.T, err error, what string) {
+	t.Helper()
+	if !errors.Is(err, ErrUnauthorized) {
+		t.Fatalf("%s: Rotate = %v, want ErrUnauthorized", what, err)
+	}
+}
+
+func TestRefreshTokenReuseRevokesSession(t *testing.T) {
+	tests := []struct {
+		name   string
+		replay int // 重放第几代令牌（0 为登录时签发的令牌）
+	}{
+		{"login token", 0},
+		{"rotated token", 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, userID := newSessionTestService(t, time.Hour)
+			session, token, err := svc.Start(userID, "test", "127.0.0.1")
+			if err != nil {
+				t.Fatal(err)
+			}
+			other, otherToken, err := svc.Start(userID, "other device", "127.0.0.2")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// 轮换两次，每次旧令牌作废、签发新令牌
+			tokens := []string{token}
+			for i := 0; i < 2; i++ {
+				_, next, err := svc.Rotate(tokens[len(tokens)-1])
+				if err != nil {
+					t.Fatalf("rotation %d: %v", i+1, err)
+				}
+				tokens = append(tokens, next)
+			}
+
+			_, _, err = svc.Rotate(tokens[tt.replay])
+			requireUnauthorized(t, err, "replayed token")
+			if !svc.IsRevoked(session.SessionID) {
+				t.Error("session is not revoked after refresh token reuse")
+			}
+			stored, err := svc.repo.GetSessionByID(session.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if stored.RevokedAt == nil || stored.RevokeReason != models.SessionRevokedReuse {
+				t.Errorf("session revoked_at %v reason %q, want reason %q", stored.RevokedAt, stored.RevokeReason, models.SessionRevokedReuse)
+			}
+			// 会话终止后，尚未使用的最新令牌也不能再用
+			_, _, err = svc.Rotate(tokens[len(tokens)-1])
+			requireUnauthorized(t, err, "latest token of a revoked session")
+
+			// 同一用户的其他会话不受影响
+			if svc.IsRevoked(other.SessionID) {
+				t.Error("another session of the same user was revoked")
+			}
+			if _, _, err := svc.Rotate(otherToken); err != nil {
+				t.Errorf("rotating the other session: %v", err)
+			}
+		})
+	}
+}
+
+func TestRefreshTokenRejectedAfterLogout(t *testing.T) {
+	svc, userID := newSessionTestService(t, time.Hour)
+	session, token, err := svc.Start(userID, "test", "127.0.0.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.End(session.SessionID); err != nil {
+		t.Fatal(err)
+	}
+	if !svc.IsRevoked(session.SessionID) {
+		t.Error("session is not revoked after logout")
+	}
+	_, _, err = svc.Rotate(token)
+	requireUnauthorized(t, err, "token after logout")
+	_, _, err = svc.Rotate("not-a-token")
+	requireUnauthorized(t, err, "unknown token")
+}
+
+func TestRefreshTokenExpires(t *testing.T) {
+	// 有效期为负，签发的令牌立即过期
+	svc, userID := newSessionTestService(t, -time.Minute)
+	_, token, err := svc.Start(userID, "test", "127.0.0.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _, err = svc.Rotate(token)
+	requireUnauthorized(t, err, "expired token")
+}
@@ -0,0 +1,225 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"erp-backend/internal/models"
+	"erp-backend/internal/repo"
+)
+
+// 库存变动的过账：业务单据产生的入库、出库按方向调整库存数量，出库后库存不能小于零。
+// 单据修改或删除时以方向相反的变动冲销原变动，原变动保留（与总账分录的冲销方式相同）。
+// 每笔变动在过账时确定单位成本（见 costing_service.go），冲销沿用原变动的成本
+
+// stockMove 一笔待过账的库存变动，from 出库、to 入库，单边变动另一方为 nil；
+// 手工登记的变动没有来源单据，source 为空。unitCost 与 purchaseID 只用于单边入库的定价（见 moveCost），
+// replaces 为过账后即被冲销的原变动，计算成本时不计入
+type stockMove struct {
+	moveType   string
+	from, to   *uint
+	quantity   int
+	date       time.Time
+	source     string
+	sourceID   uint
+	unitCost   float64
+	purchaseID *uint
+	replaces   []uint
+}
+
+// postMove 确定单位成本，调整库存数量并写入变动记录
+func postMove(tx *repo.Tx, m stockMove) (*models.InventoryTransaction, error) {
+	move, err := newMove(tx, m)
+	if err != nil {
+		return nil, err
+	}
+	if err := applyMove(tx, move); err != nil {
+		return nil, err
+	}
+	return move, nil
+}
+
+// newMove 校验待过账变动并确定单位成本，返回尚未写入的变动记录
+func newMove(tx *repo.Tx, m stockMove) (*models.InventoryTransaction, error) {
+	if m.quantity <= 0 {
+		return nil, invalidInput("quantity must be greater than zero")
+	}
+	if m.from == nil && m.to == nil {
+		return nil, invalidInput("a stock movement needs a source or destination inventory")
+	}
+	unitCost, err := moveCost(tx, m)
+	if err != nil {
+		return nil, err
+	}
+	move := &models.InventoryTransaction{
+		FromInventoryID: m.from,
+		ToInventoryID:   m.to,
+		TransactionType: m.moveType,
+		QuantityChange:  m.quantity,
+		TransactionDate: m.date,
+		SourceType:      m.source,
+		UnitCost:        unitCost,
+		PurchaseID:      m.purchaseID,
+	}
+	if m.source != "" {
+		move.SourceID = &m.sourceID
+	}
+	return move, nil
+}
+
+// repostMove 过账单据修改后的变动，同时冲销修改前的变动，返回新变动；同一库存只按净减少量校验库存，
+// 新变动的成本按原变动不存在时计算
+func repostMove(tx *repo.Tx, m stockMove) (*models.InventoryTransaction, error) {
+	old, err := tx.InventoryTransactions.ActiveBySource(m.source, m.sourceID)
+	if err != nil {
+		return nil, err
+	}
+	for _, o := range old {
+		m.replaces = append(m.replaces, o.ID)
+	}
+	move, err := newMove(tx, m)
+	if err != nil {
+		return nil, err
+	}
+	reversals := reversalsOf(old)
+	deltas := stockDeltas(nil, move)
+	for _, r := range reversals {
+		deltas = stockDeltas(deltas, r)
+	}
+	if err := adjustStock(tx, deltas); err != nil {
+		return nil, err
+	}
+	if err := tx.InventoryTransactions.Create(move); err != nil {
+		return nil, fmt.Errorf("failed to record stock movement: %w", err)
+	}
+	return move, recordReversals(tx, old, reversals)
+}
+
+// reverseMoves 冲销某单据尚未冲销的库存变动；入库的物品已经发出、库存不足以退回时拒绝
+func reverseMoves(tx *repo.Tx, source string, sourceID uint) error {
+	moves, err := tx.InventoryTransactions.ActiveBySource(source, sourceID)
+	if err != nil {
+		return err
+	}
+	return reverseEach(tx, moves)
+}
+
+func reverseEach(tx *repo.Tx, moves []models.InventoryTransaction) error {
+	reversals := reversalsOf(moves)
+	for _, r := range reversals {
+		if err := adjustStock(tx, stockDeltas(nil, r)); err != nil {
+			return err
+		}
+	}
+	return recordReversals(tx, moves, reversals)
+}
+
+// reversalsOf 为每笔变动生成方向相反、数量与成本相同的冲销变动
+func reversalsOf(moves []models.InventoryTransaction) []*models.InventoryTransaction {
+	reversals := make([]*models.InventoryTransaction, len(moves))
+	for i := range moves {
+		m := &moves[i]
+		reversals[i] = &models.InventoryTransaction{
+			FromInventoryID: m.ToInventoryID,
+			ToInventoryID:   m.FromInventoryID,
+			TransactionType: m.TransactionType,
+			QuantityChange:  m.QuantityChange,
+			TransactionDate: m.TransactionDate,
+			SourceType:      m.SourceType,
+			SourceID:        m.SourceID,
+			UnitCost:        m.UnitCost,
+			PurchaseID:      m.PurchaseID,
+			ReversalOfID:    &m.ID,
+		}
+	}
+	return reversals
+}
+
+// recordReversals 写入冲销变动并标记原变动已冲销；库存数量已由调用方调整
+func recordReversals(tx *repo.Tx, moves []models.InventoryTransaction, reversals []*models.InventoryTransaction) error {
+	for i, reversal := range reversals {
+		if err := tx.InventoryTransactions.Create(reversal); err != nil {
+			return fmt.Errorf("failed to record stock movement: %w", err)
+		}
+		if err := tx.InventoryTransactions.MarkReversed(moves[i].ID, reversal.ID); err != nil {
+			return fmt.Errorf("failed to reverse stock movement: %w", err)
+		}
+	}
+	return nil
+}
+
+// applyMove 调整库存数量，然后写入变动记录
+func applyMove(tx *repo.Tx, move *models.InventoryTransaction) error {
+	if err := adjustStock(tx, stockDeltas(nil, move)); err != nil {
+		return err
+	}
+	if err := tx.InventoryTransactions.Create(move); err != nil {
+		return fmt.Errorf("failed to record stock movement: %w", err)
+	}
+	return nil
+}
+
+// stockDelta 一项库存的数量变化
+type stockDelta struct {
+	inventoryID uint
+	quantity    int
+}
+
+// stockDeltas 把变动对各库存数量的影响累加到 deltas，同一库存合并为一项
+func stockDeltas(deltas []stockDelta, move *models.InventoryTransaction) []stockDelta {
+	add := func(id *uint, quantity int) {
+		if id == nil {
+			return
+		}
+		for i := range deltas {
+			if deltas[i].inventoryID == *id {
+				deltas[i].quantity += quantity
+				return
+			}
+		}
+		deltas = append(deltas, stockDelta{inventoryID: *id, quantity: quantity})
+	}
+	add(move.FromInventoryID, -move.QuantityChange)
+	add(move.ToInventoryID, move.QuantityChange)
+	return deltas
+}
+
+// adjustStock 先出库再入库地调整库存数量，出库后库存不能小于零
+func adjustStock(tx *repo.Tx, deltas []stockDelta) error {
+	for _, d := range deltas {
+		if d.quantity < 0 {
+			if err := withdrawStock(tx, d.inventoryID, -d.quantity); err != nil {
+				return err
+			}
+		}
+	}
+	for _, d := range deltas {
+		if d.quantity <= 0 {
+			continue
+		}
+		ok, err := tx.Inventories.AdjustStock(d.inventoryID, d.quantity)
+		if err != nil {
+			return fmt.Errorf("failed to update stock: %w", err)
+		}
+		if !ok {
+			return fmt.Errorf("%w: inventory %d", ErrNotFound, d.inventoryID)
+		}
+	}
+	return nil
+}
+
+// withdrawStock 在库存足够时扣减库存数量
+func withdrawStock(tx *repo.Tx, inventoryID uint, quantity int) error {
+	ok, err := tx.Inventories.AdjustStock(inventoryID, -quantity)
+	if err != nil {
+		return fmt.Errorf("failed to update stock: %w", err)
+	}
+	if ok {
+		return nil
+	}
+	inventory, err := tx.Inventories.GetByID(inventoryID)
+	if err != nil {
+		return notFound(err, "inventory")
+	}
+	return conflict("inventory %s has %d in stock, %d required", inventory.InventoryID, inventory.CurrentStock, quantity)
+}
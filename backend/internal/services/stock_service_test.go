@@ -0,0 +1,129 @@
+package services
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"erp-backend/internal/models"
+	"erp-backend/internal/repo"
+
+	"gorm.io/gorm"
+)
+
+// requireStock 检查库存数量，并确认账面数量与按库存变动累计的数量一致
+func requireStock(t *testing.T, db *gorm.DB, inventoryID uint, want int) {
+	t.Helper()
+	var inventory models.Inventory
+	reload(t, db, &inventory, inventoryID)
+	if inventory.CurrentStock != want {
+		t.Errorf("stock = %d, want %d", inventory.CurrentStock, want)
+	}
+	drift, _, err := NewInventoryService(repo.NewInventoryRepository(db), repo.NewStore(db)).Reconcile(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(drift) != 0 {
+		t.Errorf("stock differs from the movements: %+v", drift)
+	}
+}
+
+func TestConcurrentIssuesNeverOverdrawStock(t *testing.T) {
+	db := openConcurrentTestDB(t)
+	store := repo.NewStore(db)
+	inventory := &models.Inventory{InventoryID: "INV-1", Name: "Blanket", CurrentStock: 10, UnitCost: 2}
+	if err := NewInventoryService(repo.NewInventoryRepository(db), store).Create(inventory); err != nil {
+		t.Fatal(err)
+	}
+	delivery := &models.Delivery{DeliveryID: "DEL-1", Quantity: 12}
+	mustCreate(t, db, delivery)
+	lines := NewDeliveryInventoryService(repo.NewDeliveryInventoryRepository(db), store)
+	moves := NewInventoryTransactionService(repo.NewInventoryTransactionRepository(db), store)
+
+	// 配送出库与盘点减少各 4 笔，每笔 3 件，库存 10 件只够其中 3 笔
+	issues := make([]func() error, 8)
+	for i := range issues {
+		if i%2 == 0 {
+			issues[i] = func() error {
+				return lines.Create(&models.DeliveryInventory{DeliveryID: &delivery.ID, InventoryID: &inventory.ID, Quantity: 3})
+			}
+		} else {
+			issues[i] = func() error {
+				return moves.Create(&models.InventoryTransaction{TransactionType: models.InventoryMoveAdjustment,
+					FromInventoryID: &inventory.ID, QuantityChange: 3})
+			}
+		}
+	}
+	var wg sync.WaitGroup
+	errs := make([]error, len(issues))
+	for i, issue := range issues {
+		wg.Add(1)
+		go func(i int, issue func() error) {
+			defer wg.Done()
+			errs[i] = issue()
+		}(i, issue)
+	}
+	wg.Wait()
+
+	succeeded, delivered := 0, 0
+	for i, err := range errs {
+		switch {
+		case err == nil:
+			succeeded++
+			if i%2 == 0 {
+				delivered++
+			}
+		case !errors.Is(err, ErrConflict):
+			t.Errorf("issue %d: %v, want nil or ErrConflict", i+1, err)
+		}
+	}
+	if succeeded != 3 {
+		t.Errorf("%d issues succeeded, want 3", succeeded)
+	}
+	requireStock(t, db, inventory.ID, 1)
+	// 出库失败的配送明细随事务回滚
+	var saved int64
+	db.Model(&models.DeliveryInventory{}).Count(&saved)
+	if int(saved) != delivered {
+		t.Errorf("%d delivery lines saved for %d successful deliveries", saved, delivered)
+	}
+}
+
+func TestStockChecksNetChangeOnUpdate(t *testing.T) {
+	db := openServiceTestDB(t)
+	store := repo.NewStore(db)
+	inventory := &models.Inventory{InventoryID: "INV-1", Name: "Blanket", CurrentStock: 5, UnitCost: 2}
+	if err := NewInventoryService(repo.NewInventoryRepository(db), store).Create(inventory); err != nil {
+		t.Fatal(err)
+	}
+	delivery := &models.Delivery{DeliveryID: "DEL-1", Quantity: 5}
+	mustCreate(t, db, delivery)
+	lines := NewDeliveryInventoryService(repo.NewDeliveryInventoryRepository(db), store)
+
+	line := &models.DeliveryInventory{DeliveryID: &delivery.ID, InventoryID: &inventory.ID, Quantity: 4}
+	if err := lines.Create(line); err != nil {
+		t.Fatal(err)
+	}
+	// 修改只按净增加量校验：4 → 5 需要再出库 1 件，库存剩 1 件足够
+	steps := []struct {
+		quantity int
+		want     error
+		stock    int
+	}{
+		{6, ErrConflict, 1},
+		{5, nil, 0},
+		{2, nil, 3},
+	}
+	for _, step := range steps {
+		update := &models.DeliveryInventory{ID: line.ID, DeliveryID: &delivery.ID, InventoryID: &inventory.ID, Quantity: step.quantity}
+		if err := lines.Update(update); !errors.Is(err, step.want) {
+			t.Errorf("updating the line to %d = %v, want %v", step.quantity, err, step.want)
+		}
+		requireStock(t, db, inventory.ID, step.stock)
+	}
+
+	if err := lines.Delete(line.ID); err != nil {
+		t.Fatal(err)
+	}
+	requireStock(t, db, inventory.ID, 5)
+}
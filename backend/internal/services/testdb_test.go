@@ -0,0 +1,67 @@
+package services
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"erp-backend/internal/models"
+	"erp-backend/internal/repo"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// openServiceTestDB 打开执行过全部迁移的内存 SQLite 数据库
+func openServiceTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	return openSeededDB(t, fmt.Sprintf("file:%s?mode=memory&cache=shared&_foreign_keys=1", strings.ReplaceAll(t.Name(), "/", "_")))
+}
+
+// openConcurrentTestDB 打开临时目录中的 SQLite 文件库，供并发测试使用：
+// 共享缓存的内存库在并发写入时直接报表锁错误，文件库则按 busy_timeout 排队等待写锁
+func openConcurrentTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "erp.db")
+	return openSeededDB(t, "file:"+path+"?_foreign_keys=1&_busy_timeout=10000&_txlock=immediate")
+}
+
+// openSeededDB 执行全部迁移并写入默认会计科目与内置角色，与 repo.InitDatabase 一致
+func openSeededDB(t *testing.T, dsn string) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { sqlDB.Close() })
+	m, err := repo.NewMigrator(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Up(); err != nil {
+		t.Fatalf("migrate up: %v", err)
+	}
+	if err := repo.NewLedgerRepository(db).SeedAccounts(models.DefaultChartOfAccounts()); err != nil {
+		t.Fatalf("seed chart of accounts: %v", err)
+	}
+	if err := repo.NewRBACRepository(db).SeedRoles(models.DefaultRoles()); err != nil {
+		t.Fatalf("seed roles: %v", err)
+	}
+	return db
+}
+
+// mustCreate 直接写入测试数据，绕过服务层校验
+func mustCreate(t *testing.T, db *gorm.DB, values ...interface{}) {
+	t.Helper()
+	for _, v := range values {
+		if err := db.Create(v).Error; err != nil {
+			t.Fatalf("create %T: %v", v, err)
+		}
+	}
+}
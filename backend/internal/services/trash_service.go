@@ -0,0 +1,97 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"erp-backend/internal/repo"
+
+	"gorm.io/gorm"
+)
+
+// restoreHooks 恢复后需要重新过账的资源：删除时已冲销的余额、交易记录与总账分录，
+// 在同一事务内按原单据重新过账（含库存出入库）；所依赖的记录（如捐赠者、基金）已删除时恢复失败
+var restoreHooks = map[string]func(tx *repo.Tx, id uint) error{
+	"donations":          restoreDonation,
+	"purchases":          restorePurchase,
+	"payrolls":           restorePayroll,
+	"fund-projects":      restoreFundProject,
+	"donation-inventory": restoreInKind,
+	"delivery-inventory": restoreDeliveryLine,
+	"deliveries":         restoreDelivery,
+	"inventory":          restoreInventory,
+}
+
+// TrashService 回收站：各 ERP 资源软删除后的查看、恢复与彻底删除
+type TrashService struct {
+	repo  *repo.TrashRepository
+	store *repo.Store
+	scope *repo.ProjectScope
+}
+
+func NewTrashService(trashRepo *repo.TrashRepository, store *repo.Store) *TrashService {
+	return &TrashService{repo: trashRepo, store: store}
+}
+
+// Scoped 返回限定在调用者数据范围内的服务副本
+func (s *TrashService) Scoped(scope *repo.ProjectScope) *TrashService {
+	scoped := *s
+	scoped.scope = scope
+	return &scoped
+}
+
+func (s *TrashService) WithContext(ctx context.Context) *TrashService {
+	bound := *s
+	bound.repo = s.repo.WithContext(ctx)
+	bound.store = s.store.WithContext(ctx)
+	return &bound
+}
+
+// List 返回某资源回收站中的记录及条数
+func (s *TrashService) List(resource string) (interface{}, int, error) {
+	if !repo.IsTrashResource(resource) {
+		return nil, 0, invalidInput("%s has no trash", resource)
+	}
+	return s.repo.Scoped(s.scope).List(resource)
+}
+
+// Restore 恢复回收站中的记录，需要时重新过账
+func (s *TrashService) Restore(resource string, id uint) error {
+	if !repo.IsTrashResource(resource) {
+		return invalidInput("%s has no trash", resource)
+	}
+	return s.store.Transaction(func(tx *repo.Tx) error {
+		if err := tx.Trash.Scoped(s.scope).Restore(resource, id); err != nil {
+			return notFound(err, "deleted record")
+		}
+		if hook, ok := restoreHooks[resource]; ok {
+			return hook(tx, id)
+		}
+		return nil
+	})
+}
+
+// Purge 彻底删除回收站中的记录，按引用策略一并删除或置空引用它的记录，不可恢复
+func (s *TrashService) Purge(resource string, id uint) error {
+	if !repo.IsTrashResource(resource) {
+		return invalidInput("%s has no trash", resource)
+	}
+	return s.store.Transaction(func(tx *repo.Tx) error {
+		if err := tx.Trash.Scoped(s.scope).Purge(resource, id); err != nil {
+			return notFound(err, "deleted record")
+		}
+		return nil
+	})
+}
+
+// restoreTransaction 恢复随业务单据一起删除的交易记录；交易记录未被删除时不做处理
+func restoreTransaction(tx *repo.Tx, id *uint) error {
+	if id == nil || *id == 0 {
+		return nil
+	}
+	if err := tx.Trash.Restore("transactions", *id); err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("failed to restore transaction: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"erp-backend/internal/models"
+	"erp-backend/internal/repo"
+)
+
+func TestTrashPermissionsOfDefaultRoles(t *testing.T) {
+	db := openServiceTestDB(t)
+	rbac := NewRBACService(repo.NewRBACRepository(db))
+
+	// 恢复沿用资源自身的 <资源>:restore，彻底删除与强制级联删除另需 trash 权限
+	tests := []struct {
+		role       string
+		permission string
+		want       bool
+	}{
+		{models.RoleAdmin, "donations:restore", true},
+		{models.RoleAdmin, "trash:purge", true},
+		{models.RoleAdmin, "trash:force-delete", true},
+		{models.RoleFinanceAdmin, "donations:restore", true},
+		{models.RoleFinanceAdmin, "projects:restore", false},
+		{models.RoleFinanceAdmin, "trash:purge", false},
+		{models.RoleFinanceAdmin, "trash:force-delete", false},
+		{models.RoleProjectManager, "projects:restore", true},
+		{models.RoleProjectManager, "donations:restore", false},
+		{models.RoleProjectManager, "trash:purge", false},
+		{models.RoleWarehouse, "inventory:restore", true},
+		{models.RoleStaff, "projects:restore", false},
+		{models.RoleStaff, "trash:purge", false},
+	}
+	for _, tt := range tests {
+		resource, action, _ := models.ParsePermissionCode(tt.permission)
+		if got := rbac.HasPermission([]string{tt.role}, resource, action); got != tt.want {
+			t.Errorf("%s has %s = %v, want %v", tt.role, tt.permission, got, tt.want)
+		}
+	}
+}
+
+func TestTrashRestoreAndPurge(t *testing.T) {
+	donations, db := newDonationTestService(t)
+	wells := &models.Project{ProjectID: "PRJ-1", Name: "Wells"}
+	school := &models.Project{ProjectID: "PRJ-2", Name: "School"}
+	donor := &models.Donor{DonorID: "DNR-1", FirstName: "Dana", LastName: "Lee"}
+	fund := &models.Fund{FundID: "FND-1", Name: "General", FundType: models.FundTypeUnrestricted, TotalAmount: 100}
+	mustCreate(t, db, wells, school, donor, fund)
+	donation := &models.Donation{DonorID: &donor.ID, FundID: &fund.ID, ProjectID: &school.ID, Amount: 80, DonationType: "one-time", Category: "cash"}
+	if err := donations.Create(donation); err != nil {
+		t.Fatal(err)
+	}
+	trash := NewTrashService(repo.NewTrashRepository(db), repo.NewStore(db))
+
+	if err := trash.Purge("donations", donation.ID); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Purge of a live donation = %v, want ErrNotFound", err)
+	}
+	if err := donations.Delete(donation.ID); err != nil {
+		t.Fatal(err)
+	}
+	reload(t, db, fund, fund.ID)
+	if fund.CurrentBalance != 0 {
+		t.Fatalf("fund balance after delete = %.2f, want 0", fund.CurrentBalance)
+	}
+
+	// 不在调用者项目范围内的记录既不能恢复也不能彻底删除
+	outsider := trash.Scoped(&repo.ProjectScope{ProjectIDs: []uint{wells.ID}})
+	if err := outsider.Restore("donations", donation.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Restore outside the caller's projects = %v, want ErrNotFound", err)
+	}
+	if err := outsider.Purge("donations", donation.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Purge outside the caller's projects = %v, want ErrNotFound", err)
+	}
+	if err := trash.Restore("funds-and-things", 1); !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("Restore of an unknown resource = %v, want ErrInvalidInput", err)
+	}
+
+	// 恢复后重新过账
+	member := trash.Scoped(&repo.ProjectScope{ProjectIDs: []uint{school.ID}})
+	if err := member.Restore("donations", donation.ID); err != nil {
+		t.Fatal(err)
+	}
+	reload(t, db, fund, fund.ID)
+	reload(t, db, donor, donor.ID)
+	if fund.CurrentBalance != 80 || donor.TotalDonated != 80 {
+		t.Errorf("after restore fund balance %.2f donor total %.2f, want 80 and 80", fund.CurrentBalance, donor.TotalDonated)
+	}
+	if got := accountBalance(t, db, models.AccountCash); got != 80 {
+		t.Errorf("cash balance after restore = %.2f, want 80", got)
+	}
+	if err := member.Restore("donations", donation.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("second Restore = %v, want ErrNotFound", err)
+	}
+
+	if err := donations.Delete(donation.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := member.Purge("donations", donation.ID); err != nil {
+		t.Fatal(err)
+	}
+	var remaining int64
+	db.Unscoped().Model(&models.Donation{}).Where("id = ?", donation.ID).Count(&remaining)
+	if remaining != 0 {
+		t.Error("purged donation is still in the database")
+	}
+	if err := member.Restore("donations", donation.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Restore after purge = %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,381 @@
+package services
+
+import (
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"strings"
+	"time"
+
+	"erp-backend/internal/models"
+	"erp-backend/internal/repo"
+	"erp-backend/pkg/utils"
+
+	"gorm.io/gorm"
+)
+
+const (
+	totpSkew             = 1  // 允许前后各一个时间步（±30 秒）的时钟偏差
+	challengeMaxAttempts = 5  // 一个登录挑战最多可提交的错误验证码次数
+	recoveryCodeCount    = 10 // 每次生成的恢复码数量
+)
+
+// errBadSecondFactor 验证码或恢复码不正确（事务回滚后再累加挑战的失败次数）
+var errBadSecondFactor = unauthorized("invalid verification code")
+
+// TwoFactorService 员工账号的 TOTP 双因素认证（RFC 6238）：登记与确认、一次性恢复码，
+// 以及两步登录中的挑战令牌。角色可被管理员设为强制双因素认证
+type TwoFactorService struct {
+	repo         *repo.TwoFactorRepository
+	store        *repo.Store
+	rbac         *RBACService
+	key          []byte // 加密 TOTP 密钥的 AES 密钥
+	issuer       string // 身份验证器中显示的发行方名称
+	challengeTTL time.Duration
+}
+
+func NewTwoFactorService(twoFactorRepo *repo.TwoFactorRepository, store *repo.Store, rbac *RBACService, key []byte, issuer string, challengeTTL time.Duration) *TwoFactorService {
+	return &TwoFactorService{
+		repo:         twoFactorRepo,
+		store:        store,
+		rbac:         rbac,
+		key:          key,
+		issuer:       issuer,
+		challengeTTL: challengeTTL,
+	}
+}
+
+// TwoFactorStatus 账号的双因素认证状态
+type TwoFactorStatus struct {
+	Enabled                bool  `json:"enabled"`
+	Required               bool  `json:"required"`
+	RecoveryCodesRemaining int64 `json:"recovery_codes_remaining"`
+}
+
+// TwoFactorEnrollment 登记信息：在身份验证器中扫描 URI 的二维码，或手动输入密钥
+type TwoFactorEnrollment struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+// TwoFactorCodeRequest 提交验证码（或恢复码）
+type TwoFactorCodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// Required 判断角色集合是否强制双因素认证
+func (s *TwoFactorService) Required(roles []string) (bool, error) {
+	return s.rbac.RequiresTwoFactor(roles)
+}
+
+// Enabled 判断账号是否已启用双因素认证
+func (s *TwoFactorService) Enabled(userID uint) (bool, error) {
+	tf, err := s.repo.Get(userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return tf.Enabled, nil
+}
+
+func (s *TwoFactorService) Status(userID uint, roles []string) (*TwoFactorStatus, error) {
+	enabled, err := s.Enabled(userID)
+	if err != nil {
+		return nil, err
+	}
+	required, err := s.Required(roles)
+	if err != nil {
+		return nil, err
+	}
+	status := &TwoFactorStatus{Enabled: enabled, Required: required}
+	if enabled {
+		if status.RecoveryCodesRemaining, err = s.repo.RemainingRecoveryCodes(userID); err != nil {
+			return nil, err
+		}
+	}
+	return status, nil
+}
+
+// Enroll 生成新的 TOTP 密钥（尚未启用），须调用 Confirm 提交一次验证码后生效。
+// 重复调用会替换尚未确认的密钥
+func (s *TwoFactorService) Enroll(userID uint, username, userType string) (*TwoFactorEnrollment, error) {
+	if userType != "employee" {
+		return nil, forbidden("two-factor authentication is only available for employee accounts")
+	}
+	var enrollment *TwoFactorEnrollment
+	err := s.store.Transaction(func(tx *repo.Tx) error {
+		tf, err := tx.TwoFactor.Get(userID)
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			tf = &models.TwoFactor{UserID: userID}
+		case err != nil:
+			return err
+		case tf.Enabled:
+			return conflict("two-factor authentication is already enabled")
+		}
+		secret, err := utils.GenerateTOTPSecret()
+		if err != nil {
+			return err
+		}
+		if tf.Secret, err = utils.EncryptString(s.key, secret); err != nil {
+			return err
+		}
+		tf.LastUsedStep = 0
+		enrollment = &TwoFactorEnrollment{
+			Secret:          secret,
+			ProvisioningURI: utils.TOTPProvisioningURI(s.issuer, username, secret),
+		}
+		return tx.TwoFactor.Save(tf)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return enrollment, nil
+}
+
+// Confirm 用身份验证器生成的验证码确认登记并启用双因素认证，返回一组恢复码（只显示这一次）
+func (s *TwoFactorService) Confirm(userID uint, code string) ([]string, error) {
+	var codes []string
+	err := s.store.Transaction(func(tx *repo.Tx) error {
+		tf, err := tx.TwoFactor.Get(userID)
+		if err != nil {
+			return notFound(err, "two-factor enrollment")
+		}
+		if tf.Enabled {
+			return conflict("two-factor authentication is already enabled")
+		}
+		codes, err = s.enable(tx, tf, code)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// Disable 关闭双因素认证，须提供验证码或恢复码；所属角色强制双因素认证时不能关闭
+func (s *TwoFactorService) Disable(userID uint, roles []string, code string) error {
+	required, err := s.Required(roles)
+	if err != nil {
+		return err
+	}
+	if required {
+		return forbidden("two-factor authentication is required for your role")
+	}
+	return s.store.Transaction(func(tx *repo.Tx) error {
+		tf, err := s.enabledFactor(tx, userID)
+		if err != nil {
+			return err
+		}
+		if err := s.verify(tx, tf, code); err != nil {
+			return err
+		}
+		_, err = tx.TwoFactor.Delete(userID)
+		return err
+	})
+}
+
+// RegenerateRecoveryCodes 作废旧恢复码并生成新的一组，须提供验证码或恢复码
+func (s *TwoFactorService) RegenerateRecoveryCodes(userID uint, code string) ([]string, error) {
+	var codes []string
+	err := s.store.Transaction(func(tx *repo.Tx) error {
+		tf, err := s.enabledFactor(tx, userID)
+		if err != nil {
+			return err
+		}
+		if err := s.verify(tx, tf, code); err != nil {
+			return err
+		}
+		codes, err = replaceRecoveryCodes(tx, userID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// Reset 由管理员清除账号的双因素认证（如丢失设备且恢复码用尽），下次登录时按角色要求重新登记
+func (s *TwoFactorService) Reset(userID uint) error {
+	ok, err := s.repo.Delete(userID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return notFound(gorm.ErrRecordNotFound, "two-factor settings")
+	}
+	return nil
+}
+
+// StartChallenge 密码验证通过后签发登录挑战令牌
+func (s *TwoFactorService) StartChallenge(userID uint) (string, error) {
+	raw, err := randomToken(32)
+	if err != nil {
+		return "", err
+	}
+	err = s.repo.CreateChallenge(&models.LoginChallenge{
+		TokenHash: hashToken(raw),
+		UserID:    userID,
+		ExpiresAt: time.Now().UTC().Add(s.challengeTTL),
+	})
+	if err != nil {
+		return "", err
+	}
+	return raw, nil
+}
+
+// ChallengeUser 返回登录挑战所属的账号，挑战已使用、过期或错误次数过多时返回 ErrUnauthorized
+func (s *TwoFactorService) ChallengeUser(token string) (uint, error) {
+	challenge, err := s.challenge(s.repo, token)
+	if err != nil {
+		return 0, err
+	}
+	return challenge.UserID, nil
+}
+
+// VerifyChallenge 校验登录挑战的第二因素并使挑战失效。
+// 强制登记流程中尚未启用的账号在此完成确认，同时返回新生成的恢复码
+func (s *TwoFactorService) VerifyChallenge(token, code string) ([]string, error) {
+	var challengeID uint
+	var codes []string
+	err := s.store.Transaction(func(tx *repo.Tx) error {
+		challenge, err := s.challenge(tx.TwoFactor, token)
+		if err != nil {
+			return err
+		}
+		challengeID = challenge.ID
+		tf, err := tx.TwoFactor.Get(challenge.UserID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return conflict("two-factor enrollment has not been started")
+		}
+		if err != nil {
+			return err
+		}
+		if tf.Enabled {
+			err = s.verify(tx, tf, code)
+		} else {
+			codes, err = s.enable(tx, tf, code)
+		}
+		if err != nil {
+			return err
+		}
+		ok, err := tx.TwoFactor.CompleteChallenge(challenge.ID, time.Now().UTC())
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return unauthorized("login challenge has already been used")
+		}
+		return nil
+	})
+	if errors.Is(err, errBadSecondFactor) && challengeID != 0 {
+		if ferr := s.repo.RecordChallengeFailure(challengeID); ferr != nil {
+			return nil, ferr
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+func (s *TwoFactorService) challenge(r *repo.TwoFactorRepository, token string) (*models.LoginChallenge, error) {
+	challenge, err := r.GetChallenge(hashToken(token))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, unauthorized("invalid or expired login challenge")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if challenge.UsedAt != nil || time.Now().UTC().After(challenge.ExpiresAt) || challenge.Attempts >= challengeMaxAttempts {
+		return nil, unauthorized("invalid or expired login challenge, please log in again")
+	}
+	return challenge, nil
+}
+
+func (s *TwoFactorService) enabledFactor(tx *repo.Tx, userID uint) (*models.TwoFactor, error) {
+	tf, err := tx.TwoFactor.Get(userID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if err != nil || !tf.Enabled {
+		return nil, conflict("two-factor authentication is not enabled")
+	}
+	return tf, nil
+}
+
+// enable 校验首个验证码后启用，并生成恢复码
+func (s *TwoFactorService) enable(tx *repo.Tx, tf *models.TwoFactor, code string) ([]string, error) {
+	if err := s.verifyTOTP(tx, tf, code); err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	tf.Enabled, tf.ConfirmedAt = true, &now
+	if err := tx.TwoFactor.Save(tf); err != nil {
+		return nil, err
+	}
+	return replaceRecoveryCodes(tx, tf.UserID)
+}
+
+// verify 接受 TOTP 验证码或未使用的恢复码
+func (s *TwoFactorService) verify(tx *repo.Tx, tf *models.TwoFactor, code string) error {
+	if err := s.verifyTOTP(tx, tf, code); !errors.Is(err, errBadSecondFactor) {
+		return err
+	}
+	ok, err := tx.TwoFactor.UseRecoveryCode(tf.UserID, hashRecoveryCode(code), time.Now().UTC())
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errBadSecondFactor
+	}
+	return nil
+}
+
+// verifyTOTP 校验验证码；同一时间步的验证码只能使用一次
+func (s *TwoFactorService) verifyTOTP(tx *repo.Tx, tf *models.TwoFactor, code string) error {
+	secret, err := utils.DecryptString(s.key, tf.Secret)
+	if err != nil {
+		return errors.New("failed to decrypt two-factor secret")
+	}
+	step, ok := utils.ValidateTOTP(secret, code, time.Now(), totpSkew)
+	if !ok {
+		return errBadSecondFactor
+	}
+	used, err := tx.TwoFactor.UseStep(tf.UserID, step)
+	if err != nil {
+		return err
+	}
+	if !used {
+		return errBadSecondFactor
+	}
+	tf.LastUsedStep = step
+	return nil
+}
+
+// replaceRecoveryCodes 生成一组形如 xxxxx-xxxxx 的恢复码，只保存哈希
+func replaceRecoveryCodes(tx *repo.Tx, userID uint) ([]string, error) {
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+	for i := range codes {
+		b := make([]byte, 7)
+		if _, err := rand.Read(b); err != nil {
+			return nil, err
+		}
+		raw := strings.ToLower(base32.StdEncoding.EncodeToString(b))[:10]
+		codes[i] = raw[:5] + "-" + raw[5:]
+		hashes[i] = hashRecoveryCode(codes[i])
+	}
+	if err := tx.TwoFactor.ReplaceRecoveryCodes(userID, hashes); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// hashRecoveryCode 忽略大小写、空格与连字符后取哈希
+func hashRecoveryCode(code string) string {
+	normalized := strings.NewReplacer("-", "", " ", "").Replace(strings.ToLower(strings.TrimSpace(code)))
+	return hashToken(normalized)
+}
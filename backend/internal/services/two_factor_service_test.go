@@ -0,0 +1,129 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"erp-backend/internal/models"
+	"erp-backend/internal/repo"
+	"erp-backend/pkg/utils"
+)
+
+// newEnrolledTwoFactor 创建一个已启用双因素认证的员工账号，返回服务、账号、TOTP 密钥与恢复码
+func newEnrolledTwoFactor(t *testing.T) (*TwoFactorService, uint, string, []string) {
+	t.Helper()
+	db := openServiceTestDB(t)
+	user := &models.User{Username: "alice", PasswordHash: "x", UserType: "employee", Status: "active"}
+	mustCreate(t, db, user)
+	svc := NewTwoFactorService(repo.NewTwoFactorRepository(db), repo.NewStore(db), NewRBACService(repo.NewRBACRepository(db)),
+		[]byte("0123456789abcdef0123456789abcdef"), "ERP", 5*time.Minute)
+
+	enrollment, err := svc.Enroll(user.ID, user.Username, user.UserType)
+	if err != nil {
+		t.Fatal(err)
+	}
+	codes, err := svc.Confirm(user.ID, totpAt(t, enrollment.Secret, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(codes) != recoveryCodeCount {
+		t.Fatalf("Confirm returned %d recovery codes, want %d", len(codes), recoveryCodeCount)
+	}
+	return svc, user.ID, enrollment.Secret, codes
+}
+
+// totpAt 返回当前时间步偏移 offset 步的验证码
+func totpAt(t *testing.T, secret string, offset int64) string {
+	t.Helper()
+	code, err := utils.TOTPCode(secret, utils.TOTPStep(time.Now())+offset)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return code
+}
+
+// login 走一次两步登录，返回第二步的结果
+func login(t *testing.T, svc *TwoFactorService, userID uint, code string) error {
+	t.Helper()
+	token, err := svc.StartChallenge(userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = svc.VerifyChallenge(token, code)
+	return err
+}
+
+func TestTwoFactorTOTPStepsAreSingleUse(t *testing.T) {
+	svc, userID, secret, _ := newEnrolledTwoFactor(t)
+
+	steps := []struct {
+		name string
+		code string
+		ok   bool
+	}{
+		{"code already used to confirm", totpAt(t, secret, 0), false},
+		{"code outside the window", totpAt(t, secret, 3), false},
+		{"next step within the skew", totpAt(t, secret, 1), true},
+		{"same step again", totpAt(t, secret, 1), false},
+		{"earlier step", totpAt(t, secret, 0), false},
+		{"malformed", "12345", false},
+	}
+	for _, step := range steps {
+		err := login(t, svc, userID, step.code)
+		if step.ok && err != nil {
+			t.Errorf("%s: VerifyChallenge = %v, want nil", step.name, err)
+		}
+		if !step.ok && !errors.Is(err, ErrUnauthorized) {
+			t.Errorf("%s: VerifyChallenge = %v, want ErrUnauthorized", step.name, err)
+		}
+	}
+}
+
+func TestTwoFactorRecoveryCodesAreSingleUse(t *testing.T) {
+	svc, userID, _, codes := newEnrolledTwoFactor(t)
+
+	// 恢复码忽略大小写与连字符
+	if err := login(t, svc, userID, strings.ToUpper(strings.ReplaceAll(codes[0], "-", ""))); err != nil {
+		t.Fatalf("first use of a recovery code: %v", err)
+	}
+	if err := login(t, svc, userID, codes[0]); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("second use of a recovery code = %v, want ErrUnauthorized", err)
+	}
+	status, err := svc.Status(userID, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.RecoveryCodesRemaining != recoveryCodeCount-1 {
+		t.Errorf("%d recovery codes remain, want %d", status.RecoveryCodesRemaining, recoveryCodeCount-1)
+	}
+
+	// 重新生成后旧恢复码全部作废
+	fresh, err := svc.RegenerateRecoveryCodes(userID, codes[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := login(t, svc, userID, codes[2]); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("recovery code from the replaced set = %v, want ErrUnauthorized", err)
+	}
+	if err := login(t, svc, userID, fresh[0]); err != nil {
+		t.Errorf("recovery code from the new set: %v", err)
+	}
+}
+
+func TestTwoFactorChallengeLocksAfterFailedAttempts(t *testing.T) {
+	svc, userID, secret, _ := newEnrolledTwoFactor(t)
+	token, err := svc.StartChallenge(userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < challengeMaxAttempts; i++ {
+		if _, err := svc.VerifyChallenge(token, "000000"); !errors.Is(err, ErrUnauthorized) {
+			t.Fatalf("attempt %d: VerifyChallenge = %v, want ErrUnauthorized", i+1, err)
+		}
+	}
+	if _, err := svc.VerifyChallenge(token, totpAt(t, secret, 1)); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("correct code after %d failures = %v, want ErrUnauthorized", challengeMaxAttempts, err)
+	}
+}
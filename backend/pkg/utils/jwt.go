@@ -10,15 +10,20 @@ import (
 
 // Claims JWT声明结构
 type Claims struct {
-	UserID   uint   `json:"user_id"`
-	Username string `json:"username"`
-	UserType string `json:"user_type"`
-	RoleID   uint   `json:"role_id"`
+	UserID    uint     `json:"user_id"`
+	Username  string   `json:"username"`
+	UserType  string   `json:"user_type"`
+	RoleID    uint     `json:"role_id"`
+	Roles     []string `json:"roles"`
+	SessionID string   `json:"sid"` // 所属登录会话，会话终止后令牌随之失效
 	jwt.RegisteredClaims
 }
 
 var jwtSecret []byte
 
+// AccessTokenTTL 访问令牌有效期；过期后客户端使用刷新令牌换取新令牌
+var AccessTokenTTL = 15 * time.Minute
+
 func init() {
 	secret := os.Getenv("JWT_SECRET")
 	if secret == "" {
@@ -27,16 +32,17 @@ func init() {
 	jwtSecret = []byte(secret)
 }
 
-// GenerateToken 生成JWT token
-func GenerateToken(userID uint, username, userType string, roleID uint) (string, error) {
-	// 设置过期时间为24小时
-	expirationTime := time.Now().Add(24 * time.Hour)
+// GenerateToken 生成JWT token，roles 为用户的 RBAC 角色名，sessionID 为所属登录会话
+func GenerateToken(userID uint, username, userType string, roleID uint, roles []string, sessionID string) (string, error) {
+	expirationTime := time.Now().Add(AccessTokenTTL)
 
 	claims := &Claims{
-		UserID:   userID,
-		Username: username,
-		UserType: userType,
-		RoleID:   roleID,
+		UserID:    userID,
+		Username:  username,
+		UserType:  userType,
+		RoleID:    roleID,
+		Roles:     roles,
+		SessionID: sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(expirationTime),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
@@ -74,18 +80,3 @@ func ValidateToken(tokenString string) (*Claims, error) {
 
 	return claims, nil
 }
-
-// RefreshToken 刷新token
-func RefreshToken(tokenString string) (string, error) {
-	claims, err := ValidateToken(tokenString)
-	if err != nil {
-		return "", err
-	}
-
-	// 如果token即将在30分钟内过期，则刷新
-	if time.Until(claims.ExpiresAt.Time) > 30*time.Minute {
-		return tokenString, nil
-	}
-
-	return GenerateToken(claims.UserID, claims.Username, claims.UserType, claims.RoleID)
-}
@@ -0,0 +1,69 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+// rfc6238Secret RFC 6238 附录 B 的 SHA-1 测试密钥 "12345678901234567890"（Base32）
+const rfc6238Secret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestTOTPCodeMatchesRFC6238(t *testing.T) {
+	// RFC 给出 8 位验证码，这里取其后 6 位
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+	for _, tt := range tests {
+		got, err := TOTPCode(rfc6238Secret, TOTPStep(time.Unix(tt.unix, 0)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("TOTPCode at %d = %s, want %s", tt.unix, got, tt.want)
+		}
+	}
+}
+
+func TestValidateTOTPWindow(t *testing.T) {
+	now := time.Unix(1234567890, 0)
+	step := TOTPStep(now)
+	tests := []struct {
+		offset int64
+		skew   int
+		ok     bool
+	}{
+		{0, 0, true},
+		{-1, 0, false},
+		{-1, 1, true},
+		{1, 1, true},
+		{-2, 1, false},
+		{2, 1, false},
+	}
+	for _, tt := range tests {
+		code, err := TOTPCode(rfc6238Secret, step+tt.offset)
+		if err != nil {
+			t.Fatal(err)
+		}
+		matched, ok := ValidateTOTP(rfc6238Secret, code, now, tt.skew)
+		if ok != tt.ok {
+			t.Errorf("code for step %+d with skew %d: ok = %v, want %v", tt.offset, tt.skew, ok, tt.ok)
+		}
+		if ok && matched != step+tt.offset {
+			t.Errorf("code for step %+d matched step %d, want %d", tt.offset, matched, step+tt.offset)
+		}
+	}
+
+	for _, code := range []string{"", "12345", "1234567", "abcdef"} {
+		if _, ok := ValidateTOTP(rfc6238Secret, code, now, 1); ok {
+			t.Errorf("ValidateTOTP accepted malformed code %q", code)
+		}
+	}
+}
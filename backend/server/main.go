@@ -1,20 +1,33 @@
 package main
 
 import (
+	"fmt"
 	"log"
 	"net/http"
 	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
 
 	"erp-backend/internal/config"
 	"erp-backend/internal/handlers"
 	"erp-backend/internal/middleware"
+	"erp-backend/internal/models"
 	"erp-backend/internal/repo"
 	"erp-backend/internal/services"
+	"erp-backend/pkg/utils"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm/logger"
 )
 
 func main() {
+	// 数据库迁移命令：migrate up | down [n] | status
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		os.Exit(runMigrate(os.Args[2:]))
+	}
+
 	//config log path
 	logFile, err := os.OpenFile("./server.log", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0666)
 	if err != nil {
@@ -24,10 +37,10 @@ func main() {
 
 	// 加载配置
 	cfg := config.Load()
-	log.Printf("Loaded configuration: Port=%s, DB_Path=%s", cfg.Port, cfg.DB_Path)
+	log.Printf("Loaded configuration: Port=%s, DB_Driver=%s", cfg.Port, cfg.DB_Driver)
 
 	// 初始化数据库
-	if err := repo.InitDatabase(cfg.DB_Path); err != nil {
+	if err := repo.InitDatabase(databaseConfig(cfg)); err != nil {
 		log.Fatal("Failed to initialize database:", err)
 	}
 
@@ -60,42 +73,146 @@ func main() {
 	scheduleRepo := repo.NewScheduleRepository(db)
 
 	chartRepo := repo.NewChartRepository(db)
+	ledgerRepo := repo.NewLedgerRepository(db)
+	approvalRepo := repo.NewApprovalRepository(db)
+	rbacRepo := repo.NewRBACRepository(db)
+	sessionRepo := repo.NewSessionRepository(db)
+	registrationRepo := repo.NewRegistrationRepository(db)
+	loginAttemptRepo := repo.NewLoginAttemptRepository(db)
+	twoFactorRepo := repo.NewTwoFactorRepository(db)
+	auditRepo := repo.NewAuditRepository(db)
+	trashRepo := repo.NewTrashRepository(db)
+	importRepo := repo.NewImportRepository(db)
+	exportRepo := repo.NewExportRepository(db)
+	receiptRepo := repo.NewReceiptRepository(db)
+	costingRepo := repo.NewCostingRepository(db)
+
+	// 跨表写入（如捐赠过账）使用的事务入口
+	store := repo.NewStore(db)
 
 	// 初始化 Services
-	// AuthService 依赖多个 Repository (userRepo, employeeRepo, volunteerRepo, donorRepo)
-	authService := services.NewAuthService(userRepo, employeeRepo, volunteerRepo, donorRepo)
+	// 访问令牌短期有效，登录会话通过刷新令牌续期；撤销列表供鉴权中间件检查
+	utils.AccessTokenTTL = cfg.Access_Token_TTL
+	sessionService := services.NewSessionService(sessionRepo, store, cfg.Refresh_Token_TTL)
+	if err := sessionService.LoadRevoked(); err != nil {
+		log.Fatal("Failed to load revoked sessions:", err)
+	}
+	middleware.SetSessionValidator(sessionService)
+
+	// 用户通知（注册审核结果、密码重置邮件）：配置了 SMTP 时发送邮件，否则写入日志文件
+	var notifier services.Notifier
+	if cfg.SMTP_Host != "" {
+		notifier = services.NewSMTPNotifier(cfg.SMTP_Host, cfg.SMTP_Port, cfg.SMTP_Username, cfg.SMTP_Password, cfg.SMTP_From)
+	} else {
+		logNotifier, err := services.NewLogNotifier(cfg.Notification_Log)
+		if err != nil {
+			log.Fatal("Failed to open notification log:", err)
+		}
+		notifier = logNotifier
+	}
+
+	passwordPolicy := &services.PasswordPolicy{
+		MinLength:     cfg.Password_Min_Length,
+		RequireUpper:  cfg.Password_Require_Upper,
+		RequireLower:  cfg.Password_Require_Lower,
+		RequireDigit:  cfg.Password_Require_Digit,
+		RequireSymbol: cfg.Password_Require_Symbol,
+	}
+	if cfg.Password_Breached_List != "" {
+		if err := passwordPolicy.LoadBreachedList(cfg.Password_Breached_List); err != nil {
+			log.Fatal("Failed to load breached password list:", err)
+		}
+	}
+	passwordService := services.NewPasswordService(userRepo, store, sessionService, passwordPolicy, notifier,
+		cfg.Password_Reset_TTL, strings.TrimRight(cfg.Public_URL, "/")+"/reset-password")
+
+	// 登录失败计数与锁定阈值
+	lockoutService := services.NewLockoutService(loginAttemptRepo, store, services.LockoutPolicy{
+		MaxFailures:   cfg.Login_Max_Failures,
+		BaseLockout:   cfg.Login_Lockout_Base,
+		MaxLockout:    cfg.Login_Lockout_Max,
+		IPMaxFailures: cfg.Login_IP_Max_Failures,
+		IPWindow:      cfg.Login_IP_Window,
+	})
+
+	// 双因素认证；TOTP 密钥以 ENCRYPT_SEED 派生的密钥加密保存
+	rbacService := services.NewRBACService(rbacRepo)
+	twoFactorService := services.NewTwoFactorService(twoFactorRepo, store, rbacService,
+		utils.DeriveKey(cfg.Encrypt_Seed), cfg.Two_Factor_Issuer, cfg.Two_Factor_Challenge_TTL)
+
+	// AuthService 依赖多个 Repository (userRepo, employeeRepo, volunteerRepo, donorRepo, rbacRepo)
+	authService := services.NewAuthService(userRepo, employeeRepo, volunteerRepo, donorRepo, rbacRepo, sessionService, passwordService, lockoutService, twoFactorService)
 	chartService := services.NewChartService(chartRepo)
 	donService := services.NewDonService(donorRepo, projectRepo, employeeProjectRepo)
+	ledgerService := services.NewLedgerService(ledgerRepo, store)
+	fundAccountingService := services.NewFundAccountingService(fundRepo, projectRepo)
+	projectScopeService := services.NewProjectScopeService(employeeProjectRepo, rbacService)
+
+	registrationService := services.NewRegistrationService(registrationRepo, store, sessionService, notifier)
+	auditService := services.NewAuditService(auditRepo)
+	trashService := services.NewTrashService(trashRepo, store)
+	importService := services.NewImportService(importRepo, store)
+	exportService := services.NewExportService(exportRepo)
+
+	// 年度捐赠收据上的机构信息
+	receiptService := services.NewReceiptService(receiptRepo, store, models.ReceiptOrganisation{
+		Name:      cfg.Org_Name,
+		Address:   cfg.Org_Address,
+		TaxID:     cfg.Org_Tax_ID,
+		Contact:   cfg.Org_Contact,
+		Signatory: cfg.Receipt_Signatory,
+	})
+	costingService := services.NewCostingService(costingRepo)
+
+	// 路由鉴权使用 RBAC 权限判断；ADMIN_USERS 中的账号启动时确保拥有 admin 角色
+	middleware.SetPermissionChecker(rbacService)
+	if err := rbacService.EnsureAdmins(userRepo, strings.Split(cfg.Admin_Users, ",")); err != nil {
+		log.Fatal("Failed to assign admin role:", err)
+	}
 
 	// 其他 Services 现在都依赖各自的 Repository
 	userService := services.NewUserService(userRepo)
 	projectService := services.NewProjectService(projectRepo)
 	donorService := services.NewDonorService(donorRepo)
-	donationService := services.NewDonationService(donationRepo)
+	donationService := services.NewDonationService(donationRepo, store)
 	volunteerService := services.NewVolunteerService(volunteerRepo)
 	employeeService := services.NewEmployeeService(employeeRepo)
 	locationService := services.NewLocationService(locationRepo)
 	fundService := services.NewFundService(fundRepo)
-	expenseService := services.NewExpenseService(expenseRepo)
+	expenseService := services.NewExpenseService(expenseRepo, approvalRepo, store)
 	transactionService := services.NewTransactionService(transactionRepo)
-	purchaseService := services.NewPurchaseService(purchaseRepo)
-	payrollService := services.NewPayrollService(payrollRepo)
-	inventoryService := services.NewInventoryService(inventoryRepo)
+	purchaseService := services.NewPurchaseService(purchaseRepo, store)
+	payrollService := services.NewPayrollService(payrollRepo, store)
+	inventoryService := services.NewInventoryService(inventoryRepo, store)
 	giftTypeService := services.NewGiftTypeService(giftTypeRepo)
 	giftService := services.NewGiftService(giftRepo)
-	inventoryTransactionService := services.NewInventoryTransactionService(inventoryTransactionRepo)
-	deliveryService := services.NewDeliveryService(deliveryRepo)
+	inventoryTransactionService := services.NewInventoryTransactionService(inventoryTransactionRepo, store)
+	deliveryService := services.NewDeliveryService(deliveryRepo, store)
 	volunteerProjectService := services.NewVolunteerProjectService(volunteerProjectRepo)
 	employeeProjectService := services.NewEmployeeProjectService(employeeProjectRepo)
-	fundProjectService := services.NewFundProjectService(fundProjectRepo)
-	donationInventoryService := services.NewDonationInventoryService(donationInventoryRepo)
-	deliveryInventoryService := services.NewDeliveryInventoryService(deliveryInventoryRepo)
+	fundProjectService := services.NewFundProjectService(fundProjectRepo, store)
+	donationInventoryService := services.NewDonationInventoryService(donationInventoryRepo, store)
+	deliveryInventoryService := services.NewDeliveryInventoryService(deliveryInventoryRepo, store)
 	scheduleService := services.NewScheduleService(scheduleRepo)
 
 	// 初始化 Handlers
 	authHandler := handlers.NewAuthHandler(authService)
 	chartHandler := handlers.NewChartHandler(chartService)
 	donHandler := handlers.NewDonHandler(donService)
+	ledgerHandler := handlers.NewLedgerHandler(ledgerService)
+	fundAccountingHandler := handlers.NewFundAccountingHandler(fundAccountingService)
+	rbacHandler := handlers.NewRBACHandler(rbacService)
+	sessionHandler := handlers.NewSessionHandler(sessionService)
+	registrationHandler := handlers.NewRegistrationHandler(registrationService)
+	passwordHandler := handlers.NewPasswordHandler(passwordService)
+	lockoutHandler := handlers.NewLockoutHandler(lockoutService)
+	twoFactorHandler := handlers.NewTwoFactorHandler(authService, twoFactorService)
+	auditHandler := handlers.NewAuditHandler(auditService)
+	trashHandler := handlers.NewTrashHandler(trashService)
+	importHandler := handlers.NewImportHandler(importService)
+	exportHandler := handlers.NewExportHandler(exportService)
+	receiptHandler := handlers.NewReceiptHandler(receiptService)
+	costingHandler := handlers.NewCostingHandler(costingService)
 
 	erpHandler := handlers.NewERPHandler(
 		userService,
@@ -168,6 +285,7 @@ func main() {
 		// 认证页面
 		public.GET("/login", authHandler.ShowLoginPage)
 		public.GET("/register", authHandler.ShowRegisterPage)
+		public.GET("/reset-password", authHandler.ShowResetPasswordPage)
 
 		// ERP 管理页面（公开访问，前端 JS 会验证 token）
 		public.GET("/erp-management", func(c *gin.Context) {
@@ -207,6 +325,11 @@ func main() {
 		// 兼容旧版 API 路径
 		public.POST("/api/v1/auth/register", authHandler.Register)
 		public.POST("/api/v1/auth/login", authHandler.Login)
+		public.POST("/api/v1/auth/refresh", authHandler.Refresh)
+		public.POST("/api/v1/auth/password/forgot", passwordHandler.ForgotPassword)
+		public.POST("/api/v1/auth/password/reset", passwordHandler.ResetPassword)
+		public.POST("/api/v1/auth/2fa/login/setup", twoFactorHandler.SetupLogin)
+		public.POST("/api/v1/auth/2fa/login/verify", twoFactorHandler.VerifyLogin)
 	}
 
 	// 需要认证的路由
@@ -214,24 +337,40 @@ func main() {
 	authenticated.Use(middleware.AuthMiddlewareGin())
 	{
 		authenticated.POST("/auth/logout", authHandler.Logout)
+		authenticated.POST("/api/v1/auth/logout", authHandler.Logout)
+		authenticated.POST("/api/v1/auth/password/change", passwordHandler.ChangePassword)
+		authenticated.GET("/api/v1/auth/2fa", twoFactorHandler.GetStatus)
+		authenticated.POST("/api/v1/auth/2fa/enroll", twoFactorHandler.Enroll)
+		authenticated.POST("/api/v1/auth/2fa/confirm", twoFactorHandler.Confirm)
+		authenticated.POST("/api/v1/auth/2fa/disable", twoFactorHandler.Disable)
+		authenticated.POST("/api/v1/auth/2fa/recovery-codes", twoFactorHandler.RegenerateRecoveryCodes)
 	}
 
 	admin_api := r.Group("/api/v1/dbms/users")
 	admin_api.Use(middleware.AuthMiddlewareGin())
 	admin_api.Use(middleware.AuthVarifyUserType("employee"))
+	admin_api.Use(middleware.RequireResourcePermission("/api/v1/dbms"))
 	{
 		// user management
 		admin_api.POST("/", erpHandler.CreateUser)
 		admin_api.GET("/", erpHandler.GetAllUsers)
 		admin_api.GET("/search", erpHandler.FilterUsers)
+		admin_api.GET("/export", exportHandler.Export)
 		admin_api.PUT("/:id", erpHandler.UpdateUser)
 		admin_api.DELETE("/:id", erpHandler.DeleteUser)
+
+		// 回收站
+		admin_api.GET("/trash", trashHandler.List)
+		admin_api.POST("/:id/restore", trashHandler.Restore)
+		admin_api.DELETE("/:id/purge", middleware.RequirePermission(models.ResourceTrash, models.ActionPurge), trashHandler.Purge)
 	}
 
 	// Financial Charts API for employee dashboard
 	finchart_api := r.Group("/api/v1/fin/charts")
 	finchart_api.Use(middleware.AuthMiddlewareGin())
 	finchart_api.Use(middleware.AuthVarifyUserType("employee"))
+	finchart_api.Use(middleware.RequirePermission("charts", "read"))
+	finchart_api.Use(middleware.ResolveProjectScope(projectScopeService))
 	{
 		finchart_api.GET("/line/fund", chartHandler.FundAllocations)
 		finchart_api.GET("/pie/fund", chartHandler.FundAllocationsByProject)
@@ -242,6 +381,99 @@ func main() {
 
 	}
 
+	// General ledger API (double-entry books behind transactions)
+	ledger_api := r.Group("/api/v1/fin/ledger")
+	ledger_api.Use(middleware.AuthMiddlewareGin())
+	ledger_api.Use(middleware.AuthVarifyUserType("employee"))
+	ledger_api.Use(middleware.RequireResourcePermission("/api/v1/fin"))
+	{
+		ledger_api.GET("/accounts", ledgerHandler.GetAccounts)
+		ledger_api.POST("/accounts", ledgerHandler.CreateAccount)
+		ledger_api.GET("/accounts/:id/activity", ledgerHandler.AccountActivity)
+		ledger_api.GET("/entries", ledgerHandler.GetEntries)
+		ledger_api.POST("/entries", ledgerHandler.PostEntry)
+		ledger_api.GET("/trial-balance", ledgerHandler.TrialBalance)
+	}
+
+	// Fund accounting API (net assets and spend pre-checks)
+	fund_api := r.Group("/api/v1/fin/funds")
+	fund_api.Use(middleware.AuthMiddlewareGin())
+	fund_api.Use(middleware.AuthVarifyUserType("employee"))
+	fund_api.Use(middleware.RequireResourcePermission("/api/v1/fin"))
+	{
+		fund_api.GET("/net-assets", fundAccountingHandler.NetAssets)
+		fund_api.GET("/:id/availability", fundAccountingHandler.CheckAvailability)
+	}
+
+	// Annual donor tax receipts: bulk generation, void and reissue
+	receipt_api := r.Group("/api/v1/fin/receipts")
+	receipt_api.Use(middleware.AuthMiddlewareGin())
+	receipt_api.Use(middleware.AuthVarifyUserType("employee"))
+	receipt_api.Use(middleware.RequireResourcePermission("/api/v1/fin"))
+	{
+		receipt_api.GET("", receiptHandler.List)
+		receipt_api.POST("/generate", receiptHandler.Generate)
+		receipt_api.GET("/:id/pdf", receiptHandler.Download)
+		receipt_api.POST("/:id/void", receiptHandler.Void)
+		receipt_api.POST("/:id/reissue", receiptHandler.Reissue)
+	}
+
+	// Inventory costing: cost method per category, valuation and cost of distributions
+	costing_api := r.Group("/api/v1/fin/inventory")
+	costing_api.Use(middleware.AuthMiddlewareGin())
+	costing_api.Use(middleware.AuthVarifyUserType("employee"))
+	costing_api.Use(middleware.RequireResourcePermission("/api/v1/fin"))
+	{
+		costing_api.GET("/cost-methods", costingHandler.GetCostMethods)
+		costing_api.PUT("/cost-methods", costingHandler.SetCostMethod)
+		costing_api.GET("/valuation", costingHandler.Valuation)
+		costing_api.GET("/distribution-cost", middleware.ResolveProjectScope(projectScopeService), costingHandler.DistributionCost)
+	}
+
+	// Audit log (read-only; entries are written by database callbacks)
+	audit_api := r.Group("/api/v1/audit")
+	audit_api.Use(middleware.AuthMiddlewareGin())
+	audit_api.Use(middleware.AuthVarifyUserType("employee"))
+	audit_api.Use(middleware.RequireResourcePermission("/api/v1"))
+	{
+		audit_api.GET("", auditHandler.GetLogs)
+	}
+
+	// Role and permission management
+	rbac_api := r.Group("/api/v1/admin")
+	rbac_api.Use(middleware.AuthMiddlewareGin())
+	rbac_api.Use(middleware.AuthVarifyUserType("employee"))
+	rbac_api.Use(middleware.RequireResourcePermission("/api/v1/admin"))
+	{
+		rbac_api.GET("/roles", rbacHandler.GetRoles)
+		rbac_api.POST("/roles", rbacHandler.CreateRole)
+		rbac_api.PUT("/roles/:id", rbacHandler.UpdateRole)
+		rbac_api.DELETE("/roles/:id", rbacHandler.DeleteRole)
+		rbac_api.GET("/permissions", rbacHandler.GetPermissions)
+		rbac_api.GET("/user-roles/:id", rbacHandler.GetUserRoles)
+		rbac_api.PUT("/user-roles/:id", rbacHandler.SetUserRoles)
+
+		// 登录会话
+		rbac_api.GET("/sessions", sessionHandler.GetSessions)
+		rbac_api.DELETE("/sessions", sessionHandler.RevokeUserSessions)
+		rbac_api.DELETE("/sessions/:id", sessionHandler.RevokeSession)
+
+		// 员工注册审核
+		rbac_api.GET("/registrations", registrationHandler.GetPending)
+		rbac_api.GET("/registrations/reviews", registrationHandler.GetReviews)
+		rbac_api.POST("/registrations/:id/approve", registrationHandler.Approve)
+		rbac_api.POST("/registrations/:id/reject", registrationHandler.Reject)
+
+		// 登录记录与账号锁定
+		rbac_api.GET("/login-attempts", lockoutHandler.GetAttempts)
+		rbac_api.GET("/lockouts", lockoutHandler.GetLockouts)
+		rbac_api.DELETE("/lockouts/:id", lockoutHandler.Unlock)
+
+		// 双因素认证：按角色强制，或为丢失设备的账号清除
+		rbac_api.PUT("/roles/:id/two-factor", rbacHandler.SetRoleTwoFactor)
+		rbac_api.DELETE("/two-factor/:id", twoFactorHandler.Reset)
+	}
+
 	//Donation Charts API for donor dashboard
 	don_api := r.Group("/api/v1/donor")
 	don_api.Use(middleware.AuthMiddlewareGin())
@@ -251,12 +483,17 @@ func main() {
 		don_api.GET("/charts/pie/donations", chartHandler.DonorDonationsByProject)
 		don_api.GET("/projects", donHandler.GetProjectsByDonor)
 		don_api.GET("/donations", donHandler.GetDonationDetails)
+		don_api.GET("/receipts", receiptHandler.DonorList)
+		don_api.GET("/receipts/:year", receiptHandler.DonorDownload)
 	}
 
 	// dbms API for employee
 	dbms_api := r.Group("/api/v1/dbms")
 	dbms_api.Use(middleware.AuthMiddlewareGin())
 	dbms_api.Use(middleware.AuthVarifyUserType("employee"))
+	dbms_api.Use(middleware.RequireResourcePermission("/api/v1/dbms"))
+	// 与项目关联的数据（捐赠、支出、拨款、排班、志愿者分配）只对项目成员开放
+	dbms_api.Use(middleware.ResolveProjectScope(projectScopeService))
 
 	{
 
@@ -315,6 +552,14 @@ func main() {
 		dbms_api.GET("/expenses/search", erpHandler.FilterExpenses)
 		dbms_api.PUT("/expenses/:id", erpHandler.UpdateExpense)
 		dbms_api.DELETE("/expenses/:id", erpHandler.DeleteExpense)
+		dbms_api.POST("/expenses/:id/submit", erpHandler.SubmitExpense)
+		dbms_api.POST("/expenses/:id/approve", erpHandler.ApproveExpense)
+		dbms_api.POST("/expenses/:id/reject", erpHandler.RejectExpense)
+		dbms_api.POST("/expenses/:id/pay", erpHandler.PayExpense)
+		dbms_api.GET("/expenses/:id/approvals", erpHandler.GetExpenseApprovals)
+		dbms_api.GET("/approval-thresholds", erpHandler.GetApprovalThresholds)
+		dbms_api.PUT("/approval-thresholds", erpHandler.SaveApprovalThreshold)
+		dbms_api.DELETE("/approval-thresholds/:id", erpHandler.DeleteApprovalThreshold)
 
 		// 交易管理
 		dbms_api.POST("/transactions", erpHandler.CreateTransaction)
@@ -341,6 +586,7 @@ func main() {
 		dbms_api.POST("/inventory", erpHandler.CreateInventory)
 		dbms_api.GET("/inventory", erpHandler.GetAllInventories)
 		dbms_api.GET("/inventory/search", erpHandler.FilterInventories)
+		dbms_api.GET("/inventory/reconcile", erpHandler.ReconcileInventory)
 		dbms_api.PUT("/inventory/:id", erpHandler.UpdateInventory)
 		dbms_api.DELETE("/inventory/:id", erpHandler.DeleteInventory)
 
@@ -413,6 +659,35 @@ func main() {
 		dbms_api.GET("/schedules/search", erpHandler.FilterSchedules)
 		dbms_api.PUT("/schedules/:id", erpHandler.UpdateSchedule)
 		dbms_api.DELETE("/schedules/:id", erpHandler.DeleteSchedule)
+
+		// 回收站：GET /<resource>/trash 查看（<resource>:read），POST /<resource>/:id/restore 恢复（<resource>:restore），
+		// DELETE /<resource>/:id/purge 彻底删除（另需 trash:purge，默认只有 admin）
+		for _, resource := range repo.TrashResources() {
+			if resource == "users" {
+				continue // 见 admin_api
+			}
+			dbms_api.GET("/"+resource+"/trash", trashHandler.List)
+			dbms_api.POST("/"+resource+"/:id/restore", trashHandler.Restore)
+			dbms_api.DELETE("/"+resource+"/:id/purge", middleware.RequirePermission(models.ResourceTrash, models.ActionPurge), trashHandler.Purge)
+		}
+
+		// 导出：GET /<resource>/export?format=csv|xlsx|json（<resource>:read），过滤参数与 search 相同
+		for _, resource := range repo.ExportResources() {
+			if resource == "users" {
+				continue // 见 admin_api
+			}
+			dbms_api.GET("/"+resource+"/export", exportHandler.Export)
+		}
+
+		// 批量导入：POST /<resource>/import 导入 CSV/XLSX（<resource>:create），
+		// /<resource>/import-mappings 管理列映射模板，GET /<resource>/imports/:id/errors 下载错误报告
+		for _, resource := range services.ImportResources() {
+			dbms_api.POST("/"+resource+"/import", importHandler.Import)
+			dbms_api.GET("/"+resource+"/import-mappings", importHandler.ListMappings)
+			dbms_api.POST("/"+resource+"/import-mappings", importHandler.SaveMapping)
+			dbms_api.DELETE("/"+resource+"/import-mappings/:id", importHandler.DeleteMapping)
+			dbms_api.GET("/"+resource+"/imports/:id/errors", importHandler.ErrorReport)
+		}
 	}
 
 	// 启动服务器（使用配置中的端口）
@@ -421,3 +696,93 @@ func main() {
 		log.Fatal("Failed to start server:", err)
 	}
 }
+
+// databaseConfig 由配置得到数据库连接设置；SQLite 未设置 DB_DSN 时使用 DB_PATH
+func databaseConfig(cfg *config.Config) repo.DatabaseConfig {
+	dsn := cfg.DB_DSN
+	if dsn == "" && (cfg.DB_Driver == "" || cfg.DB_Driver == repo.DriverSQLite) {
+		dsn = cfg.DB_Path
+	}
+	return repo.DatabaseConfig{
+		Driver:          cfg.DB_Driver,
+		DSN:             dsn,
+		MaxOpenConns:    cfg.DB_Max_Open_Conns,
+		MaxIdleConns:    cfg.DB_Max_Idle_Conns,
+		ConnMaxLifetime: cfg.DB_Conn_Max_Lifetime,
+		ConnMaxIdleTime: cfg.DB_Conn_Max_Idle_Time,
+	}
+}
+
+// runMigrate 执行数据库迁移命令，返回进程退出码
+func runMigrate(args []string) int {
+	const usage = "usage: migrate up | down [n] | status"
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, usage)
+		return 2
+	}
+
+	cfg := config.Load()
+	if err := repo.OpenDatabase(databaseConfig(cfg)); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	defer repo.CloseDatabase()
+	db := repo.GetDB()
+	db.Logger = logger.Default.LogMode(logger.Warn)
+
+	migrator, err := repo.NewMigrator(db)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	switch args[0] {
+	case "up":
+		n, err := migrator.Up()
+		fmt.Printf("Applied %d migration(s)\n", n)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			if steps, err = strconv.Atoi(args[1]); err != nil || steps < 1 {
+				fmt.Fprintln(os.Stderr, usage)
+				return 2
+			}
+		}
+		n, err := migrator.Down(steps)
+		fmt.Printf("Rolled back %d migration(s)\n", n)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+	case "status":
+		status, err := migrator.Status()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tSTATUS\tAPPLIED AT")
+		for _, s := range status {
+			state, appliedAt := "pending", ""
+			if s.AppliedAt != nil {
+				state, appliedAt = "applied", s.AppliedAt.Format(time.RFC3339)
+			}
+			if s.Modified {
+				state = "modified"
+			}
+			if s.Unknown {
+				state = "unknown"
+			}
+			fmt.Fprintf(w, "%03d\t%s\t%s\t%s\n", s.Version, s.Name, state, appliedAt)
+		}
+		w.Flush()
+	default:
+		fmt.Fprintln(os.Stderr, usage)
+		return 2
+	}
+	return 0
+}
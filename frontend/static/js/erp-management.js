@@ -41,6 +41,12 @@ async function ensureEntityConfig() {
 let currentEntity = '';
 let currentData = [];
 let editingItem = null;
+// Paging state: list endpoints return one page plus pagination metadata
+const PAGE_SIZE = 50;
+let currentPage = 1;
+let currentPagination = null;
+// Query string of the active search, null when listing all records
+let currentFilter = null;
 // Currently-loaded entity config (defensive accessor stores last loaded config)
 let currentConfig = null;
 
@@ -79,6 +85,10 @@ function initTableView() {
     document.getElementById('btn-add').addEventListener('click', () => openModal());
     document.getElementById('btn-search').addEventListener('click', () => searchData());
     document.getElementById('btn-reset').addEventListener('click', () => resetSearch());
+    const prevPage = document.getElementById('btn-prev-page');
+    if (prevPage) prevPage.addEventListener('click', () => loadPage(currentPage - 1));
+    const nextPage = document.getElementById('btn-next-page');
+    if (nextPage) nextPage.addEventListener('click', () => loadPage(currentPage + 1));
     document.getElementById('btn-save').addEventListener('click', () => saveData());
     document.getElementById('btn-cancel').addEventListener('click', () => closeModal());
     document.getElementById('modal-close').addEventListener('click', () => closeModal());
@@ -185,24 +195,50 @@ function generateTable(config) {
 
 // Fetch Data
 async function fetchData() {
+    currentFilter = null;
+    await loadPage(1);
+}
+
+// Load one page of the current list or search
+async function loadPage(page) {
     try {
-        console.log("Fetching data for entity: ", currentEntity);
         const config = currentConfig || await getEntityConfig(currentEntity);
-        console.log("current config: ", config);
-        const response = await apiRequest(`${API_BASE_URL}/${config.endpoint}`);
-        console.log("current response: ", response);
+        const qs = new URLSearchParams(currentFilter || '');
+        qs.set('page', page);
+        qs.set('page_size', PAGE_SIZE);
+        const path = currentFilter ? `${config.endpoint}/search` : config.endpoint;
+        const response = await apiRequest(`${API_BASE_URL}/${path}?` + qs.toString());
         const result = await response.json();
-        console.log("Response Json: ", result);
-        
+        if (!response.ok) {
+            throw new Error(result.error);
+        }
+
         currentData = result.data || [];
+        currentPage = page;
+        currentPagination = result.pagination || null;
         renderTable();
-        
-        document.getElementById('data-count').textContent = `Total: ${currentData.length} records`;
+        renderPagination();
+        return result;
     } catch (error) {
         showToast('Failed to load data: ' + error.message, 'error');
+        return null;
     }
 }
 
+// Render total count and page controls
+function renderPagination() {
+    const total = currentPagination ? currentPagination.total_items : currentData.length;
+    const totalPages = currentPagination ? Math.max(currentPagination.total_pages, 1) : 1;
+    document.getElementById('data-count').textContent = `Total: ${total} records`;
+
+    const pageInfo = document.getElementById('page-info');
+    if (pageInfo) pageInfo.textContent = `Page ${currentPage} of ${totalPages}`;
+    const prev = document.getElementById('btn-prev-page');
+    if (prev) prev.disabled = currentPage <= 1;
+    const next = document.getElementById('btn-next-page');
+    if (next) next.disabled = !(currentPagination && currentPagination.has_more);
+}
+
 // Render Table
 function renderTable() {
     const config = currentConfig || (ENTITY_CONFIG && ENTITY_CONFIG[currentEntity]) || { fields: [] };
@@ -283,17 +319,11 @@ async function searchData() {
         if (Object.keys(number_range).length) qs.append('number_range', JSON.stringify(number_range));
         if (Object.keys(date_range).length) qs.append('date_range', JSON.stringify(date_range));
 
-        const response = await apiRequest(`${API_BASE_URL}/${config.endpoint}/search?` + qs.toString());
-        const result = await response.json();
-        if (!response.ok) {
-            throw new Error('Search request failed: ' + result.error);
+        currentFilter = qs.toString();
+        const result = await loadPage(1);
+        if (result) {
+            showToast(`Found ${result.pagination ? result.pagination.total_items : currentData.length} records`, 'success');
         }
-        
-        currentData = result.data || [];
-        renderTable();
-        
-        document.getElementById('data-count').textContent = `Total: ${currentData.length} records`;
-        showToast(`Found ${currentData.length} records`, 'success');
     } catch (error) {
         showToast('Search failed: ' + error.message, 'error');
     }
@@ -424,7 +454,7 @@ async function saveData() {
         if (response.ok) {
             showToast(result.message || 'Saved successfully', 'success');
             closeModal();
-            fetchData();
+            loadPage(currentPage);
         } else {
             showToast(result.error || 'Save failed', 'error');
         }
@@ -501,7 +531,8 @@ export async function deleteItem(id) {
         
         if (response.ok) {
             showToast(result.message || 'Deleted successfully', 'success');
-            fetchData();
+            // Step back a page when the last row of a page was deleted
+            loadPage(currentData.length === 1 && currentPage > 1 ? currentPage - 1 : currentPage);
         } else {
             showToast(result.error || 'Delete failed', 'error');
         }
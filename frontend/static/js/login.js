@@ -1,6 +1,98 @@
 document.addEventListener('DOMContentLoaded', function() {
     const loginForm = document.getElementById('login-form');
+    const twoFactorForm = document.getElementById('two-factor-form');
     const messageDiv = document.getElementById('message');
+    let challengeToken = null;
+
+    function showMessage(text, type) {
+        messageDiv.textContent = text;
+        messageDiv.className = 'message ' + type;
+        messageDiv.style.display = 'block';
+    }
+
+    async function postJSON(url, body) {
+        const response = await fetch(url, {
+            method: 'POST',
+            headers: {
+                'Content-Type': 'application/json',
+            },
+            body: JSON.stringify(body)
+        });
+        return response.json();
+    }
+
+    function completeLogin(username, result) {
+        // Save token and user info to localStorage
+        localStorage.setItem('token', result.token);
+        localStorage.setItem('refresh_token', result.refresh_token);
+        localStorage.setItem('user', JSON.stringify({
+            username: username,
+            user_type: result.user_type,
+            user_id: result.user_id
+        }));
+
+        // Recovery codes are only returned once, when two-factor setup finishes during login
+        if (result.recovery_codes && result.recovery_codes.length) {
+            alert('Two-factor authentication is now enabled. Save these recovery codes somewhere safe; each can be used once if you lose your device:\n\n' + result.recovery_codes.join('\n'));
+        }
+
+        showMessage('Login successful! Redirecting...', 'success');
+
+        // Redirect based on user type
+        setTimeout(() => {
+            const ut = result.user_type || '';
+            if (ut === 'donor') {
+                window.location.href = '/donor';
+            } else if (ut === 'volunteer') {
+                window.location.href = '/volunteer';
+            } else if (ut === 'employee') {
+                window.location.href = '/employee';
+            } else {
+                window.location.href = '/erp-management';
+            }
+        }, 800);
+    }
+
+    // Second login step: the password was accepted, now ask for the authenticator code
+    async function startTwoFactor(result) {
+        challengeToken = result.challenge_token;
+        loginForm.style.display = 'none';
+        twoFactorForm.style.display = 'block';
+        if (result.two_factor_setup_required) {
+            const data = await postJSON('/api/v1/auth/2fa/login/setup', { challenge_token: challengeToken });
+            if (!data.success) {
+                showMessage(data.message || 'Two-factor setup failed', 'error');
+                return;
+            }
+            document.getElementById('two-factor-secret').value = data.data.secret;
+            document.getElementById('two-factor-uri').href = data.data.provisioning_uri;
+            document.getElementById('two-factor-setup').style.display = 'block';
+        }
+        showMessage('Enter the code from your authenticator app', 'success');
+        document.getElementById('two-factor-code').focus();
+    }
+
+    twoFactorForm.addEventListener('submit', async function(e) {
+        e.preventDefault();
+
+        const username = document.getElementById('username').value;
+        const code = document.getElementById('two-factor-code').value;
+
+        try {
+            const data = await postJSON('/api/v1/auth/2fa/login/verify', {
+                challenge_token: challengeToken,
+                code: code
+            });
+            if (data.success) {
+                completeLogin(username, data.data);
+            } else {
+                showMessage(data.message || 'Verification failed', 'error');
+            }
+        } catch (error) {
+            console.error('Two-factor error:', error);
+            showMessage('An error occurred. Please try again.', 'error');
+        }
+    });
 
     loginForm.addEventListener('submit', async function(e) {
         e.preventDefault();
@@ -22,32 +114,10 @@ document.addEventListener('DOMContentLoaded', function() {
 
             const data = await response.json();
 
-            if (data.success) {
-                // Save token and user info to localStorage
-                localStorage.setItem('token', data.data.token);
-                localStorage.setItem('user', JSON.stringify({
-                    username: username,
-                    user_type: data.data.user_type,
-                    user_id: data.data.user_id
-                }));
-
-                messageDiv.textContent = 'Login successful! Redirecting...';
-                messageDiv.className = 'message success';
-                messageDiv.style.display = 'block';
-
-                // Redirect based on user type
-                setTimeout(() => {
-                    const ut = data.data.user_type || '';
-                    if (ut === 'donor') {
-                        window.location.href = '/donor';
-                    } else if (ut === 'volunteer') {
-                        window.location.href = '/volunteer';
-                    } else if (ut === 'employee') {
-                        window.location.href = '/employee';
-                    } else {
-                        window.location.href = '/erp-management';
-                    }
-                }, 800);
+            if (data.success && data.data.challenge_token) {
+                await startTwoFactor(data.data);
+            } else if (data.success) {
+                completeLogin(username, data.data);
             } else {
                 messageDiv.textContent = data.message || 'Login failed';
                 messageDiv.className = 'message error';
@@ -1,5 +1,35 @@
+// Clear stored credentials
+function clearSession() {
+    localStorage.removeItem('token');
+    localStorage.removeItem('refresh_token');
+    localStorage.removeItem('user');
+    localStorage.removeItem('profile');
+}
+
+// Exchange the refresh token for a new token pair; concurrent callers share one request
+let refreshing = null;
+function refreshTokens() {
+    if (!refreshing) {
+        refreshing = (async () => {
+            const refreshToken = localStorage.getItem('refresh_token');
+            if (!refreshToken) return false;
+            const response = await fetch('/api/v1/auth/refresh', {
+                method: 'POST',
+                headers: { 'Content-Type': 'application/json' },
+                body: JSON.stringify({ refresh_token: refreshToken })
+            });
+            if (!response.ok) return false;
+            const data = await response.json();
+            localStorage.setItem('token', data.data.token);
+            localStorage.setItem('refresh_token', data.data.refresh_token);
+            return true;
+        })().catch(() => false).finally(() => { refreshing = null; });
+    }
+    return refreshing;
+}
+
 // API Helper function - automatically add Authorization header
-export async function apiRequest(url, options = {}) {
+export async function apiRequest(url, options = {}, retried = false) {
     const token = localStorage.getItem('token');
     
     if (!token) {
@@ -23,11 +53,12 @@ export async function apiRequest(url, options = {}) {
     try {
         const response = await fetch(url, requestOptions);
         
-        // If unauthorized, redirect to login
+        // If unauthorized, try refreshing the access token once, otherwise redirect to login
         if (response.status === 401) {
-            localStorage.removeItem('token');
-            localStorage.removeItem('user');
-            localStorage.removeItem('profile');
+            if (!retried && await refreshTokens()) {
+                return apiRequest(url, options, true);
+            }
+            clearSession();
             window.location.href = '/login';
             throw new Error('Authentication failed');
         }
@@ -50,11 +81,20 @@ function checkAuth() {
     return true;
 }
 
-// Logout function
-function logout() {
-    localStorage.removeItem('token');
-    localStorage.removeItem('user');
-    localStorage.removeItem('profile');
+// Logout function - end the session on the server before clearing local state
+async function logout() {
+    const token = localStorage.getItem('token');
+    if (token) {
+        try {
+            await fetch('/api/v1/auth/logout', {
+                method: 'POST',
+                headers: { 'Authorization': `Bearer ${token}` }
+            });
+        } catch (error) {
+            console.error('Logout request failed:', error);
+        }
+    }
+    clearSession();
     window.location.href = '/login';
 }
 